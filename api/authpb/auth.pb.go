@@ -138,12 +138,18 @@ var xxx_messageInfo_User proto.InternalMessageInfo
 
 // Permission is a single entity
 type Permission struct {
-	PermType             Permission_Type `protobuf:"varint,1,opt,name=permType,proto3,enum=authpb.Permission_Type" json:"permType,omitempty"`
-	Key                  []byte          `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
-	RangeEnd             []byte          `protobuf:"bytes,3,opt,name=range_end,json=rangeEnd,proto3" json:"range_end,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
-	XXX_unrecognized     []byte          `json:"-"`
-	XXX_sizecache        int32           `json:"-"`
+	PermType Permission_Type `protobuf:"varint,1,opt,name=permType,proto3,enum=authpb.Permission_Type" json:"permType,omitempty"`
+	Key      []byte          `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	RangeEnd []byte          `protobuf:"bytes,3,opt,name=range_end,json=rangeEnd,proto3" json:"range_end,omitempty"`
+	// deny denies the access to the keys even if other permissions grant it.
+	Deny bool `protobuf:"varint,4,opt,name=deny,proto3" json:"deny,omitempty"`
+	// pattern is set if key is a glob pattern, where '*' matches any sequence of
+	// characters other than '/'. A non-empty range_end makes the pattern match
+	// every key beginning with a match instead of the matches only.
+	Pattern              bool     `protobuf:"varint,5,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Permission) Reset()         { *m = Permission{} }
@@ -232,29 +238,30 @@ func init() {
 func init() { proto.RegisterFile("auth.proto", fileDescriptor_8bbd6f3875b0e874) }
 
 var fileDescriptor_8bbd6f3875b0e874 = []byte{
-	// 338 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x91, 0xcf, 0x4e, 0xea, 0x40,
-	0x14, 0xc6, 0x3b, 0xb4, 0x70, 0xdb, 0xc3, 0x85, 0x90, 0x13, 0x72, 0x6f, 0x83, 0x49, 0x6d, 0xba,
-	0x6a, 0x5c, 0x54, 0x85, 0x8d, 0x5b, 0x8c, 0x2c, 0x5c, 0x49, 0x26, 0x18, 0x97, 0xa4, 0xa4, 0x13,
-	0x24, 0xc0, 0x4c, 0x33, 0x83, 0x31, 0x6c, 0x7c, 0x0e, 0x17, 0x3e, 0x10, 0x4b, 0x1e, 0x41, 0xf0,
-	0x45, 0x4c, 0x67, 0xf8, 0x13, 0xa2, 0xbb, 0xef, 0x7c, 0xe7, 0xfb, 0x66, 0x7e, 0x99, 0x01, 0x48,
-	0x5f, 0x16, 0xcf, 0x49, 0x2e, 0xc5, 0x42, 0x60, 0xa5, 0xd0, 0xf9, 0xa8, 0xd5, 0x1c, 0x8b, 0xb1,
-	0xd0, 0xd6, 0x65, 0xa1, 0xcc, 0x36, 0xba, 0x86, 0xfa, 0xa3, 0x62, 0xb2, 0x9b, 0x65, 0x0f, 0xf9,
-	0x62, 0x22, 0xb8, 0xc2, 0x73, 0xa8, 0x72, 0x31, 0xcc, 0x53, 0xa5, 0x5e, 0x85, 0xcc, 0x7c, 0x12,
-	0x92, 0xd8, 0xa5, 0xc0, 0x45, 0x7f, 0xe7, 0x44, 0x6f, 0xe0, 0x14, 0x15, 0x44, 0x70, 0x78, 0x3a,
-	0x67, 0x3a, 0xf1, 0x97, 0x6a, 0x8d, 0x2d, 0x70, 0x0f, 0xcd, 0x92, 0xf6, 0x0f, 0x33, 0x36, 0xa1,
-	0x2c, 0xc5, 0x8c, 0x29, 0xdf, 0x0e, 0xed, 0xd8, 0xa3, 0x66, 0xc0, 0x2b, 0xf8, 0x23, 0xcc, 0xcd,
-	0xbe, 0x13, 0x92, 0xb8, 0xda, 0xfe, 0x97, 0x18, 0xe0, 0xe4, 0x94, 0x8b, 0xee, 0x63, 0xd1, 0x07,
-	0x01, 0xe8, 0x33, 0x39, 0x9f, 0x28, 0x35, 0x11, 0x1c, 0x3b, 0xe0, 0xe6, 0x4c, 0xce, 0x07, 0xcb,
-	0xdc, 0xa0, 0xd4, 0xdb, 0xff, 0xf7, 0x27, 0x1c, 0x53, 0x49, 0xb1, 0xa6, 0x87, 0x20, 0x36, 0xc0,
-	0x9e, 0xb2, 0xe5, 0x0e, 0xb1, 0x90, 0x78, 0x06, 0x9e, 0x4c, 0xf9, 0x98, 0x0d, 0x19, 0xcf, 0x7c,
-	0xdb, 0xa0, 0x6b, 0xa3, 0xc7, 0xb3, 0xe8, 0x02, 0x1c, 0x5d, 0x73, 0xc1, 0xa1, 0xbd, 0xee, 0x5d,
-	0xc3, 0x42, 0x0f, 0xca, 0x4f, 0xf4, 0x7e, 0xd0, 0x6b, 0x10, 0xac, 0x81, 0x57, 0x98, 0x66, 0x2c,
-	0x45, 0x03, 0x70, 0xa8, 0x98, 0xb1, 0x5f, 0x9f, 0xe7, 0x06, 0x6a, 0x53, 0xb6, 0x3c, 0x62, 0xf9,
-	0xa5, 0xd0, 0x8e, 0xab, 0x6d, 0xfc, 0x09, 0x4c, 0x4f, 0x83, 0xb7, 0xfe, 0x6a, 0x13, 0x58, 0xeb,
-	0x4d, 0x60, 0xad, 0xb6, 0x01, 0x59, 0x6f, 0x03, 0xf2, 0xb9, 0x0d, 0xc8, 0xfb, 0x57, 0x60, 0x8d,
-	0x2a, 0xfa, 0x23, 0x3b, 0xdf, 0x01, 0x00, 0x00, 0xff, 0xff, 0x61, 0x66, 0xc6, 0x9d, 0xf4, 0x01,
-	0x00, 0x00,
+	// 359 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x51, 0x4d, 0x6b, 0xea, 0x40,
+	0x14, 0xcd, 0x98, 0xa8, 0xc9, 0xf5, 0x29, 0x72, 0x91, 0xf7, 0x06, 0x1f, 0xe4, 0x85, 0xac, 0xc2,
+	0x5b, 0xa4, 0xad, 0x6e, 0xba, 0xb5, 0xd4, 0x45, 0x57, 0x95, 0xc1, 0xd2, 0xa5, 0x44, 0x32, 0x58,
+	0x51, 0x67, 0xc2, 0x24, 0xa5, 0x64, 0xd3, 0xdf, 0xd1, 0x9f, 0x24, 0x5d, 0xf9, 0x13, 0xaa, 0xfd,
+	0x23, 0x25, 0x13, 0x3f, 0x90, 0x76, 0x77, 0xce, 0xb9, 0xe7, 0xe6, 0x9e, 0x93, 0x01, 0x88, 0x9e,
+	0xb3, 0xa7, 0x30, 0x51, 0x32, 0x93, 0x58, 0x2b, 0x70, 0x32, 0xed, 0x76, 0x66, 0x72, 0x26, 0xb5,
+	0x74, 0x51, 0xa0, 0x72, 0xea, 0x5f, 0x41, 0xeb, 0x21, 0xe5, 0x6a, 0x10, 0xc7, 0xf7, 0x49, 0x36,
+	0x97, 0x22, 0xc5, 0x7f, 0xd0, 0x10, 0x72, 0x92, 0x44, 0x69, 0xfa, 0x22, 0x55, 0x4c, 0x89, 0x47,
+	0x02, 0x9b, 0x81, 0x90, 0xa3, 0xbd, 0xe2, 0xbf, 0x82, 0x55, 0xac, 0x20, 0x82, 0x25, 0xa2, 0x15,
+	0xd7, 0x8e, 0x5f, 0x4c, 0x63, 0xec, 0x82, 0x7d, 0xdc, 0xac, 0x68, 0xfd, 0xc8, 0xb1, 0x03, 0x55,
+	0x25, 0x97, 0x3c, 0xa5, 0xa6, 0x67, 0x06, 0x0e, 0x2b, 0x09, 0x5e, 0x42, 0x5d, 0x96, 0x97, 0xa9,
+	0xe5, 0x91, 0xa0, 0xd1, 0xfb, 0x1d, 0x96, 0x81, 0xc3, 0xf3, 0x5c, 0xec, 0x60, 0xf3, 0xdf, 0x09,
+	0xc0, 0x88, 0xab, 0xd5, 0x3c, 0x4d, 0xe7, 0x52, 0x60, 0x1f, 0xec, 0x84, 0xab, 0xd5, 0x38, 0x4f,
+	0xca, 0x28, 0xad, 0xde, 0x9f, 0xc3, 0x17, 0x4e, 0xae, 0xb0, 0x18, 0xb3, 0xa3, 0x11, 0xdb, 0x60,
+	0x2e, 0x78, 0xbe, 0x8f, 0x58, 0x40, 0xfc, 0x0b, 0x8e, 0x8a, 0xc4, 0x8c, 0x4f, 0xb8, 0x88, 0xa9,
+	0x59, 0x46, 0xd7, 0xc2, 0x50, 0xc4, 0x45, 0xd5, 0x98, 0x8b, 0x5c, 0x27, 0xb4, 0x99, 0xc6, 0x48,
+	0xa1, 0x9e, 0x44, 0x59, 0xc6, 0x95, 0xa0, 0x55, 0x2d, 0x1f, 0xa8, 0xff, 0x1f, 0x2c, 0x7d, 0xc4,
+	0x06, 0x8b, 0x0d, 0x07, 0xb7, 0x6d, 0x03, 0x1d, 0xa8, 0x3e, 0xb2, 0xbb, 0xf1, 0xb0, 0x4d, 0xb0,
+	0x09, 0x4e, 0x21, 0x96, 0xb4, 0xe2, 0x8f, 0xc1, 0x62, 0x72, 0xc9, 0x7f, 0xfc, 0x99, 0xd7, 0xd0,
+	0x5c, 0xf0, 0xfc, 0x54, 0x82, 0x56, 0x3c, 0x33, 0x68, 0xf4, 0xf0, 0x7b, 0x3d, 0x76, 0x6e, 0xbc,
+	0xa1, 0xeb, 0xad, 0x6b, 0x6c, 0xb6, 0xae, 0xb1, 0xde, 0xb9, 0x64, 0xb3, 0x73, 0xc9, 0xc7, 0xce,
+	0x25, 0x6f, 0x9f, 0xae, 0x31, 0xad, 0xe9, 0x67, 0xef, 0x7f, 0x05, 0x00, 0x00, 0xff, 0xff, 0xd2,
+	0x33, 0xeb, 0xc2, 0x22, 0x02, 0x00, 0x00,
 }
 
 func (m *UserAddOptions) Marshal() (dAtA []byte, err error) {
@@ -380,6 +387,26 @@ func (m *Permission) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.Pattern {
+		i--
+		if m.Pattern {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.Deny {
+		i--
+		if m.Deny {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
+	}
 	if len(m.RangeEnd) > 0 {
 		i -= len(m.RangeEnd)
 		copy(dAtA[i:], m.RangeEnd)
@@ -523,6 +550,12 @@ func (m *Permission) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovAuth(uint64(l))
 	}
+	if m.Deny {
+		n += 2
+	}
+	if m.Pattern {
+		n += 2
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -931,6 +964,46 @@ func (m *Permission) Unmarshal(dAtA []byte) error {
 				m.RangeEnd = []byte{}
 			}
 			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Deny", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAuth
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Deny = bool(v != 0)
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Pattern", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAuth
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Pattern = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipAuth(dAtA[iNdEx:])
@@ -31,7 +31,15 @@ type RequestHeader struct {
 	// username is a username that is associated with an auth token of gRPC connection
 	Username string `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
 	// auth_revision is a revision number of auth.authStore. It is not related to mvcc
-	AuthRevision         uint64   `protobuf:"varint,3,opt,name=auth_revision,json=authRevision,proto3" json:"auth_revision,omitempty"`
+	AuthRevision uint64 `protobuf:"varint,3,opt,name=auth_revision,json=authRevision,proto3" json:"auth_revision,omitempty"`
+	// timestamp is the time in nanoseconds since the epoch when the request is proposed.
+	// It lets request rate limits be enforced the same way by all members.
+	Timestamp int64 `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// external is set if the user was verified by an authenticator instead of being stored
+	// in the auth store, and is granted the roles below. The roles are resolved when the
+	// request is proposed so that every member checks the request against the same roles.
+	External             bool     `protobuf:"varint,5,opt,name=external,proto3" json:"external,omitempty"`
+	Roles                []string `protobuf:"bytes,6,rep,name=roles,proto3" json:"roles,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -105,9 +113,18 @@ type InternalRaftRequest struct {
 	ClusterVersionSet        *membershippb.ClusterVersionSetRequest    `protobuf:"bytes,1300,opt,name=cluster_version_set,json=clusterVersionSet,proto3" json:"cluster_version_set,omitempty"`
 	ClusterMemberAttrSet     *membershippb.ClusterMemberAttrSetRequest `protobuf:"bytes,1301,opt,name=cluster_member_attr_set,json=clusterMemberAttrSet,proto3" json:"cluster_member_attr_set,omitempty"`
 	DowngradeInfoSet         *membershippb.DowngradeInfoSetRequest     `protobuf:"bytes,1302,opt,name=downgrade_info_set,json=downgradeInfoSet,proto3" json:"downgrade_info_set,omitempty"`
-	XXX_NoUnkeyedLiteral     struct{}                                  `json:"-"`
-	XXX_unrecognized         []byte                                    `json:"-"`
-	XXX_sizecache            int32                                     `json:"-"`
+	QuotaSet                 *QuotaSetRequest                          `protobuf:"bytes,1400,opt,name=quota_set,json=quotaSet,proto3" json:"quota_set,omitempty"`
+	QuotaDelete              *QuotaDeleteRequest                       `protobuf:"bytes,1401,opt,name=quota_delete,json=quotaDelete,proto3" json:"quota_delete,omitempty"`
+	CompactionHold           *CompactionHoldRequest                    `protobuf:"bytes,1402,opt,name=compaction_hold,json=compactionHold,proto3" json:"compaction_hold,omitempty"`
+	CompactionHoldRelease    *CompactionHoldReleaseRequest             `protobuf:"bytes,1403,opt,name=compaction_hold_release,json=compactionHoldRelease,proto3" json:"compaction_hold_release,omitempty"`
+	// lease_expire revokes a lease which expired.
+	LeaseExpire          *LeaseRevokeRequest `protobuf:"bytes,1404,opt,name=lease_expire,json=leaseExpire,proto3" json:"lease_expire,omitempty"`
+	LeaseAttach          *LeaseAttachRequest `protobuf:"bytes,1405,opt,name=lease_attach,json=leaseAttach,proto3" json:"lease_attach,omitempty"`
+	LeaseDetach          *LeaseDetachRequest `protobuf:"bytes,1406,opt,name=lease_detach,json=leaseDetach,proto3" json:"lease_detach,omitempty"`
+	CdcCursor            *CDCCursorRequest   `protobuf:"bytes,1407,opt,name=cdc_cursor,json=cdcCursor,proto3" json:"cdc_cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
 }
 
 func (m *InternalRaftRequest) Reset()         { *m = InternalRaftRequest{} }
@@ -189,7 +206,11 @@ type InternalAuthenticateRequest struct {
 	Name     string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	Password string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
 	// simple_token is generated in API layer (etcdserver/v3_server.go)
-	SimpleToken          string   `protobuf:"bytes,3,opt,name=simple_token,json=simpleToken,proto3" json:"simple_token,omitempty"`
+	SimpleToken string `protobuf:"bytes,3,opt,name=simple_token,json=simpleToken,proto3" json:"simple_token,omitempty"`
+	// external is set if the user was verified by an authenticator in API layer
+	// instead of a password, and is granted the roles below.
+	External             bool     `protobuf:"varint,4,opt,name=external,proto3" json:"external,omitempty"`
+	Roles                []string `protobuf:"bytes,5,rep,name=roles,proto3" json:"roles,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -228,83 +249,144 @@ func (m *InternalAuthenticateRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_InternalAuthenticateRequest proto.InternalMessageInfo
 
+// CDCCursorRequest records the last revision whose events the leader delivered
+// to a change data capture sink, so that the next leader resumes from it.
+type CDCCursorRequest struct {
+	Sink                 string   `protobuf:"bytes,1,opt,name=sink,proto3" json:"sink,omitempty"`
+	Revision             int64    `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CDCCursorRequest) Reset()         { *m = CDCCursorRequest{} }
+func (m *CDCCursorRequest) String() string { return proto.CompactTextString(m) }
+func (*CDCCursorRequest) ProtoMessage()    {}
+func (*CDCCursorRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b4c9a9be0cfca103, []int{4}
+}
+func (m *CDCCursorRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CDCCursorRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CDCCursorRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CDCCursorRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CDCCursorRequest.Merge(m, src)
+}
+func (m *CDCCursorRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *CDCCursorRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CDCCursorRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CDCCursorRequest proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterType((*RequestHeader)(nil), "etcdserverpb.RequestHeader")
 	proto.RegisterType((*InternalRaftRequest)(nil), "etcdserverpb.InternalRaftRequest")
 	proto.RegisterType((*EmptyResponse)(nil), "etcdserverpb.EmptyResponse")
 	proto.RegisterType((*InternalAuthenticateRequest)(nil), "etcdserverpb.InternalAuthenticateRequest")
+	proto.RegisterType((*CDCCursorRequest)(nil), "etcdserverpb.CDCCursorRequest")
 }
 
 func init() { proto.RegisterFile("raft_internal.proto", fileDescriptor_b4c9a9be0cfca103) }
 
 var fileDescriptor_b4c9a9be0cfca103 = []byte{
-	// 1054 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x56, 0x5d, 0x6f, 0x1b, 0x45,
-	0x14, 0xad, 0xd3, 0x34, 0x89, 0xc7, 0x49, 0x9a, 0x4e, 0x52, 0x3a, 0x38, 0x92, 0x71, 0x03, 0x2d,
-	0x01, 0x8a, 0x53, 0x1c, 0x78, 0xe1, 0x05, 0x5c, 0x3b, 0x4a, 0x83, 0x4a, 0x15, 0x6d, 0x0b, 0xaa,
-	0x84, 0xd0, 0x32, 0xde, 0xbd, 0xb1, 0xb7, 0x59, 0xef, 0x2e, 0x33, 0x63, 0x37, 0x7d, 0xe5, 0x91,
-	0x67, 0x40, 0xfc, 0x0c, 0x3e, 0xff, 0x43, 0x85, 0xf8, 0x28, 0xf0, 0x07, 0x20, 0xbc, 0xf0, 0x0e,
-	0xbc, 0xa3, 0xf9, 0xd8, 0x5d, 0xaf, 0x3d, 0xce, 0xdb, 0xfa, 0xde, 0x73, 0xcf, 0x39, 0x33, 0x73,
-	0xef, 0x78, 0xd0, 0x3a, 0xa3, 0x47, 0xc2, 0x0d, 0x22, 0x01, 0x2c, 0xa2, 0x61, 0x23, 0x61, 0xb1,
-	0x88, 0xf1, 0x32, 0x08, 0xcf, 0xe7, 0xc0, 0x46, 0xc0, 0x92, 0x6e, 0x75, 0xa3, 0x17, 0xf7, 0x62,
-	0x95, 0xd8, 0x91, 0x5f, 0x1a, 0x53, 0x5d, 0xcb, 0x31, 0x26, 0x52, 0x66, 0x89, 0x67, 0x3e, 0xeb,
-	0x32, 0xb9, 0x43, 0x93, 0x60, 0x67, 0x04, 0x8c, 0x07, 0x71, 0x94, 0x74, 0xd3, 0x2f, 0x83, 0xb8,
-	0x9e, 0x21, 0x06, 0x30, 0xe8, 0x02, 0xe3, 0xfd, 0x20, 0x49, 0xba, 0x63, 0x3f, 0x34, 0x6e, 0x8b,
-	0xa1, 0x15, 0x07, 0x3e, 0x1e, 0x02, 0x17, 0xb7, 0x81, 0xfa, 0xc0, 0xf0, 0x2a, 0x9a, 0x3b, 0xe8,
-	0x90, 0x52, 0xbd, 0xb4, 0x3d, 0xef, 0xcc, 0x1d, 0x74, 0x70, 0x15, 0x2d, 0x0d, 0xb9, 0x34, 0x3f,
-	0x00, 0x32, 0x57, 0x2f, 0x6d, 0x97, 0x9d, 0xec, 0x37, 0xbe, 0x81, 0x56, 0xe8, 0x50, 0xf4, 0x5d,
-	0x06, 0xa3, 0x40, 0x6a, 0x93, 0xf3, 0xb2, 0xec, 0xd6, 0xe2, 0xa7, 0xdf, 0x93, 0xf3, 0xbb, 0x8d,
-	0xd7, 0x9c, 0x65, 0x99, 0x75, 0x4c, 0xf2, 0xcd, 0xc5, 0x4f, 0x54, 0xf8, 0xe6, 0xd6, 0x0f, 0x18,
-	0xad, 0x1f, 0x98, 0x1d, 0x71, 0xe8, 0x91, 0x30, 0x06, 0xf0, 0x2e, 0x5a, 0xe8, 0x2b, 0x13, 0xc4,
-	0xaf, 0x97, 0xb6, 0x2b, 0xcd, 0xcd, 0xc6, 0xf8, 0x3e, 0x35, 0x0a, 0x3e, 0x1d, 0x03, 0x9d, 0xf2,
-	0x7b, 0x0d, 0xcd, 0x8d, 0x9a, 0xca, 0x69, 0xa5, 0x79, 0xd9, 0x4a, 0xe0, 0xcc, 0x8d, 0x9a, 0xf8,
-	0x26, 0xba, 0xc0, 0x68, 0xd4, 0x03, 0x65, 0xb9, 0xd2, 0xac, 0x4e, 0x20, 0x65, 0x2a, 0x85, 0x6b,
-	0x20, 0x7e, 0x19, 0x9d, 0x4f, 0x86, 0x82, 0xcc, 0x2b, 0x3c, 0x29, 0xe2, 0x0f, 0x87, 0xe9, 0x22,
-	0x1c, 0x09, 0xc2, 0x6d, 0xb4, 0xec, 0x43, 0x08, 0x02, 0x5c, 0x2d, 0x72, 0x41, 0x15, 0xd5, 0x8b,
-	0x45, 0x1d, 0x85, 0x28, 0x48, 0x55, 0xfc, 0x3c, 0x26, 0x05, 0xc5, 0x49, 0x44, 0x16, 0x6c, 0x82,
-	0xf7, 0x4f, 0xa2, 0x4c, 0x50, 0x9c, 0x44, 0xf8, 0x2d, 0x84, 0xbc, 0x78, 0x90, 0x50, 0x4f, 0xc8,
-	0x63, 0x58, 0x54, 0x25, 0xcf, 0x15, 0x4b, 0xda, 0x59, 0x3e, 0xad, 0x1c, 0x2b, 0xc1, 0x6f, 0xa3,
-	0x4a, 0x08, 0x94, 0x83, 0xdb, 0x63, 0x34, 0x12, 0x64, 0xc9, 0xc6, 0x70, 0x47, 0x02, 0xf6, 0x65,
-	0x3e, 0x63, 0x08, 0xb3, 0x90, 0x5c, 0xb3, 0x66, 0x60, 0x30, 0x8a, 0x8f, 0x81, 0x94, 0x6d, 0x6b,
-	0x56, 0x14, 0x8e, 0x02, 0x64, 0x6b, 0x0e, 0xf3, 0x98, 0x3c, 0x16, 0x1a, 0x52, 0x36, 0x20, 0xc8,
-	0x76, 0x2c, 0x2d, 0x99, 0xca, 0x8e, 0x45, 0x01, 0xf1, 0x03, 0xb4, 0xa6, 0x65, 0xbd, 0x3e, 0x78,
-	0xc7, 0x49, 0x1c, 0x44, 0x82, 0x54, 0x54, 0xf1, 0x0b, 0x16, 0xe9, 0x76, 0x06, 0x32, 0x34, 0x69,
-	0xb3, 0xbe, 0xee, 0x5c, 0x0c, 0x8b, 0x00, 0xdc, 0x42, 0x15, 0xd5, 0xdd, 0x10, 0xd1, 0x6e, 0x08,
-	0xe4, 0x6f, 0xeb, 0xae, 0xb6, 0x86, 0xa2, 0xbf, 0xa7, 0x00, 0xd9, 0x9e, 0xd0, 0x2c, 0x84, 0x3b,
-	0x48, 0x8d, 0x80, 0xeb, 0x07, 0x5c, 0x71, 0xfc, 0xb3, 0x68, 0xdb, 0x14, 0xc9, 0xd1, 0xd1, 0x88,
-	0x6c, 0x53, 0x68, 0x1e, 0xc3, 0xef, 0x18, 0x23, 0x5c, 0x50, 0x31, 0xe4, 0xe4, 0xbf, 0x99, 0x46,
-	0xee, 0x29, 0xc0, 0xc4, 0xca, 0xde, 0xd0, 0x8e, 0x74, 0x0e, 0xdf, 0xd5, 0x8e, 0x20, 0x12, 0x81,
-	0x47, 0x05, 0x90, 0x7f, 0x35, 0xd9, 0x4b, 0x45, 0xb2, 0x74, 0x3a, 0x5b, 0x63, 0xd0, 0xd4, 0x5a,
-	0xa1, 0x1e, 0xef, 0x99, 0x2b, 0x40, 0xde, 0x09, 0x2e, 0xf5, 0x7d, 0xf2, 0xe3, 0xd2, 0xac, 0x25,
-	0xbe, 0xc7, 0x81, 0xb5, 0x7c, 0xbf, 0xb0, 0x44, 0x13, 0xc3, 0x77, 0xd1, 0x5a, 0x4e, 0xa3, 0x87,
-	0x80, 0xfc, 0xa4, 0x99, 0x9e, 0xb7, 0x33, 0x99, 0xe9, 0x31, 0x64, 0xab, 0xb4, 0x10, 0x2e, 0xda,
-	0xea, 0x81, 0x20, 0x3f, 0x9f, 0x69, 0x6b, 0x1f, 0xc4, 0x94, 0xad, 0x7d, 0x10, 0xb8, 0x87, 0x9e,
-	0xcd, 0x69, 0xbc, 0xbe, 0x1c, 0x4b, 0x37, 0xa1, 0x9c, 0x3f, 0x8a, 0x99, 0x4f, 0x7e, 0xd1, 0x94,
-	0xaf, 0xd8, 0x29, 0xdb, 0x0a, 0x7d, 0x68, 0xc0, 0x29, 0xfb, 0x33, 0xd4, 0x9a, 0xc6, 0x0f, 0xd0,
-	0xc6, 0x98, 0x5f, 0x39, 0x4f, 0x2e, 0x8b, 0x43, 0x20, 0x4f, 0xb5, 0xc6, 0xf5, 0x19, 0xb6, 0xd5,
-	0x2c, 0xc6, 0x79, 0xdb, 0x5c, 0xa2, 0x93, 0x19, 0xfc, 0x01, 0xba, 0x9c, 0x33, 0xeb, 0xd1, 0xd4,
-	0xd4, 0xbf, 0x6a, 0xea, 0x17, 0xed, 0xd4, 0x66, 0x46, 0xc7, 0xb8, 0x31, 0x9d, 0x4a, 0xe1, 0xdb,
-	0x68, 0x35, 0x27, 0x0f, 0x03, 0x2e, 0xc8, 0x6f, 0x9a, 0xf5, 0xaa, 0x9d, 0xf5, 0x4e, 0xc0, 0x45,
-	0xa1, 0x8f, 0xd2, 0x60, 0xc6, 0x24, 0xad, 0x69, 0xa6, 0xdf, 0x67, 0x32, 0x49, 0xe9, 0x29, 0xa6,
-	0x34, 0x98, 0x1d, 0xbd, 0x62, 0x92, 0x1d, 0xf9, 0x55, 0x79, 0xd6, 0xd1, 0xcb, 0x9a, 0xc9, 0x8e,
-	0x34, 0xb1, 0xac, 0x23, 0x15, 0x8d, 0xe9, 0xc8, 0xaf, 0xcb, 0xb3, 0x3a, 0x52, 0x56, 0x59, 0x3a,
-	0x32, 0x0f, 0x17, 0x6d, 0xc9, 0x8e, 0xfc, 0xe6, 0x4c, 0x5b, 0x93, 0x1d, 0x69, 0x62, 0xf8, 0x21,
-	0xaa, 0x8e, 0xd1, 0xa8, 0x46, 0x49, 0x80, 0x0d, 0x02, 0xae, 0xfe, 0x7f, 0xbf, 0xd5, 0x9c, 0x37,
-	0x66, 0x70, 0x4a, 0xf8, 0x61, 0x86, 0x4e, 0xf9, 0xaf, 0x50, 0x7b, 0x1e, 0x0f, 0xd0, 0x66, 0xae,
-	0x65, 0x5a, 0x67, 0x4c, 0xec, 0x3b, 0x2d, 0xf6, 0xaa, 0x5d, 0x4c, 0x77, 0xc9, 0xb4, 0x1a, 0xa1,
-	0x33, 0x00, 0xf8, 0x23, 0xb4, 0xee, 0x85, 0x43, 0x2e, 0x80, 0xb9, 0xe6, 0x2d, 0xe3, 0x72, 0x10,
-	0xe4, 0x33, 0x64, 0x46, 0x60, 0xfc, 0x21, 0xd3, 0x68, 0x6b, 0xe4, 0xfb, 0x1a, 0x78, 0x0f, 0xc4,
-	0xd4, 0xad, 0x77, 0xc9, 0x9b, 0x84, 0xe0, 0x87, 0xe8, 0x4a, 0xaa, 0xa0, 0xc9, 0x5c, 0x2a, 0x04,
-	0x53, 0x2a, 0x9f, 0x23, 0x73, 0x0f, 0xda, 0x54, 0xde, 0x55, 0xb1, 0x96, 0x10, 0xcc, 0x26, 0xb4,
-	0xe1, 0x59, 0x50, 0xf8, 0x43, 0x84, 0xfd, 0xf8, 0x51, 0xd4, 0x63, 0xd4, 0x07, 0x37, 0x88, 0x8e,
-	0x62, 0x25, 0xf3, 0x85, 0x96, 0xb9, 0x56, 0x94, 0xe9, 0xa4, 0xc0, 0x83, 0xe8, 0x28, 0xb6, 0x49,
-	0xac, 0xf9, 0x13, 0x88, 0xfc, 0x31, 0x75, 0x11, 0xad, 0xec, 0x0d, 0x12, 0xf1, 0xd8, 0x01, 0x9e,
-	0xc4, 0x11, 0x87, 0xad, 0xc7, 0x68, 0xf3, 0x8c, 0xeb, 0x1b, 0x63, 0x34, 0xaf, 0xde, 0x72, 0x25,
-	0xf5, 0x96, 0x53, 0xdf, 0xf2, 0x8d, 0x97, 0xdd, 0x6a, 0xe6, 0x8d, 0x97, 0xfe, 0xc6, 0x57, 0xd1,
-	0x32, 0x0f, 0x06, 0x49, 0x08, 0xae, 0x88, 0x8f, 0x41, 0x3f, 0xf1, 0xca, 0x4e, 0x45, 0xc7, 0xee,
-	0xcb, 0x50, 0xe6, 0xe5, 0xd6, 0xc6, 0x93, 0x3f, 0x6b, 0xe7, 0x9e, 0x9c, 0xd6, 0x4a, 0x4f, 0x4f,
-	0x6b, 0xa5, 0x3f, 0x4e, 0x6b, 0xa5, 0x2f, 0xff, 0xaa, 0x9d, 0xeb, 0x2e, 0xa8, 0x97, 0xe6, 0xee,
-	0xff, 0x01, 0x00, 0x00, 0xff, 0xff, 0x30, 0x36, 0x53, 0xc6, 0x0b, 0x0b, 0x00, 0x00,
+	// 1328 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x97, 0xcf, 0x73, 0xdb, 0x44,
+	0x14, 0xc7, 0xab, 0x38, 0x3f, 0xec, 0x75, 0x9a, 0xa6, 0xdb, 0x94, 0x2c, 0xc9, 0xd4, 0xb8, 0x29,
+	0x29, 0xa6, 0x94, 0xa4, 0x38, 0x90, 0x03, 0x17, 0x70, 0xed, 0x4c, 0x12, 0x68, 0x3b, 0x41, 0x2d,
+	0x9d, 0xce, 0x30, 0x8c, 0x58, 0x4b, 0x1b, 0x5b, 0x8d, 0x2c, 0x29, 0xbb, 0x6b, 0x37, 0x5c, 0x39,
+	0x72, 0x06, 0x86, 0x3f, 0x83, 0x9f, 0x47, 0xee, 0x3d, 0xf0, 0xa3, 0xc0, 0x91, 0x61, 0x06, 0xc2,
+	0x85, 0x3b, 0x30, 0xc3, 0x6f, 0x98, 0xfd, 0x21, 0xc9, 0xb2, 0x65, 0xc3, 0x4d, 0x7a, 0xef, 0xbb,
+	0x9f, 0xf7, 0xf6, 0xed, 0x5b, 0x69, 0x17, 0x9c, 0xa1, 0x78, 0x9f, 0x5b, 0xae, 0xcf, 0x09, 0xf5,
+	0xb1, 0xb7, 0x16, 0xd2, 0x80, 0x07, 0x70, 0x96, 0x70, 0xdb, 0x61, 0x84, 0xf6, 0x08, 0x0d, 0x9b,
+	0x4b, 0x0b, 0xad, 0xa0, 0x15, 0x48, 0xc7, 0xba, 0x78, 0x52, 0x9a, 0xa5, 0xf9, 0x44, 0xa3, 0x2d,
+	0x05, 0x1a, 0xda, 0xfa, 0xb1, 0x2c, 0x9c, 0xeb, 0x38, 0x74, 0xd7, 0x7b, 0x84, 0x32, 0x37, 0xf0,
+	0xc3, 0x66, 0xf4, 0xa4, 0x15, 0x17, 0x63, 0x45, 0x87, 0x74, 0x9a, 0x84, 0xb2, 0xb6, 0x1b, 0x86,
+	0xcd, 0xbe, 0x17, 0xa5, 0x5b, 0xf9, 0xd6, 0x00, 0x27, 0x4d, 0x72, 0xd8, 0x25, 0x8c, 0xef, 0x10,
+	0xec, 0x10, 0x0a, 0xe7, 0xc0, 0xc4, 0x6e, 0x03, 0x19, 0x65, 0xa3, 0x32, 0x69, 0x4e, 0xec, 0x36,
+	0xe0, 0x12, 0xc8, 0x77, 0x99, 0xc8, 0xbe, 0x43, 0xd0, 0x44, 0xd9, 0xa8, 0x14, 0xcc, 0xf8, 0x1d,
+	0x5e, 0x06, 0x27, 0x71, 0x97, 0xb7, 0x2d, 0x4a, 0x7a, 0xae, 0x08, 0x8e, 0x72, 0x62, 0xd8, 0xd5,
+	0x99, 0x37, 0x3f, 0x46, 0xb9, 0x8d, 0xb5, 0xa7, 0xcc, 0x59, 0xe1, 0x35, 0xb5, 0x13, 0xae, 0x82,
+	0x02, 0x77, 0x3b, 0x84, 0x71, 0xdc, 0x09, 0xd1, 0x64, 0xd9, 0xa8, 0xe4, 0x22, 0xe5, 0xa6, 0x99,
+	0x78, 0xe0, 0x05, 0x90, 0x27, 0x47, 0xaa, 0x5e, 0x68, 0xaa, 0x6c, 0x54, 0xf2, 0x89, 0x2a, 0x76,
+	0xc0, 0x73, 0x60, 0x8a, 0x06, 0x1e, 0x61, 0x68, 0xba, 0x9c, 0xab, 0x14, 0x12, 0x85, 0xb2, 0x3e,
+	0x3b, 0xf3, 0x86, 0x7c, 0xbf, 0xb2, 0xf2, 0xcd, 0x22, 0x38, 0xb3, 0xab, 0xab, 0x6f, 0xe2, 0x7d,
+	0xae, 0xe7, 0x0a, 0x37, 0xc0, 0x74, 0x5b, 0xce, 0x17, 0x39, 0x65, 0xa3, 0x52, 0xac, 0x2e, 0xaf,
+	0xf5, 0xaf, 0xc9, 0x5a, 0xaa, 0x24, 0xa6, 0x96, 0x0e, 0x95, 0x66, 0x15, 0x4c, 0xf4, 0xaa, 0xb2,
+	0x28, 0xc5, 0xea, 0xd9, 0x4c, 0x80, 0x39, 0xd1, 0xab, 0xc2, 0x2b, 0x60, 0x8a, 0x62, 0xbf, 0x45,
+	0x64, 0x75, 0x8a, 0xd5, 0xa5, 0x01, 0xa5, 0x70, 0x45, 0x72, 0x25, 0x84, 0x97, 0x40, 0x2e, 0xec,
+	0x72, 0x59, 0xa3, 0x62, 0x15, 0xa5, 0xf5, 0x7b, 0xdd, 0x68, 0x12, 0xa6, 0x10, 0xc1, 0x3a, 0x98,
+	0x75, 0x88, 0x47, 0x38, 0xb1, 0x54, 0x90, 0x29, 0x39, 0xa8, 0x9c, 0x1e, 0xd4, 0x90, 0x8a, 0x54,
+	0xa8, 0xa2, 0x93, 0xd8, 0x44, 0x40, 0x7e, 0xe4, 0xa3, 0xe9, 0xac, 0x80, 0xb7, 0x8e, 0xfc, 0x38,
+	0x20, 0x3f, 0xf2, 0xe1, 0x73, 0x00, 0xd8, 0x41, 0x27, 0xc4, 0x36, 0x17, 0x2b, 0x3e, 0x23, 0x87,
+	0x3c, 0x92, 0x1e, 0x52, 0x8f, 0xfd, 0xd1, 0xc8, 0xbe, 0x21, 0xf0, 0x79, 0x50, 0xf4, 0x08, 0x66,
+	0xc4, 0x6a, 0x51, 0xec, 0x73, 0x94, 0xcf, 0x22, 0x5c, 0x13, 0x82, 0x6d, 0xe1, 0x8f, 0x09, 0x5e,
+	0x6c, 0x12, 0x73, 0x56, 0x04, 0x4a, 0x7a, 0xc1, 0x01, 0x41, 0x85, 0xac, 0x39, 0x4b, 0x84, 0x29,
+	0x05, 0xf1, 0x9c, 0xbd, 0xc4, 0x26, 0x96, 0x05, 0x7b, 0x98, 0x76, 0x10, 0xc8, 0x5a, 0x96, 0x9a,
+	0x70, 0xc5, 0xcb, 0x22, 0x85, 0xf0, 0x0e, 0x98, 0x57, 0x61, 0xed, 0x36, 0xb1, 0x0f, 0xc2, 0xc0,
+	0xf5, 0x39, 0x2a, 0xca, 0xc1, 0x8f, 0x66, 0x84, 0xae, 0xc7, 0x22, 0x8d, 0x89, 0xba, 0xf4, 0x69,
+	0xf3, 0x94, 0x97, 0x16, 0xc0, 0x1a, 0x28, 0xca, 0x8d, 0x44, 0x7c, 0xdc, 0xf4, 0x08, 0xfa, 0x31,
+	0xb3, 0xaa, 0xb5, 0x2e, 0x6f, 0x6f, 0x49, 0x41, 0x5c, 0x13, 0x1c, 0x9b, 0x60, 0x03, 0xc8, 0xdd,
+	0x66, 0x39, 0x2e, 0x93, 0x8c, 0x9f, 0x66, 0xb2, 0x8a, 0x22, 0x18, 0x0d, 0xa5, 0x88, 0x8b, 0x82,
+	0x13, 0x1b, 0x7c, 0x41, 0x27, 0xc2, 0x38, 0xe6, 0x5d, 0x86, 0x7e, 0x19, 0x99, 0xc8, 0x4d, 0x29,
+	0x18, 0x98, 0xd9, 0x33, 0x2a, 0x23, 0xe5, 0x83, 0x37, 0x54, 0x46, 0xc4, 0xe7, 0xae, 0x8d, 0x39,
+	0x41, 0x3f, 0x2b, 0xd8, 0xe3, 0x69, 0x58, 0xb4, 0x3b, 0x6b, 0x7d, 0xd2, 0x28, 0xb5, 0xd4, 0x78,
+	0xb8, 0xa5, 0xbf, 0x36, 0xe2, 0xf3, 0x63, 0x61, 0xc7, 0x41, 0x9f, 0xe6, 0x47, 0x4d, 0xf1, 0x65,
+	0x46, 0x68, 0xcd, 0x71, 0x52, 0x53, 0xd4, 0x36, 0x78, 0x03, 0xcc, 0x27, 0x18, 0xb5, 0x09, 0xd0,
+	0x67, 0x8a, 0x74, 0x21, 0x9b, 0xa4, 0x77, 0x8f, 0x86, 0xcd, 0xe1, 0x94, 0x39, 0x9d, 0x56, 0x8b,
+	0x70, 0xf4, 0xf9, 0xd8, 0xb4, 0xb6, 0x09, 0x1f, 0x4a, 0x6b, 0x9b, 0x70, 0xd8, 0x02, 0x0f, 0x27,
+	0x18, 0xbb, 0x2d, 0xb6, 0xa5, 0x15, 0x62, 0xc6, 0xee, 0x05, 0xd4, 0x41, 0x5f, 0x28, 0xe4, 0x13,
+	0xd9, 0xc8, 0xba, 0x54, 0xef, 0x69, 0x71, 0x44, 0x7f, 0x08, 0x67, 0xba, 0xe1, 0x1d, 0xb0, 0xd0,
+	0x97, 0xaf, 0xd8, 0x4f, 0x96, 0xf8, 0x68, 0xa2, 0x07, 0x2a, 0xc6, 0xc5, 0x11, 0x69, 0xcb, 0xbd,
+	0x18, 0x24, 0x6d, 0x73, 0x1a, 0x0f, 0x7a, 0xe0, 0x2b, 0xe0, 0x6c, 0x42, 0x56, 0x5b, 0x53, 0xa1,
+	0xbf, 0x54, 0xe8, 0xc7, 0xb2, 0xd1, 0x7a, 0x8f, 0xf6, 0xb1, 0x21, 0x1e, 0x72, 0xc1, 0x1d, 0x30,
+	0x97, 0xc0, 0x3d, 0x97, 0x71, 0xf4, 0x95, 0xa2, 0x9e, 0xcf, 0xa6, 0x5e, 0x73, 0x19, 0x4f, 0xf5,
+	0x51, 0x64, 0x8c, 0x49, 0x22, 0x35, 0x45, 0xfa, 0x7a, 0x24, 0x49, 0x84, 0x1e, 0x22, 0x45, 0xc6,
+	0x78, 0xe9, 0x25, 0x49, 0x74, 0xe4, 0x7b, 0x85, 0x51, 0x4b, 0x2f, 0xc6, 0x0c, 0x76, 0xa4, 0xb6,
+	0xc5, 0x1d, 0x29, 0x31, 0xba, 0x23, 0xdf, 0x2f, 0x8c, 0xea, 0x48, 0x31, 0x2a, 0xa3, 0x23, 0x13,
+	0x73, 0x3a, 0x2d, 0xd1, 0x91, 0x1f, 0x8c, 0x4d, 0x6b, 0xb0, 0x23, 0xb5, 0x0d, 0xde, 0x05, 0x4b,
+	0x7d, 0x18, 0xd9, 0x28, 0x21, 0xa1, 0x1d, 0x97, 0xc9, 0x5f, 0xfd, 0x87, 0x8a, 0x79, 0x79, 0x04,
+	0x53, 0xc8, 0xf7, 0x62, 0x75, 0xc4, 0x5f, 0xc4, 0xd9, 0x7e, 0xd8, 0x01, 0xcb, 0x49, 0x2c, 0xdd,
+	0x3a, 0x7d, 0xc1, 0x3e, 0x52, 0xc1, 0x9e, 0xcc, 0x0e, 0xa6, 0xba, 0x64, 0x38, 0x1a, 0xc2, 0x23,
+	0x04, 0xf0, 0x35, 0x70, 0xc6, 0xf6, 0xba, 0x8c, 0x13, 0x6a, 0xe9, 0x73, 0x93, 0xc5, 0x08, 0x47,
+	0x6f, 0x01, 0xbd, 0x05, 0xfa, 0x0f, 0x4d, 0x6b, 0x75, 0xa5, 0xbc, 0xad, 0x84, 0x37, 0x09, 0x1f,
+	0xfa, 0xea, 0x9d, 0xb6, 0x07, 0x25, 0xf0, 0x2e, 0x58, 0x8c, 0x22, 0x28, 0x98, 0x85, 0x39, 0xa7,
+	0x32, 0xca, 0xdb, 0x40, 0x7f, 0x07, 0xb3, 0xa2, 0x5c, 0x97, 0xb6, 0x1a, 0xe7, 0x34, 0x2b, 0xd0,
+	0x82, 0x9d, 0xa1, 0x82, 0xaf, 0x02, 0xe8, 0x04, 0xf7, 0xfc, 0x16, 0xc5, 0x0e, 0xb1, 0x5c, 0x7f,
+	0x3f, 0x90, 0x61, 0xde, 0x51, 0x61, 0x56, 0xd3, 0x61, 0x1a, 0x91, 0x70, 0xd7, 0xdf, 0x0f, 0xb2,
+	0x42, 0xcc, 0x3b, 0x03, 0x0a, 0xd8, 0x00, 0x85, 0xc3, 0x6e, 0xc0, 0xb1, 0xa4, 0xfe, 0xaa, 0xa8,
+	0xe7, 0xd2, 0x2b, 0xf1, 0x92, 0xf0, 0x0f, 0xd3, 0x36, 0xcd, 0xfc, 0xa1, 0xf6, 0xc0, 0xeb, 0x60,
+	0x56, 0x51, 0x74, 0x83, 0xff, 0x06, 0xb2, 0x7a, 0x52, 0x82, 0x52, 0xdd, 0x9d, 0xb0, 0x8a, 0x87,
+	0x89, 0x13, 0xde, 0x06, 0xa7, 0x92, 0x23, 0x85, 0xd5, 0x0e, 0x3c, 0x07, 0xfd, 0x0e, 0xb2, 0xb6,
+	0x4c, 0x72, 0x16, 0xd9, 0x09, 0x3c, 0x67, 0x08, 0x3a, 0x67, 0xa7, 0xfc, 0xd0, 0x03, 0x8b, 0x03,
+	0x5c, 0x8b, 0x12, 0xf9, 0xbb, 0x46, 0x7f, 0x28, 0xfe, 0xa5, 0xf1, 0x7c, 0x7d, 0xc4, 0x18, 0x08,
+	0x73, 0xd6, 0xce, 0x92, 0x89, 0xa2, 0xa8, 0x13, 0x05, 0x39, 0x0a, 0x5d, 0x4a, 0xd0, 0x9f, 0xe0,
+	0xff, 0x9d, 0x64, 0xfa, 0x8a, 0x22, 0xc7, 0x6f, 0xc9, 0xe1, 0x09, 0x0e, 0x73, 0x8e, 0xed, 0x36,
+	0xfa, 0x6b, 0x34, 0xae, 0x26, 0x15, 0x23, 0x70, 0xca, 0x99, 0xe0, 0x1c, 0x22, 0x71, 0x7f, 0x8f,
+	0xc6, 0x35, 0xc8, 0x18, 0x9c, 0x72, 0xc2, 0x6d, 0x00, 0x6c, 0xc7, 0xb6, 0xec, 0x2e, 0x65, 0x01,
+	0x45, 0xff, 0x28, 0x58, 0x69, 0xa0, 0x9a, 0x8d, 0x7a, 0x5d, 0xfa, 0x87, 0x50, 0x05, 0xdb, 0xb1,
+	0x95, 0x2b, 0x39, 0xdd, 0x9f, 0x02, 0x27, 0xb7, 0x3a, 0x21, 0x7f, 0xdd, 0x24, 0x2c, 0x0c, 0x7c,
+	0x46, 0x56, 0x3e, 0x31, 0xc0, 0xf2, 0x98, 0x03, 0x05, 0x84, 0x60, 0x52, 0x5e, 0x64, 0x0c, 0x79,
+	0x91, 0x91, 0xcf, 0xe2, 0x82, 0x13, 0xff, 0x67, 0xf5, 0x05, 0x27, 0x7a, 0x87, 0xe7, 0xc1, 0x2c,
+	0x73, 0x3b, 0xa1, 0x47, 0x2c, 0x1e, 0x1c, 0x10, 0x75, 0xbf, 0x29, 0x98, 0x45, 0x65, 0xbb, 0x25,
+	0x4c, 0xa9, 0xeb, 0xca, 0xe4, 0x7f, 0x5e, 0x57, 0xa6, 0xc6, 0x5f, 0x57, 0x5e, 0x04, 0xf3, 0x83,
+	0x15, 0x10, 0x39, 0x33, 0xd7, 0x3f, 0x88, 0x72, 0x16, 0xcf, 0x22, 0xe7, 0xf8, 0xce, 0x25, 0x72,
+	0xce, 0x99, 0xf1, 0x7b, 0x04, 0xdb, 0xbc, 0xba, 0x70, 0xff, 0xfb, 0xd2, 0x89, 0xfb, 0xc7, 0x25,
+	0xe3, 0xc1, 0x71, 0xc9, 0xf8, 0xee, 0xb8, 0x64, 0xbc, 0xfb, 0x43, 0xe9, 0x44, 0x73, 0x5a, 0x5e,
+	0xfc, 0x36, 0xfe, 0x0d, 0x00, 0x00, 0xff, 0xff, 0xc3, 0xe3, 0x65, 0xa4, 0x9a, 0x0e, 0x00, 0x00,
 }
 
 func (m *RequestHeader) Marshal() (dAtA []byte, err error) {
@@ -331,6 +413,30 @@ func (m *RequestHeader) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.Roles) > 0 {
+		for iNdEx := len(m.Roles) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Roles[iNdEx])
+			copy(dAtA[i:], m.Roles[iNdEx])
+			i = encodeVarintRaftInternal(dAtA, i, uint64(len(m.Roles[iNdEx])))
+			i--
+			dAtA[i] = 0x32
+		}
+	}
+	if m.External {
+		i--
+		if m.External {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.Timestamp != 0 {
+		i = encodeVarintRaftInternal(dAtA, i, uint64(m.Timestamp))
+		i--
+		dAtA[i] = 0x20
+	}
 	if m.AuthRevision != 0 {
 		i = encodeVarintRaftInternal(dAtA, i, uint64(m.AuthRevision))
 		i--
@@ -375,6 +481,118 @@ func (m *InternalRaftRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.CdcCursor != nil {
+		{
+			size, err := m.CdcCursor.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRaftInternal(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x57
+		i--
+		dAtA[i] = 0xfa
+	}
+	if m.LeaseDetach != nil {
+		{
+			size, err := m.LeaseDetach.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRaftInternal(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x57
+		i--
+		dAtA[i] = 0xf2
+	}
+	if m.LeaseAttach != nil {
+		{
+			size, err := m.LeaseAttach.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRaftInternal(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x57
+		i--
+		dAtA[i] = 0xea
+	}
+	if m.LeaseExpire != nil {
+		{
+			size, err := m.LeaseExpire.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRaftInternal(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x57
+		i--
+		dAtA[i] = 0xe2
+	}
+	if m.CompactionHoldRelease != nil {
+		{
+			size, err := m.CompactionHoldRelease.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRaftInternal(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x57
+		i--
+		dAtA[i] = 0xda
+	}
+	if m.CompactionHold != nil {
+		{
+			size, err := m.CompactionHold.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRaftInternal(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x57
+		i--
+		dAtA[i] = 0xd2
+	}
+	if m.QuotaDelete != nil {
+		{
+			size, err := m.QuotaDelete.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRaftInternal(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x57
+		i--
+		dAtA[i] = 0xca
+	}
+	if m.QuotaSet != nil {
+		{
+			size, err := m.QuotaSet.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRaftInternal(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x57
+		i--
+		dAtA[i] = 0xc2
+	}
 	if m.DowngradeInfoSet != nil {
 		{
 			size, err := m.DowngradeInfoSet.MarshalToSizedBuffer(dAtA[:i])
@@ -848,6 +1066,25 @@ func (m *InternalAuthenticateRequest) MarshalToSizedBuffer(dAtA []byte) (int, er
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.Roles) > 0 {
+		for iNdEx := len(m.Roles) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Roles[iNdEx])
+			copy(dAtA[i:], m.Roles[iNdEx])
+			i = encodeVarintRaftInternal(dAtA, i, uint64(len(m.Roles[iNdEx])))
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if m.External {
+		i--
+		if m.External {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
+	}
 	if len(m.SimpleToken) > 0 {
 		i -= len(m.SimpleToken)
 		copy(dAtA[i:], m.SimpleToken)
@@ -872,6 +1109,45 @@ func (m *InternalAuthenticateRequest) MarshalToSizedBuffer(dAtA []byte) (int, er
 	return len(dAtA) - i, nil
 }
 
+func (m *CDCCursorRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CDCCursorRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CDCCursorRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.Revision != 0 {
+		i = encodeVarintRaftInternal(dAtA, i, uint64(m.Revision))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Sink) > 0 {
+		i -= len(m.Sink)
+		copy(dAtA[i:], m.Sink)
+		i = encodeVarintRaftInternal(dAtA, i, uint64(len(m.Sink)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintRaftInternal(dAtA []byte, offset int, v uint64) int {
 	offset -= sovRaftInternal(v)
 	base := offset
@@ -899,6 +1175,18 @@ func (m *RequestHeader) Size() (n int) {
 	if m.AuthRevision != 0 {
 		n += 1 + sovRaftInternal(uint64(m.AuthRevision))
 	}
+	if m.Timestamp != 0 {
+		n += 1 + sovRaftInternal(uint64(m.Timestamp))
+	}
+	if m.External {
+		n += 2
+	}
+	if len(m.Roles) > 0 {
+		for _, s := range m.Roles {
+			l = len(s)
+			n += 1 + l + sovRaftInternal(uint64(l))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1038,6 +1326,38 @@ func (m *InternalRaftRequest) Size() (n int) {
 		l = m.DowngradeInfoSet.Size()
 		n += 2 + l + sovRaftInternal(uint64(l))
 	}
+	if m.QuotaSet != nil {
+		l = m.QuotaSet.Size()
+		n += 2 + l + sovRaftInternal(uint64(l))
+	}
+	if m.QuotaDelete != nil {
+		l = m.QuotaDelete.Size()
+		n += 2 + l + sovRaftInternal(uint64(l))
+	}
+	if m.CompactionHold != nil {
+		l = m.CompactionHold.Size()
+		n += 2 + l + sovRaftInternal(uint64(l))
+	}
+	if m.CompactionHoldRelease != nil {
+		l = m.CompactionHoldRelease.Size()
+		n += 2 + l + sovRaftInternal(uint64(l))
+	}
+	if m.LeaseExpire != nil {
+		l = m.LeaseExpire.Size()
+		n += 2 + l + sovRaftInternal(uint64(l))
+	}
+	if m.LeaseAttach != nil {
+		l = m.LeaseAttach.Size()
+		n += 2 + l + sovRaftInternal(uint64(l))
+	}
+	if m.LeaseDetach != nil {
+		l = m.LeaseDetach.Size()
+		n += 2 + l + sovRaftInternal(uint64(l))
+	}
+	if m.CdcCursor != nil {
+		l = m.CdcCursor.Size()
+		n += 2 + l + sovRaftInternal(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1074,6 +1394,34 @@ func (m *InternalAuthenticateRequest) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovRaftInternal(uint64(l))
 	}
+	if m.External {
+		n += 2
+	}
+	if len(m.Roles) > 0 {
+		for _, s := range m.Roles {
+			l = len(s)
+			n += 1 + l + sovRaftInternal(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *CDCCursorRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Sink)
+	if l > 0 {
+		n += 1 + l + sovRaftInternal(uint64(l))
+	}
+	if m.Revision != 0 {
+		n += 1 + sovRaftInternal(uint64(m.Revision))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1185,6 +1533,77 @@ func (m *RequestHeader) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			m.Timestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timestamp |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field External", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.External = bool(v != 0)
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Roles", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Roles = append(m.Roles, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRaftInternal(dAtA[iNdEx:])
@@ -2371,10 +2790,298 @@ func (m *InternalRaftRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipRaftInternal(dAtA[iNdEx:])
-			if err != nil {
+		case 1400:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QuotaSet", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.QuotaSet == nil {
+				m.QuotaSet = &QuotaSetRequest{}
+			}
+			if err := m.QuotaSet.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 1401:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QuotaDelete", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.QuotaDelete == nil {
+				m.QuotaDelete = &QuotaDeleteRequest{}
+			}
+			if err := m.QuotaDelete.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 1402:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CompactionHold", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.CompactionHold == nil {
+				m.CompactionHold = &CompactionHoldRequest{}
+			}
+			if err := m.CompactionHold.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 1403:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CompactionHoldRelease", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.CompactionHoldRelease == nil {
+				m.CompactionHoldRelease = &CompactionHoldReleaseRequest{}
+			}
+			if err := m.CompactionHoldRelease.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 1404:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LeaseExpire", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.LeaseExpire == nil {
+				m.LeaseExpire = &LeaseRevokeRequest{}
+			}
+			if err := m.LeaseExpire.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 1405:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LeaseAttach", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.LeaseAttach == nil {
+				m.LeaseAttach = &LeaseAttachRequest{}
+			}
+			if err := m.LeaseAttach.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 1406:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LeaseDetach", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.LeaseDetach == nil {
+				m.LeaseDetach = &LeaseDetachRequest{}
+			}
+			if err := m.LeaseDetach.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 1407:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CdcCursor", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.CdcCursor == nil {
+				m.CdcCursor = &CDCCursorRequest{}
+			}
+			if err := m.CdcCursor.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRaftInternal(dAtA[iNdEx:])
+			if err != nil {
 				return err
 			}
 			if (skippy < 0) || (iNdEx+skippy) < 0 {
@@ -2569,6 +3276,160 @@ func (m *InternalAuthenticateRequest) Unmarshal(dAtA []byte) error {
 			}
 			m.SimpleToken = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field External", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.External = bool(v != 0)
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Roles", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Roles = append(m.Roles, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRaftInternal(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CDCCursorRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRaftInternal
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CDCCursorRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CDCCursorRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sink", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Sink = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Revision", wireType)
+			}
+			m.Revision = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Revision |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRaftInternal(dAtA[iNdEx:])
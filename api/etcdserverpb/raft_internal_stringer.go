@@ -109,7 +109,7 @@ func (as *txnRequestStringer) String() string {
 }
 
 // requestOpStringer implements a custom proto String to replace value bytes fields with value
-// size fields in any nested txn and put operations.
+// size fields in any nested txn, put and append operations.
 type requestOpStringer struct {
 	Op *RequestOp
 }
@@ -124,6 +124,8 @@ func (as *requestOpStringer) String() string {
 		return fmt.Sprintf("request_put:<%s>", NewLoggablePutRequest(op.RequestPut).String())
 	case *RequestOp_RequestTxn:
 		return fmt.Sprintf("request_txn:<%s>", NewLoggableTxnRequest(op.RequestTxn).String())
+	case *RequestOp_RequestAppend:
+		return fmt.Sprintf("request_append:<%s>", newLoggableAppendRequest(op.RequestAppend).String())
 	default:
 		// nothing to redact
 	}
@@ -181,3 +183,24 @@ func NewLoggablePutRequest(request *PutRequest) *loggablePutRequest {
 func (m *loggablePutRequest) Reset()         { *m = loggablePutRequest{} }
 func (m *loggablePutRequest) String() string { return proto.CompactTextString(m) }
 func (*loggablePutRequest) ProtoMessage()    {}
+
+// loggableAppendRequest implements a custom proto String to replace value bytes field with a
+// value size field.
+// To preserve proto encoding of the key bytes, a faked out proto type is used here.
+type loggableAppendRequest struct {
+	Key       []byte `protobuf:"bytes,1,opt,name=key,proto3"`
+	ValueSize int64  `protobuf:"varint,2,opt,name=value_size,proto3"`
+	PrevKv    bool   `protobuf:"varint,3,opt,name=prev_kv,proto3"`
+}
+
+func newLoggableAppendRequest(request *AppendRequest) *loggableAppendRequest {
+	return &loggableAppendRequest{
+		request.Key,
+		int64(len(request.Value)),
+		request.PrevKv,
+	}
+}
+
+func (m *loggableAppendRequest) Reset()         { *m = loggableAppendRequest{} }
+func (m *loggableAppendRequest) String() string { return proto.CompactTextString(m) }
+func (*loggableAppendRequest) ProtoMessage()    {}
@@ -49,6 +49,38 @@ func (Event_EventType) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_2216fe83c9c12408, []int{1, 0}
 }
 
+type Event_DeleteCause int32
+
+const (
+	// REQUEST is a delete requested by a DeleteRange request or a transaction.
+	REQUEST Event_DeleteCause = 0
+	// LEASE_REVOKED is a delete of a key attached to a lease revoked by a
+	// LeaseRevoke request.
+	LEASE_REVOKED Event_DeleteCause = 1
+	// LEASE_EXPIRED is a delete of a key attached to a lease which expired.
+	LEASE_EXPIRED Event_DeleteCause = 2
+)
+
+var Event_DeleteCause_name = map[int32]string{
+	0: "REQUEST",
+	1: "LEASE_REVOKED",
+	2: "LEASE_EXPIRED",
+}
+
+var Event_DeleteCause_value = map[string]int32{
+	"REQUEST":       0,
+	"LEASE_REVOKED": 1,
+	"LEASE_EXPIRED": 2,
+}
+
+func (x Event_DeleteCause) String() string {
+	return proto.EnumName(Event_DeleteCause_name, int32(x))
+}
+
+func (Event_DeleteCause) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_2216fe83c9c12408, []int{1, 1}
+}
+
 type KeyValue struct {
 	// key is the key in bytes. An empty key is not allowed.
 	Key []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
@@ -116,10 +148,13 @@ type Event struct {
 	// its modification revision set to the revision of deletion.
 	Kv *KeyValue `protobuf:"bytes,2,opt,name=kv,proto3" json:"kv,omitempty"`
 	// prev_kv holds the key-value pair before the event happens.
-	PrevKv               *KeyValue `protobuf:"bytes,3,opt,name=prev_kv,json=prevKv,proto3" json:"prev_kv,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
-	XXX_unrecognized     []byte    `json:"-"`
-	XXX_sizecache        int32     `json:"-"`
+	PrevKv *KeyValue `protobuf:"bytes,3,opt,name=prev_kv,json=prevKv,proto3" json:"prev_kv,omitempty"`
+	// delete_cause is the cause of a DELETE event. The kv of a DELETE event
+	// caused by a lease holds the ID of the lease in its lease field.
+	DeleteCause          Event_DeleteCause `protobuf:"varint,4,opt,name=delete_cause,json=deleteCause,proto3,enum=mvccpb.Event_DeleteCause" json:"delete_cause,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *Event) Reset()         { *m = Event{} }
@@ -157,6 +192,7 @@ var xxx_messageInfo_Event proto.InternalMessageInfo
 
 func init() {
 	proto.RegisterEnum("mvccpb.Event_EventType", Event_EventType_name, Event_EventType_value)
+	proto.RegisterEnum("mvccpb.Event_DeleteCause", Event_DeleteCause_name, Event_DeleteCause_value)
 	proto.RegisterType((*KeyValue)(nil), "mvccpb.KeyValue")
 	proto.RegisterType((*Event)(nil), "mvccpb.Event")
 }
@@ -164,26 +200,31 @@ func init() {
 func init() { proto.RegisterFile("kv.proto", fileDescriptor_2216fe83c9c12408) }
 
 var fileDescriptor_2216fe83c9c12408 = []byte{
-	// 303 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x90, 0x41, 0x4e, 0xc2, 0x40,
-	0x14, 0x86, 0x3b, 0x14, 0x0a, 0x3e, 0x08, 0x36, 0x13, 0x12, 0x27, 0x2e, 0x26, 0x95, 0x8d, 0x18,
-	0x13, 0x4c, 0xf0, 0x06, 0xc6, 0xae, 0x70, 0x61, 0x1a, 0x74, 0x4b, 0x4a, 0x79, 0x21, 0xa4, 0x94,
-	0x69, 0x4a, 0x9d, 0xa4, 0x37, 0x71, 0xef, 0xde, 0x73, 0xb0, 0xe4, 0x08, 0x52, 0x2f, 0x62, 0xfa,
-	0xc6, 0xe2, 0xc6, 0xcd, 0xe4, 0xfd, 0xff, 0xff, 0x65, 0xe6, 0x7f, 0x03, 0x9d, 0x58, 0x8f, 0xd3,
-	0x4c, 0xe5, 0x8a, 0x3b, 0x89, 0x8e, 0xa2, 0x74, 0x71, 0x39, 0x58, 0xa9, 0x95, 0x22, 0xeb, 0xae,
-	0x9a, 0x4c, 0x3a, 0xfc, 0x64, 0xd0, 0x99, 0x62, 0xf1, 0x1a, 0x6e, 0xde, 0x90, 0xbb, 0x60, 0xc7,
-	0x58, 0x08, 0xe6, 0xb1, 0x51, 0x2f, 0xa8, 0x46, 0x7e, 0x0d, 0xe7, 0x51, 0x86, 0x61, 0x8e, 0xf3,
-	0x0c, 0xf5, 0x7a, 0xb7, 0x56, 0x5b, 0xd1, 0xf0, 0xd8, 0xc8, 0x0e, 0xfa, 0xc6, 0x0e, 0x7e, 0x5d,
-	0x7e, 0x05, 0xbd, 0x44, 0x2d, 0xff, 0x28, 0x9b, 0xa8, 0x6e, 0xa2, 0x96, 0x27, 0x44, 0x40, 0x5b,
-	0x63, 0x46, 0x69, 0x93, 0xd2, 0x5a, 0xf2, 0x01, 0xb4, 0x74, 0x55, 0x40, 0xb4, 0xe8, 0x65, 0x23,
-	0x2a, 0x77, 0x83, 0xe1, 0x0e, 0x85, 0x43, 0xb4, 0x11, 0xc3, 0x0f, 0x06, 0x2d, 0x5f, 0xe3, 0x36,
-	0xe7, 0xb7, 0xd0, 0xcc, 0x8b, 0x14, 0xa9, 0x6e, 0x7f, 0x72, 0x31, 0x36, 0x7b, 0x8e, 0x29, 0x34,
-	0xe7, 0xac, 0x48, 0x31, 0x20, 0x88, 0x7b, 0xd0, 0x88, 0x35, 0x75, 0xef, 0x4e, 0xdc, 0x1a, 0xad,
-	0x17, 0x0f, 0x1a, 0xb1, 0xe6, 0x37, 0xd0, 0x4e, 0x33, 0xd4, 0xf3, 0x58, 0x53, 0xf9, 0xff, 0x30,
-	0xa7, 0x02, 0xa6, 0x7a, 0xe8, 0xc1, 0xd9, 0xe9, 0x7e, 0xde, 0x06, 0xfb, 0xf9, 0x65, 0xe6, 0x5a,
-	0x1c, 0xc0, 0x79, 0xf4, 0x9f, 0xfc, 0x99, 0xef, 0xb2, 0x07, 0xb1, 0x3f, 0x4a, 0xeb, 0x70, 0x94,
-	0xd6, 0xbe, 0x94, 0xec, 0x50, 0x4a, 0xf6, 0x55, 0x4a, 0xf6, 0xfe, 0x2d, 0xad, 0x85, 0x43, 0xff,
-	0x7e, 0xff, 0x13, 0x00, 0x00, 0xff, 0xff, 0xb5, 0x45, 0x92, 0x5d, 0xa1, 0x01, 0x00, 0x00,
+	// 377 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x91, 0xcf, 0xaa, 0xda, 0x40,
+	0x14, 0xc6, 0x33, 0x89, 0x26, 0xf6, 0xc4, 0xda, 0x74, 0x10, 0x9a, 0x76, 0x11, 0xd2, 0x6c, 0x6a,
+	0x29, 0x58, 0xb0, 0xdb, 0x2e, 0xfa, 0x27, 0xb3, 0x28, 0x0a, 0xb5, 0xe3, 0x1f, 0xba, 0x0b, 0x31,
+	0x39, 0x88, 0x44, 0x4d, 0x88, 0x71, 0x20, 0x6f, 0xd2, 0x7d, 0xf7, 0x7d, 0x0e, 0x97, 0x3e, 0x42,
+	0xb5, 0x2f, 0x52, 0x32, 0xb9, 0xea, 0xbd, 0x70, 0x37, 0xc3, 0xf9, 0xbe, 0xef, 0x37, 0xcc, 0x77,
+	0x18, 0x68, 0x25, 0xa2, 0x9f, 0xe5, 0x69, 0x91, 0x52, 0x7d, 0x23, 0xa2, 0x28, 0x5b, 0xbc, 0xea,
+	0x2e, 0xd3, 0x65, 0x2a, 0xad, 0xf7, 0xd5, 0x54, 0xa7, 0xde, 0x1f, 0x02, 0xad, 0x21, 0x96, 0xf3,
+	0x70, 0xbd, 0x47, 0x6a, 0x81, 0x96, 0x60, 0x69, 0x13, 0x97, 0xf4, 0xda, 0xbc, 0x1a, 0xe9, 0x1b,
+	0x78, 0x16, 0xe5, 0x18, 0x16, 0x18, 0xe4, 0x28, 0x56, 0xbb, 0x55, 0xba, 0xb5, 0x55, 0x97, 0xf4,
+	0x34, 0xde, 0xa9, 0x6d, 0x7e, 0xe7, 0xd2, 0xd7, 0xd0, 0xde, 0xa4, 0xf1, 0x8d, 0xd2, 0x24, 0x65,
+	0x6e, 0xd2, 0xf8, 0x8a, 0xd8, 0x60, 0x08, 0xcc, 0x65, 0xda, 0x90, 0xe9, 0x45, 0xd2, 0x2e, 0x34,
+	0x45, 0x55, 0xc0, 0x6e, 0xca, 0x97, 0x6b, 0x51, 0xb9, 0x6b, 0x0c, 0x77, 0x68, 0xeb, 0x92, 0xae,
+	0x85, 0xf7, 0x5b, 0x85, 0x26, 0x13, 0xb8, 0x2d, 0xe8, 0x3b, 0x68, 0x14, 0x65, 0x86, 0xb2, 0x6e,
+	0x67, 0xf0, 0xa2, 0x5f, 0xef, 0xd9, 0x97, 0x61, 0x7d, 0x4e, 0xcb, 0x0c, 0xb9, 0x84, 0xa8, 0x0b,
+	0x6a, 0x22, 0x64, 0x77, 0x73, 0x60, 0x5d, 0xd0, 0xcb, 0xe2, 0x5c, 0x4d, 0x04, 0x7d, 0x0b, 0x46,
+	0x96, 0xa3, 0x08, 0x12, 0x21, 0xcb, 0x3f, 0x86, 0xe9, 0x15, 0x30, 0x14, 0xf4, 0x23, 0xb4, 0x63,
+	0x5c, 0x63, 0x81, 0x41, 0x14, 0xee, 0x77, 0x28, 0xd7, 0xe9, 0x0c, 0x5e, 0x3e, 0x6c, 0xe0, 0x4b,
+	0xe2, 0x6b, 0x05, 0x70, 0x33, 0xbe, 0x09, 0xcf, 0x85, 0x27, 0xd7, 0x76, 0xd4, 0x00, 0x6d, 0x3c,
+	0x9b, 0x5a, 0x0a, 0x05, 0xd0, 0x7d, 0x36, 0x62, 0x53, 0x66, 0x11, 0xef, 0x13, 0x98, 0xf7, 0x6e,
+	0x53, 0x13, 0x0c, 0xce, 0x7e, 0xcc, 0xd8, 0xa4, 0xe2, 0x9e, 0xc3, 0xd3, 0x11, 0xfb, 0x3c, 0x61,
+	0x01, 0x67, 0xf3, 0xef, 0x43, 0xe6, 0x5b, 0xe4, 0x66, 0xb1, 0x9f, 0xe3, 0x6f, 0x9c, 0xf9, 0x96,
+	0xfa, 0xc5, 0x3e, 0x9c, 0x1c, 0xe5, 0x78, 0x72, 0x94, 0xc3, 0xd9, 0x21, 0xc7, 0xb3, 0x43, 0xfe,
+	0x9e, 0x1d, 0xf2, 0xeb, 0x9f, 0xa3, 0x2c, 0x74, 0xf9, 0xef, 0x1f, 0xfe, 0x07, 0x00, 0x00, 0xff,
+	0xff, 0xe6, 0x87, 0xf3, 0xa3, 0x21, 0x02, 0x00, 0x00,
 }
 
 func (m *KeyValue) Marshal() (dAtA []byte, err error) {
@@ -271,6 +312,11 @@ func (m *Event) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.DeleteCause != 0 {
+		i = encodeVarintKv(dAtA, i, uint64(m.DeleteCause))
+		i--
+		dAtA[i] = 0x20
+	}
 	if m.PrevKv != nil {
 		{
 			size, err := m.PrevKv.MarshalToSizedBuffer(dAtA[:i])
@@ -363,6 +409,9 @@ func (m *Event) Size() (n int) {
 		l = m.PrevKv.Size()
 		n += 1 + l + sovKv(uint64(l))
 	}
+	if m.DeleteCause != 0 {
+		n += 1 + sovKv(uint64(m.DeleteCause))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -690,6 +739,25 @@ func (m *Event) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DeleteCause", wireType)
+			}
+			m.DeleteCause = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowKv
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DeleteCause |= Event_DeleteCause(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipKv(dAtA[iNdEx:])
@@ -29,13 +29,24 @@ var (
 	ErrGRPCDuplicateKey            = status.New(codes.InvalidArgument, "etcdserver: duplicate key given in txn request").Err()
 	ErrGRPCInvalidClientAPIVersion = status.New(codes.InvalidArgument, "etcdserver: invalid client api version").Err()
 	ErrGRPCInvalidSortOption       = status.New(codes.InvalidArgument, "etcdserver: invalid sort option").Err()
+	ErrGRPCInvalidContinueToken    = status.New(codes.InvalidArgument, "etcdserver: invalid continue token").Err()
+	ErrGRPCInvalidRangeFilter      = status.New(codes.InvalidArgument, "etcdserver: invalid range filter or projection").Err()
+	ErrGRPCInvalidWatchKeyPattern  = status.New(codes.InvalidArgument, "etcdserver: invalid watch key pattern").Err()
+	ErrGRPCInvalidStreamSort       = status.New(codes.InvalidArgument, "etcdserver: range stream only supports ascending key order").Err()
+	ErrGRPCInvalidAtTime           = status.New(codes.InvalidArgument, "etcdserver: at_time cannot be set with revision or in txn").Err()
+	ErrGRPCValueNotInteger         = status.New(codes.FailedPrecondition, "etcdserver: value is not an integer").Err()
+	ErrGRPCValueOutOfRange         = status.New(codes.OutOfRange, "etcdserver: value out of range").Err()
+	ErrGRPCClusterVersionTooLow    = status.New(codes.FailedPrecondition, "etcdserver: request not supported by the cluster version").Err()
 	ErrGRPCCompacted               = status.New(codes.OutOfRange, "etcdserver: mvcc: required revision has been compacted").Err()
 	ErrGRPCFutureRev               = status.New(codes.OutOfRange, "etcdserver: mvcc: required revision is a future revision").Err()
+	ErrGRPCTimeNotIndexed          = status.New(codes.OutOfRange, "etcdserver: no revision indexed at the required time").Err()
 	ErrGRPCNoSpace                 = status.New(codes.ResourceExhausted, "etcdserver: mvcc: database space exceeded").Err()
 
-	ErrGRPCLeaseNotFound    = status.New(codes.NotFound, "etcdserver: requested lease not found").Err()
-	ErrGRPCLeaseExist       = status.New(codes.FailedPrecondition, "etcdserver: lease already exists").Err()
-	ErrGRPCLeaseTTLTooLarge = status.New(codes.OutOfRange, "etcdserver: too large lease TTL").Err()
+	ErrGRPCLeaseNotFound        = status.New(codes.NotFound, "etcdserver: requested lease not found").Err()
+	ErrGRPCLeaseExist           = status.New(codes.FailedPrecondition, "etcdserver: lease already exists").Err()
+	ErrGRPCLeaseTTLTooLarge     = status.New(codes.OutOfRange, "etcdserver: too large lease TTL").Err()
+	ErrGRPCLeaseLabelInvalid    = status.New(codes.InvalidArgument, "etcdserver: invalid lease label").Err()
+	ErrGRPCLabelSelectorInvalid = status.New(codes.InvalidArgument, "etcdserver: invalid lease label selector").Err()
 
 	ErrGRPCWatchCanceled = status.New(codes.Canceled, "etcdserver: watch canceled").Err()
 
@@ -51,6 +62,16 @@ var (
 	ErrGRPCRequestTooLarge        = status.New(codes.InvalidArgument, "etcdserver: request is too large").Err()
 	ErrGRPCRequestTooManyRequests = status.New(codes.ResourceExhausted, "etcdserver: too many requests").Err()
 
+	ErrGRPCQuotaExceeded = status.New(codes.ResourceExhausted, "etcdserver: key prefix quota exceeded").Err()
+	ErrGRPCRateLimited   = status.New(codes.ResourceExhausted, "etcdserver: request rate limit exceeded").Err()
+	ErrGRPCInvalidQuota  = status.New(codes.InvalidArgument, "etcdserver: invalid quota").Err()
+	ErrGRPCQuotaNotFound = status.New(codes.NotFound, "etcdserver: quota not found").Err()
+
+	ErrGRPCCompactionHoldExists   = status.New(codes.FailedPrecondition, "etcdserver: compaction hold already exists").Err()
+	ErrGRPCCompactionHoldNotFound = status.New(codes.NotFound, "etcdserver: compaction hold not found").Err()
+
+	ErrGRPCDefragInProgress = status.New(codes.FailedPrecondition, "etcdserver: online defragmentation is already in progress").Err()
+
 	ErrGRPCRootUserNotExist     = status.New(codes.FailedPrecondition, "etcdserver: root user does not exist").Err()
 	ErrGRPCRootRoleNotExist     = status.New(codes.FailedPrecondition, "etcdserver: root user does not have root role").Err()
 	ErrGRPCUserAlreadyExist     = status.New(codes.FailedPrecondition, "etcdserver: user name already exists").Err()
@@ -68,6 +89,7 @@ var (
 	ErrGRPCInvalidAuthToken     = status.New(codes.Unauthenticated, "etcdserver: invalid auth token").Err()
 	ErrGRPCInvalidAuthMgmt      = status.New(codes.InvalidArgument, "etcdserver: invalid auth management").Err()
 	ErrGRPCAuthOldRevision      = status.New(codes.InvalidArgument, "etcdserver: revision of auth store is old").Err()
+	ErrGRPCInvalidPermPattern   = status.New(codes.InvalidArgument, "etcdserver: invalid permission key pattern").Err()
 
 	ErrGRPCNoLeader                   = status.New(codes.Unavailable, "etcdserver: no leader").Err()
 	ErrGRPCNotLeader                  = status.New(codes.FailedPrecondition, "etcdserver: not leader").Err()
@@ -98,16 +120,27 @@ var (
 		ErrorDesc(ErrGRPCValueProvided): ErrGRPCValueProvided,
 		ErrorDesc(ErrGRPCLeaseProvided): ErrGRPCLeaseProvided,
 
-		ErrorDesc(ErrGRPCTooManyOps):        ErrGRPCTooManyOps,
-		ErrorDesc(ErrGRPCDuplicateKey):      ErrGRPCDuplicateKey,
-		ErrorDesc(ErrGRPCInvalidSortOption): ErrGRPCInvalidSortOption,
-		ErrorDesc(ErrGRPCCompacted):         ErrGRPCCompacted,
-		ErrorDesc(ErrGRPCFutureRev):         ErrGRPCFutureRev,
-		ErrorDesc(ErrGRPCNoSpace):           ErrGRPCNoSpace,
+		ErrorDesc(ErrGRPCTooManyOps):             ErrGRPCTooManyOps,
+		ErrorDesc(ErrGRPCDuplicateKey):           ErrGRPCDuplicateKey,
+		ErrorDesc(ErrGRPCInvalidSortOption):      ErrGRPCInvalidSortOption,
+		ErrorDesc(ErrGRPCInvalidContinueToken):   ErrGRPCInvalidContinueToken,
+		ErrorDesc(ErrGRPCInvalidRangeFilter):     ErrGRPCInvalidRangeFilter,
+		ErrorDesc(ErrGRPCInvalidWatchKeyPattern): ErrGRPCInvalidWatchKeyPattern,
+		ErrorDesc(ErrGRPCInvalidStreamSort):      ErrGRPCInvalidStreamSort,
+		ErrorDesc(ErrGRPCInvalidAtTime):          ErrGRPCInvalidAtTime,
+		ErrorDesc(ErrGRPCValueNotInteger):        ErrGRPCValueNotInteger,
+		ErrorDesc(ErrGRPCValueOutOfRange):        ErrGRPCValueOutOfRange,
+		ErrorDesc(ErrGRPCClusterVersionTooLow):   ErrGRPCClusterVersionTooLow,
+		ErrorDesc(ErrGRPCCompacted):              ErrGRPCCompacted,
+		ErrorDesc(ErrGRPCFutureRev):              ErrGRPCFutureRev,
+		ErrorDesc(ErrGRPCTimeNotIndexed):         ErrGRPCTimeNotIndexed,
+		ErrorDesc(ErrGRPCNoSpace):                ErrGRPCNoSpace,
 
-		ErrorDesc(ErrGRPCLeaseNotFound):    ErrGRPCLeaseNotFound,
-		ErrorDesc(ErrGRPCLeaseExist):       ErrGRPCLeaseExist,
-		ErrorDesc(ErrGRPCLeaseTTLTooLarge): ErrGRPCLeaseTTLTooLarge,
+		ErrorDesc(ErrGRPCLeaseNotFound):        ErrGRPCLeaseNotFound,
+		ErrorDesc(ErrGRPCLeaseExist):           ErrGRPCLeaseExist,
+		ErrorDesc(ErrGRPCLeaseTTLTooLarge):     ErrGRPCLeaseTTLTooLarge,
+		ErrorDesc(ErrGRPCLeaseLabelInvalid):    ErrGRPCLeaseLabelInvalid,
+		ErrorDesc(ErrGRPCLabelSelectorInvalid): ErrGRPCLabelSelectorInvalid,
 
 		ErrorDesc(ErrGRPCMemberExist):            ErrGRPCMemberExist,
 		ErrorDesc(ErrGRPCPeerURLExist):           ErrGRPCPeerURLExist,
@@ -121,6 +154,16 @@ var (
 		ErrorDesc(ErrGRPCRequestTooLarge):        ErrGRPCRequestTooLarge,
 		ErrorDesc(ErrGRPCRequestTooManyRequests): ErrGRPCRequestTooManyRequests,
 
+		ErrorDesc(ErrGRPCQuotaExceeded): ErrGRPCQuotaExceeded,
+		ErrorDesc(ErrGRPCRateLimited):   ErrGRPCRateLimited,
+		ErrorDesc(ErrGRPCInvalidQuota):  ErrGRPCInvalidQuota,
+		ErrorDesc(ErrGRPCQuotaNotFound): ErrGRPCQuotaNotFound,
+
+		ErrorDesc(ErrGRPCCompactionHoldExists):   ErrGRPCCompactionHoldExists,
+		ErrorDesc(ErrGRPCCompactionHoldNotFound): ErrGRPCCompactionHoldNotFound,
+
+		ErrorDesc(ErrGRPCDefragInProgress): ErrGRPCDefragInProgress,
+
 		ErrorDesc(ErrGRPCRootUserNotExist):     ErrGRPCRootUserNotExist,
 		ErrorDesc(ErrGRPCRootRoleNotExist):     ErrGRPCRootRoleNotExist,
 		ErrorDesc(ErrGRPCUserAlreadyExist):     ErrGRPCUserAlreadyExist,
@@ -137,6 +180,7 @@ var (
 		ErrorDesc(ErrGRPCInvalidAuthToken):     ErrGRPCInvalidAuthToken,
 		ErrorDesc(ErrGRPCInvalidAuthMgmt):      ErrGRPCInvalidAuthMgmt,
 		ErrorDesc(ErrGRPCAuthOldRevision):      ErrGRPCAuthOldRevision,
+		ErrorDesc(ErrGRPCInvalidPermPattern):   ErrGRPCInvalidPermPattern,
 
 		ErrorDesc(ErrGRPCNoLeader):                   ErrGRPCNoLeader,
 		ErrorDesc(ErrGRPCNotLeader):                  ErrGRPCNotLeader,
@@ -161,20 +205,31 @@ var (
 
 // client-side error
 var (
-	ErrEmptyKey          = Error(ErrGRPCEmptyKey)
-	ErrKeyNotFound       = Error(ErrGRPCKeyNotFound)
-	ErrValueProvided     = Error(ErrGRPCValueProvided)
-	ErrLeaseProvided     = Error(ErrGRPCLeaseProvided)
-	ErrTooManyOps        = Error(ErrGRPCTooManyOps)
-	ErrDuplicateKey      = Error(ErrGRPCDuplicateKey)
-	ErrInvalidSortOption = Error(ErrGRPCInvalidSortOption)
-	ErrCompacted         = Error(ErrGRPCCompacted)
-	ErrFutureRev         = Error(ErrGRPCFutureRev)
-	ErrNoSpace           = Error(ErrGRPCNoSpace)
-
-	ErrLeaseNotFound    = Error(ErrGRPCLeaseNotFound)
-	ErrLeaseExist       = Error(ErrGRPCLeaseExist)
-	ErrLeaseTTLTooLarge = Error(ErrGRPCLeaseTTLTooLarge)
+	ErrEmptyKey               = Error(ErrGRPCEmptyKey)
+	ErrKeyNotFound            = Error(ErrGRPCKeyNotFound)
+	ErrValueProvided          = Error(ErrGRPCValueProvided)
+	ErrLeaseProvided          = Error(ErrGRPCLeaseProvided)
+	ErrTooManyOps             = Error(ErrGRPCTooManyOps)
+	ErrDuplicateKey           = Error(ErrGRPCDuplicateKey)
+	ErrInvalidSortOption      = Error(ErrGRPCInvalidSortOption)
+	ErrInvalidContinueToken   = Error(ErrGRPCInvalidContinueToken)
+	ErrInvalidRangeFilter     = Error(ErrGRPCInvalidRangeFilter)
+	ErrInvalidWatchKeyPattern = Error(ErrGRPCInvalidWatchKeyPattern)
+	ErrInvalidStreamSort      = Error(ErrGRPCInvalidStreamSort)
+	ErrInvalidAtTime          = Error(ErrGRPCInvalidAtTime)
+	ErrValueNotInteger        = Error(ErrGRPCValueNotInteger)
+	ErrValueOutOfRange        = Error(ErrGRPCValueOutOfRange)
+	ErrClusterVersionTooLow   = Error(ErrGRPCClusterVersionTooLow)
+	ErrCompacted              = Error(ErrGRPCCompacted)
+	ErrFutureRev              = Error(ErrGRPCFutureRev)
+	ErrTimeNotIndexed         = Error(ErrGRPCTimeNotIndexed)
+	ErrNoSpace                = Error(ErrGRPCNoSpace)
+
+	ErrLeaseNotFound        = Error(ErrGRPCLeaseNotFound)
+	ErrLeaseExist           = Error(ErrGRPCLeaseExist)
+	ErrLeaseTTLTooLarge     = Error(ErrGRPCLeaseTTLTooLarge)
+	ErrLeaseLabelInvalid    = Error(ErrGRPCLeaseLabelInvalid)
+	ErrLabelSelectorInvalid = Error(ErrGRPCLabelSelectorInvalid)
 
 	ErrMemberExist            = Error(ErrGRPCMemberExist)
 	ErrPeerURLExist           = Error(ErrGRPCPeerURLExist)
@@ -188,6 +243,16 @@ var (
 	ErrRequestTooLarge = Error(ErrGRPCRequestTooLarge)
 	ErrTooManyRequests = Error(ErrGRPCRequestTooManyRequests)
 
+	ErrQuotaExceeded = Error(ErrGRPCQuotaExceeded)
+	ErrRateLimited   = Error(ErrGRPCRateLimited)
+	ErrInvalidQuota  = Error(ErrGRPCInvalidQuota)
+	ErrQuotaNotFound = Error(ErrGRPCQuotaNotFound)
+
+	ErrCompactionHoldExists   = Error(ErrGRPCCompactionHoldExists)
+	ErrCompactionHoldNotFound = Error(ErrGRPCCompactionHoldNotFound)
+
+	ErrDefragInProgress = Error(ErrGRPCDefragInProgress)
+
 	ErrRootUserNotExist     = Error(ErrGRPCRootUserNotExist)
 	ErrRootRoleNotExist     = Error(ErrGRPCRootRoleNotExist)
 	ErrUserAlreadyExist     = Error(ErrGRPCUserAlreadyExist)
@@ -204,6 +269,7 @@ var (
 	ErrInvalidAuthToken     = Error(ErrGRPCInvalidAuthToken)
 	ErrAuthOldRevision      = Error(ErrGRPCAuthOldRevision)
 	ErrInvalidAuthMgmt      = Error(ErrGRPCInvalidAuthMgmt)
+	ErrInvalidPermPattern   = Error(ErrGRPCInvalidPermPattern)
 
 	ErrNoLeader                   = Error(ErrGRPCNoLeader)
 	ErrNotLeader                  = Error(ErrGRPCNotLeader)
@@ -56,6 +56,16 @@ const (
 
 type UserAddOptions authpb.UserAddOptions
 
+// PermissionOptions are the options of a permission granted to a role.
+type PermissionOptions struct {
+	// Deny denies the access to the keys even if other permissions grant it.
+	Deny bool
+	// Pattern makes the key a glob pattern, where '*' matches any sequence of
+	// characters other than '/'. A non-empty range end makes the pattern match
+	// every key beginning with a match.
+	Pattern bool
+}
+
 type Auth interface {
 	// Authenticate login and get token
 	Authenticate(ctx context.Context, name string, password string) (*AuthenticateResponse, error)
@@ -99,6 +109,9 @@ type Auth interface {
 	// RoleGrantPermission grants a permission to a role.
 	RoleGrantPermission(ctx context.Context, name string, key, rangeEnd string, permType PermissionType) (*AuthRoleGrantPermissionResponse, error)
 
+	// RoleGrantPermissionWithOptions grants a permission to a role with some options.
+	RoleGrantPermissionWithOptions(ctx context.Context, name string, key, rangeEnd string, permType PermissionType, opt *PermissionOptions) (*AuthRoleGrantPermissionResponse, error)
+
 	// RoleGet gets a detailed information of a role.
 	RoleGet(ctx context.Context, role string) (*AuthRoleGetResponse, error)
 
@@ -108,6 +121,10 @@ type Auth interface {
 	// RoleRevokePermission revokes a permission from a role.
 	RoleRevokePermission(ctx context.Context, role string, key, rangeEnd string) (*AuthRoleRevokePermissionResponse, error)
 
+	// RoleRevokePermissionWithOptions revokes from a role only the permission
+	// with the same type and options.
+	RoleRevokePermissionWithOptions(ctx context.Context, role string, key, rangeEnd string, permType PermissionType, opt *PermissionOptions) (*AuthRoleRevokePermissionResponse, error)
+
 	// RoleDelete deletes a role.
 	RoleDelete(ctx context.Context, role string) (*AuthRoleDeleteResponse, error)
 }
@@ -208,6 +225,20 @@ func (auth *authClient) RoleGrantPermission(ctx context.Context, name string, ke
 	return (*AuthRoleGrantPermissionResponse)(resp), toErr(ctx, err)
 }
 
+func (auth *authClient) RoleGrantPermissionWithOptions(ctx context.Context, name string, key, rangeEnd string, permType PermissionType, opt *PermissionOptions) (*AuthRoleGrantPermissionResponse, error) {
+	perm := &authpb.Permission{
+		Key:      []byte(key),
+		RangeEnd: []byte(rangeEnd),
+		PermType: authpb.Permission_Type(permType),
+	}
+	if opt != nil {
+		perm.Deny = opt.Deny
+		perm.Pattern = opt.Pattern
+	}
+	resp, err := auth.remote.RoleGrantPermission(ctx, &pb.AuthRoleGrantPermissionRequest{Name: name, Perm: perm}, auth.callOpts...)
+	return (*AuthRoleGrantPermissionResponse)(resp), toErr(ctx, err)
+}
+
 func (auth *authClient) RoleGet(ctx context.Context, role string) (*AuthRoleGetResponse, error) {
 	resp, err := auth.remote.RoleGet(ctx, &pb.AuthRoleGetRequest{Role: role}, auth.callOpts...)
 	return (*AuthRoleGetResponse)(resp), toErr(ctx, err)
@@ -223,6 +254,21 @@ func (auth *authClient) RoleRevokePermission(ctx context.Context, role string, k
 	return (*AuthRoleRevokePermissionResponse)(resp), toErr(ctx, err)
 }
 
+func (auth *authClient) RoleRevokePermissionWithOptions(ctx context.Context, role string, key, rangeEnd string, permType PermissionType, opt *PermissionOptions) (*AuthRoleRevokePermissionResponse, error) {
+	perm := &authpb.Permission{
+		Key:      []byte(key),
+		RangeEnd: []byte(rangeEnd),
+		PermType: authpb.Permission_Type(permType),
+	}
+	if opt != nil {
+		perm.Deny = opt.Deny
+		perm.Pattern = opt.Pattern
+	}
+	r := &pb.AuthRoleRevokePermissionRequest{Role: role, Key: []byte(key), RangeEnd: []byte(rangeEnd), Perm: perm}
+	resp, err := auth.remote.RoleRevokePermission(ctx, r, auth.callOpts...)
+	return (*AuthRoleRevokePermissionResponse)(resp), toErr(ctx, err)
+}
+
 func (auth *authClient) RoleDelete(ctx context.Context, role string) (*AuthRoleDeleteResponse, error) {
 	resp, err := auth.remote.RoleDelete(ctx, &pb.AuthRoleDeleteRequest{Role: role}, auth.callOpts...)
 	return (*AuthRoleDeleteResponse)(resp), toErr(ctx, err)
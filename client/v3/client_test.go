@@ -390,7 +390,7 @@ func (mm mockMaintenance) AlarmDisarm(ctx context.Context, m *AlarmMember) (*Ala
 	return nil, nil
 }
 
-func (mm mockMaintenance) Defragment(ctx context.Context, endpoint string) (*DefragmentResponse, error) {
+func (mm mockMaintenance) Defragment(ctx context.Context, endpoint string, opts ...DefragmentOption) (*DefragmentResponse, error) {
 	return nil, nil
 }
 
@@ -414,6 +414,34 @@ func (mm mockMaintenance) Downgrade(ctx context.Context, action DowngradeAction,
 	return nil, nil
 }
 
+func (mm mockMaintenance) QuotaSet(ctx context.Context, q *Quota) (*QuotaSetResponse, error) {
+	return nil, nil
+}
+
+func (mm mockMaintenance) QuotaDelete(ctx context.Context, typ QuotaType, name string) (*QuotaDeleteResponse, error) {
+	return nil, nil
+}
+
+func (mm mockMaintenance) QuotaList(ctx context.Context) (*QuotaListResponse, error) {
+	return nil, nil
+}
+
+func (mm mockMaintenance) CompactionRetention(ctx context.Context) (*CompactionRetentionResponse, error) {
+	return nil, nil
+}
+
+func (mm mockMaintenance) CompactionHold(ctx context.Context, rev int64, leaseID LeaseID) (*CompactionHoldResponse, error) {
+	return nil, nil
+}
+
+func (mm mockMaintenance) CompactionHoldRelease(ctx context.Context, id int64) (*CompactionHoldReleaseResponse, error) {
+	return nil, nil
+}
+
+func (mm mockMaintenance) CompactionHoldList(ctx context.Context) (*CompactionHoldListResponse, error) {
+	return nil, nil
+}
+
 type mockAuthServer struct {
 	*etcdserverpb.UnimplementedAuthServer
 }
@@ -16,6 +16,7 @@ package clientv3
 
 import (
 	"context"
+	"io"
 
 	"google.golang.org/grpc"
 
@@ -27,6 +28,7 @@ type (
 	CompactResponse pb.CompactionResponse
 	PutResponse     pb.PutResponse
 	GetResponse     pb.RangeResponse
+	HistoryResponse pb.HistoryResponse
 	DeleteResponse  pb.DeleteRangeResponse
 	TxnResponse     pb.TxnResponse
 )
@@ -48,6 +50,25 @@ type KV interface {
 	// When passed WithSort(), the keys will be sorted.
 	Get(ctx context.Context, key string, opts ...OpOption) (*GetResponse, error)
 
+	// GetStream retrieves keys like Get, but the response is streamed back in several
+	// parts, all read at the same revision, so that large ranges are neither bounded by
+	// the maximum message size nor held in memory as a whole.
+	// The keys are always returned in ascending key order; other sort options fail with
+	// rpctypes.ErrInvalidStreamSort. Canceling ctx stops the stream.
+	GetStream(ctx context.Context, key string, opts ...OpOption) (GetStreamReader, error)
+
+	// History retrieves every revision of the keys, including the deletions, ordered
+	// by revision. A deletion is a key-value pair with a zero version.
+	// The keys are selected like in Get, with WithRange(end), WithPrefix() or WithFromKey().
+	// When passed WithMinModRev(rev), the history starts at rev instead of the compact
+	// revision; if rev is compacted, the request will fail with ErrCompacted, unless the
+	// keys are under the prefix of a compaction retention rule of the server, whose
+	// history starts at the oldest revision kept by the compactions.
+	// When passed WithMaxModRev(rev), the history ends at rev instead of the current revision.
+	// When passed WithLimit(limit), the history is truncated after limit key-value pairs,
+	// except that the key-value pairs of the same revision are never split.
+	History(ctx context.Context, key string, opts ...OpOption) (*HistoryResponse, error)
+
 	// Delete deletes a key, or optionally using WithRange(end), [key, end).
 	Delete(ctx context.Context, key string, opts ...OpOption) (*DeleteResponse, error)
 
@@ -65,6 +86,14 @@ type KV interface {
 	Txn(ctx context.Context) Txn
 }
 
+// GetStreamReader receives the parts of a GetStream response.
+type GetStreamReader interface {
+	// Recv returns the next part of the response. Every part has the header and
+	// the count of the whole response; More is only set on the last part if the
+	// response was truncated by the limit. Recv returns io.EOF after the last part.
+	Recv() (*GetResponse, error)
+}
+
 type OpResponse struct {
 	put *PutResponse
 	get *GetResponse
@@ -121,6 +150,42 @@ func (kv *kv) Get(ctx context.Context, key string, opts ...OpOption) (*GetRespon
 	return r.get, toErr(ctx, err)
 }
 
+func (kv *kv) GetStream(ctx context.Context, key string, opts ...OpOption) (GetStreamReader, error) {
+	op := OpGet(key, opts...)
+	if !op.IsSortOptionValid() {
+		return nil, rpctypes.ErrInvalidSortOption
+	}
+	stream, err := kv.remote.RangeStream(ctx, op.toRangeRequest(), kv.callOpts...)
+	if err != nil {
+		return nil, toErr(ctx, err)
+	}
+	return &getStreamReader{ctx: ctx, stream: stream}, nil
+}
+
+type getStreamReader struct {
+	ctx    context.Context
+	stream pb.KV_RangeStreamClient
+}
+
+func (r *getStreamReader) Recv() (*GetResponse, error) {
+	resp, err := r.stream.Recv()
+	if err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		return nil, toErr(r.ctx, err)
+	}
+	return (*GetResponse)(resp), nil
+}
+
+func (kv *kv) History(ctx context.Context, key string, opts ...OpOption) (*HistoryResponse, error) {
+	resp, err := kv.remote.History(ctx, OpGet(key, opts...).toHistoryRequest(), kv.callOpts...)
+	if err != nil {
+		return nil, toErr(ctx, err)
+	}
+	return (*HistoryResponse)(resp), nil
+}
+
 func (kv *kv) Delete(ctx context.Context, key string, opts ...OpOption) (*DeleteResponse, error) {
 	r, err := kv.Do(ctx, OpDelete(key, opts...))
 	return r.del, toErr(ctx, err)
@@ -175,6 +240,14 @@ func (kv *kv) Do(ctx context.Context, op Op) (OpResponse, error) {
 		if err == nil {
 			return OpResponse{txn: (*TxnResponse)(resp)}, nil
 		}
+	case tIncr, tAppend:
+		// only executed in a txn
+		var resp *pb.TxnResponse
+		r := &pb.TxnRequest{Success: []*pb.RequestOp{op.toRequestOp()}}
+		resp, err = kv.remote.Txn(ctx, r, kv.callOpts...)
+		if err == nil {
+			return OpResponse{txn: (*TxnResponse)(resp)}, nil
+		}
 	default:
 		panic("Unknown op")
 	}
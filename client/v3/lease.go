@@ -29,6 +29,9 @@ import (
 
 type (
 	LeaseRevokeResponse pb.LeaseRevokeResponse
+	LeaseAttachResponse pb.LeaseAttachResponse
+	LeaseDetachResponse pb.LeaseDetachResponse
+	LeaseListResponse   pb.LeaseListResponse
 	LeaseID             int64
 )
 
@@ -60,6 +63,21 @@ type LeaseTimeToLiveResponse struct {
 
 	// Keys is the list of keys attached to this lease.
 	Keys [][]byte `json:"keys"`
+
+	// Parent is the lease this lease is revoked with, NoLease if none.
+	Parent LeaseID `json:"parent"`
+
+	// Children are the leases revoked with this lease.
+	Children []LeaseID `json:"children"`
+
+	// Labels are the labels of this lease.
+	Labels map[string]string `json:"labels"`
+
+	// More indicates if there are more attached keys than listed with WithLeaseLimit.
+	More bool `json:"more"`
+
+	// ContinueToken is passed to WithLeaseContinue to list the following keys.
+	ContinueToken []byte `json:"continue-token"`
 }
 
 // LeaseStatus represents a lease status.
@@ -109,6 +127,15 @@ type Lease interface {
 	// Grant creates a new lease.
 	Grant(ctx context.Context, ttl int64) (*LeaseGrantResponse, error)
 
+	// GrantChild creates a new lease revoked with the given parent lease.
+	// The child lease never outlives its parent, whatever its TTL.
+	GrantChild(ctx context.Context, parent LeaseID, ttl int64) (*LeaseGrantResponse, error)
+
+	// GrantWithOptions creates a new lease. With WithLeaseLabels, the lease
+	// has the given labels. With WithLeaseParent, the lease is a child of the
+	// given lease, as with GrantChild.
+	GrantWithOptions(ctx context.Context, ttl int64, opts ...LeaseOption) (*LeaseGrantResponse, error)
+
 	// Revoke revokes the given lease.
 	Revoke(ctx context.Context, id LeaseID) (*LeaseRevokeResponse, error)
 
@@ -118,6 +145,21 @@ type Lease interface {
 	// Leases retrieves all leases.
 	Leases(ctx context.Context) (*LeaseLeasesResponse, error)
 
+	// List retrieves the leases matching WithLabelSelector in ID order, with
+	// their labels, in pages of WithLeaseLimit leases.
+	List(ctx context.Context, opts ...LeaseOption) (*LeaseListResponse, error)
+
+	// Attach attaches the given keys to the lease in place of their current
+	// lease, without writing new revisions of the keys. The keys are attached
+	// atomically, and the keys which do not exist are ignored. With
+	// WithPrefixKeys, all the keys prefixed by the given keys are attached.
+	Attach(ctx context.Context, id LeaseID, keys []string, opts ...LeaseOption) (*LeaseAttachResponse, error)
+
+	// Detach detaches the given keys from their lease, without writing new
+	// revisions of the keys. With WithPrefixKeys, all the keys prefixed by
+	// the given keys are detached.
+	Detach(ctx context.Context, keys []string, opts ...LeaseOption) (*LeaseDetachResponse, error)
+
 	// KeepAlive attempts to keep the given lease alive forever. If the keepalive responses posted
 	// to the channel are not consumed promptly the channel may become full. When full, the lease
 	// client will continue sending keep alive requests to the etcd server, but will drop responses
@@ -212,7 +254,18 @@ func NewLeaseFromLeaseClient(remote pb.LeaseClient, c *Client, keepAliveTimeout
 }
 
 func (l *lessor) Grant(ctx context.Context, ttl int64) (*LeaseGrantResponse, error) {
-	r := &pb.LeaseGrantRequest{TTL: ttl}
+	return l.grant(ctx, toLeaseGrantRequest(ttl))
+}
+
+func (l *lessor) GrantChild(ctx context.Context, parent LeaseID, ttl int64) (*LeaseGrantResponse, error) {
+	return l.grant(ctx, toLeaseGrantRequest(ttl, WithLeaseParent(parent)))
+}
+
+func (l *lessor) GrantWithOptions(ctx context.Context, ttl int64, opts ...LeaseOption) (*LeaseGrantResponse, error) {
+	return l.grant(ctx, toLeaseGrantRequest(ttl, opts...))
+}
+
+func (l *lessor) grant(ctx context.Context, r *pb.LeaseGrantRequest) (*LeaseGrantResponse, error) {
 	resp, err := l.remote.LeaseGrant(ctx, r, l.callOpts...)
 	if err == nil {
 		gresp := &LeaseGrantResponse{
@@ -247,6 +300,13 @@ func (l *lessor) TimeToLive(ctx context.Context, id LeaseID, opts ...LeaseOption
 		TTL:            resp.TTL,
 		GrantedTTL:     resp.GrantedTTL,
 		Keys:           resp.Keys,
+		Parent:         LeaseID(resp.Parent),
+		Labels:         resp.Labels,
+		More:           resp.More,
+		ContinueToken:  resp.ContinueToken,
+	}
+	for _, id := range resp.Children {
+		gresp.Children = append(gresp.Children, LeaseID(id))
 	}
 	return gresp, nil
 }
@@ -263,6 +323,32 @@ func (l *lessor) Leases(ctx context.Context) (*LeaseLeasesResponse, error) {
 	return nil, toErr(ctx, err)
 }
 
+func (l *lessor) List(ctx context.Context, opts ...LeaseOption) (*LeaseListResponse, error) {
+	resp, err := l.remote.LeaseList(ctx, toLeaseListRequest(opts...), l.callOpts...)
+	if err == nil {
+		return (*LeaseListResponse)(resp), nil
+	}
+	return nil, toErr(ctx, err)
+}
+
+func (l *lessor) Attach(ctx context.Context, id LeaseID, keys []string, opts ...LeaseOption) (*LeaseAttachResponse, error) {
+	r := toLeaseAttachRequest(id, keys, opts...)
+	resp, err := l.remote.LeaseAttach(ctx, r, l.callOpts...)
+	if err == nil {
+		return (*LeaseAttachResponse)(resp), nil
+	}
+	return nil, toErr(ctx, err)
+}
+
+func (l *lessor) Detach(ctx context.Context, keys []string, opts ...LeaseOption) (*LeaseDetachResponse, error) {
+	r := toLeaseAttachRequest(NoLease, keys, opts...)
+	resp, err := l.remote.LeaseDetach(ctx, &pb.LeaseDetachRequest{Keys: r.Keys, Prefix: r.Prefix}, l.callOpts...)
+	if err == nil {
+		return (*LeaseDetachResponse)(resp), nil
+	}
+	return nil, toErr(ctx, err)
+}
+
 func (l *lessor) KeepAlive(ctx context.Context, id LeaseID) (<-chan *LeaseKeepAliveResponse, error) {
 	ch := make(chan *LeaseKeepAliveResponse, LeaseResponseChSize)
 
@@ -174,6 +174,10 @@ func (lc *leaseCache) delete(key string, hdr *v3pb.ResponseHeader) {
 func (lc *leaseCache) Evict(key string) (rev int64) {
 	lc.mu.Lock()
 	defer lc.mu.Unlock()
+	return lc.evict(key)
+}
+
+func (lc *leaseCache) evict(key string) (rev int64) {
 	if li := lc.entries[key]; li != nil {
 		rev = li.rev
 		delete(lc.entries, key)
@@ -193,7 +197,7 @@ func (lc *leaseCache) EvictRange(key, end string) {
 	}
 }
 
-func isBadOp(op v3.Op) bool { return op.Rev() > 0 || len(op.RangeBytes()) > 0 }
+func isBadOp(op v3.Op) bool { return op.Rev() > 0 || op.AtTime() != 0 || len(op.RangeBytes()) > 0 }
 
 func (lc *leaseCache) Get(ctx context.Context, op v3.Op) (*v3.GetResponse, bool) {
 	if isBadOp(op) {
@@ -86,6 +86,16 @@ func (lkv *leasingKV) Get(ctx context.Context, key string, opts ...v3.OpOption)
 	return lkv.get(ctx, v3.OpGet(key, opts...))
 }
 
+// GetStream is not served from the leasing cache since the range is read in several parts.
+func (lkv *leasingKV) GetStream(ctx context.Context, key string, opts ...v3.OpOption) (v3.GetStreamReader, error) {
+	return lkv.kv.GetStream(ctx, key, opts...)
+}
+
+// History is not served from the leasing cache since the cache only holds the latest revisions.
+func (lkv *leasingKV) History(ctx context.Context, key string, opts ...v3.OpOption) (*v3.HistoryResponse, error) {
+	return lkv.kv.History(ctx, key, opts...)
+}
+
 func (lkv *leasingKV) Put(ctx context.Context, key, val string, opts ...v3.OpOption) (*v3.PutResponse, error) {
 	return lkv.put(ctx, v3.OpPut(key, val, opts...))
 }
@@ -109,6 +119,9 @@ func (lkv *leasingKV) Do(ctx context.Context, op v3.Op) (v3.OpResponse, error) {
 		cmps, thenOps, elseOps := op.Txn()
 		resp, err := lkv.Txn(ctx).If(cmps...).Then(thenOps...).Else(elseOps...).Commit()
 		return resp.OpResponse(), err
+	case op.IsIncr(), op.IsAppend():
+		resp, err := lkv.Txn(ctx).Then(op).Commit()
+		return resp.OpResponse(), err
 	}
 	return v3.OpResponse{}, nil
 }
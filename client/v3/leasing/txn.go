@@ -174,6 +174,10 @@ func (txn *txnLeasing) commitToCache(txnResp *v3pb.TxnResponse, userTxn v3.Op) {
 		if op.IsPut() {
 			txn.lkv.leases.Update(op.KeyBytes(), op.ValueBytes(), txnResp.Header)
 		}
+		if op.IsIncr() || op.IsAppend() {
+			// the new value is computed by the server
+			txn.lkv.leases.evict(key)
+		}
 	}
 	txn.lkv.leases.mu.Unlock()
 }
@@ -36,6 +36,20 @@ type (
 	DowngradeResponse  pb.DowngradeResponse
 
 	DowngradeAction pb.DowngradeRequest_DowngradeAction
+
+	Quota               pb.Quota
+	QuotaType           pb.Quota_Type
+	QuotaSetResponse    pb.QuotaSetResponse
+	QuotaDeleteResponse pb.QuotaDeleteResponse
+	QuotaListResponse   pb.QuotaListResponse
+
+	CompactionRetentionRule     pb.CompactionRetentionRule
+	CompactionRetentionResponse pb.CompactionRetentionResponse
+
+	CompactionHold                pb.CompactionHold
+	CompactionHoldResponse        pb.CompactionHoldResponse
+	CompactionHoldReleaseResponse pb.CompactionHoldReleaseResponse
+	CompactionHoldListResponse    pb.CompactionHoldListResponse
 )
 
 const (
@@ -44,6 +58,12 @@ const (
 	DowngradeCancel   = DowngradeAction(pb.DowngradeRequest_CANCEL)
 )
 
+const (
+	QuotaPrefix = QuotaType(pb.Quota_PREFIX)
+	QuotaUser   = QuotaType(pb.Quota_USER)
+	QuotaRole   = QuotaType(pb.Quota_ROLE)
+)
+
 type Maintenance interface {
 	// AlarmList gets all active alarms.
 	AlarmList(ctx context.Context) (*AlarmResponse, error)
@@ -58,7 +78,7 @@ type Maintenance interface {
 	// at the same time.
 	// To defragment multiple members in the cluster, user need to call defragment multiple
 	// times with different endpoints.
-	Defragment(ctx context.Context, endpoint string) (*DefragmentResponse, error)
+	Defragment(ctx context.Context, endpoint string, opts ...DefragmentOption) (*DefragmentResponse, error)
 
 	// Status gets the status of the endpoint.
 	Status(ctx context.Context, endpoint string) (*StatusResponse, error)
@@ -87,6 +107,48 @@ type Maintenance interface {
 	// on the cluster version.
 	// Supported since etcd 3.5.
 	Downgrade(ctx context.Context, action DowngradeAction, version string) (*DowngradeResponse, error)
+
+	// QuotaSet sets a storage quota on a key prefix or a request rate limit on a user or role,
+	// replacing the existing one.
+	// Supported since etcd 3.6.
+	QuotaSet(ctx context.Context, q *Quota) (*QuotaSetResponse, error)
+
+	// QuotaDelete deletes the quota of the given type. The name is the key prefix of a
+	// QuotaPrefix quota, or the user or role name of a rate limit.
+	// Supported since etcd 3.6.
+	QuotaDelete(ctx context.Context, typ QuotaType, name string) (*QuotaDeleteResponse, error)
+
+	// QuotaList lists the storage quotas with their usage and the request rate limits.
+	// Supported since etcd 3.6.
+	QuotaList(ctx context.Context) (*QuotaListResponse, error)
+
+	// CompactionRetention lists the retention rules of the member keeping revisions from
+	// being compacted.
+	// Supported since etcd 3.6.
+	CompactionRetention(ctx context.Context) (*CompactionRetentionResponse, error)
+
+	// CompactionHold acquires a hold keeping the given revision, or the current revision
+	// if 0, from being compacted until the hold is released or the lease is revoked.
+	// Supported since etcd 3.6.
+	CompactionHold(ctx context.Context, rev int64, leaseID LeaseID) (*CompactionHoldResponse, error)
+
+	// CompactionHoldRelease releases the compaction hold of the given ID.
+	// Supported since etcd 3.6.
+	CompactionHoldRelease(ctx context.Context, id int64) (*CompactionHoldReleaseResponse, error)
+
+	// CompactionHoldList lists the active compaction holds.
+	// Supported since etcd 3.6.
+	CompactionHoldList(ctx context.Context) (*CompactionHoldListResponse, error)
+}
+
+// DefragmentOption configures a defragment request.
+type DefragmentOption func(*pb.DefragmentRequest)
+
+// WithOnlineDefragment makes Defragment copy the backend database of the member
+// in chunks, only blocking its reads and writes while swapping the database file.
+// Supported since etcd 3.6.
+func WithOnlineDefragment() DefragmentOption {
+	return func(r *pb.DefragmentRequest) { r.Online = true }
 }
 
 // SnapshotResponse is aggregated response from the snapshot stream.
@@ -188,13 +250,17 @@ func (m *maintenance) AlarmDisarm(ctx context.Context, am *AlarmMember) (*AlarmR
 	return nil, toErr(ctx, err)
 }
 
-func (m *maintenance) Defragment(ctx context.Context, endpoint string) (*DefragmentResponse, error) {
+func (m *maintenance) Defragment(ctx context.Context, endpoint string, opts ...DefragmentOption) (*DefragmentResponse, error) {
 	remote, cancel, err := m.dial(endpoint)
 	if err != nil {
 		return nil, toErr(ctx, err)
 	}
 	defer cancel()
-	resp, err := remote.Defragment(ctx, &pb.DefragmentRequest{}, m.callOpts...)
+	req := &pb.DefragmentRequest{}
+	for _, opt := range opts {
+		opt(req)
+	}
+	resp, err := remote.Defragment(ctx, req, m.callOpts...)
 	if err != nil {
 		return nil, toErr(ctx, err)
 	}
@@ -348,3 +414,44 @@ func (m *maintenance) Downgrade(ctx context.Context, action DowngradeAction, ver
 	resp, err := m.remote.Downgrade(ctx, &pb.DowngradeRequest{Action: actionType, Version: version}, m.callOpts...)
 	return (*DowngradeResponse)(resp), toErr(ctx, err)
 }
+
+func (m *maintenance) QuotaSet(ctx context.Context, q *Quota) (*QuotaSetResponse, error) {
+	resp, err := m.remote.QuotaSet(ctx, &pb.QuotaSetRequest{Quota: (*pb.Quota)(q)}, m.callOpts...)
+	return (*QuotaSetResponse)(resp), toErr(ctx, err)
+}
+
+func (m *maintenance) QuotaDelete(ctx context.Context, typ QuotaType, name string) (*QuotaDeleteResponse, error) {
+	req := &pb.QuotaDeleteRequest{Type: pb.Quota_Type(typ)}
+	if typ == QuotaPrefix {
+		req.Prefix = []byte(name)
+	} else {
+		req.Name = name
+	}
+	resp, err := m.remote.QuotaDelete(ctx, req, m.callOpts...)
+	return (*QuotaDeleteResponse)(resp), toErr(ctx, err)
+}
+
+func (m *maintenance) QuotaList(ctx context.Context) (*QuotaListResponse, error) {
+	resp, err := m.remote.QuotaList(ctx, &pb.QuotaListRequest{}, m.callOpts...)
+	return (*QuotaListResponse)(resp), toErr(ctx, err)
+}
+
+func (m *maintenance) CompactionRetention(ctx context.Context) (*CompactionRetentionResponse, error) {
+	resp, err := m.remote.CompactionRetention(ctx, &pb.CompactionRetentionRequest{}, m.callOpts...)
+	return (*CompactionRetentionResponse)(resp), toErr(ctx, err)
+}
+
+func (m *maintenance) CompactionHold(ctx context.Context, rev int64, leaseID LeaseID) (*CompactionHoldResponse, error) {
+	resp, err := m.remote.CompactionHold(ctx, &pb.CompactionHoldRequest{Revision: rev, Lease: int64(leaseID)}, m.callOpts...)
+	return (*CompactionHoldResponse)(resp), toErr(ctx, err)
+}
+
+func (m *maintenance) CompactionHoldRelease(ctx context.Context, id int64) (*CompactionHoldReleaseResponse, error) {
+	resp, err := m.remote.CompactionHoldRelease(ctx, &pb.CompactionHoldReleaseRequest{ID: id}, m.callOpts...)
+	return (*CompactionHoldReleaseResponse)(resp), toErr(ctx, err)
+}
+
+func (m *maintenance) CompactionHoldList(ctx context.Context) (*CompactionHoldListResponse, error) {
+	resp, err := m.remote.CompactionHoldList(ctx, &pb.CompactionHoldListRequest{}, m.callOpts...)
+	return (*CompactionHoldListResponse)(resp), toErr(ctx, err)
+}
@@ -171,6 +171,14 @@ func (m *mockKVServer) Range(context.Context, *pb.RangeRequest) (*pb.RangeRespon
 	return &pb.RangeResponse{}, nil
 }
 
+func (m *mockKVServer) RangeStream(_ *pb.RangeRequest, stream pb.KV_RangeStreamServer) error {
+	return stream.Send(&pb.RangeResponse{})
+}
+
+func (m *mockKVServer) History(context.Context, *pb.HistoryRequest) (*pb.HistoryResponse, error) {
+	return &pb.HistoryResponse{}, nil
+}
+
 func (m *mockKVServer) Put(context.Context, *pb.PutRequest) (*pb.PutResponse, error) {
 	return &pb.PutResponse{}, nil
 }
@@ -212,3 +220,15 @@ func (s *mockLeaseServer) LeaseTimeToLive(context.Context, *pb.LeaseTimeToLiveRe
 func (s *mockLeaseServer) LeaseLeases(context.Context, *pb.LeaseLeasesRequest) (*pb.LeaseLeasesResponse, error) {
 	return &pb.LeaseLeasesResponse{}, nil
 }
+
+func (s *mockLeaseServer) LeaseAttach(context.Context, *pb.LeaseAttachRequest) (*pb.LeaseAttachResponse, error) {
+	return &pb.LeaseAttachResponse{}, nil
+}
+
+func (s *mockLeaseServer) LeaseList(context.Context, *pb.LeaseListRequest) (*pb.LeaseListResponse, error) {
+	return &pb.LeaseListResponse{}, nil
+}
+
+func (s *mockLeaseServer) LeaseDetach(context.Context, *pb.LeaseDetachRequest) (*pb.LeaseDetachResponse, error) {
+	return &pb.LeaseDetachResponse{}, nil
+}
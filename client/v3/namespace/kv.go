@@ -64,6 +64,53 @@ func (kv *kvPrefix) Get(ctx context.Context, key string, opts ...clientv3.OpOpti
 	return get, nil
 }
 
+func (kv *kvPrefix) GetStream(ctx context.Context, key string, opts ...clientv3.OpOption) (clientv3.GetStreamReader, error) {
+	if len(key) == 0 && !(clientv3.IsOptsWithFromKey(opts) || clientv3.IsOptsWithPrefix(opts)) {
+		return nil, rpctypes.ErrEmptyKey
+	}
+	op := kv.prefixOp(clientv3.OpGet(key, opts...))
+	// the prefixed range end overrides the one given by the options
+	opts = append(opts, clientv3.WithRange(string(op.RangeBytes())))
+	r, err := kv.KV.GetStream(ctx, string(op.KeyBytes()), opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &getStreamReaderPrefix{r, kv}, nil
+}
+
+type getStreamReaderPrefix struct {
+	clientv3.GetStreamReader
+	kv *kvPrefix
+}
+
+func (r *getStreamReaderPrefix) Recv() (*clientv3.GetResponse, error) {
+	resp, err := r.GetStreamReader.Recv()
+	if err != nil {
+		return nil, err
+	}
+	r.kv.unprefixGetResponse(resp)
+	return resp, nil
+}
+
+func (kv *kvPrefix) History(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.HistoryResponse, error) {
+	if len(key) == 0 && !(clientv3.IsOptsWithFromKey(opts) || clientv3.IsOptsWithPrefix(opts)) {
+		return nil, rpctypes.ErrEmptyKey
+	}
+	op := kv.prefixOp(clientv3.OpGet(key, opts...))
+	if len(op.RangeBytes()) > 0 {
+		// the prefixed range end overrides the one given by the options
+		opts = append(opts, clientv3.WithRange(string(op.RangeBytes())))
+	}
+	resp, err := kv.KV.History(ctx, string(op.KeyBytes()), opts...)
+	if err != nil {
+		return nil, err
+	}
+	for i := range resp.Kvs {
+		resp.Kvs[i].Key = resp.Kvs[i].Key[len(kv.pfx):]
+	}
+	return resp, nil
+}
+
 func (kv *kvPrefix) Delete(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.DeleteResponse, error) {
 	if len(key) == 0 && !(clientv3.IsOptsWithFromKey(opts) || clientv3.IsOptsWithPrefix(opts)) {
 		return nil, rpctypes.ErrEmptyKey
@@ -175,6 +222,14 @@ func (kv *kvPrefix) unprefixTxnResponse(resp *clientv3.TxnResponse) {
 			if tv.ResponseDeleteRange != nil {
 				kv.unprefixDeleteResponse((*clientv3.DeleteResponse)(tv.ResponseDeleteRange))
 			}
+		case *pb.ResponseOp_ResponseIncr:
+			if tv.ResponseIncr != nil && tv.ResponseIncr.PrevKv != nil {
+				tv.ResponseIncr.PrevKv.Key = tv.ResponseIncr.PrevKv.Key[len(kv.pfx):]
+			}
+		case *pb.ResponseOp_ResponseAppend:
+			if tv.ResponseAppend != nil && tv.ResponseAppend.PrevKv != nil {
+				tv.ResponseAppend.PrevKv.Key = tv.ResponseAppend.PrevKv.Key[len(kv.pfx):]
+			}
 		case *pb.ResponseOp_ResponseTxn:
 			if tv.ResponseTxn != nil {
 				kv.unprefixTxnResponse((*clientv3.TxnResponse)(tv.ResponseTxn))
@@ -55,3 +55,19 @@ func (l *leasePrefix) TimeToLive(ctx context.Context, id clientv3.LeaseID, opts
 	}
 	return resp, nil
 }
+
+func (l *leasePrefix) Attach(ctx context.Context, id clientv3.LeaseID, keys []string, opts ...clientv3.LeaseOption) (*clientv3.LeaseAttachResponse, error) {
+	return l.Lease.Attach(ctx, id, l.prefixKeys(keys), opts...)
+}
+
+func (l *leasePrefix) Detach(ctx context.Context, keys []string, opts ...clientv3.LeaseOption) (*clientv3.LeaseDetachResponse, error) {
+	return l.Lease.Detach(ctx, l.prefixKeys(keys), opts...)
+}
+
+func (l *leasePrefix) prefixKeys(keys []string) []string {
+	pkeys := make([]string, len(keys))
+	for i := range keys {
+		pkeys[i] = string(l.pfx) + keys[i]
+	}
+	return pkeys
+}
@@ -14,7 +14,11 @@
 
 package clientv3
 
-import pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+import (
+	"time"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+)
 
 type opType int
 
@@ -24,6 +28,8 @@ const (
 	tPut
 	tDeleteRange
 	tTxn
+	tIncr
+	tAppend
 )
 
 var noPrefixEnd = []byte{0}
@@ -44,6 +50,10 @@ type Op struct {
 	maxModRev    int64
 	minCreateRev int64
 	maxCreateRev int64
+	continueTok  []byte
+	filter       *pb.RangeFilter
+	projection   []pb.KeyValueField
+	atTime       int64
 
 	// for range, watch
 	rev int64
@@ -66,13 +76,26 @@ type Op struct {
 	// createdNotify is for created event
 	createdNotify bool
 	// filters for watchers
-	filterPut    bool
-	filterDelete bool
+	filterPut       bool
+	filterDelete    bool
+	filterUnchanged bool
+	filterLease     LeaseID
+	keySuffix       []byte
+	keyPattern      string
+	// coalesce merges the events of a watch response by key
+	coalesce bool
+	// initialState sends the keys of the range before the events
+	initialState bool
 
 	// for put
 	val     []byte
 	leaseID LeaseID
 
+	// for incr
+	incrOp     pb.IncrRequest_Operation
+	operand    int64
+	incrBounds *pb.IncrBounds
+
 	// txn
 	cmps    []Cmp
 	thenOps []Op
@@ -115,6 +138,12 @@ func (op Op) IsGet() bool { return op.t == tRange }
 // IsDelete returns true iff the operation is a Delete.
 func (op Op) IsDelete() bool { return op.t == tDeleteRange }
 
+// IsIncr returns true iff the operation is an Incr, SetMax or SetMin.
+func (op Op) IsIncr() bool { return op.t == tIncr }
+
+// IsAppend returns true iff the operation is an Append.
+func (op Op) IsAppend() bool { return op.t == tAppend }
+
 // IsSerializable returns true if the serializable field is true.
 func (op Op) IsSerializable() bool { return op.serializable }
 
@@ -136,6 +165,12 @@ func (op Op) MinCreateRev() int64 { return op.minCreateRev }
 // MaxCreateRev returns the operation's maximum create revision.
 func (op Op) MaxCreateRev() int64 { return op.maxCreateRev }
 
+// ContinueToken returns the operation's continue token, if any.
+func (op Op) ContinueToken() []byte { return op.continueTok }
+
+// AtTime returns the time, in nanoseconds since the Unix epoch, the operation reads the keys as of, if any.
+func (op Op) AtTime() int64 { return op.atTime }
+
 // WithRangeBytes sets the byte slice for the Op's range end.
 func (op *Op) WithRangeBytes(end []byte) { op.end = end }
 
@@ -161,6 +196,10 @@ func (op Op) toRangeRequest() *pb.RangeRequest {
 		MaxModRevision:    op.maxModRev,
 		MinCreateRevision: op.minCreateRev,
 		MaxCreateRevision: op.maxCreateRev,
+		ContinueToken:     op.continueTok,
+		Filter:            op.filter,
+		Projection:        op.projection,
+		AtTime:            op.atTime,
 	}
 	if op.sort != nil {
 		r.SortOrder = pb.RangeRequest_SortOrder(op.sort.Order)
@@ -169,6 +208,22 @@ func (op Op) toRangeRequest() *pb.RangeRequest {
 	return r
 }
 
+// toHistoryRequest converts a Get operation to a HistoryRequest; the mod
+// revision bounds are the start and end revisions of the history.
+func (op Op) toHistoryRequest() *pb.HistoryRequest {
+	if op.t != tRange {
+		panic("op.t != tRange")
+	}
+	return &pb.HistoryRequest{
+		Key:           op.key,
+		RangeEnd:      op.end,
+		StartRevision: op.minModRev,
+		EndRevision:   op.maxModRev,
+		Limit:         op.limit,
+		Serializable:  op.serializable,
+	}
+}
+
 func (op Op) toTxnRequest() *pb.TxnRequest {
 	thenOps := make([]*pb.RequestOp, len(op.thenOps))
 	for i, tOp := range op.thenOps {
@@ -197,6 +252,12 @@ func (op Op) toRequestOp() *pb.RequestOp {
 		return &pb.RequestOp{Request: &pb.RequestOp_RequestDeleteRange{RequestDeleteRange: r}}
 	case tTxn:
 		return &pb.RequestOp{Request: &pb.RequestOp_RequestTxn{RequestTxn: op.toTxnRequest()}}
+	case tIncr:
+		r := &pb.IncrRequest{Key: op.key, Op: op.incrOp, Operand: op.operand, Bounds: op.incrBounds, PrevKv: op.prevKV}
+		return &pb.RequestOp{Request: &pb.RequestOp_RequestIncr{RequestIncr: r}}
+	case tAppend:
+		r := &pb.AppendRequest{Key: op.key, Value: op.val, PrevKv: op.prevKV}
+		return &pb.RequestOp{Request: &pb.RequestOp_RequestAppend{RequestAppend: r}}
 	default:
 		panic("Unknown Op")
 	}
@@ -259,6 +320,12 @@ func OpDelete(key string, opts ...OpOption) Op {
 		panic("unexpected mod revision filter in delete")
 	case ret.minCreateRev != 0, ret.maxCreateRev != 0:
 		panic("unexpected create revision filter in delete")
+	case ret.continueTok != nil:
+		panic("unexpected continue token in delete")
+	case ret.atTime != 0:
+		panic("unexpected at time in delete")
+	case ret.filter != nil, ret.projection != nil:
+		panic("unexpected range filter in delete")
 	case ret.filterDelete, ret.filterPut:
 		panic("unexpected filter in delete")
 	case ret.createdNotify:
@@ -288,6 +355,12 @@ func OpPut(key, val string, opts ...OpOption) Op {
 		panic("unexpected mod revision filter in put")
 	case ret.minCreateRev != 0, ret.maxCreateRev != 0:
 		panic("unexpected create revision filter in put")
+	case ret.continueTok != nil:
+		panic("unexpected continue token in put")
+	case ret.atTime != 0:
+		panic("unexpected at time in put")
+	case ret.filter != nil, ret.projection != nil:
+		panic("unexpected range filter in put")
 	case ret.filterDelete, ret.filterPut:
 		panic("unexpected filter in put")
 	case ret.createdNotify:
@@ -296,6 +369,62 @@ func OpPut(key, val string, opts ...OpOption) Op {
 	return ret
 }
 
+// OpIncr returns "incr" operation adding delta to the decimal integer value of
+// the key, which is decremented if delta is negative. A missing key is taken
+// as 0. The operation fails with rpctypes.ErrValueNotInteger if the value is
+// not an integer, and with rpctypes.ErrValueOutOfRange if the result
+// overflows or falls outside of the bounds set by WithIncrBounds.
+// The response of the operation holds the new value.
+func OpIncr(key string, delta int64, opts ...OpOption) Op {
+	return opIncr("incr", key, pb.IncrRequest_ADD, delta, opts)
+}
+
+// OpSetMax returns "incr" operation setting the decimal integer value of the
+// key to val if val is greater.
+func OpSetMax(key string, val int64, opts ...OpOption) Op {
+	return opIncr("set max", key, pb.IncrRequest_MAX, val, opts)
+}
+
+// OpSetMin returns "incr" operation setting the decimal integer value of the
+// key to val if val is smaller.
+func OpSetMin(key string, val int64, opts ...OpOption) Op {
+	return opIncr("set min", key, pb.IncrRequest_MIN, val, opts)
+}
+
+func opIncr(name, key string, incrOp pb.IncrRequest_Operation, operand int64, opts []OpOption) Op {
+	ret := Op{t: tIncr, key: []byte(key), incrOp: incrOp, operand: operand}
+	ret.applyOpts(opts)
+	ret.checkValueUpdate(name)
+	return ret
+}
+
+// OpAppend returns "append" operation appending val to the value of the key,
+// which is created if it does not exist.
+func OpAppend(key, val string, opts ...OpOption) Op {
+	ret := Op{t: tAppend, key: []byte(key), val: []byte(val)}
+	ret.applyOpts(opts)
+	ret.checkValueUpdate("append")
+	if ret.incrBounds != nil {
+		panic("unexpected bounds in append")
+	}
+	return ret
+}
+
+// checkValueUpdate panics on the options not supported by the operations
+// updating the current value of the key.
+func (op Op) checkValueUpdate(name string) {
+	switch {
+	case op.end != nil:
+		panic("unexpected range in " + name)
+	case op.leaseID != 0:
+		panic("unexpected lease in " + name)
+	case op.ignoreValue, op.ignoreLease:
+		panic("unexpected ignore flag in " + name)
+	case op.limit != 0, op.rev != 0, op.sort != nil, op.serializable, op.countOnly:
+		panic("unexpected range option in " + name)
+	}
+}
+
 // OpTxn returns "txn" operation based on given transaction conditions.
 func OpTxn(cmps []Cmp, thenOps []Op, elseOps []Op) Op {
 	return Op{t: tTxn, cmps: cmps, thenOps: thenOps, elseOps: elseOps}
@@ -319,6 +448,12 @@ func opWatch(key string, opts ...OpOption) Op {
 		panic("unexpected mod revision filter in watch")
 	case ret.minCreateRev != 0, ret.maxCreateRev != 0:
 		panic("unexpected create revision filter in watch")
+	case ret.continueTok != nil:
+		panic("unexpected continue token in watch")
+	case ret.atTime != 0:
+		panic("unexpected at time in watch")
+	case ret.projection != nil:
+		panic("unexpected projection in watch")
 	}
 	return ret
 }
@@ -448,6 +583,92 @@ func WithMinCreateRev(rev int64) OpOption { return func(op *Op) { op.minCreateRe
 // WithMaxCreateRev filters out keys for Get with creation revisions greater than the given revision.
 func WithMaxCreateRev(rev int64) OpOption { return func(op *Op) { op.maxCreateRev = rev } }
 
+// WithContinue resumes a paginated 'Get' request from the continue token
+// returned in the previous response. The next page is served at the revision
+// of the first page, so the pages form a consistent snapshot of the range.
+// The key and range end must be the same as in the previous request.
+func WithContinue(token []byte) OpOption { return func(op *Op) { op.continueTok = token } }
+
+// WithAtTime makes the 'Get' request read the keys as of the given time: the
+// keys are read at the latest revision written up to the end of its second.
+// It cannot be used with WithRev nor in a transaction.
+// Supported since etcd 3.6.
+func WithAtTime(t time.Time) OpOption { return func(op *Op) { op.atTime = t.UnixNano() } }
+
+// rangeFilter returns the range filter of the operation, allocating it if needed.
+func (op *Op) rangeFilter() *pb.RangeFilter {
+	if op.filter == nil {
+		op.filter = &pb.RangeFilter{}
+	}
+	return op.filter
+}
+
+// WithValuePrefix filters out keys for Get whose values do not start with the given prefix.
+func WithValuePrefix(prefix string) OpOption {
+	return func(op *Op) { op.rangeFilter().ValuePrefix = []byte(prefix) }
+}
+
+// WithValueSuffix filters out keys for Get whose values do not end with the given suffix.
+func WithValueSuffix(suffix string) OpOption {
+	return func(op *Op) { op.rangeFilter().ValueSuffix = []byte(suffix) }
+}
+
+// WithValueContains filters out keys for Get whose values do not contain the given substring.
+func WithValueContains(substr string) OpOption {
+	return func(op *Op) { op.rangeFilter().ValueContains = []byte(substr) }
+}
+
+// WithValueSize filters out keys for Get whose value sizes, in bytes, are outside of [min, max].
+// A zero max means no upper bound.
+func WithValueSize(min, max int64) OpOption {
+	return func(op *Op) {
+		f := op.rangeFilter()
+		f.MinValueSize, f.MaxValueSize = min, max
+	}
+}
+
+// WithLeaseAttached filters out keys for Get that are not attached to a lease.
+func WithLeaseAttached() OpOption {
+	return func(op *Op) { op.rangeFilter().Lease = pb.RangeFilter_ATTACHED }
+}
+
+// WithoutLease filters out keys for Get that are attached to a lease.
+func WithoutLease() OpOption {
+	return func(op *Op) { op.rangeFilter().Lease = pb.RangeFilter_DETACHED }
+}
+
+// WithVersionRange filters out keys for Get whose versions are outside of [min, max].
+// A zero max means no upper bound.
+func WithVersionRange(min, max int64) OpOption {
+	return func(op *Op) {
+		f := op.rangeFilter()
+		f.MinVersion, f.MaxVersion = min, max
+	}
+}
+
+// KeyValueField selects a field of the key-value pairs returned by a 'Get'
+// request with WithProjection.
+type KeyValueField int32
+
+const (
+	FieldCreateRevision = KeyValueField(pb.KeyValueField_CREATE_REVISION)
+	FieldModRevision    = KeyValueField(pb.KeyValueField_MOD_REVISION)
+	FieldVersion        = KeyValueField(pb.KeyValueField_VERSION)
+	FieldValue          = KeyValueField(pb.KeyValueField_VALUE)
+	FieldLease          = KeyValueField(pb.KeyValueField_LEASE)
+)
+
+// WithProjection makes the 'Get' request return only the given fields of
+// the key-value pairs, in addition to the key.
+func WithProjection(fields ...KeyValueField) OpOption {
+	return func(op *Op) {
+		op.projection = make([]pb.KeyValueField, len(fields))
+		for i, f := range fields {
+			op.projection[i] = pb.KeyValueField(f)
+		}
+	}
+}
+
 // WithFirstCreate gets the key with the oldest creation revision in the request range.
 func WithFirstCreate() []OpOption { return withTop(SortByCreateRevision, SortAscend) }
 
@@ -497,6 +718,50 @@ func WithFilterDelete() OpOption {
 	return func(op *Op) { op.filterDelete = true }
 }
 
+// WithFilterUnchanged discards PUT events that do not change the value of the key.
+func WithFilterUnchanged() OpOption {
+	return func(op *Op) { op.filterUnchanged = true }
+}
+
+// WithFilterLease discards PUT events of keys that are not attached to the given lease.
+func WithFilterLease(id LeaseID) OpOption {
+	return func(op *Op) { op.filterLease = id }
+}
+
+// WithKeySuffix discards events of keys that do not end with the given suffix.
+func WithKeySuffix(suffix string) OpOption {
+	return func(op *Op) { op.keySuffix = []byte(suffix) }
+}
+
+// WithKeyPattern discards events of keys that do not match the given pattern.
+// The pattern syntax is the one of path.Match, e.g. "/jobs/*/status".
+func WithKeyPattern(pattern string) OpOption {
+	return func(op *Op) { op.keyPattern = pattern }
+}
+
+// WithCoalesce makes the watcher merge the events of a watch response by key,
+// keeping only the latest event of each key.
+func WithCoalesce() OpOption {
+	return func(op *Op) { op.coalesce = true }
+}
+
+// WithInitialState makes the watcher first receive a PUT event for each key in
+// the range as of the revision before the start revision, or as of the current
+// revision if no start revision is given, then a response with InitialStateDone
+// set, then the events from the start revision on. The events of the initial
+// state carry the current key-value pairs rather than changes, so they do not
+// bound the revision a disconnected watcher resumes from; a watcher disconnected
+// before the end of the initial state receives it again.
+func WithInitialState() OpOption {
+	return func(op *Op) { op.initialState = true }
+}
+
+// WithIncrBounds fails the "incr" operation if the new value of the key
+// is out of the inclusive bounds.
+func WithIncrBounds(min, max int64) OpOption {
+	return func(op *Op) { op.incrBounds = &pb.IncrBounds{Min: min, Max: max} }
+}
+
 // WithPrevKV gets the previous key-value pair before the event happens. If the previous KV is already compacted,
 // nothing will be returned.
 func WithPrevKV() OpOption {
@@ -538,8 +803,20 @@ func WithIgnoreLease() OpOption {
 type LeaseOp struct {
 	id LeaseID
 
+	// for GrantWithOptions
+	labels map[string]string
+	parent LeaseID
+
 	// for TimeToLive
 	attachedKeys bool
+
+	// for TimeToLive and List
+	limit       int64
+	continueTok []byte
+	selectors   []string
+
+	// for Attach and Detach
+	prefixKeys bool
 }
 
 // LeaseOption configures lease operations.
@@ -559,7 +836,63 @@ func WithAttachedKeys() LeaseOption {
 func toLeaseTimeToLiveRequest(id LeaseID, opts ...LeaseOption) *pb.LeaseTimeToLiveRequest {
 	ret := &LeaseOp{id: id}
 	ret.applyOpts(opts)
-	return &pb.LeaseTimeToLiveRequest{ID: int64(id), Keys: ret.attachedKeys}
+	return &pb.LeaseTimeToLiveRequest{ID: int64(id), Keys: ret.attachedKeys, Limit: ret.limit, ContinueToken: ret.continueTok}
+}
+
+// WithLeaseLabels makes GrantWithOptions set the labels of the lease.
+func WithLeaseLabels(labels map[string]string) LeaseOption {
+	return func(op *LeaseOp) { op.labels = labels }
+}
+
+// WithLeaseParent makes GrantWithOptions grant a child of the given lease.
+func WithLeaseParent(parent LeaseID) LeaseOption {
+	return func(op *LeaseOp) { op.parent = parent }
+}
+
+func toLeaseGrantRequest(ttl int64, opts ...LeaseOption) *pb.LeaseGrantRequest {
+	ret := &LeaseOp{}
+	ret.applyOpts(opts)
+	return &pb.LeaseGrantRequest{TTL: ttl, Parent: int64(ret.parent), Labels: ret.labels}
+}
+
+// WithLabelSelector makes List list the leases matching all the selectors
+// "key=value", "key!=value", "key" and "!key".
+func WithLabelSelector(selectors ...string) LeaseOption {
+	return func(op *LeaseOp) { op.selectors = selectors }
+}
+
+// WithLeaseLimit limits the number of leases returned by List, or the number
+// of keys returned by TimeToLive with WithAttachedKeys.
+func WithLeaseLimit(n int64) LeaseOption {
+	return func(op *LeaseOp) { op.limit = n }
+}
+
+// WithLeaseContinue makes List and TimeToLive resume from the continue token
+// of the previous page.
+func WithLeaseContinue(token []byte) LeaseOption {
+	return func(op *LeaseOp) { op.continueTok = token }
+}
+
+func toLeaseListRequest(opts ...LeaseOption) *pb.LeaseListRequest {
+	ret := &LeaseOp{}
+	ret.applyOpts(opts)
+	return &pb.LeaseListRequest{Selectors: ret.selectors, Limit: ret.limit, ContinueToken: ret.continueTok}
+}
+
+// WithPrefixKeys makes Attach and Detach take the given keys as prefixes of
+// the keys to attach or detach.
+func WithPrefixKeys() LeaseOption {
+	return func(op *LeaseOp) { op.prefixKeys = true }
+}
+
+func toLeaseAttachRequest(id LeaseID, keys []string, opts ...LeaseOption) *pb.LeaseAttachRequest {
+	ret := &LeaseOp{id: id}
+	ret.applyOpts(opts)
+	r := &pb.LeaseAttachRequest{ID: int64(id), Keys: make([][]byte, len(keys)), Prefix: ret.prefixKeys}
+	for i := range keys {
+		r.Keys[i] = []byte(keys[i])
+	}
+	return r
 }
 
 // IsOptsWithPrefix returns true if WithPrefix option is called in the given opts.
@@ -99,3 +99,22 @@ func TestIsOptsWithFromKey(t *testing.T) {
 		t.Errorf("IsOptsWithFromKey = true, expected false")
 	}
 }
+
+func TestOpWithRangeFilter(t *testing.T) {
+	op := OpGet("foo", WithPrefix(), WithValueContains("bar"), WithValueSize(1, 10), WithLeaseAttached(), WithProjection(FieldValue, FieldLease))
+	req := op.toRangeRequest()
+	wreq := &pb.RangeRequest{
+		Key:      []byte("foo"),
+		RangeEnd: []byte("fop"),
+		Filter: &pb.RangeFilter{
+			ValueContains: []byte("bar"),
+			MinValueSize:  1,
+			MaxValueSize:  10,
+			Lease:         pb.RangeFilter_ATTACHED,
+		},
+		Projection: []pb.KeyValueField{pb.KeyValueField_VALUE, pb.KeyValueField_LEASE},
+	}
+	if !reflect.DeepEqual(req, wreq) {
+		t.Fatalf("expected %+v, got %+v", wreq, req)
+	}
+}
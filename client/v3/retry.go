@@ -105,6 +105,14 @@ func (rkv *retryKVClient) Range(ctx context.Context, in *pb.RangeRequest, opts .
 	return rkv.kc.Range(ctx, in, append(opts, withRetryPolicy(repeatable))...)
 }
 
+func (rkv *retryKVClient) RangeStream(ctx context.Context, in *pb.RangeRequest, opts ...grpc.CallOption) (pb.KV_RangeStreamClient, error) {
+	return rkv.kc.RangeStream(ctx, in, opts...)
+}
+
+func (rkv *retryKVClient) History(ctx context.Context, in *pb.HistoryRequest, opts ...grpc.CallOption) (resp *pb.HistoryResponse, err error) {
+	return rkv.kc.History(ctx, in, append(opts, withRetryPolicy(repeatable))...)
+}
+
 func (rkv *retryKVClient) Put(ctx context.Context, in *pb.PutRequest, opts ...grpc.CallOption) (resp *pb.PutResponse, err error) {
 	return rkv.kc.Put(ctx, in, opts...)
 }
@@ -148,6 +156,18 @@ func (rlc *retryLeaseClient) LeaseRevoke(ctx context.Context, in *pb.LeaseRevoke
 	return rlc.lc.LeaseRevoke(ctx, in, append(opts, withRetryPolicy(repeatable))...)
 }
 
+func (rlc *retryLeaseClient) LeaseAttach(ctx context.Context, in *pb.LeaseAttachRequest, opts ...grpc.CallOption) (resp *pb.LeaseAttachResponse, err error) {
+	return rlc.lc.LeaseAttach(ctx, in, append(opts, withRetryPolicy(repeatable))...)
+}
+
+func (rlc *retryLeaseClient) LeaseList(ctx context.Context, in *pb.LeaseListRequest, opts ...grpc.CallOption) (resp *pb.LeaseListResponse, err error) {
+	return rlc.lc.LeaseList(ctx, in, append(opts, withRetryPolicy(repeatable))...)
+}
+
+func (rlc *retryLeaseClient) LeaseDetach(ctx context.Context, in *pb.LeaseDetachRequest, opts ...grpc.CallOption) (resp *pb.LeaseDetachResponse, err error) {
+	return rlc.lc.LeaseDetach(ctx, in, append(opts, withRetryPolicy(repeatable))...)
+}
+
 func (rlc *retryLeaseClient) LeaseKeepAlive(ctx context.Context, opts ...grpc.CallOption) (stream pb.Lease_LeaseKeepAliveClient, err error) {
 	return rlc.lc.LeaseKeepAlive(ctx, append(opts, withRetryPolicy(repeatable))...)
 }
@@ -226,6 +246,34 @@ func (rmc *retryMaintenanceClient) Downgrade(ctx context.Context, in *pb.Downgra
 	return rmc.mc.Downgrade(ctx, in, opts...)
 }
 
+func (rmc *retryMaintenanceClient) QuotaSet(ctx context.Context, in *pb.QuotaSetRequest, opts ...grpc.CallOption) (resp *pb.QuotaSetResponse, err error) {
+	return rmc.mc.QuotaSet(ctx, in, opts...)
+}
+
+func (rmc *retryMaintenanceClient) QuotaDelete(ctx context.Context, in *pb.QuotaDeleteRequest, opts ...grpc.CallOption) (resp *pb.QuotaDeleteResponse, err error) {
+	return rmc.mc.QuotaDelete(ctx, in, opts...)
+}
+
+func (rmc *retryMaintenanceClient) QuotaList(ctx context.Context, in *pb.QuotaListRequest, opts ...grpc.CallOption) (resp *pb.QuotaListResponse, err error) {
+	return rmc.mc.QuotaList(ctx, in, append(opts, withRetryPolicy(repeatable))...)
+}
+
+func (rmc *retryMaintenanceClient) CompactionRetention(ctx context.Context, in *pb.CompactionRetentionRequest, opts ...grpc.CallOption) (resp *pb.CompactionRetentionResponse, err error) {
+	return rmc.mc.CompactionRetention(ctx, in, append(opts, withRetryPolicy(repeatable))...)
+}
+
+func (rmc *retryMaintenanceClient) CompactionHold(ctx context.Context, in *pb.CompactionHoldRequest, opts ...grpc.CallOption) (resp *pb.CompactionHoldResponse, err error) {
+	return rmc.mc.CompactionHold(ctx, in, opts...)
+}
+
+func (rmc *retryMaintenanceClient) CompactionHoldRelease(ctx context.Context, in *pb.CompactionHoldReleaseRequest, opts ...grpc.CallOption) (resp *pb.CompactionHoldReleaseResponse, err error) {
+	return rmc.mc.CompactionHoldRelease(ctx, in, opts...)
+}
+
+func (rmc *retryMaintenanceClient) CompactionHoldList(ctx context.Context, in *pb.CompactionHoldListRequest, opts ...grpc.CallOption) (resp *pb.CompactionHoldListResponse, err error) {
+	return rmc.mc.CompactionHoldList(ctx, in, append(opts, withRetryPolicy(repeatable))...)
+}
+
 type retryAuthClient struct {
 	ac pb.AuthClient
 }
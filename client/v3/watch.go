@@ -108,6 +108,11 @@ type WatchResponse struct {
 	// Created is used to indicate the creation of the watcher.
 	Created bool
 
+	// InitialStateDone is set on the response ending the initial state of a
+	// watcher created with WithInitialState. Header.Revision is the revision
+	// of the initial state.
+	InitialStateDone bool
+
 	closeErr error
 
 	// cancelReason is a reason of canceling watch
@@ -124,6 +129,12 @@ func (e *Event) IsModify() bool {
 	return e.Type == EventTypePut && e.Kv.CreateRevision != e.Kv.ModRevision
 }
 
+// IsLeaseExpired returns true if the event tells that the key is deleted
+// because its lease expired. Kv.Lease holds the ID of the lease.
+func (e *Event) IsLeaseExpired() bool {
+	return e.Type == EventTypeDelete && e.DeleteCause == mvccpb.LEASE_EXPIRED
+}
+
 // Err is the error value if this WatchResponse holds an error.
 func (wr *WatchResponse) Err() error {
 	switch {
@@ -142,7 +153,7 @@ func (wr *WatchResponse) Err() error {
 
 // IsProgressNotify returns true if the WatchResponse is progress notification.
 func (wr *WatchResponse) IsProgressNotify() bool {
-	return len(wr.Events) == 0 && !wr.Canceled && !wr.Created && wr.CompactRevision == 0 && wr.Header.Revision != 0
+	return len(wr.Events) == 0 && !wr.Canceled && !wr.Created && !wr.InitialStateDone && wr.CompactRevision == 0 && wr.Header.Revision != 0
 }
 
 // watcher implements the Watcher interface
@@ -219,6 +230,18 @@ type watchRequest struct {
 
 	// filters is the list of events to filter out
 	filters []pb.WatchCreateRequest_FilterType
+	// kvFilter discards PUT events whose key-value pair does not match
+	kvFilter *pb.RangeFilter
+	// lease discards PUT events of keys not attached to the lease
+	lease int64
+	// keySuffix and keyPattern discard events of keys that do not match
+	keySuffix  []byte
+	keyPattern string
+	// coalesce keeps only the latest event of each key in a response
+	coalesce bool
+	// initialState sends the keys of the range before the events, until
+	// the end of the initial state is received
+	initialState bool
 	// get the previous key-value pair before the event happens
 	prevKV bool
 	// retc receives a chan WatchResponse once the watcher is established
@@ -309,6 +332,9 @@ func (w *watcher) Watch(ctx context.Context, key string, opts ...OpOption) Watch
 	if ow.filterDelete {
 		filters = append(filters, pb.WatchCreateRequest_NODELETE)
 	}
+	if ow.filterUnchanged {
+		filters = append(filters, pb.WatchCreateRequest_NOUNCHANGED)
+	}
 
 	wr := &watchRequest{
 		ctx:            ctx,
@@ -319,6 +345,12 @@ func (w *watcher) Watch(ctx context.Context, key string, opts ...OpOption) Watch
 		progressNotify: ow.progressNotify,
 		fragment:       ow.fragment,
 		filters:        filters,
+		kvFilter:       ow.filter,
+		lease:          int64(ow.filterLease),
+		keySuffix:      ow.keySuffix,
+		keyPattern:     ow.keyPattern,
+		coalesce:       ow.coalesce,
+		initialState:   ow.initialState,
 		prevKV:         ow.prevKV,
 		retc:           make(chan chan WatchResponse, 1),
 	}
@@ -749,12 +781,13 @@ func (w *watchGrpcStream) dispatchEvent(pbresp *pb.WatchResponse) bool {
 	}
 	// TODO: return watch ID?
 	wr := &WatchResponse{
-		Header:          *pbresp.Header,
-		Events:          events,
-		CompactRevision: pbresp.CompactRevision,
-		Created:         pbresp.Created,
-		Canceled:        pbresp.Canceled,
-		cancelReason:    pbresp.CancelReason,
+		Header:           *pbresp.Header,
+		Events:           events,
+		CompactRevision:  pbresp.CompactRevision,
+		Created:          pbresp.Created,
+		Canceled:         pbresp.Canceled,
+		InitialStateDone: pbresp.InitialStateDone,
+		cancelReason:     pbresp.CancelReason,
 	}
 
 	// watch IDs are zero indexed, so request notify watch responses are assigned a watch ID of InvalidWatchID to
@@ -878,12 +911,20 @@ func (w *watchGrpcStream) serveSubstream(ws *watcherStream, resumec chan struct{
 						nextRev = wr.Header.Revision
 					}
 				}
+			} else if ws.initReq.initialState {
+				// the events of the initial state are not revisions to
+				// resume from; the initial state is sent again on resume
+				// until its end
+				if wr.InitialStateDone {
+					ws.initReq.initialState = false
+					nextRev = wr.Header.Revision + 1
+				}
 			} else {
 				// current progress of watch; <= store revision
 				nextRev = wr.Header.Revision + 1
 			}
 
-			if len(wr.Events) > 0 {
+			if len(wr.Events) > 0 && !ws.initReq.initialState {
 				nextRev = wr.Events[len(wr.Events)-1].Kv.ModRevision + 1
 			}
 
@@ -1048,13 +1089,19 @@ func (w *watchGrpcStream) openWatchClient() (ws pb.Watch_WatchClient, err error)
 // toPB converts an internal watch request structure to its protobuf WatchRequest structure.
 func (wr *watchRequest) toPB() *pb.WatchRequest {
 	req := &pb.WatchCreateRequest{
-		StartRevision:  wr.rev,
-		Key:            []byte(wr.key),
-		RangeEnd:       []byte(wr.end),
-		ProgressNotify: wr.progressNotify,
-		Filters:        wr.filters,
-		PrevKv:         wr.prevKV,
-		Fragment:       wr.fragment,
+		StartRevision:    wr.rev,
+		Key:              []byte(wr.key),
+		RangeEnd:         []byte(wr.end),
+		ProgressNotify:   wr.progressNotify,
+		Filters:          wr.filters,
+		PrevKv:           wr.prevKV,
+		Fragment:         wr.fragment,
+		KvFilter:         wr.kvFilter,
+		KeySuffix:        wr.keySuffix,
+		KeyPattern:       wr.keyPattern,
+		Lease:            wr.lease,
+		Coalesce:         wr.coalesce,
+		SendInitialState: wr.initialState,
 	}
 	cr := &pb.WatchRequest_CreateRequest{CreateRequest: req}
 	return &pb.WatchRequest{RequestUnion: cr}
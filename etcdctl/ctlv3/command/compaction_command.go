@@ -34,6 +34,35 @@ func NewCompactionCommand() *cobra.Command {
 		Run:   compactionCommandFunc,
 	}
 	cmd.Flags().BoolVar(&compactPhysical, "physical", false, "'true' to wait for compaction to physically remove all old revisions")
+	cmd.AddCommand(newCompactionRetentionCommand())
+	cmd.AddCommand(newCompactionHoldCommand())
+	return cmd
+}
+
+func newCompactionRetentionCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "retention",
+		Short: "Lists the retention rules keeping revisions from being compacted",
+		Run:   compactionRetentionCommandFunc,
+	}
+}
+
+func newCompactionHoldCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hold <leaseID> [revision]",
+		Short: "Keeps a revision, the current one by default, from being compacted while the lease is alive",
+		Run:   compactionHoldCommandFunc,
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "Lists the active compaction holds",
+		Run:   compactionHoldListCommandFunc,
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "release <holdID>",
+		Short: "Releases a compaction hold",
+		Run:   compactionHoldReleaseCommandFunc,
+	})
 	return cmd
 }
 
@@ -55,10 +84,89 @@ func compactionCommandFunc(cmd *cobra.Command, args []string) {
 
 	c := mustClientFromCmd(cmd)
 	ctx, cancel := commandCtx(cmd)
-	_, cerr := c.Compact(ctx, rev, opts...)
+	resp, cerr := c.Compact(ctx, rev, opts...)
 	cancel()
 	if cerr != nil {
 		cobrautl.ExitWithError(cobrautl.ExitError, cerr)
 	}
+	// a compaction hold may keep the revisions after the compacted revision
+	if resp.CompactRevision != 0 && resp.CompactRevision != rev {
+		fmt.Printf("compacted revision %d (requested %d, held by a compaction hold)\n", resp.CompactRevision, rev)
+		return
+	}
 	fmt.Println("compacted revision", rev)
 }
+
+// compactionRetentionCommandFunc executes the "compaction retention" command.
+func compactionRetentionCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 0 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("compaction retention command does not accept argument"))
+	}
+
+	ctx, cancel := commandCtx(cmd)
+	resp, err := mustClientFromCmd(cmd).CompactionRetention(ctx)
+	cancel()
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+	display.CompactionRetention(*resp)
+}
+
+// compactionHoldCommandFunc executes the "compaction hold" command.
+func compactionHoldCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 1 && len(args) != 2 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("compaction hold command needs lease ID and optional revision as arguments"))
+	}
+
+	id := leaseFromArgs(args[0])
+	var rev int64
+	if len(args) == 2 {
+		var err error
+		if rev, err = strconv.ParseInt(args[1], 10, 64); err != nil {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("bad revision (%v)", err))
+		}
+	}
+
+	ctx, cancel := commandCtx(cmd)
+	resp, err := mustClientFromCmd(cmd).CompactionHold(ctx, rev, id)
+	cancel()
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+	display.CompactionHold(*resp)
+}
+
+// compactionHoldListCommandFunc executes the "compaction hold list" command.
+func compactionHoldListCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 0 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("compaction hold list command does not accept argument"))
+	}
+
+	ctx, cancel := commandCtx(cmd)
+	resp, err := mustClientFromCmd(cmd).CompactionHoldList(ctx)
+	cancel()
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+	display.CompactionHoldList(*resp)
+}
+
+// compactionHoldReleaseCommandFunc executes the "compaction hold release" command.
+func compactionHoldReleaseCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("compaction hold release command needs 1 argument"))
+	}
+
+	id, err := strconv.ParseInt(args[0], 16, 64)
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("bad hold ID arg (%v), expecting ID in Hex", err))
+	}
+
+	ctx, cancel := commandCtx(cmd)
+	resp, err := mustClientFromCmd(cmd).CompactionHoldRelease(ctx, id)
+	cancel()
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+	display.CompactionHoldRelease(id, *resp)
+}
@@ -21,9 +21,12 @@ import (
 
 	"github.com/spf13/cobra"
 
+	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.etcd.io/etcd/pkg/v3/cobrautl"
 )
 
+var defragOnline bool
+
 // NewDefragCommand returns the cobra command for "Defrag".
 func NewDefragCommand() *cobra.Command {
 	cmd := &cobra.Command{
@@ -32,10 +35,15 @@ func NewDefragCommand() *cobra.Command {
 		Run:   defragCommandFunc,
 	}
 	cmd.PersistentFlags().BoolVar(&epClusterEndpoints, "cluster", false, "use all endpoints from the cluster member list")
+	cmd.Flags().BoolVar(&defragOnline, "online", false, "copy the storage in chunks without blocking reads and writes until the final swap of the storage file")
 	return cmd
 }
 
 func defragCommandFunc(cmd *cobra.Command, args []string) {
+	var opts []clientv3.DefragmentOption
+	if defragOnline {
+		opts = append(opts, clientv3.WithOnlineDefragment())
+	}
 	failures := 0
 	cfg := clientConfigFromCmd(cmd)
 	for _, ep := range endpointsFromCluster(cmd) {
@@ -43,7 +51,7 @@ func defragCommandFunc(cmd *cobra.Command, args []string) {
 		c := mustClient(cfg)
 		ctx, cancel := commandCtx(cmd)
 		start := time.Now()
-		_, err := c.Defragment(ctx, ep)
+		_, err := c.Defragment(ctx, ep, opts...)
 		d := time.Now().Sub(start)
 		cancel()
 		if err != nil {
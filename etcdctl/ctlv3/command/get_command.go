@@ -16,7 +16,9 @@ package command
 
 import (
 	"fmt"
+	"io"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -32,11 +34,19 @@ var (
 	getPrefix      bool
 	getFromKey     bool
 	getRev         int64
+	getAt          string
 	getKeysOnly    bool
 	getCountOnly   bool
+	getPaginate    bool
+	getStream      bool
+	getHistory     bool
 	printValueOnly bool
 )
 
+// defaultGetPageSize is the number of keys fetched per request by "get --paginate"
+// when no "--limit" is given.
+const defaultGetPageSize = 1000
+
 // NewGetCommand returns the cobra command for "get".
 func NewGetCommand() *cobra.Command {
 	cmd := &cobra.Command{
@@ -52,8 +62,12 @@ func NewGetCommand() *cobra.Command {
 	cmd.Flags().BoolVar(&getPrefix, "prefix", false, "Get keys with matching prefix")
 	cmd.Flags().BoolVar(&getFromKey, "from-key", false, "Get keys that are greater than or equal to the given key using byte compare")
 	cmd.Flags().Int64Var(&getRev, "rev", 0, "Specify the kv revision")
+	cmd.Flags().StringVar(&getAt, "at", "", "Get the keys as of the given RFC3339 time, at a one second resolution")
 	cmd.Flags().BoolVar(&getKeysOnly, "keys-only", false, "Get only the keys")
 	cmd.Flags().BoolVar(&getCountOnly, "count-only", false, "Get only the count")
+	cmd.Flags().BoolVar(&getPaginate, "paginate", false, "Get the keys page by page at a single revision; --limit sets the page size")
+	cmd.Flags().BoolVar(&getStream, "stream", false, "Get the keys in a single streamed request, received in several parts")
+	cmd.Flags().BoolVar(&getHistory, "history", false, "Get every revision of the keys, including the deletions, since --rev or the compact revision")
 	cmd.Flags().BoolVar(&printValueOnly, "print-value-only", false, `Only write values when using the "simple" output format`)
 
 	cmd.RegisterFlagCompletionFunc("consistency", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
@@ -72,6 +86,18 @@ func NewGetCommand() *cobra.Command {
 // getCommandFunc executes the "get" command.
 func getCommandFunc(cmd *cobra.Command, args []string) {
 	key, opts := getGetOp(args)
+	if getPaginate {
+		getPaginateFunc(cmd, key, opts)
+		return
+	}
+	if getStream {
+		getStreamFunc(cmd, key, opts)
+		return
+	}
+	if getHistory {
+		getHistoryFunc(cmd, key, opts)
+		return
+	}
 	ctx, cancel := commandCtx(cmd)
 	resp, err := mustClientFromCmd(cmd).Get(ctx, key, opts...)
 	cancel()
@@ -95,6 +121,83 @@ func getCommandFunc(cmd *cobra.Command, args []string) {
 	display.Get(*resp)
 }
 
+// getPaginateFunc fetches the range in pages, resuming each request from the
+// continue token of the previous response, and displays every page.
+func getPaginateFunc(cmd *cobra.Command, key string, opts []clientv3.OpOption) {
+	if printValueOnly {
+		dp, simple := (display).(*simplePrinter)
+		if !simple {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("print-value-only is only for `--write-out=simple`"))
+		}
+		dp.valueOnly = true
+	}
+
+	c := mustClientFromCmd(cmd)
+	var token []byte
+	for {
+		ctx, cancel := commandCtx(cmd)
+		resp, err := c.Get(ctx, key, append(opts, clientv3.WithContinue(token))...)
+		cancel()
+		if err != nil {
+			cobrautl.ExitWithError(cobrautl.ExitError, err)
+		}
+		display.Get(*resp)
+		if !resp.More || len(resp.ContinueToken) == 0 {
+			return
+		}
+		token = resp.ContinueToken
+	}
+}
+
+// getStreamFunc receives the range in several parts from a single streamed
+// request and displays every part as soon as it is received.
+func getStreamFunc(cmd *cobra.Command, key string, opts []clientv3.OpOption) {
+	if printValueOnly {
+		dp, simple := (display).(*simplePrinter)
+		if !simple {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("print-value-only is only for `--write-out=simple`"))
+		}
+		dp.valueOnly = true
+	}
+
+	ctx, cancel := commandCtx(cmd)
+	defer cancel()
+	rs, err := mustClientFromCmd(cmd).GetStream(ctx, key, opts...)
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+	for {
+		resp, err := rs.Recv()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			cobrautl.ExitWithError(cobrautl.ExitError, err)
+		}
+		display.Get(*resp)
+	}
+}
+
+// getHistoryFunc fetches every revision of the keys and displays them in
+// revision order.
+func getHistoryFunc(cmd *cobra.Command, key string, opts []clientv3.OpOption) {
+	if printValueOnly {
+		dp, simple := (display).(*simplePrinter)
+		if !simple {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("print-value-only is only for `--write-out=simple`"))
+		}
+		dp.valueOnly = true
+	}
+
+	ctx, cancel := commandCtx(cmd)
+	resp, err := mustClientFromCmd(cmd).History(ctx, key, opts...)
+	cancel()
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+	display.History(*resp)
+}
+
 func getGetOp(args []string) (string, []clientv3.OpOption) {
 	if len(args) == 0 {
 		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("get command needs one argument as key and an optional argument as range_end"))
@@ -108,6 +211,46 @@ func getGetOp(args []string) (string, []clientv3.OpOption) {
 		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("`--keys-only` and `--count-only` cannot be set at the same time, choose one"))
 	}
 
+	if getPaginate {
+		if getCountOnly {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("`--paginate` and `--count-only` cannot be set at the same time"))
+		}
+		if len(args) == 1 && !getPrefix && !getFromKey {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("`--paginate` requires a range; set range_end, `--prefix` or `--from-key`"))
+		}
+		if getLimit == 0 {
+			getLimit = defaultGetPageSize
+		}
+	}
+
+	if getStream && getPaginate {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("`--stream` and `--paginate` cannot be set at the same time, choose one"))
+	}
+
+	if getHistory {
+		if getPaginate || getStream {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("`--history` cannot be set with `--paginate` or `--stream`"))
+		}
+		if getKeysOnly || getCountOnly {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("`--history` cannot be set with `--keys-only` or `--count-only`"))
+		}
+		if getSortOrder != "" || getSortTarget != "" {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("`--history` is always sorted by revision"))
+		}
+	}
+
+	var atTime time.Time
+	if getAt != "" {
+		if getRev > 0 || getHistory {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("`--at` cannot be set with `--rev` or `--history`"))
+		}
+		t, err := time.Parse(time.RFC3339, getAt)
+		if err != nil {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("bad time %q for `--at`: %w", getAt, err))
+		}
+		atTime = t
+	}
+
 	var opts []clientv3.OpOption
 	switch getConsistency {
 	case "s":
@@ -128,6 +271,12 @@ func getGetOp(args []string) (string, []clientv3.OpOption) {
 	opts = append(opts, clientv3.WithLimit(getLimit))
 	if getRev > 0 {
 		opts = append(opts, clientv3.WithRev(getRev))
+		if getHistory {
+			opts = append(opts, clientv3.WithMinModRev(getRev))
+		}
+	}
+	if !atTime.IsZero() {
+		opts = append(opts, clientv3.WithAtTime(atTime))
 	}
 
 	sortByOrder := clientv3.SortNone
@@ -162,6 +311,13 @@ func getGetOp(args []string) (string, []clientv3.OpOption) {
 		cobrautl.ExitWithError(cobrautl.ExitBadFeature, fmt.Errorf("bad sort target %v", getSortTarget))
 	}
 
+	if getPaginate && (sortByTarget != clientv3.SortByKey || sortByOrder == clientv3.SortDescend) {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("`--paginate` only supports results sorted by key in ascending order"))
+	}
+	if getStream && (sortByTarget != clientv3.SortByKey || sortByOrder == clientv3.SortDescend) {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("`--stream` only supports results sorted by key in ascending order"))
+	}
+
 	opts = append(opts, clientv3.WithSort(sortByTarget, sortByOrder))
 
 	if getPrefix {
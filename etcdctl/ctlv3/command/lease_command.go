@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 
 	v3 "go.etcd.io/etcd/client/v3"
 	"go.etcd.io/etcd/pkg/v3/cobrautl"
@@ -37,10 +38,17 @@ func NewLeaseCommand() *cobra.Command {
 	lc.AddCommand(NewLeaseTimeToLiveCommand())
 	lc.AddCommand(NewLeaseListCommand())
 	lc.AddCommand(NewLeaseKeepAliveCommand())
+	lc.AddCommand(NewLeaseAttachCommand())
+	lc.AddCommand(NewLeaseDetachCommand())
 
 	return lc
 }
 
+var (
+	grantParentStr string
+	grantLabels    []string
+)
+
 // NewLeaseGrantCommand returns the cobra command for "lease grant".
 func NewLeaseGrantCommand() *cobra.Command {
 	lc := &cobra.Command{
@@ -50,6 +58,8 @@ func NewLeaseGrantCommand() *cobra.Command {
 		Run: leaseGrantCommandFunc,
 	}
 
+	lc.Flags().StringVar(&grantParentStr, "parent", "0", "parent lease ID (in hexadecimal) to revoke the lease with")
+	lc.Flags().StringArrayVar(&grantLabels, "label", nil, "label of the lease as key=value, can be repeated")
 	return lc
 }
 
@@ -64,8 +74,26 @@ func leaseGrantCommandFunc(cmd *cobra.Command, args []string) {
 		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("bad TTL (%v)", err))
 	}
 
+	parent, err := strconv.ParseInt(grantParentStr, 16, 64)
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("bad parent lease ID (%v), expecting ID in Hex", err))
+	}
+
+	opts := []v3.LeaseOption{v3.WithLeaseParent(v3.LeaseID(parent))}
+	if len(grantLabels) != 0 {
+		labels := make(map[string]string, len(grantLabels))
+		for _, l := range grantLabels {
+			k, v, ok := strings.Cut(l, "=")
+			if !ok {
+				cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("bad label %q, expecting key=value", l))
+			}
+			labels[k] = v
+		}
+		opts = append(opts, v3.WithLeaseLabels(labels))
+	}
+
 	ctx, cancel := commandCtx(cmd)
-	resp, err := mustClientFromCmd(cmd).Grant(ctx, ttl)
+	resp, err := mustClientFromCmd(cmd).GrantWithOptions(ctx, ttl, opts...)
 	cancel()
 	if err != nil {
 		cobrautl.ExitWithError(cobrautl.ExitError, fmt.Errorf("failed to grant lease (%v)", err))
@@ -101,7 +129,76 @@ func leaseRevokeCommandFunc(cmd *cobra.Command, args []string) {
 	display.Revoke(id, *resp)
 }
 
-var timeToLiveKeys bool
+var attachPrefix bool
+
+// NewLeaseAttachCommand returns the cobra command for "lease attach".
+func NewLeaseAttachCommand() *cobra.Command {
+	lc := &cobra.Command{
+		Use:   "attach <leaseID> <key> [key...] [options]",
+		Short: "Attaches existing keys to a lease without writing them",
+
+		Run: leaseAttachCommandFunc,
+	}
+	lc.Flags().BoolVar(&attachPrefix, "prefix", false, "Attach the keys with matching prefixes")
+
+	return lc
+}
+
+// leaseAttachCommandFunc executes the "lease attach" command.
+func leaseAttachCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) < 2 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("lease attach command needs lease ID and key arguments"))
+	}
+
+	id := leaseFromArgs(args[0])
+	ctx, cancel := commandCtx(cmd)
+	resp, err := mustClientFromCmd(cmd).Attach(ctx, id, args[1:], attachOpts()...)
+	cancel()
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, fmt.Errorf("failed to attach keys to lease (%v)", err))
+	}
+	display.Attach(id, *resp)
+}
+
+// NewLeaseDetachCommand returns the cobra command for "lease detach".
+func NewLeaseDetachCommand() *cobra.Command {
+	lc := &cobra.Command{
+		Use:   "detach <key> [key...] [options]",
+		Short: "Detaches existing keys from their lease without writing them",
+
+		Run: leaseDetachCommandFunc,
+	}
+	lc.Flags().BoolVar(&attachPrefix, "prefix", false, "Detach the keys with matching prefixes")
+
+	return lc
+}
+
+// leaseDetachCommandFunc executes the "lease detach" command.
+func leaseDetachCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) < 1 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("lease detach command needs key arguments"))
+	}
+
+	ctx, cancel := commandCtx(cmd)
+	resp, err := mustClientFromCmd(cmd).Detach(ctx, args, attachOpts()...)
+	cancel()
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, fmt.Errorf("failed to detach keys from lease (%v)", err))
+	}
+	display.Detach(*resp)
+}
+
+func attachOpts() []v3.LeaseOption {
+	if attachPrefix {
+		return []v3.LeaseOption{v3.WithPrefixKeys()}
+	}
+	return nil
+}
+
+var (
+	timeToLiveKeys  bool
+	timeToLiveLimit int64
+)
 
 // NewLeaseTimeToLiveCommand returns the cobra command for "lease timetolive".
 func NewLeaseTimeToLiveCommand() *cobra.Command {
@@ -112,6 +209,7 @@ func NewLeaseTimeToLiveCommand() *cobra.Command {
 		Run: leaseTimeToLiveCommandFunc,
 	}
 	lc.Flags().BoolVar(&timeToLiveKeys, "keys", false, "Get keys attached to this lease")
+	lc.Flags().Int64Var(&timeToLiveLimit, "limit", 0, "Maximum number of keys to get with --keys")
 
 	return lc
 }
@@ -123,7 +221,7 @@ func leaseTimeToLiveCommandFunc(cmd *cobra.Command, args []string) {
 	}
 	var opts []v3.LeaseOption
 	if timeToLiveKeys {
-		opts = append(opts, v3.WithAttachedKeys())
+		opts = append(opts, v3.WithAttachedKeys(), v3.WithLeaseLimit(timeToLiveLimit))
 	}
 	resp, rerr := mustClientFromCmd(cmd).TimeToLive(context.TODO(), leaseFromArgs(args[0]), opts...)
 	if rerr != nil {
@@ -132,18 +230,35 @@ func leaseTimeToLiveCommandFunc(cmd *cobra.Command, args []string) {
 	display.TimeToLive(*resp, timeToLiveKeys)
 }
 
+var (
+	listLabels    bool
+	listSelectors []string
+	listLimit     int64
+)
+
 // NewLeaseListCommand returns the cobra command for "lease list".
 func NewLeaseListCommand() *cobra.Command {
 	lc := &cobra.Command{
-		Use:   "list",
+		Use:   "list [options]",
 		Short: "List all active leases",
 		Run:   leaseListCommandFunc,
 	}
+	lc.Flags().BoolVar(&listLabels, "labels", false, "List the leases with their labels, parents and numbers of attached keys")
+	lc.Flags().StringArrayVar(&listSelectors, "selector", nil, "List the leases matching the label selector key=value, key!=value, key or !key, can be repeated")
+	lc.Flags().Int64Var(&listLimit, "limit", 0, "Maximum number of leases listed with their labels")
 	return lc
 }
 
 // leaseListCommandFunc executes the "lease list" command.
 func leaseListCommandFunc(cmd *cobra.Command, args []string) {
+	if listLabels || len(listSelectors) != 0 || listLimit != 0 {
+		resp, rerr := mustClientFromCmd(cmd).List(context.TODO(), v3.WithLabelSelector(listSelectors...), v3.WithLeaseLimit(listLimit))
+		if rerr != nil {
+			cobrautl.ExitWithError(cobrautl.ExitBadConnection, rerr)
+		}
+		display.LeaseList(*resp)
+		return
+	}
 	resp, rerr := mustClientFromCmd(cmd).Leases(context.TODO())
 	if rerr != nil {
 		cobrautl.ExitWithError(cobrautl.ExitBadConnection, rerr)
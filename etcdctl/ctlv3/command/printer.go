@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	v3 "go.etcd.io/etcd/client/v3"
@@ -29,6 +30,7 @@ import (
 type printer interface {
 	Del(v3.DeleteResponse)
 	Get(v3.GetResponse)
+	History(v3.HistoryResponse)
 	Put(v3.PutResponse)
 	Txn(v3.TxnResponse)
 	Watch(v3.WatchResponse)
@@ -38,6 +40,9 @@ type printer interface {
 	KeepAlive(r v3.LeaseKeepAliveResponse)
 	TimeToLive(r v3.LeaseTimeToLiveResponse, keys bool)
 	Leases(r v3.LeaseLeasesResponse)
+	LeaseList(r v3.LeaseListResponse)
+	Attach(id v3.LeaseID, r v3.LeaseAttachResponse)
+	Detach(r v3.LeaseDetachResponse)
 
 	MemberAdd(v3.MemberAddResponse)
 	MemberRemove(id uint64, r v3.MemberRemoveResponse)
@@ -56,6 +61,15 @@ type printer interface {
 
 	Alarm(v3.AlarmResponse)
 
+	QuotaSet(typ v3.QuotaType, name string, r v3.QuotaSetResponse)
+	QuotaDelete(typ v3.QuotaType, name string, r v3.QuotaDeleteResponse)
+	QuotaList(r v3.QuotaListResponse)
+
+	CompactionRetention(r v3.CompactionRetentionResponse)
+	CompactionHold(r v3.CompactionHoldResponse)
+	CompactionHoldRelease(id int64, r v3.CompactionHoldReleaseResponse)
+	CompactionHoldList(r v3.CompactionHoldListResponse)
+
 	RoleAdd(role string, r v3.AuthRoleAddResponse)
 	RoleGet(role string, r v3.AuthRoleGetResponse)
 	RoleDelete(role string, r v3.AuthRoleDeleteResponse)
@@ -101,11 +115,18 @@ func (p *printerRPC) Put(r v3.PutResponse)     { p.p((*pb.PutResponse)(&r)) }
 func (p *printerRPC) Txn(r v3.TxnResponse)     { p.p((*pb.TxnResponse)(&r)) }
 func (p *printerRPC) Watch(r v3.WatchResponse) { p.p(&r) }
 
+func (p *printerRPC) History(r v3.HistoryResponse) { p.p((*pb.HistoryResponse)(&r)) }
+
 func (p *printerRPC) Grant(r v3.LeaseGrantResponse)                      { p.p(r) }
 func (p *printerRPC) Revoke(id v3.LeaseID, r v3.LeaseRevokeResponse)     { p.p(r) }
 func (p *printerRPC) KeepAlive(r v3.LeaseKeepAliveResponse)              { p.p(r) }
 func (p *printerRPC) TimeToLive(r v3.LeaseTimeToLiveResponse, keys bool) { p.p(&r) }
 func (p *printerRPC) Leases(r v3.LeaseLeasesResponse)                    { p.p(&r) }
+func (p *printerRPC) LeaseList(r v3.LeaseListResponse)                   { p.p((*pb.LeaseListResponse)(&r)) }
+func (p *printerRPC) Attach(_ v3.LeaseID, r v3.LeaseAttachResponse) {
+	p.p((*pb.LeaseAttachResponse)(&r))
+}
+func (p *printerRPC) Detach(r v3.LeaseDetachResponse) { p.p((*pb.LeaseDetachResponse)(&r)) }
 
 func (p *printerRPC) MemberAdd(r v3.MemberAddResponse) { p.p((*pb.MemberAddResponse)(&r)) }
 func (p *printerRPC) MemberRemove(id uint64, r v3.MemberRemoveResponse) {
@@ -155,6 +176,25 @@ func (p *printerRPC) UserDelete(_ string, r v3.AuthUserDeleteResponse) {
 func (p *printerRPC) AuthStatus(r v3.AuthStatusResponse) {
 	p.p((*pb.AuthStatusResponse)(&r))
 }
+func (p *printerRPC) QuotaSet(_ v3.QuotaType, _ string, r v3.QuotaSetResponse) {
+	p.p((*pb.QuotaSetResponse)(&r))
+}
+func (p *printerRPC) QuotaDelete(_ v3.QuotaType, _ string, r v3.QuotaDeleteResponse) {
+	p.p((*pb.QuotaDeleteResponse)(&r))
+}
+func (p *printerRPC) QuotaList(r v3.QuotaListResponse) { p.p((*pb.QuotaListResponse)(&r)) }
+func (p *printerRPC) CompactionRetention(r v3.CompactionRetentionResponse) {
+	p.p((*pb.CompactionRetentionResponse)(&r))
+}
+func (p *printerRPC) CompactionHold(r v3.CompactionHoldResponse) {
+	p.p((*pb.CompactionHoldResponse)(&r))
+}
+func (p *printerRPC) CompactionHoldRelease(_ int64, r v3.CompactionHoldReleaseResponse) {
+	p.p((*pb.CompactionHoldReleaseResponse)(&r))
+}
+func (p *printerRPC) CompactionHoldList(r v3.CompactionHoldListResponse) {
+	p.p((*pb.CompactionHoldListResponse)(&r))
+}
 
 type printerUnsupported struct{ printerRPC }
 
@@ -197,6 +237,68 @@ func makeMemberListTable(r v3.MemberListResponse) (hdr []string, rows [][]string
 	return hdr, rows
 }
 
+func makeQuotaListTable(r v3.QuotaListResponse) (hdr []string, rows [][]string) {
+	hdr = []string{"Type", "Name", "Max Bytes", "Max Keys", "Used Bytes", "Used Keys", "Rate", "Burst"}
+	for _, q := range r.Quotas {
+		if q.Type == pb.Quota_PREFIX {
+			rows = append(rows, []string{
+				quotaTypeName(v3.QuotaType(q.Type)),
+				string(q.Prefix),
+				fmt.Sprint(q.MaxBytes),
+				fmt.Sprint(q.MaxKeys),
+				fmt.Sprint(q.UsedBytes),
+				fmt.Sprint(q.UsedKeys),
+				"",
+				"",
+			})
+			continue
+		}
+		rows = append(rows, []string{
+			quotaTypeName(v3.QuotaType(q.Type)),
+			q.Name,
+			"",
+			"",
+			"",
+			"",
+			fmt.Sprint(q.RequestsPerSecond),
+			fmt.Sprint(q.Burst),
+		})
+	}
+	return hdr, rows
+}
+
+func makeCompactionRetentionTable(r v3.CompactionRetentionResponse) (hdr []string, rows [][]string) {
+	hdr = []string{"Prefix", "Versions", "Duration", "Min Revision"}
+	for _, rule := range r.Rules {
+		row := []string{string(rule.Prefix), "", "", ""}
+		if rule.Versions > 0 {
+			row[1] = fmt.Sprint(rule.Versions)
+		}
+		if rule.DurationSeconds > 0 {
+			row[2] = (time.Duration(rule.DurationSeconds) * time.Second).String()
+			row[3] = fmt.Sprint(rule.MinRevision)
+		}
+		rows = append(rows, row)
+	}
+	return hdr, rows
+}
+
+func makeCompactionHoldListTable(r v3.CompactionHoldListResponse) (hdr []string, rows [][]string) {
+	hdr = []string{"ID", "Revision", "Lease"}
+	for _, hold := range r.Holds {
+		rows = append(rows, []string{
+			fmt.Sprintf("%016x", hold.ID),
+			fmt.Sprint(hold.Revision),
+			fmt.Sprintf("%016x", hold.Lease),
+		})
+	}
+	return hdr, rows
+}
+
+func quotaTypeName(typ v3.QuotaType) string {
+	return strings.ToLower(pb.Quota_Type(typ).String())
+}
+
 func makeEndpointHealthTable(healthList []epHealth) (hdr []string, rows [][]string) {
 	hdr = []string{"endpoint", "health", "took", "error"}
 	for _, h := range healthList {
@@ -75,6 +75,15 @@ func (p *fieldsPrinter) Get(r v3.GetResponse) {
 	fmt.Println(`"Count" :`, r.Count)
 }
 
+func (p *fieldsPrinter) History(r v3.HistoryResponse) {
+	p.hdr(r.Header)
+	for _, kv := range r.Kvs {
+		p.kv("", kv)
+	}
+	fmt.Println(`"More" :`, r.More)
+	fmt.Println(`"CompactRevision" :`, r.CompactRevision)
+}
+
 func (p *fieldsPrinter) Put(r v3.PutResponse) {
 	p.hdr(r.Header)
 	if r.PrevKv != nil {
@@ -146,6 +155,30 @@ func (p *fieldsPrinter) TimeToLive(r v3.LeaseTimeToLiveResponse, keys bool) {
 	for _, k := range r.Keys {
 		fmt.Printf("\"Key\" : %q\n", string(k))
 	}
+	if r.Parent != v3.NoLease {
+		p.leaseID(`"Parent"`, r.Parent)
+	}
+	for _, id := range r.Children {
+		p.leaseID(`"Child"`, id)
+	}
+	p.labels(r.Labels)
+	if r.More {
+		fmt.Println(`"More" :`, r.More)
+	}
+}
+
+func (p *fieldsPrinter) labels(labels map[string]string) {
+	if len(labels) != 0 {
+		fmt.Printf("\"Labels\" : %q\n", formatLabels(labels))
+	}
+}
+
+func (p *fieldsPrinter) leaseID(name string, id v3.LeaseID) {
+	if p.isHex {
+		fmt.Printf("%s : %016x\n", name, id)
+	} else {
+		fmt.Println(name, ":", id)
+	}
 }
 
 func (p *fieldsPrinter) Leases(r v3.LeaseLeasesResponse) {
@@ -159,6 +192,20 @@ func (p *fieldsPrinter) Leases(r v3.LeaseLeasesResponse) {
 	}
 }
 
+func (p *fieldsPrinter) LeaseList(r v3.LeaseListResponse) {
+	p.hdr(r.Header)
+	for _, l := range r.Leases {
+		p.leaseID(`"ID"`, v3.LeaseID(l.ID))
+		fmt.Println(`"GrantedTTL" :`, l.GrantedTTL)
+		if l.Parent != 0 {
+			p.leaseID(`"Parent"`, v3.LeaseID(l.Parent))
+		}
+		fmt.Println(`"Keys" :`, l.Keys)
+		p.labels(l.Labels)
+	}
+	fmt.Println(`"More" :`, r.More)
+}
+
 func (p *fieldsPrinter) MemberList(r v3.MemberListResponse) {
 	p.hdr(r.Header)
 	for _, m := range r.Members {
@@ -237,6 +284,8 @@ func (p *fieldsPrinter) RoleGet(role string, r v3.AuthRoleGetResponse) {
 		fmt.Println(`"PermType" : `, p.PermType.String())
 		fmt.Printf("\"Key\" : %q\n", string(p.Key))
 		fmt.Printf("\"RangeEnd\" : %q\n", string(p.RangeEnd))
+		fmt.Println(`"Deny" : `, p.Deny)
+		fmt.Println(`"Pattern" : `, p.Pattern)
 	}
 }
 func (p *fieldsPrinter) RoleDelete(role string, r v3.AuthRoleDeleteResponse) { p.hdr(r.Header) }
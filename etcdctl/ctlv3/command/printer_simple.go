@@ -17,9 +17,11 @@ package command
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
 	"go.etcd.io/etcd/client/pkg/v3/types"
 	v3 "go.etcd.io/etcd/client/v3"
 )
@@ -44,6 +46,17 @@ func (s *simplePrinter) Get(resp v3.GetResponse) {
 	}
 }
 
+func (s *simplePrinter) History(resp v3.HistoryResponse) {
+	for _, kv := range resp.Kvs {
+		if kv.Version == 0 {
+			fmt.Println(mvccpb.DELETE, kv.ModRevision)
+		} else {
+			fmt.Println(mvccpb.PUT, kv.ModRevision)
+		}
+		printKV(s.isHex, s.valueOnly, kv)
+	}
+}
+
 func (s *simplePrinter) Put(r v3.PutResponse) {
 	fmt.Println("OK")
 	if r.PrevKv != nil {
@@ -91,6 +104,14 @@ func (s *simplePrinter) Revoke(id v3.LeaseID, r v3.LeaseRevokeResponse) {
 	fmt.Printf("lease %016x revoked\n", id)
 }
 
+func (s *simplePrinter) Attach(id v3.LeaseID, r v3.LeaseAttachResponse) {
+	fmt.Printf("%d keys attached to lease %016x\n", r.Count, id)
+}
+
+func (s *simplePrinter) Detach(r v3.LeaseDetachResponse) {
+	fmt.Printf("%d keys detached\n", r.Count)
+}
+
 func (s *simplePrinter) KeepAlive(resp v3.LeaseKeepAliveResponse) {
 	fmt.Printf("lease %016x keepalived with TTL(%d)\n", resp.ID, resp.TTL)
 }
@@ -108,6 +129,22 @@ func (s *simplePrinter) TimeToLive(resp v3.LeaseTimeToLiveResponse, keys bool) {
 			ks[i] = string(resp.Keys[i])
 		}
 		txt += fmt.Sprintf(", attached keys(%v)", ks)
+		if resp.More {
+			txt += ", more keys"
+		}
+	}
+	if resp.Parent != v3.NoLease {
+		txt += fmt.Sprintf(", parent(%016x)", resp.Parent)
+	}
+	if len(resp.Children) != 0 {
+		ids := make([]string, len(resp.Children))
+		for i := range resp.Children {
+			ids[i] = fmt.Sprintf("%016x", resp.Children[i])
+		}
+		txt += fmt.Sprintf(", children(%v)", ids)
+	}
+	if len(resp.Labels) != 0 {
+		txt += fmt.Sprintf(", labels(%s)", formatLabels(resp.Labels))
 	}
 	fmt.Println(txt)
 }
@@ -119,12 +156,76 @@ func (s *simplePrinter) Leases(resp v3.LeaseLeasesResponse) {
 	}
 }
 
+func (s *simplePrinter) LeaseList(resp v3.LeaseListResponse) {
+	fmt.Printf("found %d leases\n", len(resp.Leases))
+	for _, l := range resp.Leases {
+		txt := fmt.Sprintf("%016x granted with TTL(%ds), attached keys(%d)", l.ID, l.GrantedTTL, l.Keys)
+		if l.Parent != 0 {
+			txt += fmt.Sprintf(", parent(%016x)", l.Parent)
+		}
+		if len(l.Labels) != 0 {
+			txt += fmt.Sprintf(", labels(%s)", formatLabels(l.Labels))
+		}
+		fmt.Println(txt)
+	}
+	if resp.More {
+		fmt.Println("more leases match")
+	}
+}
+
+// formatLabels formats the labels as key=value pairs sorted by key.
+func formatLabels(labels map[string]string) string {
+	kvs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		kvs = append(kvs, k+"="+v)
+	}
+	sort.Strings(kvs)
+	return strings.Join(kvs, ",")
+}
+
 func (s *simplePrinter) Alarm(resp v3.AlarmResponse) {
 	for _, e := range resp.Alarms {
 		fmt.Printf("%+v\n", e)
 	}
 }
 
+func (s *simplePrinter) QuotaSet(typ v3.QuotaType, name string, r v3.QuotaSetResponse) {
+	fmt.Printf("Quota on %s %s set\n", quotaTypeName(typ), name)
+}
+
+func (s *simplePrinter) QuotaDelete(typ v3.QuotaType, name string, r v3.QuotaDeleteResponse) {
+	fmt.Printf("Quota on %s %s deleted\n", quotaTypeName(typ), name)
+}
+
+func (s *simplePrinter) QuotaList(resp v3.QuotaListResponse) {
+	_, rows := makeQuotaListTable(resp)
+	for _, row := range rows {
+		fmt.Println(strings.Join(row, ", "))
+	}
+}
+
+func (s *simplePrinter) CompactionRetention(resp v3.CompactionRetentionResponse) {
+	_, rows := makeCompactionRetentionTable(resp)
+	for _, row := range rows {
+		fmt.Println(strings.Join(row, ", "))
+	}
+}
+
+func (s *simplePrinter) CompactionHold(resp v3.CompactionHoldResponse) {
+	fmt.Printf("compaction hold %016x acquired on revision %d\n", resp.ID, resp.Revision)
+}
+
+func (s *simplePrinter) CompactionHoldRelease(id int64, r v3.CompactionHoldReleaseResponse) {
+	fmt.Printf("compaction hold %016x released\n", id)
+}
+
+func (s *simplePrinter) CompactionHoldList(resp v3.CompactionHoldListResponse) {
+	_, rows := makeCompactionHoldListTable(resp)
+	for _, row := range rows {
+		fmt.Println(strings.Join(row, ", "))
+	}
+}
+
 func (s *simplePrinter) MemberAdd(r v3.MemberAddResponse) {
 	asLearner := " "
 	if r.Member.IsLearner {
@@ -217,26 +318,34 @@ func (s *simplePrinter) RoleGet(role string, r v3.AuthRoleGetResponse) {
 		if v3.GetPrefixRangeEnd(sKey) == sRangeEnd && len(sKey) > 0 {
 			fmt.Printf(" (prefix %s)", sKey)
 		}
+	}
+
+	printPerm := func(perm *v3.Permission) {
+		switch {
+		case perm.Pattern && len(perm.RangeEnd) != 0:
+			fmt.Printf("\t%s (prefix pattern)", string(perm.Key))
+		case perm.Pattern:
+			fmt.Printf("\t%s (pattern)", string(perm.Key))
+		case len(perm.RangeEnd) == 0:
+			fmt.Printf("\t%s", string(perm.Key))
+		default:
+			printRange(perm)
+		}
+		if perm.Deny {
+			fmt.Print(" (deny)")
+		}
 		fmt.Print("\n")
 	}
 
 	for _, perm := range r.Perm {
 		if perm.PermType == v3.PermRead || perm.PermType == v3.PermReadWrite {
-			if len(perm.RangeEnd) == 0 {
-				fmt.Printf("\t%s\n", string(perm.Key))
-			} else {
-				printRange((*v3.Permission)(perm))
-			}
+			printPerm((*v3.Permission)(perm))
 		}
 	}
 	fmt.Println("KV Write:")
 	for _, perm := range r.Perm {
 		if perm.PermType == v3.PermWrite || perm.PermType == v3.PermReadWrite {
-			if len(perm.RangeEnd) == 0 {
-				fmt.Printf("\t%s\n", string(perm.Key))
-			} else {
-				printRange((*v3.Permission)(perm))
-			}
+			printPerm((*v3.Permission)(perm))
 		}
 	}
 }
@@ -0,0 +1,142 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	v3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/pkg/v3/cobrautl"
+)
+
+var (
+	quotaMaxBytes int64
+	quotaMaxKeys  int64
+	quotaRate     int64
+	quotaBurst    int64
+)
+
+// NewQuotaCommand returns the cobra command for "quota".
+func NewQuotaCommand() *cobra.Command {
+	qc := &cobra.Command{
+		Use:   "quota <subcommand>",
+		Short: "Quota related commands",
+	}
+
+	qc.AddCommand(newQuotaSetCommand())
+	qc.AddCommand(newQuotaDeleteCommand())
+	qc.AddCommand(newQuotaListCommand())
+
+	return qc
+}
+
+func newQuotaSetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set <prefix|user|role> <name>",
+		Short: "Sets a storage quota on a key prefix or a request rate limit on a user or role",
+		Run:   quotaSetCommandFunc,
+	}
+	cmd.Flags().Int64Var(&quotaMaxBytes, "max-bytes", 0, "Maximum total size of the keys and values under the prefix")
+	cmd.Flags().Int64Var(&quotaMaxKeys, "max-keys", 0, "Maximum number of keys under the prefix")
+	cmd.Flags().Int64Var(&quotaRate, "rate", 0, "Requests per second allowed to the user or role")
+	cmd.Flags().Int64Var(&quotaBurst, "burst", 0, "Requests allowed at once to the user or role (defaults to --rate)")
+	return cmd
+}
+
+func newQuotaDeleteCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <prefix|user|role> <name>",
+		Short: "Deletes a storage quota or a request rate limit",
+		Run:   quotaDeleteCommandFunc,
+	}
+}
+
+func newQuotaListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "Lists the storage quotas with their usage and the request rate limits",
+		Run:   quotaListCommandFunc,
+	}
+}
+
+// quotaSetCommandFunc executes the "quota set" command.
+func quotaSetCommandFunc(cmd *cobra.Command, args []string) {
+	typ, name := quotaTarget("set", args)
+	q := &v3.Quota{Type: pb.Quota_Type(typ)}
+	if typ == v3.QuotaPrefix {
+		if quotaRate != 0 || quotaBurst != 0 {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("--rate and --burst only apply to users and roles"))
+		}
+		q.Prefix, q.MaxBytes, q.MaxKeys = []byte(name), quotaMaxBytes, quotaMaxKeys
+	} else {
+		if quotaMaxBytes != 0 || quotaMaxKeys != 0 {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("--max-bytes and --max-keys only apply to prefixes"))
+		}
+		q.Name, q.RequestsPerSecond, q.Burst = name, quotaRate, quotaBurst
+	}
+
+	ctx, cancel := commandCtx(cmd)
+	resp, err := mustClientFromCmd(cmd).QuotaSet(ctx, q)
+	cancel()
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+	display.QuotaSet(typ, name, *resp)
+}
+
+// quotaDeleteCommandFunc executes the "quota delete" command.
+func quotaDeleteCommandFunc(cmd *cobra.Command, args []string) {
+	typ, name := quotaTarget("delete", args)
+	ctx, cancel := commandCtx(cmd)
+	resp, err := mustClientFromCmd(cmd).QuotaDelete(ctx, typ, name)
+	cancel()
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+	display.QuotaDelete(typ, name, *resp)
+}
+
+// quotaListCommandFunc executes the "quota list" command.
+func quotaListCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 0 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("quota list command accepts no arguments"))
+	}
+	ctx, cancel := commandCtx(cmd)
+	resp, err := mustClientFromCmd(cmd).QuotaList(ctx)
+	cancel()
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+	display.QuotaList(*resp)
+}
+
+func quotaTarget(command string, args []string) (v3.QuotaType, string) {
+	if len(args) != 2 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("quota %s command needs a type and a name as arguments", command))
+	}
+	switch args[0] {
+	case "prefix":
+		return v3.QuotaPrefix, args[1]
+	case "user":
+		return v3.QuotaUser, args[1]
+	case "role":
+		return v3.QuotaRole, args[1]
+	}
+	cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("unknown quota type %q (only supports prefix, user or role)", args[0]))
+	return 0, ""
+}
@@ -27,6 +27,9 @@ import (
 var (
 	rolePermPrefix  bool
 	rolePermFromKey bool
+	rolePermDeny    bool
+	rolePermPattern bool
+	rolePermType    string
 )
 
 // NewRoleCommand returns the cobra command for "role".
@@ -87,6 +90,8 @@ func newRoleGrantPermissionCommand() *cobra.Command {
 
 	cmd.Flags().BoolVar(&rolePermPrefix, "prefix", false, "grant a prefix permission")
 	cmd.Flags().BoolVar(&rolePermFromKey, "from-key", false, "grant a permission of keys that are greater than or equal to the given key using byte compare")
+	cmd.Flags().BoolVar(&rolePermDeny, "deny", false, "deny the access to the keys even if other permissions grant it")
+	cmd.Flags().BoolVar(&rolePermPattern, "pattern", false, "treat the key as a glob pattern, where '*' matches any sequence of characters other than '/'")
 
 	return cmd
 }
@@ -100,6 +105,9 @@ func newRoleRevokePermissionCommand() *cobra.Command {
 
 	cmd.Flags().BoolVar(&rolePermPrefix, "prefix", false, "revoke a prefix permission")
 	cmd.Flags().BoolVar(&rolePermFromKey, "from-key", false, "revoke a permission of keys that are greater than or equal to the given key using byte compare")
+	cmd.Flags().StringVar(&rolePermType, "type", "", "revoke only the permission of the given type (read, write or readwrite), matching --deny and --pattern")
+	cmd.Flags().BoolVar(&rolePermDeny, "deny", false, "revoke only a deny permission, requires --type")
+	cmd.Flags().BoolVar(&rolePermPattern, "pattern", false, "revoke only a glob pattern permission, requires --type")
 
 	return cmd
 }
@@ -172,8 +180,13 @@ func roleGrantPermissionCommandFunc(cmd *cobra.Command, args []string) {
 		cobrautl.ExitWithError(cobrautl.ExitBadArgs, err)
 	}
 
+	if rolePermPattern && (len(args) > 3 || rolePermFromKey || len(args[2]) == 0) {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("--pattern flag requires a non-empty key and can only be combined with --prefix"))
+	}
+
 	key, rangeEnd := permRange(args[2:])
-	resp, err := mustClientFromCmd(cmd).Auth.RoleGrantPermission(context.TODO(), args[0], key, rangeEnd, perm)
+	opts := &clientv3.PermissionOptions{Deny: rolePermDeny, Pattern: rolePermPattern}
+	resp, err := mustClientFromCmd(cmd).Auth.RoleGrantPermissionWithOptions(context.TODO(), args[0], key, rangeEnd, perm, opts)
 	if err != nil {
 		cobrautl.ExitWithError(cobrautl.ExitError, err)
 	}
@@ -187,8 +200,25 @@ func roleRevokePermissionCommandFunc(cmd *cobra.Command, args []string) {
 		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("role revoke-permission command requires role name and key [endkey] as its argument"))
 	}
 
+	if rolePermType == "" && (rolePermDeny || rolePermPattern) {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("--deny and --pattern flags require --type"))
+	}
+
 	key, rangeEnd := permRange(args[1:])
-	resp, err := mustClientFromCmd(cmd).Auth.RoleRevokePermission(context.TODO(), args[0], key, rangeEnd)
+	var (
+		resp *clientv3.AuthRoleRevokePermissionResponse
+		err  error
+	)
+	if rolePermType != "" {
+		perm, perr := clientv3.StrToPermissionType(rolePermType)
+		if perr != nil {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, perr)
+		}
+		opts := &clientv3.PermissionOptions{Deny: rolePermDeny, Pattern: rolePermPattern}
+		resp, err = mustClientFromCmd(cmd).Auth.RoleRevokePermissionWithOptions(context.TODO(), args[0], key, rangeEnd, perm, opts)
+	} else {
+		resp, err = mustClientFromCmd(cmd).Auth.RoleRevokePermission(context.TODO(), args[0], key, rangeEnd)
+	}
 	if err != nil {
 		cobrautl.ExitWithError(cobrautl.ExitError, err)
 	}
@@ -99,6 +99,7 @@ func init() {
 		command.NewCheckCommand(),
 		command.NewCompletionCommand(),
 		command.NewDowngradeCommand(),
+		command.NewQuotaCommand(),
 	)
 }
 
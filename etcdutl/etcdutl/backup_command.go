@@ -33,6 +33,7 @@ import (
 	"go.etcd.io/etcd/server/v3/etcdserver/api/v2store"
 	"go.etcd.io/etcd/server/v3/storage/backend"
 	"go.etcd.io/etcd/server/v3/storage/datadir"
+	"go.etcd.io/etcd/server/v3/storage/encryption"
 	"go.etcd.io/etcd/server/v3/storage/schema"
 	"go.etcd.io/etcd/server/v3/storage/wal"
 	"go.etcd.io/etcd/server/v3/storage/wal/walpb"
@@ -43,11 +44,12 @@ import (
 )
 
 var (
-	withV3       bool
-	dataDir      string
-	backupDir    string
-	walDir       string
-	backupWalDir string
+	withV3        bool
+	dataDir       string
+	backupDir     string
+	walDir        string
+	backupWalDir  string
+	backupKeyFile string
 )
 
 func NewBackupCommand() *cobra.Command {
@@ -62,6 +64,7 @@ func NewBackupCommand() *cobra.Command {
 	cmd.Flags().StringVar(&walDir, "wal-dir", "", "Path to the etcd wal dir")
 	cmd.Flags().StringVar(&backupDir, "backup-dir", "", "Path to the backup dir")
 	cmd.Flags().StringVar(&backupWalDir, "backup-wal-dir", "", "Path to the backup wal dir")
+	cmd.Flags().StringVar(&backupKeyFile, "encryption-key-file", "", "Path to the key file given to etcd by --experimental-encryption-key-file, if the data dir is encrypted")
 	cmd.Flags().BoolVar(&withV3, "with-v3", true, "Backup v3 backend data. Note -with-v3=false is not supported since etcd v3.6. Please use v3.5.x client as the last supporting this deprecated functionality.")
 	cmd.MarkFlagRequired("data-dir")
 	cmd.MarkFlagRequired("backup-dir")
@@ -73,7 +76,7 @@ func NewBackupCommand() *cobra.Command {
 }
 
 func doBackup(cmd *cobra.Command, args []string) {
-	HandleBackup(withV3, dataDir, backupDir, walDir, backupWalDir)
+	HandleBackup(withV3, dataDir, backupDir, walDir, backupWalDir, backupKeyFile)
 }
 
 type desiredCluster struct {
@@ -105,7 +108,9 @@ func newDesiredCluster() desiredCluster {
 }
 
 // HandleBackup handles a request that intends to do a backup.
-func HandleBackup(withV3 bool, srcDir string, destDir string, srcWAL string, destWAL string) error {
+// The snap files and the WAL of an encrypted data dir are read with the keys
+// of keyFile, and the backup is encrypted with its active key.
+func HandleBackup(withV3 bool, srcDir string, destDir string, srcWAL string, destWAL string, keyFile string) error {
 	lg := GetLogger()
 
 	if !withV3 {
@@ -113,6 +118,15 @@ func HandleBackup(withV3 bool, srcDir string, destDir string, srcWAL string, des
 		return nil
 	}
 
+	var c encryption.Cipher
+	if keyFile != "" {
+		kms, err := encryption.NewLocalKMS(keyFile)
+		if err != nil {
+			lg.Fatal("failed loading encryption key file", zap.String("encryption-key-file", keyFile), zap.Error(err))
+		}
+		c = encryption.NewEnvelope(kms)
+	}
+
 	srcSnap := datadir.ToSnapDir(srcDir)
 	destSnap := datadir.ToSnapDir(destDir)
 
@@ -132,8 +146,8 @@ func HandleBackup(withV3 bool, srcDir string, destDir string, srcWAL string, des
 	srcDbPath := datadir.ToBackendFileName(srcDir)
 	desired := newDesiredCluster()
 
-	walsnap := saveSnap(lg, destSnap, srcSnap, &desired)
-	metadata, state, ents := translateWAL(lg, srcWAL, walsnap)
+	walsnap := saveSnap(lg, destSnap, srcSnap, &desired, c)
+	metadata, state, ents := translateWAL(lg, srcWAL, walsnap, c)
 	saveDB(lg, destDbPath, srcDbPath, state.Commit, state.Term, &desired)
 
 	neww, err := wal.Create(lg, destWAL, pbutil.MustMarshal(&metadata))
@@ -141,6 +155,9 @@ func HandleBackup(withV3 bool, srcDir string, destDir string, srcWAL string, des
 		lg.Fatal("wal.Create failed", zap.Error(err))
 	}
 	defer neww.Close()
+	if c != nil {
+		neww.SetCipher(c)
+	}
 	if err := neww.Save(state, ents); err != nil {
 		lg.Fatal("wal.Save failed ", zap.Error(err))
 	}
@@ -157,8 +174,11 @@ func HandleBackup(withV3 bool, srcDir string, destDir string, srcWAL string, des
 	return nil
 }
 
-func saveSnap(lg *zap.Logger, destSnap, srcSnap string, desired *desiredCluster) (walsnap walpb.Snapshot) {
+func saveSnap(lg *zap.Logger, destSnap, srcSnap string, desired *desiredCluster, c encryption.Cipher) (walsnap walpb.Snapshot) {
 	ss := snap.New(lg, srcSnap)
+	if c != nil {
+		ss.SetCipher(c)
+	}
 	snapshot, err := ss.Load()
 	if err != nil && err != snap.ErrNoSnapshot {
 		lg.Fatal("saveSnap(Snapshoter.Load) failed", zap.Error(err))
@@ -166,6 +186,9 @@ func saveSnap(lg *zap.Logger, destSnap, srcSnap string, desired *desiredCluster)
 	if snapshot != nil {
 		walsnap.Index, walsnap.Term, walsnap.ConfState = snapshot.Metadata.Index, snapshot.Metadata.Term, &desired.confState
 		newss := snap.New(lg, destSnap)
+		if c != nil {
+			newss.SetCipher(c)
+		}
 		snapshot.Metadata.ConfState = desired.confState
 		snapshot.Data = mustTranslateV2store(lg, snapshot.Data, desired)
 		if err = newss.SaveSnap(*snapshot); err != nil {
@@ -195,12 +218,15 @@ func mustTranslateV2store(lg *zap.Logger, storeData []byte, desired *desiredClus
 	return outputData
 }
 
-func translateWAL(lg *zap.Logger, srcWAL string, walsnap walpb.Snapshot) (etcdserverpb.Metadata, raftpb.HardState, []raftpb.Entry) {
+func translateWAL(lg *zap.Logger, srcWAL string, walsnap walpb.Snapshot, c encryption.Cipher) (etcdserverpb.Metadata, raftpb.HardState, []raftpb.Entry) {
 	w, err := wal.OpenForRead(lg, srcWAL, walsnap)
 	if err != nil {
 		lg.Fatal("wal.OpenForRead failed", zap.Error(err))
 	}
 	defer w.Close()
+	if c != nil {
+		w.SetCipher(c)
+	}
 	wmetadata, state, ents, err := w.ReadAll()
 	switch err {
 	case nil:
@@ -0,0 +1,247 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdutl
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/client/pkg/v3/fileutil"
+	"go.etcd.io/etcd/pkg/v3/cobrautl"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/snap"
+	"go.etcd.io/etcd/server/v3/storage/datadir"
+	"go.etcd.io/etcd/server/v3/storage/encryption"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+	"go.etcd.io/etcd/server/v3/storage/schema"
+	"go.etcd.io/etcd/server/v3/storage/wal"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	encryptionKeyFile       string
+	encryptionDataDir       string
+	encryptionOutputDataDir string
+)
+
+// rewriteBatchSize is the number of revisions rewritten per bolt transaction.
+const rewriteBatchSize = 10000
+
+// NewEncryptionCommand returns the cobra command for "encryption".
+func NewEncryptionCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "encryption <subcommand>",
+		Short: "Manages the keys encrypting the etcd data at rest",
+	}
+	cmd.PersistentFlags().StringVar(&encryptionKeyFile, "encryption-key-file", "", "Required. Path to the key file given to etcd by --experimental-encryption-key-file")
+	cmd.MarkPersistentFlagRequired("encryption-key-file")
+	cmd.AddCommand(newEncryptionRotateKeyCommand())
+	cmd.AddCommand(newEncryptionDecryptCommand())
+	return cmd
+}
+
+func newEncryptionRotateKeyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotate-key",
+		Short: "Adds a new key encrypting the data written by etcd once restarted",
+		Long: `Adds a new key to the key file, creating it if needed. The members restarted with the key file
+encrypt the data they write with the new key and decrypt the data written before with the older keys,
+which must be kept until no data encrypted with them remains. If --data-dir is given, the revisions
+of its backend are re-encrypted with the new key; otherwise they are as they are rewritten.`,
+		Run: encryptionRotateKeyCommandFunc,
+	}
+	cmd.Flags().StringVar(&encryptionDataDir, "data-dir", "", "Re-encrypts the backend of a data directory not in use by etcd with the new key.")
+	cmd.MarkFlagDirname("data-dir")
+	return cmd
+}
+
+func newEncryptionDecryptCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "decrypt",
+		Short: "Writes a decrypted copy of a data directory for offline inspection",
+		Long: `Writes a copy of the backend, the newest snap file and the WAL of a data directory not in use by
+etcd with their data decrypted, so that it is inspected without the key file.`,
+		Run: encryptionDecryptCommandFunc,
+	}
+	cmd.Flags().StringVar(&encryptionDataDir, "data-dir", "", "Required. Path to the data directory to decrypt.")
+	cmd.Flags().StringVar(&encryptionOutputDataDir, "output-data-dir", "", "Required. Path to the decrypted data directory, which must not exist.")
+	cmd.MarkFlagRequired("data-dir")
+	cmd.MarkFlagRequired("output-data-dir")
+	cmd.MarkFlagDirname("data-dir")
+	cmd.MarkFlagDirname("output-data-dir")
+	return cmd
+}
+
+func encryptionRotateKeyCommandFunc(cmd *cobra.Command, args []string) {
+	id, err := RotateEncryptionKey(GetLogger(), encryptionKeyFile, encryptionDataDir)
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+	fmt.Printf("Rotated to encryption key %q\n", id)
+}
+
+func encryptionDecryptCommandFunc(cmd *cobra.Command, args []string) {
+	if err := DecryptData(GetLogger(), encryptionKeyFile, encryptionDataDir, encryptionOutputDataDir); err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+	fmt.Printf("Decrypted %q into %q\n", encryptionDataDir, encryptionOutputDataDir)
+}
+
+// RotateEncryptionKey adds a new key to the key file and returns its ID. If
+// dataDir is not empty, the revisions of its backend are re-encrypted with the
+// new key.
+func RotateEncryptionKey(lg *zap.Logger, keyFile, dataDir string) (string, error) {
+	id, err := encryption.RotateKeyFile(keyFile)
+	if err != nil {
+		return "", err
+	}
+	if dataDir == "" {
+		return id, nil
+	}
+	kms, err := encryption.NewLocalKMS(keyFile)
+	if err != nil {
+		return id, err
+	}
+	c := encryption.NewEnvelope(kms)
+	n, err := rewriteRevisions(datadir.ToBackendFileName(dataDir), func(v []byte) ([]byte, error) {
+		return mvcc.EncryptKeyValue(c, v)
+	})
+	if err != nil {
+		return id, err
+	}
+	lg.Info("re-encrypted revisions", zap.String("key-id", id), zap.Int("revisions", n))
+	return id, nil
+}
+
+// DecryptData writes to outputDataDir a copy of the backend, the newest snap
+// file and the WAL of dataDir with their data decrypted.
+func DecryptData(lg *zap.Logger, keyFile, dataDir, outputDataDir string) error {
+	if fileutil.Exist(outputDataDir) {
+		return fmt.Errorf("output data directory %q exists", outputDataDir)
+	}
+	kms, err := encryption.NewLocalKMS(keyFile)
+	if err != nil {
+		return err
+	}
+	c := encryption.NewEnvelope(kms)
+
+	if err = fileutil.CreateDirAll(lg, datadir.ToSnapDir(outputDataDir)); err != nil {
+		return err
+	}
+	dbPath := datadir.ToBackendFileName(outputDataDir)
+	if err = copyFile(datadir.ToBackendFileName(dataDir), dbPath); err != nil {
+		return err
+	}
+	n, err := rewriteRevisions(dbPath, func(v []byte) ([]byte, error) {
+		return mvcc.DecryptKeyValue(c, v)
+	})
+	if err != nil {
+		return err
+	}
+	lg.Info("decrypted revisions", zap.String("path", dbPath), zap.Int("revisions", n))
+
+	ss := snap.New(lg, datadir.ToSnapDir(dataDir))
+	ss.SetCipher(c)
+	snapshot, err := ss.Load()
+	switch {
+	case errors.Is(err, snap.ErrNoSnapshot):
+	case err != nil:
+		return err
+	default:
+		if err = snap.New(lg, datadir.ToSnapDir(outputDataDir)).SaveSnap(*snapshot); err != nil {
+			return err
+		}
+	}
+
+	return wal.Decrypt(lg, datadir.ToWalDir(dataDir), datadir.ToWalDir(outputDataDir), c)
+}
+
+// rewriteRevisions rewrites the revisions of the key bucket of the backend
+// with f, and returns the number of revisions changed.
+func rewriteRevisions(dbPath string, f func(v []byte) ([]byte, error)) (int, error) {
+	db, err := bolt.Open(dbPath, 0600, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer db.Close()
+
+	n := 0
+	var next []byte
+	for done := false; !done; {
+		err = db.Update(func(tx *bolt.Tx) error {
+			b := tx.Bucket(schema.Key.Name())
+			if b == nil {
+				done = true
+				return nil
+			}
+			// the bucket is not modified while iterated
+			var keys, values [][]byte
+			c := b.Cursor()
+			k, v := c.First()
+			if next != nil {
+				k, v = c.Seek(next)
+			}
+			for ; k != nil && len(keys) < rewriteBatchSize; k, v = c.Next() {
+				nv, err := f(v)
+				if err != nil {
+					return fmt.Errorf("cannot rewrite revision %x: %w", k, err)
+				}
+				if !bytes.Equal(nv, v) {
+					keys = append(keys, append([]byte(nil), k...))
+					values = append(values, nv)
+				}
+			}
+			if k == nil {
+				done = true
+			} else {
+				next = append(next[:0], k...)
+			}
+			for i := range keys {
+				if err := b.Put(keys[i], values[i]); err != nil {
+					return err
+				}
+			}
+			n += len(keys)
+			return nil
+		})
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, fileutil.PrivateFileMode)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	if _, err = io.Copy(out, in); err != nil {
+		return err
+	}
+	return fileutil.Fsync(out)
+}
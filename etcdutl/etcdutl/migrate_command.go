@@ -26,6 +26,7 @@ import (
 	"go.etcd.io/etcd/pkg/v3/cobrautl"
 	"go.etcd.io/etcd/server/v3/storage/backend"
 	"go.etcd.io/etcd/server/v3/storage/datadir"
+	"go.etcd.io/etcd/server/v3/storage/encryption"
 	"go.etcd.io/etcd/server/v3/storage/schema"
 	"go.etcd.io/etcd/server/v3/storage/wal"
 	"go.etcd.io/etcd/server/v3/storage/wal/walpb"
@@ -56,6 +57,7 @@ type migrateOptions struct {
 	dataDir       string
 	targetVersion string
 	force         bool
+	keyFile       string
 }
 
 func newMigrateOptions() *migrateOptions {
@@ -71,6 +73,8 @@ func (o *migrateOptions) AddFlags(cmd *cobra.Command) {
 	cmd.MarkFlagRequired("target-version")
 
 	cmd.Flags().BoolVar(&o.force, "force", o.force, "Ignore migration failure and forcefully override storage version. Not recommended.")
+
+	cmd.Flags().StringVar(&o.keyFile, "encryption-key-file", o.keyFile, "Path to the key file given to etcd by --experimental-encryption-key-file, if the data dir is encrypted")
 }
 
 func (o *migrateOptions) Config() (*migrateConfig, error) {
@@ -91,6 +95,15 @@ func (o *migrateOptions) Config() (*migrateConfig, error) {
 		return nil, fmt.Errorf(`target version %q not supported. Minimal "3.5"`, storageVersionToString(c.targetVersion))
 	}
 
+	var cipher encryption.Cipher
+	if o.keyFile != "" {
+		kms, err := encryption.NewLocalKMS(o.keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load encryption key file: %v", err)
+		}
+		cipher = encryption.NewEnvelope(kms)
+	}
+
 	dbPath := datadir.ToBackendFileName(o.dataDir)
 	c.be = backend.NewDefaultBackend(GetLogger(), dbPath)
 
@@ -100,6 +113,9 @@ func (o *migrateOptions) Config() (*migrateConfig, error) {
 		return nil, fmt.Errorf(`failed to open wal: %v`, err)
 	}
 	defer w.Close()
+	if cipher != nil {
+		w.SetCipher(cipher)
+	}
 	c.walVersion, err = wal.ReadWALVersion(w)
 	if err != nil {
 		return nil, fmt.Errorf(`failed to read wal: %v`, err)
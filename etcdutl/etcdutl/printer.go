@@ -67,13 +67,16 @@ func newPrinterUnsupported(n string) printer {
 func (p *printerUnsupported) DBStatus(snapshot.Status) { p.p(nil) }
 
 func makeDBStatusTable(ds snapshot.Status) (hdr []string, rows [][]string) {
-	hdr = []string{"hash", "revision", "total keys", "total size", "version"}
+	hdr = []string{"hash", "revision", "total keys", "total size", "version", "compressed revisions", "encrypted revisions", "revision size (uncompressed)"}
 	rows = append(rows, []string{
 		fmt.Sprintf("%x", ds.Hash),
 		fmt.Sprint(ds.Revision),
 		fmt.Sprint(ds.TotalKey),
 		humanize.Bytes(uint64(ds.TotalSize)),
 		ds.Version,
+		fmt.Sprint(ds.CompressedRevision),
+		fmt.Sprint(ds.EncryptedRevision),
+		fmt.Sprintf("%s (%s)", humanize.Bytes(uint64(ds.RevisionSize)), humanize.Bytes(uint64(ds.RawRevisionSize))),
 	})
 	return hdr, rows
 }
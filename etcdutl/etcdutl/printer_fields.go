@@ -28,4 +28,8 @@ func (p *fieldsPrinter) DBStatus(r snapshot.Status) {
 	fmt.Println(`"Keys" :`, r.TotalKey)
 	fmt.Println(`"Size" :`, r.TotalSize)
 	fmt.Println(`"Version" :`, r.Version)
+	fmt.Println(`"CompressedRevision" :`, r.CompressedRevision)
+	fmt.Println(`"EncryptedRevision" :`, r.EncryptedRevision)
+	fmt.Println(`"RevisionSize" :`, r.RevisionSize)
+	fmt.Println(`"RawRevisionSize" :`, r.RawRevisionSize)
 }
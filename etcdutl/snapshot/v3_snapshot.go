@@ -41,6 +41,7 @@ import (
 	"go.etcd.io/etcd/server/v3/etcdserver/api/v2store"
 	"go.etcd.io/etcd/server/v3/etcdserver/cindex"
 	"go.etcd.io/etcd/server/v3/storage/backend"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
 	"go.etcd.io/etcd/server/v3/storage/schema"
 	"go.etcd.io/etcd/server/v3/storage/wal"
 	"go.etcd.io/etcd/server/v3/storage/wal/walpb"
@@ -108,6 +109,14 @@ type Status struct {
 	// Version is equal to storageVersion of the snapshot
 	// Empty if server does not supports versioned snapshots (<v3.6)
 	Version string `json:"version"`
+	// CompressedRevision is the number of revisions stored compressed.
+	CompressedRevision int `json:"compressedRevision"`
+	// EncryptedRevision is the number of revisions stored encrypted.
+	EncryptedRevision int `json:"encryptedRevision"`
+	// RevisionSize is the size of the unencrypted revisions as they are
+	// stored, and RawRevisionSize their size uncompressed.
+	RevisionSize    int64 `json:"revisionSize"`
+	RawRevisionSize int64 `json:"rawRevisionSize"`
 }
 
 // Status returns the snapshot file information.
@@ -158,6 +167,20 @@ func (s *v3Manager) Status(dbPath string) (ds Status, err error) {
 				if iskeyb {
 					rev := bytesToRev(k)
 					ds.Revision = rev.main
+					if mvcc.IsEncryptedKeyValue(v) {
+						ds.EncryptedRevision++
+						ds.TotalKey++
+						return nil
+					}
+					d, err := mvcc.DecodeKeyValue(v)
+					if err != nil {
+						return fmt.Errorf("cannot decode revision %d: %v", rev.main, err)
+					}
+					if mvcc.IsCompressedKeyValue(v) {
+						ds.CompressedRevision++
+					}
+					ds.RevisionSize += int64(len(v))
+					ds.RawRevisionSize += int64(len(d))
 				}
 				ds.TotalKey++
 				return nil
@@ -0,0 +1,202 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package audit records who did what to an etcd cluster.
+package audit
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Level selects the operations recorded by the audit log. Each level records
+// the operations of the levels before it.
+type Level string
+
+const (
+	// LevelAdmin records authentication, membership and maintenance operations.
+	LevelAdmin Level = "admin"
+	// LevelWrite records the mutations of keys and leases in addition.
+	LevelWrite Level = "write"
+	// LevelRead records the reads in addition.
+	LevelRead Level = "read"
+
+	DefaultLevel = LevelWrite
+)
+
+var levelRanks = map[Level]int{
+	LevelAdmin: 0,
+	LevelWrite: 1,
+	LevelRead:  2,
+}
+
+// ParseLevel parses the name of an audit level.
+func ParseLevel(s string) (Level, error) {
+	if _, ok := levelRanks[Level(s)]; !ok {
+		return "", fmt.Errorf("unknown audit level %q (only supports %q, %q or %q)", s, LevelAdmin, LevelWrite, LevelRead)
+	}
+	return Level(s), nil
+}
+
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// Op is an operation on a key or a range of keys.
+type Op struct {
+	Type     string `json:"type"`
+	Key      string `json:"key"`
+	RangeEnd string `json:"range_end,omitempty"`
+}
+
+// Event is a single entry of the audit log. Values of keys are never recorded.
+type Event struct {
+	Time       time.Time `json:"time"`
+	User       string    `json:"user,omitempty"`
+	ClientAddr string    `json:"client_addr,omitempty"`
+	Operation  string    `json:"operation"`
+	Key        string    `json:"key,omitempty"`
+	RangeEnd   string    `json:"range_end,omitempty"`
+	// Ops are the operations executed by a transaction.
+	Ops   []Op  `json:"ops,omitempty"`
+	Lease int64 `json:"lease,omitempty"`
+	// Target is the user, role or member the operation applies to.
+	Target   string `json:"target,omitempty"`
+	Role     string `json:"role,omitempty"`
+	Revision int64  `json:"revision,omitempty"`
+	Result   string `json:"result"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Sink receives the audit events. It must be safe for concurrent use.
+type Sink interface {
+	Write(ev *Event) error
+	Close() error
+}
+
+// Logger records audit events of the enabled level to a sink.
+type Logger struct {
+	lg              *zap.Logger
+	level           Level
+	excludePrefixes []string
+
+	mu     sync.RWMutex
+	sink   Sink
+	closed bool
+}
+
+// NewLogger creates a Logger recording the events of the given level, except
+// the ones on keys under any of the excluded prefixes.
+func NewLogger(lg *zap.Logger, sink Sink, level Level, excludePrefixes []string) *Logger {
+	if lg == nil {
+		lg = zap.NewNop()
+	}
+	if level == "" {
+		level = DefaultLevel
+	}
+	l := &Logger{lg: lg, sink: sink, level: level}
+	for _, prefix := range excludePrefixes {
+		if prefix != "" {
+			l.excludePrefixes = append(l.excludePrefixes, prefix)
+		}
+	}
+	return l
+}
+
+// Enabled reports whether operations of the given level are recorded.
+func (l *Logger) Enabled(level Level) bool {
+	return l != nil && levelRanks[level] <= levelRanks[l.level]
+}
+
+// Log records the event, unless every key it operates on is excluded.
+func (l *Logger) Log(ev *Event) {
+	if l.excluded(ev) {
+		return
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.closed {
+		return
+	}
+	if err := l.sink.Write(ev); err != nil {
+		l.lg.Warn(
+			"failed to write audit event",
+			zap.String("operation", ev.Operation),
+			zap.Error(err),
+		)
+	}
+}
+
+// Close closes the sink. Events logged afterwards are dropped.
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+	return l.sink.Close()
+}
+
+func (l *Logger) excluded(ev *Event) bool {
+	if len(l.excludePrefixes) == 0 {
+		return false
+	}
+	if len(ev.Ops) == 0 {
+		// operations on no keys, e.g. membership changes, are always recorded
+		return ev.Key != "" && l.excludedRange(ev.Key, ev.RangeEnd)
+	}
+	for _, op := range ev.Ops {
+		if !l.excludedRange(op.Key, op.RangeEnd) {
+			return false
+		}
+	}
+	return true
+}
+
+// excludedRange reports whether the range [key, rangeEnd) lies under an excluded prefix.
+func (l *Logger) excludedRange(key, rangeEnd string) bool {
+	for _, prefix := range l.excludePrefixes {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if rangeEnd == "" {
+			return true
+		}
+		if end := prefixRangeEnd(prefix); end != "" && rangeEnd != "\x00" && rangeEnd <= end {
+			return true
+		}
+	}
+	return false
+}
+
+func prefixRangeEnd(prefix string) string {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return string(end[:i+1])
+		}
+	}
+	return ""
+}
@@ -0,0 +1,157 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+type recordingSink struct {
+	events []*Event
+}
+
+func (s *recordingSink) Write(ev *Event) error {
+	s.events = append(s.events, ev)
+	return nil
+}
+
+func (s *recordingSink) Close() error { return nil }
+
+func TestParseLevel(t *testing.T) {
+	for _, s := range []string{"admin", "write", "read"} {
+		level, err := ParseLevel(s)
+		require.NoError(t, err)
+		assert.Equal(t, Level(s), level)
+	}
+	for _, s := range []string{"", "debug", "Write"} {
+		_, err := ParseLevel(s)
+		assert.Errorf(t, err, "expected error for %q", s)
+	}
+}
+
+func TestLoggerEnabled(t *testing.T) {
+	tests := []struct {
+		level Level
+
+		wantAdmin, wantWrite, wantRead bool
+	}{
+		{level: LevelAdmin, wantAdmin: true},
+		{level: LevelWrite, wantAdmin: true, wantWrite: true},
+		{level: LevelRead, wantAdmin: true, wantWrite: true, wantRead: true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.level), func(t *testing.T) {
+			l := NewLogger(zaptest.NewLogger(t), &recordingSink{}, tt.level, nil)
+			assert.Equal(t, tt.wantAdmin, l.Enabled(LevelAdmin))
+			assert.Equal(t, tt.wantWrite, l.Enabled(LevelWrite))
+			assert.Equal(t, tt.wantRead, l.Enabled(LevelRead))
+		})
+	}
+
+	var l *Logger
+	assert.False(t, l.Enabled(LevelAdmin))
+}
+
+func TestLoggerExcludePrefixes(t *testing.T) {
+	tests := []struct {
+		name string
+		ev   *Event
+
+		wantLogged bool
+	}{
+		{
+			name: "key under excluded prefix",
+			ev:   &Event{Key: "/registry/events/a"},
+		},
+		{
+			name:       "key outside of excluded prefixes",
+			ev:         &Event{Key: "/registry/pods/a"},
+			wantLogged: true,
+		},
+		{
+			name: "range under excluded prefix",
+			ev:   &Event{Key: "/registry/events/", RangeEnd: "/registry/events0"},
+		},
+		{
+			name:       "range beyond excluded prefix",
+			ev:         &Event{Key: "/registry/events/", RangeEnd: "/registry/f"},
+			wantLogged: true,
+		},
+		{
+			name:       "range from excluded key to the end",
+			ev:         &Event{Key: "/registry/events/", RangeEnd: "\x00"},
+			wantLogged: true,
+		},
+		{
+			name: "all ops under excluded prefixes",
+			ev:   &Event{Ops: []Op{{Type: "put", Key: "/registry/events/a"}, {Type: "put", Key: "/leases/b"}}},
+		},
+		{
+			name:       "some ops outside of excluded prefixes",
+			ev:         &Event{Ops: []Op{{Type: "put", Key: "/registry/events/a"}, {Type: "put", Key: "/registry/pods/b"}}},
+			wantLogged: true,
+		},
+		{
+			name:       "no keys",
+			ev:         &Event{Target: "8e9e05c52164694d"},
+			wantLogged: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &recordingSink{}
+			l := NewLogger(zaptest.NewLogger(t), sink, LevelRead, []string{"/registry/events/", "/leases/", ""})
+			l.Log(tt.ev)
+			assert.Equal(t, tt.wantLogged, len(sink.events) == 1)
+		})
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	l := NewLogger(zaptest.NewLogger(t), NewFileSink(path, 1, 0), LevelWrite, nil)
+	l.Log(&Event{User: "alice", Operation: "Put", Key: "foo", Revision: 2, Result: ResultSuccess})
+	l.Log(&Event{User: "bob", Operation: "DeleteRange", Key: "foo", RangeEnd: "fop", Result: ResultFailure, Error: "etcdserver: permission denied"})
+	require.NoError(t, l.Close())
+	// events after close are dropped
+	l.Log(&Event{User: "alice", Operation: "Put", Key: "bar", Result: ResultSuccess})
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var ev Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &ev))
+		assert.False(t, ev.Time.IsZero())
+		events = append(events, ev)
+	}
+	require.NoError(t, scanner.Err())
+	require.Len(t, events, 2)
+	assert.Equal(t, "alice", events[0].User)
+	assert.Equal(t, int64(2), events[0].Revision)
+	assert.Equal(t, ResultSuccess, events[0].Result)
+	assert.Equal(t, "fop", events[1].RangeEnd)
+	assert.Equal(t, "etcdserver: permission denied", events[1].Error)
+}
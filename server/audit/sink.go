@@ -0,0 +1,62 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// jsonSink writes each event as a line of JSON.
+type jsonSink struct {
+	mu sync.Mutex
+	w  io.WriteCloser
+}
+
+// NewJSONSink creates a sink writing each event as a line of JSON to w.
+func NewJSONSink(w io.WriteCloser) Sink {
+	return &jsonSink{w: w}
+}
+
+// NewFileSink creates a sink writing JSON lines to the file at path. The file
+// is rotated once it grows beyond maxSizeMB megabytes, and at most maxBackups
+// rotated files are retained, or all of them if maxBackups is 0.
+func NewFileSink(path string, maxSizeMB, maxBackups int) Sink {
+	return NewJSONSink(&lumberjack.Logger{
+		Filename:   path,
+		MaxSize:    maxSizeMB,
+		MaxBackups: maxBackups,
+	})
+}
+
+func (s *jsonSink) Write(ev *Event) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(data)
+	return err
+}
+
+func (s *jsonSink) Close() error {
+	return s.w.Close()
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"sort"
+
+	"go.uber.org/zap"
+)
+
+const (
+	authenticatorTypeJWT  = "jwt"
+	authenticatorTypeLDAP = "ldap"
+)
+
+// Identity is a user verified by an Authenticator.
+type Identity struct {
+	// Name is the name of the user in etcd.
+	Name string
+	// Roles are the etcd roles granted to the user.
+	Roles []string
+}
+
+// Authenticator verifies credentials of users which are not stored in etcd,
+// e.g. a JWT issued by an identity provider or a bind against a directory.
+//
+// Users verified by an Authenticator are kept in memory until their tokens are
+// not used for the token TTL or the member restarts, so they must authenticate
+// again after that.
+type Authenticator interface {
+	// Authenticate verifies the given credentials and returns the identity
+	// they belong to. The username may be empty if the credentials identify
+	// the user by themselves.
+	Authenticate(ctx context.Context, username, password string) (*Identity, error)
+}
+
+// NewAuthenticator creates a new authenticator from its options, e.g.
+// "jwt,jwks-file=/path/to/jwks.json,issuer=https://issuer.example.com" or
+// "ldap,directory-file=/path/to/directory.json". It returns nil if opts is empty.
+func NewAuthenticator(lg *zap.Logger, opts string) (Authenticator, error) {
+	if opts == "" {
+		return nil, nil
+	}
+	if lg == nil {
+		lg = zap.NewNop()
+	}
+	authenticatorType, typeSpecificOpts, err := decomposeOpts(lg, opts)
+	if err != nil {
+		return nil, ErrInvalidAuthOpts
+	}
+
+	switch authenticatorType {
+	case authenticatorTypeJWT:
+		return newJWTAuthenticator(lg, typeSpecificOpts)
+
+	case authenticatorTypeLDAP:
+		return newLDAPAuthenticatorFromOpts(lg, typeSpecificOpts)
+
+	default:
+		lg.Warn(
+			"unknown authenticator type",
+			zap.String("type", authenticatorType),
+			zap.Error(ErrInvalidAuthOpts),
+		)
+		return nil, ErrInvalidAuthOpts
+	}
+}
+
+// normalizeRoles returns the sorted roles without duplicates, as the roles of a user are stored.
+func normalizeRoles(roles []string) []string {
+	sorted := make([]string, 0, len(roles))
+	seen := make(map[string]struct{}, len(roles))
+	for _, role := range roles {
+		if _, ok := seen[role]; ok || role == "" {
+			continue
+		}
+		seen[role] = struct{}{}
+		sorted = append(sorted, role)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
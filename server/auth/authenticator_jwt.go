@@ -0,0 +1,271 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+	"go.uber.org/zap"
+)
+
+const (
+	optJWKSFile      = "jwks-file"
+	optIssuer        = "issuer"
+	optAudience      = "audience"
+	optUsernameClaim = "username-claim"
+	optRolesClaim    = "roles-claim"
+
+	defaultUsernameClaim = "sub"
+	defaultRolesClaim    = "roles"
+)
+
+// jwtValidMethods are the signing methods accepted from identity providers.
+// Symmetric methods are not accepted since the keys are public.
+var jwtValidMethods = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+}
+
+// jwtAuthenticator verifies JWTs issued by an identity provider, e.g. OIDC ID tokens.
+// The token is passed as the password; the username, if given, must match its username claim.
+type jwtAuthenticator struct {
+	lg            *zap.Logger
+	keys          map[string]interface{} // kid -> public key
+	issuer        string
+	audience      string
+	usernameClaim string
+	rolesClaim    string
+}
+
+func newJWTAuthenticator(lg *zap.Logger, optMap map[string]string) (*jwtAuthenticator, error) {
+	for k := range optMap {
+		switch k {
+		case optJWKSFile, optIssuer, optAudience, optUsernameClaim, optRolesClaim:
+		default:
+			return nil, fmt.Errorf("unknown jwt authenticator option: %s", k)
+		}
+	}
+	if optMap[optJWKSFile] == "" {
+		return nil, ErrMissingKey
+	}
+	if optMap[optIssuer] == "" {
+		return nil, fmt.Errorf("jwt authenticator option %s is required", optIssuer)
+	}
+
+	data, err := os.ReadFile(optMap[optJWKSFile])
+	if err != nil {
+		return nil, err
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &jwtAuthenticator{
+		lg:            lg,
+		keys:          keys,
+		issuer:        optMap[optIssuer],
+		audience:      optMap[optAudience],
+		usernameClaim: optMap[optUsernameClaim],
+		rolesClaim:    optMap[optRolesClaim],
+	}
+	if a.usernameClaim == "" {
+		a.usernameClaim = defaultUsernameClaim
+	}
+	if a.rolesClaim == "" {
+		a.rolesClaim = defaultRolesClaim
+	}
+	lg.Info(
+		"created jwt authenticator",
+		zap.String("issuer", a.issuer),
+		zap.Int("keys", len(a.keys)),
+	)
+	return a, nil
+}
+
+func (a *jwtAuthenticator) Authenticate(ctx context.Context, username, password string) (*Identity, error) {
+	parser := jwt.NewParser(jwt.WithValidMethods(jwtValidMethods))
+	claims := jwt.MapClaims{}
+	if _, err := parser.ParseWithClaims(password, claims, a.key); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	if !claims.VerifyExpiresAt(now, true) {
+		return nil, fmt.Errorf("token has no valid %q claim", "exp")
+	}
+	if !claims.VerifyIssuer(a.issuer, true) {
+		return nil, fmt.Errorf("token is not issued by %q", a.issuer)
+	}
+	if a.audience != "" && !claims.VerifyAudience(a.audience, true) {
+		return nil, fmt.Errorf("token is not issued for %q", a.audience)
+	}
+
+	name, ok := claims[a.usernameClaim].(string)
+	if !ok || name == "" {
+		return nil, fmt.Errorf("token has no %q claim", a.usernameClaim)
+	}
+	if username != "" && username != name {
+		return nil, fmt.Errorf("token is issued for %q", name)
+	}
+
+	var roles []string
+	switch v := claims[a.rolesClaim].(type) {
+	case string:
+		roles = strings.Fields(v)
+	case []interface{}:
+		for _, role := range v {
+			if s, ok := role.(string); ok {
+				roles = append(roles, s)
+			}
+		}
+	}
+	return &Identity{Name: name, Roles: normalizeRoles(roles)}, nil
+}
+
+func (a *jwtAuthenticator) key(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if key, ok := a.keys[kid]; ok {
+		return key, nil
+	}
+	if kid == "" && len(a.keys) == 1 {
+		for _, key := range a.keys {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown key id %q", kid)
+}
+
+// jsonWebKey is a public key of a JSON Web Key Set (RFC 7517).
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJWKS parses the signing keys of a JSON Web Key Set.
+func parseJWKS(data []byte) (map[string]interface{}, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %w", k.Kid, err)
+		}
+		if _, ok := keys[k.Kid]; ok {
+			return nil, fmt.Errorf("duplicate key id %q", k.Kid)
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, ErrMissingKey
+	}
+	return keys, nil
+}
+
+func (k *jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() {
+			return nil, fmt.Errorf("invalid exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("point is not on curve %q", k.Crv)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid key size")
+		}
+		return ed25519.PublicKey(x), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
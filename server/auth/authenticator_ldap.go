@@ -0,0 +1,195 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	optDirectoryFile = "directory-file"
+)
+
+var (
+	ErrDirectoryEntryNotFound = errors.New("auth: directory entry not found")
+	ErrDirectoryInvalidBind   = errors.New("auth: invalid directory credentials")
+)
+
+// Directory is the part of an LDAP directory used to authenticate users:
+// the entry of a user is searched by its uid and bound with the password,
+// and the groups it is a member of are mapped to etcd roles.
+type Directory interface {
+	// Search returns the distinguished name of the user entry with the given uid.
+	Search(ctx context.Context, uid string) (string, error)
+	// Bind verifies the password of the entry with the given distinguished name.
+	Bind(ctx context.Context, dn, password string) error
+	// MemberOf returns the distinguished names of the groups of the given entry.
+	MemberOf(ctx context.Context, dn string) ([]string, error)
+}
+
+// ldapAuthenticator authenticates users with a search and bind against a directory.
+// The common name of each group of a user is granted as an etcd role.
+type ldapAuthenticator struct {
+	lg  *zap.Logger
+	dir Directory
+}
+
+// NewLDAPAuthenticator creates an authenticator which binds users against the given directory.
+func NewLDAPAuthenticator(lg *zap.Logger, dir Directory) Authenticator {
+	if lg == nil {
+		lg = zap.NewNop()
+	}
+	return &ldapAuthenticator{lg: lg, dir: dir}
+}
+
+func newLDAPAuthenticatorFromOpts(lg *zap.Logger, optMap map[string]string) (Authenticator, error) {
+	for k := range optMap {
+		if k != optDirectoryFile {
+			return nil, fmt.Errorf("unknown ldap authenticator option: %s", k)
+		}
+	}
+	if optMap[optDirectoryFile] == "" {
+		return nil, fmt.Errorf("ldap authenticator option %s is required", optDirectoryFile)
+	}
+	dir, err := NewFileDirectory(optMap[optDirectoryFile])
+	if err != nil {
+		return nil, err
+	}
+	lg.Info(
+		"created ldap authenticator",
+		zap.String("directory-file", optMap[optDirectoryFile]),
+	)
+	return NewLDAPAuthenticator(lg, dir), nil
+}
+
+func (a *ldapAuthenticator) Authenticate(ctx context.Context, username, password string) (*Identity, error) {
+	// a bind without a password is an unauthenticated bind, which always succeeds
+	if username == "" || password == "" {
+		return nil, ErrDirectoryInvalidBind
+	}
+	dn, err := a.dir.Search(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if err = a.dir.Bind(ctx, dn, password); err != nil {
+		return nil, err
+	}
+	groups, err := a.dir.MemberOf(ctx, dn)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]string, 0, len(groups))
+	for _, group := range groups {
+		roles = append(roles, commonName(group))
+	}
+	return &Identity{Name: username, Roles: normalizeRoles(roles)}, nil
+}
+
+// commonName returns the value of the leading "cn" attribute of a distinguished name,
+// or the distinguished name itself if it does not start with one.
+func commonName(dn string) string {
+	rdn := strings.SplitN(dn, ",", 2)[0]
+	if kv := strings.SplitN(rdn, "=", 2); len(kv) == 2 && strings.EqualFold(strings.TrimSpace(kv[0]), "cn") {
+		return strings.TrimSpace(kv[1])
+	}
+	return dn
+}
+
+// directoryEntry is a user entry of a file directory.
+type directoryEntry struct {
+	DN       string   `json:"dn"`
+	UID      string   `json:"uid"`
+	Password string   `json:"password"` // bcrypt hash
+	MemberOf []string `json:"member_of"`
+}
+
+// fileDirectory is a directory loaded from a JSON file. It stands in for an
+// LDAP server in tests and small deployments.
+type fileDirectory struct {
+	byUID map[string]*directoryEntry
+	byDN  map[string]*directoryEntry
+}
+
+// NewFileDirectory loads a directory from a JSON file of the form
+//
+//	{"entries": [{"dn": "uid=alice,ou=people,dc=example,dc=org", "uid": "alice",
+//	  "password": "<bcrypt hash>", "member_of": ["cn=admin,ou=groups,dc=example,dc=org"]}]}
+func NewFileDirectory(path string) (Directory, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file struct {
+		Entries []*directoryEntry `json:"entries"`
+	}
+	if err = json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	d := &fileDirectory{
+		byUID: make(map[string]*directoryEntry, len(file.Entries)),
+		byDN:  make(map[string]*directoryEntry, len(file.Entries)),
+	}
+	for _, e := range file.Entries {
+		if e.DN == "" || e.UID == "" {
+			return nil, fmt.Errorf("directory entry without dn or uid")
+		}
+		if _, ok := d.byUID[e.UID]; ok {
+			return nil, fmt.Errorf("duplicate directory entry uid %q", e.UID)
+		}
+		if _, ok := d.byDN[e.DN]; ok {
+			return nil, fmt.Errorf("duplicate directory entry dn %q", e.DN)
+		}
+		d.byUID[e.UID] = e
+		d.byDN[e.DN] = e
+	}
+	return d, nil
+}
+
+func (d *fileDirectory) Search(ctx context.Context, uid string) (string, error) {
+	e, ok := d.byUID[uid]
+	if !ok {
+		return "", ErrDirectoryEntryNotFound
+	}
+	return e.DN, nil
+}
+
+func (d *fileDirectory) Bind(ctx context.Context, dn, password string) error {
+	e, ok := d.byDN[dn]
+	if !ok || e.Password == "" {
+		return ErrDirectoryInvalidBind
+	}
+	if bcrypt.CompareHashAndPassword([]byte(e.Password), []byte(password)) != nil {
+		return ErrDirectoryInvalidBind
+	}
+	return nil
+}
+
+func (d *fileDirectory) MemberOf(ctx context.Context, dn string) ([]string, error) {
+	e, ok := d.byDN[dn]
+	if !ok {
+		return nil, ErrDirectoryEntryNotFound
+	}
+	return e.MemberOf, nil
+}
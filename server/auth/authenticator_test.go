@@ -0,0 +1,312 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"golang.org/x/crypto/bcrypt"
+
+	"go.etcd.io/etcd/api/v3/authpb"
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+)
+
+const testIssuer = "https://issuer.example.com"
+
+// newTestJWTAuthenticator writes a JWKS with a new RSA key and returns the key and an authenticator using it.
+func newTestJWTAuthenticator(t *testing.T) (*rsa.PrivateKey, Authenticator) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	jwks, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "test-key",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	})
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, jwks, 0600))
+
+	a, err := NewAuthenticator(zaptest.NewLogger(t), "jwt,jwks-file="+path+",issuer="+testIssuer+",audience=etcd")
+	require.NoError(t, err)
+	return key, a
+}
+
+func signTestJWT(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	key, a := newTestJWTAuthenticator(t)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	exp := time.Now().Add(time.Hour).Unix()
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{"iss": testIssuer, "aud": "etcd", "sub": "alice", "exp": exp, "roles": []string{"writer", "reader", "writer"}}
+	}
+	tests := []struct {
+		name     string
+		username string
+		token    string
+
+		wantIdentity *Identity
+	}{
+		{
+			name:         "valid token",
+			token:        signTestJWT(t, key, "test-key", validClaims()),
+			wantIdentity: &Identity{Name: "alice", Roles: []string{"reader", "writer"}},
+		},
+		{
+			name:         "valid token with matching username",
+			username:     "alice",
+			token:        signTestJWT(t, key, "test-key", validClaims()),
+			wantIdentity: &Identity{Name: "alice", Roles: []string{"reader", "writer"}},
+		},
+		{
+			name:     "username mismatch",
+			username: "bob",
+			token:    signTestJWT(t, key, "test-key", validClaims()),
+		},
+		{
+			name:  "unknown key id",
+			token: signTestJWT(t, key, "other-key", validClaims()),
+		},
+		{
+			name:  "invalid signature",
+			token: signTestJWT(t, otherKey, "test-key", validClaims()),
+		},
+		{
+			name: "wrong issuer",
+			token: func() string {
+				c := validClaims()
+				c["iss"] = "https://other.example.com"
+				return signTestJWT(t, key, "test-key", c)
+			}(),
+		},
+		{
+			name: "wrong audience",
+			token: func() string {
+				c := validClaims()
+				c["aud"] = "other"
+				return signTestJWT(t, key, "test-key", c)
+			}(),
+		},
+		{
+			name: "expired",
+			token: func() string {
+				c := validClaims()
+				c["exp"] = time.Now().Add(-time.Minute).Unix()
+				return signTestJWT(t, key, "test-key", c)
+			}(),
+		},
+		{
+			name: "no expiry",
+			token: func() string {
+				c := validClaims()
+				delete(c, "exp")
+				return signTestJWT(t, key, "test-key", c)
+			}(),
+		},
+		{
+			name:  "not a token",
+			token: "password",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := a.Authenticate(context.Background(), tt.username, tt.token)
+			if tt.wantIdentity == nil {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantIdentity, id)
+		})
+	}
+}
+
+func TestNewAuthenticatorInvalidOpts(t *testing.T) {
+	for _, opts := range []string{
+		"unknown",
+		"jwt",
+		"jwt,issuer=" + testIssuer,
+		"jwt,jwks-file=/nonexistent,issuer=" + testIssuer,
+		"ldap",
+		"ldap,directory-file=/nonexistent",
+		"ldap,directory-file=a,unknown=b",
+	} {
+		_, err := NewAuthenticator(zaptest.NewLogger(t), opts)
+		assert.Errorf(t, err, "expected error for %q", opts)
+	}
+
+	a, err := NewAuthenticator(zaptest.NewLogger(t), "")
+	require.NoError(t, err)
+	assert.Nil(t, a)
+}
+
+func newTestLDAPAuthenticator(t *testing.T) Authenticator {
+	hashed, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	require.NoError(t, err)
+	directory, err := json.Marshal(map[string]interface{}{
+		"entries": []map[string]interface{}{
+			{
+				"dn":        "uid=alice,ou=people,dc=example,dc=org",
+				"uid":       "alice",
+				"password":  string(hashed),
+				"member_of": []string{"cn=role-test,ou=groups,dc=example,dc=org", "cn=reader,ou=groups,dc=example,dc=org"},
+			},
+			{
+				"dn":  "uid=bob,ou=people,dc=example,dc=org",
+				"uid": "bob",
+			},
+		},
+	})
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "directory.json")
+	require.NoError(t, os.WriteFile(path, directory, 0600))
+
+	a, err := NewAuthenticator(zaptest.NewLogger(t), "ldap,directory-file="+path)
+	require.NoError(t, err)
+	return a
+}
+
+func TestLDAPAuthenticator(t *testing.T) {
+	a := newTestLDAPAuthenticator(t)
+
+	id, err := a.Authenticate(context.Background(), "alice", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, &Identity{Name: "alice", Roles: []string{"reader", "role-test"}}, id)
+
+	_, err = a.Authenticate(context.Background(), "alice", "wrong")
+	assert.Equal(t, ErrDirectoryInvalidBind, err)
+	_, err = a.Authenticate(context.Background(), "alice", "")
+	assert.Equal(t, ErrDirectoryInvalidBind, err)
+	_, err = a.Authenticate(context.Background(), "bob", "secret")
+	assert.Equal(t, ErrDirectoryInvalidBind, err)
+	_, err = a.Authenticate(context.Background(), "carol", "secret")
+	assert.Equal(t, ErrDirectoryEntryNotFound, err)
+}
+
+func TestCheckCredentialsWithAuthenticator(t *testing.T) {
+	as, tearDown := setupAuthStore(t)
+	defer tearDown(t)
+	as.SetAuthenticator(newTestLDAPAuthenticator(t))
+
+	perm := &authpb.Permission{PermType: authpb.READWRITE, Key: []byte("foo"), RangeEnd: []byte("fop")}
+	_, err := as.RoleGrantPermission(&pb.AuthRoleGrantPermissionRequest{Name: "role-test", Perm: perm})
+	require.NoError(t, err)
+
+	// local users are checked with their passwords
+	_, id, err := as.CheckCredentials(context.Background(), "foo", "bar")
+	require.NoError(t, err)
+	assert.Nil(t, id)
+	_, _, err = as.CheckCredentials(context.Background(), "foo", "secret")
+	assert.Equal(t, ErrAuthFailed, err)
+
+	_, _, err = as.CheckCredentials(context.Background(), "alice", "wrong")
+	assert.Equal(t, ErrAuthFailed, err)
+	rev, id, err := as.CheckCredentials(context.Background(), "alice", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, as.Revision(), rev)
+	assert.Equal(t, &Identity{Name: "alice", Roles: []string{"reader", "role-test"}}, id)
+
+	// the identity is unknown until it is authenticated
+	ai := &AuthInfo{Username: "alice", Revision: as.Revision()}
+	assert.False(t, as.validateIdentity(ai))
+	assert.Equal(t, ErrPermissionDenied, as.isOpPermitted(ai, []byte("foo"), nil, authpb.READ))
+
+	ctx := context.WithValue(context.WithValue(context.TODO(), AuthenticateParamIndex{}, uint64(1)), AuthenticateParamSimpleTokenPrefix{}, "dummy")
+	_, err = as.Authenticate(context.WithValue(ctx, AuthenticateParamIdentity{}, id), "alice", "")
+	require.NoError(t, err)
+	require.True(t, as.validateIdentity(ai))
+	assert.Equal(t, &AuthInfo{Username: "alice", Revision: as.Revision(), External: true, Roles: []string{"reader", "role-test"}}, ai)
+	assert.NoError(t, as.isOpPermitted(ai, []byte("foo"), nil, authpb.READ))
+	assert.Equal(t, ErrPermissionDenied, as.isOpPermitted(ai, []byte("bar"), nil, authpb.READ))
+	assert.Equal(t, []string{"reader", "role-test"}, as.UserRoles(ai))
+	assert.Equal(t, ErrPermissionDenied, as.IsAdminPermitted(ai))
+
+	// the permissions are checked against the roles of the request, whether the member
+	// knows the identity or not
+	assert.NoError(t, as.isOpPermitted(&AuthInfo{Username: "bob", Revision: as.Revision(), External: true, Roles: []string{"role-test"}}, []byte("foo"), nil, authpb.READ))
+	assert.Equal(t, ErrPermissionDenied, as.isOpPermitted(&AuthInfo{Username: "bob", Revision: as.Revision()}, []byte("foo"), nil, authpb.READ))
+
+	// changes of roles are applied to the identity
+	_, err = as.RoleGrantPermission(&pb.AuthRoleGrantPermissionRequest{Name: "role-test", Perm: &authpb.Permission{PermType: authpb.READ, Key: []byte("bar")}})
+	require.NoError(t, err)
+	ai.Revision = as.Revision()
+	assert.NoError(t, as.isOpPermitted(ai, []byte("bar"), nil, authpb.READ))
+
+	// a local user takes precedence over the identity
+	_, err = as.UserAdd(&pb.AuthUserAddRequest{Name: "alice", HashedPassword: encodePassword("local"), Options: &authpb.UserAddOptions{NoPassword: false}})
+	require.NoError(t, err)
+	ai.Revision = as.Revision()
+	assert.Equal(t, ErrPermissionDenied, as.isOpPermitted(ai, []byte("foo"), nil, authpb.READ))
+	local := &AuthInfo{Username: "alice", Revision: as.Revision()}
+	assert.True(t, as.validateIdentity(local))
+	assert.False(t, local.External)
+	_, _, err = as.CheckCredentials(context.Background(), "alice", "secret")
+	assert.Equal(t, ErrAuthFailed, err)
+	_, err = as.Authenticate(context.WithValue(ctx, AuthenticateParamIdentity{}, id), "alice", "")
+	assert.Equal(t, ErrAuthFailed, err)
+}
+
+func TestIdentityExpires(t *testing.T) {
+	as, tearDown := setupAuthStore(t)
+	defer tearDown(t)
+	as.SetAuthenticator(newTestLDAPAuthenticator(t))
+
+	ctx := context.WithValue(context.WithValue(context.TODO(), AuthenticateParamIndex{}, uint64(1)), AuthenticateParamSimpleTokenPrefix{}, "dummy")
+	_, err := as.Authenticate(context.WithValue(ctx, AuthenticateParamIdentity{}, &Identity{Name: "alice", Roles: []string{"role-test"}}), "alice", "")
+	require.NoError(t, err)
+	assert.True(t, as.validateIdentity(&AuthInfo{Username: "alice"}))
+
+	// an expired identity is rejected, then dropped once the identities are swept
+	as.identitiesMu.Lock()
+	as.identities["alice"].expires = time.Now().Add(-time.Second)
+	as.identitiesMu.Unlock()
+	assert.False(t, as.validateIdentity(&AuthInfo{Username: "alice"}))
+	ctx = context.WithValue(ctx, AuthenticateParamIndex{}, uint64(2))
+	_, err = as.Authenticate(context.WithValue(ctx, AuthenticateParamIdentity{}, &Identity{Name: "bob", Roles: []string{"role-test"}}), "bob", "")
+	require.NoError(t, err)
+	as.identitiesMu.Lock()
+	as.identities["bob"].expires = time.Now().Add(-time.Second)
+	as.identitiesSwept = time.Time{}
+	as.identitiesMu.Unlock()
+	assert.False(t, as.validateIdentity(&AuthInfo{Username: "alice"}))
+	as.identitiesMu.Lock()
+	assert.Empty(t, as.identities)
+	as.identitiesMu.Unlock()
+}
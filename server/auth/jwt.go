@@ -37,6 +37,7 @@ func (t *tokenJWT) enable()                         {}
 func (t *tokenJWT) disable()                        {}
 func (t *tokenJWT) invalidateUser(string)           {}
 func (t *tokenJWT) genTokenPrefix() (string, error) { return "", nil }
+func (t *tokenJWT) tokenTTL() time.Duration         { return t.ttl }
 
 func (t *tokenJWT) info(ctx context.Context, token string, rev uint64) (*AuthInfo, bool) {
 	// rev isn't used in JWT, it is only used in simple token
@@ -0,0 +1,144 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"bytes"
+	"path"
+	"strings"
+)
+
+// keyPattern is the glob pattern of a permission, matched with path.Match.
+type keyPattern struct {
+	pattern string
+	// prefix is set if the pattern matches every key beginning with a match
+	prefix bool
+}
+
+// match reports whether the key is matched by the pattern.
+func (p keyPattern) match(key string) bool {
+	if ok, _ := path.Match(p.pattern, key); ok || !p.prefix {
+		return ok
+	}
+	// A match contains at most as many '/' as the pattern has separators and
+	// character classes, so longer prefixes of the key don't need to be tried.
+	maxSlashes := strings.Count(p.pattern, "/") + strings.Count(p.pattern, "[")
+	slashes := 0
+	for i := 0; i < len(key); i++ {
+		if ok, _ := path.Match(p.pattern, key[:i]); ok {
+			return true
+		}
+		if key[i] == '/' {
+			if slashes++; slashes > maxSlashes {
+				return false
+			}
+		}
+	}
+	return false
+}
+
+// mayMatchPrefix reports whether a key beginning with the given prefix may be
+// matched by the pattern. It errs on the side of true when it can't tell.
+func (p keyPattern) mayMatchPrefix(prefix string) bool {
+	if p.prefix && p.match(prefix) {
+		return true
+	}
+	if strings.ContainsAny(p.pattern, `[\`) {
+		return true
+	}
+
+	// '*' and '?' never match '/', so the segments of the prefix must be
+	// matched by the segments of the pattern one by one.
+	patternSegs := strings.Split(p.pattern, "/")
+	prefixSegs := strings.Split(prefix, "/")
+	if len(prefixSegs) > len(patternSegs) {
+		return false
+	}
+	last := len(prefixSegs) - 1
+	for i := 0; i < last; i++ {
+		if ok, _ := path.Match(patternSegs[i], prefixSegs[i]); !ok {
+			return false
+		}
+	}
+	seg, lit := patternSegs[last], literalPrefix(patternSegs[last])
+	if seg == lit {
+		return strings.HasPrefix(seg, prefixSegs[last])
+	}
+	return strings.HasPrefix(lit, prefixSegs[last]) || strings.HasPrefix(prefixSegs[last], lit)
+}
+
+// literalPrefix returns the part of the pattern before its first special character.
+func literalPrefix(pattern string) string {
+	if i := strings.IndexAny(pattern, `*?[\`); i >= 0 {
+		return pattern[:i]
+	}
+	return pattern
+}
+
+// matchKey reports whether the key is matched by any of the patterns.
+func matchKey(patterns []keyPattern, key []byte) bool {
+	for _, p := range patterns {
+		if p.match(string(key)) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPrefixRange reports whether every key of [key, rangeEnd) is matched by any
+// of the patterns, which is only known for the range of the keys prefixed by key.
+func matchPrefixRange(patterns []keyPattern, key, rangeEnd []byte) bool {
+	if len(key) == 0 || !bytes.Equal(rangeEnd, prefixRangeEnd(key)) {
+		return false
+	}
+	for _, p := range patterns {
+		if p.prefix && p.match(string(key)) {
+			return true
+		}
+	}
+	return false
+}
+
+// mayMatchRange reports whether any key of [key, rangeEnd) may be matched by any of
+// the patterns. A nil rangeEnd stands for all the keys greater than or equal to key.
+func mayMatchRange(patterns []keyPattern, key, rangeEnd []byte) bool {
+	for _, p := range patterns {
+		// every match begins with the literal prefix of the pattern
+		lit := []byte(literalPrefix(p.pattern))
+		if litEnd := prefixRangeEnd(lit); len(lit) != 0 &&
+			((rangeEnd != nil && bytes.Compare(rangeEnd, lit) <= 0) || (litEnd != nil && bytes.Compare(key, litEnd) >= 0)) {
+			continue
+		}
+		if len(key) != 0 && bytes.Equal(rangeEnd, prefixRangeEnd(key)) && !p.mayMatchPrefix(string(key)) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// prefixRangeEnd returns the end of the range of the keys prefixed by key,
+// or nil if there is no such end.
+func prefixRangeEnd(key []byte) []byte {
+	end := make([]byte, len(key))
+	copy(end, key)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"testing"
+)
+
+func TestKeyPatternMatch(t *testing.T) {
+	tests := []struct {
+		pattern keyPattern
+		key     string
+		want    bool
+	}{
+		{keyPattern{pattern: "/tenants/*/secrets/"}, "/tenants/a/secrets/", true},
+		{keyPattern{pattern: "/tenants/*/secrets/"}, "/tenants/a/secrets/x", false},
+		{keyPattern{pattern: "/tenants/*/secrets/"}, "/tenants/a/b/secrets/", false},
+		{keyPattern{pattern: "/tenants/*/secrets/", prefix: true}, "/tenants/a/secrets/x", true},
+		{keyPattern{pattern: "/tenants/*/secrets/", prefix: true}, "/tenants/a/secrets/x/y", true},
+		{keyPattern{pattern: "/tenants/*/secrets/", prefix: true}, "/tenants/a/config/x", false},
+		{keyPattern{pattern: "/tenants/*/secrets/", prefix: true}, "/tenants/a/b/secrets/x", false},
+		{keyPattern{pattern: "/tenants/?/", prefix: true}, "/tenants/a/x", true},
+		{keyPattern{pattern: "/tenants/?/", prefix: true}, "/tenants/ab/x", false},
+	}
+	for i, tt := range tests {
+		if got := tt.pattern.match(tt.key); got != tt.want {
+			t.Errorf("#%d: %+v.match(%q)=%t, want=%t", i, tt.pattern, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestKeyPatternMayMatchRange(t *testing.T) {
+	tests := []struct {
+		pattern  keyPattern
+		key      string
+		rangeEnd string
+		want     bool
+	}{
+		// prefix ranges
+		{keyPattern{pattern: "/tenants/*/secrets/"}, "/tenants/a/", "/tenants/a0", true},
+		{keyPattern{pattern: "/tenants/*/secrets/"}, "/tenants/a/sec", "/tenants/a/sed", true},
+		{keyPattern{pattern: "/tenants/*/secrets/"}, "/tenants/a/config/", "/tenants/a/config0", false},
+		{keyPattern{pattern: "/tenants/*/secrets/"}, "/tenants/a/secrets/x", "/tenants/a/secrets/y", false},
+		{keyPattern{pattern: "/tenants/*/secrets/", prefix: true}, "/tenants/a/secrets/x", "/tenants/a/secrets/y", true},
+		{keyPattern{pattern: "/tenants/*/secrets/"}, "/", "0", true},
+		// other ranges
+		{keyPattern{pattern: "/tenants/*/secrets/"}, "/a", "/b", false},
+		{keyPattern{pattern: "/tenants/*/secrets/"}, "/u", "", false},
+		{keyPattern{pattern: "/tenants/*/secrets/"}, "/a", "/u", true},
+	}
+	for i, tt := range tests {
+		var rangeEnd []byte
+		if tt.rangeEnd != "" {
+			rangeEnd = []byte(tt.rangeEnd)
+		}
+		if got := mayMatchRange([]keyPattern{tt.pattern}, []byte(tt.key), rangeEnd); got != tt.want {
+			t.Errorf("#%d: mayMatchRange(%+v, %q, %q)=%t, want=%t", i, tt.pattern, tt.key, tt.rangeEnd, got, tt.want)
+		}
+	}
+}
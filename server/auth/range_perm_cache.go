@@ -15,6 +15,9 @@
 package auth
 
 import (
+	"strings"
+	"time"
+
 	"go.uber.org/zap"
 
 	"go.etcd.io/etcd/api/v3/authpb"
@@ -26,17 +29,47 @@ func getMergedPerms(tx AuthReadTx, userName string) *unifiedRangePermissions {
 	if user == nil {
 		return nil
 	}
+	return getRolesMergedPerms(tx, user.Roles)
+}
 
-	readPerms := adt.NewIntervalTree()
-	writePerms := adt.NewIntervalTree()
+func getRolesMergedPerms(tx AuthReadTx, roles []string) *unifiedRangePermissions {
+	perms := &unifiedRangePermissions{
+		readPerms:      adt.NewIntervalTree(),
+		writePerms:     adt.NewIntervalTree(),
+		readDenyPerms:  adt.NewIntervalTree(),
+		writeDenyPerms: adt.NewIntervalTree(),
+	}
 
-	for _, roleName := range user.Roles {
+	for _, roleName := range roles {
 		role := tx.UnsafeGetRole(roleName)
 		if role == nil {
 			continue
 		}
 
 		for _, perm := range role.KeyPermission {
+			readPerms, writePerms := perms.readPerms, perms.writePerms
+			readPatterns, writePatterns := &perms.readPatterns, &perms.writePatterns
+			if perm.Deny {
+				readPerms, writePerms = perms.readDenyPerms, perms.writeDenyPerms
+				readPatterns, writePatterns = &perms.readDenyPatterns, &perms.writeDenyPatterns
+			}
+
+			if perm.Pattern {
+				p := keyPattern{pattern: string(perm.Key), prefix: len(perm.RangeEnd) != 0}
+				switch perm.PermType {
+				case authpb.READWRITE:
+					*readPatterns = append(*readPatterns, p)
+					*writePatterns = append(*writePatterns, p)
+
+				case authpb.READ:
+					*readPatterns = append(*readPatterns, p)
+
+				case authpb.WRITE:
+					*writePatterns = append(*writePatterns, p)
+				}
+				continue
+			}
+
 			var ivl adt.Interval
 			var rangeEnd []byte
 
@@ -64,10 +97,7 @@ func getMergedPerms(tx AuthReadTx, userName string) *unifiedRangePermissions {
 		}
 	}
 
-	return &unifiedRangePermissions{
-		readPerms:  readPerms,
-		writePerms: writePerms,
-	}
+	return perms
 }
 
 func checkKeyInterval(
@@ -82,9 +112,11 @@ func checkKeyInterval(
 	ivl := adt.NewBytesAffineInterval(key, rangeEnd)
 	switch permtyp {
 	case authpb.READ:
-		return cachedPerms.readPerms.Contains(ivl)
+		return (cachedPerms.readPerms.Contains(ivl) || matchPrefixRange(cachedPerms.readPatterns, key, rangeEnd)) &&
+			!intersects(cachedPerms.readDenyPerms, ivl) && !mayMatchRange(cachedPerms.readDenyPatterns, key, rangeEnd)
 	case authpb.WRITE:
-		return cachedPerms.writePerms.Contains(ivl)
+		return (cachedPerms.writePerms.Contains(ivl) || matchPrefixRange(cachedPerms.writePatterns, key, rangeEnd)) &&
+			!intersects(cachedPerms.writeDenyPerms, ivl) && !mayMatchRange(cachedPerms.writeDenyPatterns, key, rangeEnd)
 	default:
 		lg.Panic("unknown auth type", zap.String("auth-type", permtyp.String()))
 	}
@@ -95,25 +127,33 @@ func checkKeyPoint(lg *zap.Logger, cachedPerms *unifiedRangePermissions, key []b
 	pt := adt.NewBytesAffinePoint(key)
 	switch permtyp {
 	case authpb.READ:
-		return cachedPerms.readPerms.Intersects(pt)
+		return (cachedPerms.readPerms.Intersects(pt) || matchKey(cachedPerms.readPatterns, key)) &&
+			!intersects(cachedPerms.readDenyPerms, pt) && !matchKey(cachedPerms.readDenyPatterns, key)
 	case authpb.WRITE:
-		return cachedPerms.writePerms.Intersects(pt)
+		return (cachedPerms.writePerms.Intersects(pt) || matchKey(cachedPerms.writePatterns, key)) &&
+			!intersects(cachedPerms.writeDenyPerms, pt) && !matchKey(cachedPerms.writeDenyPatterns, key)
 	default:
 		lg.Panic("unknown auth type", zap.String("auth-type", permtyp.String()))
 	}
 	return false
 }
 
-func (as *authStore) isRangeOpPermitted(userName string, key, rangeEnd []byte, permtyp authpb.Permission_Type) bool {
+func intersects(tree adt.IntervalTree, ivl adt.Interval) bool {
+	return tree != nil && tree.Intersects(ivl)
+}
+
+func (as *authStore) isRangeOpPermitted(tx AuthReadTx, authInfo *AuthInfo, key, rangeEnd []byte, permtyp authpb.Permission_Type) bool {
 	// assumption: tx is Lock()ed
 	as.rangePermCacheMu.RLock()
-	defer as.rangePermCacheMu.RUnlock()
-
-	rangePerm, ok := as.rangePermCache[userName]
+	rangePerm, ok := as.rangePermCache[authInfo.Username]
+	as.rangePermCacheMu.RUnlock()
+	if !ok && authInfo.External {
+		rangePerm, ok = as.rolesRangePerms(tx, authInfo.Roles), true
+	}
 	if !ok {
 		as.lg.Error(
 			"user doesn't exist",
-			zap.String("user-name", userName),
+			zap.String("user-name", authInfo.Username),
 		)
 		return false
 	}
@@ -125,6 +165,28 @@ func (as *authStore) isRangeOpPermitted(userName string, key, rangeEnd []byte, p
 	return checkKeyInterval(as.lg, rangePerm, key, rangeEnd, permtyp)
 }
 
+// rolesSep separates the roles of the keys of rolesPermCache, since role names cannot be empty.
+const rolesSep = "\x00"
+
+// rolesRangePerms returns the permissions of the roles granted to a user verified by the
+// authenticator, and caches them until the next configuration update.
+func (as *authStore) rolesRangePerms(tx AuthReadTx, roles []string) *unifiedRangePermissions {
+	// assumption: tx is Lock()ed
+	key := strings.Join(roles, rolesSep)
+	as.rangePermCacheMu.RLock()
+	perms, ok := as.rolesPermCache[key]
+	as.rangePermCacheMu.RUnlock()
+	if ok {
+		return perms
+	}
+
+	perms = getRolesMergedPerms(tx, roles)
+	as.rangePermCacheMu.Lock()
+	as.rolesPermCache[key] = perms
+	as.rangePermCacheMu.Unlock()
+	return perms
+}
+
 func (as *authStore) refreshRangePermCache(tx AuthReadTx) {
 	// Note that every authentication configuration update calls this method and it invalidates the entire
 	// rangePermCache and reconstruct it based on information of users and roles stored in the backend.
@@ -135,6 +197,7 @@ func (as *authStore) refreshRangePermCache(tx AuthReadTx) {
 	as.lg.Debug("Refreshing rangePermCache")
 
 	as.rangePermCache = make(map[string]*unifiedRangePermissions)
+	as.rolesPermCache = make(map[string]*unifiedRangePermissions)
 
 	users := tx.UnsafeGetAllUsers()
 	for _, user := range users {
@@ -151,7 +214,95 @@ func (as *authStore) refreshRangePermCache(tx AuthReadTx) {
 	}
 }
 
+// getUser returns the user stored in the backend, or the user verified by the authenticator
+// with the roles of authInfo if there is no such user.
+func (as *authStore) getUser(tx AuthReadTx, authInfo *AuthInfo) *authpb.User {
+	// assumption: tx is Lock()ed
+	if user := tx.UnsafeGetUser(authInfo.Username); user != nil {
+		return user
+	}
+	if authInfo.External {
+		return &authpb.User{Name: []byte(authInfo.Username), Roles: authInfo.Roles}
+	}
+	return nil
+}
+
+// identity is a user verified by the authenticator. It is only kept in memory to resolve the
+// roles of its tokens when its requests are proposed, since the roles are carried by the
+// requests. It expires once its tokens have not been used for their TTL, so the user must
+// authenticate again and the authenticator resolves its roles again.
+type identity struct {
+	roles []string
+	// expires is extended every time a token of the identity is validated
+	expires time.Time
+}
+
+// identityTTL returns how long an identity is kept after its tokens were last used.
+func (as *authStore) identityTTL() time.Duration {
+	if ttl := as.tokenProvider.tokenTTL(); ttl > 0 {
+		return ttl
+	}
+	return simpleTokenTTLDefault
+}
+
+// addIdentity registers an identity verified by the authenticator.
+func (as *authStore) addIdentity(id *Identity) {
+	as.identitiesMu.Lock()
+	defer as.identitiesMu.Unlock()
+	as.identities[id.Name] = &identity{roles: normalizeRoles(id.Roles), expires: time.Now().Add(as.identityTTL())}
+}
+
+// validateIdentity checks that the identity of the user of authInfo, if the user is not stored
+// in the backend, has not expired, extends it since its token is in use, and sets the roles of
+// authInfo. The identities expired are dropped once per TTL, so they are bounded by the users
+// which authenticated recently.
+func (as *authStore) validateIdentity(authInfo *AuthInfo) bool {
+	if as.authenticator == nil {
+		return true
+	}
+
+	now := time.Now()
+	ttl := as.identityTTL()
+	as.identitiesMu.Lock()
+	if now.Sub(as.identitiesSwept) > ttl {
+		for userName, id := range as.identities {
+			if now.After(id.expires) {
+				delete(as.identities, userName)
+			}
+		}
+		as.identitiesSwept = now
+	}
+	var roles []string
+	id, valid := as.identities[authInfo.Username]
+	if valid = valid && !now.After(id.expires); valid {
+		id.expires = now.Add(ttl)
+		roles = id.roles
+	}
+	as.identitiesMu.Unlock()
+
+	if as.hasLocalUser(authInfo.Username) {
+		// local users take precedence over the identities verified by the authenticator
+		return true
+	}
+	if !valid {
+		// the roles of the token are unknown to this member, e.g. it restarted from a
+		// snapshot since, so the user must authenticate again
+		return false
+	}
+	authInfo.External, authInfo.Roles = true, roles
+	return true
+}
+
 type unifiedRangePermissions struct {
 	readPerms  adt.IntervalTree
 	writePerms adt.IntervalTree
+
+	// readDenyPerms and writeDenyPerms override the permissions granted above
+	readDenyPerms  adt.IntervalTree
+	writeDenyPerms adt.IntervalTree
+
+	readPatterns      []keyPattern
+	writePatterns     []keyPattern
+	readDenyPatterns  []keyPattern
+	writeDenyPatterns []keyPattern
 }
@@ -198,6 +198,13 @@ func (t *tokenSimple) disable() {
 	}
 }
 
+func (t *tokenSimple) tokenTTL() time.Duration {
+	if t.simpleTokenTTL <= 0 {
+		return simpleTokenTTLDefault
+	}
+	return t.simpleTokenTTL
+}
+
 func (t *tokenSimple) info(ctx context.Context, token string, revision uint64) (*AuthInfo, bool) {
 	if !t.isValidSimpleToken(ctx, token) {
 		return nil, false
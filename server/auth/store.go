@@ -19,6 +19,7 @@ import (
 	"context"
 	"encoding/base64"
 	"errors"
+	"path"
 	"sort"
 	"strings"
 	"sync"
@@ -58,6 +59,7 @@ var (
 	ErrPermissionNotGranted = errors.New("auth: permission is not granted to the role")
 	ErrAuthNotEnabled       = errors.New("auth: authentication is not enabled")
 	ErrAuthOldRevision      = errors.New("auth: revision in header is old")
+	ErrInvalidPermPattern   = errors.New("auth: invalid permission key pattern")
 	ErrInvalidAuthToken     = errors.New("auth: invalid auth token")
 	ErrInvalidAuthOpts      = errors.New("auth: invalid auth options")
 	ErrInvalidAuthMgmt      = errors.New("auth: invalid auth management")
@@ -78,6 +80,10 @@ const (
 type AuthInfo struct {
 	Username string
 	Revision uint64
+	// External is set if the user was verified by the authenticator instead of being
+	// stored in the backend, and is granted Roles.
+	External bool
+	Roles    []string
 }
 
 // AuthenticateParamIndex is used for a key of context in the parameters of Authenticate()
@@ -86,6 +92,10 @@ type AuthenticateParamIndex struct{}
 // AuthenticateParamSimpleTokenPrefix is used for a key of context in the parameters of Authenticate()
 type AuthenticateParamSimpleTokenPrefix struct{}
 
+// AuthenticateParamIdentity is used for a key of context in the parameters of Authenticate()
+// when the user was verified by an Authenticator
+type AuthenticateParamIdentity struct{}
+
 // AuthStore defines auth storage interface.
 type AuthStore interface {
 	// AuthEnable turns on the authentication feature
@@ -164,6 +174,11 @@ type AuthStore interface {
 	// CheckPassword checks a given pair of username and password is correct
 	CheckPassword(username, password string) (uint64, error)
 
+	// CheckCredentials checks a given pair of username and password like CheckPassword,
+	// or with the authenticator if there is no such user. It returns the identity verified
+	// by the authenticator, if any
+	CheckCredentials(ctx context.Context, username, password string) (uint64, *Identity, error)
+
 	// Close does cleanup of AuthStore
 	Close() error
 
@@ -179,6 +194,9 @@ type AuthStore interface {
 	// HasRole checks that user has role
 	HasRole(user, role string) bool
 
+	// UserRoles returns the roles granted to the user of authInfo
+	UserRoles(authInfo *AuthInfo) []string
+
 	// BcryptCost gets strength of hashing bcrypted auth password
 	BcryptCost() int
 }
@@ -191,6 +209,9 @@ type TokenProvider interface {
 
 	invalidateUser(string)
 	genTokenPrefix() (string, error)
+
+	// tokenTTL returns how long an assigned token stays valid, or zero if the provider doesn't assign tokens
+	tokenTTL() time.Duration
 }
 
 type AuthBackend interface {
@@ -244,7 +265,18 @@ type authStore struct {
 	rangePermCache   map[string]*unifiedRangePermissions // username -> unifiedRangePermissions
 	rangePermCacheMu sync.RWMutex
 
+	// rolesPermCache caches the permissions of the users verified by the authenticator by their
+	// roles, since they are not stored in the backend. It is protected by rangePermCacheMu.
+	rolesPermCache map[string]*unifiedRangePermissions // roles joined by rolesSep -> unifiedRangePermissions
+
+	// identities are the users verified by the authenticator, which resolve the roles of their
+	// tokens when their requests are proposed. They are protected by identitiesMu.
+	identities      map[string]*identity // username -> identity
+	identitiesMu    sync.Mutex
+	identitiesSwept time.Time
+
 	tokenProvider TokenProvider
+	authenticator Authenticator
 	bcryptCost    int // the algorithm cost / strength for hashing auth passwords
 }
 
@@ -302,6 +334,10 @@ func (as *authStore) AuthDisable() {
 	as.enabled = false
 	as.tokenProvider.disable()
 
+	as.identitiesMu.Lock()
+	as.identities = make(map[string]*identity)
+	as.identitiesMu.Unlock()
+
 	as.lg.Info("disabled authentication")
 }
 
@@ -320,12 +356,20 @@ func (as *authStore) Authenticate(ctx context.Context, username, password string
 		return nil, ErrAuthNotEnabled
 	}
 	user := as.be.GetUser(username)
-	if user == nil {
-		return nil, ErrAuthFailed
-	}
+	if id, ok := ctx.Value(AuthenticateParamIdentity{}).(*Identity); ok && id != nil {
+		// local users take precedence over the identities verified by the authenticator
+		if user != nil {
+			return nil, ErrAuthFailed
+		}
+		as.addIdentity(id)
+	} else {
+		if user == nil {
+			return nil, ErrAuthFailed
+		}
 
-	if user.Options != nil && user.Options.NoPassword {
-		return nil, ErrAuthFailed
+		if user.Options != nil && user.Options.NoPassword {
+			return nil, ErrAuthFailed
+		}
 	}
 
 	// Password checking is already performed in the API layer, so we don't need to check for now.
@@ -379,6 +423,53 @@ func (as *authStore) CheckPassword(username, password string) (uint64, error) {
 	return revision, nil
 }
 
+func (as *authStore) CheckCredentials(ctx context.Context, username, password string) (uint64, *Identity, error) {
+	if as.authenticator == nil || as.hasLocalUser(username) {
+		revision, err := as.CheckPassword(username, password)
+		return revision, nil, err
+	}
+	if !as.IsAuthEnabled() {
+		return 0, nil, ErrAuthNotEnabled
+	}
+
+	// the revision is read before the authenticator is called to detect staleness of roles
+	revision := as.Revision()
+	id, err := as.authenticator.Authenticate(ctx, username, password)
+	if err != nil {
+		as.lg.Info(
+			"authenticator rejected credentials",
+			zap.String("user-name", username),
+			zap.Error(err),
+		)
+		return 0, nil, ErrAuthFailed
+	}
+	if id == nil || id.Name == "" {
+		return 0, nil, ErrAuthFailed
+	}
+	if id.Name != username && as.hasLocalUser(id.Name) {
+		as.lg.Warn(
+			"identity verified by authenticator conflicts with a local user",
+			zap.String("user-name", id.Name),
+		)
+		return 0, nil, ErrAuthFailed
+	}
+	return revision, &Identity{Name: id.Name, Roles: normalizeRoles(id.Roles)}, nil
+}
+
+// hasLocalUser checks whether a user with the given name is stored in the backend.
+func (as *authStore) hasLocalUser(userName string) bool {
+	tx := as.be.ReadTx()
+	tx.Lock()
+	defer tx.Unlock()
+	return tx.UnsafeGetUser(userName) != nil
+}
+
+// SetAuthenticator sets the authenticator used for the users which are not stored in the backend.
+// It must be called before the AuthStore is used.
+func (as *authStore) SetAuthenticator(a Authenticator) {
+	as.authenticator = a
+}
+
 func (as *authStore) Recover(be AuthBackend) {
 	as.be = be
 	tx := be.ReadTx()
@@ -678,7 +769,7 @@ func (as *authStore) RoleRevokePermission(r *pb.AuthRoleRevokePermissionRequest)
 	}
 
 	for _, perm := range role.KeyPermission {
-		if !bytes.Equal(perm.Key, r.Key) || !bytes.Equal(perm.RangeEnd, r.RangeEnd) {
+		if !revokesPermission(r, perm) {
 			updatedRole.KeyPermission = append(updatedRole.KeyPermission, perm)
 		}
 	}
@@ -692,15 +783,30 @@ func (as *authStore) RoleRevokePermission(r *pb.AuthRoleRevokePermissionRequest)
 	as.commitRevision(tx)
 	as.refreshRangePermCache(tx)
 
+	key, rangeEnd := r.Key, r.RangeEnd
+	if r.Perm != nil {
+		key, rangeEnd = r.Perm.Key, r.Perm.RangeEnd
+	}
 	as.lg.Info(
 		"revoked a permission on range",
 		zap.String("role-name", r.Role),
-		zap.String("key", string(r.Key)),
-		zap.String("range-end", string(r.RangeEnd)),
+		zap.String("key", string(key)),
+		zap.String("range-end", string(rangeEnd)),
 	)
 	return &pb.AuthRoleRevokePermissionResponse{}, nil
 }
 
+// revokesPermission reports whether the request revokes the permission. With
+// Perm set, only the equal permission is revoked, otherwise every permission
+// on the key and range end is.
+func revokesPermission(r *pb.AuthRoleRevokePermissionRequest, perm *authpb.Permission) bool {
+	if r.Perm == nil {
+		return bytes.Equal(perm.Key, r.Key) && bytes.Equal(perm.RangeEnd, r.RangeEnd)
+	}
+	return bytes.Equal(perm.Key, r.Perm.Key) && bytes.Equal(perm.RangeEnd, r.Perm.RangeEnd) &&
+		perm.PermType == r.Perm.PermType && perm.Deny == r.Perm.Deny && perm.Pattern == r.Perm.Pattern
+}
+
 func (as *authStore) RoleDelete(r *pb.AuthRoleDeleteRequest) (*pb.AuthRoleDeleteResponse, error) {
 	if as.enabled && r.Role == rootRole {
 		as.lg.Error("cannot delete 'root' role", zap.String("role-name", r.Role))
@@ -774,7 +880,18 @@ func (as *authStore) RoleAdd(r *pb.AuthRoleAddRequest) (*pb.AuthRoleAddResponse,
 }
 
 func (as *authStore) authInfoFromToken(ctx context.Context, token string) (*AuthInfo, bool) {
-	return as.tokenProvider.info(ctx, token, as.Revision())
+	authInfo, ok := as.tokenProvider.info(ctx, token, as.Revision())
+	if !ok || authInfo == nil {
+		return authInfo, ok
+	}
+	if !as.validateIdentity(authInfo) {
+		as.lg.Debug(
+			"identity of token expired",
+			zap.String("user-name", authInfo.Username),
+		)
+		return nil, false
+	}
+	return authInfo, true
 }
 
 type permSlice []*authpb.Permission
@@ -805,11 +922,24 @@ func (as *authStore) RoleGrantPermission(r *pb.AuthRoleGrantPermissionRequest) (
 		return nil, ErrRoleNotFound
 	}
 
+	if r.Perm.Pattern {
+		if _, err := path.Match(string(r.Perm.Key), ""); err != nil {
+			return nil, ErrInvalidPermPattern
+		}
+	}
+
 	idx := sort.Search(len(role.KeyPermission), func(i int) bool {
 		return bytes.Compare(role.KeyPermission[i].Key, r.Perm.Key) >= 0
 	})
+	// allow and deny permissions, and patterns, are distinct from each other on the same range
+	for ; idx < len(role.KeyPermission) && bytes.Equal(role.KeyPermission[idx].Key, r.Perm.Key); idx++ {
+		perm := role.KeyPermission[idx]
+		if bytes.Equal(perm.RangeEnd, r.Perm.RangeEnd) && perm.Deny == r.Perm.Deny && perm.Pattern == r.Perm.Pattern {
+			break
+		}
+	}
 
-	if idx < len(role.KeyPermission) && bytes.Equal(role.KeyPermission[idx].Key, r.Perm.Key) && bytes.Equal(role.KeyPermission[idx].RangeEnd, r.Perm.RangeEnd) {
+	if idx < len(role.KeyPermission) && bytes.Equal(role.KeyPermission[idx].Key, r.Perm.Key) {
 		// update existing permission
 		role.KeyPermission[idx].PermType = r.Perm.PermType
 	} else {
@@ -818,6 +948,8 @@ func (as *authStore) RoleGrantPermission(r *pb.AuthRoleGrantPermissionRequest) (
 			Key:      r.Perm.Key,
 			RangeEnd: r.Perm.RangeEnd,
 			PermType: r.Perm.PermType,
+			Deny:     r.Perm.Deny,
+			Pattern:  r.Perm.Pattern,
 		}
 
 		role.KeyPermission = append(role.KeyPermission, newPerm)
@@ -835,17 +967,20 @@ func (as *authStore) RoleGrantPermission(r *pb.AuthRoleGrantPermissionRequest) (
 		zap.String("permission-name", authpb.Permission_Type_name[int32(r.Perm.PermType)]),
 		zap.ByteString("key", r.Perm.Key),
 		zap.ByteString("range-end", r.Perm.RangeEnd),
+		zap.Bool("deny", r.Perm.Deny),
+		zap.Bool("pattern", r.Perm.Pattern),
 	)
 	return &pb.AuthRoleGrantPermissionResponse{}, nil
 }
 
-func (as *authStore) isOpPermitted(userName string, revision uint64, key, rangeEnd []byte, permTyp authpb.Permission_Type) error {
+func (as *authStore) isOpPermitted(authInfo *AuthInfo, key, rangeEnd []byte, permTyp authpb.Permission_Type) error {
 	// TODO(mitake): this function would be costly so we need a caching mechanism
 	if !as.IsAuthEnabled() {
 		return nil
 	}
 
 	// only gets rev == 0 when passed AuthInfo{}; no user given
+	userName, revision := authInfo.Username, authInfo.Revision
 	if revision == 0 {
 		return ErrUserEmpty
 	}
@@ -863,7 +998,7 @@ func (as *authStore) isOpPermitted(userName string, revision uint64, key, rangeE
 	tx.Lock()
 	defer tx.Unlock()
 
-	user := tx.UnsafeGetUser(userName)
+	user := as.getUser(tx, authInfo)
 	if user == nil {
 		as.lg.Error("cannot find a user for permission check", zap.String("user-name", userName))
 		return ErrPermissionDenied
@@ -874,7 +1009,7 @@ func (as *authStore) isOpPermitted(userName string, revision uint64, key, rangeE
 		return nil
 	}
 
-	if as.isRangeOpPermitted(userName, key, rangeEnd, permTyp) {
+	if as.isRangeOpPermitted(tx, authInfo, key, rangeEnd, permTyp) {
 		return nil
 	}
 
@@ -882,15 +1017,15 @@ func (as *authStore) isOpPermitted(userName string, revision uint64, key, rangeE
 }
 
 func (as *authStore) IsPutPermitted(authInfo *AuthInfo, key []byte) error {
-	return as.isOpPermitted(authInfo.Username, authInfo.Revision, key, nil, authpb.WRITE)
+	return as.isOpPermitted(authInfo, key, nil, authpb.WRITE)
 }
 
 func (as *authStore) IsRangePermitted(authInfo *AuthInfo, key, rangeEnd []byte) error {
-	return as.isOpPermitted(authInfo.Username, authInfo.Revision, key, rangeEnd, authpb.READ)
+	return as.isOpPermitted(authInfo, key, rangeEnd, authpb.READ)
 }
 
 func (as *authStore) IsDeleteRangePermitted(authInfo *AuthInfo, key, rangeEnd []byte) error {
-	return as.isOpPermitted(authInfo.Username, authInfo.Revision, key, rangeEnd, authpb.WRITE)
+	return as.isOpPermitted(authInfo, key, rangeEnd, authpb.WRITE)
 }
 
 func (as *authStore) IsAdminPermitted(authInfo *AuthInfo) error {
@@ -904,7 +1039,7 @@ func (as *authStore) IsAdminPermitted(authInfo *AuthInfo) error {
 	tx := as.be.ReadTx()
 	tx.Lock()
 	defer tx.Unlock()
-	u := tx.UnsafeGetUser(authInfo.Username)
+	u := as.getUser(tx, authInfo)
 
 	if u == nil {
 		return ErrUserNotFound
@@ -952,6 +1087,8 @@ func NewAuthStore(lg *zap.Logger, be AuthBackend, tp TokenProvider, bcryptCost i
 		be:             be,
 		enabled:        enabled,
 		rangePermCache: make(map[string]*unifiedRangePermissions),
+		rolesPermCache: make(map[string]*unifiedRangePermissions),
+		identities:     make(map[string]*identity),
 		tokenProvider:  tp,
 		bcryptCost:     bcryptCost,
 	}
@@ -1201,6 +1338,18 @@ func (as *authStore) HasRole(user, role string) bool {
 	return false
 }
 
+func (as *authStore) UserRoles(authInfo *AuthInfo) []string {
+	tx := as.be.ReadTx()
+	tx.Lock()
+	u := as.getUser(tx, authInfo)
+	tx.Unlock()
+
+	if u == nil {
+		return nil
+	}
+	return u.Roles
+}
+
 func (as *authStore) BcryptCost() int {
 	return as.bcryptCost
 }
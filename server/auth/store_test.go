@@ -423,12 +423,66 @@ func TestIsOpPermitted(t *testing.T) {
 
 	// check permission reflected to user
 
-	err = as.isOpPermitted("foo", as.Revision(), perm.Key, perm.RangeEnd, perm.PermType)
+	err = as.isOpPermitted(&AuthInfo{Username: "foo", Revision: as.Revision()}, perm.Key, perm.RangeEnd, perm.PermType)
 	if err != nil {
 		t.Fatal(err)
 	}
 }
 
+func TestIsOpPermittedWithDenyAndPatterns(t *testing.T) {
+	as, tearDown := setupAuthStore(t)
+	defer tearDown(t)
+
+	perms := []*authpb.Permission{
+		{PermType: authpb.READWRITE, Key: []byte("/tenants/"), RangeEnd: []byte("/tenants0")},
+		{PermType: authpb.WRITE, Key: []byte("/tenants/*/secrets/"), RangeEnd: []byte("/tenants/*/secrets0"), Deny: true, Pattern: true},
+		{PermType: authpb.READ, Key: []byte("/tenants/b/"), RangeEnd: []byte("/tenants/b0"), Deny: true},
+		{PermType: authpb.READ, Key: []byte("/shared/*/config"), Pattern: true},
+	}
+	for _, perm := range perms {
+		_, err := as.RoleGrantPermission(&pb.AuthRoleGrantPermissionRequest{Name: "role-test", Perm: perm})
+		assert.NoError(t, err)
+	}
+	_, err := as.UserGrantRole(&pb.AuthUserGrantRoleRequest{User: "foo", Role: "role-test"})
+	assert.NoError(t, err)
+
+	_, err = as.RoleGrantPermission(&pb.AuthRoleGrantPermissionRequest{Name: "role-test", Perm: &authpb.Permission{Key: []byte("/[a-"), Pattern: true}})
+	assert.Equal(t, ErrInvalidPermPattern, err)
+
+	tests := []struct {
+		key, rangeEnd string
+		permTyp       authpb.Permission_Type
+		want          error
+	}{
+		{"/tenants/a/config/x", "", authpb.WRITE, nil},
+		{"/tenants/a/secrets/x", "", authpb.READ, nil},
+		{"/tenants/a/secrets/x", "", authpb.WRITE, ErrPermissionDenied},
+		{"/tenants/a/config/", "/tenants/a/config0", authpb.WRITE, nil},
+		{"/tenants/a/", "/tenants/a0", authpb.WRITE, ErrPermissionDenied},
+		{"/tenants/a/", "/tenants/a0", authpb.READ, nil},
+		{"/tenants/b/x", "", authpb.READ, ErrPermissionDenied},
+		{"/tenants/b/x", "", authpb.WRITE, nil},
+		{"/tenants/", "/tenants0", authpb.READ, ErrPermissionDenied},
+		{"/shared/a/config", "", authpb.READ, nil},
+		{"/shared/a/config", "", authpb.WRITE, ErrPermissionDenied},
+		{"/shared/a/other", "", authpb.READ, ErrPermissionDenied},
+		{"/shared/a/", "/shared/a0", authpb.READ, ErrPermissionDenied},
+	}
+	for i, tt := range tests {
+		var rangeEnd []byte
+		if tt.rangeEnd != "" {
+			rangeEnd = []byte(tt.rangeEnd)
+		}
+		err := as.isOpPermitted(&AuthInfo{Username: "foo", Revision: as.Revision()}, []byte(tt.key), rangeEnd, tt.permTyp)
+		assert.Equalf(t, tt.want, err, "#%d", i)
+	}
+
+	// the root role is not affected by deny permissions
+	_, err = as.UserGrantRole(&pb.AuthUserGrantRoleRequest{User: "foo", Role: "root"})
+	assert.NoError(t, err)
+	assert.NoError(t, as.isOpPermitted(&AuthInfo{Username: "foo", Revision: as.Revision()}, []byte("/tenants/a/secrets/x"), nil, authpb.WRITE))
+}
+
 func TestGetUser(t *testing.T) {
 	as, tearDown := setupAuthStore(t)
 	defer tearDown(t)
@@ -605,6 +659,41 @@ func TestRoleRevokePermission(t *testing.T) {
 	}
 }
 
+func TestRoleRevokePermissionMatch(t *testing.T) {
+	as, tearDown := setupAuthStore(t)
+	defer tearDown(t)
+
+	if _, err := as.RoleAdd(&pb.AuthRoleAddRequest{Name: "role-test-1"}); err != nil {
+		t.Fatal(err)
+	}
+	allow := &authpb.Permission{PermType: authpb.READ, Key: []byte("foo"), RangeEnd: []byte("fop")}
+	deny := &authpb.Permission{PermType: authpb.WRITE, Key: []byte("foo"), RangeEnd: []byte("fop"), Deny: true}
+	for _, perm := range []*authpb.Permission{allow, deny} {
+		if _, err := as.RoleGrantPermission(&pb.AuthRoleGrantPermissionRequest{Name: "role-test-1", Perm: perm}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the type, deny and pattern must match
+	for _, perm := range []*authpb.Permission{
+		{PermType: authpb.READ, Key: []byte("foo"), RangeEnd: []byte("fop"), Deny: true},
+		{PermType: authpb.WRITE, Key: []byte("foo"), RangeEnd: []byte("fop"), Deny: true, Pattern: true},
+	} {
+		_, err := as.RoleRevokePermission(&pb.AuthRoleRevokePermissionRequest{Role: "role-test-1", Perm: perm})
+		if err != ErrPermissionNotGranted {
+			t.Fatalf("revoking %v: err = %v, want %v", perm, err, ErrPermissionNotGranted)
+		}
+	}
+	if _, err := as.RoleRevokePermission(&pb.AuthRoleRevokePermissionRequest{Role: "role-test-1", Perm: deny}); err != nil {
+		t.Fatal(err)
+	}
+	r, err := as.RoleGet(&pb.AuthRoleGetRequest{Role: "role-test-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []*authpb.Permission{allow}, r.Perm)
+}
+
 func TestUserRevokePermission(t *testing.T) {
 	as, tearDown := setupAuthStore(t)
 	defer tearDown(t)
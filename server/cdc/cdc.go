@@ -0,0 +1,402 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cdc captures the committed changes of the keyspace to sinks.
+package cdc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/client/pkg/v3/fileutil"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+)
+
+const (
+	// DefaultRetryInterval is the default interval between two attempts to
+	// deliver the events a sink failed to write.
+	DefaultRetryInterval = time.Second
+	// DefaultReplicateInterval is the default interval between two
+	// replications of the cursor of a sink while the events are delivered.
+	DefaultReplicateInterval = time.Second
+
+	replicateTimeout = 5 * time.Second
+)
+
+// Sink receives the captured events in revision order. Write returns once the
+// events are durably delivered; the events are written again if it fails, if
+// the member stops before the delivery is recorded, or if the leadership
+// moves before the cursor is replicated, so a sink may receive the events of a
+// revision more than once.
+type Sink interface {
+	// Name identifies the sink, and names the file of its cursor.
+	Name() string
+	Write(evs []*mvccpb.Event) error
+	Close() error
+}
+
+// Config configures the Capturer.
+type Config struct {
+	// Prefixes are the prefixes of the keys whose events are captured, all
+	// the keys if empty.
+	Prefixes []string
+	// CursorDir is the directory of the cursors recording the last revision
+	// delivered to each sink.
+	CursorDir string
+	// RetryInterval is the interval between two attempts to deliver the
+	// events a sink failed to write.
+	RetryInterval time.Duration
+	// Replicator replicates the cursors to the members of the cluster. The
+	// cursors are only kept by the member if nil.
+	Replicator CursorReplicator
+	// ReplicateInterval is the interval between two replications of the
+	// cursor of a sink while the events are delivered.
+	ReplicateInterval time.Duration
+}
+
+// CursorReplicator replicates the cursors of the sinks, so that the member
+// taking over the leadership resumes the delivery where the previous leader
+// left it.
+type CursorReplicator interface {
+	// Cursor returns the replicated cursor of the sink, 0 if none.
+	Cursor(sink string) int64
+	// Replicate records the cursor of the sink on every member.
+	Replicate(ctx context.Context, sink string, rev int64) error
+}
+
+// Capturer delivers the events of the committed revisions to sinks, from the
+// revision following the cursor of each sink. The events of the revisions
+// compacted before they were delivered are lost.
+//
+// Every member runs a Capturer with its own cursors, but only delivers the
+// events while it is resumed, i.e. while the member is the leader, so the
+// events are not delivered by each member of the cluster. The leader
+// replicates its cursors periodically, and a Capturer resumes from the
+// replicated cursor of a sink when it is ahead of its own.
+type Capturer struct {
+	lg    *zap.Logger
+	cfg   Config
+	kv    mvcc.WatchableKV
+	sinks []Sink
+
+	// mu protects resumed and changed
+	mu      sync.Mutex
+	resumed bool
+	// changed is closed and replaced when the Capturer is paused or resumed.
+	changed chan struct{}
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New returns a new Capturer delivering the events of kv to the sinks.
+func New(lg *zap.Logger, kv mvcc.WatchableKV, cfg Config, sinks ...Sink) (*Capturer, error) {
+	if lg == nil {
+		lg = zap.NewNop()
+	}
+	if len(sinks) == 0 {
+		return nil, errors.New("no change data capture sink")
+	}
+	if cfg.CursorDir == "" {
+		return nil, errors.New("no change data capture cursor directory")
+	}
+	names := make(map[string]bool)
+	for _, s := range sinks {
+		if names[s.Name()] || strings.ContainsRune(s.Name(), filepath.Separator) {
+			return nil, fmt.Errorf("invalid change data capture sink name %q", s.Name())
+		}
+		names[s.Name()] = true
+	}
+	if err := fileutil.TouchDirAll(lg, cfg.CursorDir); err != nil {
+		return nil, err
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = DefaultRetryInterval
+	}
+	if cfg.ReplicateInterval <= 0 {
+		cfg.ReplicateInterval = DefaultReplicateInterval
+	}
+	c := &Capturer{lg: lg, cfg: cfg, kv: kv, sinks: sinks, changed: make(chan struct{})}
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+	return c, nil
+}
+
+// Pause stops delivering the events until the Capturer is resumed. A new
+// Capturer is paused.
+func (c *Capturer) Pause() {
+	c.setResumed(false)
+}
+
+// Resume starts delivering the events from the cursor of each sink.
+func (c *Capturer) Resume() {
+	c.setResumed(true)
+}
+
+func (c *Capturer) setResumed(resumed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.resumed == resumed {
+		return
+	}
+	c.resumed = resumed
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// state returns whether the Capturer is resumed, and a channel closed once
+// it is paused or resumed.
+func (c *Capturer) state() (bool, <-chan struct{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.resumed, c.changed
+}
+
+// Run starts delivering the events to each sink in background.
+func (c *Capturer) Run() {
+	for _, s := range c.sinks {
+		s := s
+		c.wg.Add(1)
+		go func() {
+			defer c.wg.Done()
+			c.capture(s)
+		}()
+	}
+}
+
+// Stop stops delivering the events and closes the sinks.
+func (c *Capturer) Stop() {
+	c.cancel()
+	c.wg.Wait()
+	for _, s := range c.sinks {
+		if err := s.Close(); err != nil {
+			c.lg.Warn("failed to close change data capture sink", zap.String("sink", s.Name()), zap.Error(err))
+		}
+	}
+}
+
+// capture delivers the events to the sink while the Capturer is resumed,
+// until it is stopped.
+func (c *Capturer) capture(s Sink) {
+	lg := c.lg.With(zap.String("sink", s.Name()))
+	cur := newCursor(filepath.Join(c.cfg.CursorDir, s.Name()+".cursor"))
+	rev, err := cur.load()
+	if err != nil {
+		lg.Error("failed to load change data capture cursor", zap.Error(err))
+		return
+	}
+	if rev == 0 {
+		// the first run resumes from the replicated cursor, if any, or
+		// captures the revisions committed from now on, also while paused,
+		// so no revision is lost once the member leads
+		if rev = c.replicated(s); rev == 0 {
+			rev = c.kv.Rev()
+		}
+		if err = cur.save(rev); err != nil {
+			lg.Error("failed to save change data capture cursor", zap.Error(err))
+			return
+		}
+	}
+	cursorRevision.WithLabelValues(s.Name()).Set(float64(rev))
+
+	for {
+		resumed, changed := c.state()
+		if !resumed {
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-changed:
+				continue
+			}
+		}
+		if r := c.replicated(s); r > rev {
+			// delivered by the previous leaders
+			rev = r
+			if err = cur.save(rev); err != nil {
+				lg.Warn("failed to save change data capture cursor", zap.Error(err))
+			}
+			cursorRevision.WithLabelValues(s.Name()).Set(float64(rev))
+		}
+		lg.Info("started change data capture", zap.Int64("cursor-revision", rev))
+		if rev, err = c.deliver(lg, s, cur, rev, changed); err != nil {
+			return
+		}
+		lg.Info("paused change data capture", zap.Int64("cursor-revision", rev))
+	}
+}
+
+// deliver delivers the events following rev to the sink until changed is
+// closed, and returns the revision of the cursor. It returns an error once the
+// Capturer is stopped, or if the keys cannot be watched.
+func (c *Capturer) deliver(lg *zap.Logger, s Sink, cur *cursor, rev int64, changed <-chan struct{}) (int64, error) {
+	ws := c.kv.NewWatchStream()
+	defer ws.Close()
+	// watch all the keys to advance the cursor in revision order, and match
+	// the prefixes here
+	id, err := ws.Watch(0, []byte{0}, []byte{}, rev+1)
+	if err != nil {
+		lg.Error("failed to watch the keys to capture", zap.Error(err))
+		return rev, err
+	}
+	var replicatec <-chan time.Time
+	if c.cfg.Replicator != nil {
+		t := time.NewTicker(c.cfg.ReplicateInterval)
+		defer t.Stop()
+		replicatec = t.C
+	}
+	saved, replicated := rev, rev
+	defer func() {
+		if rev != saved {
+			if err := cur.save(rev); err != nil {
+				lg.Warn("failed to save change data capture cursor", zap.Error(err))
+			}
+		}
+	}()
+	for {
+		var wresp mvcc.WatchResponse
+		select {
+		case <-c.ctx.Done():
+			return rev, c.ctx.Err()
+		case <-changed:
+			return rev, nil
+		case <-replicatec:
+			if rev != replicated {
+				if err := c.replicate(s, rev); err != nil {
+					lg.Warn("failed to replicate change data capture cursor", zap.Int64("cursor-revision", rev), zap.Error(err))
+					continue
+				}
+				replicated = rev
+			}
+			continue
+		case wresp = <-ws.Chan():
+		}
+
+		if wresp.CompactRevision != 0 {
+			lg.Warn(
+				"revisions were compacted before they were captured",
+				zap.Int64("cursor-revision", rev),
+				zap.Int64("compact-revision", wresp.CompactRevision),
+			)
+			lostRevisions.WithLabelValues(s.Name()).Add(float64(wresp.CompactRevision - 1 - rev))
+			rev = wresp.CompactRevision - 1
+			ws.Cancel(id)
+			if id, err = ws.Watch(0, []byte{0}, []byte{}, rev+1); err != nil {
+				lg.Error("failed to watch the keys to capture", zap.Error(err))
+				return rev, err
+			}
+			continue
+		}
+		if len(wresp.Events) == 0 {
+			continue
+		}
+
+		evs := c.events(wresp.Events)
+		if len(evs) != 0 {
+			if err = c.write(lg, s, evs, changed); err != nil {
+				if c.ctx.Err() != nil {
+					return rev, err
+				}
+				// paused before the events were delivered
+				return rev, nil
+			}
+			eventsCounter.WithLabelValues(s.Name()).Add(float64(len(evs)))
+		}
+		// the watch responses hold the events of whole revisions
+		rev = wresp.Events[len(wresp.Events)-1].Kv.ModRevision
+		cursorRevision.WithLabelValues(s.Name()).Set(float64(rev))
+		if len(evs) != 0 {
+			// the cursor is saved lazily past the revisions of no captured
+			// events, which are only scanned again after a restart
+			if err = cur.save(rev); err != nil {
+				lg.Warn("failed to save change data capture cursor", zap.Error(err))
+				continue
+			}
+			saved = rev
+		}
+	}
+}
+
+// replicated returns the replicated cursor of the sink, 0 if none.
+func (c *Capturer) replicated(s Sink) int64 {
+	if c.cfg.Replicator == nil {
+		return 0
+	}
+	return c.cfg.Replicator.Cursor(s.Name())
+}
+
+func (c *Capturer) replicate(s Sink, rev int64) error {
+	ctx, cancel := context.WithTimeout(c.ctx, replicateTimeout)
+	defer cancel()
+	return c.cfg.Replicator.Replicate(ctx, s.Name(), rev)
+}
+
+// events returns the events on the captured keys, with their previous
+// key-value pair.
+func (c *Capturer) events(wevs []mvccpb.Event) []*mvccpb.Event {
+	var evs []*mvccpb.Event
+	for i := range wevs {
+		ev := wevs[i]
+		if !c.captured(ev.Kv.Key) {
+			continue
+		}
+		if ev.Type != mvccpb.PUT || ev.Kv.CreateRevision != ev.Kv.ModRevision {
+			r, err := c.kv.Range(context.TODO(), ev.Kv.Key, nil, mvcc.RangeOptions{Rev: ev.Kv.ModRevision - 1})
+			if err == nil && len(r.KVs) != 0 {
+				ev.PrevKv = &r.KVs[0]
+			}
+		}
+		evs = append(evs, &ev)
+	}
+	return evs
+}
+
+func (c *Capturer) captured(key []byte) bool {
+	if len(c.cfg.Prefixes) == 0 {
+		return true
+	}
+	for _, prefix := range c.cfg.Prefixes {
+		if strings.HasPrefix(string(key), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// write writes the events to the sink until it succeeds, or returns the error
+// of the last attempt once the Capturer is stopped or changed is closed.
+func (c *Capturer) write(lg *zap.Logger, s Sink, evs []*mvccpb.Event, changed <-chan struct{}) error {
+	for {
+		err := s.Write(evs)
+		if err == nil {
+			return nil
+		}
+		lg.Warn("failed to write change data capture events, retrying", zap.Int("events", len(evs)), zap.Error(err))
+		writeFailuresCounter.WithLabelValues(s.Name()).Inc()
+		select {
+		case <-c.ctx.Done():
+			return err
+		case <-changed:
+			return err
+		case <-time.After(c.cfg.RetryInterval):
+		}
+	}
+}
@@ -0,0 +1,360 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cdc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/pkg/v3/traceutil"
+	"go.etcd.io/etcd/server/v3/lease"
+	betesting "go.etcd.io/etcd/server/v3/storage/backend/testing"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+)
+
+type recordingSink struct {
+	mu   sync.Mutex
+	evs  []*mvccpb.Event
+	fail int
+}
+
+func (s *recordingSink) Name() string { return "recording" }
+
+func (s *recordingSink) Write(evs []*mvccpb.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail > 0 {
+		s.fail--
+		return errors.New("sink failure")
+	}
+	s.evs = append(s.evs, evs...)
+	return nil
+}
+
+func (s *recordingSink) Close() error { return nil }
+
+func (s *recordingSink) waitEvents(t *testing.T, n int) []*mvccpb.Event {
+	require.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.evs) >= n
+	}, 5*time.Second, 10*time.Millisecond)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.evs
+}
+
+func put(kv mvcc.KV, key, val string) {
+	kv.Put([]byte(key), []byte(val), lease.NoLease)
+}
+
+func TestCapturer(t *testing.T) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, b)
+	kv := mvcc.New(zaptest.NewLogger(t), b, &lease.FakeLessor{}, mvcc.StoreConfig{})
+	defer kv.Close()
+	put(kv, "a/0", "before")
+	cursorDir := t.TempDir()
+	cfg := Config{Prefixes: []string{"a/", "c/"}, CursorDir: cursorDir, RetryInterval: 10 * time.Millisecond}
+
+	// the first run captures the revisions committed from now on
+	sink := &recordingSink{fail: 2}
+	c, err := New(zaptest.NewLogger(t), kv, cfg, sink)
+	require.NoError(t, err)
+	c.Run()
+	c.Resume()
+	require.Eventually(t, func() bool {
+		rev, err := newCursor(filepath.Join(cursorDir, "recording.cursor")).load()
+		return err == nil && rev == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	put(kv, "a/0", "v1")
+	put(kv, "b/0", "v1")
+	put(kv, "c/0", "v1")
+	kv.DeleteRange([]byte("a/0"), nil)
+	evs := sink.waitEvents(t, 3)
+	c.Stop()
+
+	require.Len(t, evs, 3)
+	assert.Equal(t, mvccpb.PUT, evs[0].Type)
+	assert.Equal(t, "a/0", string(evs[0].Kv.Key))
+	assert.Equal(t, int64(3), evs[0].Kv.ModRevision)
+	require.NotNil(t, evs[0].PrevKv)
+	assert.Equal(t, "before", string(evs[0].PrevKv.Value))
+	assert.Equal(t, "c/0", string(evs[1].Kv.Key))
+	assert.Nil(t, evs[1].PrevKv)
+	assert.Equal(t, mvccpb.DELETE, evs[2].Type)
+	assert.Equal(t, int64(6), evs[2].Kv.ModRevision)
+	require.NotNil(t, evs[2].PrevKv)
+	assert.Equal(t, "v1", string(evs[2].PrevKv.Value))
+
+	// the next run resumes from the cursor
+	put(kv, "b/1", "v2")
+	put(kv, "c/1", "v2")
+	sink = &recordingSink{}
+	c, err = New(zaptest.NewLogger(t), kv, cfg, sink)
+	require.NoError(t, err)
+	c.Run()
+	c.Resume()
+	evs = sink.waitEvents(t, 1)
+	put(kv, "a/1", "v3")
+	evs = sink.waitEvents(t, 2)
+	c.Stop()
+	require.Len(t, evs, 2)
+	assert.Equal(t, "c/1", string(evs[0].Kv.Key))
+	assert.Equal(t, "a/1", string(evs[1].Kv.Key))
+	rev, err := newCursor(filepath.Join(cursorDir, "recording.cursor")).load()
+	require.NoError(t, err)
+	assert.Equal(t, int64(9), rev)
+}
+
+func TestCapturerPaused(t *testing.T) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, b)
+	kv := mvcc.New(zaptest.NewLogger(t), b, &lease.FakeLessor{}, mvcc.StoreConfig{})
+	defer kv.Close()
+	cursorDir := t.TempDir()
+	cursorPath := filepath.Join(cursorDir, "recording.cursor")
+
+	// a paused Capturer saves the cursor of the first run, but delivers nothing
+	sink := &recordingSink{}
+	c, err := New(zaptest.NewLogger(t), kv, Config{CursorDir: cursorDir}, sink)
+	require.NoError(t, err)
+	c.Run()
+	defer c.Stop()
+	require.Eventually(t, func() bool {
+		rev, err := newCursor(cursorPath).load()
+		return err == nil && rev == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	put(kv, "a", "v1")
+	time.Sleep(50 * time.Millisecond)
+	sink.mu.Lock()
+	assert.Empty(t, sink.evs)
+	sink.mu.Unlock()
+
+	// once resumed, it delivers the revisions from its cursor
+	c.Resume()
+	evs := sink.waitEvents(t, 1)
+	assert.Equal(t, int64(2), evs[0].Kv.ModRevision)
+
+	// once paused again, it saves its cursor and stops delivering
+	c.Pause()
+	require.Eventually(t, func() bool {
+		rev, err := newCursor(cursorPath).load()
+		return err == nil && rev == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	put(kv, "a", "v2")
+	time.Sleep(50 * time.Millisecond)
+	sink.mu.Lock()
+	assert.Len(t, sink.evs, 1)
+	sink.mu.Unlock()
+}
+
+type fakeReplicator struct {
+	mu      sync.Mutex
+	cursors map[string]int64
+}
+
+func (r *fakeReplicator) Cursor(sink string) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cursors[sink]
+}
+
+func (r *fakeReplicator) Replicate(_ context.Context, sink string, rev int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cursors[sink] = rev
+	return nil
+}
+
+func TestCapturerReplicated(t *testing.T) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, b)
+	kv := mvcc.New(zaptest.NewLogger(t), b, &lease.FakeLessor{}, mvcc.StoreConfig{})
+	defer kv.Close()
+	r := &fakeReplicator{cursors: map[string]int64{}}
+
+	// the leader replicates its cursor while it delivers the events
+	sink := &recordingSink{}
+	cfg := Config{CursorDir: t.TempDir(), Replicator: r, ReplicateInterval: 10 * time.Millisecond}
+	c, err := New(zaptest.NewLogger(t), kv, cfg, sink)
+	require.NoError(t, err)
+	c.Run()
+	c.Resume()
+	require.Eventually(t, func() bool {
+		_, err := newCursor(filepath.Join(cfg.CursorDir, "recording.cursor")).load()
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	put(kv, "a", "v1")
+	put(kv, "a", "v2")
+	sink.waitEvents(t, 2)
+	require.Eventually(t, func() bool { return r.Cursor("recording") == 3 }, 5*time.Second, 10*time.Millisecond)
+	c.Stop()
+
+	// a member whose own cursor is behind resumes from the replicated cursor
+	put(kv, "a", "v3")
+	sink = &recordingSink{}
+	cfg.CursorDir = t.TempDir()
+	require.NoError(t, newCursor(filepath.Join(cfg.CursorDir, "recording.cursor")).save(1))
+	c, err = New(zaptest.NewLogger(t), kv, cfg, sink)
+	require.NoError(t, err)
+	c.Run()
+	defer c.Stop()
+	c.Resume()
+	evs := sink.waitEvents(t, 1)
+	time.Sleep(50 * time.Millisecond)
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	require.Len(t, sink.evs, 1)
+	assert.Equal(t, int64(4), evs[0].Kv.ModRevision)
+}
+
+func TestCapturerCompacted(t *testing.T) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, b)
+	kv := mvcc.New(zaptest.NewLogger(t), b, &lease.FakeLessor{}, mvcc.StoreConfig{})
+	defer kv.Close()
+	cursorDir := t.TempDir()
+	put(kv, "a", "v1")
+	require.NoError(t, newCursor(filepath.Join(cursorDir, "recording.cursor")).save(1))
+	for i := 0; i < 5; i++ {
+		put(kv, "a", "v2")
+	}
+	done, err := kv.Compact(traceutil.TODO(), 4)
+	require.NoError(t, err)
+	<-done
+
+	sink := &recordingSink{}
+	c, err := New(zaptest.NewLogger(t), kv, Config{CursorDir: cursorDir}, sink)
+	require.NoError(t, err)
+	c.Run()
+	c.Resume()
+	evs := sink.waitEvents(t, 4)
+	c.Stop()
+	// the revisions 2 and 3 are lost
+	require.Len(t, evs, 4)
+	assert.Equal(t, int64(4), evs[0].Kv.ModRevision)
+}
+
+func TestFileSink(t *testing.T) {
+	evs := []*mvccpb.Event{
+		{Type: mvccpb.PUT, Kv: &mvccpb.KeyValue{Key: []byte("a"), Value: []byte("1"), CreateRevision: 2, ModRevision: 2, Version: 1, Lease: 7}},
+		{Type: mvccpb.DELETE, Kv: &mvccpb.KeyValue{Key: []byte("a"), ModRevision: 3}, PrevKv: &mvccpb.KeyValue{Key: []byte("a"), Value: []byte("1")}},
+	}
+	for _, format := range []Format{FormatNDJSON, FormatProtobuf} {
+		t.Run(string(format), func(t *testing.T) {
+			dir := t.TempDir()
+			s := NewFileSink(dir, format, 1)
+			require.NoError(t, s.Write(evs[:1]))
+			require.NoError(t, s.Write(evs[1:]))
+			require.NoError(t, s.Close())
+
+			// each write exceeds the size of a file
+			var got []*mvccpb.Event
+			for _, name := range []string{"events-00000000000000000002-0000", "events-00000000000000000003-0000"} {
+				f, err := os.Open(filepath.Join(dir, name+format.ext()))
+				require.NoError(t, err)
+				got = append(got, readEvents(t, format, f)...)
+				f.Close()
+			}
+			assert.Equal(t, evs, got)
+
+			// the events delivered again go to a new file
+			s = NewFileSink(dir, format, 1)
+			require.NoError(t, s.Write(evs[1:]))
+			require.NoError(t, s.Close())
+			for _, name := range []string{"events-00000000000000000003-0000", "events-00000000000000000003-0001"} {
+				f, err := os.Open(filepath.Join(dir, name+format.ext()))
+				require.NoError(t, err)
+				assert.Equal(t, evs[1:], readEvents(t, format, f))
+				f.Close()
+			}
+		})
+	}
+}
+
+func readEvents(t *testing.T, format Format, r io.Reader) []*mvccpb.Event {
+	var evs []*mvccpb.Event
+	br := bufio.NewReader(r)
+	for {
+		ev := &mvccpb.Event{}
+		if format == FormatNDJSON {
+			line, err := br.ReadBytes('\n')
+			if err == io.EOF {
+				return evs
+			}
+			require.NoError(t, err)
+			require.NoError(t, jsonpb.Unmarshal(bytes.NewReader(line), ev))
+		} else {
+			size, err := binary.ReadUvarint(br)
+			if err == io.EOF {
+				return evs
+			}
+			require.NoError(t, err)
+			data := make([]byte, size)
+			_, err = io.ReadFull(br, data)
+			require.NoError(t, err)
+			require.NoError(t, ev.Unmarshal(data))
+		}
+		evs = append(evs, ev)
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		bodies [][]byte
+		status = http.StatusInternalServerError
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/x-ndjson", r.Header.Get("Content-Type"))
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		mu.Lock()
+		defer mu.Unlock()
+		bodies = append(bodies, body)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	s := NewWebhookSink(srv.URL, time.Second)
+	defer s.Close()
+	evs := []*mvccpb.Event{{Type: mvccpb.PUT, Kv: &mvccpb.KeyValue{Key: []byte("a"), Value: []byte("1"), ModRevision: 2}}}
+	assert.Error(t, s.Write(evs))
+	mu.Lock()
+	status = http.StatusNoContent
+	mu.Unlock()
+	require.NoError(t, s.Write(evs))
+
+	require.Len(t, bodies, 2)
+	assert.Equal(t, evs, readEvents(t, FormatNDJSON, bytes.NewReader(bodies[1])))
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cdc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"go.etcd.io/etcd/client/pkg/v3/fileutil"
+	"go.etcd.io/etcd/pkg/v3/ioutil"
+)
+
+// cursor persists the last revision delivered to a sink.
+type cursor struct {
+	path string
+}
+
+func newCursor(path string) *cursor {
+	return &cursor{path: path}
+}
+
+// load returns the revision of the cursor, 0 if it was never saved.
+func (c *cursor) load() (int64, error) {
+	data, err := os.ReadFile(c.path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	rev, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil || rev <= 0 {
+		return 0, fmt.Errorf("invalid cursor %q in %s", data, c.path)
+	}
+	return rev, nil
+}
+
+// save atomically replaces the revision of the cursor.
+func (c *cursor) save(rev int64) error {
+	tmp := c.path + ".tmp"
+	if err := ioutil.WriteAndSyncFile(tmp, []byte(strconv.FormatInt(rev, 10)+"\n"), fileutil.PrivateFileMode); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return err
+	}
+	dir, err := fileutil.OpenDir(filepath.Dir(c.path))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return fileutil.Fsync(dir)
+}
@@ -0,0 +1,51 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cdc
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	eventsCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "etcd_debugging",
+		Subsystem: "cdc",
+		Name:      "events_total",
+		Help:      "Total number of events delivered to the change data capture sinks.",
+	}, []string{"sink"})
+	writeFailuresCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "etcd_debugging",
+		Subsystem: "cdc",
+		Name:      "write_failures_total",
+		Help:      "Total number of failed attempts to write events to the change data capture sinks.",
+	}, []string{"sink"})
+	lostRevisions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "etcd_debugging",
+		Subsystem: "cdc",
+		Name:      "lost_revisions_total",
+		Help:      "Total number of revisions compacted before they were captured.",
+	}, []string{"sink"})
+	cursorRevision = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "etcd_debugging",
+		Subsystem: "cdc",
+		Name:      "cursor_revision",
+		Help:      "The last revision delivered to the change data capture sinks.",
+	}, []string{"sink"})
+)
+
+func init() {
+	prometheus.MustRegister(eventsCounter)
+	prometheus.MustRegister(writeFailuresCounter)
+	prometheus.MustRegister(lostRevisions)
+	prometheus.MustRegister(cursorRevision)
+}
@@ -0,0 +1,204 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cdc
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/client/pkg/v3/fileutil"
+)
+
+// Format is the encoding of the events written by the file sink.
+type Format string
+
+const (
+	// FormatNDJSON writes each event as a line of JSON, in the JSON mapping
+	// of protocol buffers.
+	FormatNDJSON Format = "ndjson"
+	// FormatProtobuf writes each event as a protobuf mvccpb.Event prefixed
+	// by its size as a varint.
+	FormatProtobuf Format = "protobuf"
+
+	DefaultFormat = FormatNDJSON
+)
+
+// ParseFormat parses the name of a file sink format.
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case FormatNDJSON, FormatProtobuf:
+		return Format(s), nil
+	}
+	return "", fmt.Errorf("unknown change data capture format %q (only supports %q or %q)", s, FormatNDJSON, FormatProtobuf)
+}
+
+func (f Format) ext() string {
+	if f == FormatProtobuf {
+		return ".pb"
+	}
+	return ".ndjson"
+}
+
+var jsonMarshaler = &jsonpb.Marshaler{OrigName: true, EmitDefaults: true}
+
+func (f Format) encode(buf *bytes.Buffer, evs []*mvccpb.Event) error {
+	for _, ev := range evs {
+		if f == FormatProtobuf {
+			data, err := ev.Marshal()
+			if err != nil {
+				return err
+			}
+			buf.Write(binary.AppendUvarint(nil, uint64(len(data))))
+			buf.Write(data)
+			continue
+		}
+		if err := jsonMarshaler.Marshal(buf, ev); err != nil {
+			return err
+		}
+		buf.WriteByte('\n')
+	}
+	return nil
+}
+
+// fileSink appends the events to files named after the revision of their
+// first event and a sequence number, and starts a new file once the current
+// one grows beyond the maximum size.
+type fileSink struct {
+	dir     string
+	format  Format
+	maxSize int64
+
+	f    *os.File
+	size int64
+}
+
+// NewFileSink creates a sink appending the events in the format to files in
+// dir, each of at most about maxSize bytes. The files are never removed.
+func NewFileSink(dir string, format Format, maxSize int64) Sink {
+	return &fileSink{dir: dir, format: format, maxSize: maxSize}
+}
+
+func (s *fileSink) Name() string { return "file" }
+
+func (s *fileSink) Write(evs []*mvccpb.Event) error {
+	var buf bytes.Buffer
+	if err := s.format.encode(&buf, evs); err != nil {
+		return err
+	}
+	if s.f == nil || s.size >= s.maxSize {
+		if err := s.rotate(evs[0].Kv.ModRevision); err != nil {
+			return err
+		}
+	}
+	n, err := s.f.Write(buf.Bytes())
+	s.size += int64(n)
+	if err == nil {
+		err = fileutil.Fsync(s.f)
+	}
+	if err != nil {
+		// start a new file rather than appending after a partial write
+		s.f.Close()
+		s.f = nil
+	}
+	return err
+}
+
+// rotate closes the current file and creates a file starting at rev. The
+// files of the same revision written before, e.g. before a restart or a
+// failed write, are kept and the new file takes the next sequence number,
+// so the files sort in the order they were written.
+func (s *fileSink) rotate(rev int64) error {
+	if s.f != nil {
+		if err := s.f.Close(); err != nil {
+			return err
+		}
+		s.f = nil
+	}
+	if err := os.MkdirAll(s.dir, fileutil.PrivateDirMode); err != nil {
+		return err
+	}
+	for seq := 0; ; seq++ {
+		name := filepath.Join(s.dir, fmt.Sprintf("events-%020d-%04d%s", rev, seq, s.format.ext()))
+		f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, fileutil.PrivateFileMode)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		s.f, s.size = f, 0
+		return nil
+	}
+}
+
+func (s *fileSink) Close() error {
+	if s.f == nil {
+		return nil
+	}
+	err := s.f.Close()
+	s.f = nil
+	return err
+}
+
+// webhookSink posts the events to a URL.
+type webhookSink struct {
+	url string
+	cli *http.Client
+}
+
+// NewWebhookSink creates a sink posting the events in the NDJSON format to
+// url. The events are delivered once the response has a 2xx status.
+func NewWebhookSink(url string, timeout time.Duration) Sink {
+	return &webhookSink{url: url, cli: &http.Client{Timeout: timeout}}
+}
+
+func (s *webhookSink) Name() string { return "webhook" }
+
+func (s *webhookSink) Write(evs []*mvccpb.Event) error {
+	var buf bytes.Buffer
+	if err := FormatNDJSON.encode(&buf, evs); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(context.TODO(), http.MethodPost, s.url, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	resp, err := s.cli.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook %s responded %s", s.url, resp.Status)
+	}
+	return nil
+}
+
+func (s *webhookSink) Close() error {
+	s.cli.CloseIdleConnections()
+	return nil
+}
@@ -28,8 +28,14 @@ import (
 	"go.etcd.io/etcd/client/pkg/v3/transport"
 	"go.etcd.io/etcd/client/pkg/v3/types"
 	"go.etcd.io/etcd/pkg/v3/netutil"
+	"go.etcd.io/etcd/server/v3/audit"
+	"go.etcd.io/etcd/server/v3/auth"
+	"go.etcd.io/etcd/server/v3/cdc"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/v3discovery"
+	"go.etcd.io/etcd/server/v3/storage/backend/engine"
 	"go.etcd.io/etcd/server/v3/storage/datadir"
+	"go.etcd.io/etcd/server/v3/storage/encryption"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
 
 	bolt "go.etcd.io/bbolt"
 )
@@ -126,6 +132,26 @@ type ServerConfig struct {
 	QuotaBackendBytes       int64
 	MaxTxnOps               uint
 
+	// CompactionRetentionRules keep the revisions of the keys under their
+	// prefixes from being compacted.
+	CompactionRetentionRules []mvcc.RetentionRule
+
+	// BackendCompression compresses the revisions written to the backend.
+	BackendCompression mvcc.CompressionType
+	// BackendCompressionMinBytes is the size in bytes under which the
+	// revisions are written uncompressed.
+	BackendCompressionMinBytes int
+	// BackendEngine is the engine storing the backend.
+	BackendEngine engine.Type
+	// EncryptionCipher, if set, encrypts the revisions written to the
+	// backend, the WAL entries and the snap files.
+	EncryptionCipher encryption.Cipher
+	// WatchEventCacheRevisions is the number of latest revisions whose events
+	// are cached to sync the unsynced watchers. 0 disables the cache.
+	WatchEventCacheRevisions int
+	// WatchEventCacheBytes bounds the size of the events in the watch event cache.
+	WatchEventCacheBytes int
+
 	// MaxRequestBytes is the maximum request size to send over raft.
 	MaxRequestBytes uint
 
@@ -145,6 +171,31 @@ type ServerConfig struct {
 	BcryptCost uint
 	TokenTTL   uint
 
+	// AuthAuthenticator specifies the authenticator of users which are not stored in etcd.
+	AuthAuthenticator string
+	// Authenticator is used instead of AuthAuthenticator if set.
+	Authenticator auth.Authenticator
+
+	// AuditLogOutput is the file the audit log is written to, if any.
+	AuditLogOutput          string
+	AuditLogLevel           string
+	AuditLogMaxSize         int
+	AuditLogMaxBackups      int
+	AuditLogExcludePrefixes []string
+	// AuditSink is used instead of AuditLogOutput if set.
+	AuditSink audit.Sink
+
+	// CDCPrefixes are the prefixes of the keys whose events are captured.
+	CDCPrefixes []string
+	// CDCFileDir is the directory the captured events are written to, if any.
+	CDCFileDir     string
+	CDCFileFormat  cdc.Format
+	CDCFileMaxSize int
+	// CDCWebhookURL is the URL the captured events are posted to, if any.
+	CDCWebhookURL string
+	// CDCSinks receive the captured events in addition.
+	CDCSinks []cdc.Sink
+
 	// InitialCorruptCheck is true to check data corruption on boot
 	// before serving any peer/client traffic.
 	InitialCorruptCheck     bool
@@ -201,6 +252,16 @@ type ServerConfig struct {
 	// consider running defrag during bootstrap. Needs to be set to non-zero value to take effect.
 	ExperimentalBootstrapDefragThresholdMegabytes uint `json:"experimental-bootstrap-defrag-threshold-megabytes"`
 
+	// ExperimentalAutoDefragThresholdMegabytes is the minimum number of megabytes needed to be freed for etcd server to
+	// defragment automatically, one member of the cluster at a time. Needs to be set to non-zero value to take effect.
+	ExperimentalAutoDefragThresholdMegabytes uint `json:"experimental-auto-defrag-threshold-megabytes"`
+	// ExperimentalAutoDefragCheckInterval is the interval between two checks of the space to be freed by auto defragmentation.
+	ExperimentalAutoDefragCheckInterval time.Duration `json:"experimental-auto-defrag-check-interval"`
+	// ExperimentalAutoDefragOnline enables auto defragmentation without blocking reads and writes during the copy.
+	ExperimentalAutoDefragOnline bool `json:"experimental-auto-defrag-online"`
+	// ExperimentalAutoDefragLeaderPolicy is the auto defragmentation policy for the leader, either "skip" or "move".
+	ExperimentalAutoDefragLeaderPolicy string `json:"experimental-auto-defrag-leader-policy"`
+
 	// ExperimentalMaxLearners sets a limit to the number of learner members that can exist in the cluster membership.
 	ExperimentalMaxLearners int `json:"experimental-max-learners"`
 
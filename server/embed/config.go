@@ -36,11 +36,18 @@ import (
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.etcd.io/etcd/pkg/v3/flags"
 	"go.etcd.io/etcd/pkg/v3/netutil"
+	"go.etcd.io/etcd/server/v3/audit"
+	"go.etcd.io/etcd/server/v3/auth"
+	"go.etcd.io/etcd/server/v3/cdc"
 	"go.etcd.io/etcd/server/v3/config"
 	"go.etcd.io/etcd/server/v3/etcdserver"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/membership"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/v3compactor"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3defrag"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/v3discovery"
+	"go.etcd.io/etcd/server/v3/storage/backend/engine"
+	"go.etcd.io/etcd/server/v3/storage/encryption"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
 
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
@@ -91,6 +98,15 @@ const (
 	// Compress   = false // compress the rotated log in gzip format
 	DefaultLogRotationConfig = `{"maxsize": 100, "maxage": 0, "maxbackups": 0, "localtime": false, "compress": false}`
 
+	// DefaultAuditLogMaxSize is the default size in megabytes of the audit log file before it is rotated.
+	DefaultAuditLogMaxSize = 100
+
+	// DefaultCDCFileMaxSize is the default size in megabytes of a file of captured events before a new one is started.
+	DefaultCDCFileMaxSize = 100
+
+	// DefaultAutoDefragCheckInterval is the default interval between two checks of the space to be freed by auto defragmentation.
+	DefaultAutoDefragCheckInterval = 5 * time.Minute
+
 	// ExperimentalDistributedTracingAddress is the default collector address.
 	ExperimentalDistributedTracingAddress = "localhost:4317"
 	// ExperimentalDistributedTracingServiceName is the default etcd service name.
@@ -325,6 +341,46 @@ type Config struct {
 	// AuthTokenTTL in seconds of the simple token
 	AuthTokenTTL uint `json:"auth-token-ttl"`
 
+	// AuthAuthenticator specifies the authenticator and its options for users which
+	// are not stored in etcd, e.g. "jwt,jwks-file=jwks.json,issuer=https://issuer.example.com"
+	// or "ldap,directory-file=directory.json". Such users authenticate with their
+	// credentials as the password and are granted the roles given by the authenticator.
+	AuthAuthenticator string `json:"auth-authenticator"`
+
+	// Authenticator, if set, is used instead of the authenticator given by AuthAuthenticator.
+	Authenticator auth.Authenticator `json:"-"`
+
+	// AuditLogOutput is the path of the file the audit log is written to as JSON lines.
+	// The audit log is disabled if neither AuditLogOutput nor AuditSink is set.
+	AuditLogOutput string `json:"audit-log-output"`
+	// AuditLogLevel configures the operations recorded in the audit log. Only supports
+	// admin (authentication, membership and maintenance), write (admin and mutations)
+	// or read (all). Default 'write'.
+	AuditLogLevel string `json:"audit-log-level"`
+	// AuditLogMaxSize is the size in megabytes of the audit log file before it is rotated.
+	AuditLogMaxSize int `json:"audit-log-max-size"`
+	// AuditLogMaxBackups is the number of rotated audit log files to retain, 0 to retain all.
+	AuditLogMaxBackups int `json:"audit-log-max-backups"`
+	// AuditLogExcludePrefixes lists the key prefixes whose operations are not recorded.
+	AuditLogExcludePrefixes []string `json:"audit-log-exclude-prefixes"`
+	// AuditSink, if set, receives the audit events instead of AuditLogOutput.
+	AuditSink audit.Sink `json:"-"`
+
+	// ExperimentalCDCPrefixes lists the prefixes of the keys whose committed events are captured, all the keys if empty.
+	ExperimentalCDCPrefixes []string `json:"experimental-cdc-prefixes"`
+	// ExperimentalCDCFileDir is the directory the captured events are appended to, in files named after the revision
+	// of their first event. Only the leader delivers the captured events, so they are spread across the directories
+	// of the members the leadership moved to. The change data capture is disabled if no sink is configured.
+	ExperimentalCDCFileDir string `json:"experimental-cdc-file-dir"`
+	// ExperimentalCDCFileFormat is the format of the files of captured events, either "ndjson" or "protobuf".
+	ExperimentalCDCFileFormat string `json:"experimental-cdc-file-format"`
+	// ExperimentalCDCFileMaxSize is the size in megabytes of a file of captured events before a new one is started.
+	ExperimentalCDCFileMaxSize int `json:"experimental-cdc-file-max-size"`
+	// ExperimentalCDCWebhookURL is the URL the captured events are posted to as NDJSON.
+	ExperimentalCDCWebhookURL string `json:"experimental-cdc-webhook-url"`
+	// ExperimentalCDCSinks, if set, receive the captured events in addition.
+	ExperimentalCDCSinks []cdc.Sink `json:"-"`
+
 	ExperimentalInitialCorruptCheck     bool          `json:"experimental-initial-corrupt-check"`
 	ExperimentalCorruptCheckTime        time.Duration `json:"experimental-corrupt-check-time"`
 	ExperimentalCompactHashCheckEnabled bool          `json:"experimental-compact-hash-check-enabled"`
@@ -341,12 +397,46 @@ type Config struct {
 	// ExperimentalCompactionSleepInterval is the sleep interval between every etcd compaction loop.
 	ExperimentalCompactionSleepInterval     time.Duration `json:"experimental-compaction-sleep-interval"`
 	ExperimentalWatchProgressNotifyInterval time.Duration `json:"experimental-watch-progress-notify-interval"`
+	// ExperimentalWatchEventCacheRevisions is the number of latest revisions whose events are kept in memory to sync
+	// the watchers catching up on them without reading the backend. 0 disables the cache.
+	ExperimentalWatchEventCacheRevisions int `json:"experimental-watch-event-cache-revisions"`
+	// ExperimentalWatchEventCacheBytes bounds the size in bytes of the events kept in the watch event cache.
+	ExperimentalWatchEventCacheBytes int `json:"experimental-watch-event-cache-bytes"`
+	// ExperimentalCompactionRetentionRules keep revisions from being compacted. Each rule is either "<prefix>=<versions>"
+	// to keep the latest revisions of each key under the prefix, or "<prefix>=<duration>" to keep the revisions written
+	// within the duration. The rules of the member proposing a compaction are replicated with it to every member.
+	ExperimentalCompactionRetentionRules []string `json:"experimental-compaction-retention-rules"`
+	// ExperimentalBackendCompression is the codec compressing the revisions written to the key bucket of the backend,
+	// either "none" or "flate". The revisions are read whether they are compressed or not.
+	ExperimentalBackendCompression string `json:"experimental-backend-compression"`
+	// ExperimentalBackendCompressionMinBytes is the size in bytes under which the revisions are written uncompressed.
+	ExperimentalBackendCompressionMinBytes int `json:"experimental-backend-compression-min-bytes"`
+	// ExperimentalBackendEngine is the engine storing the backend, either "bolt" or "memory". The memory engine keeps
+	// the backend in memory only, for ephemeral clusters: its data is lost when the member stops, which has to be
+	// added back to the cluster instead of restarted.
+	ExperimentalBackendEngine string `json:"experimental-backend-engine"`
+	// ExperimentalEncryptionKeyFile is the file of the keys encrypting the revisions written to the backend, the WAL
+	// entries and the snap files, one "<id>:<base64 encoded 32 byte key>" per line. The last key encrypts the data
+	// written, the others decrypt the data written before they were rotated.
+	ExperimentalEncryptionKeyFile string `json:"experimental-encryption-key-file"`
+	// EncryptionKMS, if set, is used instead of the keys of ExperimentalEncryptionKeyFile.
+	EncryptionKMS encryption.KMS `json:"-"`
 	// ExperimentalWarningApplyDuration is the time duration after which a warning is generated if applying request
 	// takes more time than this value.
 	ExperimentalWarningApplyDuration time.Duration `json:"experimental-warning-apply-duration"`
 	// ExperimentalBootstrapDefragThresholdMegabytes is the minimum number of megabytes needed to be freed for etcd server to
 	// consider running defrag during bootstrap. Needs to be set to non-zero value to take effect.
 	ExperimentalBootstrapDefragThresholdMegabytes uint `json:"experimental-bootstrap-defrag-threshold-megabytes"`
+	// ExperimentalAutoDefragThresholdMegabytes is the minimum number of megabytes needed to be freed for etcd server to
+	// defragment automatically, one member of the cluster at a time. Needs to be set to non-zero value to take effect.
+	ExperimentalAutoDefragThresholdMegabytes uint `json:"experimental-auto-defrag-threshold-megabytes"`
+	// ExperimentalAutoDefragCheckInterval is the interval between two checks of the space to be freed by auto defragmentation.
+	ExperimentalAutoDefragCheckInterval time.Duration `json:"experimental-auto-defrag-check-interval"`
+	// ExperimentalAutoDefragOnline enables auto defragmentation without blocking reads and writes during the copy.
+	ExperimentalAutoDefragOnline bool `json:"experimental-auto-defrag-online"`
+	// ExperimentalAutoDefragLeaderPolicy is the auto defragmentation policy for the leader. Only supports "skip", which
+	// never defragments the leader, or "move", which transfers the leadership before defragmenting.
+	ExperimentalAutoDefragLeaderPolicy string `json:"experimental-auto-defrag-leader-policy"`
 	// WarningUnaryRequestDuration is the time duration after which a warning is generated if applying
 	// unary request takes more time than this value.
 	WarningUnaryRequestDuration time.Duration `json:"warning-unary-request-duration"`
@@ -513,6 +603,12 @@ func NewConfig() *Config {
 		BcryptCost:   uint(bcrypt.DefaultCost),
 		AuthTokenTTL: 300,
 
+		AuditLogLevel:   string(audit.DefaultLevel),
+		AuditLogMaxSize: DefaultAuditLogMaxSize,
+
+		ExperimentalCDCFileFormat:  string(cdc.DefaultFormat),
+		ExperimentalCDCFileMaxSize: DefaultCDCFileMaxSize,
+
 		PreVote: true,
 
 		loggerMu:              new(sync.RWMutex),
@@ -532,6 +628,15 @@ func NewConfig() *Config {
 		ExperimentalCompactHashCheckEnabled: false,
 		ExperimentalCompactHashCheckTime:    time.Minute,
 
+		ExperimentalAutoDefragCheckInterval: DefaultAutoDefragCheckInterval,
+		ExperimentalAutoDefragLeaderPolicy:  v3defrag.LeaderPolicySkip,
+
+		ExperimentalBackendCompression:         string(mvcc.CompressionNone),
+		ExperimentalBackendEngine:              string(engine.TypeBolt),
+		ExperimentalBackendCompressionMinBytes: mvcc.DefaultCompressionMinSize,
+
+		ExperimentalWatchEventCacheBytes: mvcc.DefaultEventCacheBytes,
+
 		V2Deprecation: config.V2_DEPR_DEFAULT,
 
 		DiscoveryCfg: v3discovery.DiscoveryConfig{
@@ -762,6 +867,13 @@ func (cfg *Config) Validate() error {
 		return fmt.Errorf("unknown auto-compaction-mode %q", cfg.AutoCompactionMode)
 	}
 
+	if _, err := audit.ParseLevel(cfg.AuditLogLevel); err != nil {
+		return err
+	}
+	if cfg.AuditLogMaxSize <= 0 {
+		return fmt.Errorf("--audit-log-max-size must be >0 (set to %dMB)", cfg.AuditLogMaxSize)
+	}
+
 	// Validate distributed tracing configuration but only if enabled.
 	if cfg.ExperimentalEnableDistributedTracing {
 		if err := validateTracingConfig(cfg.ExperimentalDistributedTracingSamplingRatePerMillion); err != nil {
@@ -781,6 +893,55 @@ func (cfg *Config) Validate() error {
 		return fmt.Errorf("--experimental-compact-hash-check-time must be >0 (set to %v)", cfg.ExperimentalCompactHashCheckTime)
 	}
 
+	if _, err := parseCompactionRetentionRules(cfg.ExperimentalCompactionRetentionRules); err != nil {
+		return err
+	}
+
+	if _, err := cdc.ParseFormat(cfg.ExperimentalCDCFileFormat); err != nil {
+		return fmt.Errorf("--experimental-cdc-file-format: %w", err)
+	}
+	if cfg.ExperimentalCDCFileMaxSize <= 0 {
+		return fmt.Errorf("--experimental-cdc-file-max-size must be >0 (set to %dMB)", cfg.ExperimentalCDCFileMaxSize)
+	}
+	if cfg.ExperimentalCDCWebhookURL != "" {
+		if u, err := url.Parse(cfg.ExperimentalCDCWebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("--experimental-cdc-webhook-url must be an http or https URL (set to %q)", cfg.ExperimentalCDCWebhookURL)
+		}
+	}
+
+	if _, err := mvcc.ParseCompressionType(cfg.ExperimentalBackendCompression); err != nil {
+		return fmt.Errorf("--experimental-backend-compression: %w", err)
+	}
+	if cfg.ExperimentalBackendCompressionMinBytes <= 0 {
+		return fmt.Errorf("--experimental-backend-compression-min-bytes must be >0 (set to %d)", cfg.ExperimentalBackendCompressionMinBytes)
+	}
+
+	if _, err := engine.ParseType(cfg.ExperimentalBackendEngine); err != nil {
+		return fmt.Errorf("--experimental-backend-engine: %w", err)
+	}
+
+	if cfg.ExperimentalWatchEventCacheRevisions < 0 {
+		return fmt.Errorf("--experimental-watch-event-cache-revisions must be >=0 (set to %d)", cfg.ExperimentalWatchEventCacheRevisions)
+	}
+	if cfg.ExperimentalWatchEventCacheBytes <= 0 {
+		return fmt.Errorf("--experimental-watch-event-cache-bytes must be >0 (set to %d)", cfg.ExperimentalWatchEventCacheBytes)
+	}
+
+	if cfg.ExperimentalEncryptionKeyFile != "" && cfg.EncryptionKMS != nil {
+		return fmt.Errorf("--experimental-encryption-key-file cannot be set with EncryptionKMS")
+	}
+
+	if cfg.ExperimentalAutoDefragThresholdMegabytes != 0 {
+		if cfg.ExperimentalAutoDefragCheckInterval <= 0 {
+			return fmt.Errorf("--experimental-auto-defrag-check-interval must be >0 (set to %v)", cfg.ExperimentalAutoDefragCheckInterval)
+		}
+		switch cfg.ExperimentalAutoDefragLeaderPolicy {
+		case v3defrag.LeaderPolicySkip, v3defrag.LeaderPolicyMove:
+		default:
+			return fmt.Errorf("unknown --experimental-auto-defrag-leader-policy %q (only supports %q or %q)", cfg.ExperimentalAutoDefragLeaderPolicy, v3defrag.LeaderPolicySkip, v3defrag.LeaderPolicyMove)
+		}
+	}
+
 	// If `--name` isn't configured, then multiple members may have the same "default" name.
 	// When adding a new member with the "default" name as well, etcd may regards its peerURL
 	// as one additional peerURL of the existing member which has the same "default" name,
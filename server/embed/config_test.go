@@ -29,6 +29,7 @@ import (
 	"go.etcd.io/etcd/client/pkg/v3/srv"
 	"go.etcd.io/etcd/client/pkg/v3/transport"
 	"go.etcd.io/etcd/client/pkg/v3/types"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
 
 	"sigs.k8s.io/yaml"
 )
@@ -219,6 +220,37 @@ func TestAutoCompactionModeParse(t *testing.T) {
 	}
 }
 
+func TestCompactionRetentionRulesParse(t *testing.T) {
+	tests := []struct {
+		rules  []string
+		wrules []mvcc.RetentionRule
+		werr   bool
+	}{
+		{rules: nil, wrules: nil},
+		{
+			rules: []string{"/config/=10", "/audit/=168h", "/config/=1h"},
+			wrules: []mvcc.RetentionRule{
+				{Prefix: "/audit/", Duration: 168 * time.Hour},
+				{Prefix: "/config/", Versions: 10, Duration: time.Hour},
+			},
+		},
+		{rules: []string{"/a=b=5"}, wrules: []mvcc.RetentionRule{{Prefix: "/a=b", Versions: 5}}},
+		{rules: []string{"=1"}, wrules: []mvcc.RetentionRule{{Versions: 1}}},
+		{rules: []string{"/config/"}, werr: true},
+		{rules: []string{"/config/=0"}, werr: true},
+		{rules: []string{"/config/=-1s"}, werr: true},
+		{rules: []string{"/config/=7d"}, werr: true},
+		{rules: []string{"/config/=1", "/config/=2"}, werr: true},
+	}
+	for i, tt := range tests {
+		rules, err := parseCompactionRetentionRules(tt.rules)
+		if (err != nil) != tt.werr {
+			t.Errorf("#%d: err = %v, want %v", i, err, tt.werr)
+		}
+		assert.Equal(t, tt.wrules, rules, "#%d", i)
+	}
+}
+
 func TestPeerURLsMapAndTokenFromSRV(t *testing.T) {
 	defer func() { getCluster = srv.GetCluster }()
 
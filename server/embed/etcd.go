@@ -34,11 +34,15 @@ import (
 	"go.etcd.io/etcd/client/pkg/v3/types"
 	"go.etcd.io/etcd/pkg/v3/debugutil"
 	runtimeutil "go.etcd.io/etcd/pkg/v3/runtime"
+	"go.etcd.io/etcd/server/v3/cdc"
 	"go.etcd.io/etcd/server/v3/config"
 	"go.etcd.io/etcd/server/v3/etcdserver"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/etcdhttp"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/rafthttp"
 	"go.etcd.io/etcd/server/v3/storage"
+	"go.etcd.io/etcd/server/v3/storage/backend/engine"
+	"go.etcd.io/etcd/server/v3/storage/encryption"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
 	"go.etcd.io/etcd/server/v3/verify"
 
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
@@ -159,6 +163,28 @@ func StartEtcd(inCfg *Config) (e *Etcd, err error) {
 	if err != nil {
 		return e, err
 	}
+	compactionRetentionRules, err := parseCompactionRetentionRules(cfg.ExperimentalCompactionRetentionRules)
+	if err != nil {
+		return e, err
+	}
+	backendCompression, err := mvcc.ParseCompressionType(cfg.ExperimentalBackendCompression)
+	if err != nil {
+		return e, err
+	}
+	backendEngine, err := engine.ParseType(cfg.ExperimentalBackendEngine)
+	if err != nil {
+		return e, err
+	}
+	encryptionKMS := cfg.EncryptionKMS
+	if cfg.ExperimentalEncryptionKeyFile != "" {
+		if encryptionKMS, err = encryption.NewLocalKMS(cfg.ExperimentalEncryptionKeyFile); err != nil {
+			return e, err
+		}
+	}
+	var encryptionCipher encryption.Cipher
+	if encryptionKMS != nil {
+		encryptionCipher = encryption.NewEnvelope(encryptionKMS)
+	}
 
 	backendFreelistType := parseBackendFreelistType(cfg.BackendFreelistType)
 
@@ -198,6 +224,20 @@ func StartEtcd(inCfg *Config) (e *Etcd, err error) {
 		AuthToken:                                cfg.AuthToken,
 		BcryptCost:                               cfg.BcryptCost,
 		TokenTTL:                                 cfg.AuthTokenTTL,
+		AuthAuthenticator:                        cfg.AuthAuthenticator,
+		Authenticator:                            cfg.Authenticator,
+		AuditLogOutput:                           cfg.AuditLogOutput,
+		AuditLogLevel:                            cfg.AuditLogLevel,
+		AuditLogMaxSize:                          cfg.AuditLogMaxSize,
+		AuditLogMaxBackups:                       cfg.AuditLogMaxBackups,
+		AuditLogExcludePrefixes:                  cfg.AuditLogExcludePrefixes,
+		AuditSink:                                cfg.AuditSink,
+		CDCPrefixes:                              cfg.ExperimentalCDCPrefixes,
+		CDCFileDir:                               cfg.ExperimentalCDCFileDir,
+		CDCFileFormat:                            cdc.Format(cfg.ExperimentalCDCFileFormat),
+		CDCFileMaxSize:                           cfg.ExperimentalCDCFileMaxSize,
+		CDCWebhookURL:                            cfg.ExperimentalCDCWebhookURL,
+		CDCSinks:                                 cfg.ExperimentalCDCSinks,
 		CORS:                                     cfg.CORS,
 		HostWhitelist:                            cfg.HostWhitelist,
 		InitialCorruptCheck:                      cfg.ExperimentalInitialCorruptCheck,
@@ -214,13 +254,24 @@ func StartEtcd(inCfg *Config) (e *Etcd, err error) {
 		LeaseCheckpointPersist:                   cfg.ExperimentalEnableLeaseCheckpointPersist,
 		CompactionBatchLimit:                     cfg.ExperimentalCompactionBatchLimit,
 		CompactionSleepInterval:                  cfg.ExperimentalCompactionSleepInterval,
+		CompactionRetentionRules:                 compactionRetentionRules,
+		BackendCompression:                       backendCompression,
+		BackendCompressionMinBytes:               cfg.ExperimentalBackendCompressionMinBytes,
+		BackendEngine:                            backendEngine,
+		EncryptionCipher:                         encryptionCipher,
 		WatchProgressNotifyInterval:              cfg.ExperimentalWatchProgressNotifyInterval,
+		WatchEventCacheRevisions:                 cfg.ExperimentalWatchEventCacheRevisions,
+		WatchEventCacheBytes:                     cfg.ExperimentalWatchEventCacheBytes,
 		DowngradeCheckTime:                       cfg.ExperimentalDowngradeCheckTime,
 		WarningApplyDuration:                     cfg.ExperimentalWarningApplyDuration,
 		WarningUnaryRequestDuration:              cfg.WarningUnaryRequestDuration,
 		ExperimentalMemoryMlock:                  cfg.ExperimentalMemoryMlock,
 		ExperimentalTxnModeWriteWithSharedBuffer: cfg.ExperimentalTxnModeWriteWithSharedBuffer,
 		ExperimentalBootstrapDefragThresholdMegabytes: cfg.ExperimentalBootstrapDefragThresholdMegabytes,
+		ExperimentalAutoDefragThresholdMegabytes:      cfg.ExperimentalAutoDefragThresholdMegabytes,
+		ExperimentalAutoDefragCheckInterval:           cfg.ExperimentalAutoDefragCheckInterval,
+		ExperimentalAutoDefragOnline:                  cfg.ExperimentalAutoDefragOnline,
+		ExperimentalAutoDefragLeaderPolicy:            cfg.ExperimentalAutoDefragLeaderPolicy,
 		ExperimentalMaxLearners:                       cfg.ExperimentalMaxLearners,
 		V2Deprecation:                                 cfg.V2DeprecationEffective(),
 	}
@@ -392,11 +443,14 @@ func (e *Etcd) Close() {
 	lg.Info("closing etcd server", fields...)
 	defer func() {
 		lg.Info("closed etcd server", fields...)
-		verify.MustVerifyIfEnabled(verify.Config{
-			Logger:     lg,
-			DataDir:    e.cfg.Dir,
-			ExactIndex: false,
-		})
+		// the in-memory backend is not persisted to be verified
+		if engine.Type(e.cfg.ExperimentalBackendEngine) != engine.TypeMemory {
+			verify.MustVerifyIfEnabled(verify.Config{
+				Logger:     lg,
+				DataDir:    e.cfg.Dir,
+				ExactIndex: false,
+			})
+		}
 		lg.Sync()
 	}()
 
@@ -811,3 +865,35 @@ func parseCompactionRetention(mode, retention string) (ret time.Duration, err er
 	}
 	return ret, nil
 }
+
+// parseCompactionRetentionRules parses the compaction retention rules of the
+// form "<prefix>=<versions>" or "<prefix>=<duration>". The rules on the same
+// prefix are merged.
+func parseCompactionRetentionRules(rules []string) ([]mvcc.RetentionRule, error) {
+	var rs []mvcc.RetentionRule
+	for _, rule := range rules {
+		i := strings.LastIndex(rule, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid compaction retention rule %q (expected <prefix>=<versions> or <prefix>=<duration>)", rule)
+		}
+		prefix, value := rule[:i], rule[i+1:]
+		j := sort.Search(len(rs), func(j int) bool { return rs[j].Prefix >= prefix })
+		if j == len(rs) || rs[j].Prefix != prefix {
+			rs = append(rs[:j], append([]mvcc.RetentionRule{{Prefix: prefix}}, rs[j:]...)...)
+		}
+		r := &rs[j]
+		if versions, err := strconv.ParseInt(value, 10, 64); err == nil {
+			if versions <= 0 || r.Versions != 0 {
+				return nil, fmt.Errorf("invalid compaction retention rule %q (expected a single number of versions >0 per prefix)", rule)
+			}
+			r.Versions = versions
+			continue
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 || r.Duration != 0 {
+			return nil, fmt.Errorf("invalid compaction retention rule %q (expected a single duration >0 per prefix)", rule)
+		}
+		r.Duration = d
+	}
+	return rs, nil
+}
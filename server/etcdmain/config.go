@@ -257,6 +257,21 @@ func newConfig() *config {
 	fs.StringVar(&cfg.ec.AuthToken, "auth-token", cfg.ec.AuthToken, "Specify auth token specific options.")
 	fs.UintVar(&cfg.ec.BcryptCost, "bcrypt-cost", cfg.ec.BcryptCost, "Specify bcrypt algorithm cost factor for auth password hashing.")
 	fs.UintVar(&cfg.ec.AuthTokenTTL, "auth-token-ttl", cfg.ec.AuthTokenTTL, "The lifetime in seconds of the auth token.")
+	fs.StringVar(&cfg.ec.AuthAuthenticator, "auth-authenticator", cfg.ec.AuthAuthenticator, "Specify authenticator specific options for users not stored in etcd.")
+
+	// audit
+	fs.StringVar(&cfg.ec.AuditLogOutput, "audit-log-output", "", "Path of the file to write the audit log to. Empty disables the audit log.")
+	fs.StringVar(&cfg.ec.AuditLogLevel, "audit-log-level", cfg.ec.AuditLogLevel, "Configures the operations recorded in the audit log. Only supports admin, write or read. Default 'write'.")
+	fs.IntVar(&cfg.ec.AuditLogMaxSize, "audit-log-max-size", cfg.ec.AuditLogMaxSize, "Size in megabytes of the audit log file before it is rotated.")
+	fs.IntVar(&cfg.ec.AuditLogMaxBackups, "audit-log-max-backups", cfg.ec.AuditLogMaxBackups, "Number of rotated audit log files to retain (0 is unlimited).")
+	fs.Var(flags.NewUniqueStringsValue(""), "audit-log-exclude-prefixes", "Comma-separated key prefixes whose operations are not recorded in the audit log.")
+
+	// change data capture
+	fs.Var(flags.NewUniqueStringsValue(""), "experimental-cdc-prefixes", "Comma-separated prefixes of the keys whose committed events are captured. Empty captures all the keys.")
+	fs.StringVar(&cfg.ec.ExperimentalCDCFileDir, "experimental-cdc-file-dir", "", "Path of the directory to append the captured events to. Empty disables the file sink.")
+	fs.StringVar(&cfg.ec.ExperimentalCDCFileFormat, "experimental-cdc-file-format", cfg.ec.ExperimentalCDCFileFormat, "Format of the files of captured events, either 'ndjson' or 'protobuf'.")
+	fs.IntVar(&cfg.ec.ExperimentalCDCFileMaxSize, "experimental-cdc-file-max-size", cfg.ec.ExperimentalCDCFileMaxSize, "Size in megabytes of a file of captured events before a new one is started.")
+	fs.StringVar(&cfg.ec.ExperimentalCDCWebhookURL, "experimental-cdc-webhook-url", "", "URL to post the captured events to as NDJSON. Empty disables the webhook sink.")
 
 	// gateway
 	fs.BoolVar(&cfg.ec.EnableGRPCGateway, "enable-grpc-gateway", cfg.ec.EnableGRPCGateway, "Enable GRPC gateway.")
@@ -272,7 +287,14 @@ func newConfig() *config {
 	fs.BoolVar(&cfg.ec.ExperimentalEnableLeaseCheckpointPersist, "experimental-enable-lease-checkpoint-persist", false, "Enable persisting remainingTTL to prevent indefinite auto-renewal of long lived leases. Always enabled in v3.6. Should be used to ensure smooth upgrade from v3.5 clusters with this feature enabled. Requires experimental-enable-lease-checkpoint to be enabled.")
 	fs.IntVar(&cfg.ec.ExperimentalCompactionBatchLimit, "experimental-compaction-batch-limit", cfg.ec.ExperimentalCompactionBatchLimit, "Sets the maximum revisions deleted in each compaction batch.")
 	fs.DurationVar(&cfg.ec.ExperimentalCompactionSleepInterval, "experimental-compaction-sleep-interval", cfg.ec.ExperimentalCompactionSleepInterval, "Sets the sleep interval between each compaction batch.")
+	fs.Var(flags.NewUniqueStringsValue(""), "experimental-compaction-retention-rules", "Comma-separated rules keeping revisions from being compacted, either '<prefix>=<versions>' to keep the latest revisions of each key under the prefix or '<prefix>=<duration>' to keep the revisions written within the duration.")
+	fs.StringVar(&cfg.ec.ExperimentalBackendCompression, "experimental-backend-compression", cfg.ec.ExperimentalBackendCompression, "Codec compressing the revisions written to the backend, either 'none' or 'flate'. The revisions are read whether they are compressed or not.")
+	fs.IntVar(&cfg.ec.ExperimentalBackendCompressionMinBytes, "experimental-backend-compression-min-bytes", cfg.ec.ExperimentalBackendCompressionMinBytes, "Size in bytes under which the revisions are written uncompressed.")
+	fs.StringVar(&cfg.ec.ExperimentalBackendEngine, "experimental-backend-engine", cfg.ec.ExperimentalBackendEngine, "Engine storing the backend, either 'bolt' or 'memory'. The memory engine loses the data when the member stops, which cannot restart.")
+	fs.StringVar(&cfg.ec.ExperimentalEncryptionKeyFile, "experimental-encryption-key-file", cfg.ec.ExperimentalEncryptionKeyFile, "Path to the file of the keys encrypting the revisions written to the backend, the WAL entries and the snap files. The last key encrypts, the others only decrypt.")
 	fs.DurationVar(&cfg.ec.ExperimentalWatchProgressNotifyInterval, "experimental-watch-progress-notify-interval", cfg.ec.ExperimentalWatchProgressNotifyInterval, "Duration of periodic watch progress notifications.")
+	fs.IntVar(&cfg.ec.ExperimentalWatchEventCacheRevisions, "experimental-watch-event-cache-revisions", cfg.ec.ExperimentalWatchEventCacheRevisions, "Number of latest revisions whose events are cached in memory to sync the watchers catching up on them. 0 disables the cache.")
+	fs.IntVar(&cfg.ec.ExperimentalWatchEventCacheBytes, "experimental-watch-event-cache-bytes", cfg.ec.ExperimentalWatchEventCacheBytes, "Maximum size in bytes of the events kept in the watch event cache.")
 	fs.DurationVar(&cfg.ec.ExperimentalDowngradeCheckTime, "experimental-downgrade-check-time", cfg.ec.ExperimentalDowngradeCheckTime, "Duration of time between two downgrade status check.")
 	fs.DurationVar(&cfg.ec.ExperimentalWarningApplyDuration, "experimental-warning-apply-duration", cfg.ec.ExperimentalWarningApplyDuration, "Time duration after which a warning is generated if request takes more time.")
 	fs.DurationVar(&cfg.ec.WarningUnaryRequestDuration, "warning-unary-request-duration", cfg.ec.WarningUnaryRequestDuration, "Time duration after which a warning is generated if a unary request takes more time.")
@@ -280,6 +302,10 @@ func newConfig() *config {
 	fs.BoolVar(&cfg.ec.ExperimentalMemoryMlock, "experimental-memory-mlock", cfg.ec.ExperimentalMemoryMlock, "Enable to enforce etcd pages (in particular bbolt) to stay in RAM.")
 	fs.BoolVar(&cfg.ec.ExperimentalTxnModeWriteWithSharedBuffer, "experimental-txn-mode-write-with-shared-buffer", true, "Enable the write transaction to use a shared buffer in its readonly check operations.")
 	fs.UintVar(&cfg.ec.ExperimentalBootstrapDefragThresholdMegabytes, "experimental-bootstrap-defrag-threshold-megabytes", 0, "Enable the defrag during etcd server bootstrap on condition that it will free at least the provided threshold of disk space. Needs to be set to non-zero value to take effect.")
+	fs.UintVar(&cfg.ec.ExperimentalAutoDefragThresholdMegabytes, "experimental-auto-defrag-threshold-megabytes", 0, "Enable the automatic defrag of the member, one member of the cluster at a time, whenever it will free at least the provided threshold of disk space. Needs to be set to non-zero value to take effect.")
+	fs.DurationVar(&cfg.ec.ExperimentalAutoDefragCheckInterval, "experimental-auto-defrag-check-interval", cfg.ec.ExperimentalAutoDefragCheckInterval, "Duration between two checks of the disk space freed by the automatic defrag.")
+	fs.BoolVar(&cfg.ec.ExperimentalAutoDefragOnline, "experimental-auto-defrag-online", false, "Enable the automatic defrag to run without blocking reads and writes until the final swap of the database file.")
+	fs.StringVar(&cfg.ec.ExperimentalAutoDefragLeaderPolicy, "experimental-auto-defrag-leader-policy", cfg.ec.ExperimentalAutoDefragLeaderPolicy, "Automatic defrag policy for the leader. Only supports 'skip' (never defragment the leader) or 'move' (transfer the leadership before defragmenting).")
 	fs.IntVar(&cfg.ec.ExperimentalMaxLearners, "experimental-max-learners", membership.DefaultMaxLearners, "Sets the maximum number of learners that can be available in the cluster membership.")
 	fs.DurationVar(&cfg.ec.ExperimentalWaitClusterReadyTimeout, "experimental-wait-cluster-ready-timeout", cfg.ec.ExperimentalWaitClusterReadyTimeout, "Maximum duration to wait for the cluster to be ready.")
 	fs.Uint64Var(&cfg.ec.SnapshotCatchUpEntries, "experimental-snapshot-catchup-entries", cfg.ec.SnapshotCatchUpEntries, "Number of entries for a slow follower to catch up after compacting the the raft storage entries.")
@@ -399,6 +425,9 @@ func (cfg *config) configFromCmdLine() error {
 	cfg.ec.MaxConcurrentStreams = flags.Uint32FromFlag(cfg.cf.flagSet, "max-concurrent-streams")
 
 	cfg.ec.LogOutputs = flags.UniqueStringsFromFlag(cfg.cf.flagSet, "log-outputs")
+	cfg.ec.AuditLogExcludePrefixes = flags.UniqueStringsFromFlag(cfg.cf.flagSet, "audit-log-exclude-prefixes")
+	cfg.ec.ExperimentalCDCPrefixes = flags.UniqueStringsFromFlag(cfg.cf.flagSet, "experimental-cdc-prefixes")
+	cfg.ec.ExperimentalCompactionRetentionRules = flags.UniqueStringsFromFlag(cfg.cf.flagSet, "experimental-compaction-retention-rules")
 
 	cfg.ec.ClusterState = cfg.cf.clusterState.String()
 
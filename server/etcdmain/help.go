@@ -211,6 +211,32 @@ Auth:
     Specify the cost / strength of the bcrypt algorithm for hashing auth passwords. Valid values are between ` + fmt.Sprintf("%d", bcrypt.MinCost) + ` and ` + fmt.Sprintf("%d", bcrypt.MaxCost) + `.
   --auth-token-ttl 300
     Time (in seconds) of the auth-token-ttl.
+  --auth-authenticator ''
+    Specify an authenticator and its options for users not stored in etcd ('jwt' or 'ldap').
+
+Auditing:
+  --audit-log-output ''
+    Path of the file to write the audit log to as JSON lines. Empty disables the audit log.
+  --audit-log-level 'write'
+    Configures the operations recorded in the audit log. Only supports admin (auth, membership and maintenance), write (admin and mutations) or read (all).
+  --audit-log-max-size 100
+    Size in megabytes of the audit log file before it is rotated.
+  --audit-log-max-backups 0
+    Number of rotated audit log files to retain (0 is unlimited).
+  --audit-log-exclude-prefixes ''
+    Comma-separated key prefixes whose operations are not recorded in the audit log.
+
+Change data capture:
+  --experimental-cdc-prefixes ''
+    Comma-separated prefixes of the keys whose committed events are captured. Empty captures all the keys.
+  --experimental-cdc-file-dir ''
+    Path of the directory to append the captured events to, in files named after the revision of their first event. Only the leader delivers the captured events. Empty disables the file sink.
+  --experimental-cdc-file-format 'ndjson'
+    Format of the files of captured events, either 'ndjson' (JSON mapping of mvccpb.Event) or 'protobuf' (varint size prefixed mvccpb.Event).
+  --experimental-cdc-file-max-size 100
+    Size in megabytes of a file of captured events before a new one is started.
+  --experimental-cdc-webhook-url ''
+    URL to post the captured events to as NDJSON. The events are delivered once it responds with a 2xx status. Empty disables the webhook sink.
 
 Profiling and Monitoring:
   --enable-pprof 'false'
@@ -257,16 +283,38 @@ Experimental feature:
     ExperimentalEnableLeaseCheckpoint enables primary lessor to persist lease remainingTTL to prevent indefinite auto-renewal of long lived leases.
   --experimental-compaction-batch-limit 1000
     ExperimentalCompactionBatchLimit sets the maximum revisions deleted in each compaction batch.
+  --experimental-compaction-retention-rules ''
+    Comma-separated rules keeping revisions from being compacted, either '<prefix>=<versions>' to keep the latest revisions of each key under the prefix (e.g. '/config/=10') or '<prefix>=<duration>' to keep the revisions written within the duration (e.g. '/audit/=168h'). The rules of the member proposing a compaction are applied by every member.
+  --experimental-backend-compression 'none'
+    Codec compressing the revisions written to the backend, either 'none' or 'flate'. The revisions are read whether they are compressed or not, so it can be changed at any time.
+  --experimental-backend-compression-min-bytes 256
+    Size in bytes under which the revisions are written uncompressed.
+  --experimental-backend-engine 'bolt'
+    Engine storing the backend, either 'bolt' or 'memory'. The memory engine keeps the backend in memory only, without fsync nor mmap, for ephemeral clusters such as tests: the data is lost when the member stops, which has to be added back to the cluster instead of restarted.
+  --experimental-encryption-key-file ''
+    Path to the file of the keys encrypting the revisions written to the backend, the WAL entries and the snap files, one '<id>:<base64 encoded 32 byte key>' per line. The last key encrypts, the others only decrypt.
   --experimental-peer-skip-client-san-verification 'false'
     Skip verification of SAN field in client certificate for peer connections.
   --experimental-watch-progress-notify-interval '10m'
     Duration of periodical watch progress notification.
+  --experimental-watch-event-cache-revisions 0
+    Number of latest revisions whose events are cached in memory, so that the watchers catching up on them are synced without reading the backend. 0 disables the cache.
+  --experimental-watch-event-cache-bytes 67108864
+    Maximum size in bytes of the events kept in the watch event cache. The oldest revisions are evicted first.
   --experimental-warning-apply-duration '100ms'
     Warning is generated if requests take more than this duration.
   --experimental-txn-mode-write-with-shared-buffer 'true'
     Enable the write transaction to use a shared buffer in its readonly check operations.
   --experimental-bootstrap-defrag-threshold-megabytes
     Enable the defrag during etcd server bootstrap on condition that it will free at least the provided threshold of disk space. Needs to be set to non-zero value to take effect.
+  --experimental-auto-defrag-threshold-megabytes
+    Enable the automatic defrag of the member, one member of the cluster at a time, whenever it will free at least the provided threshold of disk space. Needs to be set to non-zero value to take effect.
+  --experimental-auto-defrag-check-interval '5m'
+    Duration between two checks of the disk space freed by the automatic defrag.
+  --experimental-auto-defrag-online 'false'
+    Enable the automatic defrag to run without blocking reads and writes until the final swap of the database file.
+  --experimental-auto-defrag-leader-policy 'skip'
+    Automatic defrag policy for the leader. Only supports 'skip' (never defragment the leader) or 'move' (transfer the leadership before defragmenting).
   --experimental-warning-unary-request-duration '300ms'
     Set time duration after which a warning is generated if a unary request takes more than this duration. It's deprecated, and will be decommissioned in v3.7. Use --warning-unary-request-duration instead.
   --experimental-max-learners '1'
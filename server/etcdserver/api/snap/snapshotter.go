@@ -29,6 +29,7 @@ import (
 	pioutil "go.etcd.io/etcd/pkg/v3/ioutil"
 	"go.etcd.io/etcd/pkg/v3/pbutil"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/snap/snappb"
+	"go.etcd.io/etcd/server/v3/storage/encryption"
 	"go.etcd.io/etcd/server/v3/storage/wal/walpb"
 	"go.etcd.io/raft/v3"
 	"go.etcd.io/raft/v3/raftpb"
@@ -53,6 +54,8 @@ var (
 type Snapshotter struct {
 	lg  *zap.Logger
 	dir string
+	// cipher encrypts the snapshots saved and decrypts the snapshots loaded.
+	cipher encryption.Cipher
 }
 
 func New(lg *zap.Logger, dir string) *Snapshotter {
@@ -65,6 +68,12 @@ func New(lg *zap.Logger, dir string) *Snapshotter {
 	}
 }
 
+// SetCipher sets the cipher encrypting the snapshots saved. The snapshots
+// saved before are still loaded.
+func (s *Snapshotter) SetCipher(c encryption.Cipher) {
+	s.cipher = c
+}
+
 func (s *Snapshotter) SaveSnap(snapshot raftpb.Snapshot) error {
 	if raft.IsEmptySnap(snapshot) {
 		return nil
@@ -77,6 +86,12 @@ func (s *Snapshotter) save(snapshot *raftpb.Snapshot) error {
 
 	fname := fmt.Sprintf("%016x-%016x%s", snapshot.Metadata.Term, snapshot.Metadata.Index, snapSuffix)
 	b := pbutil.MustMarshal(snapshot)
+	if s.cipher != nil {
+		var err error
+		if b, err = s.cipher.Encrypt(b); err != nil {
+			return err
+		}
+	}
 	crc := crc32.Update(0, crcTable, b)
 	snap := snappb.Snapshot{Crc: crc, Data: b}
 	d, err := snap.Marshal()
@@ -123,6 +138,8 @@ func (s *Snapshotter) LoadNewestAvailable(walSnaps []walpb.Snapshot) (*raftpb.Sn
 }
 
 // loadMatching returns the newest snapshot where matchFn returns true.
+// It fails instead of falling back to an older snapshot if a snap file is
+// encrypted by a key not configured, since older ones would be stale.
 func (s *Snapshotter) loadMatching(matchFn func(*raftpb.Snapshot) bool) (*raftpb.Snapshot, error) {
 	names, err := s.snapNames()
 	if err != nil {
@@ -130,7 +147,11 @@ func (s *Snapshotter) loadMatching(matchFn func(*raftpb.Snapshot) bool) (*raftpb
 	}
 	var snap *raftpb.Snapshot
 	for _, name := range names {
-		if snap, err = s.loadSnap(name); err == nil && matchFn(snap) {
+		snap, err = s.loadSnap(name)
+		if errors.Is(err, encryption.ErrNotConfigured) || errors.Is(err, encryption.ErrKeyNotFound) {
+			return nil, err
+		}
+		if err == nil && matchFn(snap) {
 			return snap, nil
 		}
 	}
@@ -139,8 +160,9 @@ func (s *Snapshotter) loadMatching(matchFn func(*raftpb.Snapshot) bool) (*raftpb
 
 func (s *Snapshotter) loadSnap(name string) (*raftpb.Snapshot, error) {
 	fpath := filepath.Join(s.dir, name)
-	snap, err := Read(s.lg, fpath)
-	if err != nil {
+	snap, err := read(s.lg, fpath, s.cipher)
+	// the snap file is not broken if it is encrypted by a key not configured
+	if err != nil && !errors.Is(err, encryption.ErrNotConfigured) && !errors.Is(err, encryption.ErrKeyNotFound) {
 		brokenPath := fpath + ".broken"
 		s.lg.Warn("failed to read a snap file", zap.String("path", fpath), zap.Error(err))
 		if rerr := os.Rename(fpath, brokenPath); rerr != nil {
@@ -154,6 +176,16 @@ func (s *Snapshotter) loadSnap(name string) (*raftpb.Snapshot, error) {
 
 // Read reads the snapshot named by snapname and returns the snapshot.
 func Read(lg *zap.Logger, snapname string) (*raftpb.Snapshot, error) {
+	return read(lg, snapname, nil)
+}
+
+// ReadWithCipher reads the snapshot named by snapname, decrypting it with the
+// cipher if it is encrypted.
+func ReadWithCipher(lg *zap.Logger, snapname string, c encryption.Cipher) (*raftpb.Snapshot, error) {
+	return read(lg, snapname, c)
+}
+
+func read(lg *zap.Logger, snapname string, c encryption.Cipher) (*raftpb.Snapshot, error) {
 	verify.Assert(lg != nil, "the logger should not be nil")
 	b, err := os.ReadFile(snapname)
 	if err != nil {
@@ -187,8 +219,20 @@ func Read(lg *zap.Logger, snapname string) (*raftpb.Snapshot, error) {
 		return nil, ErrCRCMismatch
 	}
 
+	data := serializedSnap.Data
+	if encryption.IsEncrypted(data) {
+		if c == nil {
+			lg.Warn("failed to read encrypted snap file", zap.String("path", snapname))
+			return nil, encryption.ErrNotConfigured
+		}
+		if data, err = c.Decrypt(data); err != nil {
+			lg.Warn("failed to decrypt snap file", zap.String("path", snapname), zap.Error(err))
+			return nil, err
+		}
+	}
+
 	var snap raftpb.Snapshot
-	if err = snap.Unmarshal(serializedSnap.Data); err != nil {
+	if err = snap.Unmarshal(data); err != nil {
 		lg.Warn("failed to unmarshal raftpb.Snapshot", zap.String("path", snapname), zap.Error(err))
 		return nil, err
 	}
@@ -15,6 +15,8 @@
 package snap
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"hash/crc32"
 	"os"
@@ -25,6 +27,7 @@ import (
 	"go.uber.org/zap/zaptest"
 
 	"go.etcd.io/etcd/client/pkg/v3/fileutil"
+	"go.etcd.io/etcd/server/v3/storage/encryption"
 	"go.etcd.io/etcd/server/v3/storage/wal/walpb"
 	"go.etcd.io/raft/v3/raftpb"
 )
@@ -62,6 +65,50 @@ func TestSaveAndLoad(t *testing.T) {
 	}
 }
 
+func TestSaveAndLoadEncrypted(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(t.TempDir(), "keys")
+	if _, err := encryption.RotateKeyFile(keyFile); err != nil {
+		t.Fatal(err)
+	}
+	kms, err := encryption.NewLocalKMS(keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss := New(zaptest.NewLogger(t), dir)
+	ss.SetCipher(encryption.NewEnvelope(kms))
+	if err = ss.save(testSnap); err != nil {
+		t.Fatal(err)
+	}
+	fpath := filepath.Join(dir, fmt.Sprintf("%016x-%016x.snap", 1, 1))
+	b, err := os.ReadFile(fpath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(b, testSnap.Data) {
+		t.Errorf("snap file contains the plaintext snapshot")
+	}
+
+	if _, err = Read(zaptest.NewLogger(t), fpath); !errors.Is(err, encryption.ErrNotConfigured) {
+		t.Errorf("err = %v, want %v", err, encryption.ErrNotConfigured)
+	}
+	// the snap file is kept when its key is not configured, and loading fails
+	// instead of falling back to an older snapshot
+	if _, err = New(zaptest.NewLogger(t), dir).Load(); !errors.Is(err, encryption.ErrNotConfigured) {
+		t.Errorf("err = %v, want %v", err, encryption.ErrNotConfigured)
+	}
+	if _, err = os.Stat(fpath); err != nil {
+		t.Errorf("snap file was removed: %v", err)
+	}
+	g, err := ss.Load()
+	if err != nil {
+		t.Fatalf("err = %v, want nil", err)
+	}
+	if !reflect.DeepEqual(g, testSnap) {
+		t.Errorf("snap = %#v, want %#v", g, testSnap)
+	}
+}
+
 func TestBadCRC(t *testing.T) {
 	dir := filepath.Join(os.TempDir(), "snapshot")
 	err := os.Mkdir(dir, 0700)
@@ -0,0 +1,157 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3compactor
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+)
+
+var (
+	ErrCompactionHoldExists   = errors.New("etcdserver: compaction hold already exists")
+	ErrCompactionHoldNotFound = errors.New("etcdserver: compaction hold not found")
+)
+
+type HoldBackend interface {
+	CreateCompactionHoldBucket()
+	MustPutCompactionHold(hold *pb.CompactionHold)
+	MustDeleteCompactionHold(hold *pb.CompactionHold)
+	GetAllCompactionHolds() ([]*pb.CompactionHold, error)
+	ForceCommit()
+}
+
+// LeaseChecker reports whether the lease of the given ID exists.
+type LeaseChecker func(id int64) bool
+
+// HoldStore persists the compaction holds to the backend. A hold is active
+// as long as its lease exists; the holds whose lease is gone are released
+// the next time the holds are changed or a compaction is applied.
+type HoldStore struct {
+	lg    *zap.Logger
+	mu    sync.Mutex
+	holds map[int64]*pb.CompactionHold
+
+	be          HoldBackend
+	leaseExists LeaseChecker
+}
+
+func NewHoldStore(lg *zap.Logger, be HoldBackend, leaseExists LeaseChecker) (*HoldStore, error) {
+	if lg == nil {
+		lg = zap.NewNop()
+	}
+	s := &HoldStore{lg: lg, holds: make(map[int64]*pb.CompactionHold), be: be, leaseExists: leaseExists}
+	err := s.restore()
+	return s, err
+}
+
+// Acquire adds the hold, releasing the holds whose lease is gone.
+func (s *HoldStore) Acquire(hold *pb.CompactionHold) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune()
+	if _, ok := s.holds[hold.ID]; ok {
+		return ErrCompactionHoldExists
+	}
+	s.holds[hold.ID] = hold
+	s.be.MustPutCompactionHold(hold)
+	return nil
+}
+
+// Release removes the hold of the given ID, releasing the holds whose lease is gone.
+func (s *HoldStore) Release(id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune()
+	hold, ok := s.holds[id]
+	if !ok {
+		return ErrCompactionHoldNotFound
+	}
+	delete(s.holds, id)
+	s.be.MustDeleteCompactionHold(hold)
+	return nil
+}
+
+// MinRevision releases the holds whose lease is gone and returns the lowest
+// revision held, or 0 if there is no active hold.
+func (s *HoldStore) MinRevision() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune()
+	var rev int64
+	for _, hold := range s.holds {
+		if rev == 0 || hold.Revision < rev {
+			rev = hold.Revision
+		}
+	}
+	return rev
+}
+
+// List returns the active holds ordered by revision and ID.
+func (s *HoldStore) List() []*pb.CompactionHold {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	holds := make([]*pb.CompactionHold, 0, len(s.holds))
+	for _, hold := range s.holds {
+		if s.leaseExists(hold.Lease) {
+			holds = append(holds, hold)
+		}
+	}
+	sort.Slice(holds, func(i, j int) bool {
+		if holds[i].Revision != holds[j].Revision {
+			return holds[i].Revision < holds[j].Revision
+		}
+		return holds[i].ID < holds[j].ID
+	})
+	return holds
+}
+
+// prune releases the holds whose lease is gone. It must only be called while
+// applying a request so that all members release the same holds.
+func (s *HoldStore) prune() {
+	for id, hold := range s.holds {
+		if !s.leaseExists(hold.Lease) {
+			s.lg.Info(
+				"released compaction hold of missing lease",
+				zap.Int64("id", id),
+				zap.Int64("revision", hold.Revision),
+				zap.Int64("lease", hold.Lease),
+			)
+			delete(s.holds, id)
+			s.be.MustDeleteCompactionHold(hold)
+		}
+	}
+}
+
+func (s *HoldStore) restore() error {
+	s.be.CreateCompactionHoldBucket()
+	holds, err := s.be.GetAllCompactionHolds()
+	if err != nil {
+		return err
+	}
+	for _, hold := range holds {
+		s.holds[hold.ID] = hold
+	}
+	s.be.ForceCommit()
+	return nil
+}
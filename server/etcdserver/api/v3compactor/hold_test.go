@@ -0,0 +1,68 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3compactor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	betesting "go.etcd.io/etcd/server/v3/storage/backend/testing"
+	"go.etcd.io/etcd/server/v3/storage/schema"
+)
+
+func TestHoldStore(t *testing.T) {
+	lg := zaptest.NewLogger(t)
+	be, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, be)
+
+	leases := map[int64]bool{1: true, 2: true}
+	leaseExists := func(id int64) bool { return leases[id] }
+	hs, err := NewHoldStore(lg, schema.NewCompactionHoldBackend(lg, be), leaseExists)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), hs.MinRevision())
+
+	require.NoError(t, hs.Acquire(&pb.CompactionHold{ID: 10, Revision: 5, Lease: 1}))
+	require.NoError(t, hs.Acquire(&pb.CompactionHold{ID: 11, Revision: 3, Lease: 2}))
+	require.NoError(t, hs.Acquire(&pb.CompactionHold{ID: 12, Revision: 7, Lease: 2}))
+	assert.ErrorIs(t, hs.Acquire(&pb.CompactionHold{ID: 10, Revision: 9, Lease: 1}), ErrCompactionHoldExists)
+	assert.Equal(t, int64(3), hs.MinRevision())
+
+	// the holds are restored from the backend
+	hs, err = NewHoldStore(lg, schema.NewCompactionHoldBackend(lg, be), leaseExists)
+	require.NoError(t, err)
+	assert.Equal(t, []*pb.CompactionHold{
+		{ID: 11, Revision: 3, Lease: 2},
+		{ID: 10, Revision: 5, Lease: 1},
+		{ID: 12, Revision: 7, Lease: 2},
+	}, hs.List())
+
+	require.NoError(t, hs.Release(10))
+	assert.ErrorIs(t, hs.Release(10), ErrCompactionHoldNotFound)
+
+	// the holds of a revoked lease are inactive, and released on the next change
+	delete(leases, 2)
+	assert.Empty(t, hs.List())
+	assert.Equal(t, int64(0), hs.MinRevision())
+	assert.ErrorIs(t, hs.Release(11), ErrCompactionHoldNotFound)
+
+	be.ForceCommit()
+	holds, err := schema.NewCompactionHoldBackend(lg, be).GetAllCompactionHolds()
+	require.NoError(t, err)
+	assert.Empty(t, holds)
+}
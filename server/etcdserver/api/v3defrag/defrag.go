@@ -0,0 +1,293 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3defrag
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/jonboulle/clockwork"
+	"go.uber.org/zap"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/client/pkg/v3/types"
+	"go.etcd.io/etcd/server/v3/etcdserver/api"
+	"go.etcd.io/etcd/server/v3/lease"
+	"go.etcd.io/etcd/server/v3/storage/backend"
+)
+
+const (
+	// LeaderPolicySkip never defragments the leader.
+	LeaderPolicySkip = "skip"
+	// LeaderPolicyMove transfers the leadership to another member before
+	// defragmenting the leader.
+	LeaderPolicyMove = "move"
+
+	lockTTL = 60 * time.Second
+	// revokeRetries is the number of attempts to revoke the lock lease, which
+	// may fail while the leadership is transferred.
+	revokeRetries = 3
+	// revokeTimeout bounds each attempt to revoke the lock lease.
+	revokeTimeout = 5 * time.Second
+)
+
+type BackendGetter interface {
+	Backend() backend.Backend
+}
+
+// Member is the etcd member defragmented by the Defragger.
+type Member interface {
+	MemberId() types.ID
+	Lead() uint64
+	Cluster() api.Cluster
+	// TransferLeadership transfers the leadership to another member,
+	// unless the member is the only voting one.
+	TransferLeadership() error
+	LeaseGrant(ctx context.Context, r *pb.LeaseGrantRequest) (*pb.LeaseGrantResponse, error)
+	LeaseRevoke(ctx context.Context, r *pb.LeaseRevokeRequest) (*pb.LeaseRevokeResponse, error)
+	LeaseRenew(ctx context.Context, id lease.LeaseID) (int64, error)
+	LeaseLeases(ctx context.Context, r *pb.LeaseLeasesRequest) (*pb.LeaseLeasesResponse, error)
+}
+
+// LockLeaseID returns the ID of the lock lease granted by the member while
+// it is defragmented, so that at most one member of the cluster is
+// defragmented at a time. The lease expires if the member fails. The ID is the
+// member ID made positive, as lease IDs are.
+func LockLeaseID(id types.ID) lease.LeaseID {
+	return lease.LeaseID(uint64(id) &^ (1 << 63))
+}
+
+// isLockedByOther returns true if a lease is the lock lease of another member
+// of the cluster.
+func (d *Defragger) isLockedByOther(leases []*pb.LeaseStatus) bool {
+	locks := make(map[int64]struct{})
+	for _, m := range d.m.Cluster().Members() {
+		if m.ID != d.m.MemberId() {
+			locks[int64(LockLeaseID(m.ID))] = struct{}{}
+		}
+	}
+	for _, l := range leases {
+		if _, ok := locks[l.ID]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Config configures the Defragger.
+type Config struct {
+	// ThresholdBytes is the minimum number of bytes freed by the
+	// defragmentation, i.e. backend size minus size in use, to defragment.
+	ThresholdBytes int64
+	// CheckInterval is the interval between two checks of the backend size.
+	CheckInterval time.Duration
+	// Online defragments without blocking reads and writes during the copy.
+	Online bool
+	// LeaderPolicy is the policy when the member is the leader, either
+	// LeaderPolicySkip or LeaderPolicyMove.
+	LeaderPolicy string
+}
+
+// Defragger defragments the backend of the member whenever it can free more
+// than the configured threshold, one member of the cluster at a time.
+type Defragger struct {
+	lg    *zap.Logger
+	clock clockwork.Clock
+	cfg   Config
+
+	bg BackendGetter
+	m  Member
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New returns a new Defragger for the member.
+func New(lg *zap.Logger, cfg Config, bg BackendGetter, m Member) (*Defragger, error) {
+	if lg == nil {
+		lg = zap.NewNop()
+	}
+	if cfg.ThresholdBytes <= 0 {
+		return nil, fmt.Errorf("invalid defragmentation threshold %d", cfg.ThresholdBytes)
+	}
+	if cfg.CheckInterval <= 0 {
+		return nil, fmt.Errorf("invalid defragmentation check interval %v", cfg.CheckInterval)
+	}
+	switch cfg.LeaderPolicy {
+	case LeaderPolicySkip, LeaderPolicyMove:
+	default:
+		return nil, fmt.Errorf("unsupported defragmentation leader policy %s", cfg.LeaderPolicy)
+	}
+	return newDefragger(lg, clockwork.NewRealClock(), cfg, bg, m), nil
+}
+
+func newDefragger(lg *zap.Logger, clock clockwork.Clock, cfg Config, bg BackendGetter, m Member) *Defragger {
+	d := &Defragger{
+		lg:    lg,
+		clock: clock,
+		cfg:   cfg,
+		bg:    bg,
+		m:     m,
+	}
+	d.ctx, d.cancel = context.WithCancel(context.Background())
+	return d
+}
+
+// Run starts checking the backend size in background.
+func (d *Defragger) Run() {
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		for {
+			select {
+			case <-d.ctx.Done():
+				return
+			case <-d.clock.After(d.cfg.CheckInterval):
+			}
+			d.check()
+		}
+	}()
+}
+
+// Stop stops checking the backend size and waits for the running
+// defragmentation to complete.
+func (d *Defragger) Stop() {
+	d.cancel()
+	d.wg.Wait()
+}
+
+func (d *Defragger) check() {
+	be := d.bg.Backend()
+	free := be.Size() - be.SizeInUse()
+	if free < d.cfg.ThresholdBytes {
+		return
+	}
+
+	leader := uint64(d.m.MemberId()) == d.m.Lead()
+	if leader && d.cfg.LeaderPolicy == LeaderPolicySkip {
+		d.lg.Debug(
+			"skipped auto defragmentation; local member is leader",
+			zap.String("free-size", humanize.Bytes(uint64(free))),
+		)
+		return
+	}
+
+	// lock before transferring the leadership, so that the leadership is not
+	// moved when another member is being defragmented
+	unlock, ok := d.lock()
+	if !ok {
+		return
+	}
+	defer unlock()
+
+	if leader {
+		if err := d.m.TransferLeadership(); err != nil {
+			d.lg.Warn("failed to transfer leadership before auto defragmentation", zap.Error(err))
+			return
+		}
+	}
+
+	now := time.Now()
+	d.lg.Info(
+		"starting auto defragmentation",
+		zap.Int64("free-size-bytes", free),
+		zap.String("free-size", humanize.Bytes(uint64(free))),
+		zap.Bool("online", d.cfg.Online),
+	)
+	var err error
+	if d.cfg.Online {
+		err = be.DefragOnline()
+	} else {
+		err = be.Defrag()
+	}
+	if err != nil {
+		d.lg.Warn(
+			"failed auto defragmentation",
+			zap.Duration("retry-interval", d.cfg.CheckInterval),
+			zap.Error(err),
+		)
+		return
+	}
+	d.lg.Info("completed auto defragmentation", zap.Duration("took", time.Since(now)))
+}
+
+// lock grants the lock lease of the member and keeps it alive until the
+// returned unlock is called. It returns false if another member holds its
+// lock lease. Two members locking at the same time may both back off, but
+// never both proceed, since each lists the leases after granting its own.
+func (d *Defragger) lock() (unlock func(), ok bool) {
+	id := LockLeaseID(d.m.MemberId())
+	_, err := d.m.LeaseGrant(d.ctx, &pb.LeaseGrantRequest{ID: int64(id), TTL: int64(lockTTL.Seconds())})
+	if err == lease.ErrLeaseExists {
+		// left by the member before it restarted
+		_, err = d.m.LeaseRenew(d.ctx, id)
+	}
+	if err != nil {
+		d.lg.Warn("failed to grant auto defragmentation lease", zap.Error(err))
+		return nil, false
+	}
+	revoke := func() {
+		var err error
+		// revoked even once stopped, so that the other members do not wait
+		// for the lease to expire
+		for i := 0; i < revokeRetries; i++ {
+			ctx, cancel := context.WithTimeout(context.Background(), revokeTimeout)
+			_, err = d.m.LeaseRevoke(ctx, &pb.LeaseRevokeRequest{ID: int64(id)})
+			cancel()
+			if err == nil || err == lease.ErrLeaseNotFound {
+				return
+			}
+		}
+		// the lease expires after lockTTL
+		d.lg.Warn("failed to revoke auto defragmentation lease", zap.Error(err))
+	}
+
+	resp, err := d.m.LeaseLeases(d.ctx, &pb.LeaseLeasesRequest{})
+	if err != nil {
+		d.lg.Warn("failed to list auto defragmentation leases", zap.Error(err))
+		revoke()
+		return nil, false
+	}
+	if d.isLockedByOther(resp.Leases) {
+		d.lg.Info("skipped auto defragmentation; another member is being defragmented")
+		revoke()
+		return nil, false
+	}
+
+	ctx, cancel := context.WithCancel(d.ctx)
+	donec := make(chan struct{})
+	go func() {
+		defer close(donec)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-d.clock.After(lockTTL / 3):
+			}
+			if _, err := d.m.LeaseRenew(ctx, id); err != nil && ctx.Err() == nil {
+				d.lg.Warn("failed to renew auto defragmentation lease", zap.Error(err))
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-donec
+		revoke()
+	}, true
+}
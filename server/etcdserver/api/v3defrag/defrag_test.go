@@ -0,0 +1,292 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3defrag
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/client/pkg/v3/testutil"
+	"go.etcd.io/etcd/client/pkg/v3/types"
+	"go.etcd.io/etcd/server/v3/etcdserver/api"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/membership"
+	"go.etcd.io/etcd/server/v3/lease"
+	"go.etcd.io/etcd/server/v3/storage/backend"
+)
+
+type fakeBackend struct {
+	backend.Backend
+	testutil.Recorder
+	size, sizeInUse int64
+}
+
+func (fb *fakeBackend) Size() int64      { return fb.size }
+func (fb *fakeBackend) SizeInUse() int64 { return fb.sizeInUse }
+
+func (fb *fakeBackend) Defrag() error {
+	fb.Record(testutil.Action{Name: "Defrag"})
+	return nil
+}
+
+func (fb *fakeBackend) DefragOnline() error {
+	fb.Record(testutil.Action{Name: "DefragOnline"})
+	return nil
+}
+
+type fakeBackendGetter struct {
+	be backend.Backend
+}
+
+func (fg fakeBackendGetter) Backend() backend.Backend { return fg.be }
+
+type fakeMember struct {
+	testutil.Recorder
+	leader   bool
+	grantErr error
+	leases   []int64
+}
+
+func (fm *fakeMember) MemberId() types.ID { return 1 }
+
+func (fm *fakeMember) Lead() uint64 {
+	if fm.leader {
+		return 1
+	}
+	return 2
+}
+
+func (fm *fakeMember) Cluster() api.Cluster { return fakeCluster{} }
+
+type fakeCluster struct {
+	api.Cluster
+}
+
+func (fc fakeCluster) Members() []*membership.Member {
+	return []*membership.Member{{ID: 1}, {ID: 2}, {ID: 0x100000001}}
+}
+
+func (fm *fakeMember) TransferLeadership() error {
+	fm.Record(testutil.Action{Name: "TransferLeadership"})
+	fm.leader = false
+	return nil
+}
+
+func (fm *fakeMember) LeaseGrant(ctx context.Context, r *pb.LeaseGrantRequest) (*pb.LeaseGrantResponse, error) {
+	fm.Record(testutil.Action{Name: "LeaseGrant", Params: []interface{}{r.ID}})
+	return &pb.LeaseGrantResponse{}, fm.grantErr
+}
+
+func (fm *fakeMember) LeaseRevoke(ctx context.Context, r *pb.LeaseRevokeRequest) (*pb.LeaseRevokeResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	fm.Record(testutil.Action{Name: "LeaseRevoke", Params: []interface{}{r.ID}})
+	return &pb.LeaseRevokeResponse{}, nil
+}
+
+func (fm *fakeMember) LeaseRenew(ctx context.Context, id lease.LeaseID) (int64, error) {
+	fm.Record(testutil.Action{Name: "LeaseRenew"})
+	return 0, nil
+}
+
+func (fm *fakeMember) LeaseLeases(ctx context.Context, r *pb.LeaseLeasesRequest) (*pb.LeaseLeasesResponse, error) {
+	fm.Record(testutil.Action{Name: "LeaseLeases"})
+	resp := &pb.LeaseLeasesResponse{}
+	for _, id := range fm.leases {
+		resp.Leases = append(resp.Leases, &pb.LeaseStatus{ID: id})
+	}
+	return resp, nil
+}
+
+func TestDefraggerCheck(t *testing.T) {
+	lockID := int64(LockLeaseID(1))
+	otherLockID := int64(LockLeaseID(2))
+	grant := testutil.Action{Name: "LeaseGrant", Params: []interface{}{lockID}}
+	leases := testutil.Action{Name: "LeaseLeases"}
+	revoke := testutil.Action{Name: "LeaseRevoke", Params: []interface{}{lockID}}
+	tests := []struct {
+		name         string
+		size         int64
+		online       bool
+		leader       bool
+		leaderPolicy string
+		grantErr     error
+		leases       []int64
+
+		wantBackend []testutil.Action
+		wantMember  []testutil.Action
+	}{
+		{
+			name: "below threshold",
+			size: 150,
+		},
+		{
+			name:        "above threshold",
+			size:        300,
+			wantBackend: []testutil.Action{{Name: "Defrag"}},
+			wantMember:  []testutil.Action{grant, leases, revoke},
+		},
+		{
+			name:        "online",
+			size:        300,
+			online:      true,
+			wantBackend: []testutil.Action{{Name: "DefragOnline"}},
+			wantMember:  []testutil.Action{grant, leases, revoke},
+		},
+		{
+			name:       "another member locked",
+			size:       300,
+			leases:     []int64{lockID, otherLockID, 7},
+			wantMember: []testutil.Action{grant, leases, revoke},
+		},
+		{
+			name:       "member sharing the low bits of the ID locked",
+			size:       300,
+			leases:     []int64{lockID, int64(LockLeaseID(0x100000001))},
+			wantMember: []testutil.Action{grant, leases, revoke},
+		},
+		{
+			name:        "lock left before restart",
+			size:        300,
+			grantErr:    lease.ErrLeaseExists,
+			leases:      []int64{lockID},
+			wantBackend: []testutil.Action{{Name: "Defrag"}},
+			wantMember:  []testutil.Action{grant, {Name: "LeaseRenew"}, leases, revoke},
+		},
+		{
+			name:         "leader not moved while another member locked",
+			size:         300,
+			leader:       true,
+			leaderPolicy: LeaderPolicyMove,
+			leases:       []int64{otherLockID},
+			wantMember:   []testutil.Action{grant, leases, revoke},
+		},
+		{
+			name:         "leader skipped",
+			size:         300,
+			leader:       true,
+			leaderPolicy: LeaderPolicySkip,
+		},
+		{
+			name:         "leader moved",
+			size:         300,
+			leader:       true,
+			leaderPolicy: LeaderPolicyMove,
+			wantBackend:  []testutil.Action{{Name: "Defrag"}},
+			wantMember:   []testutil.Action{grant, leases, {Name: "TransferLeadership"}, revoke},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			be := &fakeBackend{Recorder: &testutil.RecorderBuffered{}, size: tt.size, sizeInUse: 100}
+			m := &fakeMember{Recorder: &testutil.RecorderBuffered{}, leader: tt.leader, grantErr: tt.grantErr, leases: tt.leases}
+			cfg := Config{ThresholdBytes: 100, CheckInterval: time.Minute, Online: tt.online, LeaderPolicy: tt.leaderPolicy}
+			d := newDefragger(zaptest.NewLogger(t), clockwork.NewFakeClock(), cfg, fakeBackendGetter{be}, m)
+			defer d.Stop()
+
+			d.check()
+			assert.Equal(t, tt.wantBackend, nilIfEmpty(be.Action()))
+			assert.Equal(t, tt.wantMember, nilIfEmpty(m.Action()))
+		})
+	}
+}
+
+func nilIfEmpty(a []testutil.Action) []testutil.Action {
+	if len(a) == 0 {
+		return nil
+	}
+	return a
+}
+
+func TestDefraggerRenewLock(t *testing.T) {
+	fc := clockwork.NewFakeClock()
+	m := &fakeMember{Recorder: &testutil.RecorderBuffered{}}
+	d := newDefragger(zaptest.NewLogger(t), fc, Config{}, nil, m)
+	defer d.Stop()
+
+	unlock, ok := d.lock()
+	if !ok {
+		t.Fatal("expected to lock")
+	}
+	fc.BlockUntil(1)
+	fc.Advance(lockTTL / 3)
+	// renewed and waiting for the next renewal
+	fc.BlockUntil(1)
+	unlock()
+
+	var names []string
+	for _, a := range m.Action() {
+		names = append(names, a.Name)
+	}
+	assert.Equal(t, []string{"LeaseGrant", "LeaseLeases", "LeaseRenew", "LeaseRevoke"}, names)
+}
+
+type blockingBackend struct {
+	*fakeBackend
+	startc, releasec chan struct{}
+}
+
+func (bb *blockingBackend) Defrag() error {
+	close(bb.startc)
+	<-bb.releasec
+	return bb.fakeBackend.Defrag()
+}
+
+func TestDefraggerStopWaits(t *testing.T) {
+	fc := clockwork.NewFakeClock()
+	be := &blockingBackend{
+		fakeBackend: &fakeBackend{Recorder: &testutil.RecorderBuffered{}, size: 300, sizeInUse: 100},
+		startc:      make(chan struct{}),
+		releasec:    make(chan struct{}),
+	}
+	m := &fakeMember{Recorder: &testutil.RecorderBuffered{}}
+	d := newDefragger(zaptest.NewLogger(t), fc, Config{ThresholdBytes: 100, CheckInterval: time.Minute}, fakeBackendGetter{be}, m)
+	d.Run()
+	fc.BlockUntil(1)
+	fc.Advance(time.Minute)
+	<-be.startc
+
+	stopped := make(chan struct{})
+	go func() {
+		d.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		t.Fatal("stopped before the defragmentation completed")
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(be.releasec)
+	<-stopped
+	assert.Equal(t, []testutil.Action{{Name: "Defrag"}}, be.Action())
+	// the lock lease is revoked although stopped
+	actions := m.Action()
+	assert.Equal(t, "LeaseRevoke", actions[len(actions)-1].Name)
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(nil, Config{ThresholdBytes: 1, CheckInterval: time.Minute, LeaderPolicy: "unknown"}, nil, nil)
+	assert.Error(t, err)
+	_, err = New(nil, Config{CheckInterval: time.Minute, LeaderPolicy: LeaderPolicySkip}, nil, nil)
+	assert.Error(t, err)
+	_, err = New(nil, Config{ThresholdBytes: 1, CheckInterval: time.Minute, LeaderPolicy: LeaderPolicyMove}, nil, nil)
+	assert.NoError(t, err)
+}
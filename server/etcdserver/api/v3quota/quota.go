@@ -0,0 +1,460 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package v3quota manages the storage quotas of key prefixes and the request
+// rate limits of users and roles in etcd.
+package v3quota
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+)
+
+var (
+	ErrQuotaExceeded = errors.New("etcdserver: key prefix quota exceeded")
+	ErrRateLimited   = errors.New("etcdserver: request rate limit exceeded")
+	ErrInvalidQuota  = errors.New("etcdserver: invalid quota")
+	ErrQuotaNotFound = errors.New("etcdserver: quota not found")
+)
+
+const (
+	// maxRequestsPerSecond and maxBurst bound the rate limits so that the
+	// token buckets, counted in billionths of a request, do not overflow.
+	maxRequestsPerSecond = 1_000_000_000
+	maxBurst             = 1_000_000_000
+
+	nanoTokensPerRequest = 1_000_000_000
+)
+
+type QuotaBackend interface {
+	CreateQuotaBuckets()
+	MustPutQuota(quota *pb.Quota)
+	MustDeleteQuota(quota *pb.Quota)
+	GetAllQuotas() ([]*pb.Quota, error)
+	MustPutRateLimitState(quota *pb.Quota, tokens, last int64)
+	GetRateLimitState(quota *pb.Quota) (tokens, last int64, found bool)
+	ForceCommit()
+}
+
+// KeyIterator iterates the keys of a key range, usually an mvcc.KV.
+type KeyIterator interface {
+	Iterate(key, end []byte, ro mvcc.RangeOptions) (mvcc.RangeIterator, error)
+}
+
+// prefixQuota tracks the total size and the number of the keys under the
+// prefix of a quota.
+type prefixQuota struct {
+	quota *pb.Quota
+	end   []byte
+	bytes int64
+	keys  int64
+}
+
+func (q *prefixQuota) contains(key []byte) bool {
+	return bytes.HasPrefix(key, q.quota.Prefix)
+}
+
+// update replaces a key and value of the size prev, -1 if absent, by one of
+// the size size, -1 if deleted.
+func (q *prefixQuota) update(prev, size int64) {
+	if prev >= 0 {
+		q.bytes -= prev
+		q.keys--
+	}
+	if size >= 0 {
+		q.bytes += size
+		q.keys++
+	}
+}
+
+type rateLimitKey struct {
+	typ  pb.Quota_Type
+	name string
+}
+
+// rateLimit is a token bucket counting billionths of a request.
+type rateLimit struct {
+	quota  *pb.Quota
+	tokens int64
+	// last is the time in nanoseconds the bucket was refilled, 0 if never.
+	last int64
+}
+
+func (l *rateLimit) capacity() int64 {
+	return l.quota.Burst * nanoTokensPerRequest
+}
+
+// refill returns the tokens of the bucket at the time ts.
+func (l *rateLimit) refill(ts int64) int64 {
+	if l.last == 0 {
+		return l.capacity()
+	}
+	tokens := l.tokens
+	if ts > l.last {
+		if elapsed := ts - l.last; elapsed >= (l.capacity()-tokens)/l.quota.RequestsPerSecond+1 {
+			tokens = l.capacity()
+		} else {
+			tokens += elapsed * l.quota.RequestsPerSecond
+		}
+	}
+	if tokens > l.capacity() {
+		tokens = l.capacity()
+	}
+	return tokens
+}
+
+// QuotaStore persists the quotas to the backend and enforces them. It must be
+// called from the apply loop so that all members make the same decisions.
+type QuotaStore struct {
+	lg *zap.Logger
+	be QuotaBackend
+	kv KeyIterator
+
+	mu       sync.Mutex
+	prefixes map[string]*prefixQuota
+	limits   map[rateLimitKey]*rateLimit
+}
+
+func NewQuotaStore(lg *zap.Logger, be QuotaBackend, kv KeyIterator) (*QuotaStore, error) {
+	if lg == nil {
+		lg = zap.NewNop()
+	}
+	s := &QuotaStore{lg: lg, be: be, kv: kv}
+	err := s.restore()
+	return s, err
+}
+
+func (s *QuotaStore) restore() error {
+	s.be.CreateQuotaBuckets()
+	quotas, err := s.be.GetAllQuotas()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prefixes = make(map[string]*prefixQuota)
+	s.limits = make(map[rateLimitKey]*rateLimit)
+	for _, q := range quotas {
+		if q.Type == pb.Quota_PREFIX {
+			pq, err := s.newPrefixQuota(q)
+			if err != nil {
+				return err
+			}
+			s.prefixes[string(q.Prefix)] = pq
+			continue
+		}
+		l := &rateLimit{quota: q}
+		l.tokens, l.last, _ = s.be.GetRateLimitState(q)
+		s.limits[rateLimitKey{q.Type, q.Name}] = l
+	}
+	return nil
+}
+
+// newPrefixQuota scans the keys under the prefix of the quota to compute its usage.
+func (s *QuotaStore) newPrefixQuota(q *pb.Quota) (*prefixQuota, error) {
+	pq := &prefixQuota{quota: q, end: prefixEnd(q.Prefix)}
+	it, err := s.kv.Iterate(q.Prefix, pq.end, mvcc.RangeOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for {
+		kvs, err := it.Next(context.TODO(), 1000, 0)
+		if err != nil {
+			return nil, err
+		}
+		if len(kvs) == 0 {
+			return pq, nil
+		}
+		for _, kv := range kvs {
+			pq.update(-1, int64(len(kv.Key)+len(kv.Value)))
+		}
+	}
+}
+
+// Validate checks the quota and fills in its defaults.
+func Validate(q *pb.Quota) error {
+	switch q.Type {
+	case pb.Quota_PREFIX:
+		if len(q.Prefix) == 0 || q.MaxBytes < 0 || q.MaxKeys < 0 || (q.MaxBytes == 0 && q.MaxKeys == 0) {
+			return ErrInvalidQuota
+		}
+		if q.Name != "" || q.RequestsPerSecond != 0 || q.Burst != 0 {
+			return ErrInvalidQuota
+		}
+	case pb.Quota_USER, pb.Quota_ROLE:
+		if q.Name == "" || q.RequestsPerSecond <= 0 || q.RequestsPerSecond > maxRequestsPerSecond {
+			return ErrInvalidQuota
+		}
+		if q.Burst < 0 || q.Burst > maxBurst || len(q.Prefix) != 0 || q.MaxBytes != 0 || q.MaxKeys != 0 {
+			return ErrInvalidQuota
+		}
+		if q.Burst == 0 {
+			q.Burst = q.RequestsPerSecond
+			if q.Burst > maxBurst {
+				q.Burst = maxBurst
+			}
+		}
+	default:
+		return ErrInvalidQuota
+	}
+	q.UsedBytes, q.UsedKeys = 0, 0
+	return nil
+}
+
+// Set creates or replaces a quota. Replacing a rate limit refills its bucket.
+func (s *QuotaStore) Set(q *pb.Quota) error {
+	if err := Validate(q); err != nil {
+		return err
+	}
+
+	var pq *prefixQuota
+	if q.Type == pb.Quota_PREFIX {
+		s.mu.Lock()
+		old := s.prefixes[string(q.Prefix)]
+		s.mu.Unlock()
+		if old != nil {
+			pq = &prefixQuota{quota: q, end: old.end, bytes: old.bytes, keys: old.keys}
+		} else {
+			var err error
+			if pq, err = s.newPrefixQuota(q); err != nil {
+				return err
+			}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.be.MustPutQuota(q)
+	if pq != nil {
+		s.prefixes[string(q.Prefix)] = pq
+	} else {
+		s.limits[rateLimitKey{q.Type, q.Name}] = &rateLimit{quota: q}
+	}
+	return nil
+}
+
+// Delete deletes the quota of the given type on the prefix or name.
+func (s *QuotaStore) Delete(typ pb.Quota_Type, prefix []byte, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var q *pb.Quota
+	switch typ {
+	case pb.Quota_PREFIX:
+		pq, ok := s.prefixes[string(prefix)]
+		if !ok {
+			return ErrQuotaNotFound
+		}
+		q = pq.quota
+		delete(s.prefixes, string(prefix))
+	default:
+		k := rateLimitKey{typ, name}
+		l, ok := s.limits[k]
+		if !ok {
+			return ErrQuotaNotFound
+		}
+		q = l.quota
+		delete(s.limits, k)
+	}
+	s.be.MustDeleteQuota(q)
+	return nil
+}
+
+// List returns the prefix quotas sorted by prefix with their usage, followed
+// by the rate limits sorted by type and name.
+func (s *QuotaStore) List() []*pb.Quota {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var prefixes, limits []*pb.Quota
+	for _, pq := range s.prefixes {
+		q := *pq.quota
+		q.UsedBytes, q.UsedKeys = pq.bytes, pq.keys
+		prefixes = append(prefixes, &q)
+	}
+	for _, l := range s.limits {
+		q := *l.quota
+		limits = append(limits, &q)
+	}
+	sort.Slice(prefixes, func(i, j int) bool { return bytes.Compare(prefixes[i].Prefix, prefixes[j].Prefix) < 0 })
+	sort.Slice(limits, func(i, j int) bool {
+		if limits[i].Type != limits[j].Type {
+			return limits[i].Type < limits[j].Type
+		}
+		return limits[i].Name < limits[j].Name
+	})
+	return append(prefixes, limits...)
+}
+
+// CheckPuts returns ErrQuotaExceeded if applying the puts would grow the usage
+// of a prefix beyond its quota. Puts reducing the usage of a prefix over its
+// quota, e.g. after the quota was lowered, are allowed. It must not be called
+// within a write txn of the KV, as it reads the current values of the keys.
+func (s *QuotaStore) CheckPuts(puts []*pb.PutRequest) error {
+	if len(puts) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	var prefixes []*prefixQuota
+	for _, pq := range s.prefixes {
+		prefixes = append(prefixes, pq)
+	}
+	s.mu.Unlock()
+	if len(prefixes) == 0 {
+		return nil
+	}
+
+	// the sizes of the keys under a prefix, -1 if absent, as the puts go
+	sizes := make(map[string]int64)
+	for _, p := range puts {
+		if _, ok := sizes[string(p.Key)]; ok {
+			continue
+		}
+		for _, pq := range prefixes {
+			if pq.contains(p.Key) {
+				size, err := s.size(p.Key)
+				if err != nil {
+					return err
+				}
+				sizes[string(p.Key)] = size
+				break
+			}
+		}
+	}
+	if len(sizes) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, pq := range prefixes {
+		used := prefixQuota{bytes: pq.bytes, keys: pq.keys}
+		current := make(map[string]int64)
+		for _, p := range puts {
+			if !pq.contains(p.Key) {
+				continue
+			}
+			old, ok := current[string(p.Key)]
+			if !ok {
+				old = sizes[string(p.Key)]
+			}
+			size := int64(len(p.Key) + len(p.Value))
+			if p.IgnoreValue && old >= 0 {
+				size = old
+			}
+			used.update(old, size)
+			current[string(p.Key)] = size
+		}
+		q := pq.quota
+		if q.MaxBytes > 0 && used.bytes > q.MaxBytes && used.bytes > pq.bytes {
+			return ErrQuotaExceeded
+		}
+		if q.MaxKeys > 0 && used.keys > q.MaxKeys && used.keys > pq.keys {
+			return ErrQuotaExceeded
+		}
+	}
+	return nil
+}
+
+// size returns the size of the key and its current value, or -1 if the key
+// does not exist.
+func (s *QuotaStore) size(key []byte) (int64, error) {
+	it, err := s.kv.Iterate(key, nil, mvcc.RangeOptions{})
+	if err != nil {
+		return 0, err
+	}
+	kvs, err := it.Next(context.TODO(), 1, 0)
+	if err != nil || len(kvs) == 0 {
+		return -1, err
+	}
+	return int64(len(kvs[0].Key) + len(kvs[0].Value)), nil
+}
+
+// Update accounts the changes of a write txn to the usage of the prefixes,
+// given the size of the key and value each change replaces, -1 if absent.
+func (s *QuotaStore) Update(changes []mvccpb.KeyValue, prevSizes []int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, pq := range s.prefixes {
+		for i, kv := range changes {
+			if !pq.contains(kv.Key) {
+				continue
+			}
+			size := int64(-1)
+			// deletions only carry the key and the revision
+			if kv.CreateRevision != 0 {
+				size = int64(len(kv.Key) + len(kv.Value))
+			}
+			pq.update(prevSizes[i], size)
+		}
+	}
+}
+
+// Allow consumes a request from the rate limits of the user and of each of
+// its roles at the time ts in nanoseconds. It returns ErrRateLimited without
+// consuming anything if any of them is exhausted.
+func (s *QuotaStore) Allow(user string, roles []string, ts int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.limits) == 0 {
+		return nil
+	}
+
+	var limits []*rateLimit
+	if l, ok := s.limits[rateLimitKey{pb.Quota_USER, user}]; ok {
+		limits = append(limits, l)
+	}
+	for _, role := range roles {
+		if l, ok := s.limits[rateLimitKey{pb.Quota_ROLE, role}]; ok {
+			limits = append(limits, l)
+		}
+	}
+	for _, l := range limits {
+		if l.refill(ts) < nanoTokensPerRequest {
+			return ErrRateLimited
+		}
+	}
+	for _, l := range limits {
+		l.tokens = l.refill(ts) - nanoTokensPerRequest
+		if ts > l.last {
+			l.last = ts
+		}
+		s.be.MustPutRateLimitState(l.quota, l.tokens, l.last)
+	}
+	return nil
+}
+
+func prefixEnd(prefix []byte) []byte {
+	end := make([]byte, len(prefix))
+	copy(end, prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	// the prefix is all 0xff, so the range reaches the end of the keyspace
+	return []byte{}
+}
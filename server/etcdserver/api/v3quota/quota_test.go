@@ -0,0 +1,183 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3quota
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/pkg/v3/traceutil"
+	"go.etcd.io/etcd/server/v3/lease"
+	betesting "go.etcd.io/etcd/server/v3/storage/backend/testing"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+	"go.etcd.io/etcd/server/v3/storage/schema"
+)
+
+func newTestQuotaStore(t *testing.T) (*QuotaStore, mvcc.KV) {
+	lg := zaptest.NewLogger(t)
+	be, _ := betesting.NewDefaultTmpBackend(t)
+	t.Cleanup(func() { betesting.Close(t, be) })
+	kv := mvcc.NewStore(lg, be, &lease.FakeLessor{}, mvcc.StoreConfig{})
+	t.Cleanup(func() { kv.Close() })
+
+	qs, err := NewQuotaStore(lg, schema.NewQuotaBackend(lg, be), kv)
+	require.NoError(t, err)
+	kv.SetChangeHook(qs.Update)
+	return qs, kv
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		quota     pb.Quota
+		wantErr   bool
+		wantBurst int64
+	}{
+		{name: "prefix bytes", quota: pb.Quota{Prefix: []byte("/a/"), MaxBytes: 10}},
+		{name: "prefix keys", quota: pb.Quota{Prefix: []byte("/a/"), MaxKeys: 10}},
+		{name: "prefix without limit", quota: pb.Quota{Prefix: []byte("/a/")}, wantErr: true},
+		{name: "empty prefix", quota: pb.Quota{MaxKeys: 10}, wantErr: true},
+		{name: "prefix with rate", quota: pb.Quota{Prefix: []byte("/a/"), MaxKeys: 10, RequestsPerSecond: 1}, wantErr: true},
+		{name: "user default burst", quota: pb.Quota{Type: pb.Quota_USER, Name: "alice", RequestsPerSecond: 5}, wantBurst: 5},
+		{name: "role burst", quota: pb.Quota{Type: pb.Quota_ROLE, Name: "r", RequestsPerSecond: 5, Burst: 20}, wantBurst: 20},
+		{name: "user without rate", quota: pb.Quota{Type: pb.Quota_USER, Name: "alice"}, wantErr: true},
+		{name: "user without name", quota: pb.Quota{Type: pb.Quota_USER, RequestsPerSecond: 5}, wantErr: true},
+		{name: "rate too large", quota: pb.Quota{Type: pb.Quota_USER, Name: "alice", RequestsPerSecond: maxRequestsPerSecond + 1}, wantErr: true},
+		{name: "unknown type", quota: pb.Quota{Type: 7, Name: "alice", RequestsPerSecond: 5}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(&tt.quota)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidQuota)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantBurst, tt.quota.Burst)
+		})
+	}
+}
+
+func TestQuotaStorePrefixUsage(t *testing.T) {
+	qs, kv := newTestQuotaStore(t)
+	kv.Put([]byte("/a/1"), []byte("xx"), lease.NoLease)
+	kv.Put([]byte("/b/1"), []byte("xx"), lease.NoLease)
+
+	require.NoError(t, qs.Set(&pb.Quota{Prefix: []byte("/a/"), MaxBytes: 16, MaxKeys: 2}))
+	assertUsage(t, qs, 6, 1)
+
+	kv.Put([]byte("/a/2"), []byte("yyyy"), lease.NoLease)
+	assertUsage(t, qs, 14, 2)
+	kv.Put([]byte("/a/2"), []byte("y"), lease.NoLease)
+	assertUsage(t, qs, 11, 2)
+	kv.DeleteRange([]byte("/a/1"), nil)
+	assertUsage(t, qs, 5, 1)
+
+	assert.NoError(t, qs.CheckPuts([]*pb.PutRequest{{Key: []byte("/a/3"), Value: []byte("zzzz")}}))
+	assert.ErrorIs(t, qs.CheckPuts([]*pb.PutRequest{{Key: []byte("/a/3"), Value: []byte("zzzzzzzz")}}), ErrQuotaExceeded)
+	assert.ErrorIs(t, qs.CheckPuts([]*pb.PutRequest{
+		{Key: []byte("/a/3"), Value: []byte("z")},
+		{Key: []byte("/a/4"), Value: []byte("z")},
+	}), ErrQuotaExceeded)
+	assert.NoError(t, qs.CheckPuts([]*pb.PutRequest{{Key: []byte("/b/2"), Value: []byte("zzzzzzzzzzzzzzzz")}}))
+
+	// lowering the quota below the usage still allows shrinking the prefix
+	require.NoError(t, qs.Set(&pb.Quota{Prefix: []byte("/a/"), MaxBytes: 1}))
+	assertUsage(t, qs, 5, 1)
+	assert.NoError(t, qs.CheckPuts([]*pb.PutRequest{{Key: []byte("/a/2"), Value: []byte("")}}))
+	assert.ErrorIs(t, qs.CheckPuts([]*pb.PutRequest{{Key: []byte("/a/2"), Value: []byte("yy")}}), ErrQuotaExceeded)
+
+	// the changes of a txn are accounted one after another
+	txn := kv.Write(traceutil.TODO())
+	txn.Put([]byte("/a/3"), []byte("z"), lease.NoLease)
+	txn.Put([]byte("/a/3"), []byte("zzz"), lease.NoLease)
+	txn.DeleteRange([]byte("/a/2"), nil)
+	txn.End()
+	assertUsage(t, qs, 7, 1)
+
+	require.NoError(t, qs.Delete(pb.Quota_PREFIX, []byte("/a/"), ""))
+	assert.Empty(t, qs.List())
+	assert.ErrorIs(t, qs.Delete(pb.Quota_PREFIX, []byte("/a/"), ""), ErrQuotaNotFound)
+}
+
+func TestQuotaStoreRestore(t *testing.T) {
+	lg := zaptest.NewLogger(t)
+	be, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, be)
+	kv := mvcc.NewStore(lg, be, &lease.FakeLessor{}, mvcc.StoreConfig{})
+	defer kv.Close()
+	kv.Put([]byte("/a/1"), []byte("xx"), lease.NoLease)
+
+	qs, err := NewQuotaStore(lg, schema.NewQuotaBackend(lg, be), kv)
+	require.NoError(t, err)
+	require.NoError(t, qs.Set(&pb.Quota{Prefix: []byte("/a/"), MaxKeys: 10}))
+	require.NoError(t, qs.Set(&pb.Quota{Type: pb.Quota_USER, Name: "alice", RequestsPerSecond: 1}))
+	ts := time.Now().UnixNano()
+	require.NoError(t, qs.Allow("alice", nil, ts))
+
+	qs, err = NewQuotaStore(lg, schema.NewQuotaBackend(lg, be), kv)
+	require.NoError(t, err)
+	quotas := qs.List()
+	require.Len(t, quotas, 2)
+	assert.Equal(t, int64(1), quotas[0].UsedKeys)
+	assert.Equal(t, "alice", quotas[1].Name)
+	// the consumed token is restored
+	assert.ErrorIs(t, qs.Allow("alice", nil, ts), ErrRateLimited)
+}
+
+func TestQuotaStoreAllow(t *testing.T) {
+	qs, _ := newTestQuotaStore(t)
+	require.NoError(t, qs.Set(&pb.Quota{Type: pb.Quota_USER, Name: "alice", RequestsPerSecond: 2, Burst: 2}))
+	require.NoError(t, qs.Set(&pb.Quota{Type: pb.Quota_ROLE, Name: "tenant", RequestsPerSecond: 1, Burst: 3}))
+
+	ts := time.Now().UnixNano()
+	assert.NoError(t, qs.Allow("alice", nil, ts))
+	assert.NoError(t, qs.Allow("alice", nil, ts))
+	assert.ErrorIs(t, qs.Allow("alice", nil, ts), ErrRateLimited)
+	// half a second refills one request
+	assert.NoError(t, qs.Allow("alice", nil, ts+int64(500*time.Millisecond)))
+	assert.ErrorIs(t, qs.Allow("alice", nil, ts+int64(500*time.Millisecond)), ErrRateLimited)
+	// requests proposed earlier do not refill the bucket
+	assert.ErrorIs(t, qs.Allow("alice", nil, ts), ErrRateLimited)
+
+	// users without a rate limit are only limited by their roles
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, qs.Allow("bob", []string{"tenant"}, ts))
+	}
+	assert.ErrorIs(t, qs.Allow("bob", []string{"tenant"}, ts), ErrRateLimited)
+	assert.NoError(t, qs.Allow("bob", []string{"other"}, ts))
+
+	// a rejected request does not consume the other buckets
+	ts += int64(10 * time.Second)
+	assert.NoError(t, qs.Allow("alice", nil, ts))
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, qs.Allow("bob", []string{"tenant"}, ts))
+	}
+	assert.ErrorIs(t, qs.Allow("alice", []string{"tenant"}, ts), ErrRateLimited)
+	assert.NoError(t, qs.Allow("alice", nil, ts))
+}
+
+func assertUsage(t *testing.T, qs *QuotaStore, bytes, keys int64) {
+	t.Helper()
+	quotas := qs.List()
+	require.Len(t, quotas, 1)
+	assert.Equal(t, bytes, quotas[0].UsedBytes)
+	assert.Equal(t, keys, quotas[0].UsedKeys)
+}
@@ -0,0 +1,236 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3rpc
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/server/v3/audit"
+	"go.etcd.io/etcd/server/v3/etcdserver"
+	"go.etcd.io/etcd/server/v3/etcdserver/txn"
+)
+
+// newAuditUnaryInterceptor records the unary requests of the enabled audit level
+// with their results to the audit log of the server.
+func newAuditUnaryInterceptor(s *etcdserver.EtcdServer) grpc.UnaryServerInterceptor {
+	al := s.AuditLogger()
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+
+		level, ev := newAuditEvent(req, resp)
+		if ev == nil || !al.Enabled(level) {
+			return resp, err
+		}
+		ev.Operation = path.Base(info.FullMethod)
+		if ev.User == "" {
+			if ai, aerr := s.AuthInfoFromCtx(ctx); aerr == nil && ai != nil {
+				ev.User = ai.Username
+			}
+		}
+		if p, ok := peer.FromContext(ctx); ok {
+			ev.ClientAddr = p.Addr.String()
+		}
+		if h, ok := resp.(interface{ GetHeader() *pb.ResponseHeader }); ok && err == nil {
+			ev.Revision = h.GetHeader().GetRevision()
+		}
+		ev.Result = audit.ResultSuccess
+		if err != nil {
+			ev.Result = audit.ResultFailure
+			ev.Error = err.Error()
+		}
+		al.Log(ev)
+		return resp, err
+	}
+}
+
+// newAuditEvent returns the audit level of the request and an event describing it,
+// or a nil event if the request is not audited.
+func newAuditEvent(req, resp interface{}) (audit.Level, *audit.Event) {
+	switch r := req.(type) {
+	case *pb.RangeRequest:
+		return audit.LevelRead, &audit.Event{Key: string(r.Key), RangeEnd: string(r.RangeEnd)}
+	case *pb.PutRequest:
+		return audit.LevelWrite, &audit.Event{Key: string(r.Key), Lease: r.Lease}
+	case *pb.DeleteRangeRequest:
+		return audit.LevelWrite, &audit.Event{Key: string(r.Key), RangeEnd: string(r.RangeEnd)}
+	case *pb.TxnRequest:
+		level := audit.LevelWrite
+		if txn.IsTxnReadonly(r) {
+			level = audit.LevelRead
+		}
+		txnResp, _ := resp.(*pb.TxnResponse)
+		return level, &audit.Event{Ops: txnAuditOps(r, txnResp)}
+	case *pb.CompactionRequest:
+		return audit.LevelWrite, &audit.Event{Target: fmt.Sprint(r.Revision)}
+
+	case *pb.LeaseGrantRequest:
+		ev := &audit.Event{Lease: r.ID}
+		if lr, ok := resp.(*pb.LeaseGrantResponse); ok && lr != nil {
+			ev.Lease = lr.ID
+		}
+		return audit.LevelWrite, ev
+	case *pb.LeaseRevokeRequest:
+		return audit.LevelWrite, &audit.Event{Lease: r.ID}
+	case *pb.LeaseTimeToLiveRequest:
+		return audit.LevelRead, &audit.Event{Lease: r.ID}
+	case *pb.LeaseLeasesRequest, *pb.LeaseListRequest:
+		return audit.LevelRead, &audit.Event{}
+	case *pb.LeaseAttachRequest:
+		return audit.LevelWrite, &audit.Event{Lease: r.ID, Ops: attachAuditOps("attach", r.Keys, r.Prefix)}
+	case *pb.LeaseDetachRequest:
+		return audit.LevelWrite, &audit.Event{Ops: attachAuditOps("detach", r.Keys, r.Prefix)}
+
+	case *pb.AuthEnableRequest, *pb.AuthDisableRequest:
+		return audit.LevelAdmin, &audit.Event{}
+	case *pb.AuthenticateRequest:
+		return audit.LevelAdmin, &audit.Event{User: r.Name}
+	case *pb.AuthUserAddRequest:
+		return audit.LevelAdmin, &audit.Event{Target: r.Name}
+	case *pb.AuthUserDeleteRequest:
+		return audit.LevelAdmin, &audit.Event{Target: r.Name}
+	case *pb.AuthUserChangePasswordRequest:
+		return audit.LevelAdmin, &audit.Event{Target: r.Name}
+	case *pb.AuthUserGrantRoleRequest:
+		return audit.LevelAdmin, &audit.Event{Target: r.User, Role: r.Role}
+	case *pb.AuthUserRevokeRoleRequest:
+		return audit.LevelAdmin, &audit.Event{Target: r.Name, Role: r.Role}
+	case *pb.AuthRoleAddRequest:
+		return audit.LevelAdmin, &audit.Event{Role: r.Name}
+	case *pb.AuthRoleDeleteRequest:
+		return audit.LevelAdmin, &audit.Event{Role: r.Role}
+	case *pb.AuthRoleGrantPermissionRequest:
+		ev := &audit.Event{Role: r.Name}
+		if r.Perm != nil {
+			ev.Key, ev.RangeEnd = string(r.Perm.Key), string(r.Perm.RangeEnd)
+		}
+		return audit.LevelAdmin, ev
+	case *pb.AuthRoleRevokePermissionRequest:
+		return audit.LevelAdmin, &audit.Event{Role: r.Role, Key: string(r.Key), RangeEnd: string(r.RangeEnd)}
+	case *pb.AuthStatusRequest, *pb.AuthUserListRequest, *pb.AuthRoleListRequest:
+		return audit.LevelRead, &audit.Event{}
+	case *pb.AuthUserGetRequest:
+		return audit.LevelRead, &audit.Event{Target: r.Name}
+	case *pb.AuthRoleGetRequest:
+		return audit.LevelRead, &audit.Event{Role: r.Role}
+
+	case *pb.MemberAddRequest:
+		ev := &audit.Event{}
+		if mr, ok := resp.(*pb.MemberAddResponse); ok && mr != nil && mr.Member != nil {
+			ev.Target = fmt.Sprintf("%x", mr.Member.ID)
+		}
+		return audit.LevelAdmin, ev
+	case *pb.MemberRemoveRequest:
+		return audit.LevelAdmin, &audit.Event{Target: fmt.Sprintf("%x", r.ID)}
+	case *pb.MemberUpdateRequest:
+		return audit.LevelAdmin, &audit.Event{Target: fmt.Sprintf("%x", r.ID)}
+	case *pb.MemberPromoteRequest:
+		return audit.LevelAdmin, &audit.Event{Target: fmt.Sprintf("%x", r.ID)}
+	case *pb.MemberListRequest:
+		return audit.LevelRead, &audit.Event{}
+
+	case *pb.AlarmRequest:
+		if r.Action == pb.AlarmRequest_GET {
+			return audit.LevelRead, &audit.Event{}
+		}
+		return audit.LevelAdmin, &audit.Event{Target: fmt.Sprintf("%x", r.MemberID)}
+	case *pb.DefragmentRequest, *pb.DowngradeRequest:
+		return audit.LevelAdmin, &audit.Event{}
+	case *pb.MoveLeaderRequest:
+		return audit.LevelAdmin, &audit.Event{Target: fmt.Sprintf("%x", r.TargetID)}
+	case *pb.StatusRequest, *pb.HashRequest, *pb.HashKVRequest:
+		return audit.LevelRead, &audit.Event{}
+	case *pb.QuotaSetRequest:
+		if r.Quota == nil {
+			return audit.LevelAdmin, &audit.Event{}
+		}
+		return audit.LevelAdmin, &audit.Event{Key: string(r.Quota.Prefix), Target: r.Quota.Name}
+	case *pb.QuotaDeleteRequest:
+		return audit.LevelAdmin, &audit.Event{Key: string(r.Prefix), Target: r.Name}
+	case *pb.QuotaListRequest, *pb.CompactionRetentionRequest, *pb.CompactionHoldListRequest:
+		return audit.LevelRead, &audit.Event{}
+	case *pb.CompactionHoldRequest:
+		ev := &audit.Event{Target: fmt.Sprint(r.Revision), Lease: r.Lease}
+		if hr, ok := resp.(*pb.CompactionHoldResponse); ok && hr != nil {
+			ev.Target = fmt.Sprint(hr.Revision)
+		}
+		return audit.LevelAdmin, ev
+	case *pb.CompactionHoldReleaseRequest:
+		return audit.LevelAdmin, &audit.Event{Target: fmt.Sprintf("%x", r.ID)}
+	}
+	return "", nil
+}
+
+// txnAuditOps flattens the operations executed by the transaction. All the
+// operations of both branches are returned if it is unknown which one is executed.
+func txnAuditOps(r *pb.TxnRequest, resp *pb.TxnResponse) []audit.Op {
+	reqs := append(r.Success[:len(r.Success):len(r.Success)], r.Failure...)
+	var resps []*pb.ResponseOp
+	if resp != nil {
+		reqs, resps = r.Failure, resp.Responses
+		if resp.Succeeded {
+			reqs = r.Success
+		}
+	}
+
+	var ops []audit.Op
+	for i, op := range reqs {
+		switch tv := op.Request.(type) {
+		case *pb.RequestOp_RequestRange:
+			ops = append(ops, audit.Op{Type: "range", Key: string(tv.RequestRange.Key), RangeEnd: string(tv.RequestRange.RangeEnd)})
+		case *pb.RequestOp_RequestPut:
+			ops = append(ops, audit.Op{Type: "put", Key: string(tv.RequestPut.Key)})
+		case *pb.RequestOp_RequestIncr:
+			ops = append(ops, audit.Op{Type: "incr", Key: string(tv.RequestIncr.Key)})
+		case *pb.RequestOp_RequestAppend:
+			ops = append(ops, audit.Op{Type: "append", Key: string(tv.RequestAppend.Key)})
+		case *pb.RequestOp_RequestDeleteRange:
+			ops = append(ops, audit.Op{Type: "delete", Key: string(tv.RequestDeleteRange.Key), RangeEnd: string(tv.RequestDeleteRange.RangeEnd)})
+		case *pb.RequestOp_RequestTxn:
+			var txnResp *pb.TxnResponse
+			if i < len(resps) {
+				txnResp = resps[i].GetResponseTxn()
+			}
+			ops = append(ops, txnAuditOps(tv.RequestTxn, txnResp)...)
+		}
+	}
+	return ops
+}
+
+// attachAuditOps returns an operation per key attached or detached, over the
+// range of the keys prefixed by the key if prefix is true.
+func attachAuditOps(typ string, keys [][]byte, prefix bool) []audit.Op {
+	ops := make([]audit.Op, len(keys))
+	for i, key := range keys {
+		ops[i] = audit.Op{Type: typ, Key: string(key)}
+		if prefix {
+			ops[i].RangeEnd = "\x00"
+			end := []byte(string(key))
+			for j := len(end) - 1; j >= 0; j-- {
+				if end[j] < 0xff {
+					end[j]++
+					ops[i].RangeEnd = string(end[:j+1])
+					break
+				}
+			}
+		}
+	}
+	return ops
+}
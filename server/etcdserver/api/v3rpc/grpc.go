@@ -47,6 +47,9 @@ func Server(s *etcdserver.EtcdServer, tls *tls.Config, interceptor grpc.UnarySer
 		newUnaryInterceptor(s),
 		grpc_prometheus.UnaryServerInterceptor,
 	}
+	if s.AuditLogger() != nil {
+		chainUnaryInterceptors = append(chainUnaryInterceptors, newAuditUnaryInterceptor(s))
+	}
 	if interceptor != nil {
 		chainUnaryInterceptors = append(chainUnaryInterceptors, interceptor)
 	}
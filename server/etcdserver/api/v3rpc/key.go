@@ -52,6 +52,35 @@ func (s *kvServer) Range(ctx context.Context, r *pb.RangeRequest) (*pb.RangeResp
 	return resp, nil
 }
 
+func (s *kvServer) RangeStream(r *pb.RangeRequest, stream pb.KV_RangeStreamServer) error {
+	if err := checkRangeRequest(r); err != nil {
+		return err
+	}
+
+	err := s.kv.RangeStream(stream.Context(), r, func(resp *pb.RangeResponse) error {
+		s.hdr.fill(resp.Header)
+		return stream.Send(resp)
+	})
+	if err != nil {
+		return togRPCError(err)
+	}
+	return nil
+}
+
+func (s *kvServer) History(ctx context.Context, r *pb.HistoryRequest) (*pb.HistoryResponse, error) {
+	if len(r.Key) == 0 {
+		return nil, rpctypes.ErrGRPCEmptyKey
+	}
+
+	resp, err := s.kv.History(ctx, r)
+	if err != nil {
+		return nil, togRPCError(err)
+	}
+
+	s.hdr.fill(resp.Header)
+	return resp, nil
+}
+
 func (s *kvServer) Put(ctx context.Context, r *pb.PutRequest) (*pb.PutResponse, error) {
 	if err := checkPutRequest(r); err != nil {
 		return nil, err
@@ -124,6 +153,25 @@ func checkRangeRequest(r *pb.RangeRequest) error {
 		return rpctypes.ErrGRPCInvalidSortOption
 	}
 
+	if f := r.Filter; f != nil {
+		if _, ok := pb.RangeFilter_LeaseCondition_name[int32(f.Lease)]; !ok {
+			return rpctypes.ErrGRPCInvalidRangeFilter
+		}
+		if f.MinValueSize < 0 || f.MaxValueSize < 0 || f.MinVersion < 0 || f.MaxVersion < 0 {
+			return rpctypes.ErrGRPCInvalidRangeFilter
+		}
+	}
+
+	for _, f := range r.Projection {
+		if _, ok := pb.KeyValueField_name[int32(f)]; !ok {
+			return rpctypes.ErrGRPCInvalidRangeFilter
+		}
+	}
+
+	if r.AtTime != 0 && r.Revision != 0 {
+		return rpctypes.ErrGRPCInvalidAtTime
+	}
+
 	return nil
 }
 
@@ -140,6 +188,20 @@ func checkPutRequest(r *pb.PutRequest) error {
 	return nil
 }
 
+func checkIncrRequest(r *pb.IncrRequest) error {
+	if len(r.Key) == 0 {
+		return rpctypes.ErrGRPCEmptyKey
+	}
+	return nil
+}
+
+func checkAppendRequest(r *pb.AppendRequest) error {
+	if len(r.Key) == 0 {
+		return rpctypes.ErrGRPCEmptyKey
+	}
+	return nil
+}
+
 func checkDeleteRequest(r *pb.DeleteRangeRequest) error {
 	if len(r.Key) == 0 {
 		return rpctypes.ErrGRPCEmptyKey
@@ -246,11 +308,11 @@ func checkIntervals(reqs []*pb.RequestOp) (map[string]struct{}, adt.IntervalTree
 
 	// collect and check this level's puts
 	for _, req := range reqs {
-		tv, ok := req.Request.(*pb.RequestOp_RequestPut)
-		if !ok || tv.RequestPut == nil {
+		key, ok := putKey(req)
+		if !ok {
 			continue
 		}
-		k := string(tv.RequestPut.Key)
+		k := string(key)
 		if _, ok := puts[k]; ok {
 			return nil, dels, rpctypes.ErrGRPCDuplicateKey
 		}
@@ -262,15 +324,41 @@ func checkIntervals(reqs []*pb.RequestOp) (map[string]struct{}, adt.IntervalTree
 	return puts, dels, nil
 }
 
+// putKey returns the key written by a put, incr or append request.
+func putKey(req *pb.RequestOp) ([]byte, bool) {
+	switch tv := req.Request.(type) {
+	case *pb.RequestOp_RequestPut:
+		if tv.RequestPut != nil {
+			return tv.RequestPut.Key, true
+		}
+	case *pb.RequestOp_RequestIncr:
+		if tv.RequestIncr != nil {
+			return tv.RequestIncr.Key, true
+		}
+	case *pb.RequestOp_RequestAppend:
+		if tv.RequestAppend != nil {
+			return tv.RequestAppend.Key, true
+		}
+	}
+	return nil, false
+}
+
 func checkRequestOp(u *pb.RequestOp, maxTxnOps int) error {
 	// TODO: ensure only one of the field is set.
 	switch uv := u.Request.(type) {
 	case *pb.RequestOp_RequestRange:
+		if uv.RequestRange.AtTime != 0 {
+			return rpctypes.ErrGRPCInvalidAtTime
+		}
 		return checkRangeRequest(uv.RequestRange)
 	case *pb.RequestOp_RequestPut:
 		return checkPutRequest(uv.RequestPut)
 	case *pb.RequestOp_RequestDeleteRange:
 		return checkDeleteRequest(uv.RequestDeleteRange)
+	case *pb.RequestOp_RequestIncr:
+		return checkIncrRequest(uv.RequestIncr)
+	case *pb.RequestOp_RequestAppend:
+		return checkAppendRequest(uv.RequestAppend)
 	case *pb.RequestOp_RequestTxn:
 		return checkTxnRequest(uv.RequestTxn, maxTxnOps)
 	default:
@@ -41,6 +41,9 @@ func NewLeaseServer(s *etcdserver.EtcdServer) pb.LeaseServer {
 }
 
 func (ls *LeaseServer) LeaseGrant(ctx context.Context, cr *pb.LeaseGrantRequest) (*pb.LeaseGrantResponse, error) {
+	if err := lease.ValidateLabels(cr.Labels); err != nil {
+		return nil, togRPCError(err)
+	}
 	resp, err := ls.le.LeaseGrant(ctx, cr)
 
 	if err != nil {
@@ -90,6 +93,53 @@ func (ls *LeaseServer) LeaseLeases(ctx context.Context, rr *pb.LeaseLeasesReques
 	return resp, nil
 }
 
+func (ls *LeaseServer) LeaseList(ctx context.Context, lr *pb.LeaseListRequest) (*pb.LeaseListResponse, error) {
+	resp, err := ls.le.LeaseList(ctx, lr)
+	if err != nil {
+		return nil, togRPCError(err)
+	}
+	ls.hdr.fill(resp.Header)
+	return resp, nil
+}
+
+func (ls *LeaseServer) LeaseAttach(ctx context.Context, ar *pb.LeaseAttachRequest) (*pb.LeaseAttachResponse, error) {
+	if err := checkAttachKeys(ar.Keys, ar.Prefix); err != nil {
+		return nil, err
+	}
+	resp, err := ls.le.LeaseAttach(ctx, ar)
+	if err != nil {
+		return nil, togRPCError(err)
+	}
+	ls.hdr.fill(resp.Header)
+	return resp, nil
+}
+
+func (ls *LeaseServer) LeaseDetach(ctx context.Context, dr *pb.LeaseDetachRequest) (*pb.LeaseDetachResponse, error) {
+	if err := checkAttachKeys(dr.Keys, dr.Prefix); err != nil {
+		return nil, err
+	}
+	resp, err := ls.le.LeaseDetach(ctx, dr)
+	if err != nil {
+		return nil, togRPCError(err)
+	}
+	ls.hdr.fill(resp.Header)
+	return resp, nil
+}
+
+// checkAttachKeys checks that the keys to attach or detach are not empty,
+// unless they are prefixes.
+func checkAttachKeys(keys [][]byte, prefix bool) error {
+	if prefix {
+		return nil
+	}
+	for _, key := range keys {
+		if len(key) == 0 {
+			return rpctypes.ErrGRPCEmptyKey
+		}
+	}
+	return nil
+}
+
 func (ls *LeaseServer) LeaseKeepAlive(stream pb.Lease_LeaseKeepAliveServer) (err error) {
 	errc := make(chan error, 1)
 	go func() {
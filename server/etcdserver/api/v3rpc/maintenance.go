@@ -56,6 +56,22 @@ type Downgrader interface {
 	Downgrade(ctx context.Context, dr *pb.DowngradeRequest) (*pb.DowngradeResponse, error)
 }
 
+type Quotaer interface {
+	QuotaSet(ctx context.Context, r *pb.QuotaSetRequest) (*pb.QuotaSetResponse, error)
+	QuotaDelete(ctx context.Context, r *pb.QuotaDeleteRequest) (*pb.QuotaDeleteResponse, error)
+	QuotaList(ctx context.Context, r *pb.QuotaListRequest) (*pb.QuotaListResponse, error)
+}
+
+type CompactionRetentioner interface {
+	CompactionRetention(ctx context.Context, r *pb.CompactionRetentionRequest) (*pb.CompactionRetentionResponse, error)
+}
+
+type CompactionHolder interface {
+	CompactionHold(ctx context.Context, r *pb.CompactionHoldRequest) (*pb.CompactionHoldResponse, error)
+	CompactionHoldRelease(ctx context.Context, r *pb.CompactionHoldReleaseRequest) (*pb.CompactionHoldReleaseResponse, error)
+	CompactionHoldList(ctx context.Context, r *pb.CompactionHoldListRequest) (*pb.CompactionHoldListResponse, error)
+}
+
 type LeaderTransferrer interface {
 	MoveLeader(ctx context.Context, lead, target uint64) error
 }
@@ -74,11 +90,14 @@ type maintenanceServer struct {
 	hdr    header
 	cs     ClusterStatusGetter
 	d      Downgrader
+	q      Quotaer
+	cr     CompactionRetentioner
+	ch     CompactionHolder
 	vs     serverversion.Server
 }
 
 func NewMaintenanceServer(s *etcdserver.EtcdServer) pb.MaintenanceServer {
-	srv := &maintenanceServer{lg: s.Cfg.Logger, rg: s, hasher: s.KV().HashStorage(), bg: s, a: s, lt: s, hdr: newHeader(s), cs: s, d: s, vs: etcdserver.NewServerVersionAdapter(s)}
+	srv := &maintenanceServer{lg: s.Cfg.Logger, rg: s, hasher: s.KV().HashStorage(), bg: s, a: s, lt: s, hdr: newHeader(s), cs: s, d: s, q: s, cr: s, ch: s, vs: etcdserver.NewServerVersionAdapter(s)}
 	if srv.lg == nil {
 		srv.lg = zap.NewNop()
 	}
@@ -86,11 +105,16 @@ func NewMaintenanceServer(s *etcdserver.EtcdServer) pb.MaintenanceServer {
 }
 
 func (ms *maintenanceServer) Defragment(ctx context.Context, sr *pb.DefragmentRequest) (*pb.DefragmentResponse, error) {
-	ms.lg.Info("starting defragment")
-	err := ms.bg.Backend().Defrag()
+	ms.lg.Info("starting defragment", zap.Bool("online", sr.Online))
+	var err error
+	if sr.Online {
+		err = ms.bg.Backend().DefragOnline()
+	} else {
+		err = ms.bg.Backend().Defrag()
+	}
 	if err != nil {
 		ms.lg.Warn("failed to defragment", zap.Error(err))
-		return nil, err
+		return nil, togRPCError(err)
 	}
 	ms.lg.Info("finished defragment")
 	return &pb.DefragmentResponse{}, nil
@@ -272,6 +296,69 @@ func (ms *maintenanceServer) Downgrade(ctx context.Context, r *pb.DowngradeReque
 	return resp, nil
 }
 
+func (ms *maintenanceServer) QuotaSet(ctx context.Context, r *pb.QuotaSetRequest) (*pb.QuotaSetResponse, error) {
+	resp, err := ms.q.QuotaSet(ctx, r)
+	if err != nil {
+		return nil, togRPCError(err)
+	}
+	ms.hdr.fill(resp.Header)
+	return resp, nil
+}
+
+func (ms *maintenanceServer) QuotaDelete(ctx context.Context, r *pb.QuotaDeleteRequest) (*pb.QuotaDeleteResponse, error) {
+	resp, err := ms.q.QuotaDelete(ctx, r)
+	if err != nil {
+		return nil, togRPCError(err)
+	}
+	ms.hdr.fill(resp.Header)
+	return resp, nil
+}
+
+func (ms *maintenanceServer) QuotaList(ctx context.Context, r *pb.QuotaListRequest) (*pb.QuotaListResponse, error) {
+	resp, err := ms.q.QuotaList(ctx, r)
+	if err != nil {
+		return nil, togRPCError(err)
+	}
+	ms.hdr.fill(resp.Header)
+	return resp, nil
+}
+
+func (ms *maintenanceServer) CompactionRetention(ctx context.Context, r *pb.CompactionRetentionRequest) (*pb.CompactionRetentionResponse, error) {
+	resp, err := ms.cr.CompactionRetention(ctx, r)
+	if err != nil {
+		return nil, togRPCError(err)
+	}
+	ms.hdr.fill(resp.Header)
+	return resp, nil
+}
+
+func (ms *maintenanceServer) CompactionHold(ctx context.Context, r *pb.CompactionHoldRequest) (*pb.CompactionHoldResponse, error) {
+	resp, err := ms.ch.CompactionHold(ctx, r)
+	if err != nil {
+		return nil, togRPCError(err)
+	}
+	ms.hdr.fill(resp.Header)
+	return resp, nil
+}
+
+func (ms *maintenanceServer) CompactionHoldRelease(ctx context.Context, r *pb.CompactionHoldReleaseRequest) (*pb.CompactionHoldReleaseResponse, error) {
+	resp, err := ms.ch.CompactionHoldRelease(ctx, r)
+	if err != nil {
+		return nil, togRPCError(err)
+	}
+	ms.hdr.fill(resp.Header)
+	return resp, nil
+}
+
+func (ms *maintenanceServer) CompactionHoldList(ctx context.Context, r *pb.CompactionHoldListRequest) (*pb.CompactionHoldListResponse, error) {
+	resp, err := ms.ch.CompactionHoldList(ctx, r)
+	if err != nil {
+		return nil, togRPCError(err)
+	}
+	ms.hdr.fill(resp.Header)
+	return resp, nil
+}
+
 type authMaintenanceServer struct {
 	*maintenanceServer
 	*AuthAdmin
@@ -331,3 +418,27 @@ func (ams *authMaintenanceServer) Downgrade(ctx context.Context, r *pb.Downgrade
 
 	return ams.maintenanceServer.Downgrade(ctx, r)
 }
+
+func (ams *authMaintenanceServer) QuotaList(ctx context.Context, r *pb.QuotaListRequest) (*pb.QuotaListResponse, error) {
+	if err := ams.isPermitted(ctx); err != nil {
+		return nil, err
+	}
+
+	return ams.maintenanceServer.QuotaList(ctx, r)
+}
+
+func (ams *authMaintenanceServer) CompactionRetention(ctx context.Context, r *pb.CompactionRetentionRequest) (*pb.CompactionRetentionResponse, error) {
+	if err := ams.isPermitted(ctx); err != nil {
+		return nil, err
+	}
+
+	return ams.maintenanceServer.CompactionRetention(ctx, r)
+}
+
+func (ams *authMaintenanceServer) CompactionHoldList(ctx context.Context, r *pb.CompactionHoldListRequest) (*pb.CompactionHoldListResponse, error) {
+	if err := ams.isPermitted(ctx); err != nil {
+		return nil, err
+	}
+
+	return ams.maintenanceServer.CompactionHoldList(ctx, r)
+}
@@ -22,9 +22,13 @@ import (
 	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	"go.etcd.io/etcd/server/v3/auth"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/membership"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3compactor"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3quota"
 	"go.etcd.io/etcd/server/v3/etcdserver/errors"
+	"go.etcd.io/etcd/server/v3/etcdserver/tindex"
 	"go.etcd.io/etcd/server/v3/etcdserver/version"
 	"go.etcd.io/etcd/server/v3/lease"
+	"go.etcd.io/etcd/server/v3/storage/backend"
 	"go.etcd.io/etcd/server/v3/storage/mvcc"
 
 	"google.golang.org/grpc/codes"
@@ -43,6 +47,7 @@ var toGRPCErrorMap = map[error]error{
 
 	mvcc.ErrCompacted:         rpctypes.ErrGRPCCompacted,
 	mvcc.ErrFutureRev:         rpctypes.ErrGRPCFutureRev,
+	tindex.ErrTimeNotIndexed:  rpctypes.ErrGRPCTimeNotIndexed,
 	errors.ErrRequestTooLarge: rpctypes.ErrGRPCRequestTooLarge,
 	errors.ErrNoSpace:         rpctypes.ErrGRPCNoSpace,
 	errors.ErrTooManyRequests: rpctypes.ErrTooManyRequests,
@@ -57,6 +62,11 @@ var toGRPCErrorMap = map[error]error{
 	errors.ErrTimeoutWaitAppliedIndex:    rpctypes.ErrGRPCTimeoutWaitAppliedIndex,
 	errors.ErrUnhealthy:                  rpctypes.ErrGRPCUnhealthy,
 	errors.ErrKeyNotFound:                rpctypes.ErrGRPCKeyNotFound,
+	errors.ErrInvalidContinueToken:       rpctypes.ErrGRPCInvalidContinueToken,
+	errors.ErrInvalidStreamSort:          rpctypes.ErrGRPCInvalidStreamSort,
+	errors.ErrValueNotInteger:            rpctypes.ErrGRPCValueNotInteger,
+	errors.ErrValueOutOfRange:            rpctypes.ErrGRPCValueOutOfRange,
+	errors.ErrClusterVersionTooLow:       rpctypes.ErrGRPCClusterVersionTooLow,
 	errors.ErrCorrupt:                    rpctypes.ErrGRPCCorrupt,
 	errors.ErrBadLeaderTransferee:        rpctypes.ErrGRPCBadLeaderTransferee,
 
@@ -66,9 +76,21 @@ var toGRPCErrorMap = map[error]error{
 	version.ErrDowngradeInProcess:            rpctypes.ErrGRPCDowngradeInProcess,
 	version.ErrNoInflightDowngrade:           rpctypes.ErrGRPCNoInflightDowngrade,
 
-	lease.ErrLeaseNotFound:    rpctypes.ErrGRPCLeaseNotFound,
-	lease.ErrLeaseExists:      rpctypes.ErrGRPCLeaseExist,
-	lease.ErrLeaseTTLTooLarge: rpctypes.ErrGRPCLeaseTTLTooLarge,
+	lease.ErrLeaseNotFound:        rpctypes.ErrGRPCLeaseNotFound,
+	lease.ErrLeaseExists:          rpctypes.ErrGRPCLeaseExist,
+	lease.ErrLeaseTTLTooLarge:     rpctypes.ErrGRPCLeaseTTLTooLarge,
+	lease.ErrLeaseLabelInvalid:    rpctypes.ErrGRPCLeaseLabelInvalid,
+	lease.ErrLabelSelectorInvalid: rpctypes.ErrGRPCLabelSelectorInvalid,
+
+	v3quota.ErrQuotaExceeded: rpctypes.ErrGRPCQuotaExceeded,
+	v3quota.ErrRateLimited:   rpctypes.ErrGRPCRateLimited,
+	v3quota.ErrInvalidQuota:  rpctypes.ErrGRPCInvalidQuota,
+	v3quota.ErrQuotaNotFound: rpctypes.ErrGRPCQuotaNotFound,
+
+	v3compactor.ErrCompactionHoldExists:   rpctypes.ErrGRPCCompactionHoldExists,
+	v3compactor.ErrCompactionHoldNotFound: rpctypes.ErrGRPCCompactionHoldNotFound,
+
+	backend.ErrDefragInProgress: rpctypes.ErrGRPCDefragInProgress,
 
 	auth.ErrRootUserNotExist:     rpctypes.ErrGRPCRootUserNotExist,
 	auth.ErrRootRoleNotExist:     rpctypes.ErrGRPCRootRoleNotExist,
@@ -87,6 +109,7 @@ var toGRPCErrorMap = map[error]error{
 	auth.ErrInvalidAuthToken:     rpctypes.ErrGRPCInvalidAuthToken,
 	auth.ErrInvalidAuthMgmt:      rpctypes.ErrGRPCInvalidAuthMgmt,
 	auth.ErrAuthOldRevision:      rpctypes.ErrGRPCAuthOldRevision,
+	auth.ErrInvalidPermPattern:   rpctypes.ErrGRPCInvalidPermPattern,
 
 	// In sync with status.FromContextError
 	context.Canceled:         rpctypes.ErrGRPCCanceled,
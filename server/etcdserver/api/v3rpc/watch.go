@@ -15,9 +15,11 @@
 package v3rpc
 
 import (
+	"bytes"
 	"context"
 	"io"
 	"math/rand"
+	"path"
 	"sync"
 	"time"
 
@@ -29,6 +31,7 @@ import (
 	"go.etcd.io/etcd/server/v3/auth"
 	"go.etcd.io/etcd/server/v3/etcdserver"
 	"go.etcd.io/etcd/server/v3/etcdserver/apply"
+	"go.etcd.io/etcd/server/v3/etcdserver/txn"
 	"go.etcd.io/etcd/server/v3/storage/mvcc"
 
 	"go.uber.org/zap"
@@ -135,7 +138,7 @@ type serverWatchStream struct {
 	watchStream mvcc.WatchStream
 	ctrlStream  chan *pb.WatchResponse
 
-	// mu protects progress, prevKV, fragment
+	// mu protects progress, prevKV, fragment, noUnchanged
 	mu sync.RWMutex
 	// tracks the watchID that stream might need to send progress to
 	// TODO: combine progress and prevKV into a single struct?
@@ -144,6 +147,8 @@ type serverWatchStream struct {
 	prevKV map[mvcc.WatchID]bool
 	// records fragmented watch IDs
 	fragment map[mvcc.WatchID]bool
+	// record watch IDs that drop put events which do not change the value
+	noUnchanged map[mvcc.WatchID]bool
 
 	// closec indicates the stream is closed.
 	closec chan struct{}
@@ -174,6 +179,8 @@ func (ws *watchServer) Watch(stream pb.Watch_WatchServer) (err error) {
 		prevKV:   make(map[mvcc.WatchID]bool),
 		fragment: make(map[mvcc.WatchID]bool),
 
+		noUnchanged: make(map[mvcc.WatchID]bool),
+
 		closec: make(chan struct{}),
 	}
 
@@ -302,6 +309,25 @@ func (sws *serverWatchStream) recvLoop() error {
 				}
 			}
 
+			if len(creq.KeyPattern) > 0 {
+				if _, perr := path.Match(creq.KeyPattern, ""); perr != nil {
+					wr := &pb.WatchResponse{
+						Header:       sws.newResponseHeader(sws.watchStream.Rev()),
+						WatchId:      clientv3.InvalidWatchID,
+						Canceled:     true,
+						Created:      true,
+						CancelReason: rpctypes.ErrGRPCInvalidWatchKeyPattern.Error(),
+					}
+
+					select {
+					case sws.ctrlStream <- wr:
+						continue
+					case <-sws.closec:
+						return nil
+					}
+				}
+			}
+
 			filters := FiltersFromRequest(creq)
 
 			wsrev := sws.watchStream.Rev()
@@ -309,7 +335,8 @@ func (sws *serverWatchStream) recvLoop() error {
 			if rev == 0 {
 				rev = wsrev + 1
 			}
-			id, err := sws.watchStream.Watch(mvcc.WatchID(creq.WatchId), creq.Key, creq.RangeEnd, rev, filters...)
+			opts := mvcc.WatchOptions{Filters: filters, Coalesce: creq.Coalesce, InitialState: creq.SendInitialState}
+			id, err := sws.watchStream.WatchWithOptions(mvcc.WatchID(creq.WatchId), creq.Key, creq.RangeEnd, rev, opts)
 			if err == nil {
 				sws.mu.Lock()
 				if creq.ProgressNotify {
@@ -321,6 +348,9 @@ func (sws *serverWatchStream) recvLoop() error {
 				if creq.Fragment {
 					sws.fragment[id] = true
 				}
+				if HasFilter(creq, pb.WatchCreateRequest_NOUNCHANGED) {
+					sws.noUnchanged[id] = true
+				}
 				sws.mu.Unlock()
 			} else {
 				id = clientv3.InvalidWatchID
@@ -355,6 +385,7 @@ func (sws *serverWatchStream) recvLoop() error {
 					delete(sws.progress, mvcc.WatchID(id))
 					delete(sws.prevKV, mvcc.WatchID(id))
 					delete(sws.fragment, mvcc.WatchID(id))
+					delete(sws.noUnchanged, mvcc.WatchID(id))
 					sws.mu.Unlock()
 				}
 			}
@@ -408,19 +439,38 @@ func (sws *serverWatchStream) sendLoop() {
 			// either return []*mvccpb.Event from the mvcc package
 			// or define protocol buffer with []mvccpb.Event.
 			evs := wresp.Events
-			events := make([]*mvccpb.Event, len(evs))
+			events := make([]*mvccpb.Event, 0, len(evs))
 			sws.mu.RLock()
 			needPrevKV := sws.prevKV[wresp.WatchID]
+			noUnchanged := sws.noUnchanged[wresp.WatchID]
 			sws.mu.RUnlock()
+			if wresp.InitialState {
+				// the initial state holds the current key-value pairs, not changes
+				needPrevKV, noUnchanged = false, false
+			}
 			for i := range evs {
-				events[i] = &evs[i]
-				if needPrevKV && !IsCreateEvent(evs[i]) {
+				var prevKV *mvccpb.KeyValue
+				// the unchanged filter only needs the previous key-value pair of put events
+				needRange := needPrevKV || (noUnchanged && evs[i].Type == mvccpb.PUT)
+				if needRange && !IsCreateEvent(evs[i]) {
 					opt := mvcc.RangeOptions{Rev: evs[i].Kv.ModRevision - 1}
 					r, err := sws.watchable.Range(context.TODO(), evs[i].Kv.Key, nil, opt)
 					if err == nil && len(r.KVs) != 0 {
-						events[i].PrevKv = &(r.KVs[0])
+						prevKV = &(r.KVs[0])
 					}
 				}
+				if noUnchanged && evs[i].Type == mvccpb.PUT && prevKV != nil && bytes.Equal(prevKV.Value, evs[i].Kv.Value) {
+					continue
+				}
+				if needPrevKV {
+					evs[i].PrevKv = prevKV
+				}
+				events = append(events, &evs[i])
+			}
+			if len(evs) > 0 && len(events) == 0 && wresp.CompactRevision == 0 {
+				// all events were dropped by the unchanged filter
+				mvcc.ReportEventReceived(len(evs))
+				continue
 			}
 
 			canceled := wresp.CompactRevision != 0
@@ -431,11 +481,24 @@ func (sws *serverWatchStream) sendLoop() {
 				CompactRevision: wresp.CompactRevision,
 				Canceled:        canceled,
 			}
+			wrs := []*pb.WatchResponse{wr}
+			if wresp.InitialStateDone {
+				// the initial state is ended by a response without events
+				// following its last page
+				done := &pb.WatchResponse{
+					Header:           sws.newResponseHeader(wresp.Revision),
+					WatchId:          int64(wresp.WatchID),
+					InitialStateDone: true,
+				}
+				if len(events) == 0 {
+					wrs = wrs[:0]
+				}
+				wrs = append(wrs, done)
+			}
 
 			if _, okID := ids[wresp.WatchID]; !okID {
 				// buffer if id not yet announced
-				wrs := append(pending[wresp.WatchID], wr)
-				pending[wresp.WatchID] = wrs
+				pending[wresp.WatchID] = append(pending[wresp.WatchID], wrs...)
 				continue
 			}
 
@@ -445,21 +508,23 @@ func (sws *serverWatchStream) sendLoop() {
 			fragmented, ok := sws.fragment[wresp.WatchID]
 			sws.mu.RUnlock()
 
-			var serr error
-			if !fragmented && !ok {
-				serr = sws.gRPCStream.Send(wr)
-			} else {
-				serr = sendFragments(wr, sws.maxRequestBytes, sws.gRPCStream.Send)
-			}
-
-			if serr != nil {
-				if isClientCtxErr(sws.gRPCStream.Context().Err(), serr) {
-					sws.lg.Debug("failed to send watch response to gRPC stream", zap.Error(serr))
+			for _, wr := range wrs {
+				var serr error
+				if !fragmented && !ok {
+					serr = sws.gRPCStream.Send(wr)
 				} else {
-					sws.lg.Warn("failed to send watch response to gRPC stream", zap.Error(serr))
-					streamFailures.WithLabelValues("send", "watch").Inc()
+					serr = sendFragments(wr, sws.maxRequestBytes, sws.gRPCStream.Send)
+				}
+
+				if serr != nil {
+					if isClientCtxErr(sws.gRPCStream.Context().Err(), serr) {
+						sws.lg.Debug("failed to send watch response to gRPC stream", zap.Error(serr))
+					} else {
+						sws.lg.Warn("failed to send watch response to gRPC stream", zap.Error(serr))
+						streamFailures.WithLabelValues("send", "watch").Inc()
+					}
+					return
 				}
-				return
 			}
 
 			sws.mu.Lock()
@@ -593,9 +658,21 @@ func filterNoPut(e mvccpb.Event) bool {
 	return e.Type == mvccpb.PUT
 }
 
+// HasFilter checks whether a given watch create request has the filter type.
+func HasFilter(creq *pb.WatchCreateRequest, ft pb.WatchCreateRequest_FilterType) bool {
+	for _, f := range creq.Filters {
+		if f == ft {
+			return true
+		}
+	}
+	return false
+}
+
 // FiltersFromRequest returns "mvcc.FilterFunc" from a given watch create request.
+// The NOUNCHANGED filter needs the previous key-value pair of an event and is
+// applied by the watch server when sending the events instead.
 func FiltersFromRequest(creq *pb.WatchCreateRequest) []mvcc.FilterFunc {
-	filters := make([]mvcc.FilterFunc, 0, len(creq.Filters))
+	filters := make([]mvcc.FilterFunc, 0, len(creq.Filters)+4)
 	for _, ft := range creq.Filters {
 		switch ft {
 		case pb.WatchCreateRequest_NOPUT:
@@ -605,5 +682,29 @@ func FiltersFromRequest(creq *pb.WatchCreateRequest) []mvcc.FilterFunc {
 		default:
 		}
 	}
+	if kvf := txn.NewRangeFilter(creq.KvFilter); kvf != nil {
+		filters = append(filters, func(e mvccpb.Event) bool {
+			return e.Type == mvccpb.PUT && !kvf(e.Kv)
+		})
+	}
+	if creq.Lease != 0 {
+		lease := creq.Lease
+		filters = append(filters, func(e mvccpb.Event) bool {
+			return e.Type == mvccpb.PUT && e.Kv.Lease != lease
+		})
+	}
+	if len(creq.KeySuffix) > 0 {
+		suffix := creq.KeySuffix
+		filters = append(filters, func(e mvccpb.Event) bool {
+			return !bytes.HasSuffix(e.Kv.Key, suffix)
+		})
+	}
+	if len(creq.KeyPattern) > 0 {
+		pattern := creq.KeyPattern
+		filters = append(filters, func(e mvccpb.Event) bool {
+			matched, err := path.Match(pattern, string(e.Kv.Key))
+			return err != nil || !matched
+		})
+	}
 	return filters
 }
@@ -93,3 +93,47 @@ func createResponse(dataSize, events int) (resp *pb.WatchResponse) {
 	}
 	return resp
 }
+
+func TestFiltersFromRequest(t *testing.T) {
+	put := func(key, val string, lease int64) mvccpb.Event {
+		return mvccpb.Event{Type: mvccpb.PUT, Kv: &mvccpb.KeyValue{Key: []byte(key), Value: []byte(val), Lease: lease}}
+	}
+	del := func(key string) mvccpb.Event {
+		return mvccpb.Event{Type: mvccpb.DELETE, Kv: &mvccpb.KeyValue{Key: []byte(key)}}
+	}
+
+	tests := []struct {
+		name string
+		creq *pb.WatchCreateRequest
+		ev   mvccpb.Event
+		want bool // true if the event is filtered out
+	}{
+		{"no filter", &pb.WatchCreateRequest{}, put("a", "v", 0), false},
+		{"noput", &pb.WatchCreateRequest{Filters: []pb.WatchCreateRequest_FilterType{pb.WatchCreateRequest_NOPUT}}, put("a", "v", 0), true},
+		{"nodelete", &pb.WatchCreateRequest{Filters: []pb.WatchCreateRequest_FilterType{pb.WatchCreateRequest_NODELETE}}, del("a"), true},
+		{"value prefix match", &pb.WatchCreateRequest{KvFilter: &pb.RangeFilter{ValuePrefix: []byte("ok")}}, put("a", "ok1", 0), false},
+		{"value prefix mismatch", &pb.WatchCreateRequest{KvFilter: &pb.RangeFilter{ValuePrefix: []byte("ok")}}, put("a", "ko", 0), true},
+		{"value filter ignores delete", &pb.WatchCreateRequest{KvFilter: &pb.RangeFilter{ValuePrefix: []byte("ok")}}, del("a"), false},
+		{"lease match", &pb.WatchCreateRequest{Lease: 7}, put("a", "v", 7), false},
+		{"lease mismatch", &pb.WatchCreateRequest{Lease: 7}, put("a", "v", 8), true},
+		{"lease ignores delete", &pb.WatchCreateRequest{Lease: 7}, del("a"), false},
+		{"key suffix match", &pb.WatchCreateRequest{KeySuffix: []byte("/status")}, del("/jobs/1/status"), false},
+		{"key suffix mismatch", &pb.WatchCreateRequest{KeySuffix: []byte("/status")}, put("/jobs/1/spec", "v", 0), true},
+		{"key pattern match", &pb.WatchCreateRequest{KeyPattern: "/jobs/*/status"}, put("/jobs/1/status", "v", 0), false},
+		{"key pattern mismatch", &pb.WatchCreateRequest{KeyPattern: "/jobs/*/status"}, put("/jobs/1/2/status", "v", 0), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered := false
+			for _, f := range FiltersFromRequest(tt.creq) {
+				if f(tt.ev) {
+					filtered = true
+					break
+				}
+			}
+			if filtered != tt.want {
+				t.Errorf("filtered = %v, want %v", filtered, tt.want)
+			}
+		})
+	}
+}
@@ -27,6 +27,8 @@ import (
 	"go.etcd.io/etcd/server/v3/etcdserver/api"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/membership"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/v3alarm"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3compactor"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3quota"
 	"go.etcd.io/etcd/server/v3/etcdserver/cindex"
 	"go.etcd.io/etcd/server/v3/etcdserver/errors"
 	mvcctxn "go.etcd.io/etcd/server/v3/etcdserver/txn"
@@ -35,6 +37,7 @@ import (
 	serverstorage "go.etcd.io/etcd/server/v3/storage"
 	"go.etcd.io/etcd/server/v3/storage/backend"
 	"go.etcd.io/etcd/server/v3/storage/mvcc"
+	"go.etcd.io/etcd/server/v3/storage/schema"
 
 	"github.com/coreos/go-semver/semver"
 	"github.com/gogo/protobuf/proto"
@@ -79,11 +82,24 @@ type applierV3 interface {
 
 	LeaseGrant(lc *pb.LeaseGrantRequest) (*pb.LeaseGrantResponse, error)
 	LeaseRevoke(lc *pb.LeaseRevokeRequest) (*pb.LeaseRevokeResponse, error)
+	// LeaseExpire revokes an expired lease.
+	LeaseExpire(lc *pb.LeaseRevokeRequest) (*pb.LeaseRevokeResponse, error)
 
 	LeaseCheckpoint(lc *pb.LeaseCheckpointRequest) (*pb.LeaseCheckpointResponse, error)
+	LeaseAttach(lc *pb.LeaseAttachRequest) (*pb.LeaseAttachResponse, error)
+	LeaseDetach(lc *pb.LeaseDetachRequest) (*pb.LeaseDetachResponse, error)
 
 	Alarm(*pb.AlarmRequest) (*pb.AlarmResponse, error)
 
+	QuotaSet(r *pb.QuotaSetRequest) (*pb.QuotaSetResponse, error)
+	QuotaDelete(r *pb.QuotaDeleteRequest) (*pb.QuotaDeleteResponse, error)
+
+	CompactionHold(r *pb.CompactionHoldRequest) (*pb.CompactionHoldResponse, error)
+	CompactionHoldRelease(r *pb.CompactionHoldReleaseRequest) (*pb.CompactionHoldReleaseResponse, error)
+
+	// CDCCursor records the cursor of a change data capture sink.
+	CDCCursor(r *pb.CDCCursorRequest)
+
 	Authenticate(r *pb.InternalAuthenticateRequest) (*pb.AuthenticateResponse, error)
 
 	AuthEnable() (*pb.AuthEnableResponse, error)
@@ -117,8 +133,11 @@ type SnapshotServer interface {
 
 type applierV3backend struct {
 	lg              *zap.Logger
+	be              backend.Backend
 	kv              mvcc.KV
 	alarmStore      *v3alarm.AlarmStore
+	quotaStore      *v3quota.QuotaStore
+	holdStore       *v3compactor.HoldStore
 	authStore       auth.AuthStore
 	lessor          lease.Lessor
 	cluster         *membership.RaftCluster
@@ -131,8 +150,11 @@ type applierV3backend struct {
 
 func newApplierV3Backend(
 	lg *zap.Logger,
+	be backend.Backend,
 	kv mvcc.KV,
 	alarmStore *v3alarm.AlarmStore,
+	quotaStore *v3quota.QuotaStore,
+	holdStore *v3compactor.HoldStore,
 	authStore auth.AuthStore,
 	lessor lease.Lessor,
 	cluster *membership.RaftCluster,
@@ -142,8 +164,11 @@ func newApplierV3Backend(
 	txnModeWriteWithSharedBuffer bool) applierV3 {
 	return &applierV3backend{
 		lg:                           lg,
+		be:                           be,
 		kv:                           kv,
 		alarmStore:                   alarmStore,
+		quotaStore:                   quotaStore,
+		holdStore:                    holdStore,
 		authStore:                    authStore,
 		lessor:                       lessor,
 		cluster:                      cluster,
@@ -181,18 +206,46 @@ func (a *applierV3backend) Compaction(compaction *pb.CompactionRequest) (*pb.Com
 		traceutil.Field{Key: "revision", Value: compaction.Revision},
 	)
 
-	ch, err := a.kv.Compact(trace, compaction.Revision)
+	// the retention rules are resolved by the member proposing the compaction,
+	// so that every member keeps the same revisions
+	rs := make([]mvcc.Retention, 0, len(compaction.Retention))
+	for _, r := range compaction.Retention {
+		rt := mvcc.Retention{Prefix: r.Prefix, Versions: r.Versions, MinRevision: r.MinRevision}
+		if r.DurationSeconds > 0 && rt.MinRevision <= 0 {
+			// the revisions written within the duration are not known yet
+			rt.MinRevision = 1
+		}
+		rs = append(rs, rt)
+	}
+	// never compact past the lowest revision kept by a compaction hold
+	rev := compaction.Revision
+	held := false
+	if holdRev := a.holdStore.MinRevision(); holdRev > 0 && holdRev < rev {
+		a.lg.Info(
+			"compaction held",
+			zap.Int64("requested-revision", rev),
+			zap.Int64("held-revision", holdRev),
+		)
+		rev, held = holdRev, true
+		trace.AddField(traceutil.Field{Key: "held-revision", Value: rev})
+	}
+	ch, err := a.kv.CompactRetaining(trace, rev, rs)
+	if err == mvcc.ErrCompacted && held {
+		// the store is already compacted up to the hold
+		err = nil
+	}
 	if err != nil {
 		return nil, ch, nil, err
 	}
 	// get the current revision. which key to get is not important.
 	rr, _ := a.kv.Range(context.TODO(), []byte("compaction"), nil, mvcc.RangeOptions{})
 	resp.Header.Revision = rr.Rev
+	resp.CompactRevision = rev
 	return resp, ch, trace, err
 }
 
 func (a *applierV3backend) LeaseGrant(lc *pb.LeaseGrantRequest) (*pb.LeaseGrantResponse, error) {
-	l, err := a.lessor.Grant(lease.LeaseID(lc.ID), lc.TTL)
+	l, err := a.lessor.GrantWithOptions(lease.LeaseID(lc.ID), lc.TTL, lease.GrantOptions{Parent: lease.LeaseID(lc.Parent), Labels: lc.Labels})
 	resp := &pb.LeaseGrantResponse{}
 	if err == nil {
 		resp.ID = int64(l.ID)
@@ -207,6 +260,43 @@ func (a *applierV3backend) LeaseRevoke(lc *pb.LeaseRevokeRequest) (*pb.LeaseRevo
 	return &pb.LeaseRevokeResponse{Header: a.newHeader()}, err
 }
 
+func (a *applierV3backend) LeaseExpire(lc *pb.LeaseRevokeRequest) (*pb.LeaseRevokeResponse, error) {
+	err := a.lessor.RevokeExpired(lease.LeaseID(lc.ID))
+	return &pb.LeaseRevokeResponse{Header: a.newHeader()}, err
+}
+
+func (a *applierV3backend) LeaseAttach(lc *pb.LeaseAttachRequest) (*pb.LeaseAttachResponse, error) {
+	if a.lessor.Lookup(lease.LeaseID(lc.ID)) == nil {
+		return nil, lease.ErrLeaseNotFound
+	}
+	n := a.attachLease(lc.Keys, lc.Prefix, lease.LeaseID(lc.ID))
+	return &pb.LeaseAttachResponse{Header: a.newHeader(), Count: n}, nil
+}
+
+func (a *applierV3backend) LeaseDetach(lc *pb.LeaseDetachRequest) (*pb.LeaseDetachResponse, error) {
+	n := a.attachLease(lc.Keys, lc.Prefix, lease.NoLease)
+	return &pb.LeaseDetachResponse{Header: a.newHeader(), Count: n}, nil
+}
+
+// attachLease attaches the keys, or the keys prefixed by them, to the lease
+// in a single txn.
+func (a *applierV3backend) attachLease(keys [][]byte, prefix bool, id lease.LeaseID) int64 {
+	txn := a.kv.Write(traceutil.TODO())
+	defer txn.End()
+	var n int64
+	for _, key := range keys {
+		var end []byte
+		if prefix {
+			// mvcc takes an empty range end for the end of the keyspace
+			if end = prefixEnd(key); len(end) == 1 && end[0] == 0 {
+				end = []byte{}
+			}
+		}
+		n += txn.AttachLease(key, end, id)
+	}
+	return n
+}
+
 func (a *applierV3backend) LeaseCheckpoint(lc *pb.LeaseCheckpointRequest) (*pb.LeaseCheckpointResponse, error) {
 	for _, c := range lc.Checkpoints {
 		err := a.lessor.Checkpoint(lease.LeaseID(c.ID), c.Remaining_TTL)
@@ -246,6 +336,53 @@ func (a *applierV3backend) Alarm(ar *pb.AlarmRequest) (*pb.AlarmResponse, error)
 	return resp, nil
 }
 
+func (a *applierV3backend) QuotaSet(r *pb.QuotaSetRequest) (*pb.QuotaSetResponse, error) {
+	if r.Quota == nil {
+		return nil, v3quota.ErrInvalidQuota
+	}
+	if err := a.quotaStore.Set(r.Quota); err != nil {
+		return nil, err
+	}
+	return &pb.QuotaSetResponse{Header: a.newHeader()}, nil
+}
+
+func (a *applierV3backend) QuotaDelete(r *pb.QuotaDeleteRequest) (*pb.QuotaDeleteResponse, error) {
+	if err := a.quotaStore.Delete(r.Type, r.Prefix, r.Name); err != nil {
+		return nil, err
+	}
+	return &pb.QuotaDeleteResponse{Header: a.newHeader()}, nil
+}
+
+func (a *applierV3backend) CompactionHold(r *pb.CompactionHoldRequest) (*pb.CompactionHoldResponse, error) {
+	if a.lessor.Lookup(lease.LeaseID(r.Lease)) == nil {
+		return nil, lease.ErrLeaseNotFound
+	}
+	// the range fails if the revision is compacted or in the future
+	rr, err := a.kv.Range(context.TODO(), []byte("compaction"), nil, mvcc.RangeOptions{Rev: r.Revision, Count: true})
+	if err != nil {
+		return nil, err
+	}
+	rev := r.Revision
+	if rev == 0 {
+		rev = rr.Rev
+	}
+	if err := a.holdStore.Acquire(&pb.CompactionHold{ID: r.ID, Revision: rev, Lease: r.Lease}); err != nil {
+		return nil, err
+	}
+	return &pb.CompactionHoldResponse{Header: a.newHeader(), ID: r.ID, Revision: rev}, nil
+}
+
+func (a *applierV3backend) CompactionHoldRelease(r *pb.CompactionHoldReleaseRequest) (*pb.CompactionHoldReleaseResponse, error) {
+	if err := a.holdStore.Release(r.ID); err != nil {
+		return nil, err
+	}
+	return &pb.CompactionHoldReleaseResponse{Header: a.newHeader()}, nil
+}
+
+func (a *applierV3backend) CDCCursor(r *pb.CDCCursorRequest) {
+	schema.NewCDCCursorBackend(a.lg, a.be).MustPutCDCCursor(r.Sink, r.Revision)
+}
+
 type applierV3Capped struct {
 	applierV3
 	q serverstorage.BackendQuota
@@ -291,6 +428,9 @@ func (a *applierV3backend) AuthStatus() (*pb.AuthStatusResponse, error) {
 
 func (a *applierV3backend) Authenticate(r *pb.InternalAuthenticateRequest) (*pb.AuthenticateResponse, error) {
 	ctx := context.WithValue(context.WithValue(context.Background(), auth.AuthenticateParamIndex{}, a.consistentIndex.ConsistentIndex()), auth.AuthenticateParamSimpleTokenPrefix{}, r.SimpleToken)
+	if r.External {
+		ctx = context.WithValue(ctx, auth.AuthenticateParamIdentity{}, &auth.Identity{Name: r.Name, Roles: r.Roles})
+	}
 	resp, err := a.authStore.Authenticate(ctx, r.Name, r.Password)
 	if resp != nil {
 		resp.Header = a.newHeader()
@@ -440,14 +580,34 @@ func (a *applierV3backend) DowngradeInfoSet(r *membershippb.DowngradeInfoSetRequ
 
 type quotaApplierV3 struct {
 	applierV3
-	q serverstorage.Quota
+	q  serverstorage.Quota
+	qs *v3quota.QuotaStore
+	kv mvcc.KV
+	as auth.AuthStore
+
+	maxRequestBytes uint
 }
 
-func newQuotaApplierV3(lg *zap.Logger, quotaBackendBytesCfg int64, be backend.Backend, app applierV3) applierV3 {
-	return &quotaApplierV3{app, serverstorage.NewBackendQuota(lg, quotaBackendBytesCfg, be, "v3-applier")}
+func newQuotaApplierV3(lg *zap.Logger, quotaBackendBytesCfg int64, maxRequestBytes uint, be backend.Backend, kv mvcc.KV, qs *v3quota.QuotaStore, as auth.AuthStore, app applierV3) applierV3 {
+	return &quotaApplierV3{app, serverstorage.NewBackendQuota(lg, quotaBackendBytesCfg, be, "v3-applier"), qs, kv, as, maxRequestBytes}
+}
+
+// Apply rejects the requests of users exceeding the rate limits of
+// themselves or of their roles. Rate limits are checked against the time the
+// request was proposed at, so that all members reach the same decision.
+func (a *quotaApplierV3) Apply(ctx context.Context, r *pb.InternalRaftRequest, shouldApplyV3 membership.ShouldApplyV3, applyFunc applyFunc) *Result {
+	if shouldApplyV3 == membership.ApplyBoth && isRateLimited(r) && r.Header != nil && r.Header.Username != "" && r.Header.Timestamp != 0 {
+		if err := a.qs.Allow(r.Header.Username, a.as.UserRoles(&auth.AuthInfo{Username: r.Header.Username, External: r.Header.External, Roles: r.Header.Roles}), r.Header.Timestamp); err != nil {
+			return &Result{Err: err}
+		}
+	}
+	return a.applierV3.Apply(ctx, r, shouldApplyV3, applyFunc)
 }
 
 func (a *quotaApplierV3) Put(ctx context.Context, txn mvcc.TxnWrite, p *pb.PutRequest) (*pb.PutResponse, *traceutil.Trace, error) {
+	if err := a.qs.CheckPuts([]*pb.PutRequest{p}); err != nil {
+		return nil, nil, err
+	}
 	ok := a.q.Available(p)
 	resp, trace, err := a.applierV3.Put(ctx, txn, p)
 	if err == nil && !ok {
@@ -457,7 +617,21 @@ func (a *quotaApplierV3) Put(ctx context.Context, txn mvcc.TxnWrite, p *pb.PutRe
 }
 
 func (a *quotaApplierV3) Txn(ctx context.Context, rt *pb.TxnRequest) (*pb.TxnResponse, *traceutil.Trace, error) {
-	ok := a.q.Available(rt)
+	puts := mvcctxn.TxnPuts(a.kv, rt)
+	if mvcctxn.HasTxnIncrOrAppend(rt) {
+		// the values written by the appends are checked again against the values
+		// they append to when applied, as the keys may have grown since proposed
+		for _, p := range puts {
+			if len(p.Key)+len(p.Value) > int(a.maxRequestBytes) {
+				return nil, nil, errors.ErrRequestTooLarge
+			}
+		}
+	}
+	if err := a.qs.CheckPuts(puts); err != nil {
+		return nil, nil, err
+	}
+	// charge the values the txn writes, as appends grow the current values
+	ok := a.q.Available(puts)
 	resp, trace, err := a.applierV3.Txn(ctx, rt)
 	if err == nil && !ok {
 		err = errors.ErrNoSpace
@@ -474,6 +648,28 @@ func (a *quotaApplierV3) LeaseGrant(lc *pb.LeaseGrantRequest) (*pb.LeaseGrantRes
 	return resp, err
 }
 
+// isRateLimited reports whether the request counts against the rate limits.
+func isRateLimited(r *pb.InternalRaftRequest) bool {
+	return r.Range != nil || r.Put != nil || r.DeleteRange != nil || r.Txn != nil ||
+		r.Compaction != nil || r.LeaseGrant != nil || r.LeaseRevoke != nil ||
+		r.LeaseAttach != nil || r.LeaseDetach != nil
+}
+
+// prefixEnd returns the end of the range of the keys prefixed by prefix, in
+// the form of the range end of the requests: {0} for the end of the keyspace.
+func prefixEnd(prefix []byte) []byte {
+	end := make([]byte, len(prefix))
+	copy(end, prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	// the prefix is empty or all 0xff, so the range reaches the end of the keyspace
+	return []byte{0}
+}
+
 func (a *applierV3backend) newHeader() *pb.ResponseHeader {
 	return &pb.ResponseHeader{
 		ClusterId: uint64(a.cluster.ID()),
@@ -16,6 +16,7 @@ package apply
 
 import (
 	"context"
+	"slices"
 	"sync"
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
@@ -51,17 +52,23 @@ func (aa *authApplierV3) Apply(ctx context.Context, r *pb.InternalRaftRequest, s
 		// does not have header field
 		aa.authInfo.Username = r.Header.Username
 		aa.authInfo.Revision = r.Header.AuthRevision
+		aa.authInfo.External = r.Header.External
+		aa.authInfo.Roles = r.Header.Roles
 	}
 	if needAdminPermission(r) {
 		if err := aa.as.IsAdminPermitted(&aa.authInfo); err != nil {
 			aa.authInfo.Username = ""
 			aa.authInfo.Revision = 0
+			aa.authInfo.External = false
+			aa.authInfo.Roles = nil
 			return &Result{Err: err}
 		}
 	}
 	ret := aa.applierV3.Apply(ctx, r, shouldApplyV3, applyFunc)
 	aa.authInfo.Username = ""
 	aa.authInfo.Revision = 0
+	aa.authInfo.External = false
+	aa.authInfo.Roles = nil
 	return ret
 }
 
@@ -115,13 +122,63 @@ func (aa *authApplierV3) Txn(ctx context.Context, rt *pb.TxnRequest) (*pb.TxnRes
 	return aa.applierV3.Txn(ctx, rt)
 }
 
+func (aa *authApplierV3) LeaseGrant(lc *pb.LeaseGrantRequest) (*pb.LeaseGrantResponse, error) {
+	// the keys of a child lease are deleted when its parent is revoked, so
+	// only the users that can revoke the parent may grant a child under it.
+	if lc.Parent != 0 {
+		if err := aa.checkLeaseTreePuts(lease.LeaseID(lc.Parent)); err != nil {
+			return nil, err
+		}
+	}
+	return aa.applierV3.LeaseGrant(lc)
+}
+
 func (aa *authApplierV3) LeaseRevoke(lc *pb.LeaseRevokeRequest) (*pb.LeaseRevokeResponse, error) {
-	if err := aa.checkLeasePuts(lease.LeaseID(lc.ID)); err != nil {
+	if err := aa.checkLeaseTreePuts(lease.LeaseID(lc.ID)); err != nil {
 		return nil, err
 	}
 	return aa.applierV3.LeaseRevoke(lc)
 }
 
+func (aa *authApplierV3) LeaseExpire(lc *pb.LeaseRevokeRequest) (*pb.LeaseRevokeResponse, error) {
+	if err := aa.checkLeaseTreePuts(lease.LeaseID(lc.ID)); err != nil {
+		return nil, err
+	}
+	return aa.applierV3.LeaseExpire(lc)
+}
+
+func (aa *authApplierV3) LeaseAttach(lc *pb.LeaseAttachRequest) (*pb.LeaseAttachResponse, error) {
+	if err := aa.checkLeasePuts(lease.LeaseID(lc.ID)); err != nil {
+		return nil, err
+	}
+	if err := aa.checkAttachKeys(lc.Keys, lc.Prefix); err != nil {
+		return nil, err
+	}
+	return aa.applierV3.LeaseAttach(lc)
+}
+
+func (aa *authApplierV3) LeaseDetach(lc *pb.LeaseDetachRequest) (*pb.LeaseDetachResponse, error) {
+	if err := aa.checkAttachKeys(lc.Keys, lc.Prefix); err != nil {
+		return nil, err
+	}
+	return aa.applierV3.LeaseDetach(lc)
+}
+
+// checkAttachKeys checks that the keys to attach or detach, or the keys
+// prefixed by them, can be written by the user.
+func (aa *authApplierV3) checkAttachKeys(keys [][]byte, prefix bool) error {
+	for _, key := range keys {
+		var end []byte
+		if prefix {
+			end = prefixEnd(key)
+		}
+		if err := aa.as.IsDeleteRangePermitted(&aa.authInfo, key, end); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (aa *authApplierV3) checkLeasePuts(leaseID lease.LeaseID) error {
 	l := aa.lessor.Lookup(leaseID)
 	if l != nil {
@@ -135,11 +192,29 @@ func (aa *authApplierV3) checkLeasePuts(leaseID lease.LeaseID) error {
 	return nil
 }
 
+// checkLeaseTreePuts checks the keys of the lease and of the child leases
+// revoked with it.
+func (aa *authApplierV3) checkLeaseTreePuts(leaseID lease.LeaseID) error {
+	if err := aa.checkLeasePuts(leaseID); err != nil {
+		return err
+	}
+	if l := aa.lessor.Lookup(leaseID); l != nil {
+		for _, id := range l.Children() {
+			if err := aa.checkLeaseTreePuts(id); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (aa *authApplierV3) UserGet(r *pb.AuthUserGetRequest) (*pb.AuthUserGetResponse, error) {
 	err := aa.as.IsAdminPermitted(&aa.authInfo)
 	if err != nil && r.Name != aa.authInfo.Username {
 		aa.authInfo.Username = ""
 		aa.authInfo.Revision = 0
+		aa.authInfo.External = false
+		aa.authInfo.Roles = nil
 		return &pb.AuthUserGetResponse{}, err
 	}
 
@@ -148,9 +223,11 @@ func (aa *authApplierV3) UserGet(r *pb.AuthUserGetRequest) (*pb.AuthUserGetRespo
 
 func (aa *authApplierV3) RoleGet(r *pb.AuthRoleGetRequest) (*pb.AuthRoleGetResponse, error) {
 	err := aa.as.IsAdminPermitted(&aa.authInfo)
-	if err != nil && !aa.as.HasRole(aa.authInfo.Username, r.Role) {
+	if err != nil && !slices.Contains(aa.as.UserRoles(&aa.authInfo), r.Role) {
 		aa.authInfo.Username = ""
 		aa.authInfo.Revision = 0
+		aa.authInfo.External = false
+		aa.authInfo.Roles = nil
 		return &pb.AuthRoleGetResponse{}, err
 	}
 
@@ -187,6 +264,14 @@ func needAdminPermission(r *pb.InternalRaftRequest) bool {
 		return true
 	case r.AuthRoleList != nil:
 		return true
+	case r.QuotaSet != nil:
+		return true
+	case r.QuotaDelete != nil:
+		return true
+	case r.CompactionHold != nil:
+		return true
+	case r.CompactionHoldRelease != nil:
+		return true
 	default:
 		return false
 	}
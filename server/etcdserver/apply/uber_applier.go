@@ -24,6 +24,8 @@ import (
 	"go.etcd.io/etcd/server/v3/auth"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/membership"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/v3alarm"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3compactor"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3quota"
 	"go.etcd.io/etcd/server/v3/etcdserver/cindex"
 	"go.etcd.io/etcd/server/v3/etcdserver/txn"
 	"go.etcd.io/etcd/server/v3/lease"
@@ -53,6 +55,8 @@ func NewUberApplier(
 	be backend.Backend,
 	kv mvcc.KV,
 	alarmStore *v3alarm.AlarmStore,
+	quotaStore *v3quota.QuotaStore,
+	holdStore *v3compactor.HoldStore,
 	authStore auth.AuthStore,
 	lessor lease.Lessor,
 	cluster *membership.RaftCluster,
@@ -61,8 +65,9 @@ func NewUberApplier(
 	consistentIndex cindex.ConsistentIndexer,
 	warningApplyDuration time.Duration,
 	txnModeWriteWithSharedBuffer bool,
-	quotaBackendBytesCfg int64) UberApplier {
-	applyV3base_ := newApplierV3(lg, be, kv, alarmStore, authStore, lessor, cluster, raftStatus, snapshotServer, consistentIndex, txnModeWriteWithSharedBuffer, quotaBackendBytesCfg)
+	quotaBackendBytesCfg int64,
+	maxRequestBytes uint) UberApplier {
+	applyV3base_ := newApplierV3(lg, be, kv, alarmStore, quotaStore, holdStore, authStore, lessor, cluster, raftStatus, snapshotServer, consistentIndex, txnModeWriteWithSharedBuffer, quotaBackendBytesCfg, maxRequestBytes)
 
 	ua := &uberApplier{
 		lg:                   lg,
@@ -80,6 +85,8 @@ func newApplierV3(
 	be backend.Backend,
 	kv mvcc.KV,
 	alarmStore *v3alarm.AlarmStore,
+	quotaStore *v3quota.QuotaStore,
+	holdStore *v3compactor.HoldStore,
 	authStore auth.AuthStore,
 	lessor lease.Lessor,
 	cluster *membership.RaftCluster,
@@ -87,11 +94,12 @@ func newApplierV3(
 	snapshotServer SnapshotServer,
 	consistentIndex cindex.ConsistentIndexer,
 	txnModeWriteWithSharedBuffer bool,
-	quotaBackendBytesCfg int64) applierV3 {
-	applierBackend := newApplierV3Backend(lg, kv, alarmStore, authStore, lessor, cluster, raftStatus, snapshotServer, consistentIndex, txnModeWriteWithSharedBuffer)
+	quotaBackendBytesCfg int64,
+	maxRequestBytes uint) applierV3 {
+	applierBackend := newApplierV3Backend(lg, be, kv, alarmStore, quotaStore, holdStore, authStore, lessor, cluster, raftStatus, snapshotServer, consistentIndex, txnModeWriteWithSharedBuffer)
 	return newAuthApplierV3(
 		authStore,
-		newQuotaApplierV3(lg, quotaBackendBytesCfg, be, applierBackend),
+		newQuotaApplierV3(lg, quotaBackendBytesCfg, maxRequestBytes, be, kv, quotaStore, authStore, applierBackend),
 		lessor,
 	)
 }
@@ -172,6 +180,15 @@ func (a *uberApplier) dispatch(ctx context.Context, r *pb.InternalRaftRequest, s
 	case r.LeaseRevoke != nil:
 		op = "LeaseRevoke"
 		ar.Resp, ar.Err = a.applyV3.LeaseRevoke(r.LeaseRevoke)
+	case r.LeaseExpire != nil:
+		op = "LeaseExpire"
+		ar.Resp, ar.Err = a.applyV3.LeaseExpire(r.LeaseExpire)
+	case r.LeaseAttach != nil:
+		op = "LeaseAttach"
+		ar.Resp, ar.Err = a.applyV3.LeaseAttach(r.LeaseAttach)
+	case r.LeaseDetach != nil:
+		op = "LeaseDetach"
+		ar.Resp, ar.Err = a.applyV3.LeaseDetach(r.LeaseDetach)
 	case r.LeaseCheckpoint != nil:
 		op = "LeaseCheckpoint"
 		ar.Resp, ar.Err = a.applyV3.LeaseCheckpoint(r.LeaseCheckpoint)
@@ -228,6 +245,21 @@ func (a *uberApplier) dispatch(ctx context.Context, r *pb.InternalRaftRequest, s
 	case r.AuthRoleList != nil:
 		op = "AuthRoleList"
 		ar.Resp, ar.Err = a.applyV3.RoleList(r.AuthRoleList)
+	case r.QuotaSet != nil:
+		op = "QuotaSet"
+		ar.Resp, ar.Err = a.applyV3.QuotaSet(r.QuotaSet)
+	case r.QuotaDelete != nil:
+		op = "QuotaDelete"
+		ar.Resp, ar.Err = a.applyV3.QuotaDelete(r.QuotaDelete)
+	case r.CompactionHold != nil:
+		op = "CompactionHold"
+		ar.Resp, ar.Err = a.applyV3.CompactionHold(r.CompactionHold)
+	case r.CompactionHoldRelease != nil:
+		op = "CompactionHoldRelease"
+		ar.Resp, ar.Err = a.applyV3.CompactionHoldRelease(r.CompactionHoldRelease)
+	case r.CdcCursor != nil:
+		op = "CDCCursor"
+		a.applyV3.CDCCursor(r.CdcCursor)
 	default:
 		a.lg.Panic("not implemented apply", zap.Stringer("raft-request", r))
 	}
@@ -44,6 +44,7 @@ import (
 	servererrors "go.etcd.io/etcd/server/v3/etcdserver/errors"
 	serverstorage "go.etcd.io/etcd/server/v3/storage"
 	"go.etcd.io/etcd/server/v3/storage/backend"
+	"go.etcd.io/etcd/server/v3/storage/backend/engine"
 	"go.etcd.io/etcd/server/v3/storage/schema"
 	"go.etcd.io/etcd/server/v3/storage/wal"
 	"go.etcd.io/etcd/server/v3/storage/wal/walpb"
@@ -76,6 +77,11 @@ func bootstrap(cfg config.ServerConfig) (b *bootstrappedServer, err error) {
 	}
 
 	haveWAL := wal.Exist(cfg.WALDir())
+	if haveWAL && cfg.BackendEngine == engine.TypeMemory && !fileutil.Exist(cfg.BackendPath()) {
+		// the backend of the WAL entries and snapshots was lost when the member stopped,
+		// the member has to be added back to the cluster to receive it from the leader.
+		return nil, fmt.Errorf("cannot restart a member storing the backend in memory, remove the data directory %q and add the member back to the cluster", cfg.DataDir)
+	}
 	st := v2store.New(StoreClusterPrefix, StoreKeysPrefix)
 	backend, err := bootstrapBackend(cfg, haveWAL, st, ss)
 	if err != nil {
@@ -195,7 +201,11 @@ func bootstrapSnapshot(cfg config.ServerConfig) *snap.Snapshotter {
 			zap.Error(err),
 		)
 	}
-	return snap.New(cfg.Logger, cfg.SnapDir())
+	ss := snap.New(cfg.Logger, cfg.SnapDir())
+	if cfg.EncryptionCipher != nil {
+		ss.SetCipher(cfg.EncryptionCipher)
+	}
+	return ss
 }
 
 func bootstrapBackend(cfg config.ServerConfig, haveWAL bool, st v2store.Store, ss *snap.Snapshotter) (backend *bootstrappedBackend, err error) {
@@ -603,6 +613,9 @@ func openWALFromSnapshot(cfg config.ServerConfig, snapshot *raftpb.Snapshot) (*w
 		if cfg.UnsafeNoFsync {
 			w.SetUnsafeNoFsync()
 		}
+		if cfg.EncryptionCipher != nil {
+			w.SetCipher(cfg.EncryptionCipher)
+		}
 		wmetadata, st, ents, err := w.ReadAll()
 		if err != nil {
 			w.Close()
@@ -645,6 +658,9 @@ func bootstrapNewWAL(cfg config.ServerConfig, cl *bootstrapedCluster) *bootstrap
 	if cfg.UnsafeNoFsync {
 		w.SetUnsafeNoFsync()
 	}
+	if cfg.EncryptionCipher != nil {
+		w.SetCipher(cfg.EncryptionCipher)
+	}
 	return &bootstrappedWAL{
 		lg: cfg.Logger,
 		w:  w,
@@ -42,6 +42,11 @@ var (
 	ErrClusterVersionUnavailable   = errors.New("etcdserver: cluster version not found during downgrade")
 	ErrWrongDowngradeVersionFormat = errors.New("etcdserver: wrong downgrade target version format")
 	ErrKeyNotFound                 = errors.New("etcdserver: key not found")
+	ErrInvalidContinueToken        = errors.New("etcdserver: invalid continue token")
+	ErrInvalidStreamSort           = errors.New("etcdserver: range stream only supports ascending key order")
+	ErrValueNotInteger             = errors.New("etcdserver: value is not an integer")
+	ErrValueOutOfRange             = errors.New("etcdserver: value out of range")
+	ErrClusterVersionTooLow        = errors.New("etcdserver: request not supported by the cluster version")
 )
 
 type DiscoveryError struct {
@@ -0,0 +1,144 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+)
+
+const (
+	// retentionSamples is the number of revisions sampled over the duration
+	// of a retention rule.
+	retentionSamples = 100
+	// minRetentionSampleInterval is the minimum interval between two samples.
+	minRetentionSampleInterval = 100 * time.Millisecond
+)
+
+type revisionSample struct {
+	t   time.Time
+	rev int64
+}
+
+// revisionSampler samples the revision of the store over the duration of a
+// retention rule, to find the oldest revision written within the duration.
+type revisionSampler struct {
+	d        time.Duration
+	interval time.Duration
+	// samples are ordered by time, only the latest one older than the
+	// duration is kept.
+	samples []revisionSample
+}
+
+func (rs *revisionSampler) sample(now time.Time, rev int64) {
+	if n := len(rs.samples); n > 0 && now.Sub(rs.samples[n-1].t) < rs.interval {
+		return
+	}
+	rs.samples = append(rs.samples, revisionSample{t: now, rev: rev})
+	start := now.Add(-rs.d)
+	i := 0
+	for i+1 < len(rs.samples) && !rs.samples[i+1].t.After(start) {
+		i++
+	}
+	rs.samples = rs.samples[i:]
+}
+
+// minRev returns the revision of the store at the start of the duration, or
+// 0 if it is not known yet.
+func (rs *revisionSampler) minRev(now time.Time) int64 {
+	if len(rs.samples) == 0 || rs.samples[0].t.After(now.Add(-rs.d)) {
+		return 0
+	}
+	return rs.samples[0].rev
+}
+
+// compactionRetention resolves the retention rules of the member for the
+// compactions it proposes.
+type compactionRetention struct {
+	mu       sync.Mutex
+	rules    []mvcc.RetentionRule
+	samplers map[string]*revisionSampler
+	// interval is the interval between two samples of the revision.
+	interval time.Duration
+}
+
+func newCompactionRetention(rules []mvcc.RetentionRule) *compactionRetention {
+	cr := &compactionRetention{rules: rules, samplers: make(map[string]*revisionSampler)}
+	for _, r := range rules {
+		if r.Duration <= 0 {
+			continue
+		}
+		interval := r.Duration / retentionSamples
+		if interval < minRetentionSampleInterval {
+			interval = minRetentionSampleInterval
+		}
+		cr.samplers[r.Prefix] = &revisionSampler{d: r.Duration, interval: interval}
+		if cr.interval == 0 || interval < cr.interval {
+			cr.interval = interval
+		}
+	}
+	return cr
+}
+
+func (cr *compactionRetention) sample(now time.Time, rev int64) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	for _, s := range cr.samplers {
+		s.sample(now, rev)
+	}
+}
+
+// resolve returns the retention rules with the oldest revisions written
+// within their durations.
+func (cr *compactionRetention) resolve(now time.Time) []*pb.CompactionRetentionRule {
+	if cr == nil {
+		return nil
+	}
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	rs := make([]*pb.CompactionRetentionRule, 0, len(cr.rules))
+	for _, r := range cr.rules {
+		rule := &pb.CompactionRetentionRule{
+			Prefix:          []byte(r.Prefix),
+			Versions:        r.Versions,
+			DurationSeconds: int64(math.Ceil(r.Duration.Seconds())),
+		}
+		if s, ok := cr.samplers[r.Prefix]; ok {
+			rule.MinRevision = s.minRev(now)
+		}
+		rs = append(rs, rule)
+	}
+	return rs
+}
+
+// sampleRetentionRevisions samples the revision of the store for the
+// retention rules with a duration.
+func (s *EtcdServer) sampleRetentionRevisions() {
+	if s.compactionRetention == nil || s.compactionRetention.interval == 0 {
+		return
+	}
+	for {
+		s.compactionRetention.sample(time.Now(), s.KV().Rev())
+		select {
+		case <-time.After(s.compactionRetention.interval):
+		case <-s.stopping:
+			return
+		}
+	}
+}
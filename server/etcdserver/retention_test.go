@@ -0,0 +1,53 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+)
+
+func TestCompactionRetentionResolve(t *testing.T) {
+	cr := newCompactionRetention([]mvcc.RetentionRule{
+		{Prefix: "/audit/", Duration: 100 * time.Second},
+		{Prefix: "/config/", Versions: 3},
+	})
+	assert.Equal(t, time.Second, cr.interval)
+
+	start := time.Unix(0, 0)
+	// sampled every 500ms, only once a second is kept over the last 100s
+	for i := 0; i <= 300; i++ {
+		cr.sample(start.Add(time.Duration(i)*500*time.Millisecond), int64(i+1))
+	}
+	assert.Len(t, cr.samplers["/audit/"].samples, 101)
+
+	wrules := []*pb.CompactionRetentionRule{
+		{Prefix: []byte("/audit/"), DurationSeconds: 100, MinRevision: 101},
+		{Prefix: []byte("/config/"), Versions: 3},
+	}
+	assert.Equal(t, wrules, cr.resolve(start.Add(150*time.Second)))
+	assert.Equal(t, wrules, cr.resolve(start.Add(150500*time.Millisecond)))
+
+	// the revision is unknown until sampled for the whole duration
+	fresh := newCompactionRetention([]mvcc.RetentionRule{{Prefix: "/audit/", Duration: 100 * time.Second}})
+	fresh.sample(start, 1)
+	assert.Equal(t, int64(0), fresh.resolve(start.Add(50 * time.Second))[0].MinRevision)
+	assert.Equal(t, int64(1), fresh.resolve(start.Add(100 * time.Second))[0].MinRevision)
+}
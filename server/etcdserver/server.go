@@ -51,7 +51,9 @@ import (
 	"go.etcd.io/etcd/pkg/v3/schedule"
 	"go.etcd.io/etcd/pkg/v3/traceutil"
 	"go.etcd.io/etcd/pkg/v3/wait"
+	"go.etcd.io/etcd/server/v3/audit"
 	"go.etcd.io/etcd/server/v3/auth"
+	"go.etcd.io/etcd/server/v3/cdc"
 	"go.etcd.io/etcd/server/v3/etcdserver/api"
 	httptypes "go.etcd.io/etcd/server/v3/etcdserver/api/etcdhttp/types"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/membership"
@@ -61,12 +63,16 @@ import (
 	"go.etcd.io/etcd/server/v3/etcdserver/api/v2store"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/v3alarm"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/v3compactor"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3defrag"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3quota"
 	"go.etcd.io/etcd/server/v3/etcdserver/cindex"
+	"go.etcd.io/etcd/server/v3/etcdserver/tindex"
 	serverversion "go.etcd.io/etcd/server/v3/etcdserver/version"
 	"go.etcd.io/etcd/server/v3/lease"
 	"go.etcd.io/etcd/server/v3/lease/leasehttp"
 	serverstorage "go.etcd.io/etcd/server/v3/storage"
 	"go.etcd.io/etcd/server/v3/storage/backend"
+	"go.etcd.io/etcd/server/v3/storage/datadir"
 	"go.etcd.io/etcd/server/v3/storage/mvcc"
 	"go.etcd.io/etcd/server/v3/storage/schema"
 	"go.etcd.io/raft/v3"
@@ -264,6 +270,17 @@ type EtcdServer struct {
 	beHooks    *serverstorage.BackendHooks
 	authStore  auth.AuthStore
 	alarmStore *v3alarm.AlarmStore
+	quotaStore *v3quota.QuotaStore
+	holdStore  *v3compactor.HoldStore
+	// timeIndex maps the revisions of the KV to the time they are written.
+	timeIndex *tindex.TimeIndex
+	// auditLogger records client operations, nil if the audit log is disabled.
+	auditLogger *audit.Logger
+	// capturer delivers the committed events to the change data capture
+	// sinks, nil if none is configured.
+	capturer *cdc.Capturer
+	// compactionRetention resolves the retention rules of the compactions.
+	compactionRetention *compactionRetention
 
 	stats  *stats.ServerStats
 	lstats *stats.LeaderStats
@@ -271,6 +288,8 @@ type EtcdServer struct {
 	SyncTicker *time.Ticker
 	// compactor is used to auto-compact the KV.
 	compactor v3compactor.Compactor
+	// defragger is used to auto-defragment the backend.
+	defragger *v3defrag.Defragger
 
 	// peerRt used to send requests (version, lease) to peers.
 	peerRt   http.RoundTripper
@@ -371,11 +390,37 @@ func NewServer(cfg config.ServerConfig) (srv *EtcdServer, err error) {
 	mvccStoreConfig := mvcc.StoreConfig{
 		CompactionBatchLimit:    cfg.CompactionBatchLimit,
 		CompactionSleepInterval: cfg.CompactionSleepInterval,
+		Compression:             cfg.BackendCompression,
+		CompressionMinSize:      cfg.BackendCompressionMinBytes,
+		Cipher:                  cfg.EncryptionCipher,
+		EventCacheRevisions:     cfg.WatchEventCacheRevisions,
+		EventCacheBytes:         cfg.WatchEventCacheBytes,
 	}
+	srv.compactionRetention = newCompactionRetention(cfg.CompactionRetentionRules)
 	srv.kv = mvcc.New(srv.Logger(), srv.be, srv.lessor, mvccStoreConfig)
 	srv.corruptionChecker = newCorruptionChecker(cfg.Logger, srv, srv.kv.HashStorage())
 
-	srv.authStore = auth.NewAuthStore(srv.Logger(), schema.NewAuthBackend(srv.Logger(), srv.be), tp, int(cfg.BcryptCost))
+	authenticator := cfg.Authenticator
+	if authenticator == nil {
+		authenticator, err = auth.NewAuthenticator(cfg.Logger, cfg.AuthAuthenticator)
+		if err != nil {
+			cfg.Logger.Warn("failed to create authenticator", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	as := auth.NewAuthStore(srv.Logger(), schema.NewAuthBackend(srv.Logger(), srv.be), tp, int(cfg.BcryptCost))
+	as.SetAuthenticator(authenticator)
+	srv.authStore = as
+
+	if srv.auditLogger, err = newAuditLogger(cfg); err != nil {
+		cfg.Logger.Warn("failed to create audit logger", zap.Error(err))
+		return nil, err
+	}
+	if srv.capturer, err = newCapturer(cfg, srv.kv, cdcReplicator{srv}); err != nil {
+		cfg.Logger.Warn("failed to create change data capturer", zap.Error(err))
+		return nil, err
+	}
 
 	newSrv := srv // since srv == nil in defer if srv is returned as nil
 	defer func() {
@@ -392,10 +437,32 @@ func NewServer(cfg config.ServerConfig) (srv *EtcdServer, err error) {
 		}
 		srv.compactor.Run()
 	}
+	if mb := cfg.ExperimentalAutoDefragThresholdMegabytes; mb != 0 {
+		srv.defragger, err = v3defrag.New(cfg.Logger, v3defrag.Config{
+			ThresholdBytes: int64(mb) * 1024 * 1024,
+			CheckInterval:  cfg.ExperimentalAutoDefragCheckInterval,
+			Online:         cfg.ExperimentalAutoDefragOnline,
+			LeaderPolicy:   cfg.ExperimentalAutoDefragLeaderPolicy,
+		}, srv, srv)
+		if err != nil {
+			return nil, err
+		}
+		srv.defragger.Run()
+	}
 
 	if err = srv.restoreAlarms(); err != nil {
 		return nil, err
 	}
+	if err = srv.restoreQuotas(); err != nil {
+		return nil, err
+	}
+	if err = srv.restoreCompactionHolds(); err != nil {
+		return nil, err
+	}
+	schema.NewCDCCursorBackend(srv.lg, srv.be).CreateCDCCursorBucket()
+	if srv.timeIndex, err = tindex.New(srv.be); err != nil {
+		return nil, err
+	}
 	srv.uberApply = srv.NewUberApplier()
 
 	if srv.Cfg.EnableLeaseCheckpoint {
@@ -525,6 +592,9 @@ func (s *EtcdServer) adjustTicks() {
 // should be implemented in goroutines.
 func (s *EtcdServer) Start() {
 	s.start()
+	if s.capturer != nil {
+		s.capturer.Run()
+	}
 	s.GoAttach(func() { s.adjustTicks() })
 	s.GoAttach(func() { s.publishV3(s.Cfg.ReqTimeout()) })
 	s.GoAttach(s.purgeFile)
@@ -535,6 +605,7 @@ func (s *EtcdServer) Start() {
 	s.GoAttach(s.monitorKVHash)
 	s.GoAttach(s.monitorCompactHash)
 	s.GoAttach(s.monitorDowngrade)
+	s.GoAttach(s.sampleRetentionRevisions)
 }
 
 // start prepares and starts server in a new goroutine. It is no longer safe to
@@ -776,6 +847,9 @@ func (s *EtcdServer) run() {
 				if s.compactor != nil {
 					s.compactor.Pause()
 				}
+				if s.capturer != nil {
+					s.capturer.Pause()
+				}
 				setSyncC(nil)
 			} else {
 				if newLeader {
@@ -788,6 +862,9 @@ func (s *EtcdServer) run() {
 				if s.compactor != nil {
 					s.compactor.Resume()
 				}
+				if s.capturer != nil {
+					s.capturer.Resume()
+				}
 			}
 			if newLeader {
 				s.leaderChanged.Notify()
@@ -876,7 +953,7 @@ func (s *EtcdServer) revokeExpiredLeases(leases []*lease.Lease) {
 			f := func(lid int64) {
 				s.GoAttach(func() {
 					ctx := s.authStore.WithRoot(s.ctx)
-					_, lerr := s.LeaseRevoke(ctx, &pb.LeaseRevokeRequest{ID: lid})
+					_, lerr := s.leaseExpire(ctx, lid)
 					if lerr == nil {
 						leaseExpired.Inc()
 					} else {
@@ -901,9 +978,16 @@ func (s *EtcdServer) revokeExpiredLeases(leases []*lease.Lease) {
 func (s *EtcdServer) Cleanup() {
 	// kv, lessor and backend can be nil if running without v3 enabled
 	// or running unit tests.
+	if s.defragger != nil {
+		// wait for the running defragmentation before closing the backend
+		s.defragger.Stop()
+	}
 	if s.lessor != nil {
 		s.lessor.Stop()
 	}
+	if s.capturer != nil {
+		s.capturer.Stop()
+	}
 	if s.kv != nil {
 		s.kv.Close()
 	}
@@ -916,6 +1000,9 @@ func (s *EtcdServer) Cleanup() {
 	if s.compactor != nil {
 		s.compactor.Stop()
 	}
+	if s.auditLogger != nil {
+		s.auditLogger.Close()
+	}
 }
 
 func (s *EtcdServer) applyAll(ep *etcdProgress, apply *toApply) {
@@ -1037,6 +1124,32 @@ func (s *EtcdServer) applySnapshot(ep *etcdProgress, toApply *toApply) {
 
 	lg.Info("restored alarm store")
 
+	lg.Info("restoring quota store")
+
+	if err := s.restoreQuotas(); err != nil {
+		lg.Panic("failed to restore quota store", zap.Error(err))
+	}
+
+	lg.Info("restored quota store")
+
+	lg.Info("restoring compaction hold store")
+
+	if err := s.restoreCompactionHolds(); err != nil {
+		lg.Panic("failed to restore compaction hold store", zap.Error(err))
+	}
+
+	lg.Info("restored compaction hold store")
+
+	schema.NewCDCCursorBackend(lg, newbe).CreateCDCCursorBucket()
+
+	lg.Info("restoring time index")
+
+	if err := s.timeIndex.Restore(newbe); err != nil {
+		lg.Panic("failed to restore time index", zap.Error(err))
+	}
+
+	lg.Info("restored time index")
+
 	if s.authStore != nil {
 		lg.Info("restoring auth store")
 
@@ -1091,8 +1204,8 @@ func (s *EtcdServer) applySnapshot(ep *etcdProgress, toApply *toApply) {
 }
 
 func (s *EtcdServer) NewUberApplier() apply.UberApplier {
-	return apply.NewUberApplier(s.lg, s.be, s.KV(), s.alarmStore, s.authStore, s.lessor, s.cluster, s, s, s.consistIndex,
-		s.Cfg.WarningApplyDuration, s.Cfg.ExperimentalTxnModeWriteWithSharedBuffer, s.Cfg.QuotaBackendBytes)
+	return apply.NewUberApplier(s.lg, s.be, s.KV(), s.alarmStore, s.quotaStore, s.holdStore, s.authStore, s.lessor, s.cluster, s, s, s.consistIndex,
+		s.Cfg.WarningApplyDuration, s.Cfg.ExperimentalTxnModeWriteWithSharedBuffer, s.Cfg.QuotaBackendBytes, s.Cfg.MaxRequestBytes)
 }
 
 func verifySnapshotIndex(snapshot raftpb.Snapshot, cindex uint64) {
@@ -1912,7 +2025,11 @@ func (s *EtcdServer) applyEntryNormal(e *raftpb.Entry) {
 		if !needResult && raftReq.Txn != nil {
 			removeNeedlessRangeReqs(raftReq.Txn)
 		}
+		rev := s.kv.Rev()
 		ar = s.uberApply.Apply(&raftReq, shouldApplyV3)
+		if shouldApplyV3 {
+			s.updateTimeIndex(&raftReq, rev)
+		}
 	}
 
 	// do not re-toApply applied entries.
@@ -2153,6 +2270,13 @@ func (s *EtcdServer) ClusterVersion() *semver.Version {
 	return s.cluster.Version()
 }
 
+// clusterVersionAtLeast reports whether all the members of the cluster run
+// at least the version v, so that they can apply the requests added in v.
+func (s *EtcdServer) clusterVersionAtLeast(v semver.Version) bool {
+	cv := s.ClusterVersion()
+	return cv != nil && !cv.LessThan(v)
+}
+
 func (s *EtcdServer) StorageVersion() *semver.Version {
 	// `applySnapshot` sets a new backend instance, so we need to acquire the bemu lock.
 	s.bemu.RLock()
@@ -2387,6 +2511,81 @@ func (s *EtcdServer) Backend() backend.Backend {
 
 func (s *EtcdServer) AuthStore() auth.AuthStore { return s.authStore }
 
+// AuditLogger returns the logger of client operations, or nil if the audit log is disabled.
+func (s *EtcdServer) AuditLogger() *audit.Logger { return s.auditLogger }
+
+func newAuditLogger(cfg config.ServerConfig) (*audit.Logger, error) {
+	sink := cfg.AuditSink
+	if sink == nil {
+		if cfg.AuditLogOutput == "" {
+			return nil, nil
+		}
+		sink = audit.NewFileSink(cfg.AuditLogOutput, cfg.AuditLogMaxSize, cfg.AuditLogMaxBackups)
+	}
+	level := audit.DefaultLevel
+	if cfg.AuditLogLevel != "" {
+		var err error
+		if level, err = audit.ParseLevel(cfg.AuditLogLevel); err != nil {
+			return nil, err
+		}
+	}
+	cfg.Logger.Info(
+		"enabled audit log",
+		zap.String("output", cfg.AuditLogOutput),
+		zap.String("level", string(level)),
+		zap.Strings("exclude-prefixes", cfg.AuditLogExcludePrefixes),
+	)
+	return audit.NewLogger(cfg.Logger, sink, level, cfg.AuditLogExcludePrefixes), nil
+}
+
+func newCapturer(cfg config.ServerConfig, kv mvcc.WatchableKV, r cdc.CursorReplicator) (*cdc.Capturer, error) {
+	sinks := append([]cdc.Sink{}, cfg.CDCSinks...)
+	if cfg.CDCFileDir != "" {
+		format := cfg.CDCFileFormat
+		if format == "" {
+			format = cdc.DefaultFormat
+		}
+		sinks = append(sinks, cdc.NewFileSink(cfg.CDCFileDir, format, int64(cfg.CDCFileMaxSize)*1024*1024))
+	}
+	if cfg.CDCWebhookURL != "" {
+		sinks = append(sinks, cdc.NewWebhookSink(cfg.CDCWebhookURL, cfg.ReqTimeout()))
+	}
+	if len(sinks) == 0 {
+		return nil, nil
+	}
+	cfg.Logger.Info(
+		"enabled change data capture",
+		zap.String("file-dir", cfg.CDCFileDir),
+		zap.String("file-format", string(cfg.CDCFileFormat)),
+		zap.String("webhook-url", cfg.CDCWebhookURL),
+		zap.Strings("prefixes", cfg.CDCPrefixes),
+	)
+	return cdc.New(cfg.Logger, kv, cdc.Config{
+		Prefixes:   cfg.CDCPrefixes,
+		CursorDir:  datadir.ToCDCDir(cfg.DataDir),
+		Replicator: r,
+	}, sinks...)
+}
+
+// cdcReplicator replicates the cursors of the change data capture sinks
+// through raft, and records them in the backend.
+type cdcReplicator struct {
+	s *EtcdServer
+}
+
+func (r cdcReplicator) Cursor(sink string) int64 {
+	return schema.NewCDCCursorBackend(r.s.lg, r.s.Backend()).GetCDCCursor(sink)
+}
+
+func (r cdcReplicator) Replicate(ctx context.Context, sink string, rev int64) error {
+	// the members running an older version cannot apply the cursors
+	if !r.s.clusterVersionAtLeast(version.V3_6) {
+		return nil
+	}
+	_, err := r.s.raftRequestOnce(ctx, pb.InternalRaftRequest{CdcCursor: &pb.CDCCursorRequest{Sink: sink, Revision: rev}})
+	return err
+}
+
 func (s *EtcdServer) restoreAlarms() error {
 	as, err := v3alarm.NewAlarmStore(s.lg, schema.NewAlarmBackend(s.lg, s.be))
 	if err != nil {
@@ -2396,6 +2595,42 @@ func (s *EtcdServer) restoreAlarms() error {
 	return nil
 }
 
+// restoreQuotas loads the quotas from the backend and accounts the changes
+// of the KV to the usage of the prefix quotas.
+func (s *EtcdServer) restoreQuotas() error {
+	qs, err := v3quota.NewQuotaStore(s.lg, schema.NewQuotaBackend(s.lg, s.be), s.kv)
+	if err != nil {
+		return err
+	}
+	s.quotaStore = qs
+	s.kv.SetChangeHook(qs.Update)
+	return nil
+}
+
+// updateTimeIndex records the time of the revisions written by the applied
+// request, and removes the compacted ones. rev is the revision of the KV
+// before the request is applied.
+func (s *EtcdServer) updateTimeIndex(r *pb.InternalRaftRequest, rev int64) {
+	if r.Header != nil && s.kv.Rev() > rev {
+		s.timeIndex.Record(rev+1, r.Header.Timestamp)
+	}
+	if r.Compaction != nil {
+		s.timeIndex.Compact(s.kv.FirstRev())
+	}
+}
+
+// restoreCompactionHolds loads the compaction holds from the backend.
+func (s *EtcdServer) restoreCompactionHolds() error {
+	hs, err := v3compactor.NewHoldStore(s.lg, schema.NewCompactionHoldBackend(s.lg, s.be), func(id int64) bool {
+		return s.lessor.Lookup(lease.LeaseID(id)) != nil
+	})
+	if err != nil {
+		return err
+	}
+	s.holdStore = hs
+	return nil
+}
+
 // GoAttach creates a goroutine on a given function and tracks it using
 // the etcdserver waitgroup.
 // The passed function should interrupt on s.StoppingNotify().
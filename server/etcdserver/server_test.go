@@ -50,6 +50,7 @@ import (
 	apply2 "go.etcd.io/etcd/server/v3/etcdserver/apply"
 	"go.etcd.io/etcd/server/v3/etcdserver/cindex"
 	"go.etcd.io/etcd/server/v3/etcdserver/errors"
+	"go.etcd.io/etcd/server/v3/etcdserver/tindex"
 	"go.etcd.io/etcd/server/v3/lease"
 	"go.etcd.io/etcd/server/v3/mock/mockstorage"
 	"go.etcd.io/etcd/server/v3/mock/mockstore"
@@ -1128,6 +1129,11 @@ func TestSnapshotOrdering(t *testing.T) {
 
 	s.kv = mvcc.New(lg, be, &lease.FakeLessor{}, mvcc.StoreConfig{})
 	s.be = be
+	var err error
+	s.timeIndex, err = tindex.New(be)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	s.start()
 	defer s.Stop()
@@ -1282,6 +1288,11 @@ func TestConcurrentApplyAndSnapshotV3(t *testing.T) {
 
 	s.kv = mvcc.New(lg, be, &lease.FakeLessor{}, mvcc.StoreConfig{})
 	s.be = be
+	var err error
+	s.timeIndex, err = tindex.New(be)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	s.start()
 	defer s.Stop()
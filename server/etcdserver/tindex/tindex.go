@@ -0,0 +1,148 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tindex
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"go.etcd.io/etcd/server/v3/storage/backend"
+	"go.etcd.io/etcd/server/v3/storage/schema"
+)
+
+// Resolution is the resolution of the time index.
+const Resolution = time.Second
+
+var ErrTimeNotIndexed = errors.New("etcdserver: no revision indexed at the required time")
+
+type Backend interface {
+	BatchTx() backend.BatchTx
+	ReadTx() backend.ReadTx
+}
+
+type entry struct {
+	rev int64
+	// ts is the time the revision is written, in nanoseconds since the Unix epoch.
+	ts int64
+}
+
+func (e entry) slot() int64 {
+	return e.ts / int64(Resolution)
+}
+
+// TimeIndex maps the revisions of the key-value store to the time they are
+// written. Only the first revision written in each second is recorded, which
+// is enough to find the latest revision written up to the end of any second.
+type TimeIndex struct {
+	mu sync.RWMutex
+	be Backend
+	// entries are ordered by both revision and time.
+	entries []entry
+}
+
+// New loads the time index from the backend.
+func New(be Backend) (*TimeIndex, error) {
+	ti := &TimeIndex{}
+	err := ti.Restore(be)
+	return ti, err
+}
+
+// Restore reloads the time index from the given backend, which replaces the
+// current one.
+func (ti *TimeIndex) Restore(be Backend) error {
+	schema.CreateTimeIndexBucket(be.BatchTx())
+	var entries []entry
+	err := schema.ReadRevisionTimes(be.ReadTx(), func(rev, ts int64) {
+		entries = append(entries, entry{rev: rev, ts: ts})
+	})
+	if err != nil {
+		return err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].rev < entries[j].rev })
+
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+	ti.be = be
+	ti.entries = entries
+	return nil
+}
+
+// Record records the time, in nanoseconds since the Unix epoch, of the first
+// revision written by an applied request. The time is the one the request is
+// proposed at, so that every member records the same index.
+func (ti *TimeIndex) Record(rev, ts int64) {
+	if ts <= 0 {
+		return
+	}
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+
+	e := entry{rev: rev, ts: ts}
+	if n := len(ti.entries); n > 0 {
+		last := ti.entries[n-1]
+		// the proposers' clocks may go backwards; their revisions are
+		// then indexed in the second of the last recorded revision.
+		if rev <= last.rev || e.slot() <= last.slot() {
+			return
+		}
+	}
+	ti.entries = append(ti.entries, e)
+
+	tx := ti.be.BatchTx()
+	tx.LockInsideApply()
+	defer tx.Unlock()
+	schema.UnsafePutRevisionTime(tx, e.rev, e.ts)
+}
+
+// Compact removes the revisions not needed anymore to find the revisions
+// from the compacted revision.
+func (ti *TimeIndex) Compact(compactRev int64) {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+
+	// keep the latest revision up to the compacted one
+	i := sort.Search(len(ti.entries), func(i int) bool { return ti.entries[i].rev > compactRev }) - 1
+	if i <= 0 {
+		return
+	}
+	tx := ti.be.BatchTx()
+	tx.LockInsideApply()
+	defer tx.Unlock()
+	for _, e := range ti.entries[:i] {
+		schema.UnsafeDeleteRevisionTime(tx, e.rev)
+	}
+	ti.entries = append([]entry(nil), ti.entries[i:]...)
+}
+
+// Revision returns the latest revision written up to the end of the second of
+// the given time, given the current revision of the key-value store. It
+// returns ErrTimeNotIndexed if the time is before the first revision indexed.
+func (ti *TimeIndex) Revision(t time.Time, currentRev int64) (int64, error) {
+	ti.mu.RLock()
+	defer ti.mu.RUnlock()
+
+	slot := entry{ts: t.UnixNano()}.slot()
+	i := sort.Search(len(ti.entries), func(i int) bool { return ti.entries[i].slot() > slot })
+	switch {
+	case i == 0:
+		return 0, ErrTimeNotIndexed
+	case i == len(ti.entries):
+		return currentRev, nil
+	default:
+		return ti.entries[i].rev - 1, nil
+	}
+}
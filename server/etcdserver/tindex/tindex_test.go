@@ -0,0 +1,90 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tindex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	betesting "go.etcd.io/etcd/server/v3/storage/backend/testing"
+)
+
+func TestTimeIndex(t *testing.T) {
+	be, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, be)
+
+	ti, err := New(be)
+	require.NoError(t, err)
+	_, err = ti.Revision(time.Unix(100, 0), 1)
+	assert.ErrorIs(t, err, ErrTimeNotIndexed)
+
+	at := func(sec, nsec int64) int64 { return time.Unix(sec, nsec).UnixNano() }
+	ti.Record(2, at(100, 0))
+	ti.Record(3, at(100, 500))  // same second
+	ti.Record(4, at(102, 0))    // revisions 4 to 6 are written in second 102
+	ti.Record(7, at(101, 0))    // the clock went backwards
+	ti.Record(8, at(105, 10))   // revisions 8 to 9 are written in second 105
+	ti.Record(9, 0)             // no time
+	ti.Record(5, at(106, 0))    // revision already indexed
+	ti.Record(10, at(105, 900)) // same second
+
+	tests := []struct {
+		t   time.Time
+		rev int64
+		err error
+	}{
+		{t: time.Unix(99, 0), err: ErrTimeNotIndexed},
+		{t: time.Unix(100, 0), rev: 3},
+		{t: time.Unix(101, 999), rev: 3},
+		{t: time.Unix(102, 0), rev: 7},
+		{t: time.Unix(104, 0), rev: 7},
+		{t: time.Unix(105, 0), rev: 10},
+		{t: time.Unix(200, 0), rev: 10},
+	}
+	check := func(ti *TimeIndex) {
+		for _, tt := range tests {
+			rev, err := ti.Revision(tt.t, 10)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err, "time %v", tt.t)
+				continue
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.rev, rev, "time %v", tt.t)
+		}
+	}
+	check(ti)
+
+	// the index is restored from the backend
+	be.ForceCommit()
+	ti, err = New(be)
+	require.NoError(t, err)
+	check(ti)
+
+	// the latest revision indexed up to the compacted one is kept
+	ti.Compact(6)
+	_, err = ti.Revision(time.Unix(101, 0), 10)
+	assert.ErrorIs(t, err, ErrTimeNotIndexed)
+	rev, err := ti.Revision(time.Unix(102, 0), 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), rev)
+
+	be.ForceCommit()
+	ti, err = New(be)
+	require.NoError(t, err)
+	assert.Equal(t, []entry{{rev: 4, ts: at(102, 0)}, {rev: 8, ts: at(105, 10)}}, ti.entries)
+}
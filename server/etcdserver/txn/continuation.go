@@ -0,0 +1,93 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txn
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/server/v3/etcdserver/errors"
+)
+
+// continueTokenVersion is the first byte of every continue token. It allows
+// changing the token layout without misinterpreting tokens issued by older members.
+const continueTokenVersion byte = 1
+
+// continueToken is the decoded form of RangeRequest.ContinueToken.
+// The token is opaque to clients; it encodes the read revision of the
+// first page and the last key returned so far.
+type continueToken struct {
+	rev     int64
+	lastKey []byte
+}
+
+func encodeContinueToken(rev int64, lastKey []byte) []byte {
+	buf := make([]byte, 1+binary.MaxVarintLen64+len(lastKey))
+	buf[0] = continueTokenVersion
+	n := binary.PutUvarint(buf[1:], uint64(rev))
+	copy(buf[1+n:], lastKey)
+	return buf[:1+n+len(lastKey)]
+}
+
+func decodeContinueToken(token []byte) (continueToken, error) {
+	if len(token) < 2 || token[0] != continueTokenVersion {
+		return continueToken{}, errors.ErrInvalidContinueToken
+	}
+	rev, n := binary.Uvarint(token[1:])
+	if n <= 0 || rev == 0 || len(token) == 1+n {
+		return continueToken{}, errors.ErrInvalidContinueToken
+	}
+	return continueToken{rev: int64(rev), lastKey: token[1+n:]}, nil
+}
+
+// isSortedByKey returns true if the range results are returned in ascending key order,
+// which is the only order a continue token can resume.
+func isSortedByKey(r *pb.RangeRequest) bool {
+	return r.SortTarget == pb.RangeRequest_KEY && r.SortOrder != pb.RangeRequest_DESCEND
+}
+
+// resolveContinueToken rewrites the range start key and revision of r according
+// to its continue token. The returned request is a shallow copy; r is not modified.
+func resolveContinueToken(r *pb.RangeRequest) (*pb.RangeRequest, error) {
+	if len(r.ContinueToken) == 0 {
+		return r, nil
+	}
+	if !isSortedByKey(r) {
+		return nil, errors.ErrInvalidContinueToken
+	}
+	ct, err := decodeContinueToken(r.ContinueToken)
+	if err != nil {
+		return nil, err
+	}
+	if r.Revision != 0 && r.Revision != ct.rev {
+		return nil, errors.ErrInvalidContinueToken
+	}
+	// the last key must belong to the requested range
+	end := mkGteRange(r.RangeEnd)
+	if bytes.Compare(ct.lastKey, r.Key) < 0 || (len(end) > 0 && bytes.Compare(ct.lastKey, end) >= 0) {
+		return nil, errors.ErrInvalidContinueToken
+	}
+	if end == nil {
+		// a single key cannot be paginated
+		return nil, errors.ErrInvalidContinueToken
+	}
+
+	resolved := *r
+	resolved.Key = append(append(make([]byte, 0, len(ct.lastKey)+1), ct.lastKey...), 0)
+	resolved.Revision = ct.rev
+	resolved.ContinueToken = nil
+	return &resolved, nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txn
+
+import (
+	"context"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+)
+
+// History reads every revision of the keys in the range of r, including the deletions.
+func History(ctx context.Context, kv mvcc.KV, r *pb.HistoryRequest) (*pb.HistoryResponse, error) {
+	ho := mvcc.HistoryOptions{
+		StartRev: r.StartRevision,
+		EndRev:   r.EndRevision,
+		Limit:    r.Limit,
+	}
+	hr, err := kv.History(ctx, r.Key, mkGteRange(r.RangeEnd), ho)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.HistoryResponse{
+		Header:          &pb.ResponseHeader{Revision: hr.Rev},
+		Kvs:             make([]*mvccpb.KeyValue, len(hr.KVs)),
+		More:            hr.More,
+		CompactRevision: hr.CompactRev,
+	}
+	for i := range hr.KVs {
+		resp.Kvs[i] = &hr.KVs[i]
+	}
+	return resp, nil
+}
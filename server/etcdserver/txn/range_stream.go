@@ -0,0 +1,145 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txn
+
+import (
+	"context"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/server/v3/etcdserver/errors"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+)
+
+var (
+	// rangeStreamChunkSize is the maximum number of key-value pairs in a range stream response.
+	rangeStreamChunkSize = 1000
+	// rangeStreamChunkBytes is the size after which a range stream response is sent.
+	rangeStreamChunkBytes = 1024 * 1024
+)
+
+// RangeStream reads the range of r at a single revision and passes it to send in
+// several responses. Only the key-value pairs of the response being sent are held
+// in memory. The last response has More set if the range was truncated by the limit.
+func RangeStream(ctx context.Context, kv mvcc.KV, r *pb.RangeRequest, send func(*pb.RangeResponse) error) error {
+	r, err := resolveContinueToken(r)
+	if err != nil {
+		return err
+	}
+	if !isSortedByKey(r) {
+		return errors.ErrInvalidStreamSort
+	}
+
+	ro := mvcc.RangeOptions{
+		Rev:    r.Revision,
+		Count:  r.CountOnly,
+		Filter: newRangeStreamFilter(r),
+	}
+	it, err := kv.Iterate(r.Key, mkGteRange(r.RangeEnd), ro)
+	if err != nil {
+		return err
+	}
+
+	newResponse := func(kvs []mvccpb.KeyValue, more bool) *pb.RangeResponse {
+		resp := &pb.RangeResponse{
+			Header: &pb.ResponseHeader{Revision: it.Rev()},
+			Count:  int64(it.Count()),
+			More:   more,
+			Kvs:    make([]*mvccpb.KeyValue, len(kvs)),
+		}
+		for i := range kvs {
+			if r.KeysOnly {
+				kvs[i].Value = nil
+			} else if len(r.Projection) > 0 {
+				projectKV(&kvs[i], r.Projection)
+			}
+			resp.Kvs[i] = &kvs[i]
+		}
+		return resp
+	}
+	if r.CountOnly {
+		return send(newResponse(nil, false))
+	}
+
+	var read, sent int64
+	next := func() ([]mvccpb.KeyValue, error) {
+		n := int64(rangeStreamChunkSize)
+		if r.Limit > 0 {
+			if left := r.Limit - read; left <= 0 {
+				// read ahead a single key to tell whether the range was truncated
+				n = 1
+			} else if left < n {
+				n = left
+			}
+		}
+		kvs, err := it.Next(ctx, int(n), rangeStreamChunkBytes)
+		read += int64(len(kvs))
+		return kvs, err
+	}
+
+	kvs, err := next()
+	if err != nil {
+		return err
+	}
+	for {
+		var upcoming []mvccpb.KeyValue
+		if len(kvs) > 0 {
+			if upcoming, err = next(); err != nil {
+				return err
+			}
+		}
+		more := len(upcoming) > 0
+		if !more || (r.Limit > 0 && sent+int64(len(kvs)) >= r.Limit) {
+			resp := newResponse(kvs, more)
+			if more {
+				// the range can be resumed with a paginated range at the same revision
+				readRev := r.Revision
+				if readRev <= 0 {
+					readRev = it.Rev()
+				}
+				resp.ContinueToken = encodeContinueToken(readRev, kvs[len(kvs)-1].Key)
+			}
+			return send(resp)
+		}
+		if err = send(newResponse(kvs, true)); err != nil {
+			return err
+		}
+		sent += int64(len(kvs))
+		kvs = upcoming
+	}
+}
+
+// newRangeStreamFilter merges the range filter and the revision bounds of r, so that
+// they are applied while iterating and the limit counts the accepted key-value pairs only.
+func newRangeStreamFilter(r *pb.RangeRequest) func(*mvccpb.KeyValue) bool {
+	f := NewRangeFilter(r.Filter)
+	if r.MinModRevision == 0 && r.MaxModRevision == 0 &&
+		r.MinCreateRevision == 0 && r.MaxCreateRevision == 0 {
+		return f
+	}
+	return func(kv *mvccpb.KeyValue) bool {
+		switch {
+		case r.MaxModRevision != 0 && kv.ModRevision > r.MaxModRevision:
+			return false
+		case r.MinModRevision != 0 && kv.ModRevision < r.MinModRevision:
+			return false
+		case r.MaxCreateRevision != 0 && kv.CreateRevision > r.MaxCreateRevision:
+			return false
+		case r.MinCreateRevision != 0 && kv.CreateRevision < r.MinCreateRevision:
+			return false
+		}
+		return f == nil || f(kv)
+	}
+}
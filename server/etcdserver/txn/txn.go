@@ -19,6 +19,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strconv"
 
 	"go.uber.org/zap"
 
@@ -120,6 +121,11 @@ func Range(ctx context.Context, lg *zap.Logger, kv mvcc.KV, txnRead mvcc.TxnRead
 	resp := &pb.RangeResponse{}
 	resp.Header = &pb.ResponseHeader{}
 
+	r, err := resolveContinueToken(r)
+	if err != nil {
+		return nil, err
+	}
+
 	if txnRead == nil {
 		txnRead = kv.Read(mvcc.ConcurrentReadTxMode, trace)
 		defer txnRead.End()
@@ -138,9 +144,10 @@ func Range(ctx context.Context, lg *zap.Logger, kv mvcc.KV, txnRead mvcc.TxnRead
 	}
 
 	ro := mvcc.RangeOptions{
-		Limit: limit,
-		Rev:   r.Revision,
-		Count: r.CountOnly,
+		Limit:  limit,
+		Rev:    r.Revision,
+		Count:  r.CountOnly,
+		Filter: NewRangeFilter(r.Filter),
 	}
 
 	rr, err := txnRead.Range(ctx, r.Key, mkGteRange(r.RangeEnd), ro)
@@ -204,6 +211,14 @@ func Range(ctx context.Context, lg *zap.Logger, kv mvcc.KV, txnRead mvcc.TxnRead
 	if r.Limit > 0 && len(rr.KVs) > int(r.Limit) {
 		rr.KVs = rr.KVs[:r.Limit]
 		resp.More = true
+		if isSortedByKey(r) {
+			// the next page is read at the same revision as this one
+			readRev := r.Revision
+			if readRev <= 0 {
+				readRev = rr.Rev
+			}
+			resp.ContinueToken = encodeContinueToken(readRev, rr.KVs[len(rr.KVs)-1].Key)
+		}
 	}
 	trace.Step("filter and sort the key-value pairs")
 	resp.Header.Revision = rr.Rev
@@ -212,6 +227,8 @@ func Range(ctx context.Context, lg *zap.Logger, kv mvcc.KV, txnRead mvcc.TxnRead
 	for i := range rr.KVs {
 		if r.KeysOnly {
 			rr.KVs[i].Value = nil
+		} else if len(r.Projection) > 0 {
+			projectKV(&rr.KVs[i], r.Projection)
 		}
 		resp.Kvs[i] = &rr.KVs[i]
 	}
@@ -247,7 +264,12 @@ func Txn(ctx context.Context, lg *zap.Logger, rt *pb.TxnRequest, txnModeWriteWit
 	if isWrite {
 		trace.AddField(traceutil.Field{Key: "read_only", Value: false})
 		if _, err := checkRequests(txnWrite, rt, txnPath,
-			func(rv mvcc.ReadView, ro *pb.RequestOp) error { return checkRequestPut(rv, lessor, ro) }); err != nil {
+			func(rv mvcc.ReadView, ro *pb.RequestOp) error {
+				if err := checkRequestPut(rv, lessor, ro); err != nil {
+					return err
+				}
+				return checkRequestIncr(rv, ro)
+			}); err != nil {
 			txnWrite.End()
 			return nil, nil, err
 		}
@@ -313,6 +335,10 @@ func newTxnResp(rt *pb.TxnRequest, txnPath []bool) (txnResp *pb.TxnResponse, txn
 			resps[i] = &pb.ResponseOp{Response: &pb.ResponseOp_ResponsePut{}}
 		case *pb.RequestOp_RequestDeleteRange:
 			resps[i] = &pb.ResponseOp{Response: &pb.ResponseOp_ResponseDeleteRange{}}
+		case *pb.RequestOp_RequestIncr:
+			resps[i] = &pb.ResponseOp{Response: &pb.ResponseOp_ResponseIncr{}}
+		case *pb.RequestOp_RequestAppend:
+			resps[i] = &pb.ResponseOp{Response: &pb.ResponseOp_ResponseAppend{}}
 		case *pb.RequestOp_RequestTxn:
 			resp, txns := newTxnResp(tv.RequestTxn, txnPath[1:])
 			resps[i] = &pb.ResponseOp{Response: &pb.ResponseOp_ResponseTxn{ResponseTxn: resp}}
@@ -362,6 +388,27 @@ func applyTxn(ctx context.Context, lg *zap.Logger, kv mvcc.KV, lessor lease.Less
 				return 0, fmt.Errorf("applyTxn: failed DeleteRange: %w", err)
 			}
 			respi.(*pb.ResponseOp_ResponseDeleteRange).ResponseDeleteRange = resp
+		case *pb.RequestOp_RequestIncr:
+			trace.StartSubTrace(
+				traceutil.Field{Key: "req_type", Value: "incr"},
+				traceutil.Field{Key: "key", Value: string(tv.RequestIncr.Key)})
+			resp, err := incr(txnWrite, tv.RequestIncr)
+			if err != nil {
+				return 0, fmt.Errorf("applyTxn: failed Incr: %w", err)
+			}
+			respi.(*pb.ResponseOp_ResponseIncr).ResponseIncr = resp
+			trace.StopSubTrace()
+		case *pb.RequestOp_RequestAppend:
+			trace.StartSubTrace(
+				traceutil.Field{Key: "req_type", Value: "append"},
+				traceutil.Field{Key: "key", Value: string(tv.RequestAppend.Key)},
+				traceutil.Field{Key: "req_size", Value: tv.RequestAppend.Size()})
+			resp, err := appendValue(txnWrite, tv.RequestAppend)
+			if err != nil {
+				return 0, fmt.Errorf("applyTxn: failed Append: %w", err)
+			}
+			respi.(*pb.ResponseOp_ResponseAppend).ResponseAppend = resp
+			trace.StopSubTrace()
 		case *pb.RequestOp_RequestTxn:
 			resp := respi.(*pb.ResponseOp_ResponseTxn).ResponseTxn
 			applyTxns, err := applyTxn(ctx, lg, kv, lessor, txnWrite, tv.RequestTxn, txnPath[1:], resp)
@@ -378,6 +425,91 @@ func applyTxn(ctx context.Context, lg *zap.Logger, kv mvcc.KV, lessor lease.Less
 	return txns, nil
 }
 
+// incrValue returns the current key-value pair of the key of the incr request,
+// if any, and the value of the key after the request.
+func incrValue(rv mvcc.ReadView, r *pb.IncrRequest) (prev *mvccpb.KeyValue, value int64, err error) {
+	rr, err := rv.Range(context.TODO(), r.Key, nil, mvcc.RangeOptions{})
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(rr.KVs) != 0 {
+		prev = &rr.KVs[0]
+		if value, err = strconv.ParseInt(string(prev.Value), 10, 64); err != nil {
+			return nil, 0, errors.ErrValueNotInteger
+		}
+	}
+	switch r.Op {
+	case pb.IncrRequest_ADD:
+		sum := value + r.Operand
+		if (r.Operand > 0 && sum < value) || (r.Operand < 0 && sum > value) {
+			return nil, 0, errors.ErrValueOutOfRange
+		}
+		value = sum
+	case pb.IncrRequest_MAX:
+		if r.Operand > value {
+			value = r.Operand
+		}
+	case pb.IncrRequest_MIN:
+		if r.Operand < value {
+			value = r.Operand
+		}
+	}
+	if b := r.Bounds; b != nil && (value < b.Min || value > b.Max) {
+		return nil, 0, errors.ErrValueOutOfRange
+	}
+	return prev, value, nil
+}
+
+func incr(txnWrite mvcc.TxnWrite, r *pb.IncrRequest) (*pb.IncrResponse, error) {
+	prev, value, err := incrValue(txnWrite, r)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.IncrResponse{Header: &pb.ResponseHeader{}, Value: value}
+	leaseID := lease.NoLease
+	if prev != nil {
+		leaseID = lease.LeaseID(prev.Lease)
+		if r.PrevKv {
+			resp.PrevKv = prev
+		}
+	}
+	resp.Header.Revision = txnWrite.Put(r.Key, []byte(strconv.FormatInt(value, 10)), leaseID)
+	return resp, nil
+}
+
+// appendedValue returns the current key-value pair of the key of the append
+// request, if any, and the value of the key after the request.
+func appendedValue(rv mvcc.ReadView, r *pb.AppendRequest) (prev *mvccpb.KeyValue, value []byte, err error) {
+	rr, err := rv.Range(context.TODO(), r.Key, nil, mvcc.RangeOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(rr.KVs) == 0 {
+		return nil, r.Value, nil
+	}
+	prev = &rr.KVs[0]
+	value = make([]byte, 0, len(prev.Value)+len(r.Value))
+	value = append(append(value, prev.Value...), r.Value...)
+	return prev, value, nil
+}
+
+func appendValue(txnWrite mvcc.TxnWrite, r *pb.AppendRequest) (*pb.AppendResponse, error) {
+	prev, value, err := appendedValue(txnWrite, r)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.AppendResponse{Header: &pb.ResponseHeader{}}
+	leaseID := lease.NoLease
+	if prev != nil {
+		leaseID = lease.LeaseID(prev.Lease)
+		if r.PrevKv {
+			resp.PrevKv = prev
+		}
+	}
+	resp.Header.Revision = txnWrite.Put(r.Key, value, leaseID)
+	return resp, nil
+}
+
 //---------------------------------------------------------
 
 type checkReqFunc func(mvcc.ReadView, *pb.RequestOp) error
@@ -406,12 +538,26 @@ func checkRequestPut(rv mvcc.ReadView, lessor lease.Lessor, reqOp *pb.RequestOp)
 	return nil
 }
 
+// checkRequestIncr fails the txn before any write if the incr request cannot
+// be applied to the current value.
+func checkRequestIncr(rv mvcc.ReadView, reqOp *pb.RequestOp) error {
+	tv, ok := reqOp.Request.(*pb.RequestOp_RequestIncr)
+	if !ok || tv.RequestIncr == nil {
+		return nil
+	}
+	_, _, err := incrValue(rv, tv.RequestIncr)
+	return err
+}
+
 func checkRequestRange(rv mvcc.ReadView, reqOp *pb.RequestOp) error {
 	tv, ok := reqOp.Request.(*pb.RequestOp_RequestRange)
 	if !ok || tv.RequestRange == nil {
 		return nil
 	}
-	req := tv.RequestRange
+	req, err := resolveContinueToken(tv.RequestRange)
+	if err != nil {
+		return err
+	}
 	switch {
 	case req.Revision == 0:
 		return nil
@@ -446,6 +592,38 @@ func checkRequests(rv mvcc.ReadView, rt *pb.TxnRequest, txnPath []bool, f checkR
 	return txnCount, nil
 }
 
+// TxnPuts returns the puts of the transaction branches that would be
+// executed against the current state of the read view. The incr and append
+// requests are returned as the puts of the values they would write.
+func TxnPuts(rv mvcc.ReadView, rt *pb.TxnRequest) []*pb.PutRequest {
+	var puts []*pb.PutRequest
+	checkRequests(rv, rt, compareToPath(rv, rt), func(_ mvcc.ReadView, req *pb.RequestOp) error {
+		switch tv := req.Request.(type) {
+		case *pb.RequestOp_RequestPut:
+			if tv.RequestPut != nil {
+				puts = append(puts, tv.RequestPut)
+			}
+		case *pb.RequestOp_RequestIncr:
+			if tv.RequestIncr == nil {
+				break
+			}
+			// the txn fails on the errors
+			if _, value, err := incrValue(rv, tv.RequestIncr); err == nil {
+				puts = append(puts, &pb.PutRequest{Key: tv.RequestIncr.Key, Value: []byte(strconv.FormatInt(value, 10))})
+			}
+		case *pb.RequestOp_RequestAppend:
+			if tv.RequestAppend == nil {
+				break
+			}
+			if _, value, err := appendedValue(rv, tv.RequestAppend); err == nil {
+				puts = append(puts, &pb.PutRequest{Key: tv.RequestAppend.Key, Value: value})
+			}
+		}
+		return nil
+	})
+	return puts
+}
+
 // mkGteRange determines if the range end is a >= range. This works around grpc
 // sending empty byte strings as nil; >= is encoded in the range end as '\0'.
 // If it is a GTE range, then []byte{} is returned to indicate the empty byte
@@ -468,6 +646,65 @@ func pruneKVs(rr *mvcc.RangeResult, isPrunable func(*mvccpb.KeyValue) bool) {
 	rr.KVs = rr.KVs[:j]
 }
 
+// NewRangeFilter converts the range filter into a key-value predicate. It returns nil
+// if the filter has no condition set, so that mvcc can still apply the limit on
+// the index.
+func NewRangeFilter(f *pb.RangeFilter) func(*mvccpb.KeyValue) bool {
+	if f == nil ||
+		(len(f.ValuePrefix) == 0 && len(f.ValueSuffix) == 0 && len(f.ValueContains) == 0 &&
+			f.MinValueSize == 0 && f.MaxValueSize == 0 && f.Lease == pb.RangeFilter_ANY &&
+			f.MinVersion == 0 && f.MaxVersion == 0) {
+		return nil
+	}
+	return func(kv *mvccpb.KeyValue) bool {
+		switch {
+		case len(f.ValuePrefix) > 0 && !bytes.HasPrefix(kv.Value, f.ValuePrefix):
+			return false
+		case len(f.ValueSuffix) > 0 && !bytes.HasSuffix(kv.Value, f.ValueSuffix):
+			return false
+		case len(f.ValueContains) > 0 && !bytes.Contains(kv.Value, f.ValueContains):
+			return false
+		case f.MinValueSize > 0 && int64(len(kv.Value)) < f.MinValueSize:
+			return false
+		case f.MaxValueSize > 0 && int64(len(kv.Value)) > f.MaxValueSize:
+			return false
+		case f.Lease == pb.RangeFilter_ATTACHED && kv.Lease == 0:
+			return false
+		case f.Lease == pb.RangeFilter_DETACHED && kv.Lease != 0:
+			return false
+		case f.MinVersion > 0 && kv.Version < f.MinVersion:
+			return false
+		case f.MaxVersion > 0 && kv.Version > f.MaxVersion:
+			return false
+		}
+		return true
+	}
+}
+
+// projectKV clears the fields of kv that are not listed in fields. The key is always kept.
+func projectKV(kv *mvccpb.KeyValue, fields []pb.KeyValueField) {
+	var selected uint32
+	for _, f := range fields {
+		selected |= 1 << uint32(f)
+	}
+	has := func(f pb.KeyValueField) bool { return selected&(1<<uint32(f)) != 0 }
+	if !has(pb.KeyValueField_CREATE_REVISION) {
+		kv.CreateRevision = 0
+	}
+	if !has(pb.KeyValueField_MOD_REVISION) {
+		kv.ModRevision = 0
+	}
+	if !has(pb.KeyValueField_VERSION) {
+		kv.Version = 0
+	}
+	if !has(pb.KeyValueField_VALUE) {
+		kv.Value = nil
+	}
+	if !has(pb.KeyValueField_LEASE) {
+		kv.Lease = 0
+	}
+}
+
 type kvSort struct{ kvs []mvccpb.KeyValue }
 
 func (s *kvSort) Swap(i, j int) {
@@ -643,6 +880,24 @@ func IsTxnReadonly(r *pb.TxnRequest) bool {
 	return true
 }
 
+// HasTxnIncrOrAppend reports whether the transaction, including its nested
+// transactions, has an incr or append request.
+func HasTxnIncrOrAppend(r *pb.TxnRequest) bool {
+	for _, reqs := range [][]*pb.RequestOp{r.Success, r.Failure} {
+		for _, u := range reqs {
+			switch {
+			case u.GetRequestIncr() != nil, u.GetRequestAppend() != nil:
+				return true
+			case u.GetRequestTxn() != nil:
+				if HasTxnIncrOrAppend(u.GetRequestTxn()) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
 func CheckTxnAuth(as auth.AuthStore, ai *auth.AuthInfo, rt *pb.TxnRequest) error {
 	for _, c := range rt.Compare {
 		if err := as.IsRangePermitted(ai, c.Key, c.RangeEnd); err != nil {
@@ -676,6 +931,33 @@ func checkTxnReqsPermission(as auth.AuthStore, ai *auth.AuthInfo, reqs []*pb.Req
 				return err
 			}
 
+		case *pb.RequestOp_RequestIncr:
+			if tv.RequestIncr == nil {
+				continue
+			}
+
+			// the response holds the new value
+			if err := as.IsRangePermitted(ai, tv.RequestIncr.Key, nil); err != nil {
+				return err
+			}
+			if err := as.IsPutPermitted(ai, tv.RequestIncr.Key); err != nil {
+				return err
+			}
+
+		case *pb.RequestOp_RequestAppend:
+			if tv.RequestAppend == nil {
+				continue
+			}
+
+			if err := as.IsPutPermitted(ai, tv.RequestAppend.Key); err != nil {
+				return err
+			}
+			if tv.RequestAppend.PrevKv {
+				if err := as.IsRangePermitted(ai, tv.RequestAppend.Key, nil); err != nil {
+					return err
+				}
+			}
+
 		case *pb.RequestOp_RequestDeleteRange:
 			if tv.RequestDeleteRange == nil {
 				continue
@@ -16,17 +16,22 @@ package txn
 
 import (
 	"context"
+	"math"
 	"strings"
 	"testing"
 
 	"go.uber.org/zap/zaptest"
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/pkg/v3/traceutil"
+	"go.etcd.io/etcd/server/v3/etcdserver/errors"
 	"go.etcd.io/etcd/server/v3/lease"
 	betesting "go.etcd.io/etcd/server/v3/storage/backend/testing"
 	"go.etcd.io/etcd/server/v3/storage/mvcc"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestReadonlyTxnError(t *testing.T) {
@@ -94,3 +99,357 @@ func TestWriteTxnPanic(t *testing.T) {
 
 	assert.Panics(t, func() { Txn(ctx, zaptest.NewLogger(t), txn, false, s, &lease.FakeLessor{}) }, "Expected panic in Txn with writes")
 }
+
+func TestTxnIncrAppend(t *testing.T) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, b)
+	s := mvcc.NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, mvcc.StoreConfig{})
+	defer s.Close()
+	s.Put([]byte("n"), []byte("5"), lease.LeaseID(7))
+	s.Put([]byte("s"), []byte("a"), lease.NoLease)
+
+	incr := func(key string, op pb.IncrRequest_Operation, operand int64, bounds *pb.IncrBounds) *pb.RequestOp {
+		return &pb.RequestOp{Request: &pb.RequestOp_RequestIncr{RequestIncr: &pb.IncrRequest{
+			Key: []byte(key), Op: op, Operand: operand, Bounds: bounds, PrevKv: true,
+		}}}
+	}
+	appendOp := func(key, val string) *pb.RequestOp {
+		return &pb.RequestOp{Request: &pb.RequestOp_RequestAppend{RequestAppend: &pb.AppendRequest{
+			Key: []byte(key), Value: []byte(val),
+		}}}
+	}
+	tests := []struct {
+		name  string
+		op    *pb.RequestOp
+		err   error
+		value int64
+		key   string
+		want  string
+	}{
+		{name: "add", op: incr("n", pb.IncrRequest_ADD, 3, nil), value: 8, key: "n", want: "8"},
+		{name: "subtract", op: incr("n", pb.IncrRequest_ADD, -10, nil), value: -5, key: "n", want: "-5"},
+		{name: "missing key", op: incr("m", pb.IncrRequest_ADD, 2, nil), value: 2, key: "m", want: "2"},
+		{name: "max", op: incr("n", pb.IncrRequest_MAX, 1, nil), value: 5, key: "n", want: "5"},
+		{name: "min", op: incr("n", pb.IncrRequest_MIN, 1, nil), value: 1, key: "n", want: "1"},
+		{name: "out of bounds", op: incr("n", pb.IncrRequest_ADD, -6, &pb.IncrBounds{Min: 0, Max: 10}), err: errors.ErrValueOutOfRange},
+		{name: "overflow", op: incr("n", pb.IncrRequest_ADD, math.MaxInt64, nil), err: errors.ErrValueOutOfRange},
+		{name: "not integer", op: incr("s", pb.IncrRequest_ADD, 1, nil), err: errors.ErrValueNotInteger},
+		{name: "append", op: appendOp("s", "bc"), key: "s", want: "abc"},
+		{name: "append missing key", op: appendOp("t", "bc"), key: "t", want: "bc"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rev := s.Rev()
+			// a failing op fails the txn before any write
+			rt := &pb.TxnRequest{Success: []*pb.RequestOp{
+				{Request: &pb.RequestOp_RequestPut{RequestPut: &pb.PutRequest{Key: []byte("x"), Value: []byte("x")}}},
+				tc.op,
+			}}
+			resp, _, err := Txn(context.TODO(), zaptest.NewLogger(t), rt, false, s, &lease.FakeLessor{})
+			if tc.err != nil {
+				assert.Equal(t, tc.err, err)
+				assert.Equal(t, rev, s.Rev())
+				return
+			}
+			require.NoError(t, err)
+			if ir := resp.Responses[1].GetResponseIncr(); ir != nil {
+				assert.Equal(t, tc.value, ir.Value)
+				if tc.key == "n" {
+					assert.Equal(t, "5", string(ir.PrevKv.Value))
+				}
+			}
+
+			rr, err := s.Range(context.TODO(), []byte(tc.key), nil, mvcc.RangeOptions{})
+			require.NoError(t, err)
+			require.Len(t, rr.KVs, 1)
+			assert.Equal(t, tc.want, string(rr.KVs[0].Value))
+			if tc.key == "n" {
+				// the key keeps its lease
+				assert.Equal(t, int64(7), rr.KVs[0].Lease)
+				s.Put([]byte("n"), []byte("5"), lease.LeaseID(7))
+			}
+		})
+	}
+}
+
+func TestRangeContinueToken(t *testing.T) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, b)
+	s := mvcc.NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, mvcc.StoreConfig{})
+	defer s.Close()
+
+	for _, k := range []string{"a", "b", "c", "d", "e"} {
+		s.Put([]byte(k), []byte("v"), lease.NoLease)
+	}
+	req := &pb.RangeRequest{Key: []byte("a"), RangeEnd: []byte("z"), Limit: 2}
+
+	var keys []string
+	for i := 0; ; i++ {
+		resp, err := Range(context.TODO(), zaptest.NewLogger(t), s, nil, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			// writes after the first page must not be visible in the following pages
+			s.Put([]byte("bb"), []byte("v"), lease.NoLease)
+			s.Put([]byte("f"), []byte("v"), lease.NoLease)
+		}
+		for _, kv := range resp.Kvs {
+			keys = append(keys, string(kv.Key))
+		}
+		if !resp.More {
+			assert.Empty(t, resp.ContinueToken)
+			break
+		}
+		req.ContinueToken = resp.ContinueToken
+	}
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, keys)
+}
+
+func TestRangeStream(t *testing.T) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, b)
+	s := mvcc.NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, mvcc.StoreConfig{})
+	defer s.Close()
+
+	defer func(n int) { rangeStreamChunkSize = n }(rangeStreamChunkSize)
+	rangeStreamChunkSize = 2
+
+	for _, k := range []string{"a", "b", "c", "d", "e"} {
+		s.Put([]byte(k), []byte("v"), lease.NoLease)
+	}
+
+	tests := []struct {
+		name   string
+		req    *pb.RangeRequest
+		chunks [][]string
+		more   bool
+	}{
+		{
+			name:   "whole range",
+			req:    &pb.RangeRequest{Key: []byte("a"), RangeEnd: []byte("z")},
+			chunks: [][]string{{"a", "b"}, {"c", "d"}, {"e"}},
+		},
+		{
+			name:   "limit",
+			req:    &pb.RangeRequest{Key: []byte("a"), RangeEnd: []byte("z"), Limit: 3},
+			chunks: [][]string{{"a", "b"}, {"c"}},
+			more:   true,
+		},
+		{
+			name:   "limit at chunk boundary",
+			req:    &pb.RangeRequest{Key: []byte("a"), RangeEnd: []byte("z"), Limit: 4},
+			chunks: [][]string{{"a", "b"}, {"c", "d"}},
+			more:   true,
+		},
+		{
+			name:   "revision bounds",
+			req:    &pb.RangeRequest{Key: []byte("a"), RangeEnd: []byte("z"), MinModRevision: 3, MaxModRevision: 5},
+			chunks: [][]string{{"b", "c"}, {"d"}},
+		},
+		{
+			name:   "empty range",
+			req:    &pb.RangeRequest{Key: []byte("x"), RangeEnd: []byte("z")},
+			chunks: [][]string{{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resps []*pb.RangeResponse
+			err := RangeStream(context.TODO(), s, tt.req, func(resp *pb.RangeResponse) error {
+				resps = append(resps, resp)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			chunks := make([][]string, len(resps))
+			for i, resp := range resps {
+				chunks[i] = []string{}
+				for _, kv := range resp.Kvs {
+					chunks[i] = append(chunks[i], string(kv.Key))
+				}
+				assert.Equal(t, int64(6), resp.Header.Revision)
+				if i < len(resps)-1 {
+					assert.True(t, resp.More)
+				}
+			}
+			assert.Equal(t, tt.chunks, chunks)
+			last := resps[len(resps)-1]
+			assert.Equal(t, tt.more, last.More)
+			assert.Equal(t, tt.more, len(last.ContinueToken) != 0)
+		})
+	}
+
+	err := RangeStream(context.TODO(), s, &pb.RangeRequest{Key: []byte("a"), RangeEnd: []byte("z"), SortTarget: pb.RangeRequest_MOD}, func(*pb.RangeResponse) error { return nil })
+	assert.Equal(t, errors.ErrInvalidStreamSort, err)
+}
+
+func TestRangeStreamAttachedLease(t *testing.T) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, b)
+	s := mvcc.NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, mvcc.StoreConfig{})
+	defer s.Close()
+
+	s.Put([]byte("a"), []byte("v"), lease.NoLease)
+	s.Put([]byte("b"), []byte("v"), lease.NoLease)
+	txn := s.Write(traceutil.TODO())
+	txn.AttachLease([]byte("a"), nil, 5)
+	txn.End()
+
+	var kvs []*mvccpb.KeyValue
+	err := RangeStream(context.TODO(), s, &pb.RangeRequest{Key: []byte("a"), RangeEnd: []byte("z")}, func(resp *pb.RangeResponse) error {
+		kvs = append(kvs, resp.Kvs...)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, kvs, 2)
+	assert.Equal(t, int64(5), kvs[0].Lease)
+	assert.Equal(t, int64(0), kvs[1].Lease)
+}
+
+func TestRangeInvalidContinueToken(t *testing.T) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, b)
+	s := mvcc.NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, mvcc.StoreConfig{})
+	defer s.Close()
+
+	rev := s.Put([]byte("foo"), []byte("bar"), lease.NoLease)
+
+	tests := []struct {
+		name string
+		req  *pb.RangeRequest
+	}{
+		{
+			name: "malformed token",
+			req:  &pb.RangeRequest{Key: []byte("a"), RangeEnd: []byte("z"), ContinueToken: []byte("garbage")},
+		},
+		{
+			name: "key outside of range",
+			req:  &pb.RangeRequest{Key: []byte("a"), RangeEnd: []byte("c"), ContinueToken: encodeContinueToken(rev, []byte("foo"))},
+		},
+		{
+			name: "single key",
+			req:  &pb.RangeRequest{Key: []byte("foo"), ContinueToken: encodeContinueToken(rev, []byte("foo"))},
+		},
+		{
+			name: "revision mismatch",
+			req:  &pb.RangeRequest{Key: []byte("a"), RangeEnd: []byte("z"), Revision: rev - 1, ContinueToken: encodeContinueToken(rev, []byte("foo"))},
+		},
+		{
+			name: "sorted by value",
+			req: &pb.RangeRequest{Key: []byte("a"), RangeEnd: []byte("z"), SortTarget: pb.RangeRequest_VALUE,
+				ContinueToken: encodeContinueToken(rev, []byte("foo"))},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Range(context.TODO(), zaptest.NewLogger(t), s, nil, tc.req)
+			assert.ErrorIs(t, err, errors.ErrInvalidContinueToken)
+		})
+	}
+}
+
+func TestRangeFilter(t *testing.T) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, b)
+	s := mvcc.NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, mvcc.StoreConfig{})
+	defer s.Close()
+
+	s.Put([]byte("a"), []byte(`{"kind":"pod"}`), lease.NoLease)
+	s.Put([]byte("b"), []byte(`{"kind":"node"}`), lease.NoLease)
+	s.Put([]byte("c"), []byte(`{"kind":"pod","big":true}`), 1)
+	s.Put([]byte("d"), []byte("plain"), lease.NoLease)
+	s.Put([]byte("d"), []byte("plain"), lease.NoLease)
+
+	tests := []struct {
+		name   string
+		filter *pb.RangeFilter
+		limit  int64
+		want   []string
+		more   bool
+	}{
+		{
+			name: "no filter",
+			want: []string{"a", "b", "c", "d"},
+		},
+		{
+			name:   "value prefix",
+			filter: &pb.RangeFilter{ValuePrefix: []byte("{")},
+			want:   []string{"a", "b", "c"},
+		},
+		{
+			name:   "value suffix",
+			filter: &pb.RangeFilter{ValueSuffix: []byte("}")},
+			want:   []string{"a", "b", "c"},
+		},
+		{
+			name:   "value contains",
+			filter: &pb.RangeFilter{ValueContains: []byte(`"pod"`)},
+			want:   []string{"a", "c"},
+		},
+		{
+			name:   "value contains with limit",
+			filter: &pb.RangeFilter{ValueContains: []byte(`"pod"`)},
+			limit:  1,
+			want:   []string{"a"},
+			more:   true,
+		},
+		{
+			name:   "value size",
+			filter: &pb.RangeFilter{MinValueSize: 6, MaxValueSize: 15},
+			want:   []string{"a", "b"},
+		},
+		{
+			name:   "lease attached",
+			filter: &pb.RangeFilter{Lease: pb.RangeFilter_ATTACHED},
+			want:   []string{"c"},
+		},
+		{
+			name:   "lease detached",
+			filter: &pb.RangeFilter{Lease: pb.RangeFilter_DETACHED},
+			want:   []string{"a", "b", "d"},
+		},
+		{
+			name:   "version",
+			filter: &pb.RangeFilter{MinVersion: 2},
+			want:   []string{"d"},
+		},
+		{
+			name:   "combined",
+			filter: &pb.RangeFilter{ValueContains: []byte("pod"), Lease: pb.RangeFilter_DETACHED, MaxVersion: 1},
+			want:   []string{"a"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := &pb.RangeRequest{Key: []byte("a"), RangeEnd: []byte("z"), Limit: tc.limit, Filter: tc.filter}
+			resp, err := Range(context.TODO(), zaptest.NewLogger(t), s, nil, req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var keys []string
+			for _, kv := range resp.Kvs {
+				keys = append(keys, string(kv.Key))
+			}
+			assert.Equal(t, tc.want, keys)
+			assert.Equal(t, tc.more, resp.More)
+		})
+	}
+}
+
+func TestRangeProjection(t *testing.T) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, b)
+	s := mvcc.NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, mvcc.StoreConfig{})
+	defer s.Close()
+
+	rev := s.Put([]byte("foo"), []byte("bar"), 1)
+
+	req := &pb.RangeRequest{Key: []byte("foo"), Projection: []pb.KeyValueField{pb.KeyValueField_MOD_REVISION, pb.KeyValueField_LEASE}}
+	resp, err := Range(context.TODO(), zaptest.NewLogger(t), s, nil, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []*mvccpb.KeyValue{{Key: []byte("foo"), ModRevision: rev, Lease: 1}}, resp.Kvs)
+}
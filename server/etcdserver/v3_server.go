@@ -19,6 +19,7 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/binary"
+	"math"
 	"strconv"
 	"time"
 
@@ -56,6 +57,8 @@ const (
 
 type RaftKV interface {
 	Range(ctx context.Context, r *pb.RangeRequest) (*pb.RangeResponse, error)
+	RangeStream(ctx context.Context, r *pb.RangeRequest, send func(*pb.RangeResponse) error) error
+	History(ctx context.Context, r *pb.HistoryRequest) (*pb.HistoryResponse, error)
 	Put(ctx context.Context, r *pb.PutRequest) (*pb.PutResponse, error)
 	DeleteRange(ctx context.Context, r *pb.DeleteRangeRequest) (*pb.DeleteRangeResponse, error)
 	Txn(ctx context.Context, r *pb.TxnRequest) (*pb.TxnResponse, error)
@@ -77,6 +80,14 @@ type Lessor interface {
 
 	// LeaseLeases lists all leases.
 	LeaseLeases(ctx context.Context, r *pb.LeaseLeasesRequest) (*pb.LeaseLeasesResponse, error)
+
+	// LeaseList lists the leases matching the label selectors, in pages.
+	LeaseList(ctx context.Context, r *pb.LeaseListRequest) (*pb.LeaseListResponse, error)
+
+	// LeaseAttach sends LeaseAttach request to raft and toApply it after committed.
+	LeaseAttach(ctx context.Context, r *pb.LeaseAttachRequest) (*pb.LeaseAttachResponse, error)
+	// LeaseDetach sends LeaseDetach request to raft and toApply it after committed.
+	LeaseDetach(ctx context.Context, r *pb.LeaseDetachRequest) (*pb.LeaseDetachResponse, error)
 }
 
 type Authenticator interface {
@@ -127,6 +138,9 @@ func (s *EtcdServer) Range(ctx context.Context, r *pb.RangeRequest) (*pb.RangeRe
 			return nil, err
 		}
 	}
+	if r, err = s.resolveAtTime(r); err != nil {
+		return nil, err
+	}
 	chk := func(ai *auth.AuthInfo) error {
 		return s.authStore.IsRangePermitted(ai, r.Key, r.RangeEnd)
 	}
@@ -139,6 +153,83 @@ func (s *EtcdServer) Range(ctx context.Context, r *pb.RangeRequest) (*pb.RangeRe
 	return resp, err
 }
 
+// resolveAtTime returns a copy of r reading at the revision of its at_time.
+// A continued range keeps the revision of its continue token.
+func (s *EtcdServer) resolveAtTime(r *pb.RangeRequest) (*pb.RangeRequest, error) {
+	if r.AtTime == 0 || len(r.ContinueToken) != 0 {
+		return r, nil
+	}
+	rev, err := s.timeIndex.Revision(time.Unix(0, r.AtTime), s.KV().Rev())
+	if err != nil {
+		return nil, err
+	}
+	resolved := *r
+	resolved.Revision = rev
+	return &resolved, nil
+}
+
+// RangeStream reads the range like Range, but passes the response to send in several parts.
+func (s *EtcdServer) RangeStream(ctx context.Context, r *pb.RangeRequest, send func(*pb.RangeResponse) error) error {
+	trace := traceutil.New("range_stream",
+		s.Logger(),
+		traceutil.Field{Key: "range_begin", Value: string(r.Key)},
+		traceutil.Field{Key: "range_end", Value: string(r.RangeEnd)},
+	)
+	ctx = context.WithValue(ctx, traceutil.TraceKey, trace)
+	defer trace.LogIfLong(traceThreshold)
+
+	if !r.Serializable {
+		err := s.linearizableReadNotify(ctx)
+		trace.Step("agreement among raft nodes before linearized reading")
+		if err != nil {
+			return err
+		}
+	}
+	r, err := s.resolveAtTime(r)
+	if err != nil {
+		return err
+	}
+	chk := func(ai *auth.AuthInfo) error {
+		return s.authStore.IsRangePermitted(ai, r.Key, r.RangeEnd)
+	}
+
+	get := func() { err = txn.RangeStream(ctx, s.KV(), r, send) }
+	if serr := s.doSerialize(ctx, chk, get); serr != nil {
+		return serr
+	}
+	return err
+}
+
+// History reads every revision of the keys in the range of r.
+func (s *EtcdServer) History(ctx context.Context, r *pb.HistoryRequest) (*pb.HistoryResponse, error) {
+	trace := traceutil.New("history",
+		s.Logger(),
+		traceutil.Field{Key: "range_begin", Value: string(r.Key)},
+		traceutil.Field{Key: "range_end", Value: string(r.RangeEnd)},
+	)
+	ctx = context.WithValue(ctx, traceutil.TraceKey, trace)
+	defer trace.LogIfLong(traceThreshold)
+
+	if !r.Serializable {
+		err := s.linearizableReadNotify(ctx)
+		trace.Step("agreement among raft nodes before linearized reading")
+		if err != nil {
+			return nil, err
+		}
+	}
+	chk := func(ai *auth.AuthInfo) error {
+		return s.authStore.IsRangePermitted(ai, r.Key, r.RangeEnd)
+	}
+
+	var resp *pb.HistoryResponse
+	var err error
+	get := func() { resp, err = txn.History(ctx, s.KV(), r) }
+	if serr := s.doSerialize(ctx, chk, get); serr != nil {
+		return nil, serr
+	}
+	return resp, err
+}
+
 func (s *EtcdServer) Put(ctx context.Context, r *pb.PutRequest) (*pb.PutResponse, error) {
 	ctx = context.WithValue(ctx, traceutil.StartTimeKey, time.Now())
 	resp, err := s.raftRequest(ctx, pb.InternalRaftRequest{Put: r})
@@ -190,6 +281,21 @@ func (s *EtcdServer) Txn(ctx context.Context, r *pb.TxnRequest) (*pb.TxnResponse
 		return resp, err
 	}
 
+	if txn.HasTxnIncrOrAppend(r) {
+		// the members running an older version cannot apply them
+		if !s.clusterVersionAtLeast(version.V3_6) {
+			return nil, errors.ErrClusterVersionTooLow
+		}
+		// the values written by the appends are checked against the current
+		// values, so that the keys cannot grow beyond the request size limit.
+		// The check is repeated when the txn is applied.
+		for _, p := range txn.TxnPuts(s.KV(), r) {
+			if len(p.Key)+len(p.Value) > int(s.Cfg.MaxRequestBytes) {
+				return nil, errors.ErrRequestTooLarge
+			}
+		}
+	}
+
 	ctx = context.WithValue(ctx, traceutil.StartTimeKey, time.Now())
 	resp, err := s.raftRequest(ctx, pb.InternalRaftRequest{Txn: r})
 	if err != nil {
@@ -200,7 +306,10 @@ func (s *EtcdServer) Txn(ctx context.Context, r *pb.TxnRequest) (*pb.TxnResponse
 
 func (s *EtcdServer) Compact(ctx context.Context, r *pb.CompactionRequest) (*pb.CompactionResponse, error) {
 	startTime := time.Now()
-	result, err := s.processInternalRaftRequestOnce(ctx, pb.InternalRaftRequest{Compaction: r})
+	// the members apply the retention rules resolved by the proposing member
+	cr := *r
+	cr.Retention = s.compactionRetention.resolve(startTime)
+	result, err := s.processInternalRaftRequestOnce(ctx, pb.InternalRaftRequest{Compaction: &cr})
 	trace := traceutil.TODO()
 	if result != nil && result.Trace != nil {
 		trace = result.Trace
@@ -276,6 +385,37 @@ func (s *EtcdServer) LeaseRevoke(ctx context.Context, r *pb.LeaseRevokeRequest)
 	return resp.(*pb.LeaseRevokeResponse), nil
 }
 
+func (s *EtcdServer) LeaseAttach(ctx context.Context, r *pb.LeaseAttachRequest) (*pb.LeaseAttachResponse, error) {
+	resp, err := s.raftRequestOnce(ctx, pb.InternalRaftRequest{LeaseAttach: r})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.LeaseAttachResponse), nil
+}
+
+func (s *EtcdServer) LeaseDetach(ctx context.Context, r *pb.LeaseDetachRequest) (*pb.LeaseDetachResponse, error) {
+	resp, err := s.raftRequestOnce(ctx, pb.InternalRaftRequest{LeaseDetach: r})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.LeaseDetachResponse), nil
+}
+
+// leaseExpire revokes an expired lease, deleting its keys with the
+// LEASE_EXPIRED cause. The lease is revoked with LeaseRevoke until all the
+// members can apply LeaseExpire.
+func (s *EtcdServer) leaseExpire(ctx context.Context, id int64) (*pb.LeaseRevokeResponse, error) {
+	r := pb.InternalRaftRequest{LeaseExpire: &pb.LeaseRevokeRequest{ID: id}}
+	if !s.clusterVersionAtLeast(version.V3_6) {
+		r = pb.InternalRaftRequest{LeaseRevoke: &pb.LeaseRevokeRequest{ID: id}}
+	}
+	resp, err := s.raftRequestOnce(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.LeaseRevokeResponse), nil
+}
+
 func (s *EtcdServer) LeaseRenew(ctx context.Context, id lease.LeaseID) (int64, error) {
 	if s.isLeader() {
 		if err := s.waitAppliedIndex(); err != nil {
@@ -327,17 +467,7 @@ func (s *EtcdServer) LeaseTimeToLive(ctx context.Context, r *pb.LeaseTimeToLiveR
 		if le == nil {
 			return nil, lease.ErrLeaseNotFound
 		}
-		// TODO: fill out ResponseHeader
-		resp := &pb.LeaseTimeToLiveResponse{Header: &pb.ResponseHeader{}, ID: r.ID, TTL: int64(le.Remaining().Seconds()), GrantedTTL: le.TTL()}
-		if r.Keys {
-			ks := le.Keys()
-			kbs := make([][]byte, len(ks))
-			for i := range ks {
-				kbs[i] = []byte(ks[i])
-			}
-			resp.Keys = kbs
-		}
-		return resp, nil
+		return leasehttp.TimeToLiveResponse(le, r), nil
 	}
 
 	cctx, cancel := context.WithTimeout(ctx, s.Cfg.ReqTimeout())
@@ -351,7 +481,7 @@ func (s *EtcdServer) LeaseTimeToLive(ctx context.Context, r *pb.LeaseTimeToLiveR
 		}
 		for _, url := range leader.PeerURLs {
 			lurl := url + leasehttp.LeaseInternalPrefix
-			resp, err := leasehttp.TimeToLiveHTTP(cctx, lease.LeaseID(r.ID), r.Keys, lurl, s.peerRt)
+			resp, err := leasehttp.TimeToLiveHTTP(cctx, r, lurl, s.peerRt)
 			if err == nil {
 				return resp.LeaseTimeToLiveResponse, nil
 			}
@@ -386,6 +516,37 @@ func (s *EtcdServer) LeaseLeases(_ context.Context, _ *pb.LeaseLeasesRequest) (*
 	return &pb.LeaseLeasesResponse{Header: s.newHeader(), Leases: lss}, nil
 }
 
+func (s *EtcdServer) LeaseList(_ context.Context, r *pb.LeaseListRequest) (*pb.LeaseListResponse, error) {
+	sel, err := lease.ParseLabelSelector(r.Selectors)
+	if err != nil {
+		return nil, err
+	}
+	from := lease.LeaseID(math.MinInt64)
+	if len(r.ContinueToken) != 0 {
+		if len(r.ContinueToken) != 8 {
+			return nil, errors.ErrInvalidContinueToken
+		}
+		// the token is the ID following the last lease of the previous page
+		from = lease.LeaseID(binary.BigEndian.Uint64(r.ContinueToken))
+	}
+	ls, more := s.lessor.List(sel, from, int(r.Limit))
+	resp := &pb.LeaseListResponse{Header: s.newHeader(), Leases: make([]*pb.LeaseInfo, len(ls))}
+	for i, l := range ls {
+		resp.Leases[i] = &pb.LeaseInfo{
+			ID:         int64(l.ID),
+			GrantedTTL: l.TTL(),
+			Labels:     l.Labels(),
+			Parent:     int64(l.Parent()),
+			Keys:       int64(l.KeyCount()),
+		}
+	}
+	if more {
+		resp.More = true
+		resp.ContinueToken = binary.BigEndian.AppendUint64(nil, uint64(ls[len(ls)-1].ID+1))
+	}
+	return resp, nil
+}
+
 func (s *EtcdServer) waitLeader(ctx context.Context) (*membership.Member, error) {
 	leader := s.cluster.Member(s.Leader())
 	for leader == nil {
@@ -414,6 +575,61 @@ func (s *EtcdServer) Alarm(ctx context.Context, r *pb.AlarmRequest) (*pb.AlarmRe
 	return resp.(*pb.AlarmResponse), nil
 }
 
+func (s *EtcdServer) QuotaSet(ctx context.Context, r *pb.QuotaSetRequest) (*pb.QuotaSetResponse, error) {
+	resp, err := s.raftRequestOnce(ctx, pb.InternalRaftRequest{QuotaSet: r})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.QuotaSetResponse), nil
+}
+
+func (s *EtcdServer) QuotaDelete(ctx context.Context, r *pb.QuotaDeleteRequest) (*pb.QuotaDeleteResponse, error) {
+	resp, err := s.raftRequestOnce(ctx, pb.InternalRaftRequest{QuotaDelete: r})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.QuotaDeleteResponse), nil
+}
+
+func (s *EtcdServer) QuotaList(ctx context.Context, r *pb.QuotaListRequest) (*pb.QuotaListResponse, error) {
+	if err := s.linearizableReadNotify(ctx); err != nil {
+		return nil, err
+	}
+	return &pb.QuotaListResponse{Header: &pb.ResponseHeader{}, Quotas: s.quotaStore.List()}, nil
+}
+
+func (s *EtcdServer) CompactionRetention(ctx context.Context, r *pb.CompactionRetentionRequest) (*pb.CompactionRetentionResponse, error) {
+	return &pb.CompactionRetentionResponse{Header: &pb.ResponseHeader{}, Rules: s.compactionRetention.resolve(time.Now())}, nil
+}
+
+func (s *EtcdServer) CompactionHold(ctx context.Context, r *pb.CompactionHoldRequest) (*pb.CompactionHoldResponse, error) {
+	// no id given? choose one
+	for r.ID == 0 {
+		// only use positive int64 id's
+		r.ID = int64(s.reqIDGen.Next() & ((1 << 63) - 1))
+	}
+	resp, err := s.raftRequestOnce(ctx, pb.InternalRaftRequest{CompactionHold: r})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.CompactionHoldResponse), nil
+}
+
+func (s *EtcdServer) CompactionHoldRelease(ctx context.Context, r *pb.CompactionHoldReleaseRequest) (*pb.CompactionHoldReleaseResponse, error) {
+	resp, err := s.raftRequestOnce(ctx, pb.InternalRaftRequest{CompactionHoldRelease: r})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.CompactionHoldReleaseResponse), nil
+}
+
+func (s *EtcdServer) CompactionHoldList(ctx context.Context, r *pb.CompactionHoldListRequest) (*pb.CompactionHoldListResponse, error) {
+	if err := s.linearizableReadNotify(ctx); err != nil {
+		return nil, err
+	}
+	return &pb.CompactionHoldListResponse{Header: &pb.ResponseHeader{}, Holds: s.holdStore.List()}, nil
+}
+
 func (s *EtcdServer) AuthEnable(ctx context.Context, r *pb.AuthEnableRequest) (*pb.AuthEnableResponse, error) {
 	resp, err := s.raftRequestOnce(ctx, pb.InternalRaftRequest{AuthEnable: r})
 	if err != nil {
@@ -447,7 +663,7 @@ func (s *EtcdServer) Authenticate(ctx context.Context, r *pb.AuthenticateRequest
 
 	var resp proto.Message
 	for {
-		checkedRevision, err := s.AuthStore().CheckPassword(r.Name, r.Password)
+		checkedRevision, id, err := s.AuthStore().CheckCredentials(ctx, r.Name, r.Password)
 		if err != nil {
 			if err != auth.ErrAuthNotEnabled {
 				lg.Warn(
@@ -464,12 +680,18 @@ func (s *EtcdServer) Authenticate(ctx context.Context, r *pb.AuthenticateRequest
 			return nil, err
 		}
 
-		// internalReq doesn't need to have Password because the above s.AuthStore().CheckPassword() already did it.
+		// internalReq doesn't need to have Password because the above s.AuthStore().CheckCredentials() already did it.
 		// In addition, it will let a WAL entry not record password as a plain text.
 		internalReq := &pb.InternalAuthenticateRequest{
 			Name:        r.Name,
 			SimpleToken: st,
 		}
+		if id != nil {
+			// the identity verified by the authenticator is applied on every member
+			internalReq.Name = id.Name
+			internalReq.External = true
+			internalReq.Roles = id.Roles
+		}
 
 		resp, err = s.raftRequestOnce(ctx, pb.InternalRaftRequest{Authenticate: internalReq})
 		if err != nil {
@@ -479,7 +701,7 @@ func (s *EtcdServer) Authenticate(ctx context.Context, r *pb.AuthenticateRequest
 			break
 		}
 
-		lg.Info("revision when password checked became stale; retrying")
+		lg.Info("revision when credentials checked became stale; retrying")
 	}
 
 	return resp.(*pb.AuthenticateResponse), nil
@@ -592,6 +814,11 @@ func (s *EtcdServer) RoleList(ctx context.Context, r *pb.AuthRoleListRequest) (*
 }
 
 func (s *EtcdServer) RoleRevokePermission(ctx context.Context, r *pb.AuthRoleRevokePermissionRequest) (*pb.AuthRoleRevokePermissionResponse, error) {
+	if r.Perm != nil && !s.clusterVersionAtLeast(version.V3_6) {
+		// the members running an older version would revoke every permission
+		// on the range
+		return nil, errors.ErrClusterVersionTooLow
+	}
 	resp, err := s.raftRequest(ctx, pb.InternalRaftRequest{AuthRoleRevokePermission: r})
 	if err != nil {
 		return nil, err
@@ -664,7 +891,8 @@ func (s *EtcdServer) processInternalRaftRequestOnce(ctx context.Context, r pb.In
 	}
 
 	r.Header = &pb.RequestHeader{
-		ID: s.reqIDGen.Next(),
+		ID:        s.reqIDGen.Next(),
+		Timestamp: time.Now().UnixNano(),
 	}
 
 	// check authinfo if it is not InternalAuthenticateRequest
@@ -676,6 +904,8 @@ func (s *EtcdServer) processInternalRaftRequestOnce(ctx context.Context, r pb.In
 		if authInfo != nil {
 			r.Header.Username = authInfo.Username
 			r.Header.AuthRevision = authInfo.Revision
+			r.Header.External = authInfo.External
+			r.Header.Roles = authInfo.Roles
 		}
 	}
 
@@ -0,0 +1,138 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lease
+
+import (
+	"sort"
+	"strings"
+
+	"go.etcd.io/etcd/server/v3/lease/leasepb"
+)
+
+const (
+	// MaxLabels is the maximum number of labels of a lease.
+	MaxLabels = 16
+	// MaxLabelSize is the maximum size in bytes of the key and of the value
+	// of a label.
+	MaxLabelSize = 256
+)
+
+// ValidateLabels returns ErrLeaseLabelInvalid if the labels cannot be set on
+// a lease.
+func ValidateLabels(labels map[string]string) error {
+	if len(labels) > MaxLabels {
+		return ErrLeaseLabelInvalid
+	}
+	for k, v := range labels {
+		if !validLabelKey(k) || len(v) > MaxLabelSize {
+			return ErrLeaseLabelInvalid
+		}
+	}
+	return nil
+}
+
+// validLabelKey reports whether the key can be told apart in a selector.
+func validLabelKey(k string) bool {
+	return k != "" && len(k) <= MaxLabelSize && !strings.ContainsAny(k, "=!, \t\n")
+}
+
+func labelsToPB(labels map[string]string) []*leasepb.LeaseLabel {
+	if len(labels) == 0 {
+		return nil
+	}
+	lpbs := make([]*leasepb.LeaseLabel, 0, len(labels))
+	for k, v := range labels {
+		lpbs = append(lpbs, &leasepb.LeaseLabel{Key: k, Value: v})
+	}
+	sort.Slice(lpbs, func(i, j int) bool { return lpbs[i].Key < lpbs[j].Key })
+	return lpbs
+}
+
+func labelsFromPB(lpbs []*leasepb.LeaseLabel) map[string]string {
+	if len(lpbs) == 0 {
+		return nil
+	}
+	labels := make(map[string]string, len(lpbs))
+	for _, lpb := range lpbs {
+		labels[lpb.Key] = lpb.Value
+	}
+	return labels
+}
+
+type selectorOp int
+
+const (
+	selectEqual selectorOp = iota
+	selectNotEqual
+	selectExists
+	selectNotExists
+)
+
+type labelRequirement struct {
+	op    selectorOp
+	key   string
+	value string
+}
+
+func (r labelRequirement) matches(labels map[string]string) bool {
+	v, ok := labels[r.key]
+	switch r.op {
+	case selectEqual:
+		return ok && v == r.value
+	case selectNotEqual:
+		return !ok || v != r.value
+	case selectExists:
+		return ok
+	default:
+		return !ok
+	}
+}
+
+// LabelSelector selects the leases matching all of its requirements on their
+// labels. The empty selector selects all the leases.
+type LabelSelector []labelRequirement
+
+// ParseLabelSelector parses the selectors "key=value", "key!=value", "key"
+// for the leases with the label, and "!key" for the leases without it.
+func ParseLabelSelector(selectors []string) (LabelSelector, error) {
+	sel := make(LabelSelector, 0, len(selectors))
+	for _, s := range selectors {
+		var r labelRequirement
+		if i := strings.Index(s, "!="); i >= 0 {
+			r = labelRequirement{op: selectNotEqual, key: s[:i], value: s[i+2:]}
+		} else if i = strings.IndexByte(s, '='); i >= 0 {
+			r = labelRequirement{op: selectEqual, key: s[:i], value: s[i+1:]}
+		} else if strings.HasPrefix(s, "!") {
+			r = labelRequirement{op: selectNotExists, key: s[1:]}
+		} else {
+			r = labelRequirement{op: selectExists, key: s}
+		}
+		if !validLabelKey(r.key) {
+			return nil, ErrLabelSelectorInvalid
+		}
+		sel = append(sel, r)
+	}
+	return sel, nil
+}
+
+// Matches reports whether the labels match the selector.
+func (sel LabelSelector) Matches(labels map[string]string) bool {
+	for _, r := range sel {
+		if !r.matches(labels) {
+			return false
+		}
+	}
+	return true
+}
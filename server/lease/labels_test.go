@@ -0,0 +1,78 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lease
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateLabels(t *testing.T) {
+	tooMany := make(map[string]string)
+	for i := 0; i <= MaxLabels; i++ {
+		tooMany[strings.Repeat("k", i+1)] = "v"
+	}
+	tests := []struct {
+		labels map[string]string
+		werr   error
+	}{
+		{nil, nil},
+		{map[string]string{"owner": "worker-1", "empty": ""}, nil},
+		{map[string]string{"": "v"}, ErrLeaseLabelInvalid},
+		{map[string]string{"a=b": "v"}, ErrLeaseLabelInvalid},
+		{map[string]string{"!a": "v"}, ErrLeaseLabelInvalid},
+		{map[string]string{"a b": "v"}, ErrLeaseLabelInvalid},
+		{map[string]string{"a": strings.Repeat("v", MaxLabelSize+1)}, ErrLeaseLabelInvalid},
+		{tooMany, ErrLeaseLabelInvalid},
+	}
+	for i, tt := range tests {
+		if err := ValidateLabels(tt.labels); err != tt.werr {
+			t.Errorf("#%d: err = %v, want %v", i, err, tt.werr)
+		}
+	}
+}
+
+func TestLabelSelector(t *testing.T) {
+	labels := map[string]string{"owner": "worker-1", "service": "api"}
+	tests := []struct {
+		selectors []string
+		wmatch    bool
+	}{
+		{nil, true},
+		{[]string{"owner=worker-1"}, true},
+		{[]string{"owner=worker-2"}, false},
+		{[]string{"owner!=worker-2", "service"}, true},
+		{[]string{"owner!=worker-1"}, false},
+		{[]string{"zone!=eu"}, true},
+		{[]string{"!zone", "service=api"}, true},
+		{[]string{"!owner"}, false},
+		{[]string{"zone"}, false},
+	}
+	for i, tt := range tests {
+		sel, err := ParseLabelSelector(tt.selectors)
+		if err != nil {
+			t.Fatalf("#%d: unexpected error %v", i, err)
+		}
+		if match := sel.Matches(labels); match != tt.wmatch {
+			t.Errorf("#%d: match = %t, want %t", i, match, tt.wmatch)
+		}
+	}
+
+	for _, s := range []string{"", "=v", "!", "!=v", "a b=v"} {
+		if _, err := ParseLabelSelector([]string{s}); err != ErrLabelSelectorInvalid {
+			t.Errorf("selector %q: err = %v, want %v", s, err, ErrLabelSelectorInvalid)
+		}
+	}
+}
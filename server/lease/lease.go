@@ -16,6 +16,7 @@ package lease
 
 import (
 	"math"
+	"sort"
 	"sync"
 	"time"
 
@@ -33,10 +34,16 @@ type Lease struct {
 	// expiry is time when lease should expire. no expiration when expiry.IsZero() is true
 	expiry time.Time
 
-	// mu protects concurrent accesses to itemSet
-	mu      sync.RWMutex
-	itemSet map[LeaseItem]struct{}
-	revokec chan struct{}
+	// parent is the lease the lease is revoked with, nil if none
+	parent *Lease
+	// labels are never modified once granted
+	labels map[string]string
+
+	// mu protects concurrent accesses to itemSet and children
+	mu       sync.RWMutex
+	itemSet  map[LeaseItem]struct{}
+	children map[LeaseID]*Lease
+	revokec  chan struct{}
 }
 
 func (l *Lease) expired() bool {
@@ -44,7 +51,7 @@ func (l *Lease) expired() bool {
 }
 
 func (l *Lease) persistTo(b backend.Backend) {
-	lpb := leasepb.Lease{ID: int64(l.ID), TTL: l.ttl, RemainingTTL: l.remainingTTL}
+	lpb := leasepb.Lease{ID: int64(l.ID), TTL: l.ttl, RemainingTTL: l.remainingTTL, Parent: int64(l.Parent()), Labels: labelsToPB(l.labels)}
 	tx := b.BatchTx()
 	tx.LockInsideApply()
 	defer tx.Unlock()
@@ -90,8 +97,111 @@ func (l *Lease) Keys() []string {
 	return keys
 }
 
-// Remaining returns the remaining time of the lease.
+// Parent returns the ID of the lease the lease is revoked with, NoLease if
+// none.
+func (l *Lease) Parent() LeaseID {
+	if l.parent == nil {
+		return NoLease
+	}
+	return l.parent.ID
+}
+
+// Labels returns a copy of the labels of the lease.
+func (l *Lease) Labels() map[string]string {
+	if l.labels == nil {
+		return nil
+	}
+	labels := make(map[string]string, len(l.labels))
+	for k, v := range l.labels {
+		labels[k] = v
+	}
+	return labels
+}
+
+// Children returns the IDs of the leases revoked with the lease, in order.
+func (l *Lease) Children() []LeaseID {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return sortedLeaseIDs(l.children)
+}
+
+func sortedLeaseIDs(ls map[LeaseID]*Lease) []LeaseID {
+	ids := make([]LeaseID, 0, len(ls))
+	for id := range ls {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func (l *Lease) addChild(c *Lease) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.children == nil {
+		l.children = make(map[LeaseID]*Lease)
+	}
+	l.children[c.ID] = c
+}
+
+func (l *Lease) removeChild(c *Lease) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.children, c.ID)
+}
+
+// tree returns the lease followed by its descendants, each lease before its
+// children and the children in order.
+func (l *Lease) tree() []*Lease {
+	ls := []*Lease{l}
+	for i := 0; i < len(ls); i++ {
+		cur := ls[i]
+		cur.mu.RLock()
+		for _, id := range sortedLeaseIDs(cur.children) {
+			ls = append(ls, cur.children[id])
+		}
+		cur.mu.RUnlock()
+	}
+	return ls
+}
+
+// KeyCount returns the number of the keys attached to the lease.
+func (l *Lease) KeyCount() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return len(l.itemSet)
+}
+
+// KeysAfter returns in order at most limit keys attached to the lease after
+// the given key, all of them if limit is 0, and whether there are more.
+func (l *Lease) KeysAfter(after string, limit int) ([]string, bool) {
+	l.mu.RLock()
+	keys := make([]string, 0, len(l.itemSet))
+	for k := range l.itemSet {
+		if k.Key > after {
+			keys = append(keys, k.Key)
+		}
+	}
+	l.mu.RUnlock()
+	sort.Strings(keys)
+	if limit > 0 && len(keys) > limit {
+		return keys[:limit], true
+	}
+	return keys, false
+}
+
+// Remaining returns the remaining time of the lease. A child lease is revoked
+// with its parent, so it never remains longer than the parent.
 func (l *Lease) Remaining() time.Duration {
+	r := l.remaining()
+	if l.parent != nil {
+		if pr := l.parent.Remaining(); pr < r {
+			return pr
+		}
+	}
+	return r
+}
+
+func (l *Lease) remaining() time.Duration {
 	l.expiryMu.RLock()
 	defer l.expiryMu.RUnlock()
 	if l.expiry.IsZero() {
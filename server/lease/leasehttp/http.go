@@ -108,22 +108,8 @@ func (h *leaseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, lease.ErrLeaseNotFound.Error(), http.StatusNotFound)
 			return
 		}
-		// TODO: fill out ResponseHeader
 		resp := &leasepb.LeaseInternalResponse{
-			LeaseTimeToLiveResponse: &pb.LeaseTimeToLiveResponse{
-				Header:     &pb.ResponseHeader{},
-				ID:         lreq.LeaseTimeToLiveRequest.ID,
-				TTL:        int64(l.Remaining().Seconds()),
-				GrantedTTL: l.TTL(),
-			},
-		}
-		if lreq.LeaseTimeToLiveRequest.Keys {
-			ks := l.Keys()
-			kbs := make([][]byte, len(ks))
-			for i := range ks {
-				kbs[i] = []byte(ks[i])
-			}
-			resp.LeaseTimeToLiveResponse.Keys = kbs
+			LeaseTimeToLiveResponse: TimeToLiveResponse(l, lreq.LeaseTimeToLiveRequest),
 		}
 
 		v, err = resp.Marshal()
@@ -189,14 +175,41 @@ func RenewHTTP(ctx context.Context, id lease.LeaseID, url string, rt http.RoundT
 	return lresp.TTL, nil
 }
 
-// TimeToLiveHTTP retrieves lease information of the given lease ID.
-func TimeToLiveHTTP(ctx context.Context, id lease.LeaseID, keys bool, url string, rt http.RoundTripper) (*leasepb.LeaseInternalResponse, error) {
+// TimeToLiveResponse returns the lease information of the lease requested by r.
+func TimeToLiveResponse(l *lease.Lease, r *pb.LeaseTimeToLiveRequest) *pb.LeaseTimeToLiveResponse {
+	// TODO: fill out ResponseHeader
+	resp := &pb.LeaseTimeToLiveResponse{
+		Header:     &pb.ResponseHeader{},
+		ID:         r.ID,
+		TTL:        int64(l.Remaining().Seconds()),
+		GrantedTTL: l.TTL(),
+		Parent:     int64(l.Parent()),
+		Labels:     l.Labels(),
+	}
+	for _, id := range l.Children() {
+		resp.Children = append(resp.Children, int64(id))
+	}
+	if r.Keys {
+		// the keys are listed in order from the last key of the previous page
+		ks, more := l.KeysAfter(string(r.ContinueToken), int(r.Limit))
+		kbs := make([][]byte, len(ks))
+		for i := range ks {
+			kbs[i] = []byte(ks[i])
+		}
+		resp.Keys = kbs
+		if more {
+			resp.More = true
+			resp.ContinueToken = kbs[len(kbs)-1]
+		}
+	}
+	return resp
+}
+
+// TimeToLiveHTTP retrieves lease information of the lease requested by r.
+func TimeToLiveHTTP(ctx context.Context, r *pb.LeaseTimeToLiveRequest, url string, rt http.RoundTripper) (*leasepb.LeaseInternalResponse, error) {
 	// will post lreq protobuf to leader
 	lreq, err := (&leasepb.LeaseInternalRequest{
-		LeaseTimeToLiveRequest: &pb.LeaseTimeToLiveRequest{
-			ID:   int64(id),
-			Keys: keys,
-		},
+		LeaseTimeToLiveRequest: r,
 	}).Marshal()
 	if err != nil {
 		return nil, err
@@ -235,7 +248,7 @@ func TimeToLiveHTTP(ctx context.Context, id lease.LeaseID, keys bool, url string
 	if err := lresp.Unmarshal(b); err != nil {
 		return nil, fmt.Errorf(`lease: %v. data = "%s"`, err, string(b))
 	}
-	if lresp.LeaseTimeToLiveResponse.ID != int64(id) {
+	if lresp.LeaseTimeToLiveResponse.ID != r.ID {
 		return nil, fmt.Errorf("lease: TTL id mismatch")
 	}
 	return lresp, nil
@@ -27,6 +27,7 @@ import (
 	"go.uber.org/zap"
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
 	"go.etcd.io/etcd/api/v3/version"
 	"go.etcd.io/etcd/server/v3/lease/leasepb"
 	"go.etcd.io/etcd/server/v3/storage/backend"
@@ -57,16 +58,21 @@ var (
 	// the default interval to check if the expired lease is revoked
 	defaultExpiredleaseRetryInterval = 3 * time.Second
 
-	ErrNotPrimary       = errors.New("not a primary lessor")
-	ErrLeaseNotFound    = errors.New("lease not found")
-	ErrLeaseExists      = errors.New("lease already exists")
-	ErrLeaseTTLTooLarge = errors.New("too large lease TTL")
+	ErrNotPrimary           = errors.New("not a primary lessor")
+	ErrLeaseNotFound        = errors.New("lease not found")
+	ErrLeaseExists          = errors.New("lease already exists")
+	ErrLeaseTTLTooLarge     = errors.New("too large lease TTL")
+	ErrLeaseLabelInvalid    = errors.New("invalid lease label")
+	ErrLabelSelectorInvalid = errors.New("invalid lease label selector")
 )
 
 // TxnDelete is a TxnWrite that only permits deletes. Defined here
 // to avoid circular dependency with mvcc.
 type TxnDelete interface {
 	DeleteRange(key, end []byte) (n, rev int64)
+	// SetDeleteCause sets the cause of the following deletes, and the ID of
+	// the lease whose revocation causes them.
+	SetDeleteCause(cause mvccpb.Event_DeleteCause, id LeaseID)
 	End()
 }
 
@@ -79,6 +85,14 @@ type Checkpointer func(ctx context.Context, lc *pb.LeaseCheckpointRequest)
 
 type LeaseID int64
 
+// GrantOptions are the optional attributes of a granted lease.
+type GrantOptions struct {
+	// Parent is the lease the granted lease is revoked with, NoLease if none.
+	Parent LeaseID
+	// Labels are the labels of the lease, validated by ValidateLabels.
+	Labels map[string]string
+}
+
 // Lessor owns leases. It can grant, revoke, renew and modify leases for lessee.
 type Lessor interface {
 	// SetRangeDeleter lets the lessor create TxnDeletes to the store.
@@ -90,11 +104,18 @@ type Lessor interface {
 
 	// Grant grants a lease that expires at least after TTL seconds.
 	Grant(id LeaseID, ttl int64) (*Lease, error)
-	// Revoke revokes a lease with given ID. The item attached to the
-	// given lease will be removed. If the ID does not exist, an error
-	// will be returned.
+	// GrantWithOptions grants a lease like Grant, with the given parent
+	// and labels. If the parent does not exist, an error will be returned.
+	GrantWithOptions(id LeaseID, ttl int64, opts GrantOptions) (*Lease, error)
+	// Revoke revokes a lease with given ID and its child leases. The item
+	// attached to the given lease and its children will be removed. If
+	// the ID does not exist, an error will be returned.
 	Revoke(id LeaseID) error
 
+	// RevokeExpired revokes an expired lease with given ID. It is Revoke,
+	// except that the items are removed with the LEASE_EXPIRED cause.
+	RevokeExpired(id LeaseID) error
+
 	// Checkpoint applies the remainingTTL of a lease. The remainingTTL is used in Promote to set
 	// the expiry of leases to less than the full TTL when possible.
 	Checkpoint(id LeaseID, remainingTTL int64) error
@@ -129,6 +150,11 @@ type Lessor interface {
 	// Leases lists all leases.
 	Leases() []*Lease
 
+	// List lists in ID order at most limit leases matching the selector
+	// from the given ID, all of them if limit is 0, and whether there are
+	// more.
+	List(sel LabelSelector, from LeaseID, limit int) ([]*Lease, bool)
+
 	// ExpiredLeasesC returns a chan that is used to receive expired leases.
 	ExpiredLeasesC() <-chan []*Lease
 
@@ -269,6 +295,10 @@ func (le *lessor) SetCheckpointer(cp Checkpointer) {
 }
 
 func (le *lessor) Grant(id LeaseID, ttl int64) (*Lease, error) {
+	return le.GrantWithOptions(id, ttl, GrantOptions{})
+}
+
+func (le *lessor) GrantWithOptions(id LeaseID, ttl int64, opts GrantOptions) (*Lease, error) {
 	if id == NoLease {
 		return nil, ErrLeaseNotFound
 	}
@@ -282,6 +312,7 @@ func (le *lessor) Grant(id LeaseID, ttl int64) (*Lease, error) {
 	l := &Lease{
 		ID:      id,
 		ttl:     ttl,
+		labels:  opts.Labels,
 		itemSet: make(map[LeaseItem]struct{}),
 		revokec: make(chan struct{}),
 	}
@@ -297,6 +328,15 @@ func (le *lessor) Grant(id LeaseID, ttl int64) (*Lease, error) {
 		return nil, ErrLeaseExists
 	}
 
+	if opts.Parent != NoLease {
+		pl := le.leaseMap[opts.Parent]
+		if pl == nil {
+			return nil, ErrLeaseNotFound
+		}
+		l.parent = pl
+		pl.addChild(l)
+	}
+
 	if le.isPrimary() {
 		l.refresh(0)
 	} else {
@@ -319,6 +359,14 @@ func (le *lessor) Grant(id LeaseID, ttl int64) (*Lease, error) {
 }
 
 func (le *lessor) Revoke(id LeaseID) error {
+	return le.revoke(id, mvccpb.LEASE_REVOKED)
+}
+
+func (le *lessor) RevokeExpired(id LeaseID) error {
+	return le.revoke(id, mvccpb.LEASE_EXPIRED)
+}
+
+func (le *lessor) revoke(id LeaseID, cause mvccpb.Event_DeleteCause) error {
 	le.mu.Lock()
 
 	l := le.leaseMap[id]
@@ -327,12 +375,20 @@ func (le *lessor) Revoke(id LeaseID) error {
 		return ErrLeaseNotFound
 	}
 
+	// the child leases are revoked with their parent, in the same order
+	// among all members
+	ls := l.tree()
+	if l.parent != nil {
+		l.parent.removeChild(l)
+	}
+
 	// We shouldn't delete the lease inside the transaction lock, otherwise
 	// it may lead to deadlock with Grant or Checkpoint operations, which
 	// acquire the le.mu firstly and then the batchTx lock.
-	delete(le.leaseMap, id)
-
-	defer close(l.revokec)
+	for _, rl := range ls {
+		delete(le.leaseMap, rl.ID)
+		defer close(rl.revokec)
+	}
 	// unlock before doing external work
 	le.mu.Unlock()
 
@@ -341,23 +397,26 @@ func (le *lessor) Revoke(id LeaseID) error {
 	}
 
 	txn := le.rd()
+	for _, rl := range ls {
+		txn.SetDeleteCause(cause, rl.ID)
+
+		// sort keys so deletes are in same order among all members,
+		// otherwise the backend hashes will be different
+		keys := rl.Keys()
+		sort.StringSlice(keys).Sort()
+		for _, key := range keys {
+			txn.DeleteRange([]byte(key), nil)
+		}
 
-	// sort keys so deletes are in same order among all members,
-	// otherwise the backend hashes will be different
-	keys := l.Keys()
-	sort.StringSlice(keys).Sort()
-	for _, key := range keys {
-		txn.DeleteRange([]byte(key), nil)
+		// lease deletion needs to be in the same backend transaction with the
+		// kv deletion. Or we might end up with not executing the revoke or not
+		// deleting the keys if etcdserver fails in between.
+		schema.UnsafeDeleteLease(le.b.BatchTx(), &leasepb.Lease{ID: int64(rl.ID)})
 	}
 
-	// lease deletion needs to be in the same backend transaction with the
-	// kv deletion. Or we might end up with not executing the revoke or not
-	// deleting the keys if etcdserver fails in between.
-	schema.UnsafeDeleteLease(le.b.BatchTx(), &leasepb.Lease{ID: int64(l.ID)})
-
 	txn.End()
 
-	leaseRevoked.Inc()
+	leaseRevoked.Add(float64(len(ls)))
 	return nil
 }
 
@@ -464,6 +523,22 @@ func (le *lessor) Leases() []*Lease {
 	return ls
 }
 
+func (le *lessor) List(sel LabelSelector, from LeaseID, limit int) ([]*Lease, bool) {
+	le.mu.RLock()
+	var ls []*Lease
+	for id, l := range le.leaseMap {
+		if id >= from && sel.Matches(l.labels) {
+			ls = append(ls, l)
+		}
+	}
+	le.mu.RUnlock()
+	sort.Slice(ls, func(i, j int) bool { return ls[i].ID < ls[j].ID })
+	if limit > 0 && len(ls) > limit {
+		return ls[:limit], true
+	}
+	return ls, false
+}
+
 func (le *lessor) Promote(extend time.Duration) {
 	le.mu.Lock()
 	defer le.mu.Unlock()
@@ -811,8 +886,22 @@ func (le *lessor) initAndRecover() {
 			expiry:       forever,
 			revokec:      make(chan struct{}),
 			remainingTTL: lpb.RemainingTTL,
+			labels:       labelsFromPB(lpb.Labels),
 		}
 	}
+	for _, lpb := range lpbs {
+		if lpb.Parent == int64(NoLease) {
+			continue
+		}
+		l, pl := le.leaseMap[LeaseID(lpb.ID)], le.leaseMap[LeaseID(lpb.Parent)]
+		if pl == nil {
+			// the children are deleted with their parent, so the parent is
+			// never missing
+			continue
+		}
+		l.parent = pl
+		pl.addChild(l)
+	}
 	le.leaseExpiredNotifier.Init()
 	heap.Init(&le.leaseCheckpointHeap)
 
@@ -829,8 +918,14 @@ func (fl *FakeLessor) SetCheckpointer(cp Checkpointer) {}
 
 func (fl *FakeLessor) Grant(id LeaseID, ttl int64) (*Lease, error) { return nil, nil }
 
+func (fl *FakeLessor) GrantWithOptions(id LeaseID, ttl int64, opts GrantOptions) (*Lease, error) {
+	return nil, nil
+}
+
 func (fl *FakeLessor) Revoke(id LeaseID) error { return nil }
 
+func (fl *FakeLessor) RevokeExpired(id LeaseID) error { return nil }
+
 func (fl *FakeLessor) Checkpoint(id LeaseID, remainingTTL int64) error { return nil }
 
 func (fl *FakeLessor) Attach(id LeaseID, items []LeaseItem) error { return nil }
@@ -848,6 +943,10 @@ func (fl *FakeLessor) Lookup(id LeaseID) *Lease { return nil }
 
 func (fl *FakeLessor) Leases() []*Lease { return nil }
 
+func (fl *FakeLessor) List(sel LabelSelector, from LeaseID, limit int) ([]*Lease, bool) {
+	return nil, false
+}
+
 func (fl *FakeLessor) ExpiredLeasesC() <-chan []*Lease { return nil }
 
 func (fl *FakeLessor) Recover(b backend.Backend, rd RangeDeleter) {}
@@ -860,3 +959,5 @@ type FakeTxnDelete struct {
 
 func (ftd *FakeTxnDelete) DeleteRange(key, end []byte) (n, rev int64) { return 0, 0 }
 func (ftd *FakeTxnDelete) End()                                       { ftd.Unlock() }
+
+func (ftd *FakeTxnDelete) SetDeleteCause(cause mvccpb.Event_DeleteCause, id LeaseID) {}
@@ -30,6 +30,7 @@ import (
 	"go.uber.org/zap/zaptest"
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
 	"go.etcd.io/etcd/api/v3/version"
 	"go.etcd.io/etcd/server/v3/storage/backend"
 	"go.etcd.io/etcd/server/v3/storage/schema"
@@ -100,7 +101,7 @@ func TestLessorGrant(t *testing.T) {
 	defer tx.Unlock()
 	lpb := schema.MustUnsafeGetLease(tx, int64(l.ID))
 	if lpb == nil {
-		t.Errorf("lpb = %d, want not nil", lpb)
+		t.Errorf("lpb = %v, want not nil", lpb)
 	}
 }
 
@@ -198,13 +199,148 @@ func TestLessorRevoke(t *testing.T) {
 	if !reflect.DeepEqual(fd.deleted, wdeleted) {
 		t.Errorf("deleted= %v, want %v", fd.deleted, wdeleted)
 	}
+	if fd.cause != mvccpb.LEASE_REVOKED || fd.lease != l.ID {
+		t.Errorf("delete cause = %v of lease %x, want %v of lease %x", fd.cause, fd.lease, mvccpb.LEASE_REVOKED, l.ID)
+	}
 
 	tx := be.BatchTx()
 	tx.Lock()
 	defer tx.Unlock()
 	lpb := schema.MustUnsafeGetLease(tx, int64(l.ID))
 	if lpb != nil {
-		t.Errorf("lpb = %d, want nil", lpb)
+		t.Errorf("lpb = %v, want nil", lpb)
+	}
+}
+
+// TestLessorRevokeChildren ensures Lessor revokes the child leases with their
+// parent.
+func TestLessorRevokeChildren(t *testing.T) {
+	lg := zap.NewNop()
+	dir, be := NewTestBackend(t)
+	defer os.RemoveAll(dir)
+	defer be.Close()
+
+	le := newLessor(lg, be, clusterLatest(), LessorConfig{MinLeaseTTL: minLeaseTTL})
+	defer le.Stop()
+	var fd *fakeDeleter
+	le.SetRangeDeleter(func() TxnDelete {
+		fd = newFakeDeleter(be)
+		return fd
+	})
+	le.Promote(0)
+
+	if _, err := le.GrantWithOptions(2, 100, GrantOptions{Parent: 1}); err != ErrLeaseNotFound {
+		t.Fatalf("err = %v, want %v", err, ErrLeaseNotFound)
+	}
+	for _, g := range []struct{ id, parent LeaseID }{{1, NoLease}, {3, 1}, {2, 1}, {4, 3}, {5, NoLease}} {
+		var err error
+		if g.parent == NoLease {
+			_, err = le.Grant(g.id, 100)
+		} else {
+			_, err = le.GrantWithOptions(g.id, 100, GrantOptions{Parent: g.parent})
+		}
+		if err != nil {
+			t.Fatalf("could not grant lease %x (%v)", g.id, err)
+		}
+		if err = le.Attach(g.id, []LeaseItem{{fmt.Sprintf("foo%d", g.id)}}); err != nil {
+			t.Fatalf("failed to attach items to the lease: %v", err)
+		}
+	}
+	if ids := le.Lookup(1).Children(); !reflect.DeepEqual(ids, []LeaseID{2, 3}) {
+		t.Errorf("children = %v, want [2 3]", ids)
+	}
+	if p := le.Lookup(4).Parent(); p != 3 {
+		t.Errorf("parent = %x, want 3", p)
+	}
+
+	// the child never remains longer than its parent
+	if _, err := le.GrantWithOptions(6, 1000, GrantOptions{Parent: 1}); err != nil {
+		t.Fatalf("could not grant lease 6 (%v)", err)
+	}
+	if pr, r := le.Lookup(1).Remaining(), le.Lookup(6).Remaining(); r > pr {
+		t.Errorf("remaining = %v, want at most %v", r, pr)
+	}
+
+	// the tree is recovered from the backend
+	nle := newLessor(lg, be, clusterLatest(), LessorConfig{MinLeaseTTL: minLeaseTTL})
+	if ids := nle.Lookup(1).Children(); !reflect.DeepEqual(ids, []LeaseID{2, 3, 6}) {
+		t.Errorf("recovered children = %v, want [2 3 6]", ids)
+	}
+	if p := nle.Lookup(4).Parent(); p != 3 {
+		t.Errorf("recovered parent = %x, want 3", p)
+	}
+	nle.Stop()
+
+	if err := le.Revoke(3); err != nil {
+		t.Fatal("failed to revoke lease:", err)
+	}
+	if ids := le.Lookup(1).Children(); !reflect.DeepEqual(ids, []LeaseID{2, 6}) {
+		t.Errorf("children = %v, want [2 6]", ids)
+	}
+	if err := le.RevokeExpired(1); err != nil {
+		t.Fatal("failed to revoke lease:", err)
+	}
+	// the parent is deleted before its children, in order
+	if wdeleted := []string{"foo1_", "foo2_"}; !reflect.DeepEqual(fd.deleted, wdeleted) {
+		t.Errorf("deleted= %v, want %v", fd.deleted, wdeleted)
+	}
+	if fd.cause != mvccpb.LEASE_EXPIRED || fd.lease != 6 {
+		t.Errorf("delete cause = %v of lease %x, want %v of lease 6", fd.cause, fd.lease, mvccpb.LEASE_EXPIRED)
+	}
+	for _, id := range []LeaseID{1, 2, 3, 4, 6} {
+		if le.Lookup(id) != nil {
+			t.Errorf("got revoked lease %x", id)
+		}
+	}
+	if le.Lookup(5) == nil {
+		t.Error("lease 5 is revoked, want not revoked")
+	}
+
+	tx := be.BatchTx()
+	tx.Lock()
+	defer tx.Unlock()
+	if lpbs := schema.MustUnsafeGetAllLeases(tx); len(lpbs) != 1 || lpbs[0].ID != 5 {
+		t.Errorf("lpbs = %v, want lease 5", lpbs)
+	}
+}
+
+// TestLessorList ensures Lessor lists the leases matching a selector in pages,
+// with their labels recovered from the backend.
+func TestLessorList(t *testing.T) {
+	lg := zap.NewNop()
+	dir, be := NewTestBackend(t)
+	defer os.RemoveAll(dir)
+	defer be.Close()
+
+	le := newLessor(lg, be, clusterLatest(), LessorConfig{MinLeaseTTL: minLeaseTTL})
+	defer le.Stop()
+	for id := LeaseID(1); id <= 5; id++ {
+		labels := map[string]string{"owner": fmt.Sprintf("worker-%d", id%2)}
+		if _, err := le.GrantWithOptions(id, 100, GrantOptions{Labels: labels}); err != nil {
+			t.Fatalf("could not grant lease %x (%v)", id, err)
+		}
+	}
+
+	nle := newLessor(lg, be, clusterLatest(), LessorConfig{MinLeaseTTL: minLeaseTTL})
+	defer nle.Stop()
+	sel, err := ParseLabelSelector([]string{"owner=worker-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []LeaseID
+	for from, more := LeaseID(0), true; more; {
+		var ls []*Lease
+		ls, more = nle.List(sel, from, 2)
+		for _, l := range ls {
+			ids = append(ids, l.ID)
+			if l.Labels()["owner"] != "worker-1" {
+				t.Errorf("labels of lease %x = %v, want owner=worker-1", l.ID, l.Labels())
+			}
+		}
+		from = ls[len(ls)-1].ID + 1
+	}
+	if !reflect.DeepEqual(ids, []LeaseID{1, 3, 5}) {
+		t.Errorf("ids = %v, want [1 3 5]", ids)
 	}
 }
 
@@ -671,17 +807,23 @@ func TestLessorCheckpointPersistenceAfterRestart(t *testing.T) {
 
 type fakeDeleter struct {
 	deleted []string
+	cause   mvccpb.Event_DeleteCause
+	lease   LeaseID
 	tx      backend.BatchTx
 }
 
 func newFakeDeleter(be backend.Backend) *fakeDeleter {
-	fd := &fakeDeleter{nil, be.BatchTx()}
+	fd := &fakeDeleter{tx: be.BatchTx()}
 	fd.tx.Lock()
 	return fd
 }
 
 func (fd *fakeDeleter) End() { fd.tx.Unlock() }
 
+func (fd *fakeDeleter) SetDeleteCause(cause mvccpb.Event_DeleteCause, id LeaseID) {
+	fd.cause, fd.lease = cause, id
+}
+
 func (fd *fakeDeleter) DeleteRange(key, end []byte) (int64, int64) {
 	fd.deleted = append(fd.deleted, string(key)+"_"+string(end))
 	return 0, 0
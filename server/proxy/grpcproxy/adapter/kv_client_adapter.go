@@ -32,6 +32,45 @@ func (s *kvs2kvc) Range(ctx context.Context, in *pb.RangeRequest, opts ...grpc.C
 	return s.kvs.Range(ctx, in)
 }
 
+func (s *kvs2kvc) RangeStream(ctx context.Context, in *pb.RangeRequest, opts ...grpc.CallOption) (pb.KV_RangeStreamClient, error) {
+	cs := newPipeStream(ctx, func(ss chanServerStream) error {
+		return s.kvs.RangeStream(in, &rs2rcServerStream{ss})
+	})
+	return &rs2rcClientStream{cs}, nil
+}
+
+// rs2rcClientStream implements KV_RangeStreamClient
+type rs2rcClientStream struct{ chanClientStream }
+
+// rs2rcServerStream implements KV_RangeStreamServer
+type rs2rcServerStream struct{ chanServerStream }
+
+func (s *rs2rcClientStream) Send(rr *pb.RangeRequest) error {
+	return s.SendMsg(rr)
+}
+func (s *rs2rcClientStream) Recv() (*pb.RangeResponse, error) {
+	var v interface{}
+	if err := s.RecvMsg(&v); err != nil {
+		return nil, err
+	}
+	return v.(*pb.RangeResponse), nil
+}
+
+func (s *rs2rcServerStream) Send(rr *pb.RangeResponse) error {
+	return s.SendMsg(rr)
+}
+func (s *rs2rcServerStream) Recv() (*pb.RangeRequest, error) {
+	var v interface{}
+	if err := s.RecvMsg(&v); err != nil {
+		return nil, err
+	}
+	return v.(*pb.RangeRequest), nil
+}
+
+func (s *kvs2kvc) History(ctx context.Context, in *pb.HistoryRequest, opts ...grpc.CallOption) (*pb.HistoryResponse, error) {
+	return s.kvs.History(ctx, in)
+}
+
 func (s *kvs2kvc) Put(ctx context.Context, in *pb.PutRequest, opts ...grpc.CallOption) (*pb.PutResponse, error) {
 	return s.kvs.Put(ctx, in)
 }
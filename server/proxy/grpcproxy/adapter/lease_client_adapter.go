@@ -53,6 +53,18 @@ func (c *ls2lc) LeaseLeases(ctx context.Context, in *pb.LeaseLeasesRequest, opts
 	return c.leaseServer.LeaseLeases(ctx, in)
 }
 
+func (c *ls2lc) LeaseAttach(ctx context.Context, in *pb.LeaseAttachRequest, opts ...grpc.CallOption) (*pb.LeaseAttachResponse, error) {
+	return c.leaseServer.LeaseAttach(ctx, in)
+}
+
+func (c *ls2lc) LeaseList(ctx context.Context, in *pb.LeaseListRequest, opts ...grpc.CallOption) (*pb.LeaseListResponse, error) {
+	return c.leaseServer.LeaseList(ctx, in)
+}
+
+func (c *ls2lc) LeaseDetach(ctx context.Context, in *pb.LeaseDetachRequest, opts ...grpc.CallOption) (*pb.LeaseDetachResponse, error) {
+	return c.leaseServer.LeaseDetach(ctx, in)
+}
+
 // ls2lcClientStream implements Lease_LeaseKeepAliveClient
 type ls2lcClientStream struct{ chanClientStream }
 
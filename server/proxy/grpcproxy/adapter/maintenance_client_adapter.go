@@ -56,6 +56,34 @@ func (s *mts2mtc) Downgrade(ctx context.Context, r *pb.DowngradeRequest, opts ..
 	return s.mts.Downgrade(ctx, r)
 }
 
+func (s *mts2mtc) QuotaSet(ctx context.Context, r *pb.QuotaSetRequest, opts ...grpc.CallOption) (*pb.QuotaSetResponse, error) {
+	return s.mts.QuotaSet(ctx, r)
+}
+
+func (s *mts2mtc) QuotaDelete(ctx context.Context, r *pb.QuotaDeleteRequest, opts ...grpc.CallOption) (*pb.QuotaDeleteResponse, error) {
+	return s.mts.QuotaDelete(ctx, r)
+}
+
+func (s *mts2mtc) QuotaList(ctx context.Context, r *pb.QuotaListRequest, opts ...grpc.CallOption) (*pb.QuotaListResponse, error) {
+	return s.mts.QuotaList(ctx, r)
+}
+
+func (s *mts2mtc) CompactionRetention(ctx context.Context, r *pb.CompactionRetentionRequest, opts ...grpc.CallOption) (*pb.CompactionRetentionResponse, error) {
+	return s.mts.CompactionRetention(ctx, r)
+}
+
+func (s *mts2mtc) CompactionHold(ctx context.Context, r *pb.CompactionHoldRequest, opts ...grpc.CallOption) (*pb.CompactionHoldResponse, error) {
+	return s.mts.CompactionHold(ctx, r)
+}
+
+func (s *mts2mtc) CompactionHoldRelease(ctx context.Context, r *pb.CompactionHoldReleaseRequest, opts ...grpc.CallOption) (*pb.CompactionHoldReleaseResponse, error) {
+	return s.mts.CompactionHoldRelease(ctx, r)
+}
+
+func (s *mts2mtc) CompactionHoldList(ctx context.Context, r *pb.CompactionHoldListRequest, opts ...grpc.CallOption) (*pb.CompactionHoldListResponse, error) {
+	return s.mts.CompactionHoldList(ctx, r)
+}
+
 func (s *mts2mtc) Snapshot(ctx context.Context, in *pb.SnapshotRequest, opts ...grpc.CallOption) (pb.Maintenance_SnapshotClient, error) {
 	cs := newPipeStream(ctx, func(ss chanServerStream) error {
 		return s.mts.Snapshot(in, &ss2scServerStream{ss})
@@ -42,6 +42,8 @@ type Cache interface {
 }
 
 // keyFunc returns the key of a request, which is used to look up its caching response in the cache.
+// The key covers every field of the request, so that filtered or projected ranges
+// never share a cached response with the plain range of the same keys.
 func keyFunc(req *pb.RangeRequest) string {
 	// TODO: use marshalTo to reduce allocation
 	b, err := req.Marshal()
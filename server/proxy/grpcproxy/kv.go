@@ -16,6 +16,8 @@ package grpcproxy
 
 import (
 	"context"
+	"io"
+	"time"
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	clientv3 "go.etcd.io/etcd/client/v3"
@@ -38,6 +40,15 @@ func NewKvProxy(c *clientv3.Client) (pb.KVServer, <-chan struct{}) {
 }
 
 func (p *kvProxy) Range(ctx context.Context, r *pb.RangeRequest) (*pb.RangeResponse, error) {
+	if r.AtTime != 0 {
+		// the revision of a time is resolved by the server, bypass the cache
+		resp, err := p.kv.Do(ctx, RangeRequestToOp(r))
+		if err != nil {
+			return nil, err
+		}
+		return (*pb.RangeResponse)(resp.Get()), nil
+	}
+
 	if r.Serializable {
 		resp, err := p.cache.Get(r)
 		switch err {
@@ -67,6 +78,44 @@ func (p *kvProxy) Range(ctx context.Context, r *pb.RangeRequest) (*pb.RangeRespo
 	return gresp, nil
 }
 
+func (p *kvProxy) RangeStream(r *pb.RangeRequest, stream pb.KV_RangeStreamServer) error {
+	rs, err := p.kv.GetStream(stream.Context(), string(r.Key), rangeRequestToOpts(r)...)
+	if err != nil {
+		return err
+	}
+	for {
+		resp, err := rs.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err = stream.Send((*pb.RangeResponse)(resp)); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *kvProxy) History(ctx context.Context, r *pb.HistoryRequest) (*pb.HistoryResponse, error) {
+	opts := []clientv3.OpOption{
+		clientv3.WithMinModRev(r.StartRevision),
+		clientv3.WithMaxModRev(r.EndRevision),
+		clientv3.WithLimit(r.Limit),
+	}
+	if len(r.RangeEnd) != 0 {
+		opts = append(opts, clientv3.WithRange(string(r.RangeEnd)))
+	}
+	if r.Serializable {
+		opts = append(opts, clientv3.WithSerializable())
+	}
+	resp, err := p.kv.History(ctx, string(r.Key), opts...)
+	if err != nil {
+		return nil, err
+	}
+	return (*pb.HistoryResponse)(resp), nil
+}
+
 func (p *kvProxy) Put(ctx context.Context, r *pb.PutRequest) (*pb.PutResponse, error) {
 	p.cache.Invalidate(r.Key, nil)
 	cacheKeys.Set(float64(p.cache.Size()))
@@ -88,6 +137,10 @@ func (p *kvProxy) txnToCache(reqs []*pb.RequestOp, resps []*pb.ResponseOp) {
 		switch tv := resps[i].Response.(type) {
 		case *pb.ResponseOp_ResponsePut:
 			p.cache.Invalidate(reqs[i].GetRequestPut().Key, nil)
+		case *pb.ResponseOp_ResponseIncr:
+			p.cache.Invalidate(reqs[i].GetRequestIncr().Key, nil)
+		case *pb.ResponseOp_ResponseAppend:
+			p.cache.Invalidate(reqs[i].GetRequestAppend().Key, nil)
 		case *pb.ResponseOp_ResponseDeleteRange:
 			rdr := reqs[i].GetRequestDeleteRange()
 			p.cache.Invalidate(rdr.Key, rdr.RangeEnd)
@@ -157,11 +210,23 @@ func requestOpToOp(union *pb.RequestOp) clientv3.Op {
 		if tv.RequestTxn != nil {
 			return TxnRequestToOp(tv.RequestTxn)
 		}
+	case *pb.RequestOp_RequestIncr:
+		if tv.RequestIncr != nil {
+			return IncrRequestToOp(tv.RequestIncr)
+		}
+	case *pb.RequestOp_RequestAppend:
+		if tv.RequestAppend != nil {
+			return AppendRequestToOp(tv.RequestAppend)
+		}
 	}
 	panic("unknown request")
 }
 
 func RangeRequestToOp(r *pb.RangeRequest) clientv3.Op {
+	return clientv3.OpGet(string(r.Key), rangeRequestToOpts(r)...)
+}
+
+func rangeRequestToOpts(r *pb.RangeRequest) []clientv3.OpOption {
 	var opts []clientv3.OpOption
 	if len(r.RangeEnd) != 0 {
 		opts = append(opts, clientv3.WithRange(string(r.RangeEnd)))
@@ -185,8 +250,49 @@ func RangeRequestToOp(r *pb.RangeRequest) clientv3.Op {
 	if r.Serializable {
 		opts = append(opts, clientv3.WithSerializable())
 	}
+	if len(r.ContinueToken) != 0 {
+		opts = append(opts, clientv3.WithContinue(r.ContinueToken))
+	}
+	if r.AtTime != 0 {
+		opts = append(opts, clientv3.WithAtTime(time.Unix(0, r.AtTime)))
+	}
+	if r.Filter != nil {
+		opts = append(opts, rangeFilterToOpts(r.Filter)...)
+	}
+	if len(r.Projection) != 0 {
+		fields := make([]clientv3.KeyValueField, len(r.Projection))
+		for i, f := range r.Projection {
+			fields[i] = clientv3.KeyValueField(f)
+		}
+		opts = append(opts, clientv3.WithProjection(fields...))
+	}
+	return opts
+}
 
-	return clientv3.OpGet(string(r.Key), opts...)
+func rangeFilterToOpts(f *pb.RangeFilter) []clientv3.OpOption {
+	var opts []clientv3.OpOption
+	if len(f.ValuePrefix) != 0 {
+		opts = append(opts, clientv3.WithValuePrefix(string(f.ValuePrefix)))
+	}
+	if len(f.ValueSuffix) != 0 {
+		opts = append(opts, clientv3.WithValueSuffix(string(f.ValueSuffix)))
+	}
+	if len(f.ValueContains) != 0 {
+		opts = append(opts, clientv3.WithValueContains(string(f.ValueContains)))
+	}
+	if f.MinValueSize != 0 || f.MaxValueSize != 0 {
+		opts = append(opts, clientv3.WithValueSize(f.MinValueSize, f.MaxValueSize))
+	}
+	switch f.Lease {
+	case pb.RangeFilter_ATTACHED:
+		opts = append(opts, clientv3.WithLeaseAttached())
+	case pb.RangeFilter_DETACHED:
+		opts = append(opts, clientv3.WithoutLease())
+	}
+	if f.MinVersion != 0 || f.MaxVersion != 0 {
+		opts = append(opts, clientv3.WithVersionRange(f.MinVersion, f.MaxVersion))
+	}
+	return opts
 }
 
 func PutRequestToOp(r *pb.PutRequest) clientv3.Op {
@@ -215,6 +321,32 @@ func DelRequestToOp(r *pb.DeleteRangeRequest) clientv3.Op {
 	return clientv3.OpDelete(string(r.Key), opts...)
 }
 
+func IncrRequestToOp(r *pb.IncrRequest) clientv3.Op {
+	var opts []clientv3.OpOption
+	if r.Bounds != nil {
+		opts = append(opts, clientv3.WithIncrBounds(r.Bounds.Min, r.Bounds.Max))
+	}
+	if r.PrevKv {
+		opts = append(opts, clientv3.WithPrevKV())
+	}
+	switch r.Op {
+	case pb.IncrRequest_MAX:
+		return clientv3.OpSetMax(string(r.Key), r.Operand, opts...)
+	case pb.IncrRequest_MIN:
+		return clientv3.OpSetMin(string(r.Key), r.Operand, opts...)
+	default:
+		return clientv3.OpIncr(string(r.Key), r.Operand, opts...)
+	}
+}
+
+func AppendRequestToOp(r *pb.AppendRequest) clientv3.Op {
+	var opts []clientv3.OpOption
+	if r.PrevKv {
+		opts = append(opts, clientv3.WithPrevKV())
+	}
+	return clientv3.OpAppend(string(r.Key), string(r.Value), opts...)
+}
+
 func TxnRequestToOp(r *pb.TxnRequest) clientv3.Op {
 	cmps := make([]clientv3.Cmp, len(r.Compare))
 	thenops := make([]clientv3.Op, len(r.Success))
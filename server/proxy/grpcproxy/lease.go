@@ -92,24 +92,27 @@ func (lp *leaseProxy) LeaseRevoke(ctx context.Context, rr *pb.LeaseRevokeRequest
 }
 
 func (lp *leaseProxy) LeaseTimeToLive(ctx context.Context, rr *pb.LeaseTimeToLiveRequest) (*pb.LeaseTimeToLiveResponse, error) {
-	var (
-		r   *clientv3.LeaseTimeToLiveResponse
-		err error
-	)
+	opts := []clientv3.LeaseOption{clientv3.WithLeaseLimit(rr.Limit), clientv3.WithLeaseContinue(rr.ContinueToken)}
 	if rr.Keys {
-		r, err = lp.lessor.TimeToLive(ctx, clientv3.LeaseID(rr.ID), clientv3.WithAttachedKeys())
-	} else {
-		r, err = lp.lessor.TimeToLive(ctx, clientv3.LeaseID(rr.ID))
+		opts = append(opts, clientv3.WithAttachedKeys())
 	}
+	r, err := lp.lessor.TimeToLive(ctx, clientv3.LeaseID(rr.ID), opts...)
 	if err != nil {
 		return nil, err
 	}
 	rp := &pb.LeaseTimeToLiveResponse{
-		Header:     r.ResponseHeader,
-		ID:         int64(r.ID),
-		TTL:        r.TTL,
-		GrantedTTL: r.GrantedTTL,
-		Keys:       r.Keys,
+		Header:        r.ResponseHeader,
+		ID:            int64(r.ID),
+		TTL:           r.TTL,
+		GrantedTTL:    r.GrantedTTL,
+		Keys:          r.Keys,
+		Parent:        int64(r.Parent),
+		Labels:        r.Labels,
+		More:          r.More,
+		ContinueToken: r.ContinueToken,
+	}
+	for _, id := range r.Children {
+		rp.Children = append(rp.Children, int64(id))
 	}
 	return rp, err
 }
@@ -130,6 +133,48 @@ func (lp *leaseProxy) LeaseLeases(ctx context.Context, rr *pb.LeaseLeasesRequest
 	return rp, err
 }
 
+func (lp *leaseProxy) LeaseAttach(ctx context.Context, ar *pb.LeaseAttachRequest) (*pb.LeaseAttachResponse, error) {
+	r, err := lp.lessor.Attach(ctx, clientv3.LeaseID(ar.ID), attachKeys(ar.Keys), attachOpts(ar.Prefix)...)
+	if err != nil {
+		return nil, err
+	}
+	lp.leader.gotLeader()
+	return (*pb.LeaseAttachResponse)(r), nil
+}
+
+func (lp *leaseProxy) LeaseList(ctx context.Context, rr *pb.LeaseListRequest) (*pb.LeaseListResponse, error) {
+	r, err := lp.lessor.List(ctx, clientv3.WithLabelSelector(rr.Selectors...), clientv3.WithLeaseLimit(rr.Limit), clientv3.WithLeaseContinue(rr.ContinueToken))
+	if err != nil {
+		return nil, err
+	}
+	lp.leader.gotLeader()
+	return (*pb.LeaseListResponse)(r), nil
+}
+
+func (lp *leaseProxy) LeaseDetach(ctx context.Context, dr *pb.LeaseDetachRequest) (*pb.LeaseDetachResponse, error) {
+	r, err := lp.lessor.Detach(ctx, attachKeys(dr.Keys), attachOpts(dr.Prefix)...)
+	if err != nil {
+		return nil, err
+	}
+	lp.leader.gotLeader()
+	return (*pb.LeaseDetachResponse)(r), nil
+}
+
+func attachKeys(keys [][]byte) []string {
+	ks := make([]string, len(keys))
+	for i := range keys {
+		ks[i] = string(keys[i])
+	}
+	return ks
+}
+
+func attachOpts(prefix bool) []clientv3.LeaseOption {
+	if prefix {
+		return []clientv3.LeaseOption{clientv3.WithPrefixKeys()}
+	}
+	return nil
+}
+
 func (lp *leaseProxy) LeaseKeepAlive(stream pb.Lease_LeaseKeepAliveServer) error {
 	lp.mu.Lock()
 	select {
@@ -85,3 +85,31 @@ func (mp *maintenanceProxy) MoveLeader(ctx context.Context, r *pb.MoveLeaderRequ
 func (mp *maintenanceProxy) Downgrade(ctx context.Context, r *pb.DowngradeRequest) (*pb.DowngradeResponse, error) {
 	return mp.maintenanceClient.Downgrade(ctx, r)
 }
+
+func (mp *maintenanceProxy) QuotaSet(ctx context.Context, r *pb.QuotaSetRequest) (*pb.QuotaSetResponse, error) {
+	return mp.maintenanceClient.QuotaSet(ctx, r)
+}
+
+func (mp *maintenanceProxy) QuotaDelete(ctx context.Context, r *pb.QuotaDeleteRequest) (*pb.QuotaDeleteResponse, error) {
+	return mp.maintenanceClient.QuotaDelete(ctx, r)
+}
+
+func (mp *maintenanceProxy) QuotaList(ctx context.Context, r *pb.QuotaListRequest) (*pb.QuotaListResponse, error) {
+	return mp.maintenanceClient.QuotaList(ctx, r)
+}
+
+func (mp *maintenanceProxy) CompactionRetention(ctx context.Context, r *pb.CompactionRetentionRequest) (*pb.CompactionRetentionResponse, error) {
+	return mp.maintenanceClient.CompactionRetention(ctx, r)
+}
+
+func (mp *maintenanceProxy) CompactionHold(ctx context.Context, r *pb.CompactionHoldRequest) (*pb.CompactionHoldResponse, error) {
+	return mp.maintenanceClient.CompactionHold(ctx, r)
+}
+
+func (mp *maintenanceProxy) CompactionHoldRelease(ctx context.Context, r *pb.CompactionHoldReleaseRequest) (*pb.CompactionHoldReleaseResponse, error) {
+	return mp.maintenanceClient.CompactionHoldRelease(ctx, r)
+}
+
+func (mp *maintenanceProxy) CompactionHoldList(ctx context.Context, r *pb.CompactionHoldListRequest) (*pb.CompactionHoldListResponse, error) {
+	return mp.maintenanceClient.CompactionHoldList(ctx, r)
+}
@@ -256,12 +256,27 @@ func (wps *watchProxyStream) recvLoop() error {
 				progress: cr.ProgressNotify,
 				prevKV:   cr.PrevKv,
 				filters:  v3rpc.FiltersFromRequest(cr),
+
+				noUnchanged: v3rpc.HasFilter(cr, pb.WatchCreateRequest_NOUNCHANGED),
+				coalesce:    cr.Coalesce,
 			}
 			if !w.wr.valid() {
 				w.post(&pb.WatchResponse{WatchId: clientv3.InvalidWatchID, Created: true, Canceled: true})
 				wps.mu.Unlock()
 				continue
 			}
+			if cr.SendInitialState {
+				// the watchers of a range share the broadcast of its events,
+				// which starts at no particular revision
+				w.post(&pb.WatchResponse{
+					WatchId:      clientv3.InvalidWatchID,
+					Created:      true,
+					Canceled:     true,
+					CancelReason: "grpcproxy: send_initial_state is not supported",
+				})
+				wps.mu.Unlock()
+				continue
+			}
 			wps.nextWatcherID++
 			w.nextrev = cr.StartRevision
 			wps.watchers[w.id] = w
@@ -15,6 +15,7 @@
 package grpcproxy
 
 import (
+	"bytes"
 	"time"
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
@@ -38,6 +39,10 @@ type watcher struct {
 	filters  []mvcc.FilterFunc
 	progress bool
 	prevKV   bool
+	// noUnchanged and coalesce are applied by the proxy since the events
+	// of the broadcast always carry their previous key-value pairs.
+	noUnchanged bool
+	coalesce    bool
 
 	// id is the id returned to the client on its watch stream.
 	id int64
@@ -88,12 +93,6 @@ func (w *watcher) send(wr clientv3.WatchResponse) {
 		if filtered {
 			continue
 		}
-
-		if !w.prevKV {
-			evCopy := *ev
-			evCopy.PrevKv = nil
-			ev = &evCopy
-		}
 		events = append(events, ev)
 	}
 
@@ -101,6 +100,25 @@ func (w *watcher) send(wr clientv3.WatchResponse) {
 		w.nextrev = lastRev + 1
 	}
 
+	if w.coalesce && len(events) > 1 {
+		events = coalesceEvents(events)
+	}
+	if w.noUnchanged || !w.prevKV {
+		sent := events[:0]
+		for _, ev := range events {
+			if w.noUnchanged && ev.Type == mvccpb.PUT && ev.PrevKv != nil && bytes.Equal(ev.PrevKv.Value, ev.Kv.Value) {
+				continue
+			}
+			if !w.prevKV {
+				evCopy := *ev
+				evCopy.PrevKv = nil
+				ev = &evCopy
+			}
+			sent = append(sent, ev)
+		}
+		events = sent
+	}
+
 	// all events are filtered out?
 	if !wr.IsProgressNotify() && !wr.Created && len(events) == 0 && wr.CompactRevision == 0 {
 		return
@@ -117,6 +135,25 @@ func (w *watcher) send(wr clientv3.WatchResponse) {
 	})
 }
 
+// coalesceEvents collapses the events on the same key into the latest one,
+// keeping the revision order of the remaining events.
+func coalesceEvents(evs []*mvccpb.Event) []*mvccpb.Event {
+	latest := make(map[string]int, len(evs))
+	for i := range evs {
+		latest[string(evs[i].Kv.Key)] = i
+	}
+	if len(latest) == len(evs) {
+		return evs
+	}
+	ne := make([]*mvccpb.Event, 0, len(latest))
+	for i := range evs {
+		if latest[string(evs[i].Kv.Key)] == i {
+			ne = append(ne, evs[i])
+		}
+	}
+	return ne
+}
+
 // post puts a watch response on the watcher's proxy stream channel
 func (w *watcher) post(wr *pb.WatchResponse) bool {
 	select {
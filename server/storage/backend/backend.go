@@ -15,11 +15,8 @@
 package backend
 
 import (
-	"fmt"
 	"hash/crc32"
 	"io"
-	"os"
-	"path/filepath"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -28,6 +25,8 @@ import (
 	"go.uber.org/zap"
 
 	bolt "go.etcd.io/bbolt"
+
+	"go.etcd.io/etcd/server/v3/storage/backend/engine"
 )
 
 var (
@@ -36,6 +35,11 @@ var (
 
 	defragLimit = 10000
 
+	// defragOnlineMaxCatchUps is the maximum number of rounds copying the writes
+	// made during an online defragmentation before blocking the writes to copy
+	// the remaining ones and swap the database file.
+	defragOnlineMaxCatchUps = 5
+
 	// initialMmapSize is the initial size of the mmapped region. Setting this larger than
 	// the potential max db size can prevent writer from blocking reader.
 	// This only works for linux.
@@ -66,6 +70,10 @@ type Backend interface {
 	// OpenReadTxN returns the number of currently open read transactions in the backend.
 	OpenReadTxN() int64
 	Defrag() error
+	// DefragOnline defragments the backend like Defrag, but copies the
+	// database in chunks without blocking reads and writes until the
+	// final swap of the database file.
+	DefragOnline() error
 	ForceCommit()
 	Close() error
 
@@ -103,9 +111,9 @@ type backend struct {
 	// mlock prevents backend database file to be swapped
 	mlock bool
 
-	mu    sync.RWMutex
-	bopts *bolt.Options
-	db    *bolt.DB
+	mu     sync.RWMutex
+	engine engine.Engine
+	db     engine.DB
 
 	batchInterval time.Duration
 	batchLimit    int
@@ -123,6 +131,10 @@ type backend struct {
 
 	hooks Hooks
 
+	// defragTracker records the writes made while an online defragmentation
+	// is copying the database. It is guarded by the batchTx lock.
+	defragTracker *defragTracker
+
 	// txPostLockInsideApplyHook is called each time right after locking the tx.
 	txPostLockInsideApplyHook func()
 
@@ -149,6 +161,10 @@ type BackendConfig struct {
 
 	// Hooks are getting executed during lifecycle of Backend's transactions.
 	Hooks Hooks
+
+	// Engine is the storage engine of the backend, bolt with the options
+	// above if nil.
+	Engine engine.Engine
 }
 
 func DefaultBackendConfig(lg *zap.Logger) BackendConfig {
@@ -171,17 +187,21 @@ func NewDefaultBackend(lg *zap.Logger, path string) Backend {
 }
 
 func newBackend(bcfg BackendConfig) *backend {
-	bopts := &bolt.Options{}
-	if boltOpenOptions != nil {
-		*bopts = *boltOpenOptions
+	eng := bcfg.Engine
+	if eng == nil {
+		bopts := &bolt.Options{}
+		if boltOpenOptions != nil {
+			*bopts = *boltOpenOptions
+		}
+		bopts.InitialMmapSize = bcfg.mmapSize()
+		bopts.FreelistType = bcfg.BackendFreelistType
+		bopts.NoSync = bcfg.UnsafeNoFsync
+		bopts.NoGrowSync = bcfg.UnsafeNoFsync
+		bopts.Mlock = bcfg.Mlock
+		eng = engine.NewBolt(bopts)
 	}
-	bopts.InitialMmapSize = bcfg.mmapSize()
-	bopts.FreelistType = bcfg.BackendFreelistType
-	bopts.NoSync = bcfg.UnsafeNoFsync
-	bopts.NoGrowSync = bcfg.UnsafeNoFsync
-	bopts.Mlock = bcfg.Mlock
 
-	db, err := bolt.Open(bcfg.Path, 0600, bopts)
+	db, err := eng.Open(bcfg.Path)
 	if err != nil {
 		bcfg.Logger.Panic("failed to open database", zap.String("path", bcfg.Path), zap.Error(err))
 	}
@@ -189,8 +209,8 @@ func newBackend(bcfg BackendConfig) *backend {
 	// In future, may want to make buffering optional for low-concurrency systems
 	// or dynamically swap between buffered/non-buffered depending on workload.
 	b := &backend{
-		bopts: bopts,
-		db:    db,
+		engine: eng,
+		db:     db,
 
 		batchInterval: bcfg.BatchInterval,
 		batchLimit:    bcfg.BatchLimit,
@@ -202,7 +222,7 @@ func newBackend(bcfg BackendConfig) *backend {
 					txBuffer:   txBuffer{make(map[BucketID]*bucketBuffer)},
 					bufVersion: 0,
 				},
-				buckets: make(map[BucketID]*bolt.Bucket),
+				buckets: make(map[BucketID]engine.Bucket),
 				txWg:    new(sync.WaitGroup),
 				txMu:    new(sync.RWMutex),
 			},
@@ -336,9 +356,13 @@ func (b *backend) Snapshot() Snapshot {
 	if err != nil {
 		b.lg.Fatal("failed to begin tx", zap.Error(err))
 	}
+	snap, err := tx.Snapshot()
+	if err != nil {
+		b.lg.Fatal("failed to snapshot tx", zap.Error(err))
+	}
 
 	stopc, donec := make(chan struct{}), make(chan struct{})
-	dbBytes := tx.Size()
+	dbBytes := snap.Size()
 	go func() {
 		defer close(donec)
 		// sendRateBytes is based on transferring snapshot data over a 1 gigabit/s connection
@@ -368,7 +392,7 @@ func (b *backend) Snapshot() Snapshot {
 		}
 	}()
 
-	return &snapshot{tx, stopc, donec}
+	return &snapshot{snap, tx, stopc, donec}
 }
 
 func (b *backend) Hash(ignores func(bucketName, keyName []byte) bool) (uint32, error) {
@@ -376,23 +400,17 @@ func (b *backend) Hash(ignores func(bucketName, keyName []byte) bool) (uint32, e
 
 	b.mu.RLock()
 	defer b.mu.RUnlock()
-	err := b.db.View(func(tx *bolt.Tx) error {
-		c := tx.Cursor()
-		for next, _ := c.First(); next != nil; next, _ = c.Next() {
-			b := tx.Bucket(next)
-			if b == nil {
-				return fmt.Errorf("cannot get hash of bucket %s", string(next))
-			}
+	err := b.db.View(func(tx engine.Tx) error {
+		return tx.ForEach(func(next []byte, b engine.Bucket) error {
 			h.Write(next)
-			b.ForEach(func(k, v []byte) error {
+			return b.ForEach(func(k, v []byte) error {
 				if ignores != nil && !ignores(next, k) {
 					h.Write(k)
 					h.Write(v)
 				}
 				return nil
 			})
-		}
-		return nil
+		})
 	})
 
 	if err != nil {
@@ -468,24 +486,7 @@ func (b *backend) defrag() error {
 
 	b.batchTx.tx = nil
 
-	// Create a temporary file to ensure we start with a clean slate.
-	// Snapshotter.cleanupSnapdir cleans up any of these that are found during startup.
-	dir := filepath.Dir(b.db.Path())
-	temp, err := os.CreateTemp(dir, "db.tmp.*")
-	if err != nil {
-		return err
-	}
-	options := bolt.Options{}
-	if boltOpenOptions != nil {
-		options = *boltOpenOptions
-	}
-	options.OpenFile = func(_ string, _ int, _ os.FileMode) (file *os.File, err error) {
-		return temp, nil
-	}
-	// Don't load tmp db into memory regardless of opening options
-	options.Mlock = false
-	tdbp := temp.Name()
-	tmpdb, err := bolt.Open(tdbp, 0600, &options)
+	tmpdb, err := b.openTmpDB()
 	if err != nil {
 		return err
 	}
@@ -505,40 +506,13 @@ func (b *backend) defrag() error {
 	// gofail: var defragBeforeCopy struct{}
 	err = defragdb(b.db, tmpdb, defragLimit)
 	if err != nil {
-		tmpdb.Close()
-		if rmErr := os.RemoveAll(tmpdb.Path()); rmErr != nil {
+		if rmErr := b.engine.RemoveTemp(tmpdb); rmErr != nil {
 			b.lg.Error("failed to remove db.tmp after defragmentation completed", zap.Error(rmErr))
 		}
 		return err
 	}
 
-	err = b.db.Close()
-	if err != nil {
-		b.lg.Fatal("failed to close database", zap.Error(err))
-	}
-	err = tmpdb.Close()
-	if err != nil {
-		b.lg.Fatal("failed to close tmp database", zap.Error(err))
-	}
-	// gofail: var defragBeforeRename struct{}
-	err = os.Rename(tdbp, dbp)
-	if err != nil {
-		b.lg.Fatal("failed to rename tmp database", zap.Error(err))
-	}
-
-	b.db, err = bolt.Open(dbp, 0600, b.bopts)
-	if err != nil {
-		b.lg.Fatal("failed to open database", zap.String("path", dbp), zap.Error(err))
-	}
-	b.batchTx.tx = b.unsafeBegin(true)
-
-	b.readTx.reset()
-	b.readTx.tx = b.unsafeBegin(false)
-
-	size := b.readTx.tx.Size()
-	db := b.readTx.tx.DB()
-	atomic.StoreInt64(&b.size, size)
-	atomic.StoreInt64(&b.sizeInUse, size-(int64(db.Stats().FreePageN)*int64(db.Info().PageSize)))
+	b.unsafeReplaceDB(tmpdb)
 
 	took := time.Since(now)
 	defragSec.Observe(took.Seconds())
@@ -560,7 +534,31 @@ func (b *backend) defrag() error {
 	return nil
 }
 
-func defragdb(odb, tmpdb *bolt.DB, limit int) error {
+// openTmpDB opens an empty database next to the backend database.
+func (b *backend) openTmpDB() (engine.DB, error) {
+	return b.engine.OpenTemp(b.db.Path())
+}
+
+// unsafeReplaceDB replaces the backend database with tmpdb and begins
+// new transactions on it. It must be called holding the batchTx, mu and readTx
+// locks, after the batchTx has been committed and stopped.
+func (b *backend) unsafeReplaceDB(tmpdb engine.DB) {
+	var err error
+	b.db, err = b.engine.Replace(b.db, tmpdb)
+	if err != nil {
+		b.lg.Fatal("failed to replace database", zap.Error(err))
+	}
+	b.batchTx.tx = b.unsafeBegin(true)
+
+	b.readTx.reset()
+	b.readTx.tx = b.unsafeBegin(false)
+
+	size := b.readTx.tx.Size()
+	atomic.StoreInt64(&b.size, size)
+	atomic.StoreInt64(&b.sizeInUse, size-b.db.Stats().FreeBytes)
+}
+
+func defragdb(odb, tmpdb engine.DB, limit int) error {
 	// open a tx on tmpdb for writes
 	tmptx, err := tmpdb.Begin(true)
 	if err != nil {
@@ -579,25 +577,18 @@ func defragdb(odb, tmpdb *bolt.DB, limit int) error {
 	}
 	defer tx.Rollback()
 
-	c := tx.Cursor()
-
 	count := 0
-	for next, _ := c.First(); next != nil; next, _ = c.Next() {
-		b := tx.Bucket(next)
-		if b == nil {
-			return fmt.Errorf("backend: cannot defrag bucket %s", string(next))
-		}
-
+	if err = tx.ForEach(func(next []byte, b engine.Bucket) error {
 		tmpb, berr := tmptx.CreateBucketIfNotExists(next)
 		if berr != nil {
 			return berr
 		}
-		tmpb.FillPercent = 0.9 // for bucket2seq write in for each
+		tmpb.SetFillPercent(0.9) // for bucket2seq write in for each
 
-		if err = b.ForEach(func(k, v []byte) error {
+		return b.ForEach(func(k, v []byte) error {
 			count++
 			if count > limit {
-				err = tmptx.Commit()
+				err := tmptx.Commit()
 				if err != nil {
 					return err
 				}
@@ -606,35 +597,34 @@ func defragdb(odb, tmpdb *bolt.DB, limit int) error {
 					return err
 				}
 				tmpb = tmptx.Bucket(next)
-				tmpb.FillPercent = 0.9 // for bucket2seq write in for each
+				tmpb.SetFillPercent(0.9) // for bucket2seq write in for each
 
 				count = 0
 			}
 			return tmpb.Put(k, v)
-		}); err != nil {
-			return err
-		}
+		})
+	}); err != nil {
+		return err
 	}
 
 	return tmptx.Commit()
 }
 
-func (b *backend) begin(write bool) *bolt.Tx {
+func (b *backend) begin(write bool) engine.Tx {
 	b.mu.RLock()
 	tx := b.unsafeBegin(write)
+	stats := b.db.Stats()
 	b.mu.RUnlock()
 
 	size := tx.Size()
-	db := tx.DB()
-	stats := db.Stats()
 	atomic.StoreInt64(&b.size, size)
-	atomic.StoreInt64(&b.sizeInUse, size-(int64(stats.FreePageN)*int64(db.Info().PageSize)))
+	atomic.StoreInt64(&b.sizeInUse, size-stats.FreeBytes)
 	atomic.StoreInt64(&b.openReadTxN, int64(stats.OpenTxN))
 
 	return tx
 }
 
-func (b *backend) unsafeBegin(write bool) *bolt.Tx {
+func (b *backend) unsafeBegin(write bool) engine.Tx {
 	// gofail: var beforeStartDBTxn struct{}
 	tx, err := b.db.Begin(write)
 	// gofail: var afterStartDBTxn struct{}
@@ -649,7 +639,8 @@ func (b *backend) OpenReadTxN() int64 {
 }
 
 type snapshot struct {
-	*bolt.Tx
+	engine.Snapshot
+	tx    engine.Tx
 	stopc chan struct{}
 	donec chan struct{}
 }
@@ -657,5 +648,9 @@ type snapshot struct {
 func (s *snapshot) Close() error {
 	close(s.stopc)
 	<-s.donec
-	return s.Tx.Rollback()
+	if err := s.Snapshot.Close(); err != nil {
+		s.tx.Rollback()
+		return err
+	}
+	return s.tx.Rollback()
 }
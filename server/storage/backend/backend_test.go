@@ -15,6 +15,7 @@
 package backend_test
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"reflect"
@@ -24,6 +25,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	bolt "go.etcd.io/bbolt"
 	"go.etcd.io/etcd/server/v3/storage/backend"
+	"go.etcd.io/etcd/server/v3/storage/backend/engine"
 	betesting "go.etcd.io/etcd/server/v3/storage/backend/testing"
 	"go.etcd.io/etcd/server/v3/storage/schema"
 	"go.uber.org/zap/zaptest"
@@ -193,7 +195,153 @@ func TestBackendDefrag(t *testing.T) {
 	b.ForceCommit()
 }
 
+// TestBackendMemoryEngine ensures the in-memory engine writes nothing to disk
+// and stores the same data as bolt.
+func TestBackendMemoryEngine(t *testing.T) {
+	bcfg := backend.DefaultBackendConfig(zaptest.NewLogger(t))
+	bcfg.Engine = engine.NewMemory()
+	mb, path := betesting.NewTmpBackendFromCfg(t, bcfg)
+	defer betesting.Close(t, mb)
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, b)
+
+	for _, be := range []backend.Backend{mb, b} {
+		tx := be.BatchTx()
+		tx.Lock()
+		tx.UnsafeCreateBucket(schema.Test)
+		for i := 0; i < 100; i++ {
+			tx.UnsafePut(schema.Test, []byte(fmt.Sprintf("foo_%d", i)), []byte("bar"))
+		}
+		tx.Unlock()
+		be.ForceCommit()
+		tx.Lock()
+		for i := 0; i < 50; i++ {
+			tx.UnsafeDelete(schema.Test, []byte(fmt.Sprintf("foo_%d", i)))
+		}
+		tx.Unlock()
+		be.ForceCommit()
+	}
+	_, err := os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "err = %v, want not exist", err)
+
+	rtx := mb.ConcurrentReadTx()
+	rtx.RLock()
+	n := 0
+	assert.NoError(t, rtx.UnsafeForEach(schema.Test, func(k, v []byte) error {
+		n++
+		return nil
+	}))
+	rtx.RUnlock()
+	assert.Equal(t, 50, n)
+
+	h, err := b.Hash(nil)
+	assert.NoError(t, err)
+	for _, defrag := range []func() error{mb.Defrag, mb.DefragOnline} {
+		assert.NoError(t, defrag())
+		mh, err := mb.Hash(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, h, mh)
+	}
+
+	// the snapshot is a bolt file
+	f, err := os.CreateTemp(t.TempDir(), "etcd_backend_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	snap := mb.Snapshot()
+	_, err = snap.WriteTo(f)
+	assert.NoError(t, err)
+	assert.NoError(t, snap.Close())
+	assert.NoError(t, f.Close())
+	nb := backend.NewDefaultBackend(zaptest.NewLogger(t), f.Name())
+	defer betesting.Close(t, nb)
+	nh, err := nb.Hash(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, h, nh)
+}
+
 // TestBackendWriteback ensures writes are stored to the read txn on write txn unlock.
+func TestBackendDefragOnline(t *testing.T) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, b)
+
+	n := backend.DefragLimitForTest() + 100
+	tx := b.BatchTx()
+	tx.Lock()
+	tx.UnsafeCreateBucket(schema.Test)
+	for i := 0; i < n; i++ {
+		tx.UnsafePut(schema.Test, []byte(fmt.Sprintf("foo_%d", i)), []byte("bar"))
+	}
+	tx.Unlock()
+	b.ForceCommit()
+
+	tx = b.BatchTx()
+	tx.Lock()
+	for i := 0; i < 50; i++ {
+		tx.UnsafeDelete(schema.Test, []byte(fmt.Sprintf("foo_%d", i)))
+	}
+	tx.Unlock()
+	b.ForceCommit()
+
+	// keep writing while defragmenting
+	stopc, donec := make(chan struct{}), make(chan int)
+	go func() {
+		i := 0
+		defer func() { donec <- i }()
+		for ; ; i++ {
+			select {
+			case <-stopc:
+				return
+			default:
+			}
+			tx := b.BatchTx()
+			tx.Lock()
+			tx.UnsafePut(schema.Test, []byte(fmt.Sprintf("baz_%d", i)), []byte("bar"))
+			tx.UnsafeDelete(schema.Test, []byte(fmt.Sprintf("foo_%d", 50+i%(n-50))))
+			if i == 10 {
+				tx.UnsafeCreateBucket(schema.Lease)
+				tx.UnsafePut(schema.Lease, []byte("lease"), []byte("bar"))
+			}
+			tx.Unlock()
+			if i%100 == 0 {
+				b.ForceCommit()
+			}
+		}
+	}()
+	err := b.DefragOnline()
+	close(stopc)
+	written := <-donec
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.ForceCommit()
+
+	deleted := written
+	if deleted > n-50 {
+		deleted = n - 50
+	}
+	foo, baz := 0, 0
+	rtx := b.ReadTx()
+	rtx.RLock()
+	err = rtx.UnsafeForEach(schema.Test, func(k, v []byte) error {
+		switch {
+		case bytes.HasPrefix(k, []byte("foo_")):
+			foo++
+		case bytes.HasPrefix(k, []byte("baz_")):
+			baz++
+		}
+		return nil
+	})
+	leases, _ := rtx.UnsafeRange(schema.Lease, []byte("lease"), nil, 0)
+	rtx.RUnlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, n-50-deleted, foo)
+	assert.Equal(t, written, baz)
+	assert.Equal(t, written > 10, len(leases) == 1)
+}
+
 func TestBackendWriteback(t *testing.T) {
 	b, _ := betesting.NewDefaultTmpBackend(t)
 	defer betesting.Close(t, b)
@@ -23,7 +23,7 @@ import (
 
 	"go.uber.org/zap"
 
-	bolt "go.etcd.io/bbolt"
+	"go.etcd.io/etcd/server/v3/storage/backend/engine"
 )
 
 type BucketID int
@@ -60,7 +60,7 @@ type BatchTx interface {
 
 type batchTx struct {
 	sync.Mutex
-	tx      *bolt.Tx
+	tx      engine.Tx
 	backend *backend
 
 	pending int
@@ -115,7 +115,7 @@ func (t *batchTx) RUnlock() {
 
 func (t *batchTx) UnsafeCreateBucket(bucket Bucket) {
 	_, err := t.tx.CreateBucket(bucket.Name())
-	if err != nil && err != bolt.ErrBucketExists {
+	if err != nil && err != engine.ErrBucketExists {
 		t.backend.lg.Fatal(
 			"failed to create a bucket",
 			zap.Stringer("bucket-name", bucket),
@@ -123,11 +123,14 @@ func (t *batchTx) UnsafeCreateBucket(bucket Bucket) {
 		)
 	}
 	t.pending++
+	if t.backend.defragTracker != nil {
+		t.backend.defragTracker.markBucket(bucket.Name())
+	}
 }
 
 func (t *batchTx) UnsafeDeleteBucket(bucket Bucket) {
 	err := t.tx.DeleteBucket(bucket.Name())
-	if err != nil && err != bolt.ErrBucketNotFound {
+	if err != nil && err != engine.ErrBucketNotFound {
 		t.backend.lg.Fatal(
 			"failed to delete a bucket",
 			zap.Stringer("bucket-name", bucket),
@@ -135,6 +138,9 @@ func (t *batchTx) UnsafeDeleteBucket(bucket Bucket) {
 		)
 	}
 	t.pending++
+	if t.backend.defragTracker != nil {
+		t.backend.defragTracker.markBucket(bucket.Name())
+	}
 }
 
 // UnsafePut must be called holding the lock on the tx.
@@ -159,7 +165,7 @@ func (t *batchTx) unsafePut(bucketType Bucket, key []byte, value []byte, seq boo
 	if seq {
 		// it is useful to increase fill percent when the workloads are mostly append-only.
 		// this can delay the page split and reduce space usage.
-		bucket.FillPercent = 0.9
+		bucket.SetFillPercent(0.9)
 	}
 	if err := bucket.Put(key, value); err != nil {
 		t.backend.lg.Fatal(
@@ -169,6 +175,9 @@ func (t *batchTx) unsafePut(bucketType Bucket, key []byte, value []byte, seq boo
 		)
 	}
 	t.pending++
+	if t.backend.defragTracker != nil {
+		t.backend.defragTracker.markKey(bucketType.Name(), key)
+	}
 }
 
 // UnsafeRange must be called holding the lock on the tx.
@@ -184,7 +193,7 @@ func (t *batchTx) UnsafeRange(bucketType Bucket, key, endKey []byte, limit int64
 	return unsafeRange(bucket.Cursor(), key, endKey, limit)
 }
 
-func unsafeRange(c *bolt.Cursor, key, endKey []byte, limit int64) (keys [][]byte, vs [][]byte) {
+func unsafeRange(c engine.Cursor, key, endKey []byte, limit int64) (keys [][]byte, vs [][]byte) {
 	if limit <= 0 {
 		limit = math.MaxInt64
 	}
@@ -225,6 +234,9 @@ func (t *batchTx) UnsafeDelete(bucketType Bucket, key []byte) {
 		)
 	}
 	t.pending++
+	if t.backend.defragTracker != nil {
+		t.backend.defragTracker.markKey(bucketType.Name(), key)
+	}
 }
 
 // UnsafeForEach must be called holding the lock on the tx.
@@ -232,7 +244,7 @@ func (t *batchTx) UnsafeForEach(bucket Bucket, visitor func(k, v []byte) error)
 	return unsafeForEach(t.tx, bucket, visitor)
 }
 
-func unsafeForEach(tx *bolt.Tx, bucket Bucket, visitor func(k, v []byte) error) error {
+func unsafeForEach(tx engine.Tx, bucket Bucket, visitor func(k, v []byte) error) error {
 	if b := tx.Bucket(bucket.Name()); b != nil {
 		return b.ForEach(visitor)
 	}
@@ -272,9 +284,10 @@ func (t *batchTx) commit(stop bool) {
 		err := t.tx.Commit()
 		// gofail: var afterCommit struct{}
 
-		rebalanceSec.Observe(t.tx.Stats().RebalanceTime.Seconds())
-		spillSec.Observe(t.tx.Stats().SpillTime.Seconds())
-		writeSec.Observe(t.tx.Stats().WriteTime.Seconds())
+		stats := t.tx.Stats()
+		rebalanceSec.Observe(stats.RebalanceTime.Seconds())
+		spillSec.Observe(stats.SpillTime.Seconds())
+		writeSec.Observe(stats.WriteTime.Seconds())
 		commitSec.Observe(time.Since(start).Seconds())
 		atomic.AddInt64(&t.backend.commits, 1)
 
@@ -282,6 +295,10 @@ func (t *batchTx) commit(stop bool) {
 		if err != nil {
 			t.backend.lg.Fatal("failed to commit tx", zap.Error(err))
 		}
+		if t.backend.defragTracker != nil {
+			// the writes are only visible to the defragmentation copy once committed
+			t.backend.defragTracker.commit()
+		}
 	}
 	if !stop {
 		t.tx = t.backend.begin(true)
@@ -348,7 +365,7 @@ func (t *batchTxBuffered) unsafeCommit(stop bool) {
 	if t.backend.readTx.tx != nil {
 		// wait all store read transactions using the current boltdb tx to finish,
 		// then close the boltdb tx
-		go func(tx *bolt.Tx, wg *sync.WaitGroup) {
+		go func(tx engine.Tx, wg *sync.WaitGroup) {
 			wg.Wait()
 			if err := tx.Rollback(); err != nil {
 				t.backend.lg.Fatal("failed to rollback tx", zap.Error(err))
@@ -0,0 +1,346 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/server/v3/storage/backend/engine"
+)
+
+var ErrDefragInProgress = errors.New("backend: online defragmentation is already in progress")
+
+// dirtySet is a set of buckets and keys written to the backend.
+type dirtySet struct {
+	// buckets are the buckets created or deleted, which are copied as a whole.
+	buckets map[string]struct{}
+	keys    map[string]map[string]struct{}
+}
+
+func newDirtySet() dirtySet {
+	return dirtySet{
+		buckets: make(map[string]struct{}),
+		keys:    make(map[string]map[string]struct{}),
+	}
+}
+
+func (s dirtySet) len() int {
+	n := len(s.buckets)
+	for _, keys := range s.keys {
+		n += len(keys)
+	}
+	return n
+}
+
+func (s dirtySet) addKey(bucket, key string) {
+	keys, ok := s.keys[bucket]
+	if !ok {
+		keys = make(map[string]struct{})
+		s.keys[bucket] = keys
+	}
+	keys[key] = struct{}{}
+}
+
+func (s dirtySet) merge(o dirtySet) {
+	for bucket := range o.buckets {
+		s.buckets[bucket] = struct{}{}
+	}
+	for bucket, keys := range o.keys {
+		for key := range keys {
+			s.addKey(bucket, key)
+		}
+	}
+}
+
+// defragTracker records the writes made to the backend while an online
+// defragmentation copies the database. The writes are marked before the
+// commit of the batch transaction and only handed to the copy once they are
+// committed, so that the copy always reads them from the database.
+type defragTracker struct {
+	// pending holds the writes of the ongoing batch transaction.
+	// It is guarded by the batchTx lock.
+	pending dirtySet
+
+	mu sync.Mutex
+	// committed holds the committed writes not yet copied.
+	committed dirtySet
+}
+
+func newDefragTracker() *defragTracker {
+	return &defragTracker{pending: newDirtySet(), committed: newDirtySet()}
+}
+
+func (t *defragTracker) markKey(bucket, key []byte) {
+	t.pending.addKey(string(bucket), string(key))
+}
+
+func (t *defragTracker) markBucket(bucket []byte) {
+	t.pending.buckets[string(bucket)] = struct{}{}
+}
+
+// commit hands the writes of the committed batch transaction to the copy.
+func (t *defragTracker) commit() {
+	if t.pending.len() == 0 {
+		return
+	}
+	t.mu.Lock()
+	t.committed.merge(t.pending)
+	t.mu.Unlock()
+	t.pending = newDirtySet()
+}
+
+// drain returns the committed writes not yet copied.
+func (t *defragTracker) drain() dirtySet {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := t.committed
+	t.committed = newDirtySet()
+	return s
+}
+
+func (b *backend) DefragOnline() error {
+	return b.defragOnline()
+}
+
+func (b *backend) defragOnline() error {
+	now := time.Now()
+	isDefragActive.Set(1)
+	defer isDefragActive.Set(0)
+
+	// commit the ongoing writes before tracking the next ones, so that every
+	// write is either read by the copy or recorded by the tracker.
+	b.batchTx.LockOutsideApply()
+	if b.defragTracker != nil {
+		b.batchTx.Unlock()
+		return ErrDefragInProgress
+	}
+	b.batchTx.commit(false)
+	tracker := newDefragTracker()
+	b.defragTracker = tracker
+	b.batchTx.Unlock()
+
+	b.mu.RLock()
+	dbp := b.db.Path()
+	tmpdb, err := b.openTmpDB()
+	b.mu.RUnlock()
+	if err != nil {
+		b.stopDefragTracking()
+		return err
+	}
+	abort := func(err error) error {
+		if rmErr := b.engine.RemoveTemp(tmpdb); rmErr != nil {
+			b.lg.Error("failed to remove db.tmp after defragmentation failed", zap.Error(rmErr))
+		}
+		return err
+	}
+
+	size1, sizeInUse1 := b.Size(), b.SizeInUse()
+	if b.lg != nil {
+		b.lg.Info(
+			"defragmenting online",
+			zap.String("path", dbp),
+			zap.Int64("current-db-size-bytes", size1),
+			zap.String("current-db-size", humanize.Bytes(uint64(size1))),
+			zap.Int64("current-db-size-in-use-bytes", sizeInUse1),
+			zap.String("current-db-size-in-use", humanize.Bytes(uint64(sizeInUse1))),
+		)
+	}
+	// gofail: var defragOnlineBeforeCopy struct{}
+	if err = b.defragCopyChunks(tmpdb, defragLimit); err != nil {
+		b.stopDefragTracking()
+		return abort(err)
+	}
+	// catch up with the writes made during the copy while they are still
+	// many, so that the writes are blocked only for a short time at the end.
+	for i := 0; i < defragOnlineMaxCatchUps; i++ {
+		dirty := tracker.drain()
+		b.mu.RLock()
+		err = defragCopyDirty(b.db, tmpdb, dirty)
+		b.mu.RUnlock()
+		if err != nil {
+			b.stopDefragTracking()
+			return abort(err)
+		}
+		if dirty.len() <= defragLimit {
+			break
+		}
+	}
+
+	b.batchTx.LockOutsideApply()
+	defer b.batchTx.Unlock()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.readTx.Lock()
+	defer b.readTx.Unlock()
+
+	b.batchTx.unsafeCommit(true)
+	b.defragTracker = nil
+	// gofail: var defragOnlineBeforeSwap struct{}
+	if err = defragCopyDirty(b.db, tmpdb, tracker.drain()); err != nil {
+		// keep serving from the current database
+		b.batchTx.tx = b.unsafeBegin(true)
+		b.readTx.tx = b.unsafeBegin(false)
+		return abort(err)
+	}
+	b.unsafeReplaceDB(tmpdb)
+
+	took := time.Since(now)
+	defragSec.Observe(took.Seconds())
+
+	size2, sizeInUse2 := b.Size(), b.SizeInUse()
+	if b.lg != nil {
+		b.lg.Info(
+			"finished defragmenting online",
+			zap.String("path", dbp),
+			zap.Int64("current-db-size-bytes-diff", size2-size1),
+			zap.Int64("current-db-size-bytes", size2),
+			zap.String("current-db-size", humanize.Bytes(uint64(size2))),
+			zap.Int64("current-db-size-in-use-bytes-diff", sizeInUse2-sizeInUse1),
+			zap.Int64("current-db-size-in-use-bytes", sizeInUse2),
+			zap.String("current-db-size-in-use", humanize.Bytes(uint64(sizeInUse2))),
+			zap.Duration("took", took),
+		)
+	}
+	return nil
+}
+
+func (b *backend) stopDefragTracking() {
+	b.batchTx.LockOutsideApply()
+	b.defragTracker = nil
+	b.batchTx.Unlock()
+}
+
+// defragCopyChunks copies the buckets of the backend to tmpdb, at most limit
+// keys in each read transaction so that the writes are not blocked by the copy.
+func (b *backend) defragCopyChunks(tmpdb engine.DB, limit int) error {
+	var names [][]byte
+	err := b.defragView(func(tx engine.Tx) error {
+		return tx.ForEach(func(name []byte, _ engine.Bucket) error {
+			names = append(names, append([]byte(nil), name...))
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		// from is the first key of the next chunk, nil for the first chunk.
+		var from []byte
+		for done := false; !done; {
+			err = b.defragView(func(tx engine.Tx) error {
+				src := tx.Bucket(name)
+				if src == nil {
+					// deleted by a write, which is tracked
+					done = true
+					return nil
+				}
+				return tmpdb.Update(func(tmptx engine.Tx) error {
+					dst, err := tmptx.CreateBucketIfNotExists(name)
+					if err != nil {
+						return err
+					}
+					dst.SetFillPercent(0.9) // for bucket2seq write in for each
+
+					c := src.Cursor()
+					k, v := c.First()
+					if from != nil {
+						k, v = c.Seek(from)
+					}
+					for count := 0; k != nil; k, v = c.Next() {
+						if count == limit {
+							from = append([]byte(nil), k...)
+							return nil
+						}
+						if err = dst.Put(k, v); err != nil {
+							return err
+						}
+						count++
+					}
+					done = true
+					return nil
+				})
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (b *backend) defragView(f func(tx engine.Tx) error) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.db.View(f)
+}
+
+// defragCopyDirty copies the given buckets and keys from odb to tmpdb,
+// deleting the ones that no longer exist in odb.
+func defragCopyDirty(odb, tmpdb engine.DB, dirty dirtySet) error {
+	if dirty.len() == 0 {
+		return nil
+	}
+	return odb.View(func(tx engine.Tx) error {
+		return tmpdb.Update(func(tmptx engine.Tx) error {
+			for name := range dirty.buckets {
+				if err := tmptx.DeleteBucket([]byte(name)); err != nil && err != engine.ErrBucketNotFound {
+					return err
+				}
+				src := tx.Bucket([]byte(name))
+				if src == nil {
+					continue
+				}
+				dst, err := tmptx.CreateBucket([]byte(name))
+				if err != nil {
+					return err
+				}
+				dst.SetFillPercent(0.9)
+				if err = src.ForEach(dst.Put); err != nil {
+					return err
+				}
+			}
+			for name, keys := range dirty.keys {
+				if _, ok := dirty.buckets[name]; ok {
+					continue
+				}
+				src := tx.Bucket([]byte(name))
+				if src == nil {
+					continue
+				}
+				dst, err := tmptx.CreateBucketIfNotExists([]byte(name))
+				if err != nil {
+					return err
+				}
+				for key := range keys {
+					if v := src.Get([]byte(key)); v != nil {
+						err = dst.Put([]byte(key), v)
+					} else {
+						err = dst.Delete([]byte(key))
+					}
+					if err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		})
+	})
+}
@@ -0,0 +1,210 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+type boltEngine struct {
+	opts *bolt.Options
+}
+
+// NewBolt returns the engine storing the databases in bbolt files opened
+// with the given options.
+func NewBolt(opts *bolt.Options) Engine {
+	return &boltEngine{opts: opts}
+}
+
+func (e *boltEngine) Open(path string) (DB, error) {
+	db, err := bolt.Open(path, 0600, e.opts)
+	if err != nil {
+		return nil, err
+	}
+	return &boltDB{db}, nil
+}
+
+func (e *boltEngine) OpenTemp(path string) (DB, error) {
+	// Create a temporary file to ensure we start with a clean slate.
+	// Snapshotter.cleanupSnapdir cleans up any of these that are found during startup.
+	temp, err := os.CreateTemp(filepath.Dir(path), "db.tmp.*")
+	if err != nil {
+		return nil, err
+	}
+	options := bolt.Options{}
+	if e.opts != nil {
+		options = *e.opts
+	}
+	options.OpenFile = func(_ string, _ int, _ os.FileMode) (file *os.File, err error) {
+		return temp, nil
+	}
+	// Don't load tmp db into memory regardless of opening options
+	options.Mlock = false
+	db, err := bolt.Open(temp.Name(), 0600, &options)
+	if err != nil {
+		return nil, err
+	}
+	return &boltDB{db}, nil
+}
+
+func (e *boltEngine) Replace(db, tmp DB) (DB, error) {
+	dbp, tdbp := db.Path(), tmp.Path()
+	if err := db.Close(); err != nil {
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+	// gofail: var defragBeforeRename struct{}
+	if err := os.Rename(tdbp, dbp); err != nil {
+		return nil, err
+	}
+	return e.Open(dbp)
+}
+
+func (e *boltEngine) RemoveTemp(tmp DB) error {
+	tmp.Close()
+	return os.RemoveAll(tmp.Path())
+}
+
+type boltDB struct {
+	*bolt.DB
+}
+
+// BoltDB returns the bbolt database of a database of the bolt engine, or
+// nil if the database is of another engine.
+func BoltDB(db DB) *bolt.DB {
+	if bdb, ok := db.(*boltDB); ok {
+		return bdb.DB
+	}
+	return nil
+}
+
+func (db *boltDB) Begin(writable bool) (Tx, error) {
+	tx, err := db.DB.Begin(writable)
+	if err != nil {
+		return nil, err
+	}
+	return &boltTx{tx}, nil
+}
+
+func (db *boltDB) View(fn func(Tx) error) error {
+	return db.DB.View(func(tx *bolt.Tx) error { return fn(&boltTx{tx}) })
+}
+
+func (db *boltDB) Update(fn func(Tx) error) error {
+	return db.DB.Update(func(tx *bolt.Tx) error { return fn(&boltTx{tx}) })
+}
+
+func (db *boltDB) Stats() Stats {
+	stats := db.DB.Stats()
+	return Stats{
+		FreeBytes: int64(stats.FreePageN) * int64(db.DB.Info().PageSize),
+		OpenTxN:   stats.OpenTxN,
+	}
+}
+
+type boltTx struct {
+	tx *bolt.Tx
+}
+
+func (tx *boltTx) Size() int64 { return tx.tx.Size() }
+
+func (tx *boltTx) Bucket(name []byte) Bucket {
+	if b := tx.tx.Bucket(name); b != nil {
+		return &boltBucket{b}
+	}
+	return nil
+}
+
+func (tx *boltTx) CreateBucket(name []byte) (Bucket, error) {
+	b, err := tx.tx.CreateBucket(name)
+	if err != nil {
+		return nil, boltError(err)
+	}
+	return &boltBucket{b}, nil
+}
+
+func (tx *boltTx) CreateBucketIfNotExists(name []byte) (Bucket, error) {
+	b, err := tx.tx.CreateBucketIfNotExists(name)
+	if err != nil {
+		return nil, boltError(err)
+	}
+	return &boltBucket{b}, nil
+}
+
+func (tx *boltTx) DeleteBucket(name []byte) error {
+	return boltError(tx.tx.DeleteBucket(name))
+}
+
+func (tx *boltTx) ForEach(fn func(name []byte, b Bucket) error) error {
+	return tx.tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+		return fn(name, &boltBucket{b})
+	})
+}
+
+func (tx *boltTx) Snapshot() (Snapshot, error) { return boltSnapshot{tx.tx}, nil }
+
+func (tx *boltTx) Commit() error   { return boltError(tx.tx.Commit()) }
+func (tx *boltTx) Rollback() error { return boltError(tx.tx.Rollback()) }
+
+func (tx *boltTx) Stats() TxStats {
+	stats := tx.tx.Stats()
+	return TxStats{
+		RebalanceTime: stats.RebalanceTime,
+		SpillTime:     stats.SpillTime,
+		WriteTime:     stats.WriteTime,
+	}
+}
+
+func boltError(err error) error {
+	switch {
+	case errors.Is(err, bolt.ErrBucketExists):
+		return ErrBucketExists
+	case errors.Is(err, bolt.ErrBucketNotFound):
+		return ErrBucketNotFound
+	case errors.Is(err, bolt.ErrTxClosed):
+		return ErrTxClosed
+	case errors.Is(err, bolt.ErrTxNotWritable):
+		return ErrTxNotWritable
+	}
+	return err
+}
+
+type boltBucket struct {
+	b *bolt.Bucket
+}
+
+func (b *boltBucket) Get(key []byte) []byte                    { return b.b.Get(key) }
+func (b *boltBucket) Put(key, value []byte) error              { return boltError(b.b.Put(key, value)) }
+func (b *boltBucket) Delete(key []byte) error                  { return boltError(b.b.Delete(key)) }
+func (b *boltBucket) Cursor() Cursor                           { return b.b.Cursor() }
+func (b *boltBucket) ForEach(fn func(k, v []byte) error) error { return b.b.ForEach(fn) }
+func (b *boltBucket) SetFillPercent(p float64)                 { b.b.FillPercent = p }
+
+// boltSnapshot writes the database as of the transaction, which is rolled
+// back by its owner.
+type boltSnapshot struct {
+	tx *bolt.Tx
+}
+
+func (s boltSnapshot) Size() int64                              { return s.tx.Size() }
+func (s boltSnapshot) WriteTo(w io.Writer) (n int64, err error) { return s.tx.WriteTo(w) }
+func (s boltSnapshot) Close() error                             { return nil }
@@ -0,0 +1,149 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package engine defines the storage engines the backend stores its buckets
+// in: bbolt, the default, and an in-memory engine for ephemeral clusters.
+//
+// An engine provides the semantics of bbolt: sorted buckets of keys,
+// a single writable transaction at a time and read-only transactions reading
+// the database as of their beginning, concurrently with the writes.
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+var (
+	ErrBucketExists   = errors.New("engine: bucket already exists")
+	ErrBucketNotFound = errors.New("engine: bucket not found")
+	ErrTxClosed       = errors.New("engine: tx closed")
+	ErrTxNotWritable  = errors.New("engine: tx not writable")
+)
+
+// Type is the name of an engine.
+type Type string
+
+const (
+	TypeBolt   Type = "bolt"
+	TypeMemory Type = "memory"
+)
+
+// ParseType returns the engine type of the given name; the empty name is
+// the default bolt engine.
+func ParseType(s string) (Type, error) {
+	switch t := Type(s); t {
+	case "", TypeBolt:
+		return TypeBolt, nil
+	case TypeMemory:
+		return t, nil
+	default:
+		return "", fmt.Errorf("unknown backend engine %q (expected %q or %q)", s, TypeBolt, TypeMemory)
+	}
+}
+
+// Engine opens the databases of the backend.
+type Engine interface {
+	// Open opens the database at path, creating it if it does not exist.
+	Open(path string) (DB, error)
+	// OpenTemp opens an empty temporary database next to the database at
+	// path, which the defragmentation copies the database to.
+	OpenTemp(path string) (DB, error)
+	// Replace closes db and tmp, replaces db with tmp and returns the
+	// database reopened.
+	Replace(db, tmp DB) (DB, error)
+	// RemoveTemp closes and removes a temporary database.
+	RemoveTemp(tmp DB) error
+}
+
+// DB is a database of buckets.
+type DB interface {
+	Path() string
+	// Begin starts a transaction. Only one writable transaction can be
+	// open at a time; Begin(true) blocks until the previous one is closed.
+	Begin(writable bool) (Tx, error)
+	// View runs fn in a read-only transaction.
+	View(fn func(Tx) error) error
+	// Update runs fn in a writable transaction, committed if fn succeeds.
+	Update(fn func(Tx) error) error
+	Stats() Stats
+	Close() error
+}
+
+// Stats are the statistics of a database.
+type Stats struct {
+	// FreeBytes is the number of bytes allocated but not in use.
+	FreeBytes int64
+	// OpenTxN is the number of open read transactions.
+	OpenTxN int
+}
+
+// Tx is a transaction on a database.
+type Tx interface {
+	// Size returns the size in bytes of the database as of the transaction.
+	Size() int64
+	// Bucket returns the bucket of the given name, or nil if it does not exist.
+	Bucket(name []byte) Bucket
+	CreateBucket(name []byte) (Bucket, error)
+	CreateBucketIfNotExists(name []byte) (Bucket, error)
+	DeleteBucket(name []byte) error
+	// ForEach calls fn with the name of each bucket, in order.
+	ForEach(fn func(name []byte, b Bucket) error) error
+	// Snapshot returns the database as of the transaction in the bbolt file
+	// format, which the snapshots sent to the members and the clients are in.
+	// It must be closed before the transaction.
+	Snapshot() (Snapshot, error)
+	Commit() error
+	Rollback() error
+	// Stats returns the statistics of the commit of the transaction.
+	Stats() TxStats
+}
+
+// TxStats are the statistics of the commit of a transaction.
+type TxStats struct {
+	RebalanceTime time.Duration
+	SpillTime     time.Duration
+	WriteTime     time.Duration
+}
+
+// Bucket is a sorted collection of keys. The keys and values it returns are
+// only valid for the life of the transaction.
+type Bucket interface {
+	Get(key []byte) []byte
+	Put(key, value []byte) error
+	Delete(key []byte) error
+	Cursor() Cursor
+	ForEach(fn func(k, v []byte) error) error
+	// SetFillPercent sets how full the pages of the bucket are filled
+	// before they are split, a hint for the buckets mostly appended to.
+	SetFillPercent(p float64)
+}
+
+// Cursor iterates the keys of a bucket in order. The methods return a nil
+// key once the iteration is done.
+type Cursor interface {
+	First() (key, value []byte)
+	// Seek moves to the first key greater than or equal to seek.
+	Seek(seek []byte) (key, value []byte)
+	Next() (key, value []byte)
+}
+
+// Snapshot is a copy of a database in the bbolt file format.
+type Snapshot interface {
+	Size() int64
+	WriteTo(w io.Writer) (n int64, err error)
+	Close() error
+}
@@ -0,0 +1,558 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/google/btree"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	errDatabaseClosed = errors.New("engine: database closed")
+	errKeyRequired    = errors.New("engine: key required")
+)
+
+const (
+	memoryBTreeDegree = 32
+	// memorySnapshotBatchLimit is the number of keys written to the bbolt
+	// file of a snapshot per transaction.
+	memorySnapshotBatchLimit = 10000
+)
+
+type memoryEngine struct{}
+
+// NewMemory returns the engine keeping the databases in memory. Nothing is
+// written to disk but the snapshots, so that the data is lost once the
+// database is closed.
+//
+// Open loads the bbolt file at the path if any, so that the snapshots
+// received from the leader and restored by etcdutl are read, then removes
+// it so that no stale copy of the database is left on disk.
+func NewMemory() Engine {
+	return memoryEngine{}
+}
+
+func (memoryEngine) Open(path string) (DB, error) {
+	db := newMemoryDB(path)
+	info, err := os.Stat(path)
+	if err != nil {
+		return db, nil
+	}
+	if info.Size() > 0 {
+		if err = db.load(path); err != nil {
+			return nil, err
+		}
+	}
+	if err = os.Remove(path); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+func (memoryEngine) OpenTemp(path string) (DB, error) {
+	return newMemoryDB(path), nil
+}
+
+func (memoryEngine) Replace(db, tmp DB) (DB, error) {
+	if err := db.Close(); err != nil {
+		return nil, err
+	}
+	return tmp, nil
+}
+
+func (memoryEngine) RemoveTemp(tmp DB) error {
+	return tmp.Close()
+}
+
+type memoryItem struct {
+	key, value []byte
+}
+
+func lessMemoryItem(a, b memoryItem) bool {
+	return bytes.Compare(a.key, b.key) < 0
+}
+
+type memoryTree = btree.BTreeG[memoryItem]
+
+// memoryState is a version of the database. A committed state is never
+// modified: the writable transaction clones the trees of the buckets it
+// writes to, which share their nodes until they are written.
+type memoryState struct {
+	buckets map[string]*memoryTree
+	size    int64
+}
+
+type memoryDB struct {
+	path string
+	// writer is held by the writable transaction.
+	writer sync.Mutex
+
+	mu sync.RWMutex
+	// state is the last committed state, nil once the database is closed.
+	state *memoryState
+
+	openTxN int64
+}
+
+func newMemoryDB(path string) *memoryDB {
+	return &memoryDB{
+		path:  path,
+		state: &memoryState{buckets: make(map[string]*memoryTree)},
+	}
+}
+
+// load reads the buckets of the bbolt file at path.
+func (db *memoryDB) load(path string) error {
+	bdb, err := bolt.Open(path, 0400, &bolt.Options{ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer bdb.Close()
+	return bdb.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+			t := btree.NewG(memoryBTreeDegree, lessMemoryItem)
+			db.state.size += int64(len(name))
+			err := b.ForEach(func(k, v []byte) error {
+				// skip the nested buckets, not used by the backend
+				if v != nil {
+					t.ReplaceOrInsert(memoryItem{key: cloneBytes(k), value: cloneBytes(v)})
+					db.state.size += int64(len(k) + len(v))
+				}
+				return nil
+			})
+			db.state.buckets[string(name)] = t
+			return err
+		})
+	})
+}
+
+func (db *memoryDB) Path() string { return db.path }
+
+func (db *memoryDB) Begin(writable bool) (Tx, error) {
+	if writable {
+		db.writer.Lock()
+	}
+	db.mu.RLock()
+	st := db.state
+	db.mu.RUnlock()
+	if st == nil {
+		if writable {
+			db.writer.Unlock()
+		}
+		return nil, errDatabaseClosed
+	}
+	tx := &memoryTx{db: db, writable: writable, state: st}
+	if writable {
+		buckets := make(map[string]*memoryTree, len(st.buckets))
+		for name, t := range st.buckets {
+			buckets[name] = t
+		}
+		tx.state = &memoryState{buckets: buckets, size: st.size}
+		tx.owned = make(map[string]bool)
+	} else {
+		atomic.AddInt64(&db.openTxN, 1)
+	}
+	return tx, nil
+}
+
+func (db *memoryDB) View(fn func(Tx) error) error {
+	tx, err := db.Begin(false)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	return fn(tx)
+}
+
+func (db *memoryDB) Update(fn func(Tx) error) error {
+	tx, err := db.Begin(true)
+	if err != nil {
+		return err
+	}
+	if err = fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (db *memoryDB) Stats() Stats {
+	return Stats{OpenTxN: int(atomic.LoadInt64(&db.openTxN))}
+}
+
+func (db *memoryDB) Close() error {
+	// wait for the writable transaction
+	db.writer.Lock()
+	defer db.writer.Unlock()
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.state = nil
+	return nil
+}
+
+type memoryTx struct {
+	db       *memoryDB
+	writable bool
+	state    *memoryState
+	// owned are the buckets the writable transaction cloned or created,
+	// which it can write to.
+	owned  map[string]bool
+	closed bool
+}
+
+func (tx *memoryTx) Size() int64 { return tx.state.size }
+
+func (tx *memoryTx) Bucket(name []byte) Bucket {
+	if _, ok := tx.state.buckets[string(name)]; !ok {
+		return nil
+	}
+	return &memoryBucket{tx: tx, name: string(name)}
+}
+
+func (tx *memoryTx) CreateBucket(name []byte) (Bucket, error) {
+	if err := tx.checkWritable(); err != nil {
+		return nil, err
+	}
+	if _, ok := tx.state.buckets[string(name)]; ok {
+		return nil, ErrBucketExists
+	}
+	tx.state.buckets[string(name)] = btree.NewG(memoryBTreeDegree, lessMemoryItem)
+	tx.state.size += int64(len(name))
+	tx.owned[string(name)] = true
+	return &memoryBucket{tx: tx, name: string(name)}, nil
+}
+
+func (tx *memoryTx) CreateBucketIfNotExists(name []byte) (Bucket, error) {
+	if b := tx.Bucket(name); b != nil {
+		return b, nil
+	}
+	return tx.CreateBucket(name)
+}
+
+func (tx *memoryTx) DeleteBucket(name []byte) error {
+	if err := tx.checkWritable(); err != nil {
+		return err
+	}
+	t, ok := tx.state.buckets[string(name)]
+	if !ok {
+		return ErrBucketNotFound
+	}
+	tx.state.size -= int64(len(name))
+	t.Ascend(func(item memoryItem) bool {
+		tx.state.size -= int64(len(item.key) + len(item.value))
+		return true
+	})
+	delete(tx.state.buckets, string(name))
+	delete(tx.owned, string(name))
+	return nil
+}
+
+func (tx *memoryTx) ForEach(fn func(name []byte, b Bucket) error) error {
+	for _, name := range tx.bucketNames() {
+		if err := fn([]byte(name), &memoryBucket{tx: tx, name: name}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (tx *memoryTx) bucketNames() []string {
+	names := make([]string, 0, len(tx.state.buckets))
+	for name := range tx.state.buckets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Snapshot writes the buckets to a temporary bbolt file next to the
+// database, removed once the snapshot is closed.
+func (tx *memoryTx) Snapshot() (Snapshot, error) {
+	f, err := os.CreateTemp(filepath.Dir(tx.db.path), "db.tmp.*")
+	if err != nil {
+		return nil, err
+	}
+	f.Close()
+	s := &memorySnapshot{path: f.Name()}
+	if err = tx.writeBolt(s.path); err != nil {
+		s.Close()
+		return nil, err
+	}
+	info, err := os.Stat(s.path)
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+	s.size = info.Size()
+	return s, nil
+}
+
+func (tx *memoryTx) writeBolt(path string) error {
+	bdb, err := bolt.Open(path, 0600, &bolt.Options{NoSync: true})
+	if err != nil {
+		return err
+	}
+	defer bdb.Close()
+	btx, err := bdb.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			btx.Rollback()
+		}
+	}()
+	count := 0
+	for _, name := range tx.bucketNames() {
+		var b *bolt.Bucket
+		if b, err = btx.CreateBucket([]byte(name)); err != nil {
+			return err
+		}
+		b.FillPercent = 1.0
+		tx.state.buckets[name].Ascend(func(item memoryItem) bool {
+			if count == memorySnapshotBatchLimit {
+				if err = btx.Commit(); err != nil {
+					return false
+				}
+				if btx, err = bdb.Begin(true); err != nil {
+					return false
+				}
+				b = btx.Bucket([]byte(name))
+				b.FillPercent = 1.0
+				count = 0
+			}
+			err = b.Put(item.key, item.value)
+			count++
+			return err == nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return btx.Commit()
+}
+
+func (tx *memoryTx) Commit() error {
+	if tx.closed {
+		return ErrTxClosed
+	}
+	if !tx.writable {
+		return ErrTxNotWritable
+	}
+	tx.closed = true
+	tx.db.mu.Lock()
+	if tx.db.state != nil {
+		tx.db.state = tx.state
+	}
+	tx.db.mu.Unlock()
+	tx.db.writer.Unlock()
+	return nil
+}
+
+func (tx *memoryTx) Rollback() error {
+	if tx.closed {
+		return ErrTxClosed
+	}
+	tx.closed = true
+	if tx.writable {
+		tx.db.writer.Unlock()
+	} else {
+		atomic.AddInt64(&tx.db.openTxN, -1)
+	}
+	return nil
+}
+
+func (tx *memoryTx) Stats() TxStats { return TxStats{} }
+
+func (tx *memoryTx) checkWritable() error {
+	if tx.closed {
+		return ErrTxClosed
+	}
+	if !tx.writable {
+		return ErrTxNotWritable
+	}
+	return nil
+}
+
+// writableTree returns the tree of the bucket, cloned the first time it is
+// written by the transaction.
+func (tx *memoryTx) writableTree(name string) (*memoryTree, error) {
+	if err := tx.checkWritable(); err != nil {
+		return nil, err
+	}
+	t, ok := tx.state.buckets[name]
+	if !ok {
+		return nil, ErrBucketNotFound
+	}
+	if !tx.owned[name] {
+		t = t.Clone()
+		tx.state.buckets[name] = t
+		tx.owned[name] = true
+	}
+	return t, nil
+}
+
+// memoryBucket looks its tree up on each call, so that it reads the writes
+// made through the other handles of the bucket.
+type memoryBucket struct {
+	tx   *memoryTx
+	name string
+}
+
+func (b *memoryBucket) tree() *memoryTree { return b.tx.state.buckets[b.name] }
+
+func (b *memoryBucket) Get(key []byte) []byte {
+	t := b.tree()
+	if t == nil {
+		return nil
+	}
+	item, ok := t.Get(memoryItem{key: key})
+	if !ok {
+		return nil
+	}
+	return item.value
+}
+
+func (b *memoryBucket) Put(key, value []byte) error {
+	if len(key) == 0 {
+		return errKeyRequired
+	}
+	t, err := b.tx.writableTree(b.name)
+	if err != nil {
+		return err
+	}
+	// the caller may reuse the slices once Put returns
+	item := memoryItem{key: cloneBytes(key), value: cloneBytes(value)}
+	if old, ok := t.ReplaceOrInsert(item); ok {
+		b.tx.state.size -= int64(len(old.key) + len(old.value))
+	}
+	b.tx.state.size += int64(len(item.key) + len(item.value))
+	return nil
+}
+
+func (b *memoryBucket) Delete(key []byte) error {
+	t, err := b.tx.writableTree(b.name)
+	if err != nil {
+		return err
+	}
+	if old, ok := t.Delete(memoryItem{key: key}); ok {
+		b.tx.state.size -= int64(len(old.key) + len(old.value))
+	}
+	return nil
+}
+
+func (b *memoryBucket) Cursor() Cursor { return &memoryCursor{b: b} }
+
+func (b *memoryBucket) ForEach(fn func(k, v []byte) error) error {
+	t := b.tree()
+	if t == nil {
+		return nil
+	}
+	var err error
+	t.Ascend(func(item memoryItem) bool {
+		err = fn(item.key, item.value)
+		return err == nil
+	})
+	return err
+}
+
+func (b *memoryBucket) SetFillPercent(float64) {}
+
+type memoryCursor struct {
+	b *memoryBucket
+	// key is the current key, nil once the iteration is done.
+	key []byte
+}
+
+func (c *memoryCursor) First() ([]byte, []byte) {
+	t := c.b.tree()
+	if t == nil {
+		return c.set(memoryItem{}, false)
+	}
+	return c.set(t.Min())
+}
+
+func (c *memoryCursor) Seek(seek []byte) ([]byte, []byte) {
+	return c.set(c.ceil(seek, true))
+}
+
+func (c *memoryCursor) Next() ([]byte, []byte) {
+	if c.key == nil {
+		return nil, nil
+	}
+	return c.set(c.ceil(c.key, false))
+}
+
+// ceil returns the first item greater than key, or equal if inclusive.
+func (c *memoryCursor) ceil(key []byte, inclusive bool) (found memoryItem, ok bool) {
+	t := c.b.tree()
+	if t == nil {
+		return found, false
+	}
+	t.AscendGreaterOrEqual(memoryItem{key: key}, func(item memoryItem) bool {
+		if !inclusive && bytes.Equal(item.key, key) {
+			return true
+		}
+		found, ok = item, true
+		return false
+	})
+	return found, ok
+}
+
+func (c *memoryCursor) set(item memoryItem, ok bool) ([]byte, []byte) {
+	if !ok {
+		c.key = nil
+		return nil, nil
+	}
+	c.key = item.key
+	return item.key, item.value
+}
+
+// cloneBytes copies b, keeping the empty values apart from the missing ones.
+func cloneBytes(b []byte) []byte {
+	c := make([]byte, len(b))
+	copy(c, b)
+	return c
+}
+
+type memorySnapshot struct {
+	path string
+	size int64
+}
+
+func (s *memorySnapshot) Size() int64 { return s.size }
+
+func (s *memorySnapshot) WriteTo(w io.Writer) (int64, error) {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return io.Copy(w, f)
+}
+
+func (s *memorySnapshot) Close() error {
+	return os.Remove(s.path)
+}
@@ -0,0 +1,177 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testBucket = []byte("test")
+
+func mustPut(t *testing.T, db DB, kvs ...string) {
+	require.NoError(t, db.Update(func(tx Tx) error {
+		b, err := tx.CreateBucketIfNotExists(testBucket)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < len(kvs); i += 2 {
+			if err := b.Put([]byte(kvs[i]), []byte(kvs[i+1])); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+}
+
+func readAll(t *testing.T, tx Tx) map[string]string {
+	kvs := make(map[string]string)
+	b := tx.Bucket(testBucket)
+	if b == nil {
+		return kvs
+	}
+	require.NoError(t, b.ForEach(func(k, v []byte) error {
+		kvs[string(k)] = string(v)
+		return nil
+	}))
+	return kvs
+}
+
+func TestMemoryIsolation(t *testing.T) {
+	db, err := NewMemory().Open(filepath.Join(t.TempDir(), "db"))
+	require.NoError(t, err)
+	defer db.Close()
+	mustPut(t, db, "a", "1")
+
+	rtx, err := db.Begin(false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, db.Stats().OpenTxN)
+	wtx, err := db.Begin(true)
+	require.NoError(t, err)
+	require.NoError(t, wtx.Bucket(testBucket).Put([]byte("a"), []byte("2")))
+	require.NoError(t, wtx.Bucket(testBucket).Put([]byte("b"), []byte("3")))
+	assert.Equal(t, map[string]string{"a": "2", "b": "3"}, readAll(t, wtx))
+	assert.Equal(t, map[string]string{"a": "1"}, readAll(t, rtx))
+
+	require.NoError(t, wtx.Commit())
+	assert.Equal(t, map[string]string{"a": "1"}, readAll(t, rtx))
+	assert.ErrorIs(t, rtx.Bucket(testBucket).Put([]byte("c"), nil), ErrTxNotWritable)
+	require.NoError(t, rtx.Rollback())
+	assert.Equal(t, 0, db.Stats().OpenTxN)
+
+	require.NoError(t, db.View(func(tx Tx) error {
+		assert.Equal(t, map[string]string{"a": "2", "b": "3"}, readAll(t, tx))
+		return nil
+	}))
+}
+
+func TestMemoryRollback(t *testing.T) {
+	db, err := NewMemory().Open(filepath.Join(t.TempDir(), "db"))
+	require.NoError(t, err)
+	defer db.Close()
+	mustPut(t, db, "a", "1")
+
+	tx, err := db.Begin(true)
+	require.NoError(t, err)
+	require.NoError(t, tx.Bucket(testBucket).Delete([]byte("a")))
+	_, err = tx.CreateBucket([]byte("other"))
+	require.NoError(t, err)
+	_, err = tx.CreateBucket([]byte("other"))
+	assert.ErrorIs(t, err, ErrBucketExists)
+	require.NoError(t, tx.Rollback())
+	assert.ErrorIs(t, tx.Commit(), ErrTxClosed)
+
+	require.NoError(t, db.View(func(tx Tx) error {
+		assert.Nil(t, tx.Bucket([]byte("other")))
+		assert.Equal(t, map[string]string{"a": "1"}, readAll(t, tx))
+		return nil
+	}))
+}
+
+func TestMemoryCursor(t *testing.T) {
+	db, err := NewMemory().Open(filepath.Join(t.TempDir(), "db"))
+	require.NoError(t, err)
+	defer db.Close()
+	mustPut(t, db, "b", "2", "d", "4", "f", "")
+
+	require.NoError(t, db.View(func(tx Tx) error {
+		b := tx.Bucket(testBucket)
+		v := b.Get([]byte("f"))
+		assert.NotNil(t, v, "an empty value is not a missing key")
+		assert.Len(t, v, 0)
+		assert.Nil(t, b.Get([]byte("c")))
+
+		c := b.Cursor()
+		var keys []string
+		for k, _ := c.First(); k != nil; k, _ = c.Next() {
+			keys = append(keys, string(k))
+		}
+		assert.Equal(t, []string{"b", "d", "f"}, keys)
+
+		k, v := c.Seek([]byte("c"))
+		assert.Equal(t, "d", string(k))
+		assert.Equal(t, "4", string(v))
+		k, _ = c.Next()
+		assert.Equal(t, "f", string(k))
+		k, _ = c.Next()
+		assert.Nil(t, k)
+		k, _ = c.Seek([]byte("g"))
+		assert.Nil(t, k)
+		return nil
+	}))
+}
+
+func TestMemorySnapshot(t *testing.T) {
+	dir := t.TempDir()
+	db, err := NewMemory().Open(filepath.Join(dir, "db"))
+	require.NoError(t, err)
+	defer db.Close()
+	mustPut(t, db, "a", "1", "b", "2")
+
+	tx, err := db.Begin(false)
+	require.NoError(t, err)
+	s, err := tx.Snapshot()
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "snapshot")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	n, err := s.WriteTo(f)
+	require.NoError(t, err)
+	assert.Equal(t, s.Size(), n)
+	require.NoError(t, f.Close())
+	require.NoError(t, s.Close())
+	require.NoError(t, tx.Rollback())
+
+	// nothing is left next to the database
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	for _, e := range []Engine{NewBolt(nil), NewMemory()} {
+		sdb, err := e.Open(path)
+		require.NoError(t, err)
+		require.NoError(t, sdb.View(func(tx Tx) error {
+			assert.Equal(t, map[string]string{"a": "1", "b": "2"}, readAll(t, tx))
+			return nil
+		}))
+		require.NoError(t, sdb.Close())
+	}
+	// the memory engine does not leave the loaded file on disk
+	assert.NoFileExists(t, path)
+}
@@ -14,10 +14,14 @@
 
 package backend
 
-import bolt "go.etcd.io/bbolt"
+import (
+	bolt "go.etcd.io/bbolt"
+
+	"go.etcd.io/etcd/server/v3/storage/backend/engine"
+)
 
 func DbFromBackendForTest(b Backend) *bolt.DB {
-	return b.(*backend).db
+	return engine.BoltDB(b.(*backend).db)
 }
 
 func DefragLimitForTest() int {
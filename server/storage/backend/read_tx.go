@@ -18,7 +18,7 @@ import (
 	"math"
 	"sync"
 
-	bolt "go.etcd.io/bbolt"
+	"go.etcd.io/etcd/server/v3/storage/backend/engine"
 )
 
 // IsSafeRangeBucket is a hack to avoid inadvertently reading duplicate keys;
@@ -44,8 +44,8 @@ type baseReadTx struct {
 	// TODO: group and encapsulate {txMu, tx, buckets, txWg}, as they share the same lifecycle.
 	// txMu protects accesses to buckets and tx on Range requests.
 	txMu    *sync.RWMutex
-	tx      *bolt.Tx
-	buckets map[BucketID]*bolt.Bucket
+	tx      engine.Tx
+	buckets map[BucketID]engine.Bucket
 	// txWg protects tx from being rolled back at the end of a batch interval until all reads using this tx are done.
 	txWg *sync.WaitGroup
 }
@@ -131,7 +131,7 @@ func (rt *readTx) RUnlock() { rt.mu.RUnlock() }
 
 func (rt *readTx) reset() {
 	rt.buf.reset()
-	rt.buckets = make(map[BucketID]*bolt.Bucket)
+	rt.buckets = make(map[BucketID]engine.Bucket)
 	rt.tx = nil
 	rt.txWg = new(sync.WaitGroup)
 }
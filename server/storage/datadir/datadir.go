@@ -20,6 +20,7 @@ const (
 	memberDirSegment   = "member"
 	snapDirSegment     = "snap"
 	walDirSegment      = "wal"
+	cdcDirSegment      = "cdc"
 	backendFileSegment = "db"
 )
 
@@ -35,6 +36,11 @@ func ToWalDir(dataDir string) string {
 	return filepath.Join(ToMemberDir(dataDir), walDirSegment)
 }
 
+// ToCDCDir returns the directory of the change data capture cursors.
+func ToCDCDir(dataDir string) string {
+	return filepath.Join(ToMemberDir(dataDir), cdcDirSegment)
+}
+
 func ToMemberDir(dataDir string) string {
 	return filepath.Join(dataDir, memberDirSegment)
 }
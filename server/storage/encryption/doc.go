@@ -0,0 +1,25 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package encryption implements the envelope encryption of the data etcd
+// stores on disk: the revisions of the backend, the entries of the WAL and
+// the snap files.
+//
+// The data is encrypted with AES-GCM by data encryption keys generated by
+// etcd, which are themselves encrypted by the key encryption keys of a KMS
+// and stored along the data. The local KMS keeps its keys in a key file;
+// rotating its current key makes the data written afterward encrypted with
+// the new key, while the data written before stays readable as long as the
+// old keys are kept.
+package encryption
@@ -0,0 +1,189 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	ErrCorrupted     = errors.New("encryption: encrypted data is corrupted")
+	ErrNotConfigured = errors.New("encryption: data is encrypted but no encryption key is configured")
+)
+
+// Cipher encrypts the data written to disk and decrypts the data read.
+type Cipher interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// magic prefixes the encrypted data. It starts with 0x00, which is not a
+// valid protobuf field tag, so that the encrypted data is told apart from
+// the protobuf messages written before the encryption is enabled.
+var magic = []byte{0x00, 'e', 'n', 'c'}
+
+const envelopeVersion = 1
+
+// maxDEKUses is the number of encryptions after which a new data encryption
+// key is generated, far below the 2^32 limit of AES-GCM with random nonces.
+const maxDEKUses = 1 << 28
+
+// IsEncrypted reports whether the data is encrypted by an Envelope.
+func IsEncrypted(b []byte) bool {
+	return bytes.HasPrefix(b, magic)
+}
+
+// Envelope encrypts the data with a data encryption key encrypted by the
+// current key encryption key of the KMS. The encrypted data embeds the
+// encrypted data encryption key, so that it is decrypted as long as the KMS
+// keeps the key encryption key, whatever the current one is.
+type Envelope struct {
+	kms KMS
+
+	mu      sync.Mutex
+	current *dataKey
+	// deks caches the decrypted data encryption keys by their encrypted
+	// form, prefixed by the ID of their key encryption key.
+	deks map[string]cipher.AEAD
+}
+
+type dataKey struct {
+	aead cipher.AEAD
+	// header prefixes the data encrypted by the key: magic, the envelope
+	// version, and the key encryption key ID and encrypted key, each
+	// prefixed by its length.
+	header []byte
+	uses   int
+}
+
+func NewEnvelope(kms KMS) *Envelope {
+	return &Envelope{kms: kms, deks: make(map[string]cipher.AEAD)}
+}
+
+func (e *Envelope) Encrypt(plaintext []byte) ([]byte, error) {
+	dk, err := e.dataKey()
+	if err != nil {
+		return nil, err
+	}
+	ns := dk.aead.NonceSize()
+	b := make([]byte, len(dk.header)+ns, len(dk.header)+ns+len(plaintext)+dk.aead.Overhead())
+	copy(b, dk.header)
+	nonce := b[len(dk.header):]
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return dk.aead.Seal(b, nonce, plaintext, nil), nil
+}
+
+func (e *Envelope) Decrypt(ciphertext []byte) ([]byte, error) {
+	if !IsEncrypted(ciphertext) {
+		return nil, ErrCorrupted
+	}
+	b := ciphertext[len(magic):]
+	if len(b) == 0 || b[0] != envelopeVersion {
+		return nil, fmt.Errorf("%w: unknown envelope version", ErrCorrupted)
+	}
+	b = b[1:]
+	keyID, b, err := readBytes(b)
+	if err != nil {
+		return nil, err
+	}
+	edek, b, err := readBytes(b)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := e.decryptDataKey(string(keyID), edek)
+	if err != nil {
+		return nil, err
+	}
+	return open(aead, b)
+}
+
+// KeyID returns the ID of the key encryption key of the encrypted data.
+func KeyID(ciphertext []byte) (string, error) {
+	if !IsEncrypted(ciphertext) || len(ciphertext) == len(magic) {
+		return "", ErrCorrupted
+	}
+	keyID, _, err := readBytes(ciphertext[len(magic)+1:])
+	return string(keyID), err
+}
+
+// dataKey returns the current data encryption key, generating one the first
+// time and once it is used too many times.
+func (e *Envelope) dataKey() (*dataKey, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.current != nil && e.current.uses < maxDEKUses {
+		e.current.uses++
+		return e.current, nil
+	}
+	dek := make([]byte, KeySize)
+	if _, err := rand.Read(dek); err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(dek)
+	if err != nil {
+		return nil, err
+	}
+	keyID, edek, err := e.kms.Encrypt(dek)
+	if err != nil {
+		return nil, err
+	}
+	header := append([]byte(nil), magic...)
+	header = append(header, envelopeVersion)
+	header = binary.AppendUvarint(header, uint64(len(keyID)))
+	header = append(header, keyID...)
+	header = binary.AppendUvarint(header, uint64(len(edek)))
+	header = append(header, edek...)
+	e.current = &dataKey{aead: aead, header: header, uses: 1}
+	e.deks[keyID+"\x00"+string(edek)] = aead
+	return e.current, nil
+}
+
+func (e *Envelope) decryptDataKey(keyID string, edek []byte) (cipher.AEAD, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	k := keyID + "\x00" + string(edek)
+	if aead, ok := e.deks[k]; ok {
+		return aead, nil
+	}
+	dek, err := e.kms.Decrypt(keyID, edek)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(dek)
+	if err != nil {
+		return nil, err
+	}
+	e.deks[k] = aead
+	return aead, nil
+}
+
+// readBytes reads a slice prefixed by its length.
+func readBytes(b []byte) (v, rest []byte, err error) {
+	n, l := binary.Uvarint(b)
+	if l <= 0 || uint64(len(b)-l) < n {
+		return nil, nil, ErrCorrupted
+	}
+	return b[l : l+int(n)], b[l+int(n):], nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvelopeKeyRotation(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "keys")
+	_, err := NewLocalKMS(keyFile)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	id, err := RotateKeyFile(keyFile)
+	require.NoError(t, err)
+	assert.Equal(t, "key-1", id)
+	kms1, err := NewLocalKMS(keyFile)
+	require.NoError(t, err)
+	e1 := NewEnvelope(kms1)
+
+	plaintext := []byte("secret")
+	c1, err := e1.Encrypt(plaintext)
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(c1))
+	assert.NotContains(t, string(c1), string(plaintext))
+	keyID, err := KeyID(c1)
+	require.NoError(t, err)
+	assert.Equal(t, "key-1", keyID)
+
+	// the data encrypted before the rotation is still decrypted
+	id, err = RotateKeyFile(keyFile)
+	require.NoError(t, err)
+	assert.Equal(t, "key-2", id)
+	kms2, err := NewLocalKMS(keyFile)
+	require.NoError(t, err)
+	assert.Equal(t, "key-2", kms2.CurrentKeyID())
+	e2 := NewEnvelope(kms2)
+	c2, err := e2.Encrypt(plaintext)
+	require.NoError(t, err)
+	keyID, err = KeyID(c2)
+	require.NoError(t, err)
+	assert.Equal(t, "key-2", keyID)
+	for _, c := range [][]byte{c1, c2} {
+		d, err := e2.Decrypt(c)
+		require.NoError(t, err)
+		assert.Equal(t, plaintext, d)
+	}
+
+	// the data encrypted after the rotation needs the new key
+	_, err = e1.Decrypt(c2)
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	c2[len(c2)-1]++
+	_, err = e2.Decrypt(c2)
+	assert.ErrorIs(t, err, ErrCorrupted)
+	_, err = e2.Decrypt(c2[:len(magic)+2])
+	assert.ErrorIs(t, err, ErrCorrupted)
+}
+
+func TestNewLocalKMSInvalidKeyFile(t *testing.T) {
+	tests := []struct {
+		name string
+		keys string
+		werr error
+	}{
+		{name: "empty", keys: "# no key\n", werr: ErrNoKey},
+		{name: "no id", keys: "AAAA\n"},
+		{name: "short key", keys: "k:AAAA\n"},
+		{name: "duplicate", keys: "k:AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=\nk:AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyFile := filepath.Join(t.TempDir(), "keys")
+			require.NoError(t, os.WriteFile(keyFile, []byte(tt.keys), 0600))
+			_, err := NewLocalKMS(keyFile)
+			require.Error(t, err)
+			if tt.werr != nil {
+				assert.ErrorIs(t, err, tt.werr)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// KeySize is the size in bytes of the AES-256 keys.
+const KeySize = 32
+
+var (
+	ErrKeyNotFound = errors.New("encryption: key encryption key not found")
+	ErrNoKey       = errors.New("encryption: no key in the key file")
+)
+
+// KMS encrypts the data encryption keys with key encryption keys it keeps.
+type KMS interface {
+	// Encrypt encrypts the data encryption key with the current key
+	// encryption key, and returns the ID of the key encryption key.
+	Encrypt(dek []byte) (keyID string, edek []byte, err error)
+	// Decrypt decrypts the data encryption key encrypted by the key
+	// encryption key of the given ID.
+	Decrypt(keyID string, edek []byte) ([]byte, error)
+}
+
+// LocalKMS is a KMS keeping its key encryption keys in a local key file.
+// Each line of the key file is a key, written as "<id>:<base64 key>"; the
+// last key is the current one, and the other keys only decrypt the data
+// encryption keys encrypted before the last rotation.
+type LocalKMS struct {
+	keys    map[string]cipher.AEAD
+	current string
+}
+
+// NewLocalKMS loads the keys of the key file.
+func NewLocalKMS(keyFile string) (*LocalKMS, error) {
+	b, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	k := &LocalKMS{keys: make(map[string]cipher.AEAD)}
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		id, key, err := parseKey(line)
+		if err != nil {
+			return nil, fmt.Errorf("encryption: invalid key at line %d of %q: %w", n, keyFile, err)
+		}
+		if _, ok := k.keys[id]; ok {
+			return nil, fmt.Errorf("encryption: duplicate key %q in %q", id, keyFile)
+		}
+		if k.keys[id], err = newAEAD(key); err != nil {
+			return nil, err
+		}
+		k.current = id
+	}
+	if err = s.Err(); err != nil {
+		return nil, err
+	}
+	if k.current == "" {
+		return nil, ErrNoKey
+	}
+	return k, nil
+}
+
+// CurrentKeyID returns the ID of the key encrypting the data encryption keys.
+func (k *LocalKMS) CurrentKeyID() string { return k.current }
+
+func (k *LocalKMS) Encrypt(dek []byte) (string, []byte, error) {
+	edek, err := seal(k.keys[k.current], dek)
+	return k.current, edek, err
+}
+
+func (k *LocalKMS) Decrypt(keyID string, edek []byte) ([]byte, error) {
+	aead, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrKeyNotFound, keyID)
+	}
+	return open(aead, edek)
+}
+
+// RotateKeyFile appends a new random key to the key file, which becomes its
+// current key, and returns the ID of the key. The key file is created if it
+// does not exist.
+func RotateKeyFile(keyFile string) (string, error) {
+	var ids map[string]bool
+	if k, err := NewLocalKMS(keyFile); err == nil {
+		ids = make(map[string]bool, len(k.keys))
+		for id := range k.keys {
+			ids[id] = true
+		}
+	} else if !errors.Is(err, os.ErrNotExist) && !errors.Is(err, ErrNoKey) {
+		return "", err
+	}
+	id := fmt.Sprintf("key-%d", len(ids)+1)
+	for n := len(ids) + 2; ids[id]; n++ {
+		id = fmt.Sprintf("key-%d", n)
+	}
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+
+	f, err := os.OpenFile(keyFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return "", err
+	}
+	if _, err = fmt.Fprintf(f, "%s:%s\n", id, base64.StdEncoding.EncodeToString(key)); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err = f.Sync(); err != nil {
+		f.Close()
+		return "", err
+	}
+	return id, f.Close()
+}
+
+func parseKey(line string) (id string, key []byte, err error) {
+	i := strings.LastIndex(line, ":")
+	if i <= 0 {
+		return "", nil, errors.New("expected <id>:<base64 key>")
+	}
+	id = line[:i]
+	if key, err = base64.StdEncoding.DecodeString(line[i+1:]); err != nil {
+		return "", nil, err
+	}
+	if len(key) != KeySize {
+		return "", nil, fmt.Errorf("key %q is %d bytes, expected %d", id, len(key), KeySize)
+	}
+	return id, key, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts the plaintext with a random nonce, which prefixes the
+// returned ciphertext.
+func seal(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(aead cipher.AEAD, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, ErrCorrupted
+	}
+	nonce, ciphertext := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,159 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvcc
+
+import (
+	"bytes"
+	"compress/flate"
+	"fmt"
+	"io"
+	"sync"
+
+	"go.etcd.io/etcd/server/v3/storage/encryption"
+)
+
+// CompressionType is the codec compressing the revisions stored in the key
+// bucket.
+type CompressionType string
+
+const (
+	CompressionNone  CompressionType = "none"
+	CompressionFlate CompressionType = "flate"
+)
+
+// DefaultCompressionMinSize is the default size in bytes under which the
+// revisions are stored uncompressed.
+const DefaultCompressionMinSize = 256
+
+// A compressed revision is stored as compressionMagic, the ID of its codec and
+// the compressed mvccpb.KeyValue. compressionMagic is not a valid protobuf
+// field tag, so a marshaled mvccpb.KeyValue never starts with it and the
+// revisions stored uncompressed are read as they are.
+//
+// An encrypted revision is stored as compressionMagic, codecEncrypted and
+// the encrypted revision, compressed or not.
+const (
+	compressionMagic      = 0x00
+	compressionHeaderSize = 2
+	codecEncrypted        = 0x80
+)
+
+// codecs maps the IDs stored in the headers of the compressed revisions to
+// their codec. The IDs must never be reused.
+var codecs = map[byte]codec{
+	1: flateCodec{},
+}
+
+var codecIDs = map[CompressionType]byte{
+	CompressionFlate: 1,
+}
+
+type codec interface {
+	compress(w io.Writer, src []byte) error
+	decompress(src []byte) ([]byte, error)
+}
+
+// ParseCompressionType returns the compression type of the given name; the
+// empty name disables the compression.
+func ParseCompressionType(s string) (CompressionType, error) {
+	switch t := CompressionType(s); t {
+	case "", CompressionNone:
+		return CompressionNone, nil
+	case CompressionFlate:
+		return t, nil
+	default:
+		return "", fmt.Errorf("unknown compression type %q (expected %q or %q)", s, CompressionNone, CompressionFlate)
+	}
+}
+
+// compressKeyValue returns the compressed revision of the marshaled
+// mvccpb.KeyValue d, or d if it is smaller than minSize or does not compress.
+func compressKeyValue(t CompressionType, minSize int, d []byte) []byte {
+	id, ok := codecIDs[t]
+	if !ok || len(d) < minSize {
+		return d
+	}
+	var buf bytes.Buffer
+	buf.Grow(len(d))
+	buf.Write([]byte{compressionMagic, id})
+	if err := codecs[id].compress(&buf, d); err != nil || buf.Len() >= len(d) {
+		return d
+	}
+	return buf.Bytes()
+}
+
+// IsCompressedKeyValue reports whether the revision stored in the key bucket
+// is compressed.
+func IsCompressedKeyValue(v []byte) bool {
+	return len(v) >= compressionHeaderSize && v[0] == compressionMagic && v[1] != codecEncrypted
+}
+
+// DecodeKeyValue returns the marshaled mvccpb.KeyValue of a revision stored in
+// the key bucket, decompressing it if needed. It returns
+// encryption.ErrNotConfigured if the revision is encrypted.
+func DecodeKeyValue(v []byte) ([]byte, error) {
+	if IsEncryptedKeyValue(v) {
+		return nil, encryption.ErrNotConfigured
+	}
+	if !IsCompressedKeyValue(v) {
+		return v, nil
+	}
+	c, ok := codecs[v[1]]
+	if !ok {
+		return nil, fmt.Errorf("mvcc: unknown compression codec %d", v[1])
+	}
+	return c.decompress(v[compressionHeaderSize:])
+}
+
+// flateCodec compresses at the best speed since the revisions are
+// compressed while they are applied.
+type flateCodec struct{}
+
+var (
+	flateWriters sync.Pool
+	flateReaders sync.Pool
+)
+
+func (flateCodec) compress(w io.Writer, src []byte) error {
+	fw, _ := flateWriters.Get().(*flate.Writer)
+	if fw == nil {
+		var err error
+		if fw, err = flate.NewWriter(w, flate.BestSpeed); err != nil {
+			return err
+		}
+	} else {
+		fw.Reset(w)
+	}
+	defer flateWriters.Put(fw)
+	if _, err := fw.Write(src); err != nil {
+		return err
+	}
+	return fw.Close()
+}
+
+func (flateCodec) decompress(src []byte) ([]byte, error) {
+	fr, _ := flateReaders.Get().(io.ReadCloser)
+	if fr == nil {
+		fr = flate.NewReader(bytes.NewReader(src))
+	} else if err := fr.(flate.Resetter).Reset(bytes.NewReader(src), nil); err != nil {
+		return nil, err
+	}
+	defer flateReaders.Put(fr)
+	d, err := io.ReadAll(fr)
+	if err != nil {
+		return nil, fmt.Errorf("mvcc: failed to decompress revision: %w", err)
+	}
+	return d, nil
+}
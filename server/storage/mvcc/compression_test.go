@@ -0,0 +1,121 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvcc
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/server/v3/lease"
+	betesting "go.etcd.io/etcd/server/v3/storage/backend/testing"
+	"go.etcd.io/etcd/server/v3/storage/schema"
+)
+
+func TestCompressKeyValue(t *testing.T) {
+	random := make([]byte, 1024)
+	_, err := rand.Read(random)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		compression CompressionType
+		value       []byte
+		wcompressed bool
+	}{
+		{name: "none", compression: CompressionNone, value: bytes.Repeat([]byte("a"), 1024)},
+		{name: "flate", compression: CompressionFlate, value: bytes.Repeat([]byte("a"), 1024), wcompressed: true},
+		{name: "small", compression: CompressionFlate, value: []byte("a")},
+		{name: "incompressible", compression: CompressionFlate, value: random},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kv := mvccpb.KeyValue{Key: []byte("foo"), Value: tt.value, CreateRevision: 2, ModRevision: 3, Version: 2}
+			d, err := kv.Marshal()
+			require.NoError(t, err)
+
+			v := compressKeyValue(tt.compression, DefaultCompressionMinSize, d)
+			assert.Equal(t, tt.wcompressed, IsCompressedKeyValue(v))
+			if tt.wcompressed {
+				assert.Less(t, len(v), len(d))
+			} else {
+				assert.Equal(t, d, v)
+			}
+
+			var got mvccpb.KeyValue
+			require.NoError(t, UnmarshalKeyValue(v, &got))
+			assert.Equal(t, kv, got)
+		})
+	}
+
+	_, err = DecodeKeyValue([]byte{compressionMagic, 255, 1, 2, 3})
+	assert.Error(t, err)
+}
+
+// TestStoreCompression tests that the store reads the revisions written
+// with or without compression, and hashes them the same way.
+func TestStoreCompression(t *testing.T) {
+	lg := zaptest.NewLogger(t)
+	b1, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, b1)
+	b2, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, b2)
+
+	s1 := NewStore(lg, b1, &lease.FakeLessor{}, StoreConfig{})
+	s2 := NewStore(lg, b2, &lease.FakeLessor{}, StoreConfig{Compression: CompressionFlate})
+	value := bytes.Repeat([]byte("bar"), 1024)
+	for _, s := range []*store{s1, s2} {
+		s.Put([]byte("foo"), []byte("small"), lease.NoLease)
+		s.Put([]byte("foo"), value, lease.NoLease)
+		s.DeleteRange([]byte("foo"), nil)
+		s.Put([]byte("foo"), value, lease.NoLease)
+		s.Commit()
+	}
+
+	var compressed int
+	tx := b2.ReadTx()
+	tx.RLock()
+	tx.UnsafeForEach(schema.Key, func(k, v []byte) error {
+		if IsCompressedKeyValue(v) {
+			compressed++
+		}
+		return nil
+	})
+	tx.RUnlock()
+	assert.Equal(t, 2, compressed)
+
+	h1, _, err := s1.HashStorage().HashByRev(0)
+	require.NoError(t, err)
+	h2, _, err := s2.HashStorage().HashByRev(0)
+	require.NoError(t, err)
+	assert.Equal(t, h1, h2)
+
+	// the store is restored from the compressed revisions, even with
+	// the compression disabled
+	s2.Close()
+	s2 = NewStore(lg, b2, &lease.FakeLessor{}, StoreConfig{})
+	for rev := int64(3); rev <= 5; rev += 2 {
+		r, err := s2.Range(context.TODO(), []byte("foo"), nil, RangeOptions{Rev: rev})
+		require.NoError(t, err)
+		require.Len(t, r.KVs, 1)
+		assert.Equal(t, value, r.KVs[0].Value)
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvcc
+
+import (
+	"go.etcd.io/etcd/api/v3/mvccpb"
+)
+
+// DefaultEventCacheBytes is the default bound of the size of the events kept
+// in the watch event cache.
+const DefaultEventCacheBytes = 64 * 1024 * 1024
+
+// eventCache keeps the events of the latest revisions in memory, so that the
+// unsynced watchers catching up on recent revisions are synced without reading
+// the backend. It holds contiguous revisions, bounded by number and by size,
+// and evicts the oldest revisions first. It is protected by watchableStore.mu.
+type eventCache struct {
+	maxRevs  int
+	maxBytes int
+
+	// revs is a ring of the cached revisions; the oldest one is at start
+	revs  []eventCacheRev
+	start int
+	n     int
+	bytes int
+}
+
+type eventCacheRev struct {
+	rev  int64
+	evs  []mvccpb.Event
+	size int
+}
+
+func newEventCache(maxRevs, maxBytes int) *eventCache {
+	if maxBytes <= 0 {
+		maxBytes = DefaultEventCacheBytes
+	}
+	return &eventCache{maxRevs: maxRevs, maxBytes: maxBytes, revs: make([]eventCacheRev, maxRevs)}
+}
+
+func (c *eventCache) at(i int) *eventCacheRev { return &c.revs[(c.start+i)%len(c.revs)] }
+
+// add caches the events of rev. A revision not following the latest cached
+// one, or larger than the cache, empties the cache to keep it contiguous.
+func (c *eventCache) add(rev int64, evs []mvccpb.Event) {
+	if c.n > 0 && rev != c.at(c.n-1).rev+1 {
+		c.reset()
+	}
+	size := 0
+	for i := range evs {
+		size += evs[i].Size()
+	}
+	if size > c.maxBytes {
+		c.reset()
+		return
+	}
+	for c.n == c.maxRevs || c.bytes+size > c.maxBytes {
+		c.evict()
+	}
+	*c.at(c.n) = eventCacheRev{rev: rev, evs: evs, size: size}
+	c.n++
+	c.bytes += size
+	c.report()
+}
+
+func (c *eventCache) evict() {
+	c.bytes -= c.at(0).size
+	*c.at(0) = eventCacheRev{}
+	c.start = (c.start + 1) % len(c.revs)
+	c.n--
+}
+
+func (c *eventCache) reset() {
+	for c.n > 0 {
+		c.evict()
+	}
+	c.start = 0
+	c.report()
+}
+
+func (c *eventCache) report() {
+	eventCacheRevisionsGauge.Set(float64(c.n))
+	eventCacheBytesGauge.Set(float64(c.bytes))
+}
+
+// rangeEvents returns the events of the revisions from minRev to maxRev on
+// the keys watched by wg, or false if the cache does not hold them all.
+func (c *eventCache) rangeEvents(wg *watcherGroup, minRev, maxRev int64) ([]mvccpb.Event, bool) {
+	if minRev > maxRev {
+		return nil, true
+	}
+	if c.n == 0 || minRev < c.at(0).rev || maxRev > c.at(c.n-1).rev {
+		return nil, false
+	}
+	var evs []mvccpb.Event
+	for i := int(minRev - c.at(0).rev); i < c.n; i++ {
+		r := c.at(i)
+		if r.rev > maxRev {
+			break
+		}
+		for _, ev := range r.evs {
+			if wg.contains(string(ev.Kv.Key)) {
+				evs = append(evs, ev)
+			}
+		}
+	}
+	return evs, true
+}
@@ -0,0 +1,138 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvcc
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/server/v3/lease"
+	betesting "go.etcd.io/etcd/server/v3/storage/backend/testing"
+	"go.etcd.io/etcd/server/v3/storage/schema"
+)
+
+func eventCacheTestEvents(rev int64, keys ...string) []mvccpb.Event {
+	evs := make([]mvccpb.Event, len(keys))
+	for i, k := range keys {
+		evs[i] = mvccpb.Event{Type: mvccpb.PUT, Kv: &mvccpb.KeyValue{Key: []byte(k), ModRevision: rev, Value: []byte("v")}}
+	}
+	return evs
+}
+
+func eventCacheTestKeys(evs []mvccpb.Event) []string {
+	var keys []string
+	for _, ev := range evs {
+		keys = append(keys, fmt.Sprintf("%s@%d", ev.Kv.Key, ev.Kv.ModRevision))
+	}
+	return keys
+}
+
+func TestEventCache(t *testing.T) {
+	evSize := eventCacheTestEvents(1, "a")[0].Size()
+	wg := newWatcherGroup()
+	wg.add(&watcher{key: []byte("a"), end: []byte("c")})
+
+	tests := []struct {
+		name     string
+		maxRevs  int
+		maxBytes int
+		add      []int64
+
+		minRev, maxRev int64
+		wok            bool
+		wkeys          []string
+	}{
+		{name: "hit", maxRevs: 10, add: []int64{2, 3, 4}, minRev: 3, maxRev: 4, wok: true, wkeys: []string{"a@3", "b@3", "a@4", "b@4"}},
+		{name: "hit part", maxRevs: 10, add: []int64{2, 3, 4}, minRev: 2, maxRev: 3, wok: true, wkeys: []string{"a@2", "b@2", "a@3", "b@3"}},
+		{name: "evicted by revisions", maxRevs: 2, add: []int64{2, 3, 4}, minRev: 2, maxRev: 4},
+		{name: "kept by revisions", maxRevs: 2, add: []int64{2, 3, 4}, minRev: 3, maxRev: 4, wok: true, wkeys: []string{"a@3", "b@3", "a@4", "b@4"}},
+		{name: "evicted by bytes", maxRevs: 10, maxBytes: 5 * evSize, add: []int64{2, 3, 4}, minRev: 3, maxRev: 4},
+		{name: "kept by bytes", maxRevs: 10, maxBytes: 5 * evSize, add: []int64{2, 3, 4}, minRev: 4, maxRev: 4, wok: true, wkeys: []string{"a@4", "b@4"}},
+		{name: "not contiguous", maxRevs: 10, add: []int64{2, 3, 5}, minRev: 3, maxRev: 5},
+		{name: "after gap", maxRevs: 10, add: []int64{2, 3, 5, 6}, minRev: 5, maxRev: 6, wok: true, wkeys: []string{"a@5", "b@5", "a@6", "b@6"}},
+		{name: "future revision", maxRevs: 10, add: []int64{2, 3}, minRev: 3, maxRev: 4},
+		{name: "larger than the cache", maxRevs: 10, maxBytes: evSize, add: []int64{2}, minRev: 2, maxRev: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newEventCache(tt.maxRevs, tt.maxBytes)
+			for _, rev := range tt.add {
+				c.add(rev, eventCacheTestEvents(rev, "a", "b", "c"))
+			}
+			evs, ok := c.rangeEvents(&wg, tt.minRev, tt.maxRev)
+			if ok != tt.wok {
+				t.Fatalf("ok = %v, want %v", ok, tt.wok)
+			}
+			if keys := eventCacheTestKeys(evs); !reflect.DeepEqual(keys, tt.wkeys) {
+				t.Errorf("events = %v, want %v", keys, tt.wkeys)
+			}
+		})
+	}
+}
+
+// TestWatchEventCache ensures the unsynced watchers are synced from the
+// event cache without reading the backend.
+func TestWatchEventCache(t *testing.T) {
+	b, tmpPath := betesting.NewDefaultTmpBackend(t)
+	s := newWatchableStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{EventCacheRevisions: 100})
+
+	defer func() {
+		s.store.Close()
+		os.Remove(tmpPath)
+	}()
+
+	for i := 0; i < 10; i++ {
+		s.Put([]byte(fmt.Sprintf("foo%d", i)), []byte("bar"), lease.NoLease)
+	}
+	// the cache holds the revisions written after the restore
+	s.Restore(b)
+	for i := 0; i < 10; i++ {
+		s.Put([]byte(fmt.Sprintf("foo%d", i)), []byte("baz"), lease.NoLease)
+	}
+
+	// the revisions are only left in the cache
+	tx := b.BatchTx()
+	tx.Lock()
+	revs, _ := tx.UnsafeRange(schema.Key, []byte{0}, []byte{0xff}, 0)
+	for _, rev := range revs {
+		tx.UnsafeDelete(schema.Key, rev)
+	}
+	tx.Unlock()
+	b.ForceCommit()
+
+	w := s.NewWatchStream()
+	defer w.Close()
+	if _, err := w.Watch(0, []byte("foo"), []byte("fop"), 12); err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for len(keys) < 10 {
+		select {
+		case resp := <-w.Chan():
+			keys = append(keys, eventCacheTestKeys(resp.Events)...)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("failed to receive the events, got %v", keys)
+		}
+	}
+	if keys[0] != "foo0@12" || keys[9] != "foo9@21" {
+		t.Errorf("events = %v", keys)
+	}
+}
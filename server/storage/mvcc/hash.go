@@ -30,8 +30,8 @@ const (
 	hashStorageMaxSize = 10
 )
 
-func unsafeHashByRev(tx backend.ReadTx, compactRevision, revision int64, keep map[revision]struct{}) (KeyValueHash, error) {
-	h := newKVHasher(compactRevision, revision, keep)
+func unsafeHashByRev(tx backend.ReadTx, codec keyValueCodec, compactRevision, revision int64, keep map[revision]struct{}) (KeyValueHash, error) {
+	h := newKVHasher(codec, compactRevision, revision, keep)
 	err := tx.UnsafeForEach(schema.Key, func(k, v []byte) error {
 		h.WriteKeyValue(k, v)
 		return nil
@@ -41,16 +41,18 @@ func unsafeHashByRev(tx backend.ReadTx, compactRevision, revision int64, keep ma
 
 type kvHasher struct {
 	hash            hash.Hash32
+	codec           keyValueCodec
 	compactRevision int64
 	revision        int64
 	keep            map[revision]struct{}
 }
 
-func newKVHasher(compactRev, rev int64, keep map[revision]struct{}) kvHasher {
+func newKVHasher(codec keyValueCodec, compactRev, rev int64, keep map[revision]struct{}) kvHasher {
 	h := crc32.New(crc32.MakeTable(crc32.Castagnoli))
 	h.Write(schema.Key.Name())
 	return kvHasher{
 		hash:            h,
+		codec:           codec,
 		compactRevision: compactRev,
 		revision:        rev,
 		keep:            keep,
@@ -72,6 +74,11 @@ func (h *kvHasher) WriteKeyValue(k, v []byte) {
 		}
 	}
 	h.hash.Write(k)
+	// hash the revisions decoded so that the members compressing or
+	// encrypting them differently, or not at all, have the same hash.
+	if d, err := h.codec.decode(v); err == nil {
+		v = d
+	}
 	h.hash.Write(v)
 }
 
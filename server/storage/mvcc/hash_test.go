@@ -128,6 +128,37 @@ func testHashByRev(t *testing.T, s *store, rev int64) KeyValueHash {
 	return hash
 }
 
+// TestHashByRevAttachedLease ensures the hash of the revisions leaves out the
+// attached leases, as attaching leases does not create a revision, while the
+// hash of the backend covers them.
+func TestHashByRevAttachedLease(t *testing.T) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	s := NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{})
+
+	s.Put([]byte("foo"), []byte("bar"), lease.NoLease)
+	rev := s.Put([]byte("foo1"), []byte("bar"), lease.NoLease)
+	before, _, err := s.hashByRev(rev)
+	assert.NoError(t, err)
+	beforeAll, _, err := s.hash()
+	assert.NoError(t, err)
+
+	txn := s.Write(traceutil.TODO())
+	assert.Equal(t, int64(1), txn.AttachLease([]byte("foo"), nil, 1))
+	txn.End()
+	after, _, err := s.hashByRev(rev)
+	assert.NoError(t, err)
+	assert.Equal(t, before, after)
+	afterAll, _, err := s.hash()
+	assert.NoError(t, err)
+	assert.NotEqual(t, beforeAll, afterAll)
+
+	done, err := s.Compact(traceutil.TODO(), rev)
+	assert.NoError(t, err)
+	<-done
+	hashes := s.HashStorage().Hashes()
+	assert.Equal(t, before.Hash, hashes[len(hashes)-1].Hash)
+}
+
 // TestCompactionHash tests compaction hash
 // TODO: Change this to fuzz test
 func TestCompactionHash(t *testing.T) {
@@ -15,6 +15,7 @@
 package mvcc
 
 import (
+	"sort"
 	"sync"
 
 	"github.com/google/btree"
@@ -26,10 +27,11 @@ type index interface {
 	Range(key, end []byte, atRev int64) ([][]byte, []revision)
 	Revisions(key, end []byte, atRev int64, limit int) ([]revision, int)
 	CountRevisions(key, end []byte, atRev int64) int
+	History(key, end []byte, startRev, endRev, limit int64) (revs []revision, more bool)
 	Put(key []byte, rev revision)
 	Tombstone(key []byte, rev revision) error
-	Compact(rev int64) map[revision]struct{}
-	Keep(rev int64) map[revision]struct{}
+	Compact(rev int64, rs []Retention) (available, retained map[revision]struct{})
+	Keep(rev int64, rs []Retention) map[revision]struct{}
 	Equal(b index) bool
 
 	Insert(ki *keyIndex)
@@ -155,6 +157,66 @@ func (ti *treeIndex) CountRevisions(key, end []byte, atRev int64) int {
 	return total
 }
 
+// History returns the revisions from startRev to endRev (both included) of the keys
+// from key(included) to end(excluded), including the tombstones. The returned slice
+// is sorted in the order of revision. If limit > 0, it returns the first limit
+// revisions, or more to not split the revisions sharing the same main revision,
+// and whether there are more revisions.
+func (ti *treeIndex) History(key, end []byte, startRev, endRev, limit int64) (revs []revision, more bool) {
+	ti.RLock()
+	defer ti.RUnlock()
+
+	// no key has more than limit + 1 revisions in the result, counting the
+	// one telling there are more
+	keyLimit := 0
+	if limit > 0 {
+		keyLimit = int(limit) + 1
+	}
+	if end == nil {
+		keyi := ti.keyIndex(&keyIndex{key: key})
+		if keyi == nil {
+			return nil, false
+		}
+		return limitHistory(keyi.history(ti.lg, startRev, endRev, keyLimit), limit)
+	}
+	ti.unsafeVisit(key, end, func(ki *keyIndex) bool {
+		revs = append(revs, ki.history(ti.lg, startRev, endRev, keyLimit)...)
+		if keyLimit > 0 && len(revs) > 2*keyLimit {
+			// keep the first revision past the limit to tell there are more,
+			// and only read the revisions up to the limit from the next keys
+			sortRevisions(revs)
+			last := revs[limit-1].main
+			n := int(limit)
+			for n < len(revs) && revs[n].main == last {
+				n++
+			}
+			if n < len(revs) {
+				revs, endRev = revs[:n+1], last
+			}
+		}
+		return true
+	})
+	sortRevisions(revs)
+	return limitHistory(revs, limit)
+}
+
+func sortRevisions(revs []revision) {
+	sort.Slice(revs, func(i, j int) bool { return revs[j].GreaterThan(revs[i]) })
+}
+
+// limitHistory returns the first limit revisions, without splitting the
+// revisions sharing the same main revision, and whether there are more.
+func limitHistory(revs []revision, limit int64) ([]revision, bool) {
+	if limit <= 0 || int64(len(revs)) <= limit {
+		return revs, false
+	}
+	n := int(limit)
+	for n < len(revs) && revs[n].main == revs[n-1].main {
+		n++
+	}
+	return revs[:n], n < len(revs)
+}
+
 func (ti *treeIndex) Range(key, end []byte, atRev int64) (keys [][]byte, revs []revision) {
 	ti.RLock()
 	defer ti.RUnlock()
@@ -189,8 +251,10 @@ func (ti *treeIndex) Tombstone(key []byte, rev revision) error {
 	return ki.tombstone(ti.lg, rev.main, rev.sub)
 }
 
-func (ti *treeIndex) Compact(rev int64) map[revision]struct{} {
-	available := make(map[revision]struct{})
+// Compact compacts the index at the given rev. It returns the revisions kept by
+// the compaction and the ones only kept by the given retention rules.
+func (ti *treeIndex) Compact(rev int64, rs []Retention) (available, retained map[revision]struct{}) {
+	available, retained = make(map[revision]struct{}), make(map[revision]struct{})
 	ti.lg.Info("compact tree index", zap.Int64("revision", rev))
 	ti.Lock()
 	clone := ti.tree.Clone()
@@ -200,7 +264,7 @@ func (ti *treeIndex) Compact(rev int64) map[revision]struct{} {
 		// Lock is needed here to prevent modification to the keyIndex while
 		// compaction is going on or revision added to empty before deletion
 		ti.Lock()
-		keyi.compact(ti.lg, rev, available)
+		keyi.compactRetaining(ti.lg, rev, rs, available, retained)
 		if keyi.isEmpty() {
 			_, ok := ti.tree.Delete(keyi)
 			if !ok {
@@ -210,16 +274,20 @@ func (ti *treeIndex) Compact(rev int64) map[revision]struct{} {
 		ti.Unlock()
 		return true
 	})
-	return available
+	return available, retained
 }
 
-// Keep finds all revisions to be kept for a Compaction at the given rev.
-func (ti *treeIndex) Keep(rev int64) map[revision]struct{} {
+// Keep finds all revisions to be kept for a Compaction at the given rev,
+// including the ones kept by the given retention rules.
+func (ti *treeIndex) Keep(rev int64, rs []Retention) map[revision]struct{} {
 	available := make(map[revision]struct{})
 	ti.RLock()
 	defer ti.RUnlock()
 	ti.tree.Ascend(func(keyi *keyIndex) bool {
 		keyi.keep(rev, available)
+		for r := range keyi.retained(rev, rs) {
+			available[r] = struct{}{}
+		}
 		return true
 	})
 	return available
@@ -15,6 +15,7 @@
 package mvcc
 
 import (
+	"fmt"
 	"reflect"
 	"testing"
 
@@ -233,6 +234,43 @@ func TestIndexRevision(t *testing.T) {
 	}
 }
 
+func TestIndexHistory(t *testing.T) {
+	ti := newTreeIndex(zaptest.NewLogger(t))
+	ti.Put([]byte("foo"), revision{main: 1})
+	ti.Put([]byte("foo1"), revision{main: 2})
+	ti.Put([]byte("foo"), revision{main: 3})
+	if err := ti.Tombstone([]byte("foo"), revision{main: 4}); err != nil {
+		t.Fatal(err)
+	}
+	ti.Put([]byte("foo"), revision{main: 5, sub: 0})
+	ti.Put([]byte("foo1"), revision{main: 5, sub: 1})
+
+	tests := []struct {
+		key, end         []byte
+		startRev, endRev int64
+		limit            int64
+		wrevs            []revision
+		wmore            bool
+	}{
+		{[]byte("bar"), nil, 1, 5, 0, nil, false},
+		{[]byte("foo"), nil, 1, 5, 0, []revision{{main: 1}, {main: 3}, {main: 4}, {main: 5}}, false},
+		{[]byte("foo"), nil, 2, 4, 0, []revision{{main: 3}, {main: 4}}, false},
+		{[]byte("foo"), []byte("foo2"), 1, 5, 0, []revision{{main: 1}, {main: 2}, {main: 3}, {main: 4}, {main: 5}, {main: 5, sub: 1}}, false},
+		{[]byte("foo1"), []byte("foo2"), 3, 5, 0, []revision{{main: 5, sub: 1}}, false},
+		{[]byte("foo"), nil, 1, 5, 2, []revision{{main: 1}, {main: 3}}, true},
+		{[]byte("foo"), nil, 1, 5, 4, []revision{{main: 1}, {main: 3}, {main: 4}, {main: 5}}, false},
+		{[]byte("foo"), []byte("foo2"), 1, 5, 3, []revision{{main: 1}, {main: 2}, {main: 3}}, true},
+		// the revisions of the same main revision are not split
+		{[]byte("foo"), []byte("foo2"), 1, 5, 5, []revision{{main: 1}, {main: 2}, {main: 3}, {main: 4}, {main: 5}, {main: 5, sub: 1}}, false},
+	}
+	for i, tt := range tests {
+		revs, more := ti.History(tt.key, tt.end, tt.startRev, tt.endRev, tt.limit)
+		if !reflect.DeepEqual(revs, tt.wrevs) || more != tt.wmore {
+			t.Errorf("#%d: revs = %+v, more = %v, want %+v, %v", i, revs, more, tt.wrevs, tt.wmore)
+		}
+	}
+}
+
 func TestIndexCompactAndKeep(t *testing.T) {
 	maxRev := int64(20)
 	tests := []struct {
@@ -265,8 +303,8 @@ func TestIndexCompactAndKeep(t *testing.T) {
 		}
 	}
 	for i := int64(1); i < maxRev; i++ {
-		am := ti.Compact(i)
-		keep := ti.Keep(i)
+		am, _ := ti.Compact(i, nil)
+		keep := ti.Keep(i, nil)
 		if !(reflect.DeepEqual(am, keep)) {
 			t.Errorf("#%d: compact keep %v != Keep keep %v", i, am, keep)
 		}
@@ -297,8 +335,8 @@ func TestIndexCompactAndKeep(t *testing.T) {
 				ti.Put(tt.key, tt.rev)
 			}
 		}
-		am := ti.Compact(i)
-		keep := ti.Keep(i)
+		am, _ := ti.Compact(i, nil)
+		keep := ti.Keep(i, nil)
 		if !(reflect.DeepEqual(am, keep)) {
 			t.Errorf("#%d: compact keep %v != Keep keep %v", i, am, keep)
 		}
@@ -333,3 +371,31 @@ func restore(ti *treeIndex, key []byte, created, modified revision, ver int64) {
 	}
 	okeyi.put(ti.lg, modified.main, modified.sub)
 }
+
+func TestIndexHistoryLimit(t *testing.T) {
+	ti := newTreeIndex(zaptest.NewLogger(t))
+	// the keys are written in the reverse order of their revisions
+	var all []revision
+	for rev := int64(1); rev <= 100; rev++ {
+		ti.Put([]byte(fmt.Sprintf("foo%03d", 100-rev)), revision{main: rev})
+		all = append(all, revision{main: rev})
+	}
+	// a txn writes two keys at the same revision
+	ti.Put([]byte("foo098"), revision{main: 101})
+	ti.Put([]byte("foo099"), revision{main: 101, sub: 1})
+	all = append(all, revision{main: 101}, revision{main: 101, sub: 1})
+
+	for _, limit := range []int64{1, 2, 3, 10, 99, 100, 101, 102} {
+		revs, more := ti.History([]byte("foo"), []byte("fop"), 1, 101, limit)
+		n := limit
+		if n == 101 {
+			n = 102
+		}
+		if n > int64(len(all)) {
+			n = int64(len(all))
+		}
+		if !reflect.DeepEqual(revs, all[:n]) || more != (n < int64(len(all))) {
+			t.Errorf("limit %d: revs = %+v, more = %v, want %+v, %v", limit, revs, more, all[:n], n < int64(len(all)))
+		}
+	}
+}
@@ -169,8 +169,32 @@ func (ki *keyIndex) since(lg *zap.Logger, rev int64) []revision {
 		)
 	}
 	since := revision{rev, 0}
+	gi := ki.sinceGeneration(since)
+
+	var revs []revision
+	var last int64
+	for ; gi < len(ki.generations); gi++ {
+		for _, r := range ki.generations[gi].revs {
+			if since.GreaterThan(r) {
+				continue
+			}
+			if r.main == last {
+				// replace the revision with a new one that has higher sub value,
+				// because the original one should not be seen by external
+				revs[len(revs)-1] = r
+				continue
+			}
+			revs = append(revs, r)
+			last = r.main
+		}
+	}
+	return revs
+}
+
+// sinceGeneration returns the index of the generation to start checking for
+// the revisions since the given one.
+func (ki *keyIndex) sinceGeneration(since revision) int {
 	var gi int
-	// find the generations to start checking
 	for gi = len(ki.generations) - 1; gi > 0; gi-- {
 		g := ki.generations[gi]
 		if g.isEmpty() {
@@ -180,20 +204,37 @@ func (ki *keyIndex) since(lg *zap.Logger, rev int64) []revision {
 			break
 		}
 	}
+	return gi
+}
 
+// history returns the revisions from startRev to endRev, both included, as
+// since does. If limit > 0, it returns at most limit revisions.
+func (ki *keyIndex) history(lg *zap.Logger, startRev, endRev int64, limit int) []revision {
+	if ki.isEmpty() {
+		lg.Panic(
+			"'history' got an unexpected empty keyIndex",
+			zap.String("key", string(ki.key)),
+		)
+	}
+	since := revision{startRev, 0}
 	var revs []revision
 	var last int64
-	for ; gi < len(ki.generations); gi++ {
+	for gi := ki.sinceGeneration(since); gi < len(ki.generations); gi++ {
 		for _, r := range ki.generations[gi].revs {
 			if since.GreaterThan(r) {
 				continue
 			}
+			if r.main > endRev {
+				return revs
+			}
 			if r.main == last {
-				// replace the revision with a new one that has higher sub value,
-				// because the original one should not be seen by external
+				// replace the revision with a new one that has higher sub value
 				revs[len(revs)-1] = r
 				continue
 			}
+			if limit > 0 && len(revs) == limit {
+				return revs
+			}
 			revs = append(revs, r)
 			last = r.main
 		}
@@ -232,6 +273,92 @@ func (ki *keyIndex) compact(lg *zap.Logger, atRev int64, available map[revision]
 	ki.generations = ki.generations[genIdx:]
 }
 
+// compactRetaining compacts a keyIndex like compact, but also keeps the
+// revisions retained by the given retention rules. The revisions kept only by
+// the rules are added to retained instead of available.
+func (ki *keyIndex) compactRetaining(lg *zap.Logger, atRev int64, rs []Retention, available, retained map[revision]struct{}) {
+	rr := ki.retained(atRev, rs)
+	if len(rr) == 0 {
+		ki.compact(lg, atRev, available)
+		return
+	}
+
+	// compact resizes the revisions of the generations in place
+	generations := make([]generation, len(ki.generations))
+	copy(generations, ki.generations)
+	ki.compact(lg, atRev, available)
+
+	// the revisions kept by compact and the retained ones are the latest of
+	// each generation
+	kept := make([]generation, 0, len(generations))
+	for i, g := range generations {
+		n := 0
+		for n < len(g.revs) {
+			rev := g.revs[n]
+			_, ok := available[rev]
+			_, rok := rr[rev]
+			if rev.main > atRev || ok || rok {
+				break
+			}
+			n++
+		}
+		if n == len(g.revs) && i != len(generations)-1 {
+			continue
+		}
+		for _, rev := range g.revs[n:] {
+			if _, ok := available[rev]; !ok && rev.main <= atRev {
+				retained[rev] = struct{}{}
+			}
+		}
+		kept = append(kept, generation{ver: g.ver, created: g.created, revs: g.revs[n:]})
+	}
+	ki.generations = kept
+}
+
+// retained returns the revisions with smaller or equal revision than the
+// given atRev kept by the given retention rules. A key deleted at or before
+// atRev is not kept by its number of versions, so that its generations are
+// eventually compacted.
+func (ki *keyIndex) retained(atRev int64, rs []Retention) map[revision]struct{} {
+	var versions, minRev int64
+	for _, r := range rs {
+		if !bytes.HasPrefix(ki.key, r.Prefix) {
+			continue
+		}
+		if r.Versions > versions {
+			versions = r.Versions
+		}
+		if r.MinRevision > 0 && (minRev == 0 || r.MinRevision < minRev) {
+			minRev = r.MinRevision
+		}
+	}
+	if n := len(ki.generations); versions > 0 && n > 1 && ki.generations[n-1].isEmpty() {
+		if revs := ki.generations[n-2].revs; revs[len(revs)-1].main <= atRev {
+			versions = 0
+		}
+	}
+	if versions == 0 && minRev == 0 {
+		return nil
+	}
+
+	rr := make(map[revision]struct{})
+	n := int64(0)
+	for i := len(ki.generations) - 1; i >= 0; i-- {
+		revs := ki.generations[i].revs
+		for j := len(revs) - 1; j >= 0; j-- {
+			n++
+			rev := revs[j]
+			if n > versions && (minRev == 0 || rev.main < minRev) {
+				return rr
+			}
+			if rev.main <= atRev {
+				rr[rev] = struct{}{}
+			}
+		}
+	}
+	return rr
+}
+
 // keep finds the revision to be kept if compact is called at given atRev.
 func (ki *keyIndex) keep(atRev int64, available map[revision]struct{}) {
 	if ki.isEmpty() {
@@ -557,6 +557,110 @@ func TestKeyIndexCompactOnFurtherRev(t *testing.T) {
 	}
 }
 
+func TestKeyIndexCompactRetaining(t *testing.T) {
+	tests := []struct {
+		name    string
+		compact int64
+		rs      []Retention
+
+		wgens     []generation
+		wam       map[revision]struct{}
+		wretained map[revision]struct{}
+	}{
+		{
+			name:    "no matching rule",
+			compact: 15,
+			rs:      []Retention{{Prefix: []byte("bar"), Versions: 10}},
+			wgens: []generation{
+				{created: revision{14, 0}, ver: 3, revs: []revision{{main: 14, sub: 1}, {main: 16}}},
+				{},
+			},
+			wam:       map[revision]struct{}{{main: 14, sub: 1}: {}},
+			wretained: map[revision]struct{}{},
+		},
+		{
+			name:    "versions",
+			compact: 15,
+			rs:      []Retention{{Prefix: []byte("fo"), Versions: 4}},
+			wgens: []generation{
+				{created: revision{8, 0}, ver: 3, revs: []revision{{main: 12}}},
+				{created: revision{14, 0}, ver: 3, revs: []revision{{main: 14}, {main: 14, sub: 1}, {main: 16}}},
+				{},
+			},
+			wam:       map[revision]struct{}{{main: 14, sub: 1}: {}},
+			wretained: map[revision]struct{}{{main: 12}: {}, {main: 14}: {}},
+		},
+		{
+			name:    "duration",
+			compact: 15,
+			rs:      []Retention{{Prefix: []byte("foo"), MinRevision: 9}},
+			wgens: []generation{
+				{created: revision{8, 0}, ver: 3, revs: []revision{{main: 10}, {main: 12}}},
+				{created: revision{14, 0}, ver: 3, revs: []revision{{main: 14}, {main: 14, sub: 1}, {main: 16}}},
+				{},
+			},
+			wam:       map[revision]struct{}{{main: 14, sub: 1}: {}},
+			wretained: map[revision]struct{}{{main: 10}: {}, {main: 12}: {}, {main: 14}: {}},
+		},
+		{
+			name:    "largest rule",
+			compact: 7,
+			rs: []Retention{
+				{Prefix: []byte("foo"), Versions: 1, MinRevision: 5},
+				{Prefix: []byte("f"), MinRevision: 3},
+			},
+			wgens: []generation{
+				{created: revision{2, 0}, ver: 3, revs: []revision{{main: 4}, {main: 6}}},
+				{created: revision{8, 0}, ver: 3, revs: []revision{{main: 8}, {main: 10}, {main: 12}}},
+				{created: revision{14, 0}, ver: 3, revs: []revision{{main: 14}, {main: 14, sub: 1}, {main: 16}}},
+				{},
+			},
+			wam:       map[revision]struct{}{},
+			wretained: map[revision]struct{}{{main: 4}: {}, {main: 6}: {}},
+		},
+		{
+			name:    "deleted key",
+			compact: 16,
+			rs:      []Retention{{Prefix: []byte("foo"), Versions: 1}},
+			wgens: []generation{
+				{},
+			},
+			wam:       map[revision]struct{}{},
+			wretained: map[revision]struct{}{},
+		},
+		{
+			name:    "deleted key duration",
+			compact: 16,
+			rs:      []Retention{{Prefix: []byte("foo"), Versions: 1, MinRevision: 16}},
+			wgens: []generation{
+				{created: revision{14, 0}, ver: 3, revs: []revision{{main: 16}}},
+				{},
+			},
+			wam:       map[revision]struct{}{},
+			wretained: map[revision]struct{}{{main: 16}: {}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg := zaptest.NewLogger(t)
+			ki := newTestKeyIndex(lg)
+			am, retained := make(map[revision]struct{}), make(map[revision]struct{})
+			ki.compactRetaining(lg, tt.compact, tt.rs, am, retained)
+
+			wki := &keyIndex{key: []byte("foo"), modified: revision{16, 0}, generations: tt.wgens}
+			if !reflect.DeepEqual(ki, wki) {
+				t.Errorf("ki = %+v, want %+v", ki, wki)
+			}
+			if !reflect.DeepEqual(am, tt.wam) {
+				t.Errorf("am = %+v, want %+v", am, tt.wam)
+			}
+			if !reflect.DeepEqual(retained, tt.wretained) {
+				t.Errorf("retained = %+v, want %+v", retained, tt.wretained)
+			}
+		})
+	}
+}
+
 func TestKeyIndexIsEmpty(t *testing.T) {
 	tests := []struct {
 		ki *keyIndex
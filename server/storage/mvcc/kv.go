@@ -27,6 +27,27 @@ type RangeOptions struct {
 	Limit int64
 	Rev   int64
 	Count bool
+	// Filter, when set, drops the key-value pairs for which it returns false.
+	// Limit applies to the key-value pairs accepted by the filter.
+	// Count is not affected by the filter.
+	Filter func(kv *mvccpb.KeyValue) bool
+}
+
+// RangeIterator reads the key-value pairs of a range in several steps. All steps
+// read the range at the revision the iterator was created at, so that a large range
+// can be read without holding all of its key-value pairs in memory at once.
+type RangeIterator interface {
+	// Rev returns the current revision of the KV when the iterator was created.
+	Rev() int64
+
+	// Count returns the number of keys in the range. It is not affected by the filter.
+	Count() int
+
+	// Next returns up to n key-value pairs following the ones returned by the previous call.
+	// If maxBytes > 0, it stops once the returned key-value pairs reach maxBytes in size.
+	// It returns no key-value pair once the range is exhausted.
+	// If the revision of the iterator has been compacted, ErrCompacted will be returned.
+	Next(ctx context.Context, n int, maxBytes int) ([]mvccpb.KeyValue, error)
 }
 
 type RangeResult struct {
@@ -35,6 +56,25 @@ type RangeResult struct {
 	Count int
 }
 
+type HistoryOptions struct {
+	// StartRev is the first revision of the history, the compact revision if <= 0.
+	StartRev int64
+	// EndRev is the last revision of the history, the current revision if <= 0.
+	EndRev int64
+	// Limit limits the number of key-value pairs returned, except that the
+	// key-value pairs of the same revision are never split.
+	Limit int64
+}
+
+type HistoryResult struct {
+	// KVs are the revisions of the keys ordered by revision. A tombstone has
+	// only the key and the mod revision set.
+	KVs        []mvccpb.KeyValue
+	Rev        int64
+	CompactRev int64
+	More       bool
+}
+
 type ReadView interface {
 	// FirstRev returns the first KV revision at the time of opening the txn.
 	// After a compaction, the first revision increases to the compaction
@@ -87,6 +127,16 @@ type TxnWrite interface {
 	WriteView
 	// Changes gets the changes made since opening the write txn.
 	Changes() []mvccpb.KeyValue
+	// SetDeleteCause sets the cause of the following deletes of the txn, and
+	// the ID of the lease whose revocation causes them.
+	SetDeleteCause(cause mvccpb.Event_DeleteCause, id lease.LeaseID)
+	// AttachLease attaches the keys in the range to the given lease in place
+	// of their current lease, or detaches them from their lease if id is
+	// NoLease. The keys keep their current revision, so no event is
+	// generated, and the attachments are left out of the hashes of the
+	// revisions; only the hash of the backend covers them. The number of keys
+	// in the range is returned. AttachLease does not validate the lease id.
+	AttachLease(key, end []byte, id lease.LeaseID) (n int64)
 }
 
 // txnReadWrite coerces a read txn to a write, panicking on any write operation.
@@ -98,6 +148,12 @@ func (trw *txnReadWrite) Put(key, value []byte, lease lease.LeaseID) (rev int64)
 }
 func (trw *txnReadWrite) Changes() []mvccpb.KeyValue { return nil }
 
+func (trw *txnReadWrite) AttachLease(key, end []byte, id lease.LeaseID) (n int64) {
+	panic("unexpected AttachLease")
+}
+
+func (trw *txnReadWrite) SetDeleteCause(cause mvccpb.Event_DeleteCause, id lease.LeaseID) {}
+
 func NewReadOnlyTxnWrite(txn TxnRead) TxnWrite { return &txnReadWrite{txn} }
 
 type ReadTxMode uint32
@@ -119,17 +175,38 @@ type KV interface {
 	// Write creates a write transaction.
 	Write(trace *traceutil.Trace) TxnWrite
 
+	// Iterate creates an iterator over the keys in the range at the revision of ro.
+	// The arguments are the same as for Range, except that Limit is ignored.
+	Iterate(key, end []byte, ro RangeOptions) (RangeIterator, error)
+
+	// History gets all revisions of the keys in the range between the revisions
+	// of ho, both included, with the same key and end conventions as Range.
+	// If the start revision is compacted, ErrCompacted will be returned.
+	History(ctx context.Context, key, end []byte, ho HistoryOptions) (*HistoryResult, error)
+
 	// HashStorage returns HashStorage interface for KV storage.
 	HashStorage() HashStorage
 
-	// Compact frees all superseded keys with revisions less than rev.
+	// Compact frees all superseded keys with revisions less than rev, but
+	// the ones kept by the retention rules of the last compaction.
 	Compact(trace *traceutil.Trace, rev int64) (<-chan struct{}, error)
 
+	// CompactRetaining is like Compact, but keeps the revisions retained by
+	// the given retention rules, which are kept for the next compactions.
+	CompactRetaining(trace *traceutil.Trace, rev int64, rs []Retention) (<-chan struct{}, error)
+
 	// Commit commits outstanding txns into the underlying backend.
 	Commit()
 
 	// Restore restores the KV store from a backend.
 	Restore(b backend.Backend) error
+
+	// SetChangeHook sets the function called with the changes of each write txn
+	// before they become visible to readers, along with the size of the key and
+	// value each change replaces, or -1 if the key did not exist. The hook must
+	// not open txns on the KV.
+	SetChangeHook(hook func(changes []mvccpb.KeyValue, prevSizes []int64))
+
 	Close() error
 }
 
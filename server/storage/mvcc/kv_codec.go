@@ -0,0 +1,100 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvcc
+
+import (
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/server/v3/storage/encryption"
+)
+
+// IsEncryptedKeyValue reports whether the revision stored in the key bucket
+// is encrypted.
+func IsEncryptedKeyValue(v []byte) bool {
+	return len(v) >= compressionHeaderSize && v[0] == compressionMagic && v[1] == codecEncrypted
+}
+
+// UnmarshalKeyValue unmarshals an unencrypted revision stored in the key
+// bucket, whether it is compressed or not.
+func UnmarshalKeyValue(v []byte, kv *mvccpb.KeyValue) error {
+	return keyValueCodec{}.unmarshal(v, kv)
+}
+
+// EncryptKeyValue encrypts a revision stored in the key bucket with the
+// cipher, decrypting it first with the same cipher if it is encrypted.
+func EncryptKeyValue(c encryption.Cipher, v []byte) ([]byte, error) {
+	codec := keyValueCodec{cipher: c}
+	d, err := codec.decrypt(v)
+	if err != nil {
+		return nil, err
+	}
+	return codec.encrypt(d)
+}
+
+// DecryptKeyValue decrypts a revision stored in the key bucket with the
+// cipher, if it is encrypted. The decrypted revision may be compressed.
+func DecryptKeyValue(c encryption.Cipher, v []byte) ([]byte, error) {
+	return keyValueCodec{cipher: c}.decrypt(v)
+}
+
+// keyValueCodec encodes the revisions written to the key bucket, and
+// decodes the revisions read whatever the codec they are written with.
+type keyValueCodec struct {
+	compression CompressionType
+	minSize     int
+	// cipher encrypts the revisions if not nil.
+	cipher encryption.Cipher
+}
+
+func (c keyValueCodec) encode(d []byte) ([]byte, error) {
+	return c.encrypt(compressKeyValue(c.compression, c.minSize, d))
+}
+
+func (c keyValueCodec) encrypt(v []byte) ([]byte, error) {
+	if c.cipher == nil {
+		return v, nil
+	}
+	ev, err := c.cipher.Encrypt(v)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{compressionMagic, codecEncrypted}, ev...), nil
+}
+
+func (c keyValueCodec) decrypt(v []byte) ([]byte, error) {
+	if !IsEncryptedKeyValue(v) {
+		return v, nil
+	}
+	if c.cipher == nil {
+		return nil, encryption.ErrNotConfigured
+	}
+	return c.cipher.Decrypt(v[compressionHeaderSize:])
+}
+
+// decode returns the marshaled mvccpb.KeyValue of a revision.
+func (c keyValueCodec) decode(v []byte) ([]byte, error) {
+	d, err := c.decrypt(v)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeKeyValue(d)
+}
+
+func (c keyValueCodec) unmarshal(v []byte, kv *mvccpb.KeyValue) error {
+	d, err := c.decode(v)
+	if err != nil {
+		return err
+	}
+	return kv.Unmarshal(d)
+}
@@ -22,6 +22,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap/zaptest"
 
 	"go.etcd.io/etcd/api/v3/mvccpb"
@@ -256,6 +257,226 @@ func testKVRangeLimit(t *testing.T, f rangeFunc) {
 	}
 }
 
+func TestKVIterate(t *testing.T) {
+	b, tmpPath := betesting.NewDefaultTmpBackend(t)
+	s := NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{})
+	defer cleanup(s, b, tmpPath)
+
+	kvs := put3TestKVs(s)
+
+	it, err := s.Iterate([]byte("foo"), []byte("foo3"), RangeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// writes after the creation of the iterator are not visible
+	s.Put([]byte("foo0"), []byte("bar0"), lease.NoLease)
+	s.DeleteRange([]byte("foo2"), nil)
+
+	if it.Rev() != 4 || it.Count() != 3 {
+		t.Fatalf("rev, count = %d, %d, want 4, 3", it.Rev(), it.Count())
+	}
+	var got []mvccpb.KeyValue
+	for _, n := range []int{2, 2} {
+		r, err := it.Next(context.TODO(), n, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, r...)
+	}
+	if !reflect.DeepEqual(got, kvs) {
+		t.Errorf("kvs = %+v, want %+v", got, kvs)
+	}
+	if r, err := it.Next(context.TODO(), 2, 0); err != nil || len(r) != 0 {
+		t.Errorf("next = %+v, %v, want no key-value pair", r, err)
+	}
+
+	// maxBytes stops once it has been reached, and the filter skips key-value pairs
+	it, err = s.Iterate([]byte("foo"), []byte("foo3"), RangeOptions{
+		Rev:    4,
+		Filter: func(kv *mvccpb.KeyValue) bool { return string(kv.Key) != "foo1" },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := it.Next(context.TODO(), 10, 1)
+	if err != nil || !reflect.DeepEqual(r, kvs[:1]) {
+		t.Errorf("next = %+v, %v, want %+v", r, err, kvs[:1])
+	}
+	r, err = it.Next(context.TODO(), 10, 0)
+	if err != nil || !reflect.DeepEqual(r, kvs[2:]) {
+		t.Errorf("next = %+v, %v, want %+v", r, err, kvs[2:])
+	}
+
+	// the iterator fails once its revision is compacted
+	it, err = s.Iterate([]byte("foo"), []byte("foo3"), RangeOptions{Rev: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.Compact(traceutil.TODO(), 5); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = it.Next(context.TODO(), 1, 0); err != ErrCompacted {
+		t.Errorf("next error = %v, want %v", err, ErrCompacted)
+	}
+	if _, err = s.Iterate([]byte("foo"), []byte("foo3"), RangeOptions{Rev: 4}); err != ErrCompacted {
+		t.Errorf("iterate error = %v, want %v", err, ErrCompacted)
+	}
+	if _, err = s.Iterate([]byte("foo"), []byte("foo3"), RangeOptions{Rev: 100}); err != ErrFutureRev {
+		t.Errorf("iterate error = %v, want %v", err, ErrFutureRev)
+	}
+}
+
+func TestKVHistory(t *testing.T) {
+	b, tmpPath := betesting.NewDefaultTmpBackend(t)
+	s := NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{})
+	defer cleanup(s, b, tmpPath)
+
+	s.Put([]byte("foo"), []byte("bar"), lease.NoLease)   // 2
+	s.Put([]byte("foo"), []byte("bar1"), lease.NoLease)  // 3
+	s.DeleteRange([]byte("foo"), nil)                    // 4
+	s.Put([]byte("foo1"), []byte("bar2"), lease.NoLease) // 5
+	s.Put([]byte("foo"), []byte("bar3"), lease.NoLease)  // 6
+	// 7
+	txn := s.Write(traceutil.TODO())
+	txn.Put([]byte("foo"), []byte("bar4"), lease.NoLease)
+	txn.Put([]byte("foo1"), []byte("bar5"), lease.NoLease)
+	txn.End()
+
+	kv := func(key, val string, create, mod, ver int64) mvccpb.KeyValue {
+		return mvccpb.KeyValue{Key: []byte(key), Value: []byte(val), CreateRevision: create, ModRevision: mod, Version: ver}
+	}
+	fooKVs := []mvccpb.KeyValue{
+		kv("foo", "bar", 2, 2, 1),
+		kv("foo", "bar1", 2, 3, 2),
+		{Key: []byte("foo"), ModRevision: 4},
+		kv("foo", "bar3", 6, 6, 1),
+		kv("foo", "bar4", 6, 7, 2),
+	}
+	rangeKVs := []mvccpb.KeyValue{
+		fooKVs[0], fooKVs[1], fooKVs[2],
+		kv("foo1", "bar2", 5, 5, 1),
+		fooKVs[3], fooKVs[4],
+		kv("foo1", "bar5", 5, 7, 2),
+	}
+
+	tests := []struct {
+		key, end []byte
+		ho       HistoryOptions
+
+		wkvs  []mvccpb.KeyValue
+		wmore bool
+	}{
+		{[]byte("foo"), nil, HistoryOptions{}, fooKVs, false},
+		{[]byte("foo"), nil, HistoryOptions{StartRev: 3, EndRev: 6}, fooKVs[1:4], false},
+		{[]byte("foo"), []byte("foo2"), HistoryOptions{}, rangeKVs, false},
+		{[]byte("foo"), []byte("foo2"), HistoryOptions{Limit: 2}, rangeKVs[:2], true},
+		// the revision 7 is not split
+		{[]byte("foo"), []byte("foo2"), HistoryOptions{Limit: 6}, rangeKVs, false},
+		{[]byte("foo"), []byte("foo2"), HistoryOptions{StartRev: 5, Limit: 2}, rangeKVs[3:5], true},
+		{[]byte("none"), nil, HistoryOptions{}, nil, false},
+	}
+	for i, tt := range tests {
+		r, err := s.History(context.TODO(), tt.key, tt.end, tt.ho)
+		if err != nil {
+			t.Fatalf("#%d: history error = %v", i, err)
+		}
+		if r.Rev != 7 {
+			t.Errorf("#%d: rev = %d, want 7", i, r.Rev)
+		}
+		if len(r.KVs) != 0 || len(tt.wkvs) != 0 {
+			if !reflect.DeepEqual(r.KVs, tt.wkvs) {
+				t.Errorf("#%d: kvs = %+v, want %+v", i, r.KVs, tt.wkvs)
+			}
+		}
+		if r.More != tt.wmore {
+			t.Errorf("#%d: more = %v, want %v", i, r.More, tt.wmore)
+		}
+	}
+
+	if _, err := s.History(context.TODO(), []byte("foo"), nil, HistoryOptions{EndRev: 100}); err != ErrFutureRev {
+		t.Errorf("history error = %v, want %v", err, ErrFutureRev)
+	}
+	// the history starts at the compact revision
+	if _, err := s.Compact(traceutil.TODO(), 5); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.History(context.TODO(), []byte("foo"), nil, HistoryOptions{StartRev: 4}); err != ErrCompacted {
+		t.Errorf("history error = %v, want %v", err, ErrCompacted)
+	}
+	r, err := s.History(context.TODO(), []byte("foo"), []byte("foo2"), HistoryOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.CompactRev != 5 || !reflect.DeepEqual(r.KVs, rangeKVs[3:]) {
+		t.Errorf("compact rev, kvs = %d, %+v, want 5, %+v", r.CompactRev, r.KVs, rangeKVs[3:])
+	}
+}
+
+func TestKVHistoryRetention(t *testing.T) {
+	b, tmpPath := betesting.NewDefaultTmpBackend(t)
+	s := NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{})
+	defer func() { cleanup(s, b, tmpPath) }()
+
+	for i := 0; i < 3; i++ {
+		s.Put([]byte("/config/a"), []byte(fmt.Sprint(i)), lease.NoLease) // 2, 5, 8
+		s.Put([]byte("/audit/a"), []byte(fmt.Sprint(i)), lease.NoLease)  // 3, 6, 9
+		s.Put([]byte("/other"), []byte(fmt.Sprint(i)), lease.NoLease)    // 4, 7, 10
+	}
+	rs := []Retention{
+		{Prefix: []byte("/audit/"), MinRevision: 6},
+		{Prefix: []byte("/config/"), Versions: 2},
+	}
+	done, err := s.CompactRetaining(traceutil.TODO(), 10, rs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-done
+
+	modRevs := func(s KV, key string) []int64 {
+		r, err := s.History(context.TODO(), []byte(key), nil, HistoryOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var revs []int64
+		for _, kv := range r.KVs {
+			revs = append(revs, kv.ModRevision)
+		}
+		return revs
+	}
+	check := func(s KV) {
+		assert.Equal(t, []int64{5, 8}, modRevs(s, "/config/a"))
+		assert.Equal(t, []int64{6, 9}, modRevs(s, "/audit/a"))
+		assert.Equal(t, []int64{10}, modRevs(s, "/other"))
+		_, err := s.History(context.TODO(), []byte("/other"), nil, HistoryOptions{StartRev: 2})
+		assert.Equal(t, ErrCompacted, err)
+		_, err = s.History(context.TODO(), []byte("/"), []byte("/p"), HistoryOptions{StartRev: 2})
+		assert.Equal(t, ErrCompacted, err)
+		r, err := s.History(context.TODO(), []byte("/config/"), []byte("/config0"), HistoryOptions{StartRev: 2})
+		assert.NoError(t, err)
+		assert.Len(t, r.KVs, 2)
+		rr, err := s.Range(context.TODO(), []byte("/"), []byte("/p"), RangeOptions{})
+		assert.NoError(t, err)
+		assert.Len(t, rr.KVs, 3)
+	}
+	check(s)
+
+	// the retained revisions and the retention rules are restored from the backend
+	s.Close()
+	s = NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{})
+	check(s)
+	assert.Equal(t, rs, s.retention)
+
+	// the deleted keys are not kept by their number of versions
+	s.DeleteRange([]byte("/config/a"), nil) // 11
+	done, err = s.Compact(traceutil.TODO(), 11)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-done
+	assert.Empty(t, modRevs(s, "/config/a"))
+	assert.Equal(t, []int64{6, 9}, modRevs(s, "/audit/a"))
+}
+
 func TestKVPutMultipleTimes(t *testing.T)    { testKVPutMultipleTimes(t, normalPutFunc) }
 func TestKVTxnPutMultipleTimes(t *testing.T) { testKVPutMultipleTimes(t, txnPutFunc) }
 
@@ -27,6 +27,7 @@ import (
 	"go.etcd.io/etcd/pkg/v3/traceutil"
 	"go.etcd.io/etcd/server/v3/lease"
 	"go.etcd.io/etcd/server/v3/storage/backend"
+	"go.etcd.io/etcd/server/v3/storage/encryption"
 	"go.etcd.io/etcd/server/v3/storage/schema"
 
 	"go.uber.org/zap"
@@ -53,6 +54,22 @@ var minimumBatchInterval = 10 * time.Millisecond
 type StoreConfig struct {
 	CompactionBatchLimit    int
 	CompactionSleepInterval time.Duration
+	// Compression compresses the revisions written to the key bucket; the
+	// revisions are read whether they are compressed or not.
+	Compression CompressionType
+	// CompressionMinSize is the size in bytes under which the revisions are
+	// written uncompressed, DefaultCompressionMinSize if 0.
+	CompressionMinSize int
+	// Cipher encrypts the revisions written to the key bucket if not nil.
+	// The revisions are read whether they are encrypted or not.
+	Cipher encryption.Cipher
+	// EventCacheRevisions is the number of latest revisions whose events are
+	// kept in memory to sync the unsynced watchers without reading the
+	// backend. The watch event cache is disabled if 0.
+	EventCacheRevisions int
+	// EventCacheBytes bounds the size of the events kept in the watch event
+	// cache, DefaultEventCacheBytes if 0.
+	EventCacheBytes int
 }
 
 type store struct {
@@ -60,6 +77,8 @@ type store struct {
 	WriteView
 
 	cfg StoreConfig
+	// codec encodes the revisions written to the key bucket.
+	codec keyValueCodec
 
 	// mu read locks for txns and write locks for non-txn store changes.
 	mu sync.RWMutex
@@ -77,6 +96,8 @@ type store struct {
 	currentRev int64
 	// compactMainRev is the main revision of the last compaction.
 	compactMainRev int64
+	// retention are the retention rules of the last compaction.
+	retention []Retention
 
 	fifoSched schedule.Scheduler
 
@@ -84,6 +105,15 @@ type store struct {
 
 	lg     *zap.Logger
 	hashes HashStorage
+
+	// changeHook is called with the changes of each write txn before it ends.
+	changeHook func(changes []mvccpb.KeyValue, prevSizes []int64)
+
+	// attachMu protects attachments.
+	attachMu sync.RWMutex
+	// attachments are the leases the keys are attached to by AttachLease in
+	// place of the lease of their current revision.
+	attachments map[string]attachment
 }
 
 // NewStore returns a new store. It is useful to create a store inside
@@ -98,8 +128,12 @@ func NewStore(lg *zap.Logger, b backend.Backend, le lease.Lessor, cfg StoreConfi
 	if cfg.CompactionSleepInterval == 0 {
 		cfg.CompactionSleepInterval = minimumBatchInterval
 	}
+	if cfg.CompressionMinSize == 0 {
+		cfg.CompressionMinSize = DefaultCompressionMinSize
+	}
 	s := &store{
 		cfg:     cfg,
+		codec:   keyValueCodec{compression: cfg.Compression, minSize: cfg.CompressionMinSize, cipher: cfg.Cipher},
 		b:       b,
 		kvindex: newTreeIndex(lg),
 
@@ -124,6 +158,8 @@ func NewStore(lg *zap.Logger, b backend.Backend, le lease.Lessor, cfg StoreConfi
 	tx := s.b.BatchTx()
 	tx.LockOutsideApply()
 	tx.UnsafeCreateBucket(schema.Key)
+	schema.UnsafeCreateKeyLeaseBucket(tx)
+	schema.UnsafeCreateDeleteCauseBucket(tx)
 	schema.UnsafeCreateMetaBucket(tx)
 	tx.Unlock()
 	s.b.ForceCommit()
@@ -187,18 +223,18 @@ func (s *store) hashByRev(rev int64) (hash KeyValueHash, currentRev int64, err e
 	if rev == 0 {
 		rev = currentRev
 	}
-	keep := s.kvindex.Keep(rev)
+	keep := s.kvindex.Keep(rev, s.retention)
 
 	tx := s.b.ReadTx()
 	tx.RLock()
 	defer tx.RUnlock()
 	s.mu.RUnlock()
-	hash, err = unsafeHashByRev(tx, compactRev, rev, keep)
+	hash, err = unsafeHashByRev(tx, s.codec, compactRev, rev, keep)
 	hashRevSec.Observe(time.Since(start).Seconds())
 	return hash, currentRev, err
 }
 
-func (s *store) updateCompactRev(rev int64) (<-chan struct{}, int64, error) {
+func (s *store) updateCompactRev(rev int64, rs []Retention) (<-chan struct{}, int64, error) {
 	s.revMu.Lock()
 	if rev <= s.compactMainRev {
 		ch := make(chan struct{})
@@ -213,8 +249,16 @@ func (s *store) updateCompactRev(rev int64) (<-chan struct{}, int64, error) {
 	}
 	compactMainRev := s.compactMainRev
 	s.compactMainRev = rev
+	prevRetention := s.retention
+	s.retention = rs
 
-	SetScheduledCompact(s.b.BatchTx(), rev)
+	tx := s.b.BatchTx()
+	tx.LockInsideApply()
+	UnsafeSetScheduledCompact(tx, rev)
+	if len(rs) > 0 || len(prevRetention) > 0 {
+		UnsafeSetScheduledCompactRetention(tx, rs)
+	}
+	tx.Unlock()
 	// ensure that desired compaction is persisted
 	// gofail: var compactBeforeCommitScheduledCompact struct{}
 	s.b.ForceCommit()
@@ -225,14 +269,14 @@ func (s *store) updateCompactRev(rev int64) (<-chan struct{}, int64, error) {
 	return nil, compactMainRev, nil
 }
 
-func (s *store) compact(trace *traceutil.Trace, rev, prevCompactRev int64) (<-chan struct{}, error) {
+func (s *store) compact(trace *traceutil.Trace, rev, prevCompactRev int64, rs []Retention) (<-chan struct{}, error) {
 	ch := make(chan struct{})
 	j := schedule.NewJob("kvstore_compact", func(ctx context.Context) {
 		if ctx.Err() != nil {
 			s.compactBarrier(ctx, ch)
 			return
 		}
-		hash, err := s.scheduleCompaction(rev, prevCompactRev)
+		hash, err := s.scheduleCompaction(rev, prevCompactRev, rs)
 		if err != nil {
 			s.lg.Warn("Failed compaction", zap.Error(err))
 			s.compactBarrier(context.TODO(), ch)
@@ -247,19 +291,26 @@ func (s *store) compact(trace *traceutil.Trace, rev, prevCompactRev int64) (<-ch
 	return ch, nil
 }
 
-func (s *store) compactLockfree(rev int64) (<-chan struct{}, error) {
-	ch, prevCompactRev, err := s.updateCompactRev(rev)
+func (s *store) compactLockfree(rev int64, rs []Retention) (<-chan struct{}, error) {
+	ch, prevCompactRev, err := s.updateCompactRev(rev, rs)
 	if err != nil {
 		return ch, err
 	}
 
-	return s.compact(traceutil.TODO(), rev, prevCompactRev)
+	return s.compact(traceutil.TODO(), rev, prevCompactRev, rs)
 }
 
 func (s *store) Compact(trace *traceutil.Trace, rev int64) (<-chan struct{}, error) {
+	s.mu.RLock()
+	rs := s.retention
+	s.mu.RUnlock()
+	return s.CompactRetaining(trace, rev, rs)
+}
+
+func (s *store) CompactRetaining(trace *traceutil.Trace, rev int64, rs []Retention) (<-chan struct{}, error) {
 	s.mu.Lock()
 
-	ch, prevCompactRev, err := s.updateCompactRev(rev)
+	ch, prevCompactRev, err := s.updateCompactRev(rev, rs)
 	trace.Step("check and update compact revision")
 	if err != nil {
 		s.mu.Unlock()
@@ -267,7 +318,7 @@ func (s *store) Compact(trace *traceutil.Trace, rev int64) (<-chan struct{}, err
 	}
 	s.mu.Unlock()
 
-	return s.compact(trace, rev, prevCompactRev)
+	return s.compact(trace, rev, prevCompactRev, rs)
 }
 
 func (s *store) Commit() {
@@ -276,6 +327,12 @@ func (s *store) Commit() {
 	s.b.ForceCommit()
 }
 
+func (s *store) SetChangeHook(hook func(changes []mvccpb.KeyValue, prevSizes []int64)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.changeHook = hook
+}
+
 func (s *store) Restore(b backend.Backend) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -313,6 +370,8 @@ func (s *store) restore() error {
 	tx := s.b.ReadTx()
 	tx.Lock()
 
+	var err error
+
 	finishedCompact, found := UnsafeReadFinishedCompact(tx)
 	if found {
 		s.revMu.Lock()
@@ -326,6 +385,11 @@ func (s *store) restore() error {
 		s.revMu.Unlock()
 	}
 	scheduledCompact, _ := UnsafeReadScheduledCompact(tx)
+	s.retention, err = UnsafeReadScheduledCompactRetention(tx)
+	if err != nil {
+		tx.Unlock()
+		return err
+	}
 	// index keys concurrently as they're loaded in from tx
 	keysGauge.Set(0)
 	rkvc, revc := restoreIntoIndex(s.lg, s.kvindex)
@@ -336,7 +400,7 @@ func (s *store) restore() error {
 		}
 		// rkvc blocks if the total pending keys exceeds the restore
 		// chunk size to keep keys from consuming too much memory.
-		restoreChunk(s.lg, rkvc, keys, vals, keyToLease)
+		restoreChunk(s.lg, s.codec, rkvc, keys, vals, keyToLease)
 		if len(keys) < restoreChunkKeys {
 			// partial set implies final set
 			break
@@ -365,6 +429,26 @@ func (s *store) restore() error {
 		scheduledCompact = 0
 	}
 
+	s.attachMu.Lock()
+	s.attachments = make(map[string]attachment)
+	err = schema.UnsafeForEachKeyLease(tx, func(key []byte, rev, id int64) {
+		// the attachment only holds until the key is written again
+		if modified, _, _, err := s.kvindex.Get(key, s.currentRev); err != nil || modified.main != rev {
+			return
+		}
+		s.attachments[string(key)] = attachment{rev: rev, id: lease.LeaseID(id)}
+		if lid := lease.LeaseID(id); lid != lease.NoLease {
+			keyToLease[string(key)] = lid
+		} else {
+			delete(keyToLease, string(key))
+		}
+	})
+	s.attachMu.Unlock()
+	if err != nil {
+		tx.Unlock()
+		return err
+	}
+
 	for key, lid := range keyToLease {
 		if s.le == nil {
 			tx.Unlock()
@@ -385,7 +469,7 @@ func (s *store) restore() error {
 	s.lg.Info("kvstore restored", zap.Int64("current-rev", s.currentRev))
 
 	if scheduledCompact != 0 {
-		if _, err := s.compactLockfree(scheduledCompact); err != nil {
+		if _, err := s.compactLockfree(scheduledCompact, s.retention); err != nil {
 			s.lg.Warn("compaction encountered error", zap.Error(err))
 		}
 
@@ -451,10 +535,10 @@ func restoreIntoIndex(lg *zap.Logger, idx index) (chan<- revKeyValue, <-chan int
 	return rkvc, revc
 }
 
-func restoreChunk(lg *zap.Logger, kvc chan<- revKeyValue, keys, vals [][]byte, keyToLease map[string]lease.LeaseID) {
+func restoreChunk(lg *zap.Logger, codec keyValueCodec, kvc chan<- revKeyValue, keys, vals [][]byte, keyToLease map[string]lease.LeaseID) {
 	for i, key := range keys {
 		rkv := revKeyValue{key: key}
-		if err := rkv.kv.Unmarshal(vals[i]); err != nil {
+		if err := codec.unmarshal(vals[i], &rkv.kv); err != nil {
 			lg.Fatal("failed to unmarshal mvccpb.KeyValue", zap.Error(err))
 		}
 		rkv.kstr = string(rkv.kv.Key)
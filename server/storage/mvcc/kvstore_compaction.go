@@ -24,9 +24,9 @@ import (
 	"go.etcd.io/etcd/server/v3/storage/schema"
 )
 
-func (s *store) scheduleCompaction(compactMainRev, prevCompactRev int64) (KeyValueHash, error) {
+func (s *store) scheduleCompaction(compactMainRev, prevCompactRev int64, rs []Retention) (KeyValueHash, error) {
 	totalStart := time.Now()
-	keep := s.kvindex.Compact(compactMainRev)
+	keep, retained := s.kvindex.Compact(compactMainRev, rs)
 	indexCompactionPauseMs.Observe(float64(time.Since(totalStart) / time.Millisecond))
 
 	totalStart = time.Now()
@@ -41,7 +41,19 @@ func (s *store) scheduleCompaction(compactMainRev, prevCompactRev int64) (KeyVal
 	batchNum := s.cfg.CompactionBatchLimit
 	batchTicker := time.NewTicker(s.cfg.CompactionSleepInterval)
 	defer batchTicker.Stop()
-	h := newKVHasher(prevCompactRev, compactMainRev, keep)
+	// the retained revisions are hashed as well, they are kept on every
+	// member as the retention rules are replicated with the compaction
+	hashed := keep
+	if len(retained) > 0 {
+		hashed = make(map[revision]struct{}, len(keep)+len(retained))
+		for rev := range keep {
+			hashed[rev] = struct{}{}
+		}
+		for rev := range retained {
+			hashed[rev] = struct{}{}
+		}
+	}
+	h := newKVHasher(s.codec, prevCompactRev, compactMainRev, hashed)
 	last := make([]byte, 8+1+8)
 	for {
 		var rev revision
@@ -53,8 +65,13 @@ func (s *store) scheduleCompaction(compactMainRev, prevCompactRev int64) (KeyVal
 		keys, values := tx.UnsafeRange(schema.Key, last, end, int64(batchNum))
 		for i := range keys {
 			rev = bytesToRev(keys[i])
-			if _, ok := keep[rev]; !ok {
+			_, ok := keep[rev]
+			_, rok := retained[rev]
+			if !ok && !rok {
 				tx.UnsafeDelete(schema.Key, keys[i])
+				if isTombstone(keys[i]) {
+					schema.UnsafeDeleteDeleteCause(tx, keys[i][:revBytesLen])
+				}
 				keyCompactions++
 			}
 			h.WriteKeyValue(keys[i], values[i])
@@ -84,7 +84,7 @@ func TestScheduleCompaction(t *testing.T) {
 		}
 		tx.Unlock()
 
-		_, err := s.scheduleCompaction(tt.rev, 0)
+		_, err := s.scheduleCompaction(tt.rev, 0, nil)
 		if err != nil {
 			t.Error(err)
 		}
@@ -0,0 +1,107 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvcc
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/server/v3/storage/schema"
+)
+
+func (s *store) History(ctx context.Context, key, end []byte, ho HistoryOptions) (*HistoryResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	s.revMu.RLock()
+	curRev, compactRev := s.currentRev, s.compactMainRev
+	s.revMu.RUnlock()
+
+	startRev, endRev := ho.StartRev, ho.EndRev
+	if endRev > curRev {
+		return nil, ErrFutureRev
+	}
+	if endRev <= 0 {
+		endRev = curRev
+	}
+	// the revisions of the keys under the prefix of a retention rule are
+	// read down to the ones kept by the compactions
+	retained := s.retained(key, end)
+	if startRev <= 0 {
+		startRev = compactRev
+		if startRev < 1 || retained {
+			startRev = 1
+		}
+	}
+	if startRev < compactRev && !retained {
+		return nil, ErrCompacted
+	}
+
+	// the key-value pairs written by the same revision are not split
+	revs, more := s.kvindex.History(key, end, startRev, endRev, ho.Limit)
+
+	tx := s.b.ConcurrentReadTx()
+	tx.RLock()
+	defer tx.RUnlock() // RUnlock signals the end of concurrentReadTx.
+
+	// the revisions read from the index stay in the backend as long as they
+	// are not compacted
+	s.revMu.RLock()
+	compactRev = s.compactMainRev
+	s.revMu.RUnlock()
+	if startRev < compactRev && !retained {
+		return nil, ErrCompacted
+	}
+
+	kvs := make([]mvccpb.KeyValue, 0, len(revs))
+	revBytes, endBytes := newRevBytes(), newRevBytes()
+	for _, revpair := range revs {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("history: context cancelled: %w", ctx.Err())
+		default:
+		}
+		// the range covers the tombstone mark
+		revToBytes(revpair, revBytes)
+		revToBytes(revision{main: revpair.main, sub: revpair.sub + 1}, endBytes)
+		ks, vs := tx.UnsafeRange(schema.Key, revBytes, endBytes, 0)
+		if len(vs) == 0 && retained && revpair.main <= compactRev {
+			// removed by a compaction since read from the index
+			continue
+		}
+		if len(vs) != 1 {
+			s.lg.Fatal(
+				"history failed to find revision pair",
+				zap.Int64("revision-main", revpair.main),
+				zap.Int64("revision-sub", revpair.sub),
+				zap.Int("len-values", len(vs)),
+			)
+		}
+		var kv mvccpb.KeyValue
+		if err := s.codec.unmarshal(vs[0], &kv); err != nil {
+			s.lg.Fatal(
+				"failed to unmarshal mvccpb.KeyValue",
+				zap.Error(err),
+			)
+		}
+		if isTombstone(ks[0]) {
+			kv.ModRevision = revpair.main
+		}
+		kvs = append(kvs, kv)
+	}
+	return &HistoryResult{KVs: kvs, Rev: curRev, CompactRev: compactRev, More: more}, nil
+}
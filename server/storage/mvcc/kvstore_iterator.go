@@ -0,0 +1,140 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvcc
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/server/v3/storage/schema"
+)
+
+// storeRangeIterator keeps the revisions of the keys in the range, which are
+// much smaller than the key-value pairs, and reads the key-value pairs from
+// the backend on demand.
+type storeRangeIterator struct {
+	s *store
+
+	curRev int64
+	rev    int64
+	count  int
+	revs   []revision
+	filter func(kv *mvccpb.KeyValue) bool
+}
+
+func (s *store) Iterate(key, end []byte, ro RangeOptions) (RangeIterator, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	s.revMu.RLock()
+	curRev, compactRev := s.currentRev, s.compactMainRev
+	s.revMu.RUnlock()
+
+	rev := ro.Rev
+	if rev > curRev {
+		return nil, ErrFutureRev
+	}
+	if rev <= 0 {
+		rev = curRev
+	}
+	if rev < compactRev {
+		return nil, ErrCompacted
+	}
+
+	it := &storeRangeIterator{s: s, curRev: curRev, rev: rev, filter: ro.Filter}
+	if ro.Count {
+		it.count = s.kvindex.CountRevisions(key, end, rev)
+		return it, nil
+	}
+	it.revs, it.count = s.kvindex.Revisions(key, end, rev, 0)
+	return it, nil
+}
+
+func (it *storeRangeIterator) Rev() int64 { return it.curRev }
+func (it *storeRangeIterator) Count() int { return it.count }
+
+func (it *storeRangeIterator) Next(ctx context.Context, n int, maxBytes int) ([]mvccpb.KeyValue, error) {
+	if len(it.revs) == 0 || n <= 0 {
+		return nil, nil
+	}
+
+	s := it.s
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	tx := s.b.ConcurrentReadTx()
+	tx.RLock()
+	defer tx.RUnlock() // RUnlock signals the end of concurrentReadTx.
+
+	// the revisions of the iterator stay in the backend as long as they are not compacted
+	s.revMu.RLock()
+	compactRev := s.compactMainRev
+	s.revMu.RUnlock()
+	if it.rev < compactRev {
+		return nil, ErrCompacted
+	}
+
+	// the keys are looked up in the attachments only if AttachLease attached any
+	s.attachMu.RLock()
+	attached := len(s.attachments) != 0
+	s.attachMu.RUnlock()
+
+	var (
+		kvs      []mvccpb.KeyValue
+		size     int
+		read     int
+		revBytes = newRevBytes()
+	)
+	for _, revpair := range it.revs {
+		if len(kvs) == n || (maxBytes > 0 && size >= maxBytes) {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("rangeIterator: context cancelled: %w", ctx.Err())
+		default:
+		}
+		read++
+		revToBytes(revpair, revBytes)
+		_, vs := tx.UnsafeRange(schema.Key, revBytes, nil, 0)
+		if len(vs) != 1 {
+			s.lg.Fatal(
+				"range iterator failed to find revision pair",
+				zap.Int64("revision-main", revpair.main),
+				zap.Int64("revision-sub", revpair.sub),
+				zap.Int64("revision-iterator", it.rev),
+				zap.Int("len-values", len(vs)),
+			)
+		}
+		var kv mvccpb.KeyValue
+		if err := s.codec.unmarshal(vs[0], &kv); err != nil {
+			s.lg.Fatal(
+				"failed to unmarshal mvccpb.KeyValue",
+				zap.Error(err),
+			)
+		}
+		if attached {
+			s.attachedLease(&kv)
+		}
+		if it.filter != nil && !it.filter(&kv) {
+			continue
+		}
+		kvs = append(kvs, kv)
+		size += len(vs[0])
+	}
+	it.revs = it.revs[read:]
+	return kvs, nil
+}
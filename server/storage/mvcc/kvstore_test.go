@@ -393,6 +393,7 @@ func TestStoreRestore(t *testing.T) {
 	}
 	b.tx.rangeRespc <- rangeResp{[][]byte{schema.FinishedCompactKeyName}, [][]byte{newTestRevBytes(revision{3, 0})}}
 	b.tx.rangeRespc <- rangeResp{[][]byte{schema.ScheduledCompactKeyName}, [][]byte{newTestRevBytes(revision{3, 0})}}
+	b.tx.rangeRespc <- rangeResp{nil, nil}
 
 	b.tx.rangeRespc <- rangeResp{[][]byte{putkey, delkey}, [][]byte{putkvb, delkvb}}
 	b.tx.rangeRespc <- rangeResp{nil, nil}
@@ -408,6 +409,7 @@ func TestStoreRestore(t *testing.T) {
 	wact := []testutil.Action{
 		{Name: "range", Params: []interface{}{schema.Meta, schema.FinishedCompactKeyName, []byte(nil), int64(0)}},
 		{Name: "range", Params: []interface{}{schema.Meta, schema.ScheduledCompactKeyName, []byte(nil), int64(0)}},
+		{Name: "range", Params: []interface{}{schema.Meta, schema.ScheduledCompactRetentionKeyName, []byte(nil), int64(0)}},
 		{Name: "range", Params: []interface{}{schema.Key, newTestRevBytes(revision{1, 0}), newTestRevBytes(revision{math.MaxInt64, math.MaxInt64}), int64(restoreChunkKeys)}},
 	}
 	if g := b.tx.Action(); !reflect.DeepEqual(g, wact) {
@@ -701,6 +703,62 @@ func TestTxnPut(t *testing.T) {
 	}
 }
 
+// TestStoreAttachLease ensures AttachLease changes the lease of the keys
+// without writing a new revision, until the keys are written again.
+func TestStoreAttachLease(t *testing.T) {
+	b, tmpPath := betesting.NewDefaultTmpBackend(t)
+	s := NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{})
+	defer os.Remove(tmpPath)
+
+	s.Put([]byte("a/1"), []byte("v"), lease.NoLease)
+	s.Put([]byte("a/2"), []byte("v"), lease.NoLease)
+	s.Put([]byte("b"), []byte("v"), lease.NoLease)
+
+	txn := s.Write(traceutil.TODO())
+	if n := txn.AttachLease([]byte("a/"), []byte("a0"), 5); n != 2 {
+		t.Errorf("attached = %d, want 2", n)
+	}
+	txn.End()
+	if rev := s.Rev(); rev != 4 {
+		t.Errorf("rev = %d, want 4", rev)
+	}
+
+	checkLeases := func(s *store, want map[string]int64) {
+		t.Helper()
+		r, err := s.Range(context.TODO(), []byte("a"), []byte("c"), RangeOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make(map[string]int64)
+		for _, kv := range r.KVs {
+			got[string(kv.Key)] = kv.Lease
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("leases = %v, want %v", got, want)
+		}
+	}
+	checkLeases(s, map[string]int64{"a/1": 5, "a/2": 5, "b": 0})
+
+	// the attachments are restored
+	s.Commit()
+	s.Close()
+	s = NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{})
+	checkLeases(s, map[string]int64{"a/1": 5, "a/2": 5, "b": 0})
+
+	// writing a key drops its attachment
+	s.Put([]byte("a/1"), []byte("v2"), lease.NoLease)
+	s.DeleteRange([]byte("a/2"), nil)
+	checkLeases(s, map[string]int64{"a/1": 0, "b": 0})
+	s.Commit()
+	s.Close()
+	s = NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{})
+	defer cleanup(s, b, tmpPath)
+	checkLeases(s, map[string]int64{"a/1": 0, "b": 0})
+	if len(s.attachments) != 0 {
+		t.Errorf("attachments = %v, want none", s.attachments)
+	}
+}
+
 // TestConcurrentReadNotBlockingWrite ensures Read does not blocking Write after its creation
 func TestConcurrentReadNotBlockingWrite(t *testing.T) {
 	b, tmpPath := betesting.NewDefaultTmpBackend(t)
@@ -974,6 +1032,7 @@ func (b *fakeBackend) OpenReadTxN() int64
 func (b *fakeBackend) Snapshot() backend.Snapshot                                 { return nil }
 func (b *fakeBackend) ForceCommit()                                               {}
 func (b *fakeBackend) Defrag() error                                              { return nil }
+func (b *fakeBackend) DefragOnline() error                                        { return nil }
 func (b *fakeBackend) Close() error                                               { return nil }
 func (b *fakeBackend) SetTxPostLockInsideApplyHook(func())                        {}
 
@@ -1014,6 +1073,10 @@ func (i *fakeIndex) CountRevisions(key, end []byte, atRev int64) int {
 	return len(rev)
 }
 
+func (i *fakeIndex) History(key, end []byte, startRev, endRev, limit int64) ([]revision, bool) {
+	return i.RangeSince(key, end, startRev), false
+}
+
 func (i *fakeIndex) Get(key []byte, atRev int64) (rev, created revision, ver int64, err error) {
 	i.Recorder.Record(testutil.Action{Name: "get", Params: []interface{}{key, atRev}})
 	r := <-i.indexGetRespc
@@ -1036,11 +1099,11 @@ func (i *fakeIndex) RangeSince(key, end []byte, rev int64) []revision {
 	r := <-i.indexRangeEventsRespc
 	return r.revs
 }
-func (i *fakeIndex) Compact(rev int64) map[revision]struct{} {
+func (i *fakeIndex) Compact(rev int64, rs []Retention) (map[revision]struct{}, map[revision]struct{}) {
 	i.Recorder.Record(testutil.Action{Name: "compact", Params: []interface{}{rev}})
-	return <-i.indexCompactRespc
+	return <-i.indexCompactRespc, nil
 }
-func (i *fakeIndex) Keep(rev int64) map[revision]struct{} {
+func (i *fakeIndex) Keep(rev int64, rs []Retention) map[revision]struct{} {
 	i.Recorder.Record(testutil.Action{Name: "keep", Params: []interface{}{rev}})
 	return <-i.indexCompactRespc
 }
@@ -80,7 +80,12 @@ func (tr *storeTxnRead) rangeKeys(ctx context.Context, key, end []byte, curRev i
 		tr.trace.Step("count revisions from in-memory index tree")
 		return &RangeResult{KVs: nil, Count: total, Rev: curRev}, nil
 	}
-	revpairs, total := tr.s.kvindex.Revisions(key, end, rev, int(ro.Limit))
+	indexLimit := int(ro.Limit)
+	if ro.Filter != nil {
+		// the filter needs the values, so the limit is applied while reading the backend
+		indexLimit = 0
+	}
+	revpairs, total := tr.s.kvindex.Revisions(key, end, rev, indexLimit)
 	tr.trace.Step("range keys from in-memory index tree")
 	if len(revpairs) == 0 {
 		return &RangeResult{KVs: nil, Count: total, Rev: curRev}, nil
@@ -93,7 +98,15 @@ func (tr *storeTxnRead) rangeKeys(ctx context.Context, key, end []byte, curRev i
 
 	kvs := make([]mvccpb.KeyValue, limit)
 	revBytes := newRevBytes()
-	for i, revpair := range revpairs[:len(kvs)] {
+	// the keys are looked up in the attachments only if AttachLease attached any
+	tr.s.attachMu.RLock()
+	attached := len(tr.s.attachments) != 0
+	tr.s.attachMu.RUnlock()
+	n := 0
+	for _, revpair := range revpairs {
+		if n == len(kvs) {
+			break
+		}
 		select {
 		case <-ctx.Done():
 			return nil, fmt.Errorf("rangeKeys: context cancelled: %w", ctx.Err())
@@ -115,13 +128,23 @@ func (tr *storeTxnRead) rangeKeys(ctx context.Context, key, end []byte, curRev i
 				zap.Int("len-values", len(vs)),
 			)
 		}
-		if err := kvs[i].Unmarshal(vs[0]); err != nil {
+		if err := tr.s.codec.unmarshal(vs[0], &kvs[n]); err != nil {
 			tr.s.lg.Fatal(
 				"failed to unmarshal mvccpb.KeyValue",
 				zap.Error(err),
 			)
 		}
+		if attached {
+			tr.s.attachedLease(&kvs[n])
+		}
+		if ro.Filter != nil && !ro.Filter(&kvs[n]) {
+			// reset the slot so that the next unmarshal does not inherit unset fields
+			kvs[n] = mvccpb.KeyValue{}
+			continue
+		}
+		n++
 	}
+	kvs = kvs[:n]
 	tr.trace.Step("range keys from bolt db")
 	return &RangeResult{KVs: kvs, Count: total, Rev: curRev}, nil
 }
@@ -137,6 +160,14 @@ type storeTxnWrite struct {
 	// beginRev is the revision where the txn begins; it will write to the next revision.
 	beginRev int64
 	changes  []mvccpb.KeyValue
+	// prevSizes holds the size of the key and value each change replaces, or
+	// -1 if the key did not exist. It is only recorded for the change hook.
+	prevSizes []int64
+
+	// deleteCause and deleteLease are recorded apart from the tombstones of
+	// the deletes
+	deleteCause mvccpb.Event_DeleteCause
+	deleteLease lease.LeaseID
 }
 
 func (s *store) Write(trace *traceutil.Trace) TxnWrite {
@@ -177,6 +208,9 @@ func (tw *storeTxnWrite) Put(key, value []byte, lease lease.LeaseID) int64 {
 func (tw *storeTxnWrite) End() {
 	// only update index if the txn modifies the mvcc state.
 	if len(tw.changes) != 0 {
+		if tw.s.changeHook != nil {
+			tw.s.changeHook(tw.changes, tw.prevSizes)
+		}
 		// hold revMu lock to prevent new read txns from opening until writeback.
 		tw.s.revMu.Lock()
 		tw.s.currentRev++
@@ -195,11 +229,16 @@ func (tw *storeTxnWrite) put(key, value []byte, leaseID lease.LeaseID) {
 
 	// if the key exists before, use its previous created and
 	// get its previous leaseID
-	_, created, ver, err := tw.s.kvindex.Get(key, rev)
+	modified, created, ver, err := tw.s.kvindex.Get(key, rev)
+	prevSize := int64(-1)
 	if err == nil {
 		c = created.main
 		oldLease = tw.s.le.GetLease(lease.LeaseItem{Key: string(key)})
 		tw.trace.Step("get key's previous created_revision and leaseID")
+		tw.dropAttachment(key)
+		if tw.s.changeHook != nil {
+			prevSize = tw.revisionSize(modified)
+		}
 	}
 	ibytes := newRevBytes()
 	idxRev := revision{main: rev, sub: int64(len(tw.changes))}
@@ -224,9 +263,18 @@ func (tw *storeTxnWrite) put(key, value []byte, leaseID lease.LeaseID) {
 	}
 
 	tw.trace.Step("marshal mvccpb.KeyValue")
+	if d, err = tw.s.codec.encode(d); err != nil {
+		tw.storeTxnRead.s.lg.Fatal(
+			"failed to encode mvccpb.KeyValue",
+			zap.Error(err),
+		)
+	}
 	tw.tx.UnsafeSeqPut(schema.Key, ibytes, d)
 	tw.s.kvindex.Put(key, idxRev)
 	tw.changes = append(tw.changes, kv)
+	if tw.s.changeHook != nil {
+		tw.prevSizes = append(tw.prevSizes, prevSize)
+	}
 	tw.trace.Step("store kv pair into bolt db")
 
 	if oldLease == leaseID {
@@ -274,10 +322,21 @@ func (tw *storeTxnWrite) deleteRange(key, end []byte) int64 {
 }
 
 func (tw *storeTxnWrite) delete(key []byte) {
+	if tw.s.changeHook != nil {
+		prevSize := int64(-1)
+		if modified, _, _, err := tw.s.kvindex.Get(key, tw.beginRev+1); err == nil {
+			prevSize = tw.revisionSize(modified)
+		}
+		tw.prevSizes = append(tw.prevSizes, prevSize)
+	}
+
 	ibytes := newRevBytes()
 	idxRev := revision{main: tw.beginRev + 1, sub: int64(len(tw.changes))}
 	revToBytes(idxRev, ibytes)
 
+	if tw.deleteCause != mvccpb.REQUEST {
+		schema.UnsafePutDeleteCause(tw.tx, ibytes, tw.deleteCause, int64(tw.deleteLease))
+	}
 	ibytes = appendMarkTombstone(tw.storeTxnRead.s.lg, ibytes)
 
 	kv := mvccpb.KeyValue{Key: key}
@@ -289,6 +348,12 @@ func (tw *storeTxnWrite) delete(key []byte) {
 			zap.Error(err),
 		)
 	}
+	if d, err = tw.s.codec.encode(d); err != nil {
+		tw.storeTxnRead.s.lg.Fatal(
+			"failed to encode mvccpb.KeyValue",
+			zap.Error(err),
+		)
+	}
 
 	tw.tx.UnsafeSeqPut(schema.Key, ibytes, d)
 	err = tw.s.kvindex.Tombstone(key, idxRev)
@@ -299,7 +364,10 @@ func (tw *storeTxnWrite) delete(key []byte) {
 			zap.Error(err),
 		)
 	}
+	// the change of a delete caused by a lease holds the ID of the lease
+	kv.Lease = int64(tw.deleteLease)
 	tw.changes = append(tw.changes, kv)
+	tw.dropAttachment(key)
 
 	item := lease.LeaseItem{Key: string(key)}
 	leaseID := tw.s.le.GetLease(item)
@@ -316,3 +384,98 @@ func (tw *storeTxnWrite) delete(key []byte) {
 }
 
 func (tw *storeTxnWrite) Changes() []mvccpb.KeyValue { return tw.changes }
+
+// attachment is a lease a key is attached to in place of the lease of its
+// revision rev.
+type attachment struct {
+	rev int64
+	id  lease.LeaseID
+}
+
+func (tw *storeTxnWrite) AttachLease(key, end []byte, id lease.LeaseID) int64 {
+	rrev := tw.beginRev
+	if len(tw.changes) > 0 {
+		rrev++
+	}
+	keys, revs := tw.s.kvindex.Range(key, end, rrev)
+	for i := range keys {
+		tw.attachLease(keys[i], revs[i].main, id)
+	}
+	return int64(len(keys))
+}
+
+func (tw *storeTxnWrite) attachLease(key []byte, rev int64, id lease.LeaseID) {
+	item := lease.LeaseItem{Key: string(key)}
+	oldLease := tw.s.le.GetLease(item)
+	if oldLease == id {
+		return
+	}
+	if oldLease != lease.NoLease {
+		if err := tw.s.le.Detach(oldLease, []lease.LeaseItem{item}); err != nil {
+			tw.storeTxnRead.s.lg.Error(
+				"failed to detach old lease from a key",
+				zap.Error(err),
+			)
+		}
+	}
+	if id != lease.NoLease {
+		if err := tw.s.le.Attach(id, []lease.LeaseItem{item}); err != nil {
+			panic("unexpected error from lease Attach")
+		}
+	}
+
+	schema.UnsafePutKeyLease(tw.tx, key, rev, int64(id))
+	tw.s.attachMu.Lock()
+	tw.s.attachments[string(key)] = attachment{rev: rev, id: id}
+	tw.s.attachMu.Unlock()
+}
+
+// dropAttachment drops the attachment of a key written at a new revision.
+func (tw *storeTxnWrite) dropAttachment(key []byte) {
+	tw.s.attachMu.RLock()
+	_, ok := tw.s.attachments[string(key)]
+	tw.s.attachMu.RUnlock()
+	if !ok {
+		return
+	}
+	schema.UnsafeDeleteKeyLease(tw.tx, key)
+	tw.s.attachMu.Lock()
+	delete(tw.s.attachments, string(key))
+	tw.s.attachMu.Unlock()
+}
+
+// revisionSize returns the size of the key and value written at the revision.
+func (tw *storeTxnWrite) revisionSize(rev revision) int64 {
+	revBytes := newRevBytes()
+	revToBytes(rev, revBytes)
+	_, vs := tw.tx.UnsafeRange(schema.Key, revBytes, nil, 0)
+	if len(vs) != 1 {
+		tw.s.lg.Fatal(
+			"failed to find revision",
+			zap.Int64("revision-main", rev.main),
+			zap.Int64("revision-sub", rev.sub),
+		)
+	}
+	var kv mvccpb.KeyValue
+	if err := tw.s.codec.unmarshal(vs[0], &kv); err != nil {
+		tw.s.lg.Fatal(
+			"failed to unmarshal mvccpb.KeyValue",
+			zap.Error(err),
+		)
+	}
+	return int64(len(kv.Key) + len(kv.Value))
+}
+
+// attachedLease sets the lease of kv to the lease it is attached to by
+// AttachLease, if any.
+func (s *store) attachedLease(kv *mvccpb.KeyValue) {
+	s.attachMu.RLock()
+	if a, ok := s.attachments[string(kv.Key)]; ok && a.rev == kv.ModRevision {
+		kv.Lease = int64(a.id)
+	}
+	s.attachMu.RUnlock()
+}
+
+func (tw *storeTxnWrite) SetDeleteCause(cause mvccpb.Event_DeleteCause, id lease.LeaseID) {
+	tw.deleteCause, tw.deleteLease = cause, id
+}
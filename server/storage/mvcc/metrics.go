@@ -101,6 +101,34 @@ var (
 			Help:      "Total number of pending events to be sent.",
 		})
 
+	eventCacheRequestsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "etcd_debugging",
+			Subsystem: "mvcc",
+			Name:      "watch_event_cache_requests_total",
+			Help:      "Total number of syncs of unsynced watchers served by the watch event cache (result=hit) or by the backend (result=miss).",
+		},
+		[]string{"result"},
+	)
+	eventCacheHitCounter  = eventCacheRequestsCounter.WithLabelValues("hit")
+	eventCacheMissCounter = eventCacheRequestsCounter.WithLabelValues("miss")
+
+	eventCacheRevisionsGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "etcd_debugging",
+			Subsystem: "mvcc",
+			Name:      "watch_event_cache_revisions",
+			Help:      "Number of revisions whose events are kept in the watch event cache.",
+		})
+
+	eventCacheBytesGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "etcd_debugging",
+			Subsystem: "mvcc",
+			Name:      "watch_event_cache_size_bytes",
+			Help:      "Size in bytes of the events kept in the watch event cache.",
+		})
+
 	indexCompactionPauseMs = prometheus.NewHistogram(
 		prometheus.HistogramOpts{
 			Namespace: "etcd_debugging",
@@ -278,6 +306,9 @@ func init() {
 	prometheus.MustRegister(slowWatcherGauge)
 	prometheus.MustRegister(totalEventsCounter)
 	prometheus.MustRegister(pendingEventsGauge)
+	prometheus.MustRegister(eventCacheRequestsCounter)
+	prometheus.MustRegister(eventCacheRevisionsGauge)
+	prometheus.MustRegister(eventCacheBytesGauge)
 	prometheus.MustRegister(indexCompactionPauseMs)
 	prometheus.MustRegister(dbCompactionPauseMs)
 	prometheus.MustRegister(dbCompactionTotalMs)
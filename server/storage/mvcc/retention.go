@@ -0,0 +1,82 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvcc
+
+import (
+	"bytes"
+	"time"
+)
+
+// RetentionRule keeps the revisions of the keys under a prefix from being
+// removed by compactions.
+type RetentionRule struct {
+	// Prefix is the prefix of the keys the rule applies to.
+	Prefix string
+	// Versions is the number of latest revisions of each key, including the
+	// deletions, kept by compactions.
+	Versions int64
+	// Duration is how long the revisions of each key are kept by
+	// compactions.
+	Duration time.Duration
+}
+
+// Retention is a retention rule resolved for a compaction. The retentions
+// are replicated with the compactions, so that every member keeps the same
+// revisions whatever its own rules.
+type Retention struct {
+	// Prefix is the prefix of the keys the rule applies to.
+	Prefix []byte
+	// Versions is the number of latest revisions of each key, including the
+	// deletions, kept by the compaction. The revisions of the keys deleted at
+	// or before the compaction are not kept by their number.
+	Versions int64
+	// MinRevision is the oldest main revision kept by the compaction, or 0 if
+	// the rule does not keep the revisions by age.
+	MinRevision int64
+}
+
+// retained returns whether the revisions of the given key range are kept by
+// the retentions of the latest compaction, i.e. whether the range is under
+// the prefix of a rule.
+func (s *store) retained(key, end []byte) bool {
+	for _, r := range s.retention {
+		if !bytes.HasPrefix(key, r.Prefix) {
+			continue
+		}
+		if end == nil {
+			return true
+		}
+		// an empty end is the end of the keyspace
+		pend := prefixEnd(r.Prefix)
+		if pend == nil || (len(end) > 0 && bytes.Compare(end, pend) <= 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// prefixEnd returns the end of the range of the keys with the given
+// prefix, or nil if there is none.
+func prefixEnd(prefix []byte) []byte {
+	end := make([]byte, len(prefix))
+	copy(end, prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
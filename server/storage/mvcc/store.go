@@ -15,6 +15,9 @@
 package mvcc
 
 import (
+	"encoding/binary"
+	"fmt"
+
 	"go.etcd.io/etcd/server/v3/storage/backend"
 	"go.etcd.io/etcd/server/v3/storage/schema"
 )
@@ -58,3 +61,65 @@ func UnsafeSetFinishedCompact(tx backend.BatchTx, value int64) {
 	revToBytes(revision{main: value}, rbytes)
 	tx.UnsafePut(schema.Meta, schema.FinishedCompactKeyName, rbytes)
 }
+
+// UnsafeReadScheduledCompactRetention reads the retention rules of the
+// scheduled compaction.
+func UnsafeReadScheduledCompactRetention(tx backend.ReadTx) ([]Retention, error) {
+	_, vs := tx.UnsafeRange(schema.Meta, schema.ScheduledCompactRetentionKeyName, nil, 0)
+	if len(vs) == 0 {
+		return nil, nil
+	}
+	return decodeRetention(vs[0])
+}
+
+// UnsafeSetScheduledCompactRetention saves the retention rules of the
+// scheduled compaction, so that it is resumed with them.
+func UnsafeSetScheduledCompactRetention(tx backend.BatchTx, rs []Retention) {
+	if len(rs) == 0 {
+		tx.UnsafeDelete(schema.Meta, schema.ScheduledCompactRetentionKeyName)
+		return
+	}
+	tx.UnsafePut(schema.Meta, schema.ScheduledCompactRetentionKeyName, encodeRetention(rs))
+}
+
+// retentionEncodingVersion is the version of the encoding of the retention
+// rules, written as their first byte.
+const retentionEncodingVersion = 1
+
+// encodeRetention encodes each rule as the varint length of its prefix, the
+// prefix, and the varints of its versions and minimum revision.
+func encodeRetention(rs []Retention) []byte {
+	b := []byte{retentionEncodingVersion}
+	for _, r := range rs {
+		b = binary.AppendUvarint(b, uint64(len(r.Prefix)))
+		b = append(b, r.Prefix...)
+		b = binary.AppendVarint(b, r.Versions)
+		b = binary.AppendVarint(b, r.MinRevision)
+	}
+	return b
+}
+
+func decodeRetention(b []byte) ([]Retention, error) {
+	if len(b) == 0 || b[0] != retentionEncodingVersion {
+		return nil, fmt.Errorf("unknown retention encoding")
+	}
+	var rs []Retention
+	for b = b[1:]; len(b) > 0; {
+		n, l := binary.Uvarint(b)
+		if l <= 0 || uint64(len(b)-l) < n {
+			return nil, fmt.Errorf("malformed retention prefix")
+		}
+		r := Retention{Prefix: append([]byte{}, b[l:l+int(n)]...)}
+		b = b[l+int(n):]
+		if r.Versions, l = binary.Varint(b); l <= 0 {
+			return nil, fmt.Errorf("malformed retention versions")
+		}
+		b = b[l:]
+		if r.MinRevision, l = binary.Varint(b); l <= 0 {
+			return nil, fmt.Errorf("malformed retention min revision")
+		}
+		b = b[l:]
+		rs = append(rs, r)
+	}
+	return rs, nil
+}
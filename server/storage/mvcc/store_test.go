@@ -111,3 +111,45 @@ func TestFinishedCompact(t *testing.T) {
 		})
 	}
 }
+
+// TestScheduledCompactRetention ensures that UnsafeSetScheduledCompactRetention&UnsafeReadScheduledCompactRetention work well together.
+func TestScheduledCompactRetention(t *testing.T) {
+	tcs := []struct {
+		name  string
+		value []Retention
+	}{
+		{
+			name: "none",
+		},
+		{
+			name: "rules",
+			value: []Retention{
+				{Prefix: []byte{}, Versions: 1},
+				{Prefix: []byte("/audit/"), MinRevision: math.MaxInt64},
+				{Prefix: []byte("/config/"), Versions: 3, MinRevision: 10},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			lg := zaptest.NewLogger(t)
+			be, tmpPath := betesting.NewTmpBackend(t, time.Microsecond, 10)
+			tx := be.BatchTx()
+			if tx == nil {
+				t.Fatal("batch tx is nil")
+			}
+			tx.Lock()
+			tx.UnsafeCreateBucket(schema.Meta)
+			UnsafeSetScheduledCompactRetention(tx, tc.value)
+			tx.Unlock()
+			be.ForceCommit()
+			be.Close()
+
+			b := backend.NewDefaultBackend(lg, tmpPath)
+			defer b.Close()
+			v, err := UnsafeReadScheduledCompactRetention(b.BatchTx())
+			assert.NoError(t, err)
+			assert.Equal(t, tc.value, v)
+		})
+	}
+}
@@ -15,6 +15,7 @@
 package mvcc
 
 import (
+	"context"
 	"sync"
 	"time"
 
@@ -36,10 +37,14 @@ var (
 
 	// maxWatchersPerSync is the number of watchers to sync in a single batch
 	maxWatchersPerSync = 512
+
+	// initialStatePageSize is the number of key-value pairs of the initial
+	// state of a watcher sent in a single response
+	initialStatePageSize = 1000
 )
 
 type watchable interface {
-	watch(key, end []byte, startRev int64, id WatchID, ch chan<- WatchResponse, fcs ...FilterFunc) (*watcher, cancelFunc)
+	watch(key, end []byte, startRev int64, id WatchID, ch chan<- WatchResponse, opts WatchOptions) (*watcher, cancelFunc)
 	progress(w *watcher)
 	rev() int64
 }
@@ -62,6 +67,9 @@ type watchableStore struct {
 	// The key of the map is the key that the watcher watches on.
 	synced watcherGroup
 
+	// eventCache holds the events of the latest revisions if not nil.
+	eventCache *eventCache
+
 	stopc chan struct{}
 	wg    sync.WaitGroup
 }
@@ -85,6 +93,9 @@ func newWatchableStore(lg *zap.Logger, b backend.Backend, le lease.Lessor, cfg S
 		synced:   newWatcherGroup(),
 		stopc:    make(chan struct{}),
 	}
+	if cfg.EventCacheRevisions > 0 {
+		s.eventCache = newEventCache(cfg.EventCacheRevisions, cfg.EventCacheBytes)
+	}
 	s.store.ReadView = &readView{s}
 	s.store.WriteView = &writeView{s}
 	if s.le != nil {
@@ -113,14 +124,19 @@ func (s *watchableStore) NewWatchStream() WatchStream {
 	}
 }
 
-func (s *watchableStore) watch(key, end []byte, startRev int64, id WatchID, ch chan<- WatchResponse, fcs ...FilterFunc) (*watcher, cancelFunc) {
+func (s *watchableStore) watch(key, end []byte, startRev int64, id WatchID, ch chan<- WatchResponse, opts WatchOptions) (*watcher, cancelFunc) {
 	wa := &watcher{
-		key:    key,
-		end:    end,
-		minRev: startRev,
-		id:     id,
-		ch:     ch,
-		fcs:    fcs,
+		key:      key,
+		end:      end,
+		minRev:   startRev,
+		id:       id,
+		ch:       ch,
+		fcs:      opts.Filters,
+		coalesce: opts.Coalesce,
+	}
+
+	if opts.InitialState {
+		return s.watchInitialState(wa, startRev)
 	}
 
 	s.mu.Lock()
@@ -144,6 +160,72 @@ func (s *watchableStore) watch(key, end []byte, startRev int64, id WatchID, ch c
 	return wa, func() { s.cancelWatcher(wa) }
 }
 
+// watchInitialState adds a watcher whose first responses are the initial
+// state of its range as of startRev - 1. The initial state is sent in pages as
+// a victim, so that it is retried while the watcher channel is blocked and the
+// following pages are loaded once the previous one is sent, after which the
+// watcher is synced from startRev on.
+func (s *watchableStore) watchInitialState(wa *watcher, startRev int64) (*watcher, cancelFunc) {
+	rev, evs, next, err := s.initialState(wa.key, wa.end, startRev)
+	wa.minRev = rev + 1
+
+	s.mu.Lock()
+	slowWatcherGauge.Inc()
+	if err != nil {
+		// the initial state is compacted; the next sync cancels the watcher
+		wa.minRev = rev
+		s.unsynced.add(wa)
+	} else {
+		wa.victim = true
+		s.addVictim(watcherBatch{wa: &eventBatch{evs: evs, initialState: true, initialStateNext: next}})
+	}
+	s.mu.Unlock()
+
+	watcherGauge.Inc()
+
+	return wa, func() { s.cancelWatcher(wa) }
+}
+
+// initialState returns the revision startRev - 1, or the current revision if
+// startRev <= 0, and the first page of the initial state of the range as of
+// it.
+func (s *watchableStore) initialState(key, end []byte, startRev int64) (rev int64, evs []mvccpb.Event, next []byte, err error) {
+	rev = startRev - 1
+	if startRev <= 0 {
+		s.store.revMu.RLock()
+		rev = s.store.currentRev
+		s.store.revMu.RUnlock()
+	}
+	if rev <= 0 {
+		// nothing was written before the first revision
+		return 0, nil, nil, nil
+	}
+	evs, next, err = s.initialStatePage(key, end, rev)
+	return rev, evs, next, err
+}
+
+// initialStatePage returns the put events of at most initialStatePageSize
+// key-value pairs of the range from key as of rev, and the key the next page
+// starts at, or nil if the page is the last one.
+func (s *watchableStore) initialStatePage(key, end []byte, rev int64) ([]mvccpb.Event, []byte, error) {
+	txn := s.store.Read(ConcurrentReadTxMode, traceutil.TODO())
+	defer txn.End()
+	r, err := txn.Range(context.TODO(), key, end, RangeOptions{Rev: rev, Limit: int64(initialStatePageSize) + 1})
+	if err != nil {
+		return nil, nil, err
+	}
+	var next []byte
+	if len(r.KVs) > initialStatePageSize {
+		next = r.KVs[initialStatePageSize].Key
+		r.KVs = r.KVs[:initialStatePageSize]
+	}
+	evs := make([]mvccpb.Event, len(r.KVs))
+	for i := range r.KVs {
+		evs[i] = mvccpb.Event{Type: mvccpb.PUT, Kv: &r.KVs[i]}
+	}
+	return evs, next, nil
+}
+
 // cancelWatcher removes references of the watcher from the watchableStore
 func (s *watchableStore) cancelWatcher(wa *watcher) {
 	for {
@@ -198,6 +280,10 @@ func (s *watchableStore) Restore(b backend.Backend) error {
 	if err != nil {
 		return err
 	}
+	if s.eventCache != nil {
+		// the events of the restored revisions were not seen
+		s.eventCache.reset()
+	}
 
 	for wa := range s.synced.watchers {
 		wa.restore = true
@@ -282,7 +368,14 @@ func (s *watchableStore) moveVictims() (moved int) {
 		for w, eb := range wb {
 			// watcher has observed the store up to, but not including, w.minRev
 			rev := w.minRev - 1
-			if w.send(WatchResponse{WatchID: w.id, Events: eb.evs, Revision: rev}) {
+			wr := WatchResponse{
+				WatchID:          w.id,
+				Events:           eb.evs,
+				Revision:         rev,
+				InitialState:     eb.initialState,
+				InitialStateDone: eb.initialState && eb.initialStateNext == nil,
+			}
+			if w.send(wr) {
 				pendingEventsGauge.Add(float64(len(eb.evs)))
 			} else {
 				if newVictim == nil {
@@ -292,6 +385,20 @@ func (s *watchableStore) moveVictims() (moved int) {
 				continue
 			}
 			moved++
+
+			if eb.initialState && eb.initialStateNext != nil {
+				evs, next, err := s.initialStatePage(eb.initialStateNext, w.end, rev)
+				if err != nil {
+					// the initial state is compacted; the next sync cancels the watcher
+					w.minRev = rev
+					continue
+				}
+				if newVictim == nil {
+					newVictim = make(watcherBatch)
+				}
+				// the watcher stays a victim until the last page is sent
+				newVictim[w] = &eventBatch{evs: evs, initialState: true, initialStateNext: next}
+			}
 		}
 
 		// assign completed victim watchers to unsync/sync
@@ -350,20 +457,31 @@ func (s *watchableStore) syncWatchers() int {
 	compactionRev := s.store.compactMainRev
 
 	wg, minRev := s.unsynced.choose(maxWatchersPerSync, curRev, compactionRev)
-	minBytes, maxBytes := newRevBytes(), newRevBytes()
-	revToBytes(revision{main: minRev}, minBytes)
-	revToBytes(revision{main: curRev + 1}, maxBytes)
-
-	// UnsafeRange returns keys and values. And in boltdb, keys are revisions.
-	// values are actual key-value pairs in backend.
-	tx := s.store.b.ReadTx()
-	tx.RLock()
-	revs, vs := tx.UnsafeRange(schema.Key, minBytes, maxBytes, 0)
-	evs := kvsToEvents(s.store.lg, wg, revs, vs)
-	// Must unlock after kvsToEvents, because vs (come from boltdb memory) is not deep copy.
-	// We can only unlock after Unmarshal, which will do deep copy.
-	// Otherwise we will trigger SIGSEGV during boltdb re-mmap.
-	tx.RUnlock()
+	var evs []mvccpb.Event
+	cached := false
+	if s.eventCache != nil {
+		if evs, cached = s.eventCache.rangeEvents(wg, minRev, curRev); cached {
+			eventCacheHitCounter.Inc()
+		} else {
+			eventCacheMissCounter.Inc()
+		}
+	}
+	if !cached {
+		minBytes, maxBytes := newRevBytes(), newRevBytes()
+		revToBytes(revision{main: minRev}, minBytes)
+		revToBytes(revision{main: curRev + 1}, maxBytes)
+
+		// UnsafeRange returns keys and values. And in boltdb, keys are revisions.
+		// values are actual key-value pairs in backend.
+		tx := s.store.b.ReadTx()
+		tx.RLock()
+		revs, vs := tx.UnsafeRange(schema.Key, minBytes, maxBytes, 0)
+		evs = kvsToEvents(s.store.lg, s.store.codec, tx, wg, revs, vs)
+		// Must unlock after kvsToEvents, because vs (come from boltdb memory) is not deep copy.
+		// We can only unlock after Unmarshal, which will do deep copy.
+		// Otherwise we will trigger SIGSEGV during boltdb re-mmap.
+		tx.RUnlock()
+	}
 
 	victims := make(watcherBatch)
 	wb := newWatcherBatch(wg, evs)
@@ -410,11 +528,12 @@ func (s *watchableStore) syncWatchers() int {
 	return s.unsynced.size()
 }
 
-// kvsToEvents gets all events for the watchers from all key-value pairs
-func kvsToEvents(lg *zap.Logger, wg *watcherGroup, revs, vals [][]byte) (evs []mvccpb.Event) {
+// kvsToEvents gets all events for the watchers from all key-value pairs, and
+// the causes of the deletes from tx.
+func kvsToEvents(lg *zap.Logger, codec keyValueCodec, tx backend.ReadTx, wg *watcherGroup, revs, vals [][]byte) (evs []mvccpb.Event) {
 	for i, v := range vals {
 		var kv mvccpb.KeyValue
-		if err := kv.Unmarshal(v); err != nil {
+		if err := codec.unmarshal(v, &kv); err != nil {
 			lg.Panic("failed to unmarshal mvccpb.KeyValue", zap.Error(err))
 		}
 
@@ -422,13 +541,14 @@ func kvsToEvents(lg *zap.Logger, wg *watcherGroup, revs, vals [][]byte) (evs []m
 			continue
 		}
 
-		ty := mvccpb.PUT
+		ev := mvccpb.Event{Kv: &kv, Type: mvccpb.PUT}
 		if isTombstone(revs[i]) {
-			ty = mvccpb.DELETE
+			ev.Type = mvccpb.DELETE
+			ev.DeleteCause, kv.Lease = schema.UnsafeReadDeleteCause(tx, revs[i][:revBytesLen])
 			// patch in mod revision so watchers won't skip
 			kv.ModRevision = bytesToRev(revs[i]).main
 		}
-		evs = append(evs, mvccpb.Event{Kv: &kv, Type: ty})
+		evs = append(evs, ev)
 	}
 	return evs
 }
@@ -436,6 +556,9 @@ func kvsToEvents(lg *zap.Logger, wg *watcherGroup, revs, vals [][]byte) (evs []m
 // notify notifies the fact that given event at the given rev just happened to
 // watchers that watch on the key of the event.
 func (s *watchableStore) notify(rev int64, evs []mvccpb.Event) {
+	if s.eventCache != nil {
+		s.eventCache.add(rev, evs)
+	}
 	victim := make(watcherBatch)
 	for w, eb := range newWatcherBatch(&s.synced, evs) {
 		if eb.revs != 1 {
@@ -508,6 +631,8 @@ type watcher struct {
 	id     WatchID
 
 	fcs []FilterFunc
+	// coalesce is set when only the latest event on each key of a response is sent
+	coalesce bool
 	// a chan to send out the watch response.
 	// The chan might be shared with other watchers.
 	ch chan<- WatchResponse
@@ -533,8 +658,13 @@ func (w *watcher) send(wr WatchResponse) bool {
 		wr.Events = ne
 	}
 
-	// if all events are filtered out, we should send nothing.
-	if !progressEvent && len(wr.Events) == 0 {
+	if w.coalesce && len(wr.Events) > 1 {
+		wr.Events = coalesceEvents(wr.Events)
+	}
+
+	// if all events are filtered out, we should send nothing,
+	// unless the response ends the initial state.
+	if !progressEvent && len(wr.Events) == 0 && !wr.InitialStateDone {
 		return true
 	}
 	select {
@@ -544,3 +674,22 @@ func (w *watcher) send(wr WatchResponse) bool {
 		return false
 	}
 }
+
+// coalesceEvents collapses the events on the same key into the latest one,
+// keeping the revision order of the remaining events.
+func coalesceEvents(evs []mvccpb.Event) []mvccpb.Event {
+	latest := make(map[string]int, len(evs))
+	for i := range evs {
+		latest[string(evs[i].Kv.Key)] = i
+	}
+	if len(latest) == len(evs) {
+		return evs
+	}
+	ne := make([]mvccpb.Event, 0, len(latest))
+	for i := range evs {
+		if latest[string(evs[i].Kv.Key)] == i {
+			ne = append(ne, evs[i])
+		}
+	}
+	return ne
+}
@@ -29,6 +29,7 @@ import (
 	"go.etcd.io/etcd/pkg/v3/traceutil"
 	"go.etcd.io/etcd/server/v3/lease"
 	betesting "go.etcd.io/etcd/server/v3/storage/backend/testing"
+	"go.etcd.io/etcd/server/v3/storage/schema"
 )
 
 func TestWatch(t *testing.T) {
@@ -433,6 +434,261 @@ func TestWatchBatchUnsynced(t *testing.T) {
 	}
 }
 
+func TestWatchCoalesce(t *testing.T) {
+	b, tmpPath := betesting.NewDefaultTmpBackend(t)
+	s := newWatchableStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{})
+
+	defer func() {
+		s.store.Close()
+		os.Remove(tmpPath)
+	}()
+
+	foo, bar := []byte("foo"), []byte("bar")
+	s.Put(foo, []byte("1"), lease.NoLease)
+	s.Put(bar, []byte("1"), lease.NoLease)
+	s.Put(foo, []byte("2"), lease.NoLease)
+	s.DeleteRange(bar, nil)
+	s.Put(foo, []byte("3"), lease.NoLease)
+
+	w := s.NewWatchStream()
+	defer w.Close()
+	// the unsynced watcher receives all the events in one response
+	if _, err := w.WatchWithOptions(0, []byte("a"), []byte("z"), 1, WatchOptions{Coalesce: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case resp := <-w.Chan():
+		if len(resp.Events) != 2 {
+			t.Fatalf("len(events) = %d, want 2", len(resp.Events))
+		}
+		if ev := resp.Events[0]; ev.Type != mvccpb.DELETE || !bytes.Equal(ev.Kv.Key, bar) || ev.Kv.ModRevision != 5 {
+			t.Errorf("events[0] = %+v, want DELETE bar at revision 5", ev)
+		}
+		if ev := resp.Events[1]; ev.Type != mvccpb.PUT || !bytes.Equal(ev.Kv.Value, []byte("3")) || ev.Kv.ModRevision != 6 {
+			t.Errorf("events[1] = %+v, want PUT foo=3 at revision 6", ev)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("failed to receive watch response")
+	}
+}
+
+func TestWatchInitialState(t *testing.T) {
+	b, tmpPath := betesting.NewDefaultTmpBackend(t)
+	s := newWatchableStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{})
+
+	defer func() {
+		s.store.Close()
+		os.Remove(tmpPath)
+	}()
+
+	s.Put([]byte("foo1"), []byte("1"), lease.NoLease)
+	s.Put([]byte("foo2"), []byte("1"), lease.NoLease)
+	s.Put([]byte("bar"), []byte("1"), lease.NoLease)
+	s.Put([]byte("foo1"), []byte("2"), lease.NoLease)
+
+	recv := func(t *testing.T, w WatchStream) WatchResponse {
+		select {
+		case resp := <-w.Chan():
+			return resp
+		case <-time.After(5 * time.Second):
+			t.Fatal("failed to receive watch response")
+		}
+		return WatchResponse{}
+	}
+
+	tests := []struct {
+		name     string
+		startRev int64
+		opts     WatchOptions
+
+		wrev    int64
+		wvalues []string
+		wevs    int
+	}{
+		{name: "current revision", wrev: 5, wvalues: []string{"foo1=2", "foo2=1"}},
+		{name: "past revision", startRev: 4, wrev: 3, wvalues: []string{"foo1=1", "foo2=1"}, wevs: 1},
+		{name: "first revision", startRev: 1, wrev: 0, wevs: 3},
+		{
+			name:    "filtered",
+			opts:    WatchOptions{Filters: []FilterFunc{func(e mvccpb.Event) bool { return e.Type == mvccpb.PUT }}},
+			wrev:    5,
+			wvalues: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := s.NewWatchStream()
+			defer w.Close()
+			tt.opts.InitialState = true
+			if _, err := w.WatchWithOptions(0, []byte("foo"), []byte("fop"), tt.startRev, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+
+			resp := recv(t, w)
+			if !resp.InitialState || resp.Revision != tt.wrev {
+				t.Fatalf("response = %+v, want the initial state at revision %d", resp, tt.wrev)
+			}
+			var values []string
+			for _, ev := range resp.Events {
+				if ev.Type != mvccpb.PUT {
+					t.Errorf("event type = %v, want PUT", ev.Type)
+				}
+				values = append(values, fmt.Sprintf("%s=%s", ev.Kv.Key, ev.Kv.Value))
+			}
+			if !reflect.DeepEqual(values, tt.wvalues) {
+				t.Errorf("initial state = %v, want %v", values, tt.wvalues)
+			}
+
+			// the events following the initial state are sent next
+			evs := 0
+			for evs < tt.wevs {
+				resp = recv(t, w)
+				if resp.InitialState {
+					t.Fatalf("unexpected initial state %+v", resp)
+				}
+				evs += len(resp.Events)
+			}
+			if evs != tt.wevs {
+				t.Errorf("events = %d, want %d", evs, tt.wevs)
+			}
+		})
+	}
+
+	w := s.NewWatchStream()
+	defer w.Close()
+	if _, err := w.WatchWithOptions(0, []byte("foo"), nil, 7, WatchOptions{InitialState: true}); err != ErrFutureRev {
+		t.Errorf("err = %v, want %v", err, ErrFutureRev)
+	}
+}
+
+func TestWatchInitialStatePages(t *testing.T) {
+	b, tmpPath := betesting.NewDefaultTmpBackend(t)
+	s := newWatchableStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{})
+
+	defer func() {
+		s.store.Close()
+		os.Remove(tmpPath)
+	}()
+
+	defer func(size int) { initialStatePageSize = size }(initialStatePageSize)
+	initialStatePageSize = 2
+
+	for _, k := range []string{"foo1", "foo2", "foo3", "foo4", "foo5"} {
+		s.Put([]byte(k), []byte("1"), lease.NoLease)
+	}
+
+	w := s.NewWatchStream()
+	defer w.Close()
+	if _, err := w.WatchWithOptions(0, []byte("foo"), []byte("fop"), 0, WatchOptions{InitialState: true}); err != nil {
+		t.Fatal(err)
+	}
+	// the pages are loaded once the previous one is sent
+	s.Put([]byte("foo6"), []byte("1"), lease.NoLease)
+
+	var pages [][]string
+	for done := false; !done; {
+		select {
+		case resp := <-w.Chan():
+			if !resp.InitialState || resp.Revision != 6 {
+				t.Fatalf("response = %+v, want a page of the initial state at revision 6", resp)
+			}
+			var keys []string
+			for _, ev := range resp.Events {
+				keys = append(keys, string(ev.Kv.Key))
+			}
+			pages = append(pages, keys)
+			done = resp.InitialStateDone
+		case <-time.After(5 * time.Second):
+			t.Fatal("failed to receive watch response")
+		}
+	}
+	wpages := [][]string{{"foo1", "foo2"}, {"foo3", "foo4"}, {"foo5"}}
+	if !reflect.DeepEqual(pages, wpages) {
+		t.Errorf("pages = %v, want %v", pages, wpages)
+	}
+
+	select {
+	case resp := <-w.Chan():
+		if resp.InitialState || len(resp.Events) != 1 || string(resp.Events[0].Kv.Key) != "foo6" {
+			t.Errorf("response = %+v, want the put of foo6", resp)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("failed to receive watch response")
+	}
+}
+
+func TestWatchDeleteCause(t *testing.T) {
+	b, tmpPath := betesting.NewDefaultTmpBackend(t)
+	s := newWatchableStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{})
+
+	defer func() {
+		s.store.Close()
+		os.Remove(tmpPath)
+	}()
+
+	s.Put([]byte("foo"), []byte("bar"), lease.NoLease)
+	w := s.NewWatchStream()
+	defer w.Close()
+	w.Watch(0, []byte("foo"), nil, 0)
+
+	tw := s.Write(traceutil.TODO())
+	tw.SetDeleteCause(mvccpb.LEASE_EXPIRED, 7)
+	tw.DeleteRange([]byte("foo"), nil)
+	tw.End()
+	s.Put([]byte("foo"), []byte("bar"), lease.NoLease)
+	s.DeleteRange([]byte("foo"), nil)
+
+	want := []mvccpb.Event{
+		{Type: mvccpb.DELETE, Kv: &mvccpb.KeyValue{Key: []byte("foo"), ModRevision: 3, Lease: 7}, DeleteCause: mvccpb.LEASE_EXPIRED},
+		{Type: mvccpb.DELETE, Kv: &mvccpb.KeyValue{Key: []byte("foo"), ModRevision: 5}, DeleteCause: mvccpb.REQUEST},
+	}
+	var synced []mvccpb.Event
+	for len(synced) < 3 {
+		select {
+		case resp := <-w.Chan():
+			synced = append(synced, resp.Events...)
+		case <-time.After(5 * time.Second):
+			t.Fatal("failed to receive synced events")
+		}
+	}
+	if got := []mvccpb.Event{synced[0], synced[2]}; !reflect.DeepEqual(got, want) {
+		t.Errorf("synced events = %+v, want %+v", got, want)
+	}
+
+	// the tombstones are unchanged
+	tx := s.store.b.ReadTx()
+	tx.RLock()
+	revBytes := newRevBytes()
+	revToBytes(revision{main: 3}, revBytes)
+	_, vs := tx.UnsafeRange(schema.Key, appendMarkTombstone(zaptest.NewLogger(t), revBytes), nil, 0)
+	tx.RUnlock()
+	if len(vs) != 1 {
+		t.Fatalf("len(tombstones) = %d, want 1", len(vs))
+	}
+	var tombstone mvccpb.KeyValue
+	if err := s.store.codec.unmarshal(vs[0], &tombstone); err != nil {
+		t.Fatal(err)
+	}
+	if want := (mvccpb.KeyValue{Key: []byte("foo")}); !reflect.DeepEqual(tombstone, want) {
+		t.Errorf("tombstone = %+v, want %+v", tombstone, want)
+	}
+
+	// the unsynced watchers read the cause apart from the tombstones
+	w.Watch(0, []byte("foo"), nil, 3)
+	select {
+	case resp := <-w.Chan():
+		if len(resp.Events) != 3 {
+			t.Fatalf("len(events) = %d, want 3", len(resp.Events))
+		}
+		if got := []mvccpb.Event{resp.Events[0], resp.Events[2]}; !reflect.DeepEqual(got, want) {
+			t.Errorf("unsynced events = %+v, want %+v", got, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("failed to receive unsynced events")
+	}
+}
+
 func TestNewMapwatcherToEventMap(t *testing.T) {
 	k0, k1, k2 := []byte("foo0"), []byte("foo1"), []byte("foo2")
 	v0, v1, v2 := []byte("bar0"), []byte("bar1"), []byte("bar2")
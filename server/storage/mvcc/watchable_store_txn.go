@@ -17,6 +17,7 @@ package mvcc
 import (
 	"go.etcd.io/etcd/api/v3/mvccpb"
 	"go.etcd.io/etcd/pkg/v3/traceutil"
+	"go.etcd.io/etcd/server/v3/lease"
 )
 
 func (tw *watchableStoreTxnWrite) End() {
@@ -28,10 +29,16 @@ func (tw *watchableStoreTxnWrite) End() {
 
 	rev := tw.Rev() + 1
 	evs := make([]mvccpb.Event, len(changes))
+	causes := tw.causes
+	cause := mvccpb.REQUEST
 	for i, change := range changes {
+		for len(causes) != 0 && causes[0].from <= i {
+			cause, causes = causes[0].cause, causes[1:]
+		}
 		evs[i].Kv = &changes[i]
 		if change.CreateRevision == 0 {
 			evs[i].Type = mvccpb.DELETE
+			evs[i].DeleteCause = cause
 			evs[i].Kv.ModRevision = rev
 		} else {
 			evs[i].Type = mvccpb.PUT
@@ -49,8 +56,21 @@ func (tw *watchableStoreTxnWrite) End() {
 type watchableStoreTxnWrite struct {
 	TxnWrite
 	s *watchableStore
+
+	// causes are the causes of the deletes from the changes they are set at
+	causes []deleteCause
+}
+
+type deleteCause struct {
+	from  int
+	cause mvccpb.Event_DeleteCause
+}
+
+func (tw *watchableStoreTxnWrite) SetDeleteCause(cause mvccpb.Event_DeleteCause, id lease.LeaseID) {
+	tw.causes = append(tw.causes, deleteCause{from: len(tw.Changes()), cause: cause})
+	tw.TxnWrite.SetDeleteCause(cause, id)
 }
 
 func (s *watchableStore) Write(trace *traceutil.Trace) TxnWrite {
-	return &watchableStoreTxnWrite{s.store.Write(trace), s}
+	return &watchableStoreTxnWrite{TxnWrite: s.store.Write(trace), s: s}
 }
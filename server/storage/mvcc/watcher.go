@@ -34,6 +34,19 @@ type WatchID int64
 // FilterFunc returns true if the given event should be filtered out.
 type FilterFunc func(e mvccpb.Event) bool
 
+// WatchOptions configures a watcher created by WatchStream.WatchWithOptions.
+type WatchOptions struct {
+	// Filters drop the events for which any of them returns true.
+	Filters []FilterFunc
+	// Coalesce collapses the events on the same key within one watch
+	// response into the latest one.
+	Coalesce bool
+	// InitialState first sends the key-value pairs of the range as of
+	// startRev - 1, or as of the current revision if startRev <= 0, as put
+	// events in pages of responses with InitialState set.
+	InitialState bool
+}
+
 type WatchStream interface {
 	// Watch creates a watcher. The watcher watches the events happening or
 	// happened on the given key or range [key, end) from the given startRev.
@@ -47,6 +60,9 @@ type WatchStream interface {
 	// an auto-generated watch ID is returned.
 	Watch(id WatchID, key, end []byte, startRev int64, fcs ...FilterFunc) (WatchID, error)
 
+	// WatchWithOptions creates a watcher like Watch, configured by the given options.
+	WatchWithOptions(id WatchID, key, end []byte, startRev int64, opts WatchOptions) (WatchID, error)
+
 	// Chan returns a chan. All watch response will be sent to the returned chan.
 	Chan() <-chan WatchResponse
 
@@ -85,6 +101,15 @@ type WatchResponse struct {
 
 	// CompactRevision is set when the watcher is cancelled due to compaction.
 	CompactRevision int64
+
+	// InitialState is set on the responses carrying the pages of the initial
+	// state of a watcher created with WatchOptions.InitialState, the key-value
+	// pairs of its range as of Revision. They precede all the other events.
+	InitialState bool
+
+	// InitialStateDone is set on the response carrying the last page of the
+	// initial state.
+	InitialStateDone bool
 }
 
 // watchStream contains a collection of watchers that share
@@ -103,12 +128,22 @@ type watchStream struct {
 
 // Watch creates a new watcher in the stream and returns its WatchID.
 func (ws *watchStream) Watch(id WatchID, key, end []byte, startRev int64, fcs ...FilterFunc) (WatchID, error) {
+	return ws.WatchWithOptions(id, key, end, startRev, WatchOptions{Filters: fcs})
+}
+
+// WatchWithOptions creates a new watcher configured by opts in the stream and returns its WatchID.
+func (ws *watchStream) WatchWithOptions(id WatchID, key, end []byte, startRev int64, opts WatchOptions) (WatchID, error) {
 	// prevent wrong range where key >= end lexicographically
 	// watch request with 'WithFromKey' has empty-byte range end
 	if len(end) != 0 && bytes.Compare(key, end) != -1 {
 		return -1, ErrEmptyWatcherRange
 	}
 
+	// the initial state cannot be read as of a future revision
+	if opts.InitialState && startRev > ws.watchable.rev()+1 {
+		return -1, ErrFutureRev
+	}
+
 	ws.mu.Lock()
 	defer ws.mu.Unlock()
 	if ws.closed {
@@ -125,7 +160,7 @@ func (ws *watchStream) Watch(id WatchID, key, end []byte, startRev int64, fcs ..
 		return -1, ErrWatcherDuplicateID
 	}
 
-	w, c := ws.watchable.watch(key, end, startRev, id, ws.ch, fcs...)
+	w, c := ws.watchable.watch(key, end, startRev, id, ws.ch, opts)
 
 	ws.cancels[id] = c
 	ws.watchers[id] = w
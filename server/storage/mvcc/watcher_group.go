@@ -36,6 +36,11 @@ type eventBatch struct {
 	revs int
 	// moreRev is first revision with more events following this batch
 	moreRev int64
+	// initialState is set when evs is a page of the initial state of the
+	// watcher, and initialStateNext is the key the next page starts at, or
+	// nil if evs is the last page.
+	initialState     bool
+	initialStateNext []byte
 }
 
 func (eb *eventBatch) add(ev mvccpb.Event) {
@@ -61,6 +61,9 @@ const (
 	leaseOverhead = 64
 	// kvOverhead is an estimate for the cost of storing a key's Metadata
 	kvOverhead = 256
+	// maxIntegerValueSize is the size of the longest decimal int64 written
+	// by an incr request
+	maxIntegerValueSize = 20
 )
 
 var (
@@ -139,6 +142,8 @@ func (b *BackendQuota) Cost(v interface{}) int {
 		return costPut(r)
 	case *pb.TxnRequest:
 		return costTxn(r)
+	case []*pb.PutRequest:
+		return costPuts(r)
 	case *pb.LeaseGrantRequest:
 		return leaseOverhead
 	default:
@@ -148,12 +153,31 @@ func (b *BackendQuota) Cost(v interface{}) int {
 
 func costPut(r *pb.PutRequest) int { return kvOverhead + len(r.Key) + len(r.Value) }
 
+// costPuts charges the puts a txn writes once applied, including the values
+// written by its incr and append requests.
+func costPuts(puts []*pb.PutRequest) int {
+	cost := 0
+	for _, p := range puts {
+		cost += costPut(p)
+	}
+	return cost
+}
+
 func costTxnReq(u *pb.RequestOp) int {
-	r := u.GetRequestPut()
-	if r == nil {
-		return 0
+	switch {
+	case u.GetRequestPut() != nil:
+		return costPut(u.GetRequestPut())
+	case u.GetRequestIncr() != nil:
+		return kvOverhead + len(u.GetRequestIncr().Key) + maxIntegerValueSize
+	case u.GetRequestAppend() != nil:
+		// the size of the current value is unknown until the txn is applied,
+		// where the appended value is charged by costPuts
+		r := u.GetRequestAppend()
+		return kvOverhead + len(r.Key) + len(r.Value)
+	case u.GetRequestTxn() != nil:
+		return costTxn(u.GetRequestTxn())
 	}
-	return costPut(r)
+	return 0
 }
 
 func costTxn(r *pb.TxnRequest) int {
@@ -36,6 +36,19 @@ var (
 	authUsersBucketName = []byte("authUsers")
 	authRolesBucketName = []byte("authRoles")
 
+	quotaBucketName          = []byte("quota")
+	rateLimitStateBucketName = []byte("rateLimitState")
+
+	compactionHoldBucketName = []byte("compactionHold")
+
+	timeIndexBucketName = []byte("timeIndex")
+
+	keyLeaseBucketName = []byte("keyLease")
+
+	deleteCauseBucketName = []byte("deleteCause")
+
+	cdcCursorBucketName = []byte("cdcCursor")
+
 	testBucketName = []byte("test")
 )
 
@@ -53,6 +66,19 @@ var (
 	AuthUsers = backend.Bucket(bucket{id: 21, name: authUsersBucketName, safeRangeBucket: false})
 	AuthRoles = backend.Bucket(bucket{id: 22, name: authRolesBucketName, safeRangeBucket: false})
 
+	Quota          = backend.Bucket(bucket{id: 30, name: quotaBucketName, safeRangeBucket: false})
+	RateLimitState = backend.Bucket(bucket{id: 31, name: rateLimitStateBucketName, safeRangeBucket: false})
+
+	CompactionHold = backend.Bucket(bucket{id: 32, name: compactionHoldBucketName, safeRangeBucket: false})
+
+	TimeIndex = backend.Bucket(bucket{id: 33, name: timeIndexBucketName, safeRangeBucket: false})
+
+	KeyLease = backend.Bucket(bucket{id: 34, name: keyLeaseBucketName, safeRangeBucket: false})
+
+	DeleteCause = backend.Bucket(bucket{id: 35, name: deleteCauseBucketName, safeRangeBucket: false})
+
+	CDCCursor = backend.Bucket(bucket{id: 36, name: cdcCursorBucketName, safeRangeBucket: false})
+
 	Test = backend.Bucket(bucket{id: 100, name: testBucketName, safeRangeBucket: false})
 )
 
@@ -80,7 +106,8 @@ var (
 	ClusterClusterVersionKeyName = []byte("clusterVersion")
 	ClusterDowngradeKeyName      = []byte("downgrade")
 	// Since v3.6
-	MetaStorageVersionName = []byte("storageVersion")
+	MetaStorageVersionName           = []byte("storageVersion")
+	ScheduledCompactRetentionKeyName = []byte("scheduledCompactRetention")
 	// Before adding new meta key please update server/etcdserver/version
 )
 
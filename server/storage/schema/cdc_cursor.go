@@ -0,0 +1,72 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/binary"
+
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/server/v3/storage/backend"
+)
+
+type cdcCursorBackend struct {
+	lg *zap.Logger
+	be backend.Backend
+}
+
+func NewCDCCursorBackend(lg *zap.Logger, be backend.Backend) *cdcCursorBackend {
+	return &cdcCursorBackend{
+		lg: lg,
+		be: be,
+	}
+}
+
+func (s *cdcCursorBackend) CreateCDCCursorBucket() {
+	tx := s.be.BatchTx()
+	tx.LockOutsideApply()
+	defer tx.Unlock()
+	tx.UnsafeCreateBucket(CDCCursor)
+}
+
+// MustPutCDCCursor records the revision of the cursor of the sink, unless the
+// recorded one is ahead of it.
+func (s *cdcCursorBackend) MustPutCDCCursor(sink string, rev int64) {
+	tx := s.be.BatchTx()
+	tx.LockInsideApply()
+	defer tx.Unlock()
+
+	_, vs := tx.UnsafeRange(CDCCursor, []byte(sink), nil, 0)
+	if len(vs) == 1 && len(vs[0]) == 8 && int64(binary.BigEndian.Uint64(vs[0])) >= rev {
+		return
+	}
+	v := make([]byte, 8)
+	binary.BigEndian.PutUint64(v, uint64(rev))
+	tx.UnsafePut(CDCCursor, []byte(sink), v)
+}
+
+// GetCDCCursor returns the revision of the cursor of the sink recorded with
+// MustPutCDCCursor, 0 if none.
+func (s *cdcCursorBackend) GetCDCCursor(sink string) int64 {
+	tx := s.be.ReadTx()
+	tx.Lock()
+	defer tx.Unlock()
+
+	_, vs := tx.UnsafeRange(CDCCursor, []byte(sink), nil, 0)
+	if len(vs) != 1 || len(vs[0]) != 8 {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(vs[0]))
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/binary"
+
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/server/v3/storage/backend"
+)
+
+type compactionHoldBackend struct {
+	lg *zap.Logger
+	be backend.Backend
+}
+
+func NewCompactionHoldBackend(lg *zap.Logger, be backend.Backend) *compactionHoldBackend {
+	return &compactionHoldBackend{
+		lg: lg,
+		be: be,
+	}
+}
+
+func (s *compactionHoldBackend) CreateCompactionHoldBucket() {
+	tx := s.be.BatchTx()
+	tx.LockOutsideApply()
+	defer tx.Unlock()
+	tx.UnsafeCreateBucket(CompactionHold)
+}
+
+func compactionHoldKey(id int64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(id))
+	return key
+}
+
+func (s *compactionHoldBackend) MustPutCompactionHold(hold *etcdserverpb.CompactionHold) {
+	tx := s.be.BatchTx()
+	tx.LockInsideApply()
+	defer tx.Unlock()
+
+	v, err := hold.Marshal()
+	if err != nil {
+		s.lg.Panic("failed to marshal compaction hold", zap.Error(err))
+	}
+	tx.UnsafePut(CompactionHold, compactionHoldKey(hold.ID), v)
+}
+
+func (s *compactionHoldBackend) MustDeleteCompactionHold(hold *etcdserverpb.CompactionHold) {
+	tx := s.be.BatchTx()
+	tx.LockInsideApply()
+	defer tx.Unlock()
+
+	tx.UnsafeDelete(CompactionHold, compactionHoldKey(hold.ID))
+}
+
+func (s *compactionHoldBackend) GetAllCompactionHolds() ([]*etcdserverpb.CompactionHold, error) {
+	tx := s.be.ReadTx()
+	tx.Lock()
+	defer tx.Unlock()
+
+	var holds []*etcdserverpb.CompactionHold
+	err := tx.UnsafeForEach(CompactionHold, func(k, v []byte) error {
+		var hold etcdserverpb.CompactionHold
+		if err := hold.Unmarshal(v); err != nil {
+			return err
+		}
+		holds = append(holds, &hold)
+		return nil
+	})
+	return holds, err
+}
+
+func (s *compactionHoldBackend) ForceCommit() {
+	s.be.ForceCommit()
+}
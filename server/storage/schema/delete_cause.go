@@ -0,0 +1,59 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/binary"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/server/v3/storage/backend"
+)
+
+// deleteCauseVersion is the version of the encoding of the delete causes.
+const deleteCauseVersion = 1
+
+// UnsafeCreateDeleteCauseBucket creates the `deleteCause` bucket (if it does
+// not exist yet).
+func UnsafeCreateDeleteCauseBucket(tx backend.BatchTx) {
+	tx.UnsafeCreateBucket(DeleteCause)
+}
+
+// UnsafePutDeleteCause saves the cause of the delete at the revision rev of
+// the key bucket, and the ID of the lease causing it. The tombstones in the
+// key bucket are left unchanged, so that the cause is not part of the hash
+// of the key-value store.
+func UnsafePutDeleteCause(tx backend.BatchTx, rev []byte, cause mvccpb.Event_DeleteCause, id int64) {
+	value := make([]byte, 1+4+8)
+	value[0] = deleteCauseVersion
+	binary.BigEndian.PutUint32(value[1:], uint32(cause))
+	binary.BigEndian.PutUint64(value[5:], uint64(id))
+	tx.UnsafePut(DeleteCause, rev, value)
+}
+
+// UnsafeReadDeleteCause returns the cause of the delete at the revision rev
+// and the ID of the lease causing it. The delete is requested by a client if
+// no cause was saved.
+func UnsafeReadDeleteCause(tx backend.ReadTx, rev []byte) (mvccpb.Event_DeleteCause, int64) {
+	_, vs := tx.UnsafeRange(DeleteCause, rev, nil, 0)
+	if len(vs) != 1 || len(vs[0]) != 1+4+8 || vs[0][0] != deleteCauseVersion {
+		return mvccpb.REQUEST, 0
+	}
+	return mvccpb.Event_DeleteCause(binary.BigEndian.Uint32(vs[0][1:])), int64(binary.BigEndian.Uint64(vs[0][5:]))
+}
+
+// UnsafeDeleteDeleteCause deletes the cause of the delete at the revision rev.
+func UnsafeDeleteDeleteCause(tx backend.BatchTx, rev []byte) {
+	tx.UnsafeDelete(DeleteCause, rev)
+}
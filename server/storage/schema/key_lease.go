@@ -0,0 +1,50 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/binary"
+
+	"go.etcd.io/etcd/server/v3/storage/backend"
+)
+
+// UnsafeCreateKeyLeaseBucket creates the `keyLease` bucket (if it does not
+// exist yet).
+func UnsafeCreateKeyLeaseBucket(tx backend.BatchTx) {
+	tx.UnsafeCreateBucket(KeyLease)
+}
+
+// UnsafePutKeyLease saves the lease the key is attached to in place of the
+// lease of its revision rev.
+func UnsafePutKeyLease(tx backend.BatchTx, key []byte, rev, id int64) {
+	value := make([]byte, 16)
+	binary.BigEndian.PutUint64(value, uint64(rev))
+	binary.BigEndian.PutUint64(value[8:], uint64(id))
+	tx.UnsafePut(KeyLease, key, value)
+}
+
+// UnsafeDeleteKeyLease deletes the lease the key is attached to.
+func UnsafeDeleteKeyLease(tx backend.BatchTx, key []byte) {
+	tx.UnsafeDelete(KeyLease, key)
+}
+
+// UnsafeForEachKeyLease calls f with the keys, the revision and the lease
+// they are attached to.
+func UnsafeForEachKeyLease(tx backend.ReadTx, f func(key []byte, rev, id int64)) error {
+	return tx.UnsafeForEach(KeyLease, func(k, v []byte) error {
+		f(k, int64(binary.BigEndian.Uint64(v)), int64(binary.BigEndian.Uint64(v[8:])))
+		return nil
+	})
+}
@@ -0,0 +1,123 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/binary"
+
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/server/v3/storage/backend"
+)
+
+type quotaBackend struct {
+	lg *zap.Logger
+	be backend.Backend
+}
+
+func NewQuotaBackend(lg *zap.Logger, be backend.Backend) *quotaBackend {
+	return &quotaBackend{
+		lg: lg,
+		be: be,
+	}
+}
+
+func (s *quotaBackend) CreateQuotaBuckets() {
+	tx := s.be.BatchTx()
+	tx.LockOutsideApply()
+	defer tx.Unlock()
+	tx.UnsafeCreateBucket(Quota)
+	tx.UnsafeCreateBucket(RateLimitState)
+}
+
+// quotaKey returns the key of a quota, which is its type followed by its prefix or name.
+func quotaKey(quota *etcdserverpb.Quota) []byte {
+	key := []byte{byte(quota.Type)}
+	if quota.Type == etcdserverpb.Quota_PREFIX {
+		return append(key, quota.Prefix...)
+	}
+	return append(key, quota.Name...)
+}
+
+func (s *quotaBackend) MustPutQuota(quota *etcdserverpb.Quota) {
+	tx := s.be.BatchTx()
+	tx.LockInsideApply()
+	defer tx.Unlock()
+
+	v, err := quota.Marshal()
+	if err != nil {
+		s.lg.Panic("failed to marshal quota", zap.Error(err))
+	}
+	key := quotaKey(quota)
+	tx.UnsafePut(Quota, key, v)
+	tx.UnsafeDelete(RateLimitState, key)
+}
+
+func (s *quotaBackend) MustDeleteQuota(quota *etcdserverpb.Quota) {
+	tx := s.be.BatchTx()
+	tx.LockInsideApply()
+	defer tx.Unlock()
+
+	key := quotaKey(quota)
+	tx.UnsafeDelete(Quota, key)
+	tx.UnsafeDelete(RateLimitState, key)
+}
+
+func (s *quotaBackend) GetAllQuotas() ([]*etcdserverpb.Quota, error) {
+	tx := s.be.ReadTx()
+	tx.Lock()
+	defer tx.Unlock()
+
+	var quotas []*etcdserverpb.Quota
+	err := tx.UnsafeForEach(Quota, func(k, v []byte) error {
+		var quota etcdserverpb.Quota
+		if err := quota.Unmarshal(v); err != nil {
+			return err
+		}
+		quotas = append(quotas, &quota)
+		return nil
+	})
+	return quotas, err
+}
+
+// MustPutRateLimitState persists the tokens left to a rate limit and the time they were last refilled.
+func (s *quotaBackend) MustPutRateLimitState(quota *etcdserverpb.Quota, tokens, last int64) {
+	tx := s.be.BatchTx()
+	tx.LockInsideApply()
+	defer tx.Unlock()
+
+	v := make([]byte, 16)
+	binary.BigEndian.PutUint64(v, uint64(tokens))
+	binary.BigEndian.PutUint64(v[8:], uint64(last))
+	tx.UnsafePut(RateLimitState, quotaKey(quota), v)
+}
+
+// GetRateLimitState returns the state of a rate limit persisted with MustPutRateLimitState.
+func (s *quotaBackend) GetRateLimitState(quota *etcdserverpb.Quota) (tokens, last int64, found bool) {
+	tx := s.be.ReadTx()
+	tx.Lock()
+	defer tx.Unlock()
+
+	_, vs := tx.UnsafeRange(RateLimitState, quotaKey(quota), nil, 0)
+	if len(vs) != 1 || len(vs[0]) != 16 {
+		return 0, 0, false
+	}
+	return int64(binary.BigEndian.Uint64(vs[0])), int64(binary.BigEndian.Uint64(vs[0][8:])), true
+}
+
+func (s *quotaBackend) ForceCommit() {
+	s.be.ForceCommit()
+}
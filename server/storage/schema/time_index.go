@@ -0,0 +1,56 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/binary"
+
+	"go.etcd.io/etcd/server/v3/storage/backend"
+)
+
+// CreateTimeIndexBucket creates the `timeIndex` bucket (if it does not exist yet).
+func CreateTimeIndexBucket(tx backend.BatchTx) {
+	tx.LockOutsideApply()
+	defer tx.Unlock()
+	tx.UnsafeCreateBucket(TimeIndex)
+}
+
+// UnsafePutRevisionTime saves the time, in nanoseconds since the Unix epoch,
+// the revision is written.
+func UnsafePutRevisionTime(tx backend.BatchTx, rev, ts int64) {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(rev))
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint64(value, uint64(ts))
+	tx.UnsafePut(TimeIndex, key, value)
+}
+
+// UnsafeDeleteRevisionTime deletes the time the revision is written.
+func UnsafeDeleteRevisionTime(tx backend.BatchTx, rev int64) {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(rev))
+	tx.UnsafeDelete(TimeIndex, key)
+}
+
+// ReadRevisionTimes calls f with the revisions and their time in ascending
+// order of revision.
+func ReadRevisionTimes(tx backend.ReadTx, f func(rev, ts int64)) error {
+	tx.RLock()
+	defer tx.RUnlock()
+	return tx.UnsafeForEach(TimeIndex, func(k, v []byte) error {
+		f(int64(binary.BigEndian.Uint64(k)), int64(binary.BigEndian.Uint64(v)))
+		return nil
+	})
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wal
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/client/pkg/v3/fileutil"
+	"go.etcd.io/etcd/server/v3/storage/encryption"
+	"go.etcd.io/etcd/server/v3/storage/wal/walpb"
+)
+
+// decryptEntry returns the marshaled raftpb.Entry of an entry record,
+// decrypting it with the cipher if it is encrypted.
+func decryptEntry(c encryption.Cipher, data []byte) ([]byte, error) {
+	if !encryption.IsEncrypted(data) {
+		return data, nil
+	}
+	if c == nil {
+		return nil, encryption.ErrNotConfigured
+	}
+	return c.Decrypt(data)
+}
+
+// Decrypt copies the WAL files of dirpath to the new directory dstpath,
+// decrypting their entries with the cipher so that the copy is read
+// without it.
+func Decrypt(lg *zap.Logger, dirpath, dstpath string, c encryption.Cipher) error {
+	if lg == nil {
+		lg = zap.NewNop()
+	}
+	names, err := readWALNames(lg, dirpath)
+	if err != nil {
+		return err
+	}
+	if err = fileutil.CreateDirAll(lg, dstpath); err != nil {
+		return err
+	}
+	var crc uint32
+	for _, name := range names {
+		crc, err = rewriteFile(filepath.Join(dirpath, name), filepath.Join(dstpath, name), crc, func(data []byte) ([]byte, error) {
+			return decryptEntry(c, data)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	lg.Info("decrypted WAL", zap.String("path", dirpath), zap.String("decrypted-path", dstpath), zap.Int("files", len(names)))
+	return nil
+}
+
+// rewriteFile copies the records of a WAL file, rewriting the data of the
+// entry records with f. It returns the crc of the last record copied, which
+// the copy of the next file starts from.
+func rewriteFile(src, dst string, prevCrc uint32, f func(data []byte) ([]byte, error)) (uint32, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, fileutil.PrivateFileMode)
+	if err != nil {
+		return 0, err
+	}
+	defer out.Close()
+
+	decoder := NewDecoder(fileutil.NewFileReader(in))
+	encoder, err := newFileEncoder(out, prevCrc)
+	if err != nil {
+		return 0, err
+	}
+	rec := &walpb.Record{}
+	for err = decoder.Decode(rec); err == nil; err = decoder.Decode(rec) {
+		switch rec.Type {
+		case CrcType:
+			decoder.UpdateCRC(rec.Crc)
+			// the encoder computes the crc of the copy
+			err = encoder.encode(&walpb.Record{Type: CrcType})
+		case EntryType:
+			var data []byte
+			if data, err = f(rec.Data); err == nil {
+				err = encoder.encode(&walpb.Record{Type: EntryType, Data: data})
+			}
+		default:
+			err = encoder.encode(&walpb.Record{Type: rec.Type, Data: rec.Data})
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	// the last entry of the last file may be torn
+	if !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return 0, err
+	}
+	if err = encoder.flush(); err != nil {
+		return 0, err
+	}
+	if err = fileutil.Fsync(out); err != nil {
+		return 0, err
+	}
+	return encoder.crc.Sum32(), nil
+}
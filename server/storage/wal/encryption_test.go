@@ -0,0 +1,90 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wal
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"go.etcd.io/etcd/server/v3/storage/encryption"
+	"go.etcd.io/etcd/server/v3/storage/wal/walpb"
+	"go.etcd.io/raft/v3/raftpb"
+)
+
+func TestSaveEncrypted(t *testing.T) {
+	lg := zaptest.NewLogger(t)
+	keyFile := filepath.Join(t.TempDir(), "keys")
+	_, err := encryption.RotateKeyFile(keyFile)
+	require.NoError(t, err)
+	kms, err := encryption.NewLocalKMS(keyFile)
+	require.NoError(t, err)
+	c := encryption.NewEnvelope(kms)
+
+	restoreLater := SegmentSizeBytes
+	SegmentSizeBytes = 2 * 1024
+	defer func() { SegmentSizeBytes = restoreLater }()
+
+	p := t.TempDir()
+	w, err := Create(lg, p, []byte("metadata"))
+	require.NoError(t, err)
+	state := raftpb.HardState{Term: 1, Commit: 1}
+	// the entries saved before the cipher is set are not encrypted
+	ents := []raftpb.Entry{{Index: 1, Term: 1, Data: []byte("plaintext")}}
+	require.NoError(t, w.Save(state, ents))
+	w.SetCipher(c)
+	data := bytes.Repeat([]byte("secret"), 100)
+	for i := uint64(2); i < 10; i++ {
+		e := raftpb.Entry{Index: i, Term: 1, Data: data}
+		require.NoError(t, w.Save(state, []raftpb.Entry{e}))
+		ents = append(ents, e)
+	}
+	w.Close()
+
+	names, err := readWALNames(lg, p)
+	require.NoError(t, err)
+	require.Greater(t, len(names), 1)
+	for _, name := range names {
+		b, err := os.ReadFile(filepath.Join(p, name))
+		require.NoError(t, err)
+		assert.NotContains(t, string(b), "secret")
+	}
+
+	readAll := func(dir string, c encryption.Cipher) ([]raftpb.Entry, error) {
+		w, err := Open(lg, dir, walpb.Snapshot{})
+		require.NoError(t, err)
+		defer w.Close()
+		w.SetCipher(c)
+		_, _, ents, err := w.ReadAll()
+		return ents, err
+	}
+	_, err = readAll(p, nil)
+	assert.ErrorIs(t, err, encryption.ErrNotConfigured)
+	got, err := readAll(p, c)
+	require.NoError(t, err)
+	assert.Equal(t, ents, got)
+
+	// the decrypted copy is read without the cipher
+	dst := filepath.Join(t.TempDir(), "wal")
+	require.NoError(t, Decrypt(lg, p, dst, c))
+	got, err = readAll(dst, nil)
+	require.NoError(t, err)
+	assert.Equal(t, ents, got)
+}
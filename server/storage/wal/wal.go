@@ -28,6 +28,7 @@ import (
 
 	"go.etcd.io/etcd/client/pkg/v3/fileutil"
 	"go.etcd.io/etcd/pkg/v3/pbutil"
+	"go.etcd.io/etcd/server/v3/storage/encryption"
 	"go.etcd.io/etcd/server/v3/storage/wal/walpb"
 	"go.etcd.io/raft/v3"
 	"go.etcd.io/raft/v3/raftpb"
@@ -86,6 +87,10 @@ type WAL struct {
 
 	unsafeNoSync bool // if set, do not fsync
 
+	// cipher encrypts the entries saved if not nil; the entries read are
+	// decrypted whether they are encrypted by the cipher or not encrypted.
+	cipher encryption.Cipher
+
 	mu      sync.Mutex
 	enti    uint64   // index of the last entry saved to the wal
 	encoder *encoder // encoder to encode records
@@ -238,13 +243,24 @@ func (w *WAL) Reopen(lg *zap.Logger, snap walpb.Snapshot) (*WAL, error) {
 	if err != nil {
 		lg.Panic("failed to close WAL during reopen", zap.Error(err))
 	}
-	return Open(lg, w.dir, snap)
+	nw, err := Open(lg, w.dir, snap)
+	if err != nil {
+		return nil, err
+	}
+	nw.cipher = w.cipher
+	return nw, nil
 }
 
 func (w *WAL) SetUnsafeNoFsync() {
 	w.unsafeNoSync = true
 }
 
+// SetCipher sets the cipher encrypting the entries saved and decrypting the
+// entries read. It must be set before ReadAll.
+func (w *WAL) SetCipher(c encryption.Cipher) {
+	w.cipher = c
+}
+
 func (w *WAL) cleanupWAL(lg *zap.Logger) {
 	var err error
 	if err = w.Close(); err != nil {
@@ -455,7 +471,12 @@ func (w *WAL) ReadAll() (metadata []byte, state raftpb.HardState, ents []raftpb.
 	for err = decoder.Decode(rec); err == nil; err = decoder.Decode(rec) {
 		switch rec.Type {
 		case EntryType:
-			e := MustUnmarshalEntry(rec.Data)
+			data, derr := decryptEntry(w.cipher, rec.Data)
+			if derr != nil {
+				state.Reset()
+				return nil, state, nil, derr
+			}
+			e := MustUnmarshalEntry(data)
 			// 0 <= e.Index-w.start.Index - 1 < len(ents)
 			if e.Index > w.start.Index {
 				// prevent "panic: runtime error: slice bounds out of range [:13038096702221461992] with capacity 0"
@@ -905,6 +926,12 @@ func (w *WAL) Close() error {
 func (w *WAL) saveEntry(e *raftpb.Entry) error {
 	// TODO: add MustMarshalTo to reduce one allocation.
 	b := pbutil.MustMarshal(e)
+	if w.cipher != nil {
+		var err error
+		if b, err = w.cipher.Encrypt(b); err != nil {
+			return err
+		}
+	}
 	rec := &walpb.Record{Type: EntryType, Data: b}
 	if err := w.encoder.encode(rec); err != nil {
 		return err
@@ -16,6 +16,7 @@ package common
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -25,26 +26,30 @@ import (
 
 func TestDefragOnline(t *testing.T) {
 	testRunner.BeforeTest(t)
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	options := config.DefragOption{Timeout: 10 * time.Second}
-	clus := testRunner.NewCluster(ctx, t)
-	cc := testutils.MustClient(clus.Client())
-	testutils.ExecuteUntil(ctx, t, func() {
-		defer clus.Close()
-		var kvs = []testutils.KV{{Key: "key", Val: "val1"}, {Key: "key", Val: "val2"}, {Key: "key", Val: "val3"}}
-		for i := range kvs {
-			if err := cc.Put(ctx, kvs[i].Key, kvs[i].Val, config.PutOptions{}); err != nil {
-				t.Fatalf("compactTest #%d: put kv error (%v)", i, err)
-			}
-		}
-		_, err := cc.Compact(ctx, 4, config.CompactOption{Physical: true, Timeout: 10 * time.Second})
-		if err != nil {
-			t.Fatalf("defrag_test: compact with revision error (%v)", err)
-		}
+	for _, online := range []bool{false, true} {
+		t.Run(fmt.Sprintf("online=%v", online), func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			options := config.DefragOption{Online: online, Timeout: 10 * time.Second}
+			clus := testRunner.NewCluster(ctx, t)
+			cc := testutils.MustClient(clus.Client())
+			testutils.ExecuteUntil(ctx, t, func() {
+				defer clus.Close()
+				var kvs = []testutils.KV{{Key: "key", Val: "val1"}, {Key: "key", Val: "val2"}, {Key: "key", Val: "val3"}}
+				for i := range kvs {
+					if err := cc.Put(ctx, kvs[i].Key, kvs[i].Val, config.PutOptions{}); err != nil {
+						t.Fatalf("compactTest #%d: put kv error (%v)", i, err)
+					}
+				}
+				_, err := cc.Compact(ctx, 4, config.CompactOption{Physical: true, Timeout: 10 * time.Second})
+				if err != nil {
+					t.Fatalf("defrag_test: compact with revision error (%v)", err)
+				}
 
-		if err = cc.Defragment(ctx, options); err != nil {
-			t.Fatalf("defrag_test: defrag error (%v)", err)
-		}
-	})
+				if err = cc.Defragment(ctx, options); err != nil {
+					t.Fatalf("defrag_test: defrag error (%v)", err)
+				}
+			})
+		})
+	}
 }
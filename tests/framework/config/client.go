@@ -60,6 +60,7 @@ type CompactOption struct {
 }
 
 type DefragOption struct {
+	Online  bool
 	Timeout time.Duration
 }
 
@@ -44,6 +44,8 @@ import (
 	"go.etcd.io/etcd/client/pkg/v3/types"
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.etcd.io/etcd/pkg/v3/grpc_testing"
+	"go.etcd.io/etcd/server/v3/audit"
+	"go.etcd.io/etcd/server/v3/auth"
 	"go.etcd.io/etcd/server/v3/config"
 	"go.etcd.io/etcd/server/v3/embed"
 	"go.etcd.io/etcd/server/v3/etcdserver"
@@ -51,11 +53,15 @@ import (
 	"go.etcd.io/etcd/server/v3/etcdserver/api/membership"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/rafthttp"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/v3client"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3defrag"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/v3election"
 	epb "go.etcd.io/etcd/server/v3/etcdserver/api/v3election/v3electionpb"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/v3lock"
 	lockpb "go.etcd.io/etcd/server/v3/etcdserver/api/v3lock/v3lockpb"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/v3rpc"
+	"go.etcd.io/etcd/server/v3/storage/backend/engine"
+	"go.etcd.io/etcd/server/v3/storage/encryption"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
 	"go.etcd.io/etcd/server/v3/verify"
 	framecfg "go.etcd.io/etcd/tests/v3/framework/config"
 	"go.etcd.io/etcd/tests/v3/framework/testutils"
@@ -140,8 +146,10 @@ type ClusterConfig struct {
 
 	DiscoveryURL string
 
-	AuthToken    string
-	AuthTokenTTL uint
+	AuthToken     string
+	AuthTokenTTL  uint
+	Authenticator auth.Authenticator
+	AuditSink     audit.Sink
 
 	QuotaBackendBytes int64
 
@@ -173,6 +181,19 @@ type ClusterConfig struct {
 	ExperimentalMaxLearners     int
 	DisableStrictReconfigCheck  bool
 	CorruptCheckTime            time.Duration
+
+	AutoDefragThresholdMegabytes uint
+	AutoDefragCheckInterval      time.Duration
+	AutoDefragOnline             bool
+	AutoDefragLeaderPolicy       string
+
+	CompactionRetentionRules []mvcc.RetentionRule
+	BackendCompression       mvcc.CompressionType
+	BackendEngine            engine.Type
+	EncryptionKMS            encryption.KMS
+	WatchEventCacheRevisions int
+	CDCPrefixes              []string
+	CDCWebhookURL            string
 }
 
 type Cluster struct {
@@ -266,6 +287,8 @@ func (c *Cluster) mustNewMember(t testutil.TB) *Member {
 			MemberNumber:                memberNumber,
 			AuthToken:                   c.Cfg.AuthToken,
 			AuthTokenTTL:                c.Cfg.AuthTokenTTL,
+			Authenticator:               c.Cfg.Authenticator,
+			AuditSink:                   c.Cfg.AuditSink,
 			PeerTLS:                     c.Cfg.PeerTLS,
 			ClientTLS:                   c.Cfg.ClientTLS,
 			QuotaBackendBytes:           c.Cfg.QuotaBackendBytes,
@@ -288,6 +311,19 @@ func (c *Cluster) mustNewMember(t testutil.TB) *Member {
 			ExperimentalMaxLearners:     c.Cfg.ExperimentalMaxLearners,
 			DisableStrictReconfigCheck:  c.Cfg.DisableStrictReconfigCheck,
 			CorruptCheckTime:            c.Cfg.CorruptCheckTime,
+
+			AutoDefragThresholdMegabytes: c.Cfg.AutoDefragThresholdMegabytes,
+			AutoDefragCheckInterval:      c.Cfg.AutoDefragCheckInterval,
+			AutoDefragOnline:             c.Cfg.AutoDefragOnline,
+			AutoDefragLeaderPolicy:       c.Cfg.AutoDefragLeaderPolicy,
+
+			CompactionRetentionRules: c.Cfg.CompactionRetentionRules,
+			BackendCompression:       c.Cfg.BackendCompression,
+			BackendEngine:            c.Cfg.BackendEngine,
+			EncryptionKMS:            c.Cfg.EncryptionKMS,
+			WatchEventCacheRevisions: c.Cfg.WatchEventCacheRevisions,
+			CDCPrefixes:              c.Cfg.CDCPrefixes,
+			CDCWebhookURL:            c.Cfg.CDCWebhookURL,
 		})
 	m.DiscoveryURL = c.Cfg.DiscoveryURL
 	return m
@@ -592,6 +628,8 @@ type MemberConfig struct {
 	ClientTLS                   *transport.TLSInfo
 	AuthToken                   string
 	AuthTokenTTL                uint
+	Authenticator               auth.Authenticator
+	AuditSink                   audit.Sink
 	QuotaBackendBytes           int64
 	MaxTxnOps                   uint
 	MaxRequestBytes             uint
@@ -612,6 +650,19 @@ type MemberConfig struct {
 	ExperimentalMaxLearners     int
 	DisableStrictReconfigCheck  bool
 	CorruptCheckTime            time.Duration
+
+	AutoDefragThresholdMegabytes uint
+	AutoDefragCheckInterval      time.Duration
+	AutoDefragOnline             bool
+	AutoDefragLeaderPolicy       string
+
+	CompactionRetentionRules []mvcc.RetentionRule
+	BackendCompression       mvcc.CompressionType
+	BackendEngine            engine.Type
+	EncryptionKMS            encryption.KMS
+	WatchEventCacheRevisions int
+	CDCPrefixes              []string
+	CDCWebhookURL            string
 }
 
 // MustNewMember return an inited member with the given name. If peerTLS is
@@ -688,6 +739,8 @@ func MustNewMember(t testutil.TB, mcfg MemberConfig) *Member {
 	if mcfg.AuthTokenTTL != 0 {
 		m.TokenTTL = mcfg.AuthTokenTTL
 	}
+	m.Authenticator = mcfg.Authenticator
+	m.AuditSink = mcfg.AuditSink
 
 	m.BcryptCost = uint(bcrypt.MinCost) // use min bcrypt cost to speedy up integration testing
 
@@ -726,6 +779,22 @@ func MustNewMember(t testutil.TB, mcfg MemberConfig) *Member {
 	if mcfg.ExperimentalMaxLearners != 0 {
 		m.ExperimentalMaxLearners = mcfg.ExperimentalMaxLearners
 	}
+	m.ExperimentalAutoDefragThresholdMegabytes = mcfg.AutoDefragThresholdMegabytes
+	m.ExperimentalAutoDefragCheckInterval = mcfg.AutoDefragCheckInterval
+	m.ExperimentalAutoDefragOnline = mcfg.AutoDefragOnline
+	m.ExperimentalAutoDefragLeaderPolicy = v3defrag.LeaderPolicySkip
+	if mcfg.AutoDefragLeaderPolicy != "" {
+		m.ExperimentalAutoDefragLeaderPolicy = mcfg.AutoDefragLeaderPolicy
+	}
+	m.CompactionRetentionRules = mcfg.CompactionRetentionRules
+	m.BackendCompression = mcfg.BackendCompression
+	m.BackendEngine = mcfg.BackendEngine
+	m.WatchEventCacheRevisions = mcfg.WatchEventCacheRevisions
+	m.CDCPrefixes = mcfg.CDCPrefixes
+	m.CDCWebhookURL = mcfg.CDCWebhookURL
+	if mcfg.EncryptionKMS != nil {
+		m.EncryptionCipher = encryption.NewEnvelope(mcfg.EncryptionKMS)
+	}
 	m.V2Deprecation = config.V2_DEPR_DEFAULT
 	m.GrpcServerRecorder = &grpc_testing.GrpcRecorder{}
 	m.Logger = memberLogger(t, mcfg.Name)
@@ -1195,9 +1264,9 @@ func (m *Member) Close() {
 	for _, f := range m.ServerClosers {
 		f()
 	}
-	if !m.Closed {
-		// Avoid verification of the same file multiple times
-		// (that might not exist any longer)
+	// Avoid verification of the same file multiple times
+	// (that might not exist any longer), or of the in-memory backend
+	if !m.Closed && m.BackendEngine != engine.TypeMemory {
 		verify.MustVerifyIfEnabled(verify.Config{
 			Logger:     m.Logger,
 			DataDir:    m.DataDir,
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"reflect"
 	"strconv"
@@ -288,6 +289,184 @@ func TestKVRange(t *testing.T) {
 	}
 }
 
+func TestKVRangeWithContinue(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	kv := clus.RandClient()
+	ctx := context.TODO()
+
+	keySet := []string{"foo/a", "foo/b", "foo/c", "foo/d", "foo/e"}
+	for i, key := range keySet {
+		if _, err := kv.Put(ctx, key, ""); err != nil {
+			t.Fatalf("#%d: couldn't put %q (%v)", i, key, err)
+		}
+	}
+
+	var (
+		keys  []string
+		token []byte
+	)
+	for page := 0; ; page++ {
+		resp, err := kv.Get(ctx, "foo/", clientv3.WithPrefix(), clientv3.WithLimit(2), clientv3.WithContinue(token))
+		if err != nil {
+			t.Fatalf("#%d: couldn't range (%v)", page, err)
+		}
+		if page == 0 {
+			// keys written after the first page are not part of the paginated snapshot
+			if _, err := kv.Put(ctx, "foo/bb", ""); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, kv := range resp.Kvs {
+			keys = append(keys, string(kv.Key))
+		}
+		if !resp.More {
+			break
+		}
+		if len(resp.ContinueToken) == 0 {
+			t.Fatalf("#%d: expected continue token when more is set", page)
+		}
+		token = resp.ContinueToken
+	}
+	if !reflect.DeepEqual(keySet, keys) {
+		t.Fatalf("keys expected %v, got %v", keySet, keys)
+	}
+
+	_, err := kv.Get(ctx, "foo/", clientv3.WithPrefix(), clientv3.WithContinue([]byte("invalid")))
+	if err != rpctypes.ErrInvalidContinueToken {
+		t.Fatalf("expected %v, got %v", rpctypes.ErrInvalidContinueToken, err)
+	}
+}
+
+func TestKVGetStream(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	kv := clus.RandClient()
+	ctx := context.TODO()
+
+	// large values so that the range does not fit in a single part
+	val := strings.Repeat("a", 600*1024)
+	keySet := []string{"foo/a", "foo/b", "foo/c", "foo/d"}
+	for i, key := range keySet {
+		if _, err := kv.Put(ctx, key, val); err != nil {
+			t.Fatalf("#%d: couldn't put %q (%v)", i, key, err)
+		}
+	}
+
+	rs, err := kv.GetStream(ctx, "foo/", clientv3.WithPrefix())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		keys  []string
+		parts int
+	)
+	for {
+		resp, err := rs.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Count != int64(len(keySet)) {
+			t.Fatalf("count expected %d, got %d", len(keySet), resp.Count)
+		}
+		for _, kv := range resp.Kvs {
+			if string(kv.Value) != val {
+				t.Fatalf("unexpected value of %q", kv.Key)
+			}
+			keys = append(keys, string(kv.Key))
+		}
+		parts++
+	}
+	if !reflect.DeepEqual(keySet, keys) {
+		t.Fatalf("keys expected %v, got %v", keySet, keys)
+	}
+	if parts < 2 {
+		t.Fatalf("expected the range to be streamed in several parts, got %d", parts)
+	}
+
+	rs, err = kv.GetStream(ctx, "foo/", clientv3.WithPrefix(), clientv3.WithSort(clientv3.SortByModRevision, clientv3.SortDescend))
+	if err == nil {
+		_, err = rs.Recv()
+	}
+	if err != rpctypes.ErrInvalidStreamSort {
+		t.Fatalf("expected %v, got %v", rpctypes.ErrInvalidStreamSort, err)
+	}
+}
+
+func TestKVHistory(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	kv := clus.RandClient()
+	ctx := context.TODO()
+
+	if _, err := kv.Put(ctx, "foo", "bar"); err != nil { // 2
+		t.Fatal(err)
+	}
+	if _, err := kv.Put(ctx, "foo1", "bar1"); err != nil { // 3
+		t.Fatal(err)
+	}
+	if _, err := kv.Put(ctx, "foo", "bar2"); err != nil { // 4
+		t.Fatal(err)
+	}
+	if _, err := kv.Delete(ctx, "foo"); err != nil { // 5
+		t.Fatal(err)
+	}
+
+	resp, err := kv.History(ctx, "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantKVs := []*mvccpb.KeyValue{
+		{Key: []byte("foo"), Value: []byte("bar"), CreateRevision: 2, ModRevision: 2, Version: 1},
+		{Key: []byte("foo"), Value: []byte("bar2"), CreateRevision: 2, ModRevision: 4, Version: 2},
+		{Key: []byte("foo"), ModRevision: 5},
+	}
+	if !reflect.DeepEqual(resp.Kvs, wantKVs) {
+		t.Fatalf("kvs expected %+v, got %+v", wantKVs, resp.Kvs)
+	}
+	if resp.Header.Revision != 5 || resp.More {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+
+	resp, err = kv.History(ctx, "foo", clientv3.WithPrefix(), clientv3.WithMinModRev(3), clientv3.WithLimit(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var revs []int64
+	for _, kv := range resp.Kvs {
+		revs = append(revs, kv.ModRevision)
+	}
+	if !reflect.DeepEqual(revs, []int64{3, 4}) || !resp.More {
+		t.Fatalf("expected revisions [3 4] and more, got %v and %v", revs, resp.More)
+	}
+
+	if _, err = kv.Compact(ctx, 4); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = kv.History(ctx, "foo", clientv3.WithMinModRev(3)); err != rpctypes.ErrCompacted {
+		t.Fatalf("expected %v, got %v", rpctypes.ErrCompacted, err)
+	}
+	resp, err = kv.History(ctx, "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Kvs) != 2 || resp.CompactRevision != 4 {
+		t.Fatalf("expected 2 revisions since the compact revision 4, got %+v", resp)
+	}
+}
+
 func TestKVGetErrConnClosed(t *testing.T) {
 	integration2.BeforeTest(t)
 
@@ -702,6 +702,117 @@ func TestLeaseRenewLostQuorum(t *testing.T) {
 	}
 }
 
+// TestLeaseAttach ensures LeaseAttach and LeaseDetach move existing keys
+// between leases without writing them.
+func TestLeaseAttach(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 3})
+	defer clus.Terminate(t)
+
+	cli := clus.Client(0)
+	ctx := context.Background()
+
+	old, err := cli.Grant(ctx, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"svc/a", "svc/b", "svc/c"} {
+		if _, err = cli.Put(ctx, key, "v", clientv3.WithLease(old.ID)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err = cli.Attach(ctx, clientv3.LeaseID(500), []string{"svc/a"}); err != rpctypes.ErrLeaseNotFound {
+		t.Fatalf("err = %v, want %v", err, rpctypes.ErrLeaseNotFound)
+	}
+
+	wctx, wcancel := context.WithCancel(ctx)
+	defer wcancel()
+	wch := cli.Watch(wctx, "svc/", clientv3.WithPrefix(), clientv3.WithCreatedNotify())
+	<-wch
+
+	lresp, err := cli.Grant(ctx, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aresp, err := cli.Attach(ctx, lresp.ID, []string{"svc/"}, clientv3.WithPrefixKeys())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if aresp.Count != 3 {
+		t.Errorf("attached = %d, want 3", aresp.Count)
+	}
+	dresp, err := cli.Detach(ctx, []string{"svc/c", "svc/none"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dresp.Count != 1 {
+		t.Errorf("detached = %d, want 1", dresp.Count)
+	}
+
+	want := map[string]clientv3.LeaseID{"svc/a": lresp.ID, "svc/b": lresp.ID, "svc/c": clientv3.NoLease}
+	checkLeases := func(i int) {
+		t.Helper()
+		gresp, err := clus.Client(i).Get(ctx, "svc/", clientv3.WithPrefix(), clientv3.WithSerializable())
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make(map[string]clientv3.LeaseID)
+		for _, kv := range gresp.Kvs {
+			got[string(kv.Key)] = clientv3.LeaseID(kv.Lease)
+			if kv.Version != 1 {
+				t.Errorf("member %d: version of %q = %d, want 1", i, kv.Key, kv.Version)
+			}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("member %d: leases = %v, want %v", i, got, want)
+		}
+	}
+	// wait for the other members to apply the requests
+	if _, err = clus.Client(1).Get(ctx, "svc/a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = clus.Client(2).Get(ctx, "svc/a"); err != nil {
+		t.Fatal(err)
+	}
+	for i := range clus.Members {
+		checkLeases(i)
+	}
+	select {
+	case wresp := <-wch:
+		t.Fatalf("unexpected watch response %+v", wresp)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	ttl, err := cli.TimeToLive(ctx, old.ID, clientv3.WithAttachedKeys())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ttl.Keys) != 0 {
+		t.Errorf("keys of the old lease = %q, want none", ttl.Keys)
+	}
+
+	// the attachments survive a restart
+	clus.Members[1].Stop(t)
+	if err = clus.Members[1].Restart(t); err != nil {
+		t.Fatal(err)
+	}
+	clus.WaitLeader(t)
+	checkLeases(1)
+
+	// revoking the lease deletes the attached keys only
+	if _, err = cli.Revoke(ctx, lresp.ID); err != nil {
+		t.Fatal(err)
+	}
+	gresp, err := cli.Get(ctx, "svc/", clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gresp.Kvs) != 1 || string(gresp.Kvs[0].Key) != "svc/c" {
+		t.Errorf("keys = %v, want [svc/c]", gresp.Kvs)
+	}
+}
+
 func TestLeaseKeepAliveLoopExit(t *testing.T) {
 	integration2.BeforeTest(t)
 
@@ -845,3 +956,204 @@ func TestLeaseWithRequireLeader(t *testing.T) {
 		// wait some to detect any closes happening soon after kaReqLeader closing
 	}
 }
+
+// TestLeaseGrantChild ensures the child leases are revoked with their parent,
+// and never outlive its expiry.
+func TestLeaseGrantChild(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 3})
+	defer clus.Terminate(t)
+
+	cli := clus.Client(0)
+	ctx := context.Background()
+
+	if _, err := cli.GrantChild(ctx, clientv3.LeaseID(500), 60); err != rpctypes.ErrLeaseNotFound {
+		t.Fatalf("err = %v, want %v", err, rpctypes.ErrLeaseNotFound)
+	}
+
+	parent, err := cli.Grant(ctx, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	child, err := cli.GrantChild(ctx, parent.ID, 600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	grandchild, err := cli.GrantChild(ctx, child.ID, 600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cli.Put(ctx, "session/sub", "v", clientv3.WithLease(grandchild.ID)); err != nil {
+		t.Fatal(err)
+	}
+
+	// the tree is reported by all the members
+	for i := range clus.Members {
+		presp, err := clus.Client(i).TimeToLive(ctx, parent.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if presp.Parent != clientv3.NoLease || !reflect.DeepEqual(presp.Children, []clientv3.LeaseID{child.ID}) {
+			t.Errorf("member %d: parent = %x, children = %v, want none and [%x]", i, presp.Parent, presp.Children, child.ID)
+		}
+		cresp, err := clus.Client(i).TimeToLive(ctx, child.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cresp.Parent != parent.ID || !reflect.DeepEqual(cresp.Children, []clientv3.LeaseID{grandchild.ID}) {
+			t.Errorf("member %d: parent = %x, children = %v, want %x and [%x]", i, cresp.Parent, cresp.Children, parent.ID, grandchild.ID)
+		}
+		if cresp.GrantedTTL != 600 || cresp.TTL > presp.TTL {
+			t.Errorf("member %d: granted TTL = %d, TTL = %d, want 600 and at most %d", i, cresp.GrantedTTL, cresp.TTL, presp.TTL)
+		}
+	}
+
+	// a child lease can have labels
+	labeled, err := cli.GrantWithOptions(ctx, 600, clientv3.WithLeaseParent(parent.ID), clientv3.WithLeaseLabels(map[string]string{"role": "worker"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lresp, err := cli.TimeToLive(ctx, labeled.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lresp.Parent != parent.ID || lresp.Labels["role"] != "worker" {
+		t.Errorf("parent = %x, labels = %v, want %x and role=worker", lresp.Parent, lresp.Labels, parent.ID)
+	}
+
+	if _, err = cli.Revoke(ctx, parent.ID); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []clientv3.LeaseID{child.ID, grandchild.ID, labeled.ID} {
+		lresp, err := cli.TimeToLive(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lresp.TTL != -1 {
+			t.Errorf("TTL of lease %x = %d, want -1", id, lresp.TTL)
+		}
+	}
+	gresp, err := cli.Get(ctx, "session/sub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gresp.Kvs) != 0 {
+		t.Errorf("got %v, want the key deleted with the parent lease", gresp.Kvs)
+	}
+
+	// the children expire with their parent
+	parent, err = cli.Grant(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	child, err = cli.GrantChild(ctx, parent.ID, 600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	presp, err := cli.Put(ctx, "session/sub", "v", clientv3.WithLease(child.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wch := cli.Watch(ctx, "session/sub", clientv3.WithRev(presp.Header.Revision+1))
+	select {
+	case wresp := <-wch:
+		if len(wresp.Events) != 1 || !wresp.Events[0].IsLeaseExpired() || wresp.Events[0].Kv.Lease != int64(child.ID) {
+			t.Errorf("events = %v, want the key deleted with the expiry of lease %x", wresp.Events, child.ID)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the child lease to expire")
+	}
+}
+
+// TestLeaseList ensures the leases are listed by their labels in pages, and the
+// keys of a lease are listed in pages.
+func TestLeaseList(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 3})
+	defer clus.Terminate(t)
+
+	cli := clus.Client(0)
+	ctx := context.Background()
+
+	if _, err := cli.GrantWithOptions(ctx, 60, clientv3.WithLeaseLabels(map[string]string{"a=b": "c"})); err != rpctypes.ErrLeaseLabelInvalid {
+		t.Fatalf("err = %v, want %v", err, rpctypes.ErrLeaseLabelInvalid)
+	}
+	if _, err := cli.List(ctx, clientv3.WithLabelSelector("=v")); err != rpctypes.ErrLabelSelectorInvalid {
+		t.Fatalf("err = %v, want %v", err, rpctypes.ErrLabelSelectorInvalid)
+	}
+
+	var wids []int64
+	for i := 0; i < 5; i++ {
+		owner := fmt.Sprintf("worker-%d", i%2)
+		resp, err := cli.GrantWithOptions(ctx, 60, clientv3.WithLeaseLabels(map[string]string{"owner": owner, "service": "api"}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if owner == "worker-1" {
+			wids = append(wids, int64(resp.ID))
+		}
+		if _, err = cli.Put(ctx, fmt.Sprintf("k%d", i), "v", clientv3.WithLease(resp.ID)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sort.Slice(wids, func(i, j int) bool { return wids[i] < wids[j] })
+
+	// every member lists the leases of worker-1 in pages
+	for i := range clus.Members {
+		var (
+			ids []int64
+			tok []byte
+		)
+		for {
+			resp, err := clus.Client(i).List(ctx, clientv3.WithLabelSelector("owner=worker-1", "service"), clientv3.WithLeaseLimit(1), clientv3.WithLeaseContinue(tok))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, l := range resp.Leases {
+				ids = append(ids, l.ID)
+				if l.Labels["owner"] != "worker-1" || l.Keys != 1 || l.GrantedTTL != 60 {
+					t.Errorf("member %d: lease = %v, want owner=worker-1, 1 key and granted TTL 60", i, l)
+				}
+			}
+			if !resp.More {
+				break
+			}
+			tok = resp.ContinueToken
+		}
+		if !reflect.DeepEqual(ids, wids) {
+			t.Errorf("member %d: ids = %v, want %v", i, ids, wids)
+		}
+	}
+
+	lresp, err := cli.Grant(ctx, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"c", "a", "b"} {
+		if _, err = cli.Put(ctx, key, "v", clientv3.WithLease(lresp.ID)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var (
+		keys []string
+		tok  []byte
+	)
+	for {
+		resp, err := clus.Client(1).TimeToLive(ctx, lresp.ID, clientv3.WithAttachedKeys(), clientv3.WithLeaseLimit(2), clientv3.WithLeaseContinue(tok))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, k := range resp.Keys {
+			keys = append(keys, string(k))
+		}
+		if !resp.More {
+			break
+		}
+		tok = resp.ContinueToken
+	}
+	if !reflect.DeepEqual(keys, []string{"a", "b", "c"}) {
+		t.Errorf("keys = %v, want [a b c]", keys)
+	}
+}
@@ -16,6 +16,7 @@ package clientv3test
 
 import (
 	"context"
+	"io"
 	"reflect"
 	"testing"
 
@@ -54,6 +55,71 @@ func TestNamespacePutGet(t *testing.T) {
 	}
 }
 
+func TestNamespaceGetStream(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	c := clus.Client(0)
+	nsKV := namespace.NewKV(c.KV, "foo/")
+
+	for _, key := range []string{"foo/a", "foo/b", "bar/a", "foo0"} {
+		if _, err := c.Put(context.TODO(), key, "v"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rs, err := nsKV.GetStream(context.TODO(), "", clientv3.WithFromKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for {
+		resp, err := rs.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, kv := range resp.Kvs {
+			keys = append(keys, string(kv.Key))
+		}
+	}
+	if wkeys := []string{"a", "b"}; !reflect.DeepEqual(keys, wkeys) {
+		t.Errorf("expected keys=%q, got keys=%q", wkeys, keys)
+	}
+}
+
+func TestNamespaceHistory(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	c := clus.Client(0)
+	nsKV := namespace.NewKV(c.KV, "foo/")
+
+	for _, key := range []string{"foo/a", "bar/a", "foo/b", "foo0", "foo/a"} {
+		if _, err := c.Put(context.TODO(), key, "v"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resp, err := nsKV.History(context.TODO(), "", clientv3.WithFromKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, kv := range resp.Kvs {
+		keys = append(keys, string(kv.Key))
+	}
+	if wkeys := []string{"a", "b", "a"}; !reflect.DeepEqual(keys, wkeys) {
+		t.Fatalf("keys expected %v, got %v", wkeys, keys)
+	}
+}
+
 func TestNamespaceWatch(t *testing.T) {
 	integration2.BeforeTest(t)
 
@@ -17,6 +17,8 @@ package clientv3test
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -226,3 +228,98 @@ func TestTxnNested(t *testing.T) {
 		t.Errorf("unexpected Get response %+v", resp)
 	}
 }
+
+func TestTxnIncrAppend(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	kv := clus.Client(0)
+	ctx := context.TODO()
+
+	// concurrent increments are not lost
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := kv.Txn(ctx).Then(clientv3.OpIncr("counter", 1), clientv3.OpAppend("log", "x")).Commit(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	resp, err := kv.Get(ctx, "counter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Kvs) != 1 || string(resp.Kvs[0].Value) != "10" {
+		t.Fatalf("unexpected Get response %+v", resp)
+	}
+	resp, err = kv.Get(ctx, "log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Kvs) != 1 || string(resp.Kvs[0].Value) != "xxxxxxxxxx" {
+		t.Fatalf("unexpected Get response %+v", resp)
+	}
+
+	tresp, err := kv.Txn(ctx).Then(clientv3.OpSetMax("counter", 7), clientv3.OpSetMin("low", 3)).Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := tresp.Responses[0].GetResponseIncr().Value; v != 10 {
+		t.Errorf("expected max 10, got %d", v)
+	}
+	if v := tresp.Responses[1].GetResponseIncr().Value; v != 0 {
+		t.Errorf("expected min 0, got %d", v)
+	}
+
+	oresp, err := kv.Do(ctx, clientv3.OpIncr("counter", -10, clientv3.WithIncrBounds(0, 100), clientv3.WithPrevKV()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ir := oresp.Txn().Responses[0].GetResponseIncr()
+	if ir.Value != 0 || string(ir.PrevKv.Value) != "10" {
+		t.Errorf("unexpected incr response %+v", ir)
+	}
+
+	if _, err = kv.Do(ctx, clientv3.OpIncr("counter", -1, clientv3.WithIncrBounds(0, 100))); err != rpctypes.ErrValueOutOfRange {
+		t.Errorf("expected %v, got %v", rpctypes.ErrValueOutOfRange, err)
+	}
+	if _, err = kv.Do(ctx, clientv3.OpIncr("log", 1)); err != rpctypes.ErrValueNotInteger {
+		t.Errorf("expected %v, got %v", rpctypes.ErrValueNotInteger, err)
+	}
+	if _, err = kv.Txn(ctx).Then(clientv3.OpPut("counter", "1"), clientv3.OpIncr("counter", 1)).Commit(); err != rpctypes.ErrDuplicateKey {
+		t.Errorf("expected %v, got %v", rpctypes.ErrDuplicateKey, err)
+	}
+}
+
+func TestTxnAppendTooLarge(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1, MaxRequestBytes: 1024})
+	defer clus.Terminate(t)
+
+	kv := clus.Client(0)
+	ctx := context.TODO()
+
+	chunk := strings.Repeat("x", 400)
+	for i := 0; i < 2; i++ {
+		if _, err := kv.Do(ctx, clientv3.OpAppend("log", chunk)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// each request fits within the limit, but the appended value does not
+	if _, err := kv.Do(ctx, clientv3.OpAppend("log", chunk)); err != rpctypes.ErrRequestTooLarge {
+		t.Fatalf("expected %v, got %v", rpctypes.ErrRequestTooLarge, err)
+	}
+	resp, err := kv.Get(ctx, "log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Kvs) != 1 || len(resp.Kvs[0].Value) != 800 {
+		t.Fatalf("unexpected Get response %+v", resp)
+	}
+}
@@ -151,3 +151,60 @@ func TestGetTokenWithoutAuth(t *testing.T) {
 		t.Errorf("other errors:%v", err)
 	}
 }
+
+// TestRoleGrantPermissionWithOptions ensures that deny permissions override
+// the granted ones and that patterns cover keys of every tenant.
+func TestRoleGrantPermissionWithOptions(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	authapi := clus.RandClient()
+	ctx := context.TODO()
+	if _, err := authapi.RoleAdd(ctx, "tenants"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := authapi.RoleGrantPermissionWithOptions(ctx, "tenants", "/tenants/*/", clientv3.GetPrefixRangeEnd("/tenants/*/"), clientv3.PermissionType(clientv3.PermReadWrite), &clientv3.PermissionOptions{Pattern: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := authapi.RoleGrantPermissionWithOptions(ctx, "tenants", "/tenants/*/secrets/", clientv3.GetPrefixRangeEnd("/tenants/*/secrets/"), clientv3.PermissionType(clientv3.PermWrite), &clientv3.PermissionOptions{Pattern: true, Deny: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := authapi.RoleGrantPermissionWithOptions(ctx, "tenants", "/tenants/[a-", "", clientv3.PermissionType(clientv3.PermRead), &clientv3.PermissionOptions{Pattern: true}); err != rpctypes.ErrInvalidPermPattern {
+		t.Fatalf("expected %v, got %v", rpctypes.ErrInvalidPermPattern, err)
+	}
+	if _, err := authapi.UserAdd(ctx, "foo", "bar"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := authapi.UserGrantRole(ctx, "foo", "tenants"); err != nil {
+		t.Fatal(err)
+	}
+	authSetupRoot(t, authapi.Auth)
+
+	cfg := clientv3.Config{
+		Endpoints:   authapi.Endpoints(),
+		DialTimeout: 5 * time.Second,
+		DialOptions: []grpc.DialOption{grpc.WithBlock()},
+		Username:    "foo",
+		Password:    "bar",
+	}
+	foo, err := integration2.NewClient(t, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer foo.Close()
+
+	if _, err = foo.Put(ctx, "/tenants/a/config", "v"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = foo.Get(ctx, "/tenants/a/", clientv3.WithPrefix()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = foo.Put(ctx, "/tenants/a/secrets/key", "v"); err != rpctypes.ErrPermissionDenied {
+		t.Fatalf("expected %v, got %v", rpctypes.ErrPermissionDenied, err)
+	}
+	if _, err = foo.Put(ctx, "/other", "v"); err != rpctypes.ErrPermissionDenied {
+		t.Fatalf("expected %v, got %v", rpctypes.ErrPermissionDenied, err)
+	}
+}
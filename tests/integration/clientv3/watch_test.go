@@ -924,6 +924,168 @@ func TestWatchWithFilter(t *testing.T) {
 	}
 }
 
+// TestWatchWithValueFilters checks that value, key pattern and unchanged
+// filters are applied by the server.
+func TestWatchWithValueFilters(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	cluster := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer cluster.Terminate(t)
+
+	client := cluster.RandClient()
+	ctx := context.Background()
+
+	wcValue := client.Watch(ctx, "/jobs/", clientv3.WithPrefix(), clientv3.WithValuePrefix("ok"), clientv3.WithFilterDelete())
+	wcPattern := client.Watch(ctx, "/jobs/", clientv3.WithPrefix(), clientv3.WithKeyPattern("/jobs/*/status"))
+	wcUnchanged := client.Watch(ctx, "/jobs/", clientv3.WithPrefix(), clientv3.WithFilterUnchanged())
+
+	for _, kv := range [][2]string{
+		{"/jobs/1/spec", "ko"},
+		{"/jobs/1/status", "ok"},
+		{"/jobs/1/status", "ok"},
+	} {
+		if _, err := client.Put(ctx, kv[0], kv[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, wc := range []clientv3.WatchChan{wcValue, wcPattern} {
+		var evs []*clientv3.Event
+		for len(evs) < 2 {
+			resp := <-wc
+			evs = append(evs, resp.Events...)
+		}
+		for _, ev := range evs {
+			if string(ev.Kv.Key) != "/jobs/1/status" {
+				t.Fatalf("unexpected event %+v", ev)
+			}
+		}
+	}
+
+	var evs []*clientv3.Event
+	for len(evs) < 2 {
+		resp := <-wcUnchanged
+		evs = append(evs, resp.Events...)
+	}
+	if string(evs[0].Kv.Key) != "/jobs/1/spec" || string(evs[1].Kv.Key) != "/jobs/1/status" || evs[1].Kv.Version != 1 {
+		t.Fatalf("unexpected events %+v", evs)
+	}
+
+	select {
+	case resp := <-wcValue:
+		t.Fatalf("unexpected event on value filter (%+v)", resp)
+	case resp := <-wcPattern:
+		t.Fatalf("unexpected event on key pattern filter (%+v)", resp)
+	case resp := <-wcUnchanged:
+		t.Fatalf("unexpected event on unchanged filter (%+v)", resp)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// TestWatchWithInitialState checks that WithInitialState sends the keys of the
+// range, then the end of the initial state, then the events, and that a
+// resumed watcher does not receive the initial state again.
+func TestWatchWithInitialState(t *testing.T) {
+	if integration2.ThroughProxy {
+		t.Skipf("grpc-proxy does not support the watch initial state")
+	}
+	integration2.BeforeTest(t)
+
+	cluster := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1, UseBridge: true})
+	defer cluster.Terminate(t)
+
+	client := cluster.RandClient()
+	ctx := context.Background()
+
+	for _, kv := range [][2]string{{"/a", "1"}, {"/b", "2"}, {"/a", "3"}, {"/c/d", "4"}, {"/e", "5"}} {
+		if _, err := client.Put(ctx, kv[0], kv[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := client.Delete(ctx, "/e"); err != nil {
+		t.Fatal(err)
+	}
+
+	wch := client.Watch(ctx, "/", clientv3.WithPrefix(), clientv3.WithInitialState())
+	var state []string
+	var resp clientv3.WatchResponse
+	for !resp.InitialStateDone {
+		resp = <-wch
+		if err := resp.Err(); err != nil {
+			t.Fatal(err)
+		}
+		for _, ev := range resp.Events {
+			if ev.Type != clientv3.EventTypePut {
+				t.Fatalf("unexpected event %+v in the initial state", ev)
+			}
+			state = append(state, fmt.Sprintf("%s=%s", ev.Kv.Key, ev.Kv.Value))
+		}
+	}
+	if !reflect.DeepEqual(state, []string{"/a=3", "/b=2", "/c/d=4"}) {
+		t.Errorf("initial state = %v", state)
+	}
+	if resp.Header.Revision != 7 || resp.IsProgressNotify() {
+		t.Errorf("end of the initial state = %+v, want revision 7", resp)
+	}
+
+	cluster.Members[0].Bridge().DropConnections()
+	// the connections of the client are dropped
+	putClient, err := integration2.NewClientV3(cluster.Members[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer putClient.Close()
+	if _, err := putClient.Put(ctx, "/b", "6"); err != nil {
+		t.Fatal(err)
+	}
+	resp = <-wch
+	if len(resp.Events) != 1 || string(resp.Events[0].Kv.Value) != "6" || resp.InitialStateDone {
+		t.Fatalf("unexpected response %+v after the initial state", resp)
+	}
+	select {
+	case resp = <-wch:
+		t.Fatalf("unexpected response %+v", resp)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// TestWatchWithCoalesce checks that a watcher catching up on past revisions
+// receives only the latest event of each key.
+func TestWatchWithCoalesce(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	cluster := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer cluster.Terminate(t)
+
+	client := cluster.RandClient()
+	ctx := context.Background()
+
+	var startRev int64
+	for i, kv := range [][2]string{
+		{"/a", "1"},
+		{"/b", "1"},
+		{"/a", "2"},
+		{"/a", "3"},
+	} {
+		resp, err := client.Put(ctx, kv[0], kv[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			startRev = resp.Header.Revision
+		}
+	}
+
+	wc := client.Watch(ctx, "/", clientv3.WithPrefix(), clientv3.WithRev(startRev), clientv3.WithCoalesce())
+	resp := <-wc
+	if len(resp.Events) != 2 {
+		t.Fatalf("expected 2 events, got %+v", resp.Events)
+	}
+	if string(resp.Events[0].Kv.Key) != "/b" || string(resp.Events[1].Kv.Key) != "/a" || string(resp.Events[1].Kv.Value) != "3" {
+		t.Fatalf("unexpected events %+v", resp.Events)
+	}
+}
+
 // TestWatchWithCreatedNotification checks that WithCreatedNotify returns a
 // Created watch response.
 func TestWatchWithCreatedNotification(t *testing.T) {
@@ -1215,3 +1377,72 @@ func testWatchClose(t *testing.T, wctx *watchctx) {
 		t.Fatalf("read wch got %v; expected closed channel", wresp)
 	}
 }
+
+// TestWatchDeleteCause ensures the delete events of the keys attached to a
+// lease carry whether the lease was revoked or expired, and the lease ID.
+func TestWatchDeleteCause(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	cli := clus.RandClient()
+	ctx := context.Background()
+	wch := cli.Watch(ctx, "a", clientv3.WithPrefix())
+
+	revoked, err := cli.Grant(ctx, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, err := cli.Grant(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cli.Put(ctx, "a/revoked", "v", clientv3.WithLease(revoked.ID)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cli.Put(ctx, "a/expired", "v", clientv3.WithLease(expired.ID)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cli.Put(ctx, "a/deleted", "v"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cli.Delete(ctx, "a/deleted"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cli.Revoke(ctx, revoked.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]struct {
+		cause mvccpb.Event_DeleteCause
+		lease clientv3.LeaseID
+	}{
+		"a/deleted": {mvccpb.REQUEST, clientv3.NoLease},
+		"a/revoked": {mvccpb.LEASE_REVOKED, revoked.ID},
+		"a/expired": {mvccpb.LEASE_EXPIRED, expired.ID},
+	}
+	for len(want) > 0 {
+		select {
+		case wresp := <-wch:
+			for _, ev := range wresp.Events {
+				if ev.Type != mvccpb.DELETE {
+					continue
+				}
+				w, ok := want[string(ev.Kv.Key)]
+				if !ok {
+					t.Fatalf("unexpected delete event %+v", ev)
+				}
+				if ev.DeleteCause != w.cause || clientv3.LeaseID(ev.Kv.Lease) != w.lease {
+					t.Errorf("delete of %q = (%v, %x), want (%v, %x)", ev.Kv.Key, ev.DeleteCause, ev.Kv.Lease, w.cause, w.lease)
+				}
+				if ev.IsLeaseExpired() != (w.cause == mvccpb.LEASE_EXPIRED) {
+					t.Errorf("delete of %q IsLeaseExpired() = %v", ev.Kv.Key, ev.IsLeaseExpired())
+				}
+				delete(want, string(ev.Kv.Key))
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timed out waiting for the delete events of %v", want)
+		}
+	}
+}
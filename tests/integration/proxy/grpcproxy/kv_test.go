@@ -17,6 +17,7 @@ package grpcproxy
 import (
 	"context"
 	"net"
+	"reflect"
 	"testing"
 	"time"
 
@@ -53,6 +54,109 @@ func TestKVProxyRange(t *testing.T) {
 	client.Close()
 }
 
+func TestKVProxyRangeWithContinue(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	kvts := newKVProxyServer([]string{clus.Members[0].GRPCURL()}, t)
+	defer kvts.close()
+
+	cfg := clientv3.Config{
+		Endpoints:   []string{kvts.l.Addr().String()},
+		DialTimeout: 5 * time.Second,
+	}
+	client, err := integration2.NewClient(t, cfg)
+	if err != nil {
+		t.Fatalf("err = %v, want nil", err)
+	}
+	defer client.Close()
+
+	ctx := context.TODO()
+	keySet := []string{"foo/a", "foo/b", "foo/c", "foo/d", "foo/e"}
+	for i, key := range keySet {
+		if _, err = client.Put(ctx, key, ""); err != nil {
+			t.Fatalf("#%d: couldn't put %q (%v)", i, key, err)
+		}
+	}
+
+	var (
+		keys  []string
+		token []byte
+	)
+	for page := 0; ; page++ {
+		resp, err := client.Get(ctx, "foo/", clientv3.WithPrefix(), clientv3.WithLimit(2), clientv3.WithContinue(token))
+		if err != nil {
+			t.Fatalf("#%d: couldn't range (%v)", page, err)
+		}
+		for _, kv := range resp.Kvs {
+			keys = append(keys, string(kv.Key))
+		}
+		if !resp.More {
+			break
+		}
+		if len(resp.ContinueToken) == 0 {
+			t.Fatalf("#%d: expected continue token when more is set", page)
+		}
+		if page >= len(keySet) {
+			t.Fatalf("#%d: pagination did not end", page)
+		}
+		token = resp.ContinueToken
+	}
+	if !reflect.DeepEqual(keySet, keys) {
+		t.Fatalf("keys expected %v, got %v", keySet, keys)
+	}
+}
+
+func TestKVProxyRangeFilterProjection(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	kvts := newKVProxyServer([]string{clus.Members[0].GRPCURL()}, t)
+	defer kvts.close()
+
+	cfg := clientv3.Config{
+		Endpoints:   []string{kvts.l.Addr().String()},
+		DialTimeout: 5 * time.Second,
+	}
+	client, err := integration2.NewClient(t, cfg)
+	if err != nil {
+		t.Fatalf("err = %v, want nil", err)
+	}
+	defer client.Close()
+
+	ctx := context.TODO()
+	for _, key := range []string{"foo/a", "foo/b"} {
+		if _, err = client.Put(ctx, key, "val-"+key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err = client.Put(ctx, "foo/c", "other"); err != nil {
+		t.Fatal(err)
+	}
+
+	// populate the cache with the unfiltered range first
+	if _, err = client.Get(ctx, "foo/", clientv3.WithPrefix(), clientv3.WithSerializable()); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Get(ctx, "foo/", clientv3.WithPrefix(), clientv3.WithSerializable(),
+		clientv3.WithValuePrefix("val-"), clientv3.WithProjection(clientv3.FieldVersion))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Kvs) != 2 {
+		t.Fatalf("len(kvs) = %d, want 2", len(resp.Kvs))
+	}
+	for _, kv := range resp.Kvs {
+		if len(kv.Value) != 0 || kv.Version != 1 {
+			t.Fatalf("kv = %+v, want projected version only", kv)
+		}
+	}
+}
+
 type kvproxyTestServer struct {
 	kp     pb.KVServer
 	c      *clientv3.Client
@@ -0,0 +1,116 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/audit"
+	"go.etcd.io/etcd/tests/v3/framework/integration"
+)
+
+type recordingAuditSink struct {
+	mu     sync.Mutex
+	events []audit.Event
+}
+
+func (s *recordingAuditSink) Write(ev *audit.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, *ev)
+	return nil
+}
+
+func (s *recordingAuditSink) Close() error { return nil }
+
+// operations returns the events of the given operation.
+func (s *recordingAuditSink) operations(op string) []audit.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var evs []audit.Event
+	for _, ev := range s.events {
+		if ev.Operation == op {
+			evs = append(evs, ev)
+		}
+	}
+	return evs
+}
+
+// TestV3AuditLog ensures that mutations and admin operations are recorded
+// with their users and results, and reads are not at the default level.
+func TestV3AuditLog(t *testing.T) {
+	integration.BeforeTest(t)
+	sink := &recordingAuditSink{}
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1, AuditSink: sink})
+	defer clus.Terminate(t)
+
+	authSetupUsers(t, integration.ToGRPC(clus.Client(0)).Auth, []user{{name: "user1", password: "user1-123", role: "role1", key: "foo", end: "fop"}})
+	authSetupRoot(t, integration.ToGRPC(clus.Client(0)).Auth)
+
+	c, err := integration.NewClient(t, clientv3.Config{Endpoints: clus.Client(0).Endpoints(), Username: "user1", Password: "user1-123"})
+	require.NoError(t, err)
+	defer c.Close()
+
+	putResp, err := c.Put(context.TODO(), "foo", "bar")
+	require.NoError(t, err)
+	_, err = c.Put(context.TODO(), "bar", "foo")
+	require.ErrorIs(t, err, rpctypes.ErrPermissionDenied)
+	_, err = c.Get(context.TODO(), "foo")
+	require.NoError(t, err)
+	_, err = c.Txn(context.TODO()).
+		If(clientv3.Compare(clientv3.Version("foo"), "=", 0)).
+		Then(clientv3.OpPut("foo", "a")).
+		Else(clientv3.OpDelete("foo", clientv3.WithPrefix())).
+		Commit()
+	require.NoError(t, err)
+
+	evs := sink.operations("UserAdd")
+	require.Len(t, evs, 2)
+	assert.Equal(t, "user1", evs[0].Target)
+	assert.Equal(t, audit.ResultSuccess, evs[0].Result)
+
+	evs = sink.operations("Authenticate")
+	require.NotEmpty(t, evs)
+	assert.Equal(t, "user1", evs[0].User)
+
+	evs = sink.operations("Put")
+	require.Len(t, evs, 2)
+	assert.Equal(t, audit.Event{
+		Time:       evs[0].Time,
+		User:       "user1",
+		ClientAddr: evs[0].ClientAddr,
+		Operation:  "Put",
+		Key:        "foo",
+		Revision:   putResp.Header.Revision,
+		Result:     audit.ResultSuccess,
+	}, evs[0])
+	assert.NotEmpty(t, evs[0].ClientAddr)
+	assert.Equal(t, "bar", evs[1].Key)
+	assert.Equal(t, audit.ResultFailure, evs[1].Result)
+	assert.Equal(t, rpctypes.ErrGRPCPermissionDenied.Error(), evs[1].Error)
+
+	evs = sink.operations("Txn")
+	require.Len(t, evs, 1)
+	assert.Equal(t, []audit.Op{{Type: "delete", Key: "foo", RangeEnd: "fop"}}, evs[0].Ops)
+
+	assert.Empty(t, sink.operations("Range"))
+}
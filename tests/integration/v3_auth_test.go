@@ -16,6 +16,7 @@ package integration
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"testing"
@@ -26,6 +27,7 @@ import (
 	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	"go.etcd.io/etcd/client/pkg/v3/testutil"
 	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/auth"
 	"go.etcd.io/etcd/tests/v3/framework/integration"
 )
 
@@ -320,6 +322,100 @@ func TestV3AuthWithLeaseAttach(t *testing.T) {
 	}
 }
 
+// TestV3AuthLeaseGrantChild ensures a child lease can only be granted under
+// a parent lease the user can revoke.
+func TestV3AuthLeaseGrantChild(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	users := []user{
+		{
+			name:     "user1",
+			password: "user1-123",
+			role:     "role1",
+			key:      "k1",
+			end:      "k3",
+		},
+		{
+			name:     "user2",
+			password: "user2-123",
+			role:     "role2",
+			key:      "k2",
+			end:      "k4",
+		},
+	}
+	authSetupUsers(t, integration.ToGRPC(clus.Client(0)).Auth, users)
+
+	authSetupRoot(t, integration.ToGRPC(clus.Client(0)).Auth)
+
+	user1c, cerr := integration.NewClient(t, clientv3.Config{Endpoints: clus.Client(0).Endpoints(), Username: "user1", Password: "user1-123"})
+	if cerr != nil {
+		t.Fatal(cerr)
+	}
+	defer user1c.Close()
+
+	user2c, cerr := integration.NewClient(t, clientv3.Config{Endpoints: clus.Client(0).Endpoints(), Username: "user2", Password: "user2-123"})
+	if cerr != nil {
+		t.Fatal(cerr)
+	}
+	defer user2c.Close()
+
+	leaseResp, err := user1c.Grant(context.TODO(), 90)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// permission of k1 isn't granted to user2
+	if _, err = user1c.Put(context.TODO(), "k1", "val", clientv3.WithLease(leaseResp.ID)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = user1c.GrantChild(context.TODO(), leaseResp.ID, 90); err != nil {
+		t.Fatal(err)
+	}
+	_, err = user2c.GrantChild(context.TODO(), leaseResp.ID, 90)
+	if !errors.Is(err, rpctypes.ErrPermissionDenied) {
+		t.Fatalf("got %v, expected %v", err, rpctypes.ErrPermissionDenied)
+	}
+}
+
+// TestV3AuthLeaseAttachPrefix ensures attaching the keys prefixed by a prefix
+// reaching the end of the keyspace requires the permission on all of them.
+func TestV3AuthLeaseAttachPrefix(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	users := []user{
+		{
+			name:     "user1",
+			password: "user1-123",
+			role:     "role1",
+			key:      "\xff",
+		},
+	}
+	authSetupUsers(t, integration.ToGRPC(clus.Client(0)).Auth, users)
+
+	authSetupRoot(t, integration.ToGRPC(clus.Client(0)).Auth)
+
+	user1c, cerr := integration.NewClient(t, clientv3.Config{Endpoints: clus.Client(0).Endpoints(), Username: "user1", Password: "user1-123"})
+	if cerr != nil {
+		t.Fatal(cerr)
+	}
+	defer user1c.Close()
+
+	leaseResp, err := user1c.Grant(context.TODO(), 90)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = user1c.Attach(context.TODO(), leaseResp.ID, []string{"\xff"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = user1c.Attach(context.TODO(), leaseResp.ID, []string{"\xff"}, clientv3.WithPrefixKeys())
+	if !errors.Is(err, rpctypes.ErrPermissionDenied) {
+		t.Fatalf("got %v, expected %v", err, rpctypes.ErrPermissionDenied)
+	}
+}
+
 func authSetupUsers(t *testing.T, auth pb.AuthClient, users []user) {
 	for _, user := range users {
 		if _, err := auth.UserAdd(context.TODO(), &pb.AuthUserAddRequest{Name: user.name, Password: user.password, Options: &authpb.UserAddOptions{NoPassword: false}}); err != nil {
@@ -585,3 +681,64 @@ func TestV3AuthWatchErrorAndWatchId0(t *testing.T) {
 
 	<-watchEndCh
 }
+
+// staticAuthenticator verifies passwords of users which are not stored in etcd.
+type staticAuthenticator map[string]*auth.Identity // password -> identity
+
+func (a staticAuthenticator) Authenticate(ctx context.Context, username, password string) (*auth.Identity, error) {
+	id, ok := a[password]
+	if !ok || (username != "" && username != id.Name) {
+		return nil, auth.ErrAuthFailed
+	}
+	return id, nil
+}
+
+// TestV3AuthWithAuthenticator ensures that users verified by an authenticator
+// are granted their roles on every member.
+func TestV3AuthWithAuthenticator(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{
+		Size: 3,
+		Authenticator: staticAuthenticator{
+			"alice-token": {Name: "alice", Roles: []string{"role-foo"}},
+		},
+	})
+	defer clus.Terminate(t)
+
+	api := integration.ToGRPC(clus.Client(0))
+	if _, err := api.Auth.RoleAdd(context.TODO(), &pb.AuthRoleAddRequest{Name: "role-foo"}); err != nil {
+		t.Fatal(err)
+	}
+	perm := &authpb.Permission{PermType: authpb.READWRITE, Key: []byte("foo"), RangeEnd: []byte("fop")}
+	if _, err := api.Auth.RoleGrantPermission(context.TODO(), &pb.AuthRoleGrantPermissionRequest{Name: "role-foo", Perm: perm}); err != nil {
+		t.Fatal(err)
+	}
+	authSetupRoot(t, api.Auth)
+
+	if _, err := integration.NewClient(t, clientv3.Config{Endpoints: clus.Client(0).Endpoints(), Username: "alice", Password: "wrong"}); !eqErrGRPC(err, rpctypes.ErrAuthFailed) {
+		t.Fatalf("got %v, expected %v", err, rpctypes.ErrAuthFailed)
+	}
+
+	c, err := integration.NewClient(t, clientv3.Config{Endpoints: clus.Client(0).Endpoints(), Username: "alice", Password: "alice-token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if _, err = c.Put(context.TODO(), "foo", "bar"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.Put(context.TODO(), "bar", "foo"); !eqErrGRPC(err, rpctypes.ErrPermissionDenied) {
+		t.Fatalf("got %v, expected %v", err, rpctypes.ErrPermissionDenied)
+	}
+
+	// the token issued by member 0 is accepted by other members
+	c.SetEndpoints(clus.Client(1).Endpoints()...)
+	resp, err := c.Get(context.TODO(), "foo", clientv3.WithSerializable())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Kvs) != 1 || string(resp.Kvs[0].Value) != "bar" {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+}
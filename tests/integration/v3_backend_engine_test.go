@@ -0,0 +1,85 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.etcd.io/etcd/client/pkg/v3/fileutil"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/storage/backend/engine"
+	"go.etcd.io/etcd/server/v3/storage/datadir"
+	"go.etcd.io/etcd/tests/v3/framework/integration"
+)
+
+// TestV3MemoryBackendEngine tests that the members of a cluster storing the
+// backend in memory serve the requests, send their backend in snapshots to
+// the members joining and defragment it, without writing it to disk, and
+// refuse to restart once the backend is lost.
+func TestV3MemoryBackendEngine(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{
+		Size:                       3,
+		BackendEngine:              engine.TypeMemory,
+		SnapshotCount:              10,
+		SnapshotCatchUpEntries:     5,
+		DisableStrictReconfigCheck: true,
+	})
+	defer clus.Terminate(t)
+
+	ctx := context.TODO()
+	cli := clus.RandClient()
+	wch := cli.Watch(ctx, "foo", clientv3.WithPrefix())
+	for i := 0; i < 20; i++ {
+		_, err := cli.Put(ctx, fmt.Sprintf("foo%d", i), "bar")
+		require.NoError(t, err)
+	}
+	wresp := <-wch
+	require.NoError(t, wresp.Err())
+	require.NotEmpty(t, wresp.Events)
+
+	// the new member restores the backend from a snapshot of the leader
+	clus.AddMember(t)
+	clus.WaitMembersForLeader(t, clus.Members)
+	integration.WaitClientV3(t, clus.Members[3].Client)
+
+	presp, err := cli.Put(ctx, "bar", "baz")
+	require.NoError(t, err)
+	var hash uint32
+	for i, m := range clus.Members {
+		c := clus.Client(i)
+		resp, err := c.Get(ctx, "foo", clientv3.WithPrefix(), clientv3.WithCountOnly())
+		require.NoError(t, err)
+		assert.Equal(t, int64(20), resp.Count)
+		_, err = c.Defragment(ctx, m.GRPCURL())
+		require.NoError(t, err)
+		hresp, err := c.HashKV(ctx, m.GRPCURL(), presp.Header.Revision)
+		require.NoError(t, err)
+		if i > 0 {
+			assert.Equal(t, hash, hresp.Hash, "member %d", i)
+		}
+		hash = hresp.Hash
+		assert.False(t, fileutil.Exist(datadir.ToBackendFileName(m.DataDir)), "member %d wrote the backend to disk", i)
+	}
+
+	// the backend is lost when the member stops, so it cannot restart from its WAL
+	clus.Members[0].Stop(t)
+	require.ErrorContains(t, clus.Members[0].Restart(t), "cannot restart a member storing the backend in memory")
+}
@@ -0,0 +1,249 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/server/v3/storage/schema"
+	"go.etcd.io/etcd/tests/v3/framework/integration"
+)
+
+// TestV3CDCWebhook tests that the events of the captured keys are posted to
+// the webhook, and that the member resumes from its cursor once restarted.
+func TestV3CDCWebhook(t *testing.T) {
+	integration.BeforeTest(t)
+
+	var (
+		mu          sync.Mutex
+		evs         = make(map[int64]*mvccpb.Event)
+		unavailable bool
+	)
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		if unavailable {
+			mu.Unlock()
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		mu.Unlock()
+		sc := bufio.NewScanner(r.Body)
+		for sc.Scan() {
+			ev := &mvccpb.Event{}
+			if !assert.NoError(t, jsonpb.UnmarshalString(sc.Text(), ev)) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			mu.Lock()
+			// the events may be delivered more than once
+			evs[ev.Kv.ModRevision] = ev
+			mu.Unlock()
+		}
+	}))
+	defer stub.Close()
+
+	clus := integration.NewCluster(t, &integration.ClusterConfig{
+		Size:          1,
+		CDCPrefixes:   []string{"cdc/"},
+		CDCWebhookURL: stub.URL,
+	})
+	defer clus.Terminate(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var revs []int64
+	put := func(key string) {
+		resp, err := clus.RandClient().Put(ctx, key, "v")
+		require.NoError(t, err)
+		if key != "other" {
+			revs = append(revs, resp.Header.Revision)
+		}
+	}
+	waitEvents := func() {
+		require.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(evs) == len(revs)
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	for i := 0; i < 3; i++ {
+		put(fmt.Sprintf("cdc/%d", i))
+		put("other")
+	}
+	waitEvents()
+
+	// the events rejected by the webhook are delivered after the restart
+	mu.Lock()
+	unavailable = true
+	mu.Unlock()
+	for i := 0; i < 3; i++ {
+		put(fmt.Sprintf("cdc/%d", i))
+	}
+	m := clus.Members[0]
+	m.Stop(t)
+	mu.Lock()
+	unavailable = false
+	mu.Unlock()
+	require.NoError(t, m.Restart(t))
+	clus.WaitLeader(t)
+	put("cdc/3")
+	waitEvents()
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, rev := range revs {
+		ev, ok := evs[rev]
+		require.True(t, ok, "missing event of revision %d", rev)
+		assert.Equal(t, mvccpb.PUT, ev.Type)
+	}
+	// the second put of cdc/0 has the first one as previous key-value pair
+	require.NotNil(t, evs[revs[3]].PrevKv)
+	assert.Equal(t, revs[0], evs[revs[3]].PrevKv.ModRevision)
+}
+
+// TestV3CDCLeaderOnly tests that only the leader of a cluster delivers the
+// captured events.
+func TestV3CDCLeaderOnly(t *testing.T) {
+	integration.BeforeTest(t)
+
+	var (
+		mu         sync.Mutex
+		deliveries = make(map[int64]int)
+	)
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sc := bufio.NewScanner(r.Body)
+		for sc.Scan() {
+			ev := &mvccpb.Event{}
+			if !assert.NoError(t, jsonpb.UnmarshalString(sc.Text(), ev)) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			mu.Lock()
+			deliveries[ev.Kv.ModRevision]++
+			mu.Unlock()
+		}
+	}))
+	defer stub.Close()
+
+	clus := integration.NewCluster(t, &integration.ClusterConfig{
+		Size:          3,
+		CDCWebhookURL: stub.URL,
+	})
+	defer clus.Terminate(t)
+	clus.WaitLeader(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var revs []int64
+	for i := 0; i < 5; i++ {
+		resp, err := clus.RandClient().Put(ctx, fmt.Sprintf("cdc/%d", i), "v")
+		require.NoError(t, err)
+		revs = append(revs, resp.Header.Revision)
+	}
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return deliveries[revs[len(revs)-1]] > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	// give the followers the time to deliver the events if they did
+	time.Sleep(200 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, rev := range revs {
+		assert.Equal(t, 1, deliveries[rev], "deliveries of revision %d", rev)
+	}
+}
+
+// TestV3CDCLeaderMoved tests that the member taking over the leadership
+// resumes the delivery from the cursor replicated by the previous leader.
+func TestV3CDCLeaderMoved(t *testing.T) {
+	integration.BeforeTest(t)
+
+	var (
+		mu         sync.Mutex
+		deliveries = make(map[int64]int)
+	)
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sc := bufio.NewScanner(r.Body)
+		for sc.Scan() {
+			ev := &mvccpb.Event{}
+			if !assert.NoError(t, jsonpb.UnmarshalString(sc.Text(), ev)) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			mu.Lock()
+			deliveries[ev.Kv.ModRevision]++
+			mu.Unlock()
+		}
+	}))
+	defer stub.Close()
+
+	clus := integration.NewCluster(t, &integration.ClusterConfig{
+		Size:          3,
+		CDCWebhookURL: stub.URL,
+	})
+	defer clus.Terminate(t)
+	lead := clus.WaitLeader(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	put := func() int64 {
+		resp, err := clus.RandClient().Put(ctx, "cdc/0", "v")
+		require.NoError(t, err)
+		return resp.Header.Revision
+	}
+	var revs []int64
+	for i := 0; i < 3; i++ {
+		revs = append(revs, put())
+	}
+	// wait until every member records the cursor of the leader
+	require.Eventually(t, func() bool {
+		for _, m := range clus.Members {
+			if schema.NewCDCCursorBackend(m.Logger, m.Server.Backend()).GetCDCCursor("webhook") < revs[len(revs)-1] {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, clus.Members[lead].Server.TransferLeadership())
+	clus.WaitLeader(t)
+	revs = append(revs, put())
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return deliveries[revs[len(revs)-1]] > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, rev := range revs {
+		assert.Equal(t, 1, deliveries[rev], "deliveries of revision %d", rev)
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/tests/v3/framework/integration"
+)
+
+// TestV3CompactionHold tests that the compactions never go past the lowest
+// revision held, until the hold is released or its lease is revoked.
+func TestV3CompactionHold(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 3})
+	defer clus.Terminate(t)
+
+	ctx := context.TODO()
+	cli := clus.RandClient()
+	for i := 0; i < 10; i++ {
+		_, err := cli.Put(ctx, "foo", fmt.Sprint(i))
+		require.NoError(t, err)
+	}
+
+	lresp1, err := cli.Grant(ctx, 60)
+	require.NoError(t, err)
+	lresp2, err := cli.Grant(ctx, 60)
+	require.NoError(t, err)
+
+	_, err = cli.CompactionHold(ctx, 5, 0)
+	require.ErrorIs(t, err, rpctypes.ErrLeaseNotFound)
+	_, err = cli.CompactionHold(ctx, 100, lresp1.ID)
+	require.ErrorIs(t, err, rpctypes.ErrFutureRev)
+
+	hresp1, err := cli.CompactionHold(ctx, 5, lresp1.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), hresp1.Revision)
+	hresp2, err := cli.CompactionHold(ctx, 8, lresp2.ID)
+	require.NoError(t, err)
+
+	// the compaction stops at the lowest hold
+	cresp, err := cli.Compact(ctx, 10, clientv3.WithCompactPhysical())
+	require.NoError(t, err)
+	require.Equal(t, int64(5), cresp.CompactRevision)
+	for i := range clus.Members {
+		c := clus.Client(i)
+		_, err = c.Get(ctx, "foo", clientv3.WithRev(5), clientv3.WithSerializable())
+		require.NoError(t, err)
+		_, err = c.Get(ctx, "foo", clientv3.WithRev(4), clientv3.WithSerializable())
+		require.ErrorIs(t, err, rpctypes.ErrCompacted)
+
+		lresp, err := c.CompactionHoldList(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []*pb.CompactionHold{
+			{ID: hresp1.ID, Revision: 5, Lease: int64(lresp1.ID)},
+			{ID: hresp2.ID, Revision: 8, Lease: int64(lresp2.ID)},
+		}, lresp.Holds)
+	}
+	_, err = cli.CompactionHold(ctx, 4, lresp1.ID)
+	require.ErrorIs(t, err, rpctypes.ErrCompacted)
+
+	// compacting again at the same hold is not an error
+	_, err = cli.Compact(ctx, 10)
+	require.NoError(t, err)
+
+	_, err = cli.CompactionHoldRelease(ctx, hresp1.ID)
+	require.NoError(t, err)
+	_, err = cli.CompactionHoldRelease(ctx, hresp1.ID)
+	require.ErrorIs(t, err, rpctypes.ErrCompactionHoldNotFound)
+	cresp, err = cli.Compact(ctx, 10)
+	require.NoError(t, err)
+	require.Equal(t, int64(8), cresp.CompactRevision)
+	_, err = cli.Get(ctx, "foo", clientv3.WithRev(8))
+	require.NoError(t, err)
+	_, err = cli.Get(ctx, "foo", clientv3.WithRev(7))
+	require.ErrorIs(t, err, rpctypes.ErrCompacted)
+
+	// the hold is released with its lease
+	_, err = cli.Revoke(ctx, lresp2.ID)
+	require.NoError(t, err)
+	lresp, err := cli.CompactionHoldList(ctx)
+	require.NoError(t, err)
+	require.Empty(t, lresp.Holds)
+	cresp, err = cli.Compact(ctx, 10)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), cresp.CompactRevision)
+	_, err = cli.Get(ctx, "foo", clientv3.WithRev(9))
+	require.ErrorIs(t, err, rpctypes.ErrCompacted)
+}
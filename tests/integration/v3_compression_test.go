@@ -0,0 +1,82 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+	"go.etcd.io/etcd/tests/v3/framework/integration"
+)
+
+// TestV3BackendCompression tests that the compressed revisions are read,
+// watched and hashed like the uncompressed ones.
+func TestV3BackendCompression(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 3, BackendCompression: mvcc.CompressionFlate})
+	defer clus.Terminate(t)
+
+	ctx := context.TODO()
+	cli := clus.RandClient()
+	value := strings.Repeat(`{"name":"foo","spec":{"replicas":3}}`, 100)
+	for _, v := range []string{"small", value, value + "2"} {
+		_, err := cli.Put(ctx, "foo", v)
+		require.NoError(t, err)
+	}
+
+	for i := range clus.Members {
+		c := clus.Client(i)
+		resp, err := c.Get(ctx, "foo", clientv3.WithRev(3))
+		require.NoError(t, err)
+		require.Equal(t, value, string(resp.Kvs[0].Value))
+	}
+
+	wch := cli.Watch(ctx, "foo", clientv3.WithRev(2))
+	var vals []string
+	for len(vals) < 3 {
+		wresp := <-wch
+		require.NoError(t, wresp.Err())
+		for _, ev := range wresp.Events {
+			vals = append(vals, string(ev.Kv.Value))
+		}
+	}
+	require.Equal(t, []string{"small", value, value + "2"}, vals)
+
+	// the members compressing differently have the same hash
+	clus.Members[0].Stop(t)
+	clus.Members[0].BackendCompression = mvcc.CompressionNone
+	require.NoError(t, clus.Members[0].Restart(t))
+	clus.WaitLeader(t)
+	presp, err := cli.Put(ctx, "bar", value)
+	require.NoError(t, err)
+
+	var hash uint32
+	for i, m := range clus.Members {
+		// wait for the member to apply the put
+		_, err = clus.Client(i).Get(ctx, "bar")
+		require.NoError(t, err)
+		resp, err := clus.Client(i).HashKV(ctx, m.GRPCURL(), presp.Header.Revision)
+		require.NoError(t, err)
+		if i > 0 {
+			require.Equal(t, hash, resp.Hash)
+		}
+		hash = resp.Hash
+	}
+}
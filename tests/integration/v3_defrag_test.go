@@ -0,0 +1,84 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3defrag"
+	"go.etcd.io/etcd/tests/v3/framework/integration"
+)
+
+// TestV3AutoDefrag tests that the members, including the leader, are
+// defragmented automatically once enough space can be freed.
+func TestV3AutoDefrag(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{
+		Size:                         3,
+		AutoDefragThresholdMegabytes: 1,
+		AutoDefragCheckInterval:      100 * time.Millisecond,
+		AutoDefragOnline:             true,
+		AutoDefragLeaderPolicy:       v3defrag.LeaderPolicyMove,
+	})
+	defer clus.Terminate(t)
+
+	ctx := context.TODO()
+	cli := clus.RandClient()
+	val := strings.Repeat("a", 16*1024)
+	for i := 0; i < 200; i++ {
+		_, err := cli.Put(ctx, fmt.Sprintf("foo%d", i), val)
+		require.NoError(t, err)
+	}
+	resp, err := cli.Delete(ctx, "foo", clientv3.WithPrefix())
+	require.NoError(t, err)
+	_, err = cli.Compact(ctx, resp.Header.Revision, clientv3.WithCompactPhysical())
+	require.NoError(t, err)
+
+	free := func(m *integration.Member) int64 {
+		be := m.Server.Backend()
+		return be.Size() - be.SizeInUse()
+	}
+	// the pages freed by the compaction are only reported as free once
+	// the following commits release them
+	for i := 0; i < 5; i++ {
+		_, err = cli.Put(ctx, "bar", "baz")
+		require.NoError(t, err)
+		time.Sleep(200 * time.Millisecond)
+	}
+	for _, m := range clus.Members {
+		require.Eventually(t, func() bool { return free(m) < 1024*1024 }, 20*time.Second, 100*time.Millisecond,
+			"member %s was not defragmented", m.Name)
+	}
+	// the lock leases are revoked once done
+	require.Eventually(t, func() bool {
+		resp, err := cli.Leases(ctx)
+		require.NoError(t, err)
+		for _, l := range resp.Leases {
+			for _, m := range clus.Members {
+				if l.ID == clientv3.LeaseID(v3defrag.LockLeaseID(m.Server.MemberId())) {
+					return false
+				}
+			}
+		}
+		return true
+	}, 10*time.Second, 100*time.Millisecond)
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/etcdutl/v3/etcdutl"
+	"go.etcd.io/etcd/server/v3/storage/datadir"
+	"go.etcd.io/etcd/server/v3/storage/encryption"
+	"go.etcd.io/etcd/tests/v3/framework/integration"
+)
+
+// TestV3EncryptionAtRest tests that the data of the members configured with
+// encryption keys is not written in plaintext, and is read after the keys
+// are rotated.
+func TestV3EncryptionAtRest(t *testing.T) {
+	integration.BeforeTest(t)
+	keyFile := filepath.Join(t.TempDir(), "keys")
+	_, err := encryption.RotateKeyFile(keyFile)
+	require.NoError(t, err)
+	kms, err := encryption.NewLocalKMS(keyFile)
+	require.NoError(t, err)
+
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 3, EncryptionKMS: kms, SnapshotCount: 5})
+	defer clus.Terminate(t)
+
+	ctx := context.TODO()
+	cli := clus.RandClient()
+	secret := "secret-value"
+	for i := 0; i < 10; i++ {
+		_, err = cli.Put(ctx, fmt.Sprintf("foo%d", i), secret)
+		require.NoError(t, err)
+	}
+
+	m := clus.Members[0]
+	m.Stop(t)
+	dataDir := m.DataDir
+	snapNames := requireNotContains(t, datadir.ToSnapDir(dataDir), secret)
+	assert.Contains(t, strings.Join(snapNames, ","), ".snap")
+	requireNotContains(t, datadir.ToWalDir(dataDir), secret)
+
+	// the member restarted with the rotated key reads the data encrypted by
+	// the previous key
+	_, err = etcdutl.RotateEncryptionKey(zaptest.NewLogger(t), keyFile, "")
+	require.NoError(t, err)
+	kms, err = encryption.NewLocalKMS(keyFile)
+	require.NoError(t, err)
+	m.EncryptionCipher = encryption.NewEnvelope(kms)
+	require.NoError(t, m.Restart(t))
+	clus.WaitLeader(t)
+	presp, err := cli.Put(ctx, "bar", secret)
+	require.NoError(t, err)
+
+	var hash uint32
+	for i, m := range clus.Members {
+		c := clus.Client(i)
+		resp, err := c.Get(ctx, "foo", clientv3.WithPrefix())
+		require.NoError(t, err)
+		require.Len(t, resp.Kvs, 10)
+		for _, kv := range resp.Kvs {
+			require.Equal(t, secret, string(kv.Value))
+		}
+		_, err = c.Get(ctx, "bar")
+		require.NoError(t, err)
+		hresp, err := c.HashKV(ctx, m.GRPCURL(), presp.Header.Revision)
+		require.NoError(t, err)
+		if i > 0 {
+			require.Equal(t, hash, hresp.Hash)
+		}
+		hash = hresp.Hash
+	}
+
+	m.Stop(t)
+	// the backup of the encrypted data directory stays encrypted
+	backupDir := filepath.Join(t.TempDir(), "backup")
+	require.NoError(t, etcdutl.HandleBackup(true, dataDir, backupDir, "", "", keyFile))
+	requireNotContains(t, datadir.ToSnapDir(backupDir), secret)
+	requireNotContains(t, datadir.ToWalDir(backupDir), secret)
+	require.NoError(t, etcdutl.DecryptData(zaptest.NewLogger(t), keyFile, backupDir, filepath.Join(t.TempDir(), "decrypted-backup")))
+
+	outputDataDir := filepath.Join(t.TempDir(), "decrypted")
+	require.NoError(t, etcdutl.DecryptData(zaptest.NewLogger(t), keyFile, dataDir, outputDataDir))
+	db, err := os.ReadFile(datadir.ToBackendFileName(outputDataDir))
+	require.NoError(t, err)
+	assert.Contains(t, string(db), secret)
+}
+
+// requireNotContains checks that the files of the directory do not contain
+// s, and returns their names.
+func requireNotContains(t *testing.T, dir, s string) []string {
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		require.NoError(t, err)
+		require.NotContains(t, string(b), s, "file %s", e.Name())
+		names = append(names, e.Name())
+	}
+	return names
+}
@@ -0,0 +1,144 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/tests/v3/framework/integration"
+)
+
+// TestV3QuotaPrefix tests that prefix quotas are enforced on all members and
+// do not affect the keys outside of the prefix.
+func TestV3QuotaPrefix(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 3})
+	defer clus.Terminate(t)
+
+	ctx := context.TODO()
+	cli := clus.Client(0)
+	_, err := cli.Put(ctx, "/tenant/a", "1234")
+	require.NoError(t, err)
+
+	_, err = cli.QuotaSet(ctx, &clientv3.Quota{Type: pb.Quota_PREFIX, Prefix: []byte("/tenant/"), MaxKeys: 2, MaxBytes: 40})
+	require.NoError(t, err)
+
+	_, err = clus.Client(1).Put(ctx, "/tenant/b", "1234")
+	require.NoError(t, err)
+	_, err = clus.Client(2).Put(ctx, "/tenant/c", "1234")
+	assert.Equal(t, rpctypes.ErrQuotaExceeded, err)
+	_, err = cli.Txn(ctx).Then(clientv3.OpPut("/tenant/a", "12345678901234567890")).Commit()
+	assert.Equal(t, rpctypes.ErrQuotaExceeded, err)
+	// a txn only putting under the prefix in the branch not taken succeeds
+	_, err = cli.Txn(ctx).If(clientv3.Compare(clientv3.Version("/tenant/a"), "=", 0)).
+		Then(clientv3.OpPut("/tenant/c", "1234")).Commit()
+	require.NoError(t, err)
+	_, err = cli.Put(ctx, "/other/c", "12345678901234567890")
+	require.NoError(t, err)
+
+	_, err = cli.Delete(ctx, "/tenant/a")
+	require.NoError(t, err)
+	_, err = cli.Put(ctx, "/tenant/c", "1234")
+	require.NoError(t, err)
+
+	for i := range clus.Members {
+		resp, err := clus.Client(i).QuotaList(ctx)
+		require.NoError(t, err)
+		require.Len(t, resp.Quotas, 1)
+		assert.Equal(t, int64(2), resp.Quotas[0].UsedKeys)
+		assert.Equal(t, int64(26), resp.Quotas[0].UsedBytes)
+	}
+
+	_, err = cli.QuotaDelete(ctx, clientv3.QuotaPrefix, "/tenant/")
+	require.NoError(t, err)
+	_, err = cli.Put(ctx, "/tenant/d", "1234")
+	require.NoError(t, err)
+	_, err = cli.QuotaDelete(ctx, clientv3.QuotaPrefix, "/tenant/")
+	assert.Equal(t, rpctypes.ErrQuotaNotFound, err)
+	_, err = cli.QuotaSet(ctx, &clientv3.Quota{Type: pb.Quota_PREFIX, Prefix: []byte("/tenant/")})
+	assert.Equal(t, rpctypes.ErrInvalidQuota, err)
+}
+
+// TestV3QuotaRateLimit tests that the requests of users are limited by the
+// rate limits of themselves and of their roles.
+func TestV3QuotaRateLimit(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	users := []user{
+		{name: "user1", password: "user1-123", role: "role1", key: "k", end: "l"},
+		{name: "user2", password: "user2-123", role: "role2", key: "k", end: "l"},
+	}
+	authSetupUsers(t, integration.ToGRPC(clus.Client(0)).Auth, users)
+	authSetupRoot(t, integration.ToGRPC(clus.Client(0)).Auth)
+
+	ctx := context.TODO()
+	rootc, err := integration.NewClient(t, clientv3.Config{Endpoints: clus.Client(0).Endpoints(), Username: "root", Password: "123"})
+	require.NoError(t, err)
+	defer rootc.Close()
+	user1c, err := integration.NewClient(t, clientv3.Config{Endpoints: clus.Client(0).Endpoints(), Username: "user1", Password: "user1-123"})
+	require.NoError(t, err)
+	defer user1c.Close()
+	user2c, err := integration.NewClient(t, clientv3.Config{Endpoints: clus.Client(0).Endpoints(), Username: "user2", Password: "user2-123"})
+	require.NoError(t, err)
+	defer user2c.Close()
+
+	// only the root role may set quotas
+	_, err = user1c.QuotaSet(ctx, &clientv3.Quota{Type: pb.Quota_USER, Name: "user1", RequestsPerSecond: 1000})
+	assert.Equal(t, rpctypes.ErrPermissionDenied, err)
+
+	_, err = rootc.QuotaSet(ctx, &clientv3.Quota{Type: pb.Quota_USER, Name: "user1", RequestsPerSecond: 1, Burst: 3})
+	require.NoError(t, err)
+	_, err = rootc.QuotaSet(ctx, &clientv3.Quota{Type: pb.Quota_ROLE, Name: "role2", RequestsPerSecond: 1, Burst: 2})
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err = user1c.Put(ctx, "k1", "v")
+		require.NoError(t, err)
+	}
+	_, err = user1c.Put(ctx, "k1", "v")
+	assert.Equal(t, rpctypes.ErrRateLimited, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = user2c.Put(ctx, "k2", "v")
+		require.NoError(t, err)
+	}
+	_, err = user2c.Delete(ctx, "k2")
+	assert.Equal(t, rpctypes.ErrRateLimited, err)
+
+	// users without rate limits are not affected
+	_, err = rootc.Put(ctx, "k3", "v")
+	require.NoError(t, err)
+
+	resp, err := rootc.QuotaList(ctx)
+	require.NoError(t, err)
+	require.Len(t, resp.Quotas, 2)
+	assert.Equal(t, pb.Quota_USER, resp.Quotas[0].Type)
+	assert.Equal(t, "user1", resp.Quotas[0].Name)
+	assert.Equal(t, "role2", resp.Quotas[1].Name)
+
+	_, err = rootc.QuotaDelete(ctx, clientv3.QuotaUser, "user1")
+	require.NoError(t, err)
+	_, err = user1c.Put(ctx, "k1", "v")
+	require.NoError(t, err)
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+	"go.etcd.io/etcd/tests/v3/framework/integration"
+)
+
+// TestV3CompactionRetention tests that the compactions keep the revisions of
+// the keys under the prefixes of the retention rules of the member proposing
+// them on every member.
+func TestV3CompactionRetention(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{
+		Size: 3,
+		CompactionRetentionRules: []mvcc.RetentionRule{
+			{Prefix: "/audit/", Duration: time.Hour},
+			{Prefix: "/config/", Versions: 2},
+		},
+	})
+	defer clus.Terminate(t)
+
+	ctx := context.TODO()
+	cli := clus.RandClient()
+	for i := 0; i < 3; i++ {
+		for _, key := range []string{"/audit/a", "/config/a", "/other"} {
+			_, err := cli.Put(ctx, key, fmt.Sprint(i))
+			require.NoError(t, err)
+		}
+	}
+	// only the first member has retention rules
+	for _, m := range clus.Members[1:] {
+		m.Stop(t)
+		m.CompactionRetentionRules = nil
+		require.NoError(t, m.Restart(t))
+	}
+	clus.WaitLeader(t)
+	resp, err := clus.Client(0).Compact(ctx, 10, clientv3.WithCompactPhysical())
+	require.NoError(t, err)
+
+	modRevs := func(cli *clientv3.Client, key string) []int64 {
+		resp, err := cli.History(ctx, key, clientv3.WithSerializable())
+		require.NoError(t, err)
+		var revs []int64
+		for _, kv := range resp.Kvs {
+			revs = append(revs, kv.ModRevision)
+		}
+		return revs
+	}
+	for i := range clus.Members {
+		c := clus.Client(i)
+		// the duration is not sampled yet, every revision is kept
+		require.Equal(t, []int64{2, 5, 8}, modRevs(c, "/audit/a"))
+		require.Equal(t, []int64{6, 9}, modRevs(c, "/config/a"))
+		require.Equal(t, []int64{10}, modRevs(c, "/other"))
+		_, err = c.History(ctx, "/other", clientv3.WithMinModRev(2), clientv3.WithSerializable())
+		require.ErrorIs(t, err, rpctypes.ErrCompacted)
+
+		// the retained revisions are hashed the same on every member
+		hresp, err := c.HashKV(ctx, clus.Members[i].GRPCURL(), resp.Header.Revision)
+		require.NoError(t, err)
+		hresp0, err := clus.Client(0).HashKV(ctx, clus.Members[0].GRPCURL(), resp.Header.Revision)
+		require.NoError(t, err)
+		require.Equal(t, hresp0.Hash, hresp.Hash)
+
+	}
+
+	rresp, err := clus.Client(0).CompactionRetention(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []*pb.CompactionRetentionRule{
+		{Prefix: []byte("/audit/"), DurationSeconds: 3600},
+		{Prefix: []byte("/config/"), Versions: 2},
+	}, rresp.Rules)
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/tests/v3/framework/integration"
+)
+
+// TestV3RangeAtTime tests that every member serves the range requests at a
+// past time at the latest revision written up to the end of its second.
+func TestV3RangeAtTime(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 3})
+	defer clus.Terminate(t)
+
+	ctx := context.TODO()
+	cli := clus.RandClient()
+
+	before := time.Now().Add(-time.Minute)
+	_, err := cli.Put(ctx, "foo", "bar1")
+	require.NoError(t, err)
+	first := time.Now()
+
+	// write the next revision in a later second
+	time.Sleep(time.Second - time.Duration(first.Nanosecond()) + 100*time.Millisecond)
+	_, err = cli.Put(ctx, "foo", "bar2")
+	require.NoError(t, err)
+	second := time.Now()
+
+	for i := range clus.Members {
+		c := clus.Client(i)
+		_, err = c.Get(ctx, "foo", clientv3.WithAtTime(before))
+		require.ErrorIs(t, err, rpctypes.ErrTimeNotIndexed)
+
+		resp, err := c.Get(ctx, "foo", clientv3.WithAtTime(first))
+		require.NoError(t, err)
+		require.Len(t, resp.Kvs, 1)
+		require.Equal(t, "bar1", string(resp.Kvs[0].Value))
+
+		resp, err = c.Get(ctx, "foo", clientv3.WithAtTime(second), clientv3.WithSerializable())
+		require.NoError(t, err)
+		require.Len(t, resp.Kvs, 1)
+		require.Equal(t, "bar2", string(resp.Kvs[0].Value))
+	}
+
+	_, err = integration.ToGRPC(cli).KV.Range(ctx, &pb.RangeRequest{Key: []byte("foo"), Revision: 1, AtTime: first.UnixNano()})
+	require.Truef(t, eqErrGRPC(err, rpctypes.ErrGRPCInvalidAtTime), "got %v, expected %v", err, rpctypes.ErrGRPCInvalidAtTime)
+}
@@ -1397,3 +1397,41 @@ func TestV3WatchCloseCancelRace(t *testing.T) {
 		t.Fatalf("expected %s watch, got %s", expected, minWatches)
 	}
 }
+
+// TestV3WatchEventCache tests that the watchers catching up on the cached
+// revisions receive their events from the watch event cache.
+func TestV3WatchEventCache(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1, WatchEventCacheRevisions: 100})
+	defer clus.Terminate(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cli := clus.RandClient()
+	var startRev int64
+	for i := 0; i < 10; i++ {
+		resp, err := cli.Put(ctx, fmt.Sprintf("foo%d", i), "bar")
+		require.NoError(t, err)
+		if i == 0 {
+			startRev = resp.Header.Revision
+		}
+	}
+
+	wch := cli.Watch(ctx, "foo", clientv3.WithPrefix(), clientv3.WithRev(startRev))
+	var evs []*clientv3.Event
+	for len(evs) < 10 {
+		wresp, ok := <-wch
+		require.True(t, ok, "watch channel closed")
+		require.NoError(t, wresp.Err())
+		evs = append(evs, wresp.Events...)
+	}
+	for i, ev := range evs {
+		require.Equal(t, fmt.Sprintf("foo%d", i), string(ev.Kv.Key))
+		require.Equal(t, startRev+int64(i), ev.Kv.ModRevision)
+	}
+
+	hits, err := clus.Members[0].Metric("etcd_debugging_mvcc_watch_event_cache_requests_total", `result="hit"`)
+	require.NoError(t, err)
+	require.NotEqual(t, "", hits)
+	require.NotEqual(t, "0", hits)
+}
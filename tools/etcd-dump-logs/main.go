@@ -35,6 +35,7 @@ import (
 	"go.etcd.io/etcd/client/pkg/v3/types"
 	"go.etcd.io/etcd/pkg/v3/pbutil"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/snap"
+	"go.etcd.io/etcd/server/v3/storage/encryption"
 	"go.etcd.io/etcd/server/v3/storage/wal"
 	"go.etcd.io/etcd/server/v3/storage/wal/walpb"
 	"go.etcd.io/raft/v3/raftpb"
@@ -57,6 +58,7 @@ IRRCompaction, IRRLeaseGrant, IRRLeaseRevoke, IRRLeaseCheckpoint`)
 hex encoded lines of binary input (from etcd-dump-logs)
 and output a hex encoded line of binary for each input line`)
 	raw := flag.Bool("raw", false, "Read the logs in the low-level form")
+	keyFile := flag.String("encryption-key-file", "", "Path to the key file given to etcd by --experimental-encryption-key-file, if the data dir is encrypted")
 
 	flag.Parse()
 	lg := zap.NewExample()
@@ -70,8 +72,17 @@ and output a hex encoded line of binary for each input line`)
 		log.Fatal("start-snap and start-index flags cannot be used together.")
 	}
 
+	var cipher encryption.Cipher
+	if *keyFile != "" {
+		kms, err := encryption.NewLocalKMS(*keyFile)
+		if err != nil {
+			log.Fatalf("Failed loading encryption key file: %v", err)
+		}
+		cipher = encryption.NewEnvelope(kms)
+	}
+
 	if !*raw {
-		ents := readUsingReadAll(lg, index, snapfile, dataDir, waldir)
+		ents := readUsingReadAll(lg, index, snapfile, dataDir, waldir, cipher)
 
 		fmt.Printf("WAL entries: %d\n", len(ents))
 		if len(ents) > 0 {
@@ -100,7 +111,7 @@ and output a hex encoded line of binary for each input line`)
 	}
 }
 
-func readUsingReadAll(lg *zap.Logger, index *uint64, snapfile *string, dataDir string, waldir *string) []raftpb.Entry {
+func readUsingReadAll(lg *zap.Logger, index *uint64, snapfile *string, dataDir string, waldir *string, cipher encryption.Cipher) []raftpb.Entry {
 	var (
 		walsnap  walpb.Snapshot
 		snapshot *raftpb.Snapshot
@@ -115,9 +126,12 @@ func readUsingReadAll(lg *zap.Logger, index *uint64, snapfile *string, dataDir s
 	} else {
 		if *snapfile == "" {
 			ss := snap.New(lg, snapDir(dataDir))
+			if cipher != nil {
+				ss.SetCipher(cipher)
+			}
 			snapshot, err = ss.Load()
 		} else {
-			snapshot, err = snap.Read(lg, filepath.Join(snapDir(dataDir), *snapfile))
+			snapshot, err = snap.ReadWithCipher(lg, filepath.Join(snapDir(dataDir), *snapfile), cipher)
 		}
 
 		switch err {
@@ -147,6 +161,9 @@ func readUsingReadAll(lg *zap.Logger, index *uint64, snapfile *string, dataDir s
 	if err != nil {
 		log.Fatalf("Failed opening WAL: %v", err)
 	}
+	if cipher != nil {
+		w.SetCipher(cipher)
+	}
 	wmetadata, state, ents, err := w.ReadAll()
 	w.Close()
 	if err != nil && (!isIndex || err != wal.ErrSnapshotNotFound) {